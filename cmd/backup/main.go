@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"enterprise-blog/internal/backup"
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+)
+
+func main() {
+	output := flag.String("output", "", "archive path, or s3://bucket/key to upload to BACKUP_S3_ENDPOINT (required)")
+	tables := flag.String("tables", "", "comma-separated tables to back up (default: all)")
+	uploads := flag.Bool("uploads", false, "include files from UPLOAD_DIR")
+	batchSize := flag.Int("batch-size", backup.DefaultBatchSize, "rows read per query")
+	flag.Parse()
+
+	// 加载配置
+	if err := config.Load(); err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// 初始化日志
+	if err := logger.Init("info", ""); err != nil {
+		panic(fmt.Sprintf("Failed to init logger: %v", err))
+	}
+	l := logger.GetLogger()
+
+	if *output == "" {
+		l.Fatal().Msg("--output is required")
+	}
+	selected, err := backup.ResolveTables(splitList(*tables))
+	if err != nil {
+		l.Fatal().Err(err).Msg("Invalid --tables")
+	}
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		l.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	w, commit, abort, err := backup.CreateArchive(ctx, *output, config.Get().Backup)
+	if err != nil {
+		l.Fatal().Err(err).Msg("Failed to create archive")
+	}
+
+	opts := backup.ExportOptions{
+		Tables:    selected,
+		Driver:    database.DB.Dialector.Name(),
+		BatchSize: *batchSize,
+	}
+	if *uploads {
+		opts.UploadsDir = config.Get().Upload.Dir
+	}
+	manifest, err := backup.Export(ctx, repository.NewBackupRepository(), w, opts)
+	if err != nil {
+		abort()
+		l.Fatal().Err(err).Msg("Backup failed")
+	}
+	if err := commit(); err != nil {
+		l.Fatal().Err(err).Str("output", *output).Msg("Failed to write archive")
+	}
+
+	for _, t := range manifest.Tables {
+		l.Info().Str("table", t.Name).Int64("rows", t.Rows).Msg("Table backed up")
+	}
+	l.Info().Str("output", *output).Int("uploads", manifest.Uploads).Msg("Backup completed")
+}
+
+// splitList 拆分逗号分隔的参数
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,17 +1,43 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
 	"enterprise-blog/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// migrationsDir 迁移文件目录（相对项目根目录）
+const migrationsDir = "migrations"
+
+const (
+	// migrationLockKey 迁移使用的 advisory lock 键，所有迁移进程使用同一个固定值
+	migrationLockKey int64 = 7_215_400_300_001
+	// migrationLockPollInterval 等待迁移锁时的重试间隔
+	migrationLockPollInterval = 500 * time.Millisecond
 )
 
+// errMigrationInProgress 其他进程正在执行迁移
+var errMigrationInProgress = errors.New("another migration is in progress")
+
+// migrationNamePattern 迁移名称只允许小写字母、数字和单个下划线分隔
+var migrationNamePattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
 type Migration struct {
 	Version int
 	Name    string
@@ -19,17 +45,113 @@ type Migration struct {
 	Down    string
 }
 
+// Checksum 返回 up 文件内容的 SHA-256（十六进制）
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+const usage = `Usage: migrate <command> [args]
+
+Commands:
+  up                 执行所有未执行的迁移（默认）
+  down [n]           按倒序回滚最近执行的 n 个迁移（默认 1）
+  force <version>    不执行 SQL，直接把迁移记录标记为执行到 version（0 表示清空记录），用于手工修复失败的迁移后
+  status [--check]   列出所有迁移文件的执行状态；--check 时存在未执行或无对应文件的迁移记录则以非零状态码退出
+  version            输出当前已执行的最高迁移版本（未执行过任何迁移时为 0）
+  create <name>      生成下一个版本的 NNN_name.up.sql / NNN_name.down.sql，name 只能包含小写字母、数字和下划线
+`
+
 func main() {
+	command := "up"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	// 先校验命令行参数，避免参数错误时也去连接数据库
+	var steps, forceVersion int
+	var check bool
+	var createName string
+	switch command {
+	case "up":
+		if len(args) != 0 {
+			exitUsage()
+		}
+	case "down":
+		steps = 1
+		if len(args) > 1 {
+			exitUsage()
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				exitUsage()
+			}
+			steps = n
+		}
+	case "force":
+		if len(args) != 1 {
+			exitUsage()
+		}
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 0 {
+			exitUsage()
+		}
+		forceVersion = v
+	case "status":
+		if len(args) > 1 || (len(args) == 1 && args[0] != "--check") {
+			exitUsage()
+		}
+		check = len(args) == 1
+	case "version":
+		if len(args) != 0 {
+			exitUsage()
+		}
+	case "create":
+		if len(args) != 1 {
+			exitUsage()
+		}
+		if !migrationNamePattern.MatchString(args[0]) {
+			fmt.Fprintf(os.Stderr, "invalid migration name %q: use lowercase letters, digits and underscores, e.g. add_user_avatar\n", args[0])
+			os.Exit(2)
+		}
+		createName = args[0]
+	default:
+		exitUsage()
+	}
+
 	// 加载配置
 	if err := config.Load(); err != nil {
 		panic(fmt.Sprintf("Failed to load config: %v", err))
 	}
+	// 迁移文件包含多条语句，无法预编译
+	config.Get().Database.PrepareStmt = false
 
-	// 初始化日志
-	if err := logger.Init("info", ""); err != nil {
+	// 初始化日志（日志输出到 stdout，只读命令提高日志级别，避免混入 status / version 的输出）
+	logLevel := "info"
+	if command == "status" || command == "version" {
+		logLevel = "warn"
+	}
+	if err := logger.Init(logLevel, ""); err != nil {
 		panic(fmt.Sprintf("Failed to init logger: %v", err))
 	}
 
+	// 加载迁移文件（版本重复、缺少 up / down 文件时直接报错）
+	migrations, err := loadMigrations()
+	if err != nil {
+		l := logger.GetLogger()
+		l.Fatal().Err(err).Msg("Failed to load migrations")
+	}
+
+	if command == "create" {
+		if err := createMigration(migrations, createName); err != nil {
+			l := logger.GetLogger()
+			l.Fatal().Err(err).Msg("Failed to create migration")
+		}
+		return
+	}
+
 	// 初始化数据库（使用 GORM）
 	if err := database.Init(); err != nil {
 		l := logger.GetLogger()
@@ -37,68 +159,312 @@ func main() {
 	}
 	defer database.Close()
 
+	// 迁移文件只适用于 PostgreSQL；SQLite（本地开发）按 GORM 模型建表，不记录版本
+	if database.IsSQLite() {
+		l := logger.GetLogger()
+		if command != "up" {
+			l.Fatal().Str("command", command).Msg("SQLite only supports up (creates missing tables from models)")
+		}
+		if err := database.AutoMigrate(); err != nil {
+			l.Fatal().Err(err).Msg("Failed to create SQLite tables")
+		}
+		l.Info().Msg("SQLite tables created from models")
+		return
+	}
+
+	// 会修改数据库的命令先获取迁移锁，避免多个进程同时执行迁移
+	if command == "up" || command == "down" || command == "force" {
+		timeout := time.Duration(config.Get().Database.MigrateLockTimeoutSeconds) * time.Second
+		release, err := acquireMigrationLock(timeout)
+		if err != nil {
+			l := logger.GetLogger()
+			l.Fatal().Err(err).Msg("Failed to acquire migration lock")
+		}
+		defer release()
+	}
+
 	// 创建迁移表
 	if err := createMigrationsTable(); err != nil {
 		l := logger.GetLogger()
 		l.Fatal().Err(err).Msg("Failed to create migrations table")
 	}
 
-	// 加载迁移文件
-	migrations, err := loadMigrations()
+	switch command {
+	case "up":
+		err = migrateUp(migrations)
+	case "down":
+		err = migrateDown(migrations, steps)
+	case "force":
+		err = migrateForce(migrations, forceVersion)
+	case "status":
+		var clean bool
+		clean, err = migrateStatus(migrations)
+		if err == nil && check && !clean {
+			database.Close()
+			os.Exit(1)
+		}
+	case "version":
+		err = printVersion()
+	}
 	if err != nil {
 		l := logger.GetLogger()
-		l.Fatal().Err(err).Msg("Failed to load migrations")
+		l.Fatal().Err(err).Str("command", command).Msg("Migration failed")
 	}
+}
 
+func exitUsage() {
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(2)
+}
+
+// migrateUp 按版本顺序执行所有未执行的迁移，每个迁移在单独的事务中执行并记录
+func migrateUp(migrations []Migration) error {
 	// 获取已执行的迁移
 	executed, err := getExecutedMigrations()
 	if err != nil {
-		l := logger.GetLogger()
-		l.Fatal().Err(err).Msg("Failed to get executed migrations")
+		return fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+	if err := verifyChecksums(migrations); err != nil {
+		return err
 	}
 
-	// 执行未执行的迁移
+	l := logger.GetLogger()
 	for _, migration := range migrations {
 		if executed[migration.Version] {
-			l := logger.GetLogger()
 			l.Info().Int("version", migration.Version).Msg("Migration already executed, skipping")
 			continue
 		}
 
-		l := logger.GetLogger()
 		l.Info().Int("version", migration.Version).Str("name", migration.Name).Msg("Running migration")
 
-		tx := database.DB.Begin()
-		if tx.Error != nil {
-			l := logger.GetLogger()
-			l.Fatal().Err(tx.Error).Msg("Failed to begin transaction")
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			// 执行 up 迁移
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return fmt.Errorf("failed to execute migration %03d: %w", migration.Version, err)
+			}
+			// 记录迁移
+			if err := tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+				migration.Version, migration.Name, migration.Checksum()).Error; err != nil {
+				return fmt.Errorf("failed to record migration %03d: %w", migration.Version, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
-		// 执行 up 迁移
-		if err := tx.Exec(migration.Up).Error; err != nil {
-			tx.Rollback()
-			l := logger.GetLogger()
-			l.Fatal().Err(err).Int("version", migration.Version).Msg("Failed to execute migration")
+		l.Info().Int("version", migration.Version).Msg("Migration completed")
+	}
+
+	l.Info().Msg("All migrations completed")
+	return nil
+}
+
+// migrateDown 按版本倒序回滚最近执行的 steps 个迁移
+// 每个迁移在单独的事务中执行 down SQL 并删除 schema_migrations 记录；
+// 任一待回滚迁移缺少 down 文件时一个都不回滚
+func migrateDown(migrations []Migration, steps int) error {
+	var versions []int
+	if err := database.DB.Raw("SELECT version FROM schema_migrations ORDER BY version DESC LIMIT $1", steps).
+		Scan(&versions).Error; err != nil {
+		return fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+	if len(versions) == 0 {
+		fmt.Println("No applied migrations to revert")
+		return nil
+	}
+	if err := verifyChecksums(migrations); err != nil {
+		return err
+	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	// 先整体校验，避免回滚到一半才发现缺少 down 文件
+	toRevert := make([]Migration, 0, len(versions))
+	for _, v := range versions {
+		m, ok := byVersion[v]
+		if !ok || strings.TrimSpace(m.Down) == "" {
+			return fmt.Errorf("refusing to roll back: migration %03d has no down file", v)
 		}
+		toRevert = append(toRevert, m)
+	}
 
-		// 记录迁移
-		if err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name).Error; err != nil {
-			tx.Rollback()
-			l := logger.GetLogger()
-			l.Fatal().Err(err).Msg("Failed to record migration")
+	l := logger.GetLogger()
+	reverted := make([]string, 0, len(toRevert))
+	for _, migration := range toRevert {
+		l.Info().Int("version", migration.Version).Str("name", migration.Name).Msg("Reverting migration")
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return fmt.Errorf("failed to revert migration %03d: %w", migration.Version, err)
+			}
+			if err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version).Error; err != nil {
+				return fmt.Errorf("failed to delete migration record %03d: %w", migration.Version, err)
+			}
+			return nil
+		})
+		if err != nil {
+			if len(reverted) > 0 {
+				fmt.Printf("Reverted before failure: %s\n", strings.Join(reverted, ", "))
+			}
+			return err
 		}
 
-		if err := tx.Commit().Error; err != nil {
-			l := logger.GetLogger()
-			l.Fatal().Err(err).Msg("Failed to commit transaction")
+		reverted = append(reverted, fmt.Sprintf("%03d_%s", migration.Version, migration.Name))
+		fmt.Printf("Reverted %03d_%s\n", migration.Version, migration.Name)
+	}
+
+	fmt.Printf("Reverted %d migration(s): %s\n", len(reverted), strings.Join(reverted, ", "))
+	return nil
+}
+
+// migrateForce 不执行任何迁移 SQL，把 schema_migrations 改写为“已执行到 version”：
+// 删除大于 version 的记录，补齐小于等于 version 的记录
+// 注意: 只用于手工修复数据库结构之后，让迁移记录与实际结构保持一致
+func migrateForce(migrations []Migration, version int) error {
+	if version != 0 {
+		found := false
+		for _, m := range migrations {
+			if m.Version == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown migration version %d", version)
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM schema_migrations WHERE version > $1", version).Error; err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if m.Version > version {
+				break
+			}
+			if err := tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3) ON CONFLICT (version) DO NOTHING",
+				m.Version, m.Name, m.Checksum()).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to force version %d: %w", version, err)
+	}
+
+	fmt.Printf("Forced schema version to %03d\n", version)
+	return nil
+}
+
+// migrateStatus 以表格输出磁盘上每个迁移的执行状态和执行时间
+// schema_migrations 中存在但没有对应文件的版本会在表格中标记为 UNKNOWN 并在 stderr 输出警告
+// 返回: 没有未执行迁移且没有未知记录时为 true
+func migrateStatus(migrations []Migration) (bool, error) {
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return false, fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	appliedByVersion := make(map[int]appliedMigration, len(applied))
+	for _, a := range applied {
+		appliedByVersion[a.Version] = a
+	}
+	onDisk := make(map[int]bool, len(migrations))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tEXECUTED AT")
+	pending := 0
+	for _, m := range migrations {
+		onDisk[m.Version] = true
+		if a, ok := appliedByVersion[m.Version]; ok {
+			fmt.Fprintf(w, "%03d\t%s\tapplied\t%s\n", m.Version, m.Name, a.ExecutedAt.Format(time.RFC3339))
+			continue
+		}
+		pending++
+		fmt.Fprintf(w, "%03d\t%s\tpending\t-\n", m.Version, m.Name)
+	}
+
+	var unknown []string
+	for _, a := range applied {
+		if onDisk[a.Version] {
+			continue
 		}
+		unknown = append(unknown, fmt.Sprintf("%03d_%s", a.Version, a.Name))
+		fmt.Fprintf(w, "%03d\t%s\tUNKNOWN (no file)\t%s\n", a.Version, a.Name, a.ExecutedAt.Format(time.RFC3339))
+	}
+	if err := w.Flush(); err != nil {
+		return false, err
+	}
 
-		l2 := logger.GetLogger()
-		l2.Info().Int("version", migration.Version).Msg("Migration completed")
+	fmt.Printf("\n%d applied, %d pending, %d unknown\n", len(applied)-len(unknown), pending, len(unknown))
+	if len(unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: schema_migrations contains %d version(s) with no migration file: %s\n"+
+			"         the database is ahead of this build or a migration file was deleted\n",
+			len(unknown), strings.Join(unknown, ", "))
+	}
+	return pending == 0 && len(unknown) == 0, nil
+}
+
+// printVersion 输出当前已执行的最高迁移版本，未执行过任何迁移时输出 0
+func printVersion() error {
+	var version int
+	if err := database.DB.Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version).Error; err != nil {
+		return fmt.Errorf("failed to get current version: %w", err)
+	}
+	fmt.Println(version)
+	return nil
+}
+
+// createMigration 在 migrations 目录下生成下一个版本的 up / down 文件
+// 下一个版本取迁移文件和 schema_migrations 中的最大版本 + 1；数据库不可用时只按文件计算并输出警告
+func createMigration(migrations []Migration, name string) error {
+	next := 1
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Version + 1
 	}
 
 	l := logger.GetLogger()
-	l.Info().Msg("All migrations completed")
+	if err := database.Init(); err != nil {
+		l.Warn().Err(err).Msg("Database unavailable, next version is based on migration files only")
+	} else {
+		defer database.Close()
+		var applied int
+		if err := database.DB.Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&applied).Error; err != nil {
+			l.Warn().Err(err).Msg("Failed to read schema_migrations, next version is based on migration files only")
+		} else if applied >= next {
+			next = applied + 1
+		}
+	}
+
+	created := time.Now().Format("2006-01-02")
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(migrationsDir, fmt.Sprintf("%03d_%s.%s.sql", next, name, direction))
+		header := fmt.Sprintf("-- %03d_%s（%s）\n-- 创建于 %s\n\n", next, name, direction, created)
+		// O_EXCL：目标文件已存在时报错，不覆盖
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteString(header)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+
+	for _, path := range paths {
+		fmt.Println("Created", path)
+	}
+	return nil
 }
 
 func createMigrationsTable() error {
@@ -109,19 +475,104 @@ func createMigrationsTable() error {
 			executed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
 	`
-	return database.DB.Exec(query).Error
+	if err := database.DB.Exec(query).Error; err != nil {
+		return err
+	}
+	// up 文件内容的 SHA-256，用于发现已执行迁移被修改；旧记录为 NULL，首次校验时补齐
+	return database.DB.Exec("ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64)").Error
 }
 
-func loadMigrations() ([]Migration, error) {
-	// 直接从项目根目录下的 migrations 目录读取 SQL 文件
-	migrationsDir := filepath.Join("migrations")
+// acquireMigrationLock 获取 PostgreSQL 会话级 advisory lock，最多等待 timeout
+// 锁绑定在单独的连接上，调用返回的 release 释放锁并归还连接；进程退出时连接断开，锁也会自动释放
+// 返回: 超时仍未获取到锁时返回 errMigrationInProgress
+func acquireMigrationLock(timeout time.Duration) (func(), error) {
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l := logger.GetLogger()
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			conn.Close()
+			return nil, fmt.Errorf("%w (waited %s)", errMigrationInProgress, timeout)
+		}
+		if !waiting {
+			waiting = true
+			l.Warn().Dur("timeout", timeout).Msg("Another migration is in progress, waiting for its lock")
+		}
+		time.Sleep(migrationLockPollInterval)
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			l.Warn().Err(err).Msg("Failed to release migration lock")
+		}
+		conn.Close()
+	}, nil
+}
+
+// verifyChecksums 校验已执行迁移的 up 文件是否在执行后被修改
+// 没有校验和的旧记录按当前文件补齐
+func verifyChecksums(migrations []Migration) error {
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	l := logger.GetLogger()
+	for _, a := range applied {
+		m, ok := byVersion[a.Version]
+		if !ok {
+			continue
+		}
+		sum := m.Checksum()
+		if a.Checksum == nil || *a.Checksum == "" {
+			if err := database.DB.Exec("UPDATE schema_migrations SET checksum = $1 WHERE version = $2", sum, a.Version).Error; err != nil {
+				return fmt.Errorf("failed to record checksum of migration %03d: %w", a.Version, err)
+			}
+			l.Info().Int("version", a.Version).Msg("Recorded checksum for previously applied migration")
+			continue
+		}
+		if *a.Checksum != sum {
+			return fmt.Errorf("migration %03d_%s was modified after it was applied (checksum mismatch); "+
+				"restore the original file and put the change in a new migration", m.Version, m.Name)
+		}
+	}
+	return nil
+}
 
+// loadMigrations 读取 migrations 目录下的迁移文件，按版本升序返回
+// 返回: 同一版本出现多个名称或重复文件、只有 up 或只有 down 文件时返回错误
+func loadMigrations() ([]Migration, error) {
 	entries, err := os.ReadDir(migrationsDir)
 	if err != nil {
 		return nil, err
 	}
 
 	migrationsMap := make(map[int]*Migration)
+	// 记录每个版本已读取的文件，用于报告冲突
+	files := make(map[int][]string)
 
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
@@ -157,22 +608,38 @@ func loadMigrations() ([]Migration, error) {
 			return nil, err
 		}
 
-		if migrationsMap[version] == nil {
-			migrationsMap[version] = &Migration{
+		m := migrationsMap[version]
+		if m == nil {
+			m = &Migration{
 				Version: version,
 				Name:    name,
 			}
+			migrationsMap[version] = m
+		}
+		files[version] = append(files[version], filename)
+
+		duplicate := m.Name != name ||
+			(direction == "up" && m.Up != "") ||
+			(direction == "down" && m.Down != "")
+		if duplicate {
+			return nil, fmt.Errorf("duplicate migration version %03d: %s", version, strings.Join(files[version], ", "))
 		}
 
 		if direction == "up" {
-			migrationsMap[version].Up = string(content)
+			m.Up = string(content)
 		} else {
-			migrationsMap[version].Down = string(content)
+			m.Down = string(content)
 		}
 	}
 
 	var migrations []Migration
 	for _, m := range migrationsMap {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up file (or it is empty)", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no down file (or it is empty)", m.Version, m.Name)
+		}
 		migrations = append(migrations, *m)
 	}
 
@@ -183,6 +650,22 @@ func loadMigrations() ([]Migration, error) {
 	return migrations, nil
 }
 
+// appliedMigration schema_migrations 中的一条执行记录
+type appliedMigration struct {
+	Version    int
+	Name       string
+	ExecutedAt time.Time
+	Checksum   *string
+}
+
+// getAppliedMigrations 按版本升序返回所有执行记录
+func getAppliedMigrations() ([]appliedMigration, error) {
+	var applied []appliedMigration
+	err := database.DB.Raw("SELECT version, name, executed_at, checksum FROM schema_migrations ORDER BY version").
+		Scan(&applied).Error
+	return applied, err
+}
+
 func getExecutedMigrations() (map[int]bool, error) {
 	var versions []int
 	if err := database.DB.Raw("SELECT version FROM schema_migrations").Scan(&versions).Error; err != nil {
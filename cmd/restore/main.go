@@ -0,0 +1,150 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"enterprise-blog/internal/backup"
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// reindexBatchSize 重建搜索索引时每批读取的文章数
+const reindexBatchSize = 200
+
+func main() {
+	input := flag.String("input", "", "archive path, or s3://bucket/key to download from BACKUP_S3_ENDPOINT (required)")
+	tables := flag.String("tables", "", "comma-separated tables to restore (default: all tables in the archive)")
+	force := flag.Bool("force", false, "restore into non-empty tables, skipping rows that already exist")
+	uploads := flag.Bool("uploads", false, "extract archived upload files into UPLOAD_DIR (existing files are kept)")
+	reindex := flag.Bool("reindex", false, "rebuild the Elasticsearch index after restoring")
+	batchSize := flag.Int("batch-size", backup.DefaultBatchSize, "rows written per statement")
+	flag.Parse()
+
+	// 加载配置
+	if err := config.Load(); err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// 初始化日志
+	if err := logger.Init("info", ""); err != nil {
+		panic(fmt.Sprintf("Failed to init logger: %v", err))
+	}
+	l := logger.GetLogger()
+
+	if *input == "" {
+		l.Fatal().Msg("--input is required")
+	}
+	selected, err := backup.ResolveTables(splitList(*tables))
+	if err != nil {
+		l.Fatal().Err(err).Msg("Invalid --tables")
+	}
+
+	// 初始化数据库（表结构需要先通过 migrate up 创建）
+	if err := database.Init(); err != nil {
+		l.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	f, closeArchive, err := backup.OpenArchive(ctx, *input, config.Get().Backup)
+	if err != nil {
+		l.Fatal().Err(err).Msg("Failed to open archive")
+	}
+	defer closeArchive()
+	info, err := f.Stat()
+	if err != nil {
+		l.Fatal().Err(err).Msg("Failed to open archive")
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		l.Fatal().Err(err).Msg("Invalid archive")
+	}
+
+	opts := backup.RestoreOptions{
+		Tables:    selected,
+		Force:     *force,
+		BatchSize: *batchSize,
+	}
+	if *uploads {
+		opts.UploadsDir = config.Get().Upload.Dir
+	}
+	result, err := backup.Restore(ctx, repository.NewBackupRepository(), zr, opts)
+	if result != nil {
+		for _, t := range result.Tables {
+			l.Info().Str("table", t.Name).Int64("expected", t.Expected).Int64("inserted", t.Inserted).Msg("Table restored")
+			if t.Inserted < t.Read {
+				l.Warn().Str("table", t.Name).Int64("skipped", t.Read-t.Inserted).Msg("Rows already present were skipped")
+			}
+		}
+	}
+	var notEmpty *backup.NotEmptyError
+	if errors.As(err, &notEmpty) {
+		l.Fatal().Strs("tables", notEmpty.Tables).Msg("Refusing to restore into a non-empty database, pass --force to merge into existing data")
+	}
+	if err != nil {
+		l.Fatal().Err(err).Msg("Restore failed")
+	}
+	l.Info().Str("input", *input).Int("uploads", result.Uploads).Msg("Restore completed")
+
+	if *reindex {
+		if err := reindexArticles(ctx); err != nil {
+			l.Fatal().Err(err).Msg("Search reindex failed")
+		}
+	}
+}
+
+// reindexArticles 同步重建全部文章的搜索索引
+// 注意: 不经过 ReindexService（依赖 Redis 锁和任务状态），恢复通常在服务停止时执行
+func reindexArticles(ctx context.Context) error {
+	l := logger.GetLogger()
+	search.InitElasticsearch()
+	if !search.Enabled() {
+		return search.ErrSearchDisabled
+	}
+
+	articleRepo := repository.NewArticleRepository()
+	afterID := uuid.Nil
+	var indexed, failed int
+	for {
+		articles, err := articleRepo.ListForIndexing(ctx, nil, afterID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		ok, bad, err := search.BulkIndexArticles(ctx, articles)
+		if err != nil {
+			return err
+		}
+		indexed += ok
+		failed += bad
+		afterID = articles[len(articles)-1].ID
+		if len(articles) < reindexBatchSize {
+			break
+		}
+	}
+	l.Info().Int("indexed", indexed).Int("failed", failed).Msg("Search reindex completed")
+	return nil
+}
+
+// splitList 拆分逗号分隔的参数
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/jwt"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// seedMarkerEmail 第一个演示作者的邮箱，存在即认为演示数据已经写入
+const seedMarkerEmail = "alice@demo.example.com"
+
+const (
+	articleCount = 50
+	// searchIndexWait 退出前等待异步索引完成的最长时间
+	searchIndexWait = 30 * time.Second
+)
+
+// seeder 通过业务服务写入演示数据，slug 生成、缓存清理、Elasticsearch 索引与正常请求走同一路径
+type seeder struct {
+	userRepo        *repository.UserRepository
+	userService     *services.UserService
+	articleService  *services.ArticleService
+	categoryService *services.CategoryService
+	tagService      *services.TagService
+	commentService  *services.CommentService
+	imageService    *services.ImageService
+
+	rnd *rand.Rand
+}
+
+func main() {
+	force := flag.Bool("force", false, "allow seeding when SERVER_MODE=release")
+	flag.Parse()
+
+	// 加载配置
+	if err := config.Load(); err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// 初始化日志
+	if err := logger.Init("info", ""); err != nil {
+		panic(fmt.Sprintf("Failed to init logger: %v", err))
+	}
+	l := logger.GetLogger()
+
+	if config.Get().Server.Mode == "release" && !*force {
+		l.Fatal().Msg("Refusing to seed demo data when SERVER_MODE=release, pass --force to override")
+	}
+
+	adminEmail := getEnv("SEED_ADMIN_EMAIL", "admin@demo.example.com")
+	adminUsername := getEnv("SEED_ADMIN_USERNAME", "admin")
+	adminPassword := os.Getenv("SEED_ADMIN_PASSWORD")
+	if len(adminPassword) < 6 {
+		l.Fatal().Msg("SEED_ADMIN_PASSWORD must be set (at least 6 characters)")
+	}
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		l.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer database.Close()
+
+	// 初始化Redis（可选，用于验证缓存清理路径）
+	if err := database.InitRedis(); err != nil {
+		l.Warn().Err(err).Msg("Failed to connect to redis, continuing without cache")
+	} else {
+		defer database.CloseRedis()
+	}
+
+	// 初始化 Elasticsearch（可选，启用时文章会同步写入索引）
+	search.InitElasticsearch()
+
+	ctx := context.Background()
+
+	settingsService := services.NewSettingsService(repository.NewSettingRepository())
+	if err := settingsService.Init(ctx); err != nil {
+		l.Warn().Err(err).Msg("Failed to load settings, using config defaults")
+	}
+
+	userRepo := repository.NewUserRepository()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	jwtMgr := jwt.NewJWTManager(config.Get().JWT.Secret, config.Get().JWT.ExpireDuration()).
+		WithRefreshTTL(config.Get().JWT.RefreshExpireDuration())
+
+	s := &seeder{
+		userRepo:        userRepo,
+		userService:     services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), jwtMgr),
+		articleService:  services.NewArticleService(articleRepo, categoryRepo, tagRepo),
+		categoryService: services.NewCategoryService(categoryRepo, articleRepo),
+		tagService:      services.NewTagService(tagRepo, articleRepo),
+		commentService:  services.NewCommentService(repository.NewCommentRepository(), articleRepo),
+		imageService:    services.NewImageService(repository.NewImageRepository(), config.Get().Upload.Dir),
+		// 固定随机种子，每次生成的数据分布一致
+		rnd: rand.New(rand.NewSource(20240101)),
+	}
+
+	if _, err := userRepo.GetByEmail(ctx, seedMarkerEmail); err == nil {
+		l.Info().Str("marker", seedMarkerEmail).Msg("Demo data already present, skipping")
+		return
+	}
+
+	if err := s.run(ctx, adminEmail, adminUsername, adminPassword); err != nil {
+		// 已写入的部分数据（含标记用户）不会回滚，需要清理后才能重新执行
+		l.Fatal().Err(err).Str("marker", seedMarkerEmail).Msg("Failed to seed demo data, remove the partially seeded data before re-running")
+	}
+	waitForSearchIndexing()
+	l.Info().Msg("Demo data seeded")
+}
+
+// waitForSearchIndexing 等待异步的 Elasticsearch 写入完成，避免进程退出时丢失索引
+func waitForSearchIndexing() {
+	deadline := time.Now().Add(searchIndexWait)
+	for search.PendingOperations() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if n := search.PendingOperations(); n > 0 {
+		l := logger.GetLogger()
+		l.Warn().Int64("pending", n).Msg("Search indexing still pending, run a reindex later")
+	}
+}
+
+func (s *seeder) run(ctx context.Context, adminEmail, adminUsername, adminPassword string) error {
+	l := logger.GetLogger()
+
+	// 管理员已存在时直接复用，不修改其密码
+	if _, err := s.userRepo.GetByEmail(ctx, adminEmail); err != nil {
+		if _, err := s.register(ctx, adminUsername, adminEmail, adminPassword, models.RoleAdmin); err != nil {
+			return fmt.Errorf("create admin: %w", err)
+		}
+		l.Info().Str("email", adminEmail).Msg("Admin user created")
+	}
+
+	authors, err := s.createAuthors(ctx)
+	if err != nil {
+		return err
+	}
+	categories, err := s.createCategories(ctx)
+	if err != nil {
+		return err
+	}
+	tags, err := s.createTags(ctx)
+	if err != nil {
+		return err
+	}
+	images, err := s.uploadImages(ctx, authors[0].ID)
+	if err != nil {
+		return err
+	}
+	articles, err := s.createArticles(ctx, authors, categories, tags, images)
+	if err != nil {
+		return err
+	}
+	comments, err := s.createComments(ctx, authors, articles)
+	if err != nil {
+		return err
+	}
+
+	l.Info().
+		Int("authors", len(authors)).
+		Int("categories", len(categories)).
+		Int("tags", len(tags)).
+		Int("images", len(images)).
+		Int("articles", len(articles)).
+		Int("comments", comments).
+		Msg("Seed summary")
+	return nil
+}
+
+func (s *seeder) register(ctx context.Context, username, email, password string, role models.UserRole) (*models.User, error) {
+	user, err := s.userService.Register(ctx, &models.UserCreate{
+		Username: username,
+		Email:    email,
+		Password: password,
+		Role:     role,
+	})
+	if errors.Is(err, services.ErrRegistrationClosed) {
+		return nil, fmt.Errorf("%w: set registration_mode to open before seeding", err)
+	}
+	return user, err
+}
+
+func (s *seeder) createAuthors(ctx context.Context) ([]*models.User, error) {
+	// 第一个作者的邮箱同时作为幂等标记，必须最先创建
+	defs := []struct {
+		username string
+		email    string
+		role     models.UserRole
+	}{
+		{"alice", seedMarkerEmail, models.RoleEditor},
+		{"bob", "bob@demo.example.com", models.RoleAuthor},
+		{"chen_wei", "chenwei@demo.example.com", models.RoleAuthor},
+		{"li_na", "lina@demo.example.com", models.RoleAuthor},
+	}
+
+	authors := make([]*models.User, 0, len(defs))
+	for _, d := range defs {
+		user, err := s.register(ctx, d.username, d.email, "demo123456", d.role)
+		if err != nil {
+			return nil, fmt.Errorf("create author %s: %w", d.username, err)
+		}
+		authors = append(authors, user)
+	}
+	return authors, nil
+}
+
+func (s *seeder) createCategories(ctx context.Context) ([]*models.Category, error) {
+	tree := []struct {
+		name     string
+		desc     string
+		children []string
+	}{
+		{"技术", "编程与工程实践", []string{"Go", "数据库", "前端"}},
+		{"Product", "Product thinking and design", []string{"Design", "Growth"}},
+		{"生活", "读书、旅行与日常", []string{"旅行", "读书笔记"}},
+	}
+
+	var leaves []*models.Category
+	for i, root := range tree {
+		parent, err := s.categoryService.Create(ctx, &models.CategoryCreate{Name: root.name, Description: root.desc, Order: i})
+		if err != nil {
+			return nil, fmt.Errorf("create category %s: %w", root.name, err)
+		}
+		for j, name := range root.children {
+			child, err := s.categoryService.Create(ctx, &models.CategoryCreate{Name: name, ParentID: &parent.ID, Order: j})
+			if err != nil {
+				return nil, fmt.Errorf("create category %s: %w", name, err)
+			}
+			leaves = append(leaves, child)
+		}
+	}
+	return leaves, nil
+}
+
+func (s *seeder) createTags(ctx context.Context) ([]*models.Tag, error) {
+	defs := []struct{ name, color string }{
+		{"golang", "#00ADD8"},
+		{"PostgreSQL", "#336791"},
+		{"Redis", "#DC382D"},
+		{"性能优化", "#F59E0B"},
+		{"架构", "#6366F1"},
+		{"入门", "#10B981"},
+		{"Career", "#EC4899"},
+		{"随笔", "#6B7280"},
+	}
+
+	tags := make([]*models.Tag, 0, len(defs))
+	for _, d := range defs {
+		tag, err := s.tagService.Create(ctx, &models.TagCreate{Name: d.name, Color: d.color})
+		if err != nil {
+			return nil, fmt.Errorf("create tag %s: %w", d.name, err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// uploadImages 生成几张纯色 PNG，通过 ImageService.Upload 上传（与接口上传走同一路径）
+func (s *seeder) uploadImages(ctx context.Context, uploaderID uuid.UUID) ([]*models.Image, error) {
+	if err := os.MkdirAll(s.imageService.GetUploadDir(), 0o755); err != nil {
+		return nil, err
+	}
+
+	colors := []color.RGBA{
+		{R: 0x25, G: 0x63, B: 0xEB, A: 0xFF},
+		{R: 0x10, G: 0xB9, B: 0x81, A: 0xFF},
+		{R: 0xF5, G: 0x9E, B: 0x0B, A: 0xFF},
+		{R: 0xEF, G: 0x44, B: 0x44, A: 0xFF},
+	}
+
+	images := make([]*models.Image, 0, len(colors))
+	for i, c := range colors {
+		name := fmt.Sprintf("demo-cover-%d.png", i+1)
+		file, err := pngFileHeader(name, c, 1200, 630)
+		if err != nil {
+			return nil, err
+		}
+		img, err := s.imageService.Upload(ctx, uploaderID, file, "演示封面图 "+name, []string{"demo", "cover"})
+		if err != nil {
+			return nil, fmt.Errorf("upload image %s: %w", name, err)
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// pngFileHeader 生成一张纯色 PNG 并包装为 multipart.FileHeader
+func pngFileHeader(filename string, c color.RGBA, width, height int) (*multipart.FileHeader, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	h.Set("Content-Type", "image/png")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return nil, err
+	}
+	if err := png.Encode(part, img); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(32 << 20)
+	if err != nil {
+		return nil, err
+	}
+	return form.File["file"][0], nil
+}
+
+var (
+	zhTopics = []string{"Go 并发模式", "PostgreSQL 索引", "Redis 缓存策略", "微服务拆分", "接口幂等设计", "全文搜索", "单元测试", "读书笔记", "云南旅行", "团队协作"}
+	zhForms  = []string{"实践总结", "入门指南", "踩坑记录", "深入浅出", "十个技巧"}
+	enTopics = []string{"Go Generics", "Database Migrations", "Caching at Scale", "Product Discovery", "Remote Work", "Observability", "API Design", "Design Systems"}
+	enForms  = []string{"A Practical Guide", "Lessons Learned", "From Zero to Production", "Common Pitfalls"}
+)
+
+func (s *seeder) createArticles(ctx context.Context, authors []*models.User, categories []*models.Category, tags []*models.Tag, images []*models.Image) ([]*models.Article, error) {
+	articles := make([]*models.Article, 0, articleCount)
+	for i := 0; i < articleCount; i++ {
+		var title string
+		if i%2 == 0 {
+			title = fmt.Sprintf("%s：%s", zhTopics[s.rnd.Intn(len(zhTopics))], zhForms[s.rnd.Intn(len(zhForms))])
+		} else {
+			title = fmt.Sprintf("%s: %s", enTopics[s.rnd.Intn(len(enTopics))], enForms[s.rnd.Intn(len(enForms))])
+		}
+
+		category := categories[s.rnd.Intn(len(categories))]
+		req := &models.ArticleCreate{
+			Title:      title,
+			Content:    articleContent(title, s.rnd.Intn(4)+3),
+			Status:     models.StatusPublished,
+			CategoryID: &category.ID,
+			TagIDs:     s.pickTags(tags, s.rnd.Intn(3)+1),
+		}
+		if i%3 == 0 {
+			req.CoverImage = images[s.rnd.Intn(len(images))].URL
+		}
+
+		author := authors[i%len(authors)]
+		article, err := s.articleService.Create(ctx, author.ID, req)
+		if err != nil {
+			return nil, fmt.Errorf("create article %q: %w", title, err)
+		}
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+func (s *seeder) pickTags(tags []*models.Tag, n int) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, n)
+	for _, i := range s.rnd.Perm(len(tags))[:n] {
+		ids = append(ids, tags[i].ID)
+	}
+	return ids
+}
+
+func articleContent(title string, paragraphs int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for i := 1; i <= paragraphs; i++ {
+		fmt.Fprintf(&b, "## 第 %d 部分 / Part %d\n\n", i, i)
+		b.WriteString("这是一段用于演示的正文内容，包含中文和 English mixed text，方便验证全文搜索、摘要生成和排版效果。")
+		b.WriteString("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.\n\n")
+	}
+	return b.String()
+}
+
+var (
+	commentTexts = []string{"写得很清楚，收藏了！", "Great write-up, thanks for sharing.", "第二部分能再展开讲讲吗？", "I ran into the same issue last week.", "有没有示例代码仓库？", "Nice, but I'd argue the opposite in some cases."}
+	replyTexts   = []string{"谢谢反馈，后续会补充。", "Good point, I'll update the post.", "+1，同问", "Agreed!"}
+	// 评论最终的审核状态分布：以已通过为主，少量待审核、已拒绝和被举报
+	commentStatuses = []string{
+		models.CommentStatusApproved, models.CommentStatusApproved, models.CommentStatusApproved,
+		models.CommentStatusPending, models.CommentStatusRejected, models.CommentStatusReported,
+	}
+)
+
+// createComments 为部分文章创建评论和回复，并设置不同的审核状态
+// 返回: 创建的评论总数
+func (s *seeder) createComments(ctx context.Context, authors []*models.User, articles []*models.Article) (int, error) {
+	total := 0
+	for i, article := range articles {
+		if i%2 == 1 {
+			continue
+		}
+		for j := 0; j < s.rnd.Intn(4)+1; j++ {
+			parent, err := s.createComment(ctx, authors, article.ID, nil, commentTexts[s.rnd.Intn(len(commentTexts))])
+			if err != nil {
+				return total, err
+			}
+			total++
+
+			// 约一半的评论带一条回复，回复中再嵌套一层
+			if s.rnd.Intn(2) == 0 {
+				continue
+			}
+			reply, err := s.createComment(ctx, authors, article.ID, &parent.ID, replyTexts[s.rnd.Intn(len(replyTexts))])
+			if err != nil {
+				return total, err
+			}
+			total++
+			if s.rnd.Intn(3) == 0 {
+				if _, err := s.createComment(ctx, authors, article.ID, &reply.ID, replyTexts[s.rnd.Intn(len(replyTexts))]); err != nil {
+					return total, err
+				}
+				total++
+			}
+		}
+	}
+	return total, nil
+}
+
+func (s *seeder) createComment(ctx context.Context, authors []*models.User, articleID uuid.UUID, parentID *uuid.UUID, content string) (*models.Comment, error) {
+	req := &models.CommentCreate{
+		ArticleID: articleID,
+		ParentID:  parentID,
+		Content:   content,
+	}
+
+	// 一半为登录用户评论，一半为游客评论
+	var userID *uuid.UUID
+	if s.rnd.Intn(2) == 0 {
+		author := authors[s.rnd.Intn(len(authors))]
+		userID = &author.ID
+		req.Author = author.Username
+		req.Email = author.Email
+	} else {
+		n := s.rnd.Intn(1000)
+		req.Author = fmt.Sprintf("访客%d", n)
+		req.Email = fmt.Sprintf("guest%d@demo.example.com", n)
+	}
+
+	comment, err := s.commentService.Create(ctx, userID, "127.0.0.1", req)
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+
+	status := commentStatuses[s.rnd.Intn(len(commentStatuses))]
+	if comment.Status != status {
+		if comment, err = s.commentService.Update(ctx, comment.ID, &models.CommentUpdate{Status: &status}); err != nil {
+			return nil, fmt.Errorf("update comment status: %w", err)
+		}
+	}
+	return comment, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,11 +12,15 @@ import (
 
 	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/flags"
+	"enterprise-blog/internal/graph"
 	"enterprise-blog/internal/handlers"
 	"enterprise-blog/internal/middleware"
 	"enterprise-blog/internal/repository"
 	"enterprise-blog/internal/search"
 	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/cron"
+	"enterprise-blog/pkg/errorreport"
 	"enterprise-blog/pkg/jwt"
 	"enterprise-blog/pkg/logger"
 	"enterprise-blog/pkg/metrics"
@@ -25,16 +30,54 @@ import (
 )
 
 func main() {
-	// 加载配置
+	// 加载配置，配置有误时一次输出全部问题后退出
 	if err := config.Load(); err != nil {
-		panic(fmt.Sprintf("Failed to load config: %v", err))
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			fmt.Fprintln(os.Stderr, "Invalid configuration:")
+			for _, problem := range validationErr.Problems {
+				fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		}
+		os.Exit(1)
 	}
 
 	// 初始化日志
-	if err := logger.Init(config.AppConfig.Log.Level, config.AppConfig.Log.File); err != nil {
+	logCfg := config.Get().Log
+	if err := logger.InitWithOptions(logger.Options{
+		Level:      logCfg.Level,
+		File:       logCfg.File,
+		MaxSizeMB:  logCfg.MaxSizeMB,
+		MaxBackups: logCfg.MaxBackups,
+		MaxAgeDays: logCfg.MaxAgeDays,
+		Compress:   logCfg.Compress,
+
+		ModuleLevels: logCfg.ModuleLevels,
+	}); err != nil {
 		panic(fmt.Sprintf("Failed to init logger: %v", err))
 	}
 
+	// 初始化错误上报（SENTRY_DSN 为空时不上报），error 及以上级别的日志同时上报
+	if sentryCfg := config.Get().Sentry; sentryCfg.DSN != "" {
+		environment := sentryCfg.Environment
+		if environment == "" {
+			environment = config.Get().Server.Mode
+		}
+		reporter, err := errorreport.NewSentryReporter(errorreport.SentryOptions{
+			DSN:         sentryCfg.DSN,
+			Environment: environment,
+			Release:     sentryCfg.Release,
+		})
+		if err != nil {
+			panic(fmt.Sprintf("Failed to init error reporter: %v", err))
+		}
+		errorreport.SetReporter(reporter)
+		defer errorreport.Flush(2 * time.Second)
+	}
+	logger.AddHook(errorreport.LogHook{})
+
 	// 初始化数据库
 	if err := database.Init(); err != nil {
 		l := logger.GetLogger()
@@ -54,7 +97,8 @@ func main() {
 	search.InitElasticsearch()
 
 	// 初始化JWT管理器
-	jwtMgr := jwt.NewJWTManager(config.AppConfig.JWT.Secret, config.AppConfig.JWT.ExpireDuration())
+	jwtMgr := jwt.NewJWTManager(config.Get().JWT.Secret, config.Get().JWT.ExpireDuration()).
+		WithRefreshTTL(config.Get().JWT.RefreshExpireDuration())
 
 	// 初始化Repository
 	userRepo := repository.NewUserRepository()
@@ -64,16 +108,72 @@ func main() {
 	commentRepo := repository.NewCommentRepository()
 	smsRepo := repository.NewSMSRepository()
 	imageRepo := repository.NewImageRepository()
+	statsRepo := repository.NewStatsRepository()
+	settingRepo := repository.NewSettingRepository()
+	auditLogRepo := repository.NewAuditLogRepository()
+	trashRepo := repository.NewTrashRepository()
+	flagRepo := repository.NewFeatureFlagRepository()
+	webhookRepo := repository.NewWebhookRepository()
+	newsletterRepo := repository.NewNewsletterRepository()
+	notificationRepo := repository.NewNotificationRepository()
 
 	// 初始化Service
-	userService := services.NewUserService(userRepo, jwtMgr)
+	// 设置服务需最先初始化，其他服务通过它读取运行时设置
+	settingsService := services.NewSettingsService(settingRepo)
+	if err := settingsService.Init(context.Background()); err != nil {
+		l := logger.GetLogger()
+		l.Warn().Err(err).Msg("Failed to load settings, using config defaults")
+	}
+	go settingsService.Subscribe(context.Background())
+
+	// 功能开关：处理器和服务通过 flags.Enabled 判断
+	flagService := flags.NewService(flagRepo)
+	if err := flagService.Init(context.Background()); err != nil {
+		l := logger.GetLogger()
+		l.Warn().Err(err).Msg("Failed to load feature flags, using config defaults")
+	}
+	go flagService.Subscribe(context.Background())
+
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), jwtMgr)
+	// 同一邮箱或 IP 密码错误次数过多时暂时锁定登录
+	loginLimits := config.Get().RateLimit
+	loginGuard := services.NewLoginGuard(loginLimits.LoginMaxFailures, loginLimits.LoginIPMaxFailures, loginLimits.LoginWindow())
+	userService.SetLoginGuard(loginGuard)
 	smsService := services.NewSMSService(smsRepo, userRepo)
+	smsService.SetJWTManager(jwtMgr)
 	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
-	tagService := services.NewTagService(tagRepo)
+	// 未配置 SUMMARIZER_BASE_URL 时不调用外部服务，摘要使用截取正文
+	articleService.SetSummarizer(services.NewSummarizer(config.Get().Summarizer))
+	categoryService := services.NewCategoryService(categoryRepo, articleRepo)
+	tagService := services.NewTagService(tagRepo, articleRepo)
 	commentService := services.NewCommentService(commentRepo, articleRepo)
 	// 图片上传目录从配置文件读取
-	imageService := services.NewImageService(imageRepo, config.AppConfig.Upload.Dir)
+	imageService := services.NewImageService(imageRepo, config.Get().Upload.Dir)
+	dashboardService := services.NewDashboardService(statsRepo)
+	auditService := services.NewAuditService(auditLogRepo)
+	reindexService := services.NewReindexService(articleRepo)
+	exportService := services.NewExportService(userRepo, articleRepo, config.Get().Export.Dir, config.Get().Export.MaxRows)
+	emailSender := services.NewEmailSender(config.Get().Email)
+	trashService := services.NewTrashService(trashRepo, articleRepo, userRepo, categoryRepo, tagRepo)
+	reportService := services.NewReportService(dashboardService, emailSender, config.Get().Report.WeeklyRecipients)
+	reloadService := services.NewReloadService()
+	// 注册后文章、评论、用户服务的内容事件才会投递到 Webhook
+	webhookService := services.NewWebhookService(webhookRepo, config.Get().Webhook)
+	// 退订链接使用 JWT 密钥签名
+	newsletterService := services.NewNewsletterService(newsletterRepo, articleRepo, emailSender,
+		config.Get().Newsletter, config.Get().Server, config.Get().JWT.Secret)
+	// 注册后新账号为 pending 状态，验证邮箱后才能登录
+	emailVerificationService := services.NewEmailVerificationService(repository.NewEmailVerificationRepository(), userRepo, emailSender, config.Get().Server)
+	userService.SetEmailVerification(emailVerificationService)
+	passwordResetService := services.NewPasswordResetService(repository.NewPasswordResetRepository(), userRepo, repository.NewRefreshTokenRepository(), emailSender, config.Get().Server)
+	// 注册后文章和评论的提交内容会经过敏感词过滤
+	contentFilter := services.NewContentFilter()
+	if err := contentFilter.LoadFile(config.Get().ContentFilter.WordsFile); err != nil {
+		l := logger.GetLogger()
+		l.Warn().Err(err).Msg("Failed to load content filter words file")
+	}
+	// 注册后评论通过和文章审核结果会生成站内通知
+	notificationService := services.NewNotificationService(notificationRepo, articleRepo, commentRepo)
 
 	// 初始化Handler
 	userHandler := handlers.NewUserHandler(userService, smsService, jwtMgr)
@@ -82,30 +182,96 @@ func main() {
 	tagHandler := handlers.NewTagHandler(tagService)
 	commentHandler := handlers.NewCommentHandler(commentService)
 	imageHandler := handlers.NewImageHandler(imageService)
-	adminHandler := handlers.NewAdminHandler()
+	adminHandler := handlers.NewAdminHandler(dashboardService, reloadService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	settingsHandler := handlers.NewSettingsHandler(settingsService)
+	flagHandler := handlers.NewFlagHandler(flagService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	newsletterHandler := handlers.NewNewsletterHandler(newsletterService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	graphqlHandler := handlers.NewGraphQLHandler(graph.NewServer(articleService, categoryService, tagService, commentRepo, userRepo, config.Get().GraphQL))
+	auditHandler := handlers.NewAuditHandler(auditService)
+	searchHandler := handlers.NewSearchHandler(reindexService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	previewHandler := handlers.NewPreviewHandler(services.NewPreviewService(articleRepo, jwtMgr))
+	authorHandler := handlers.NewAuthorHandler(userService, articleService)
+	emailVerificationHandler := handlers.NewEmailVerificationHandler(emailVerificationService)
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
+	seoHandler := handlers.NewSEOHandler(services.NewSitemapService(articleRepo, categoryRepo, tagRepo))
 
 	// 设置Gin模式
-	gin.SetMode(config.AppConfig.Server.Mode)
+	gin.SetMode(config.Get().Server.Mode)
 
 	// 创建路由
 	router := gin.New()
+	// 只采信可信代理转发的 X-Forwarded-For，未配置时 ClientIP 取连接的远端地址，防止伪造请求头绕过限流
+	if err := router.SetTrustedProxies(config.Get().Server.TrustedProxies); err != nil {
+		l := logger.GetLogger()
+		l.Fatal().Err(err).Msg("Invalid trusted proxies")
+	}
+
+	// 可热加载的运行时组件：配置重新加载（SIGHUP 或管理接口）后更新
+	rateLimitCfg := config.Get().RateLimit
+	// 登录用户接口按用户 ID 计数，ExemptRoles 中的角色（默认 admin）不限流
+	rateLimiter := middleware.NewRateLimiter(rateLimitCfg.Requests, rateLimitCfg.Window())
+	rateLimiter.SetPerUser(true)
+	rateLimiter.SetExemptRoles(rateLimitCfg.ExemptRoles)
+	// 评论、短信验证码等接口使用 rate_limit.buckets 中单独配置的额度
+	rateBuckets := middleware.NewRateLimitBuckets(rateLimitCfg)
+	subscribeLimiter := middleware.NewRateLimiter(config.Get().Newsletter.SubscribeLimit, time.Hour)
+	// 点赞：每个 IP 对同一篇文章单独计数（按解析后的文章 ID，而不是原始路径）
+	likeLimiter := middleware.NewRateLimiter(rateLimitCfg.LikesPerMinute, time.Minute).WithKey(handlers.LikeRateLimitKey)
+	likeAuth := middleware.NewAuthSwitch(rateLimitCfg.LikesRequireAuth)
 
 	// 中间件
-	router.Use(middleware.LoggerMiddleware())
+	router.Use(middleware.RequestIDMiddleware()) // 请求 ID 需在日志中间件之前写入上下文
+	router.Use(middleware.SampledLoggerMiddleware(middleware.NewRequestSampler(
+		logCfg.SampleRate,
+		logCfg.SampleRoutes,
+		time.Duration(logCfg.SlowRequestMs)*time.Millisecond,
+	)))
 	router.Use(metrics.MetricsMiddleware()) // Prometheus metrics中间件
-	router.Use(middleware.CORSMiddleware())
-	router.Use(gin.Recovery())
-
-	// 健康检查
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	corsPolicy := middleware.NewCORSPolicy(config.Get().CORS.AllowedOrigins)
+	router.Use(corsPolicy.Middleware())
+	router.Use(middleware.RecoveryMiddleware())
+	// 维护模式：健康检查、监控和维护模式开关本身不受影响，管理员照常访问；
+	// 登录和刷新 token 也放行，否则管理员的 token 在维护期间过期后无法再关闭维护模式
+	router.Use(middleware.MaintenanceMiddleware(jwtMgr, "/health", "/health/live", "/readyz", "/metrics", "/api/v1/admin/system/maintenance",
+		"/api/v1/auth/login", "/api/v1/auth/refresh"))
+
+	reloadService.OnReload(func(cfg *config.Config) error {
+		corsPolicy.SetAllowedOrigins(cfg.CORS.AllowedOrigins)
+		rateLimiter.SetLimit(cfg.RateLimit.Requests, cfg.RateLimit.Window())
+		rateLimiter.SetExemptRoles(cfg.RateLimit.ExemptRoles)
+		rateBuckets.SetConfig(cfg.RateLimit)
+		subscribeLimiter.SetLimit(cfg.Newsletter.SubscribeLimit, time.Hour)
+		likeLimiter.SetLimit(cfg.RateLimit.LikesPerMinute, time.Minute)
+		likeAuth.SetRequired(cfg.RateLimit.LikesRequireAuth)
+		loginGuard.SetLimits(cfg.RateLimit.LoginMaxFailures, cfg.RateLimit.LoginIPMaxFailures, cfg.RateLimit.LoginWindow())
+		return logger.SetLevels(cfg.Log.Level, cfg.Log.ModuleLevels)
+	})
+	// 敏感词文件在每次重新加载时重新读取（路径未变也会读取修改后的内容）
+	reloadService.OnReload(func(cfg *config.Config) error {
+		return contentFilter.LoadFile(cfg.ContentFilter.WordsFile)
 	})
 
+	// 健康检查：/health/live 只表示进程存活；/health 和 /readyz 检查数据库、Redis、Elasticsearch，必需依赖不可用时返回 503
+	healthHandler := handlers.NewHealthHandler()
+	router.GET("/health/live", healthHandler.Live)
+	router.GET("/health", healthHandler.Ready)
+	router.GET("/readyz", healthHandler.Ready)
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// 搜索引擎抓取规则
+	router.GET("/robots.txt", seoHandler.Robots)
+	router.GET("/sitemap.xml", seoHandler.Sitemap)
+	router.GET("/sitemaps/:file", seoHandler.SitemapPage)
+
 	// 静态文件服务：上传的图片（需要在API路由组之前，避免路径冲突）
-	router.Static("/uploads/images", config.AppConfig.Upload.Dir)
+	router.Static("/uploads/images", config.Get().Upload.Dir)
 
 	// API路由组
 	api := router.Group("/api/v1")
@@ -116,42 +282,96 @@ func main() {
 			// 用户认证
 			public.POST("/auth/register", userHandler.Register)
 			public.POST("/auth/login", userHandler.Login)
-			public.POST("/auth/send-sms-code", userHandler.SendSMSCode)
+			public.POST("/auth/send-sms-code", rateBuckets.Middleware("sms_send"), userHandler.SendSMSCode)
 			public.POST("/auth/login-phone", userHandler.LoginWithPhone)
+			public.POST("/auth/refresh", userHandler.Refresh)
+			public.POST("/auth/logout", userHandler.Logout)
+			public.POST("/auth/verify-email", emailVerificationHandler.Verify)
+			public.POST("/auth/resend-verification", emailVerificationHandler.Resend)
+			public.POST("/auth/forgot-password", passwordResetHandler.Forgot)
+			public.POST("/auth/reset-password", passwordResetHandler.Reset)
+
+			// 作者公开主页
+			public.GET("/users/:id/public", authorHandler.Profile)
+			public.GET("/users/:id/articles", authorHandler.Articles)
 
 			// 文章（公开访问）
 			public.GET("/articles", articleHandler.List)
-			public.GET("/articles/:id", articleHandler.GetByID)
-			public.GET("/articles/slug/:slug", articleHandler.GetBySlug)
-			public.POST("/articles/:id/like", articleHandler.Like)
+			public.GET("/articles/archive", articleHandler.Archive)
+			// 草稿预览：凭作者生成的预览 token 只读访问，不需要登录
+			public.GET("/articles/preview", previewHandler.Preview)
+			// 可选登录：详情中的 liked 按当前用户判断，未登录时按 IP
+			public.GET("/articles/:id", middleware.OptionalAuthMiddleware(jwtMgr), articleHandler.GetByID)
+			public.GET("/articles/slug/:slug", middleware.OptionalAuthMiddleware(jwtMgr), articleHandler.GetBySlug)
+			public.GET("/articles/slug/:slug/meta", articleHandler.GetMeta)
+			// 可选登录：作者可以导出自己未发布的文章
+			public.GET("/articles/:id/export.html", middleware.OptionalAuthMiddleware(jwtMgr), articleHandler.ExportHTML)
+			// 先认证再限流，限流通过后才写入点赞计数
+			public.POST("/articles/:id/like", likeAuth.Middleware(jwtMgr), likeLimiter.Middleware(), articleHandler.Like)
+			public.DELETE("/articles/:id/like", likeAuth.Middleware(jwtMgr), likeLimiter.Middleware(), articleHandler.Unlike)
 
 			// 分类和标签
 			public.GET("/categories", categoryHandler.List)
+			public.GET("/categories/tree", categoryHandler.Tree)
+			public.GET("/categories/autocomplete", categoryHandler.Autocomplete)
 			public.GET("/tags", tagHandler.List)
+			public.GET("/tags/autocomplete", middleware.OptionalAuthMiddleware(jwtMgr), tagHandler.Autocomplete)
+			public.GET("/tags/slug/:slug", tagHandler.GetBySlug)
 
 			// 评论（使用文章 ID 路径参数 id，与 /articles/:id 保持一致）
 			public.GET("/articles/:id/comments", commentHandler.GetByArticleID)
-			public.POST("/articles/:id/comments", commentHandler.Create)
+			// 可选登录：登录用户的评论记录作者，发表评论按用户（未登录时按 IP）限流
+			public.POST("/articles/:id/comments", middleware.OptionalAuthMiddleware(jwtMgr), rateBuckets.Middleware("comment_create"), commentHandler.Create)
+			public.GET("/comments/:id", commentHandler.GetByID)
 
 			// 图片（公开访问）
 			public.GET("/images", imageHandler.List)
 			public.GET("/images/:id", imageHandler.GetByID)
+
+			// 邮件订阅（订阅请求按 IP 限流）
+			public.POST("/newsletter/subscribe", subscribeLimiter.Middleware(), newsletterHandler.Subscribe)
+			public.GET("/newsletter/confirm", newsletterHandler.Confirm)
+			public.GET("/newsletter/unsubscribe", newsletterHandler.Unsubscribe)
+			public.POST("/newsletter/unsubscribe", newsletterHandler.Unsubscribe)
+
+			// GraphQL 只读接口（认证可选，匿名只能查看已发布文章）
+			public.POST("/graphql", middleware.OptionalAuthMiddleware(jwtMgr), graphqlHandler.Query)
 		}
 
 		// 需要认证的路由
 		authenticated := api.Group("")
 		authenticated.Use(middleware.AuthMiddleware(jwtMgr))
-		authenticated.Use(middleware.RateLimitMiddleware(100, time.Minute))
+		authenticated.Use(rateLimiter.Middleware())
+		authenticated.Use(middleware.ImpersonationAuditMiddleware(auditService))
 		{
+			// 结束模拟登录
+			authenticated.POST("/auth/stop-impersonation", userHandler.StopImpersonation)
+
 			// 用户
 			authenticated.GET("/users/profile", userHandler.GetProfile)
 			authenticated.PUT("/users/profile", userHandler.UpdateProfile)
 			authenticated.PUT("/users/password", userHandler.ChangePassword)
+			authenticated.GET("/users/articles", articleHandler.ListMine)
+			authenticated.POST("/users/article-states", articleHandler.ArticleStates)
+
+			// 站内通知
+			authenticated.GET("/users/notifications", notificationHandler.List)
+			authenticated.GET("/users/notifications/unread-count", notificationHandler.UnreadCount)
+			authenticated.POST("/users/notifications/read-all", notificationHandler.MarkAllRead)
+			authenticated.POST("/users/notifications/:id/read", notificationHandler.MarkRead)
 
 			// 文章（需要认证）
 			authenticated.POST("/articles", articleHandler.Create)
 			authenticated.PUT("/articles/:id", articleHandler.Update)
 			authenticated.DELETE("/articles/:id", articleHandler.Delete)
+			authenticated.POST("/articles/:id/generate-summary", middleware.RoleMiddleware("admin", "editor", "author"), articleHandler.GenerateSummary)
+			authenticated.GET("/articles/:id/status-history", articleHandler.StatusHistory)
+			authenticated.GET("/articles/:id/revisions", articleHandler.ListRevisions)
+			authenticated.GET("/articles/:id/revisions/:rev_id", articleHandler.GetRevision)
+			authenticated.GET("/articles/:id/revisions/:rev_id/diff/:to_id", articleHandler.DiffRevisions)
+			authenticated.POST("/articles/:id/revisions/:rev_id/restore", articleHandler.RestoreRevision)
+			authenticated.POST("/articles/:id/preview-token", previewHandler.CreateToken)
+			authenticated.DELETE("/articles/:id/preview-token/:jti", previewHandler.RevokeToken)
 
 			// 图片（需要认证）
 			authenticated.POST("/images/upload", imageHandler.Upload)
@@ -163,37 +383,94 @@ func main() {
 		admin := api.Group("/admin")
 		admin.Use(middleware.AuthMiddleware(jwtMgr))
 		admin.Use(middleware.RoleMiddleware("admin"))
+		admin.Use(middleware.AuditMiddleware(auditService))
 		{
 			// 仪表盘 & 系统配置
 			admin.GET("/dashboard", adminHandler.Dashboard)
+			admin.GET("/dashboard/top", adminHandler.DashboardTop)
+			admin.GET("/stats/categories", categoryHandler.Stats)
+			admin.GET("/stats/tags", tagHandler.Stats)
 			admin.GET("/system/config", adminHandler.SystemConfig)
+			admin.GET("/system/status", adminHandler.SystemStatus)
+			admin.POST("/system/reload", adminHandler.ReloadConfig)
+			admin.POST("/system/maintenance", settingsHandler.SetMaintenance)
+			admin.POST("/cache/flush", adminHandler.FlushCache)
+			admin.POST("/reports/weekly/send-test", reportHandler.SendWeeklyTest)
+			admin.GET("/settings", settingsHandler.List)
+			admin.PUT("/settings", settingsHandler.Update)
+			admin.GET("/flags", flagHandler.List)
+			admin.PUT("/flags", flagHandler.Update)
+
+			// Webhook
+			admin.GET("/webhooks", webhookHandler.List)
+			admin.POST("/webhooks", webhookHandler.Create)
+			admin.GET("/webhooks/:id", webhookHandler.GetByID)
+			admin.PUT("/webhooks/:id", webhookHandler.Update)
+			admin.DELETE("/webhooks/:id", webhookHandler.Delete)
+			admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+			admin.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+			// 邮件订阅
+			admin.GET("/newsletter/subscribers", newsletterHandler.ListSubscribers)
+			admin.GET("/newsletter/campaigns", newsletterHandler.ListCampaigns)
+			admin.POST("/newsletter/campaigns", newsletterHandler.CreateCampaign)
+			admin.GET("/newsletter/campaigns/:id", newsletterHandler.GetCampaign)
+
+			// 回收站
+			admin.GET("/trash", trashHandler.List)
+			admin.POST("/trash/:type/:id/restore", trashHandler.Restore)
+			admin.DELETE("/trash/:type/:id", trashHandler.Purge)
+
+			// 审计日志
+			admin.GET("/audit-logs", auditHandler.List)
+			admin.GET("/audit-logs/:id", auditHandler.GetByID)
+
+			// 搜索索引
+			admin.POST("/search/reindex", searchHandler.Reindex)
+			admin.GET("/search/reindex/:job", searchHandler.ReindexStatus)
+
+			// 数据导出
+			admin.GET("/export/users.csv", exportHandler.ExportUsers)
+			admin.GET("/export/articles.csv", exportHandler.ExportArticles)
+			admin.GET("/export/jobs/:id", exportHandler.GetJob)
+			admin.GET("/export/jobs/:id/download", exportHandler.DownloadJob)
 
 			admin.GET("/users", userHandler.ListUsers)
 			admin.GET("/users/:id", userHandler.GetUser)
 			admin.PUT("/users/:id", userHandler.AdminUpdateUser)
+			admin.POST("/users/:id/impersonate", userHandler.Impersonate)
 			// 管理后台文章管理
 			admin.GET("/articles", articleHandler.AdminList)
+			// 已删除文章（回收站中文章类型的快捷入口）
+			admin.GET("/articles/trash", trashHandler.ListArticles)
+			admin.POST("/articles/:id/restore", trashHandler.RestoreArticle)
+			admin.DELETE("/articles/:id/purge", trashHandler.PurgeArticle)
 			admin.GET("/articles/:id", articleHandler.AdminGetByID)
 			admin.PUT("/articles/:id/status", articleHandler.AdminUpdateStatus)
 			admin.DELETE("/articles/:id", articleHandler.AdminDelete)
+			admin.POST("/articles/reading-stats/backfill", articleHandler.BackfillReadingStats)
+
+			// 管理后台评论列表（按状态筛选待审核、被举报的评论）
+			admin.GET("/comments", commentHandler.AdminList)
 
 			// 管理后台分类与标签管理
-			admin.GET("/categories", categoryHandler.List)
+			admin.GET("/categories", categoryHandler.AdminList)
 			admin.POST("/categories", categoryHandler.Create)
 			admin.GET("/categories/:id", categoryHandler.GetByID)
 			admin.PUT("/categories/:id", categoryHandler.Update)
 			admin.DELETE("/categories/:id", categoryHandler.Delete)
 
-			admin.GET("/tags", tagHandler.List)
+			admin.GET("/tags", tagHandler.AdminList)
 			admin.POST("/tags", tagHandler.Create)
 			admin.GET("/tags/:id", tagHandler.GetByID)
 			admin.PUT("/tags/:id", tagHandler.Update)
 			admin.DELETE("/tags/:id", tagHandler.Delete)
+			admin.POST("/tags/:id/merge", tagHandler.Merge)
 		}
 	}
 
 	// 启动服务器
-	addr := fmt.Sprintf("%s:%s", config.AppConfig.Server.Host, config.AppConfig.Server.Port)
+	addr := fmt.Sprintf("%s:%s", config.Get().Server.Host, config.Get().Server.Port)
 	srv := &http.Server{
 		Addr:    addr,
 		Handler: router,
@@ -210,6 +487,85 @@ func main() {
 		}
 	}()
 
+	// 启动定时发布 goroutine：发布定时发布时间已到的文章
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if n, err := articleService.PublishDueArticles(ctx); err != nil {
+				errorreport.CaptureError(ctx, err, map[string]string{"worker": "scheduled_publish"})
+				l := logger.GetLogger()
+				l.Warn().Err(err).Msg("Failed to publish scheduled articles")
+			} else if n > 0 {
+				l := logger.GetLogger()
+				l.Info().Int("published", n).Msg("Published scheduled articles")
+			}
+			cancel()
+		}
+	}()
+
+	// 定期刷新系统状态指标（连接池、索引队列、后台任务）和活跃用户数，供 Prometheus 抓取告警
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			services.CollectSystemStatus()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if _, err := services.RefreshActiveUsers(ctx); err != nil {
+				l := logger.GetLogger()
+				l.Warn().Err(err).Msg("Failed to refresh active users")
+			}
+			cancel()
+		}
+	}()
+
+	// 启动审计日志清理 goroutine（按 audit_log_retention_days 设置保留）
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if n, err := auditService.Prune(ctx); err != nil {
+				errorreport.CaptureError(ctx, err, map[string]string{"worker": "audit_prune"})
+				l := logger.GetLogger()
+				l.Warn().Err(err).Msg("Failed to prune audit logs")
+			} else if n > 0 {
+				l := logger.GetLogger()
+				l.Info().Int64("deleted", n).Msg("Pruned expired audit logs")
+			}
+			cancel()
+		}
+	}()
+
+	// 启动导出文件清理 goroutine：任务记录过期后文件无法下载，且包含用户邮箱，不再保留
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if n, err := exportService.PruneExpiredFiles(ctx); err != nil {
+				errorreport.CaptureError(ctx, err, map[string]string{"worker": "export_prune"})
+				l := logger.GetLogger()
+				l.Warn().Err(err).Msg("Failed to prune expired export files")
+			} else if n > 0 {
+				l := logger.GetLogger()
+				l.Info().Int("deleted", n).Msg("Pruned expired export files")
+			}
+			cancel()
+		}
+	}()
+
+	// 启动每周统计邮件调度（REPORT_WEEKLY_SCHEDULE 为空时不启动）
+	if spec := config.Get().Report.WeeklySchedule; spec != "" {
+		if schedule, err := cron.Parse(spec); err != nil {
+			l := logger.GetLogger()
+			l.Warn().Err(err).Str("schedule", spec).Msg("Invalid weekly report schedule, scheduler disabled")
+		} else {
+			go reportService.RunWeeklySchedule(context.Background(), schedule)
+		}
+	}
+
 	// 优雅关闭
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -221,6 +577,15 @@ func main() {
 	l := logger.GetLogger()
 	l.Info().Str("address", addr).Msg("Server started")
 
+	// SIGHUP 重新加载配置（可热加载的部分立即生效，其余记录为需要重启）
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			_, _ = reloadService.Reload(context.Background())
+		}
+	}()
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -237,6 +602,35 @@ func main() {
 		l3.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// 等待进行中的 Webhook 投递，超时后未完成的投递保持 pending，可在管理后台重新投递
+	if err := webhookService.Wait(ctx); err != nil {
+		l3 := logger.GetLogger()
+		l3.Warn().Err(err).Msg("Webhook deliveries still in progress at shutdown")
+	}
+	// 群发中断后已发送的进度保留在群发记录中，未发送的订阅者不会自动补发
+	if err := newsletterService.Wait(ctx); err != nil {
+		l3 := logger.GetLogger()
+		l3.Warn().Err(err).Msg("Newsletter emails still being sent at shutdown")
+	}
+	if err := notificationService.Wait(ctx); err != nil {
+		l3 := logger.GetLogger()
+		l3.Warn().Err(err).Msg("Notifications still being created at shutdown")
+	}
+	// 未发出的验证邮件可以通过重新发送接口补发
+	if err := emailVerificationService.Wait(ctx); err != nil {
+		l3 := logger.GetLogger()
+		l3.Warn().Err(err).Msg("Verification emails still being sent at shutdown")
+	}
+	if err := passwordResetService.Wait(ctx); err != nil {
+		l3 := logger.GetLogger()
+		l3.Warn().Err(err).Msg("Password reset emails still being sent at shutdown")
+	}
+	// 浏览计数先写入 Redis 缓冲，由定时任务回刷数据库
+	if err := services.WaitArticleViewCounts(ctx); err != nil {
+		l3 := logger.GetLogger()
+		l3.Warn().Err(err).Msg("Article view counts still being recorded at shutdown")
+	}
+
 	l4 := logger.GetLogger()
 	l4.Info().Msg("Server exited")
 }
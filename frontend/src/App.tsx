@@ -4,6 +4,9 @@ import { ArticleDetail } from "./components/ArticleDetail";
 import { ArticleEditor } from "./components/ArticleEditor";
 import { Login } from "./components/Login";
 import { Register } from "./components/Register";
+import { VerifyEmail } from "./components/VerifyEmail";
+import { ForgotPassword } from "./components/ForgotPassword";
+import { ResetPassword } from "./components/ResetPassword";
 import { Profile } from "./components/Profile";
 import { AdminUserList } from "./components/AdminUserList";
 import { AdminUserDetail } from "./components/AdminUserDetail";
@@ -88,6 +91,9 @@ export default function App() {
           />
           <Route path="/login" element={<Login />} />
           <Route path="/register" element={<Register />} />
+          <Route path="/verify-email" element={<VerifyEmail />} />
+          <Route path="/forgot-password" element={<ForgotPassword />} />
+          <Route path="/reset-password" element={<ResetPassword />} />
           <Route
             path="/profile"
             element={
@@ -43,6 +43,7 @@ export interface Article {
   view_count: number;
   like_count: number;
   comment_count: number;
+  version: number;
   published_at?: string;
   created_at: string;
   updated_at: string;
@@ -116,6 +117,7 @@ export interface ArticlePayload {
   status?: string;
   category_id?: string | null;
   tag_ids?: string[];
+  expected_version?: number;
 }
 
 export interface Comment {
@@ -73,12 +73,8 @@ export function AdminArticleDetail() {
     if (!window.confirm("确定要删除这篇文章吗？此操作不可恢复。")) return;
     setUpdating(true);
     try {
-      const res = await apiClient.delete<ApiResponse<null>>(
-        `/admin/articles/${id}`
-      );
-      if (res.data.code !== 200) {
-        throw new Error(res.data.message || "删除失败");
-      }
+      // 删除成功返回 204，无响应体
+      await apiClient.delete(`/admin/articles/${id}`);
       showSuccess("文章已删除");
       setArticle(null);
     } catch (e: any) {
@@ -42,6 +42,8 @@ export function ArticleEditor() {
   const [tags, setTags] = useState<Tag[]>([]);
   const [selectedTagIds, setSelectedTagIds] = useState<string[]>([]);
   const [showImagePicker, setShowImagePicker] = useState(false);
+  // 加载文章时的版本号，保存时用于乐观并发检查
+  const [version, setVersion] = useState<number | null>(null);
   const isAdmin = user?.role === "admin";
 
   useEffect(() => {
@@ -92,6 +94,7 @@ export function ArticleEditor() {
           tag_ids_text: ""
         });
         setSelectedTagIds(article.tags?.map((t) => t.id) || []);
+        setVersion(article.version ?? null);
       } catch (e: any) {
         setError(e.message || "发生未知错误");
       } finally {
@@ -121,7 +124,8 @@ export function ArticleEditor() {
     cover_image: form.cover_image,
     status: form.status,
     category_id: form.category_id || undefined,
-    tag_ids: selectedTagIds.length > 0 ? selectedTagIds : undefined
+    tag_ids: selectedTagIds.length > 0 ? selectedTagIds : undefined,
+    expected_version: isEdit && version !== null ? version : undefined
   });
 
   const handleSubmit = async (e: React.FormEvent) => {
@@ -143,12 +147,22 @@ export function ArticleEditor() {
       } else {
         res = await apiClient.post<ApiResponse<Article>>("/articles", payload);
       }
-      if (res.data.code !== 200 || !res.data.data) {
+      if ((res.data.code !== 200 && res.data.code !== 201) || !res.data.data) {
         throw new Error(res.data.message || "操作失败");
       }
       showSuccess(isEdit ? "文章已更新" : "文章已创建");
       navigate(`/articles/${res.data.data.id}`);
     } catch (e: any) {
+      if (e.response?.status === 409 && e.response?.data?.data) {
+        // 文章已被他人修改：保留当前编辑内容，记录服务端最新版本，
+        // 用户核对合并后再次保存即基于最新版本提交
+        const current = e.response.data.data as Article;
+        setVersion(current.version);
+        const msg = `文章已被他人修改（当前版本 ${current.version}），请核对最新内容后再次保存`;
+        setError(msg);
+        showError(msg);
+        return;
+      }
       const msg =
         e.response?.data?.message || e.message || "发生未知错误";
       setError(msg);
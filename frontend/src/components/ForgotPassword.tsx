@@ -0,0 +1,55 @@
+import { useState } from "react";
+import { Link } from "react-router-dom";
+import { apiClient } from "../api/client";
+import { Button } from "./Button";
+
+// ForgotPassword 申请找回密码，向注册邮箱发送重置链接
+// 无论邮箱是否注册，接口都返回成功，页面统一提示
+export function ForgotPassword() {
+  const [email, setEmail] = useState("");
+  const [loading, setLoading] = useState(false);
+  const [submitted, setSubmitted] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setLoading(true);
+    setError(null);
+    try {
+      await apiClient.post("/auth/forgot-password", { email });
+      setSubmitted(true);
+    } catch (e: any) {
+      setError(e.response?.data?.message || e.message || "发送失败");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="auth-form">
+      <h2>找回密码</h2>
+      {submitted ? (
+        <p>如果该邮箱已注册，您将收到重置密码的邮件，链接 30 分钟内有效。</p>
+      ) : (
+        <form onSubmit={handleSubmit}>
+          <label>
+            邮箱
+            <input
+              type="email"
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              required
+            />
+          </label>
+          {error && <p className="error">{error}</p>}
+          <Button type="submit" loading={loading}>
+            发送重置邮件
+          </Button>
+        </form>
+      )}
+      <p className="auth-switch">
+        <Link to="/login">返回登录</Link>
+      </p>
+    </div>
+  );
+}
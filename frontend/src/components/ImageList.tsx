@@ -1,7 +1,7 @@
 import { useEffect, useState } from "react";
 import { Link } from "react-router-dom";
 import { apiClient } from "../api/client";
-import type { ApiResponse, Image, PaginatedResponse } from "../api/types";
+import type { Image, PaginatedResponse } from "../api/types";
 import { useAuth } from "../hooks/useAuth";
 import { Button } from "./Button";
 import { BackButton } from "./BackButton";
@@ -88,10 +88,8 @@ export function ImageList() {
 
     setDeletingId(id);
     try {
-      const res = await apiClient.delete<ApiResponse<null>>(`/images/${id}`);
-      if (res.data.code !== 200) {
-        throw new Error(res.data.message || "删除失败");
-      }
+      // 删除成功返回 204，无响应体
+      await apiClient.delete(`/images/${id}`);
       showSuccess("图片已删除");
       loadImages();
     } catch (e: any) {
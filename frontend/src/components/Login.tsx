@@ -265,6 +265,12 @@ export function Login() {
 
       <p className="auth-switch">
         还没有账号？<Link to="/register">立即注册</Link>
+        {mode === "email" && (
+          <>
+            {" "}
+            <Link to="/forgot-password">忘记密码？</Link>
+          </>
+        )}
       </p>
     </div>
   );
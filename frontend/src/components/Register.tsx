@@ -10,6 +10,7 @@ interface RegisterResponse {
   id: string;
   username: string;
   email: string;
+  status: string;
 }
 
 export function Register() {
@@ -36,10 +37,17 @@ export function Register() {
           password
         }
       );
-      if (res.data.code !== 200) {
+      if (res.data.code !== 201) {
         throw new Error(res.data.message || "注册失败");
       }
 
+      // 需要验证邮箱时不能直接登录，提示用户查收验证邮件
+      if (res.data.data.status === "pending") {
+        showSuccess("注册成功，请查收验证邮件并点击链接激活账号");
+        navigate("/login");
+        return;
+      }
+
       // 注册后自动登录
       const loginRes =
         await apiClient.post<ApiResponse<{ token: string; user?: AuthUser }>>(
@@ -0,0 +1,87 @@
+import { useState } from "react";
+import { Link, useNavigate, useSearchParams } from "react-router-dom";
+import { apiClient } from "../api/client";
+import { Button } from "./Button";
+import { useMessage } from "./MessageProvider";
+
+// ResetPassword 找回密码邮件中的链接指向此页面（/reset-password?token=...），设置新密码
+export function ResetPassword() {
+  const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get("token") ?? "";
+  const { showSuccess } = useMessage();
+
+  const [password, setPassword] = useState("");
+  const [confirm, setConfirm] = useState("");
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (password !== confirm) {
+      setError("两次输入的密码不一致");
+      return;
+    }
+    setLoading(true);
+    setError(null);
+    try {
+      await apiClient.post("/auth/reset-password", {
+        token,
+        new_password: password
+      });
+      showSuccess("密码已重置，请使用新密码登录");
+      navigate("/login");
+    } catch (e: any) {
+      setError(e.response?.data?.message || e.message || "重置失败");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (!token) {
+    return (
+      <div className="auth-form">
+        <h2>重置密码</h2>
+        <p className="error">重置链接无效</p>
+        <p className="auth-switch">
+          <Link to="/forgot-password">重新申请</Link>
+        </p>
+      </div>
+    );
+  }
+
+  return (
+    <div className="auth-form">
+      <h2>重置密码</h2>
+      <form onSubmit={handleSubmit}>
+        <label>
+          新密码
+          <input
+            type="password"
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            minLength={6}
+            required
+          />
+        </label>
+        <label>
+          确认新密码
+          <input
+            type="password"
+            value={confirm}
+            onChange={(e) => setConfirm(e.target.value)}
+            minLength={6}
+            required
+          />
+        </label>
+        {error && <p className="error">{error}</p>}
+        <Button type="submit" loading={loading}>
+          重置密码
+        </Button>
+      </form>
+      <p className="auth-switch">
+        <Link to="/forgot-password">重新申请重置链接</Link>
+      </p>
+    </div>
+  );
+}
@@ -0,0 +1,79 @@
+import { useEffect, useState } from "react";
+import { Link, useSearchParams } from "react-router-dom";
+import { apiClient } from "../api/client";
+import type { ApiResponse } from "../api/types";
+import { Button } from "./Button";
+import { useMessage } from "./MessageProvider";
+
+// VerifyEmail 验证邮件中的链接指向此页面（/verify-email?token=...），打开后自动提交验证
+export function VerifyEmail() {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get("token") ?? "";
+  const { showSuccess, showError } = useMessage();
+
+  const [status, setStatus] = useState<"verifying" | "verified" | "failed">(
+    token ? "verifying" : "failed"
+  );
+  const [error, setError] = useState<string | null>(
+    token ? null : "验证链接无效"
+  );
+  const [email, setEmail] = useState("");
+  const [resending, setResending] = useState(false);
+
+  useEffect(() => {
+    if (!token) {
+      return;
+    }
+    apiClient
+      .post<ApiResponse<unknown>>("/auth/verify-email", { token })
+      .then(() => setStatus("verified"))
+      .catch((e: any) => {
+        setStatus("failed");
+        setError(e.response?.data?.message || e.message || "验证失败");
+      });
+  }, [token]);
+
+  const handleResend = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setResending(true);
+    try {
+      await apiClient.post("/auth/resend-verification", { email });
+      showSuccess("如果该邮箱已注册且尚未验证，您将收到新的验证邮件");
+    } catch (e: any) {
+      showError(e.response?.data?.message || e.message || "发送失败");
+    } finally {
+      setResending(false);
+    }
+  };
+
+  return (
+    <div className="auth-form">
+      <h2>验证邮箱</h2>
+      {status === "verifying" && <p>正在验证...</p>}
+      {status === "verified" && (
+        <p>
+          邮箱验证成功，<Link to="/login">前往登录</Link>
+        </p>
+      )}
+      {status === "failed" && (
+        <>
+          <p className="error">{error}</p>
+          <form onSubmit={handleResend}>
+            <label>
+              邮箱
+              <input
+                type="email"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                required
+              />
+            </label>
+            <Button type="submit" loading={resending}>
+              重新发送验证邮件
+            </Button>
+          </form>
+        </>
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,514 @@
+// Package backup 实现内容数据的备份归档格式，以及导出（cmd/backup）和恢复（cmd/restore）的流程
+//
+// 归档为 zip 文件：
+//   - manifest.json: 格式版本、创建时间、各表行数和时间列、上传文件数
+//   - tables/<表名>.jsonl: 每行一个 JSON 对象（列名到值），按主键排序
+//   - uploads/<相对路径>: 可选，上传目录中的文件
+//
+// 设计考虑：
+//   - 按表分批读写（主键游标分页、多行 INSERT），内存占用与数据量无关
+//   - 直接读写整行，保留 UUID、时间戳、密码哈希和软删除状态，恢复后与原库一致
+//   - zip 的中央目录位于文件末尾，manifest 可以在数据写完后再写入，读取时不依赖条目顺序
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// FormatVersion 当前归档格式版本，格式不兼容地变化时递增
+const FormatVersion = 1
+
+// DefaultBatchSize 默认每批读写的行数
+const DefaultBatchSize = 500
+
+const (
+	manifestName  = "manifest.json"
+	tablesPrefix  = "tables/"
+	uploadsPrefix = "uploads/"
+)
+
+// Manifest 归档清单
+type Manifest struct {
+	FormatVersion int             `json:"format_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Driver        string          `json:"driver"`
+	Tables        []ManifestTable `json:"tables"`
+	// Uploads 归档中的上传文件数，未包含上传文件时为 0
+	Uploads int `json:"uploads"`
+}
+
+// ManifestTable 归档中一张表的信息
+type ManifestTable struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+	// TimeColumns 时间类型的列，恢复时把 JSON 中的字符串解析回时间
+	TimeColumns []string `json:"time_columns,omitempty"`
+}
+
+// Table 根据表名查找
+func (m *Manifest) Table(name string) (ManifestTable, bool) {
+	for _, t := range m.Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return ManifestTable{}, false
+}
+
+// NotEmptyError 恢复的目标表中已有数据
+type NotEmptyError struct {
+	Tables []string
+}
+
+func (e *NotEmptyError) Error() string {
+	return "target tables are not empty: " + strings.Join(e.Tables, ", ")
+}
+
+// ResolveTables 解析 --tables 参数
+// names: 表名列表，为空表示全部
+// 返回: 按外键依赖排序的表定义，包含未知表名时返回错误
+func ResolveTables(names []string) ([]repository.BackupTable, error) {
+	if len(names) == 0 {
+		return repository.BackupTables, nil
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := repository.LookupBackupTable(name); !ok {
+			return nil, fmt.Errorf("unknown table %q (supported: %s)", name, strings.Join(tableNames(repository.BackupTables), ", "))
+		}
+		wanted[name] = true
+	}
+	var tables []repository.BackupTable
+	for _, t := range repository.BackupTables {
+		if wanted[t.Name] {
+			tables = append(tables, t)
+		}
+	}
+	return tables, nil
+}
+
+func tableNames(tables []repository.BackupTable) []string {
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// ExportOptions 导出参数
+type ExportOptions struct {
+	Tables []repository.BackupTable
+	// Driver 源数据库驱动，写入 manifest 供排查
+	Driver string
+	// UploadsDir 非空时把该目录中的文件一并写入归档
+	UploadsDir string
+	BatchSize  int
+}
+
+// Export 把数据导出为归档并写入 w
+// 返回: 写入的清单
+// 注意: 各表分别读取，不在同一个事务快照中；导出期间有写入时，表之间可能不完全一致，建议在维护窗口执行
+func Export(ctx context.Context, repo *repository.BackupRepository, w io.Writer, opts ExportOptions) (*Manifest, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Driver:        opts.Driver,
+	}
+
+	zw := zip.NewWriter(w)
+	for _, table := range opts.Tables {
+		entry, err := exportTable(ctx, repo, zw, table, opts.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", table.Name, err)
+		}
+		manifest.Tables = append(manifest.Tables, entry)
+	}
+
+	if opts.UploadsDir != "" {
+		n, err := exportUploads(zw, opts.UploadsDir)
+		if err != nil {
+			return nil, fmt.Errorf("export uploads: %w", err)
+		}
+		manifest.Uploads = n
+	}
+
+	mw, err := zw.Create(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func exportTable(ctx context.Context, repo *repository.BackupRepository, zw *zip.Writer, table repository.BackupTable, batchSize int) (ManifestTable, error) {
+	entry := ManifestTable{Name: table.Name}
+	fw, err := zw.Create(tablesPrefix + table.Name + ".jsonl")
+	if err != nil {
+		return entry, err
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetEscapeHTML(false)
+
+	timeColumns := make(map[string]bool)
+	var after []interface{}
+	for {
+		rows, err := repo.ExportBatch(ctx, table, after, batchSize)
+		if err != nil {
+			return entry, err
+		}
+		for _, row := range rows {
+			for col, v := range row {
+				if _, ok := v.(time.Time); ok {
+					timeColumns[col] = true
+				}
+				row[col] = exportValue(v)
+			}
+			if err := enc.Encode(row); err != nil {
+				return entry, err
+			}
+		}
+		entry.Rows += int64(len(rows))
+		if len(rows) < batchSize {
+			break
+		}
+		last := rows[len(rows)-1]
+		after = make([]interface{}, len(table.Keys))
+		for i, key := range table.Keys {
+			after[i] = last[key]
+		}
+	}
+
+	for col := range timeColumns {
+		entry.TimeColumns = append(entry.TimeColumns, col)
+	}
+	sort.Strings(entry.TimeColumns)
+	return entry, nil
+}
+
+// exportValue 把驱动返回的值转换为可以写入 JSON 的值
+func exportValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case [16]byte:
+		return uuid.UUID(val).String()
+	case time.Time:
+		return val.UTC()
+	}
+	return v
+}
+
+func exportUploads(zw *zip.Writer, dir string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		fw, err := zw.Create(uploadsPrefix + filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(fw, f); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) && count == 0 {
+		// 上传目录不存在：没有上传过文件
+		return 0, nil
+	}
+	return count, err
+}
+
+// ReadManifest 读取并校验归档清单
+func ReadManifest(zr *zip.Reader) (*Manifest, error) {
+	f, err := zr.Open(manifestName)
+	if err != nil {
+		return nil, fmt.Errorf("archive has no %s: %w", manifestName, err)
+	}
+	defer f.Close()
+	var manifest Manifest
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestName, err)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("unsupported archive format version %d (supported: 1-%d)", manifest.FormatVersion, FormatVersion)
+	}
+	return &manifest, nil
+}
+
+// RestoreOptions 恢复参数
+type RestoreOptions struct {
+	Tables []repository.BackupTable
+	// Force 为 true 时允许写入非空的表，主键或唯一约束冲突的行跳过
+	Force bool
+	// UploadsDir 非空时把归档中的上传文件写入该目录，已存在的文件保留不覆盖
+	UploadsDir string
+	BatchSize  int
+}
+
+// TableResult 单张表的恢复结果
+type TableResult struct {
+	Name string
+	// Expected 清单中记录的行数
+	Expected int64
+	// Read 从归档读取的行数
+	Read int64
+	// Inserted 实际写入的行数，Force 时可能因冲突少于 Read
+	Inserted int64
+}
+
+// RestoreResult 恢复结果
+type RestoreResult struct {
+	Tables  []TableResult
+	Uploads int
+}
+
+// Restore 把归档恢复到当前数据库
+// 返回: 各表的行数统计；目标表非空且未指定 Force 时返回 *NotEmptyError，不写入任何数据
+// 注意:
+//   - 按外键依赖顺序写入，只恢复部分表时需要自行保证被引用的数据已存在
+//   - 每批单独提交，中途失败时已写入的批次不会回滚，可清空后重试或使用 Force 续写
+//   - 读取的行数与清单不一致（归档损坏）、或未指定 Force 时写入行数或表行数与清单不一致，返回错误
+func Restore(ctx context.Context, repo *repository.BackupRepository, zr *zip.Reader, opts RestoreOptions) (*RestoreResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	manifest, err := ReadManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []repository.BackupTable
+	for _, table := range opts.Tables {
+		if _, ok := manifest.Table(table.Name); ok {
+			tables = append(tables, table)
+		}
+	}
+
+	if !opts.Force {
+		var notEmpty []string
+		for _, table := range tables {
+			has, err := repo.HasRows(ctx, table)
+			if err != nil {
+				return nil, fmt.Errorf("check %s: %w", table.Name, err)
+			}
+			if has {
+				notEmpty = append(notEmpty, table.Name)
+			}
+		}
+		if len(notEmpty) > 0 {
+			return nil, &NotEmptyError{Tables: notEmpty}
+		}
+	}
+
+	result := &RestoreResult{}
+	for _, table := range tables {
+		entry, _ := manifest.Table(table.Name)
+		res, err := restoreTable(ctx, repo, zr, table, entry, opts.BatchSize)
+		if err != nil {
+			return result, fmt.Errorf("restore %s: %w", table.Name, err)
+		}
+		result.Tables = append(result.Tables, res)
+
+		if res.Read != res.Expected {
+			return result, fmt.Errorf("restore %s: read %d rows, manifest records %d", table.Name, res.Read, res.Expected)
+		}
+		if !opts.Force {
+			count, err := repo.Count(ctx, table)
+			if err != nil {
+				return result, fmt.Errorf("count %s: %w", table.Name, err)
+			}
+			if res.Inserted != res.Expected || count != res.Expected {
+				return result, fmt.Errorf("restore %s: inserted %d rows, table has %d, manifest records %d", table.Name, res.Inserted, count, res.Expected)
+			}
+		}
+	}
+
+	if opts.UploadsDir != "" {
+		n, err := restoreUploads(zr, opts.UploadsDir)
+		if err != nil {
+			return result, fmt.Errorf("restore uploads: %w", err)
+		}
+		result.Uploads = n
+	}
+	return result, nil
+}
+
+// parentLink 延后回填的自引用
+type parentLink struct {
+	id, parentID interface{}
+}
+
+func restoreTable(ctx context.Context, repo *repository.BackupRepository, zr *zip.Reader, table repository.BackupTable, entry ManifestTable, batchSize int) (TableResult, error) {
+	res := TableResult{Name: table.Name, Expected: entry.Rows}
+	f, err := zr.Open(tablesPrefix + table.Name + ".jsonl")
+	if err != nil {
+		return res, err
+	}
+	defer f.Close()
+
+	timeColumns := make(map[string]bool, len(entry.TimeColumns))
+	for _, c := range entry.TimeColumns {
+		timeColumns[c] = true
+	}
+
+	var parents []parentLink
+	batch := make([]map[string]interface{}, 0, batchSize)
+	flush := func() error {
+		n, err := repo.InsertBatch(ctx, table, batch)
+		if err != nil {
+			return err
+		}
+		res.Inserted += n
+		batch = batch[:0]
+		return nil
+	}
+
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	for {
+		var row map[string]interface{}
+		if err := dec.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return res, fmt.Errorf("line %d: %w", res.Read+1, err)
+		}
+		res.Read++
+		for col, v := range row {
+			value, err := restoreValue(v, timeColumns[col])
+			if err != nil {
+				return res, fmt.Errorf("line %d column %s: %w", res.Read, col, err)
+			}
+			row[col] = value
+		}
+		// 父级可能排在后面，先写入 NULL
+		if table.ParentColumn != "" && row[table.ParentColumn] != nil {
+			parents = append(parents, parentLink{id: row[table.Keys[0]], parentID: row[table.ParentColumn]})
+			row[table.ParentColumn] = nil
+		}
+
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return res, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return res, err
+	}
+
+	for _, link := range parents {
+		if err := repo.SetParent(ctx, table, link.id, link.parentID); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// restoreValue 把 JSON 解码得到的值转换为写入数据库的值
+func restoreValue(v interface{}, isTime bool) (interface{}, error) {
+	switch val := v.(type) {
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n, nil
+		}
+		return val.Float64()
+	case string:
+		if isTime {
+			return time.Parse(time.RFC3339Nano, val)
+		}
+	case map[string]interface{}, []interface{}:
+		// 导出时不会产生嵌套值，按 JSON 文本写入
+		data, err := json.Marshal(val)
+		return string(data), err
+	}
+	return v, nil
+}
+
+func restoreUploads(zr *zip.Reader, dir string) (int, error) {
+	count := 0
+	for _, zf := range zr.File {
+		if !strings.HasPrefix(zf.Name, uploadsPrefix) || strings.HasSuffix(zf.Name, "/") {
+			continue
+		}
+		rel := strings.TrimPrefix(zf.Name, uploadsPrefix)
+		// 拒绝绝对路径和 ..，防止写到上传目录之外
+		if !filepath.IsLocal(filepath.FromSlash(rel)) || path.Clean(rel) != rel {
+			return count, fmt.Errorf("invalid upload path %q", zf.Name)
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(rel))
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		if err := extractFile(zf, dest); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func extractFile(zf *zip.File, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	src, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/pkg/s3"
+)
+
+// s3Scheme 归档地址使用对象存储时的前缀，如 s3://backups/blog/2024-01-01.zip
+const s3Scheme = "s3://"
+
+// ParseS3URL 解析 s3://bucket/key 形式的归档地址
+// 返回: 不是 s3:// 地址时 ok 为 false；bucket 或 key 为空时返回错误
+func ParseS3URL(location string) (bucket, key string, ok bool, err error) {
+	if !strings.HasPrefix(location, s3Scheme) {
+		return "", "", false, nil
+	}
+	bucket, key, _ = strings.Cut(strings.TrimPrefix(location, s3Scheme), "/")
+	if bucket == "" || key == "" {
+		return "", "", true, fmt.Errorf("invalid archive location %q, expected s3://bucket/key", location)
+	}
+	return bucket, key, true, nil
+}
+
+func newS3Client(cfg config.BackupConfig) (*s3.Client, error) {
+	if cfg.S3Endpoint == "" {
+		return nil, fmt.Errorf("BACKUP_S3_ENDPOINT must be set to use s3:// archives")
+	}
+	return &s3.Client{
+		Endpoint:  cfg.S3Endpoint,
+		Region:    cfg.S3Region,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+	}, nil
+}
+
+// CreateArchive 打开归档的写入目标
+// location: 本地路径或 s3://bucket/key
+// 返回: 写入完成后调用 commit 使归档生效（本地文件原子重命名、对象存储上传），失败时调用 abort 清理临时文件
+// 注意: S3 要求上传时给出对象大小，归档先写入临时文件再上传
+func CreateArchive(ctx context.Context, location string, cfg config.BackupConfig) (w io.Writer, commit func() error, abort func(), err error) {
+	bucket, key, isS3, err := ParseS3URL(location)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var client *s3.Client
+	dir := filepath.Dir(location)
+	if isS3 {
+		if client, err = newS3Client(cfg); err != nil {
+			return nil, nil, nil, err
+		}
+		dir = ""
+	}
+
+	tmp, err := os.CreateTemp(dir, "blog-backup-*.zip.tmp")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	abort = func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	commit = func() error {
+		if !isS3 {
+			if err := tmp.Close(); err != nil {
+				return err
+			}
+			return os.Rename(tmp.Name(), location)
+		}
+		defer abort()
+		info, err := tmp.Stat()
+		if err != nil {
+			return err
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return client.PutObject(ctx, bucket, key, tmp, info.Size())
+	}
+	return tmp, commit, abort, nil
+}
+
+// OpenArchive 打开归档用于读取
+// location: 本地路径或 s3://bucket/key，对象存储中的归档先下载到临时文件（zip 需要随机读取）
+// 返回: 归档文件和用于关闭（并清理临时文件）的函数
+func OpenArchive(ctx context.Context, location string, cfg config.BackupConfig) (*os.File, func(), error) {
+	bucket, key, isS3, err := ParseS3URL(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isS3 {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, func() { f.Close() }, nil
+	}
+
+	client, err := newS3Client(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := client.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "blog-restore-*.zip")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return tmp, cleanup, nil
+}
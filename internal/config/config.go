@@ -1,124 +1,755 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server        ServerConfig
-	Database      DatabaseConfig
-	Redis         RedisConfig
-	Elasticsearch ElasticsearchConfig
-	JWT           JWTConfig
-	Log           LogConfig
-	Upload        UploadConfig
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Redis         RedisConfig         `yaml:"redis"`
+	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
+	JWT           JWTConfig           `yaml:"jwt"`
+	Log           LogConfig           `yaml:"log"`
+	Upload        UploadConfig        `yaml:"upload"`
+	Export        ExportConfig        `yaml:"export"`
+	Site          SiteConfig          `yaml:"site"`
+	Email         EmailConfig         `yaml:"email"`
+	Report        ReportConfig        `yaml:"report"`
+	Sentry        SentryConfig        `yaml:"sentry"`
+	CORS          CORSConfig          `yaml:"cors"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Webhook       WebhookConfig       `yaml:"webhook"`
+	Newsletter    NewsletterConfig    `yaml:"newsletter"`
+	GraphQL       GraphQLConfig       `yaml:"graphql"`
+	SEO           SEOConfig           `yaml:"seo"`
+	I18n          I18nConfig          `yaml:"i18n"`
+	ContentFilter ContentFilterConfig `yaml:"content_filter"`
+	Summarizer    SummarizerConfig    `yaml:"summarizer"`
+	Backup        BackupConfig        `yaml:"backup"`
+	Health        HealthConfig        `yaml:"health"`
+	Pagination    PaginationConfig    `yaml:"pagination"`
+	// FeatureFlags 功能开关的默认状态（覆盖代码中的默认值），如 new_search: "25%"，见 ParseFeatureFlag
+	FeatureFlags map[string]string `yaml:"feature_flags"`
+	// Timezone 业务时区（IANA 名称，如 Asia/Shanghai），决定“今天”、按天汇总和定时任务的日期边界
+	Timezone string `yaml:"timezone"`
+
+	// location 由 Timezone 解析得到，见 Location
+	location *time.Location
 }
 
 type ServerConfig struct {
-	Host string
-	Port string
-	Mode string
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+	Mode string `yaml:"mode"`
+	// PublicURL 后端 API 对外访问地址，用于邮件中的确认 / 退订链接、robots.txt 中的 Sitemap 地址和文章封面的绝对地址
+	PublicURL string `yaml:"public_url"`
+	// FrontendURL 前端站点地址，用于邮件中的文章链接和文章的 canonical 地址（/articles/:id）
+	FrontendURL string `yaml:"frontend_url"`
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才采信 X-Forwarded-For / X-Real-IP；
+	// 为空时不信任任何代理，客户端 IP 取 TCP 连接的远端地址
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-	SSLMode  string
+	// 数据库驱动：postgres（默认，生产环境）或 sqlite（本地开发和测试）
+	Driver string `yaml:"driver"`
+	// SQLite 数据库文件路径，仅 Driver 为 sqlite 时使用
+	SQLitePath string `yaml:"sqlite_path"`
+
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"ssl_mode"`
 	// 连接池配置
-	MaxOpenConns           int
-	MaxIdleConns           int
-	ConnMaxLifetimeMinutes int
+	MaxOpenConns           int `yaml:"max_open_conns"`
+	MaxIdleConns           int `yaml:"max_idle_conns"`
+	ConnMaxLifetimeMinutes int `yaml:"conn_max_lifetime_minutes"`
+	// 迁移工具等待其他迁移进程释放锁的最长时间（秒）
+	MigrateLockTimeoutSeconds int `yaml:"migrate_lock_timeout_seconds"`
+	// GORM 缓存预编译语句（每个连接按 SQL 文本缓存）
+	PrepareStmt bool `yaml:"prepare_stmt"`
+	// GORM 模型方式的单条写入不再额外包一层事务（默认关闭）
+	SkipDefaultTx bool `yaml:"skip_default_tx"`
 }
 
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+
+	// 连接池：PoolSize 为 0 时使用 go-redis 默认值（每个 CPU 10 个连接）；MinIdleConns 为启动时预建的空闲连接数
+	PoolSize     int `yaml:"pool_size"`
+	MinIdleConns int `yaml:"min_idle_conns"`
+	// 单条命令的读 / 写超时（毫秒），缓存读取和写入也按此设置超时
+	ReadTimeoutMs  int `yaml:"read_timeout_ms"`
+	WriteTimeoutMs int `yaml:"write_timeout_ms"`
+	// MaxRetries 命令失败的重试次数，0 使用 go-redis 默认值 3，-1 表示不重试
+	MaxRetries int `yaml:"max_retries"`
 }
 
 type ElasticsearchConfig struct {
-	URL     string
-	Enabled bool
+	URL     string `yaml:"url"`
+	Enabled bool   `yaml:"enabled"`
+
+	// RequestTimeoutMs 单次请求（搜索、索引、健康检查）的超时时间（毫秒）
+	RequestTimeoutMs int `yaml:"request_timeout_ms"`
+	// MaxRetries 请求失败（连接错误、502/503/504）的重试次数
+	MaxRetries int    `yaml:"max_retries"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	// CACert 自签名证书的 CA 文件路径（PEM），为空时使用系统根证书
+	CACert string `yaml:"ca_cert"`
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpireHours int
+	Secret      string `yaml:"secret"`
+	ExpireHours int    `yaml:"expire_hours"`
+	// RefreshExpireHours 刷新 token 的有效期（小时），从登录时起算，刷新访问 token 时不会延长
+	RefreshExpireHours int `yaml:"refresh_expire_hours"`
 }
 
 type LogConfig struct {
-	Level string
-	File  string
+	Level string `yaml:"level"`
+	File  string `yaml:"file"`
+	// 日志文件轮转：单文件最大大小（MB）、保留的历史文件数和天数（0 表示不限制）、是否 gzip 压缩
+	MaxSizeMB  int  `yaml:"max_size_mb"`
+	MaxBackups int  `yaml:"max_backups"`
+	MaxAgeDays int  `yaml:"max_age_days"`
+	Compress   bool `yaml:"compress"`
+
+	// 按模块覆盖日志级别，如 search: debug
+	ModuleLevels map[string]string `yaml:"module_levels"`
+
+	// 请求日志采样：匹配 SampleRoutes 的 2xx GET 请求每 SampleRate 条记录一条（0 或 1 表示不采样），
+	// 4xx/5xx 和耗时超过 SlowRequestMs 的请求始终记录
+	SampleRate    int      `yaml:"sample_rate"`
+	SampleRoutes  []string `yaml:"sample_routes"`
+	SlowRequestMs int      `yaml:"slow_request_ms"`
 }
 
 type UploadConfig struct {
-	Dir         string
-	MaxSize     int64
-	AllowedExts []string
+	Dir         string   `yaml:"dir"`
+	MaxSize     int64    `yaml:"max_size"`
+	AllowedExts []string `yaml:"allowed_exts"`
 }
 
-var AppConfig *Config
+type ExportConfig struct {
+	Dir     string `yaml:"dir"`
+	MaxRows int    `yaml:"max_rows"` // 同步导出的最大行数，超过后转为异步任务
+}
+
+// SiteConfig 站点运行参数的默认值，运行时以 settings 表中的值为准
+type SiteConfig struct {
+	CommentModeration     bool   `yaml:"comment_moderation"`
+	RegistrationMode      string `yaml:"registration_mode"` // open / closed
+	ExcerptLength         int    `yaml:"excerpt_length"`
+	ArticleDetailCacheTTL int    `yaml:"article_detail_cache_ttl"` // 秒
+	ArticleListCacheTTL   int    `yaml:"article_list_cache_ttl"`   // 秒
+	AuditLogRetentionDays int    `yaml:"audit_log_retention_days"`
+	ContentFilterPolicy   string `yaml:"content_filter_policy"` // reject / replace / review
+	AutoSummary           bool   `yaml:"auto_summary"`          // 发布时自动生成空缺的摘要和 SEO 描述
+}
+
+// EmailConfig SMTP 发信配置，SMTPHost 为空时只在日志中输出邮件（开发环境）
+type EmailConfig struct {
+	SMTPHost string `yaml:"smtp_host"`
+	SMTPPort int    `yaml:"smtp_port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// ReportConfig 定时报表配置
+type ReportConfig struct {
+	WeeklySchedule   string   `yaml:"weekly_schedule"`   // cron 表达式（分 时 日 月 周），为空表示不发送
+	WeeklyRecipients []string `yaml:"weekly_recipients"` // 收件人列表
+}
+
+// CORSConfig 跨域配置，AllowedOrigins 在本地开发地址（localhost:3000 / 5173）之外额外允许的来源
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// RateLimitConfig 登录用户接口的限流：每个 IP 每个路径在 WindowSeconds 内最多 Requests 次请求
+type RateLimitConfig struct {
+	Requests      int `yaml:"requests"`
+	WindowSeconds int `yaml:"window_seconds"`
+	// LikesPerMinute 点赞接口的限流：每个 IP 对同一篇文章每分钟最多点赞的次数
+	LikesPerMinute int `yaml:"likes_per_minute"`
+	// LikesRequireAuth 点赞是否要求登录（默认允许匿名点赞）
+	LikesRequireAuth bool `yaml:"likes_require_auth"`
+	// LoginMaxFailures 同一邮箱在 LoginWindowMinutes 内密码错误的次数上限，达到后暂时锁定该邮箱的登录
+	LoginMaxFailures int `yaml:"login_max_failures"`
+	// LoginIPMaxFailures 同一 IP 在 LoginWindowMinutes 内登录失败的次数上限（防止用多个邮箱轮流尝试）
+	LoginIPMaxFailures int `yaml:"login_ip_max_failures"`
+	// LoginWindowMinutes 登录失败计数的滑动窗口（分钟）
+	LoginWindowMinutes int `yaml:"login_window_minutes"`
+	// Buckets 按名称配置的路由限流（如 comment_create、sms_send），同一名称的路由共用额度；
+	// 路由使用了未配置的名称时按 Requests / WindowSeconds 限流
+	Buckets map[string]RateLimitBucket `yaml:"buckets"`
+	// ExemptRoles 不受接口限流和 Buckets 限制的角色（由认证中间件写入的 role 判断），默认 admin
+	ExemptRoles []string `yaml:"exempt_roles"`
+}
+
+// RateLimitBucket 命名限流桶：每个计数对象在 WindowSeconds 内最多 Requests 次请求
+// 注意: 两者都必须至少为 1（Validate 检查），缺省为 0 的桶会拒绝全部请求
+type RateLimitBucket struct {
+	Requests      int `yaml:"requests"`
+	WindowSeconds int `yaml:"window_seconds"`
+	// PerUser 登录用户按用户 ID 计数（换 IP 不能绕过，同一出口 IP 的用户互不影响），未登录时按 IP
+	PerUser bool `yaml:"per_user"`
+}
+
+// Window 限流窗口
+func (b RateLimitBucket) Window() time.Duration {
+	return time.Duration(b.WindowSeconds) * time.Second
+}
+
+// Bucket 名为 name 的限流桶，未配置时使用 Requests / WindowSeconds（按 IP 计数）
+func (r RateLimitConfig) Bucket(name string) RateLimitBucket {
+	if b, ok := r.Buckets[name]; ok {
+		return b
+	}
+	return RateLimitBucket{Requests: r.Requests, WindowSeconds: r.WindowSeconds}
+}
+
+// Window 限流窗口
+func (r RateLimitConfig) Window() time.Duration {
+	return time.Duration(r.WindowSeconds) * time.Second
+}
+
+// LoginWindow 登录失败计数的滑动窗口
+func (r RateLimitConfig) LoginWindow() time.Duration {
+	return time.Duration(r.LoginWindowMinutes) * time.Minute
+}
+
+// WebhookConfig Webhook 投递配置
+type WebhookConfig struct {
+	// TimeoutMs 单次请求的超时时间（毫秒）
+	TimeoutMs int `yaml:"timeout_ms"`
+	// MaxAttempts 每次投递最多尝试的次数（含第一次）
+	MaxAttempts int `yaml:"max_attempts"`
+	// RetryBackoffMs 第一次重试前的等待时间（毫秒），之后每次翻倍
+	RetryBackoffMs int `yaml:"retry_backoff_ms"`
+}
+
+// Timeout 单次投递请求的超时时间
+func (w WebhookConfig) Timeout() time.Duration {
+	return time.Duration(w.TimeoutMs) * time.Millisecond
+}
+
+// RetryBackoff 第一次重试前的等待时间
+func (w WebhookConfig) RetryBackoff() time.Duration {
+	return time.Duration(w.RetryBackoffMs) * time.Millisecond
+}
+
+// NewsletterConfig 邮件订阅配置
+type NewsletterConfig struct {
+	// BatchSize 群发时每批读取的订阅者数量（每批结束后更新发送进度）
+	BatchSize int `yaml:"batch_size"`
+	// ProviderRates 各发信服务商每分钟最多发送的邮件数，键为 smtp / log，0 或未配置表示不限制
+	ProviderRates map[string]string `yaml:"provider_rates"`
+	// SubscribeLimit 每个 IP 每小时最多提交的订阅请求数
+	SubscribeLimit int `yaml:"subscribe_limit"`
+	// ConfirmTTLHours 确认链接的有效期（小时）
+	ConfirmTTLHours int `yaml:"confirm_ttl_hours"`
+}
+
+// ConfirmTTL 确认链接的有效期
+func (n NewsletterConfig) ConfirmTTL() time.Duration {
+	return time.Duration(n.ConfirmTTLHours) * time.Hour
+}
+
+// RatePerMinute 发信服务商每分钟最多发送的邮件数，0 表示不限制
+func (n NewsletterConfig) RatePerMinute(provider string) int {
+	rate, err := strconv.Atoi(n.ProviderRates[provider])
+	if err != nil || rate < 0 {
+		return 0
+	}
+	return rate
+}
 
+// GraphQLConfig GraphQL 只读接口的查询限制
+type GraphQLConfig struct {
+	// MaxDepth 查询允许的最大嵌套深度
+	MaxDepth int `yaml:"max_depth"`
+	// MaxComplexity 查询允许的最大复杂度（每解析一个字段计 1，pageSize / first 参数按请求的数量计入）
+	MaxComplexity int `yaml:"max_complexity"`
+}
+
+// SEOConfig 搜索引擎相关配置
+type SEOConfig struct {
+	// DisallowAll robots.txt 禁止抓取全部内容（非 release 模式下总是禁止，用于预发布等 release 模式的非生产环境）
+	DisallowAll bool `yaml:"disallow_all"`
+}
+
+// I18nConfig 接口消息的多语言配置
+type I18nConfig struct {
+	// DefaultLanguage 请求未通过 Accept-Language 指定支持的语言时使用的语言（zh-CN / en）
+	DefaultLanguage string `yaml:"default_language"`
+}
+
+// ContentFilterConfig 敏感词过滤配置，命中后的处理方式见 SiteConfig.ContentFilterPolicy
+type ContentFilterConfig struct {
+	// WordsFile 敏感词文件路径，每行“分类: 词1, 词2”，# 开头为注释；为空时只使用 settings 表中的 content_filter_words
+	WordsFile string `yaml:"words_file"`
+}
+
+// SummarizerConfig 文章摘要生成服务（OpenAI 兼容的 chat completions 接口），BaseURL 为空时不启用
+type SummarizerConfig struct {
+	// BaseURL 接口地址（如 https://api.openai.com/v1），请求发送到 BaseURL + /chat/completions
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+	Model   string `yaml:"model"`
+	// TimeoutMs 单次请求的超时时间（毫秒），超时后使用截取正文的摘要
+	TimeoutMs int `yaml:"timeout_ms"`
+	// MaxInputTokens 发送的正文最多占用的 token 数（按字符估算），超出部分截断
+	MaxInputTokens int `yaml:"max_input_tokens"`
+}
+
+// Timeout 单次请求的超时时间
+func (s SummarizerConfig) Timeout() time.Duration {
+	return time.Duration(s.TimeoutMs) * time.Millisecond
+}
+
+// BackupConfig 备份与恢复命令（cmd/backup、cmd/restore）使用的 S3 兼容对象存储，
+// 归档地址为 s3://bucket/key 时生效
+type BackupConfig struct {
+	// S3Endpoint 服务地址，如 https://s3.us-east-1.amazonaws.com 或 http://minio:9000
+	S3Endpoint  string `yaml:"s3_endpoint"`
+	S3Region    string `yaml:"s3_region"`
+	S3AccessKey string `yaml:"s3_access_key"`
+	S3SecretKey string `yaml:"s3_secret_key"`
+}
+
+// HealthConfig 就绪检查（/health、/readyz）配置
+type HealthConfig struct {
+	// TimeoutMs 每项依赖检查（数据库、Redis、Elasticsearch）的超时时间（毫秒）
+	TimeoutMs int `yaml:"timeout_ms"`
+	// RedisRequired Redis 不可用时就绪检查是否返回 503；默认只报告 degraded（限流、缓存等降级运行）
+	RedisRequired bool `yaml:"redis_required"`
+	// ElasticsearchRequired 已启用的 Elasticsearch 不可用时就绪检查是否返回 503；默认只报告 degraded（搜索退回数据库）
+	ElasticsearchRequired bool `yaml:"elasticsearch_required"`
+}
+
+// Timeout 每项依赖检查的超时时间，未配置时为 1 秒
+func (h HealthConfig) Timeout() time.Duration {
+	if h.TimeoutMs <= 0 {
+		return time.Second
+	}
+	return time.Duration(h.TimeoutMs) * time.Millisecond
+}
+
+// ParseFeatureFlag 解析功能开关配置值
+// value: on / off / true / false，或灰度百分比（如 25%、25，表示开启并对 25% 的用户生效）
+// 返回: 是否开启和灰度百分比（0-100）
+func ParseFeatureFlag(value string) (enabled bool, rolloutPercent int, err error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch v {
+	case "on", "true":
+		return true, 100, nil
+	case "off", "false":
+		return false, 100, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(v, "%"))
+	if err != nil || n < 0 || n > 100 {
+		return false, 0, fmt.Errorf("%q must be on, off or a percentage between 0 and 100", value)
+	}
+	return true, n, nil
+}
+
+// SentryConfig 错误上报配置，DSN 为空时不上报
+type SentryConfig struct {
+	DSN         string `yaml:"dsn"`
+	Environment string `yaml:"environment"` // 为空时使用 server.mode
+	Release     string `yaml:"release"`
+}
+
+// appConfig 当前生效的配置；Reload 会整体替换，并发读取时不会看到更新到一半的配置
+var appConfig atomic.Pointer[Config]
+
+// Get 返回当前生效的配置，未加载时为 nil
+// 注意: 返回的配置不可修改；需要同一份配置中的多个值时只调用一次，避免前后读到新旧两份配置
+func Get() *Config {
+	return appConfig.Load()
+}
+
+// Set 替换当前生效的配置（启动时由 Load 设置，测试中用于注入配置）
+func Set(cfg *Config) {
+	appConfig.Store(cfg)
+}
+
+// Load 加载并校验配置，优先级：默认值 < 配置文件 < 环境变量（含 .env）
+// 注意: 配置文件可选，路径见 findConfigFile；文件格式错误时返回包含出错键名的错误
+// 注意: 环境变量格式错误和 Validate 发现的问题合并为一个 *ValidationError 返回，此时配置仍会被设置
 func Load() error {
-	// 加载 .env 文件（如果存在）
-	_ = godotenv.Load()
+	cfg, err := read()
+	if cfg != nil {
+		Set(cfg)
+	}
+	return err
+}
+
+// read 按 Load 的优先级读取并校验配置，不修改当前配置
+// 返回: 配置文件错误时返回 nil 配置；只有校验问题时同时返回配置和 *ValidationError
+func read() (*Config, error) {
+	loadDotEnv()
+
+	cfg := defaultConfig()
+
+	path, err := findConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if err := loadConfigFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	problems := applyEnv(cfg)
+	var validationErr *ValidationError
+	if err := cfg.Validate(); errors.As(err, &validationErr) {
+		problems = append(problems, validationErr.Problems...)
+	}
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
+	}
+	cfg.location, _ = time.LoadLocation(cfg.Timezone)
+	return cfg, nil
+}
+
+// dotenvKeys 由 .env 文件写入（而不是进程启动时已有）的环境变量
+var dotenvKeys = make(map[string]bool)
 
-	AppConfig = &Config{
+// loadDotEnv 加载 .env 文件（如果存在），进程启动时已有的环境变量优先
+// 注意: 重新加载时 .env 中修改或删除的变量同步更新，之前由 .env 写入的值不会被当作进程环境变量保留
+func loadDotEnv() {
+	values, err := godotenv.Read()
+	if err != nil {
+		values = nil
+	}
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set && !dotenvKeys[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		dotenvKeys[key] = true
+	}
+}
+
+// defaultConfig 未配置时使用的默认值
+func defaultConfig() *Config {
+	return &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
-			Port: getEnv("SERVER_PORT", "8080"),
-			Mode: getEnv("SERVER_MODE", "debug"),
+			Host: "0.0.0.0",
+			Port: "8080",
+			Mode: "debug",
+
+			PublicURL:   "http://localhost:8080",
+			FrontendURL: "http://localhost:3000",
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			Name:     getEnv("DB_NAME", "enterprise_blog"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
-			// 默认值适用于中小规模部署，可通过环境变量覆盖
-			MaxOpenConns:           getEnvAsInt("DB_MAX_OPEN_CONNS", 50),
-			MaxIdleConns:           getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
-			ConnMaxLifetimeMinutes: getEnvAsInt("DB_CONN_MAX_LIFETIME_MINUTES", 60),
+			Driver:     "postgres",
+			SQLitePath: "enterprise_blog.db",
+
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "postgres",
+			Password: "postgres",
+			Name:     "enterprise_blog",
+			SSLMode:  "disable",
+			// 默认值适用于中小规模部署，可通过配置文件或环境变量覆盖
+			MaxOpenConns:           50,
+			MaxIdleConns:           10,
+			ConnMaxLifetimeMinutes: 60,
+
+			MigrateLockTimeoutSeconds: 60,
+
+			// 两者默认关闭，需基准测试确认收益后再按部署开启
+			PrepareStmt:   false,
+			SkipDefaultTx: false,
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
+			Host:     "localhost",
+			Port:     "6379",
+			Password: "",
+			DB:       0,
+
+			ReadTimeoutMs:  200,
+			WriteTimeoutMs: 500,
 		},
 		Elasticsearch: ElasticsearchConfig{
-			URL:     getEnv("ELASTICSEARCH_URL", ""),
-			Enabled: getEnv("ELASTICSEARCH_ENABLED", "true") == "true",
+			URL:     "",
+			Enabled: false,
+
+			RequestTimeoutMs: 5000,
+			MaxRetries:       3,
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-			ExpireHours: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+			Secret:             defaultJWTSecret,
+			ExpireHours:        24,
+			RefreshExpireHours: 720,
 		},
 		Log: LogConfig{
-			Level: getEnv("LOG_LEVEL", "debug"),
-			File:  getEnv("LOG_FILE", "logs/app.log"),
+			Level:      "debug",
+			File:       "logs/app.log",
+			MaxSizeMB:  100,
+			MaxBackups: 10,
+			MaxAgeDays: 30,
+			Compress:   false,
+
+			SampleRate:    1,
+			SampleRoutes:  []string{"/health", "/api/v1/articles", "/uploads/images/*"},
+			SlowRequestMs: 1000,
 		},
 		Upload: UploadConfig{
-			Dir:         getEnv("UPLOAD_DIR", "./uploads/images"),
-			MaxSize:     int64(getEnvAsInt("MAX_UPLOAD_SIZE", 10485760)), // 默认10MB
+			Dir:         "./uploads/images",
+			MaxSize:     10485760, // 默认10MB
 			AllowedExts: []string{".jpg", ".jpeg", ".png", ".gif", ".webp"},
 		},
+		Export: ExportConfig{
+			Dir:     "./exports",
+			MaxRows: 10000,
+		},
+		Site: SiteConfig{
+			CommentModeration:     true,
+			RegistrationMode:      "open",
+			ExcerptLength:         200,
+			ArticleDetailCacheTTL: 60,
+			ArticleListCacheTTL:   120,
+			AuditLogRetentionDays: 90,
+			ContentFilterPolicy:   "reject",
+		},
+		Email: EmailConfig{
+			SMTPHost: "",
+			SMTPPort: 587,
+			Username: "",
+			Password: "",
+			From:     "noreply@example.com",
+		},
+		Report: ReportConfig{
+			// 默认每周一 08:00（服务器时区）
+			WeeklySchedule: "0 8 * * 1",
+		},
+		RateLimit: RateLimitConfig{
+			Requests:       100,
+			WindowSeconds:  60,
+			LikesPerMinute: 10,
+			// 同一邮箱 15 分钟内密码错误 5 次、同一 IP 失败 20 次后锁定
+			LoginMaxFailures:   5,
+			LoginIPMaxFailures: 20,
+			LoginWindowMinutes: 15,
+			// 评论和短信验证码比普通接口严格得多
+			Buckets: map[string]RateLimitBucket{
+				"comment_create": {Requests: 5, WindowSeconds: 60, PerUser: true},
+				"sms_send":       {Requests: 5, WindowSeconds: 3600},
+			},
+			ExemptRoles: []string{"admin"},
+		},
+		Webhook: WebhookConfig{
+			TimeoutMs:      10000,
+			MaxAttempts:    5,
+			RetryBackoffMs: 2000,
+		},
+		Newsletter: NewsletterConfig{
+			BatchSize:       100,
+			ProviderRates:   map[string]string{"smtp": "60"},
+			SubscribeLimit:  5,
+			ConfirmTTLHours: 48,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      10,
+			MaxComplexity: 1000,
+		},
+		I18n: I18nConfig{
+			DefaultLanguage: "zh-CN",
+		},
+		Summarizer: SummarizerConfig{
+			TimeoutMs:      10000,
+			MaxInputTokens: 2000,
+		},
+		Backup: BackupConfig{
+			S3Region: "us-east-1",
+		},
+		Health: HealthConfig{
+			TimeoutMs: 1000,
+		},
+		Pagination: defaultPagination(),
+		Timezone:   "UTC",
 	}
+}
+
+// applyEnv 用已设置（非空）的环境变量覆盖配置
+// 返回: 无法解析的环境变量（如 MAX_UPLOAD_SIZE=banana），对应字段保留原值
+func applyEnv(cfg *Config) []string {
+	env := &envOverlay{}
+
+	env.string(&cfg.Server.Host, "SERVER_HOST")
+	env.string(&cfg.Server.Port, "SERVER_PORT")
+	env.string(&cfg.Server.Mode, "SERVER_MODE")
+	env.string(&cfg.Server.PublicURL, "PUBLIC_URL")
+	env.string(&cfg.Server.FrontendURL, "FRONTEND_URL")
+	env.list(&cfg.Server.TrustedProxies, "TRUSTED_PROXIES")
+
+	db := &cfg.Database
+	env.string(&db.Driver, "DB_DRIVER")
+	env.string(&db.SQLitePath, "DB_SQLITE_PATH")
+	env.string(&db.Host, "DB_HOST")
+	env.string(&db.Port, "DB_PORT")
+	env.string(&db.User, "DB_USER")
+	env.string(&db.Password, "DB_PASSWORD")
+	env.string(&db.Name, "DB_NAME")
+	env.string(&db.SSLMode, "DB_SSLMODE")
+	env.int(&db.MaxOpenConns, "DB_MAX_OPEN_CONNS")
+	env.int(&db.MaxIdleConns, "DB_MAX_IDLE_CONNS")
+	env.int(&db.ConnMaxLifetimeMinutes, "DB_CONN_MAX_LIFETIME_MINUTES")
+	env.int(&db.MigrateLockTimeoutSeconds, "DB_MIGRATE_LOCK_TIMEOUT_SECONDS")
+	env.bool(&db.PrepareStmt, "DB_PREPARE_STMT")
+	env.bool(&db.SkipDefaultTx, "DB_SKIP_DEFAULT_TX")
+
+	env.string(&cfg.Redis.Host, "REDIS_HOST")
+	env.string(&cfg.Redis.Port, "REDIS_PORT")
+	env.string(&cfg.Redis.Password, "REDIS_PASSWORD")
+	env.int(&cfg.Redis.DB, "REDIS_DB")
+	env.int(&cfg.Redis.PoolSize, "REDIS_POOL_SIZE")
+	env.int(&cfg.Redis.MinIdleConns, "REDIS_MIN_IDLE_CONNS")
+	env.int(&cfg.Redis.ReadTimeoutMs, "REDIS_READ_TIMEOUT_MS")
+	env.int(&cfg.Redis.WriteTimeoutMs, "REDIS_WRITE_TIMEOUT_MS")
+	env.int(&cfg.Redis.MaxRetries, "REDIS_MAX_RETRIES")
+
+	env.string(&cfg.Elasticsearch.URL, "ELASTICSEARCH_URL")
+	env.bool(&cfg.Elasticsearch.Enabled, "ELASTICSEARCH_ENABLED")
+	env.int(&cfg.Elasticsearch.RequestTimeoutMs, "ELASTICSEARCH_REQUEST_TIMEOUT_MS")
+	env.int(&cfg.Elasticsearch.MaxRetries, "ELASTICSEARCH_MAX_RETRIES")
+	env.string(&cfg.Elasticsearch.Username, "ELASTICSEARCH_USERNAME")
+	env.string(&cfg.Elasticsearch.Password, "ELASTICSEARCH_PASSWORD")
+	env.string(&cfg.Elasticsearch.CACert, "ELASTICSEARCH_CA_CERT")
+
+	env.string(&cfg.JWT.Secret, "JWT_SECRET")
+	env.int(&cfg.JWT.ExpireHours, "JWT_EXPIRE_HOURS")
+	env.int(&cfg.JWT.RefreshExpireHours, "JWT_REFRESH_EXPIRE_HOURS")
+
+	env.string(&cfg.Log.Level, "LOG_LEVEL")
+	env.string(&cfg.Log.File, "LOG_FILE")
+	env.int(&cfg.Log.MaxSizeMB, "LOG_MAX_SIZE_MB")
+	env.int(&cfg.Log.MaxBackups, "LOG_MAX_BACKUPS")
+	env.int(&cfg.Log.MaxAgeDays, "LOG_MAX_AGE_DAYS")
+	env.bool(&cfg.Log.Compress, "LOG_COMPRESS")
+	env.keyValues(&cfg.Log.ModuleLevels, "LOG_MODULE_LEVELS")
+	env.int(&cfg.Log.SampleRate, "LOG_SAMPLE_RATE")
+	env.list(&cfg.Log.SampleRoutes, "LOG_SAMPLE_ROUTES")
+	env.int(&cfg.Log.SlowRequestMs, "LOG_SLOW_REQUEST_MS")
+
+	env.string(&cfg.Upload.Dir, "UPLOAD_DIR")
+	env.int64(&cfg.Upload.MaxSize, "MAX_UPLOAD_SIZE")
+	env.list(&cfg.Upload.AllowedExts, "UPLOAD_ALLOWED_EXTS")
+
+	env.string(&cfg.Export.Dir, "EXPORT_DIR")
+	env.int(&cfg.Export.MaxRows, "EXPORT_MAX_ROWS")
+
+	site := &cfg.Site
+	env.bool(&site.CommentModeration, "COMMENT_MODERATION")
+	env.string(&site.RegistrationMode, "REGISTRATION_MODE")
+	env.int(&site.ExcerptLength, "EXCERPT_LENGTH")
+	env.int(&site.ArticleDetailCacheTTL, "ARTICLE_DETAIL_CACHE_TTL")
+	env.int(&site.ArticleListCacheTTL, "ARTICLE_LIST_CACHE_TTL")
+	env.int(&site.AuditLogRetentionDays, "AUDIT_LOG_RETENTION_DAYS")
+	env.string(&site.ContentFilterPolicy, "CONTENT_FILTER_POLICY")
+	env.bool(&site.AutoSummary, "AUTO_SUMMARY")
+
+	env.string(&cfg.Email.SMTPHost, "SMTP_HOST")
+	env.int(&cfg.Email.SMTPPort, "SMTP_PORT")
+	env.string(&cfg.Email.Username, "SMTP_USERNAME")
+	env.string(&cfg.Email.Password, "SMTP_PASSWORD")
+	env.string(&cfg.Email.From, "EMAIL_FROM")
+
+	env.string(&cfg.Report.WeeklySchedule, "REPORT_WEEKLY_SCHEDULE")
+	env.list(&cfg.Report.WeeklyRecipients, "REPORT_WEEKLY_RECIPIENTS")
+
+	env.string(&cfg.Sentry.DSN, "SENTRY_DSN")
+	env.string(&cfg.Sentry.Environment, "SENTRY_ENVIRONMENT")
+	env.string(&cfg.Sentry.Release, "SENTRY_RELEASE")
+
+	env.list(&cfg.CORS.AllowedOrigins, "CORS_ALLOWED_ORIGINS")
+	env.int(&cfg.Webhook.TimeoutMs, "WEBHOOK_TIMEOUT_MS")
+	env.int(&cfg.Webhook.MaxAttempts, "WEBHOOK_MAX_ATTEMPTS")
+	env.int(&cfg.Webhook.RetryBackoffMs, "WEBHOOK_RETRY_BACKOFF_MS")
+	env.int(&cfg.Newsletter.BatchSize, "NEWSLETTER_BATCH_SIZE")
+	env.keyValues(&cfg.Newsletter.ProviderRates, "NEWSLETTER_PROVIDER_RATES")
+	env.int(&cfg.Newsletter.SubscribeLimit, "NEWSLETTER_SUBSCRIBE_LIMIT")
+	env.int(&cfg.Newsletter.ConfirmTTLHours, "NEWSLETTER_CONFIRM_TTL_HOURS")
 
-	return nil
+	env.int(&cfg.GraphQL.MaxDepth, "GRAPHQL_MAX_DEPTH")
+	env.int(&cfg.GraphQL.MaxComplexity, "GRAPHQL_MAX_COMPLEXITY")
+
+	env.bool(&cfg.SEO.DisallowAll, "SEO_DISALLOW_ALL")
+
+	env.string(&cfg.I18n.DefaultLanguage, "I18N_DEFAULT_LANGUAGE")
+
+	env.string(&cfg.ContentFilter.WordsFile, "CONTENT_FILTER_WORDS_FILE")
+
+	env.string(&cfg.Summarizer.BaseURL, "SUMMARIZER_BASE_URL")
+	env.string(&cfg.Summarizer.APIKey, "SUMMARIZER_API_KEY")
+	env.string(&cfg.Summarizer.Model, "SUMMARIZER_MODEL")
+	env.int(&cfg.Summarizer.TimeoutMs, "SUMMARIZER_TIMEOUT_MS")
+	env.int(&cfg.Summarizer.MaxInputTokens, "SUMMARIZER_MAX_INPUT_TOKENS")
+
+	env.string(&cfg.Backup.S3Endpoint, "BACKUP_S3_ENDPOINT")
+	env.string(&cfg.Backup.S3Region, "BACKUP_S3_REGION")
+	env.string(&cfg.Backup.S3AccessKey, "BACKUP_S3_ACCESS_KEY")
+	env.string(&cfg.Backup.S3SecretKey, "BACKUP_S3_SECRET_KEY")
+	env.int(&cfg.Health.TimeoutMs, "HEALTH_TIMEOUT_MS")
+	env.bool(&cfg.Health.RedisRequired, "HEALTH_REDIS_REQUIRED")
+	env.bool(&cfg.Health.ElasticsearchRequired, "HEALTH_ELASTICSEARCH_REQUIRED")
+
+	env.int(&cfg.Pagination.Articles.Default, "ARTICLES_PAGE_SIZE")
+	env.int(&cfg.Pagination.Articles.Max, "ARTICLES_MAX_PAGE_SIZE")
+	env.int(&cfg.Pagination.Comments.Default, "COMMENTS_PAGE_SIZE")
+	env.int(&cfg.Pagination.Comments.Max, "COMMENTS_MAX_PAGE_SIZE")
+	env.int(&cfg.Pagination.Images.Default, "IMAGES_PAGE_SIZE")
+	env.int(&cfg.Pagination.Images.Max, "IMAGES_MAX_PAGE_SIZE")
+	env.int(&cfg.Pagination.Users.Default, "USERS_PAGE_SIZE")
+	env.int(&cfg.Pagination.Users.Max, "USERS_MAX_PAGE_SIZE")
+	env.int(&cfg.Pagination.Other.Default, "DEFAULT_PAGE_SIZE")
+	env.int(&cfg.Pagination.Other.Max, "MAX_PAGE_SIZE")
+
+	env.keyValues(&cfg.FeatureFlags, "FEATURE_FLAGS")
+	env.string(&cfg.Timezone, "TIMEZONE")
+	env.int(&cfg.RateLimit.Requests, "RATE_LIMIT_REQUESTS")
+	env.int(&cfg.RateLimit.WindowSeconds, "RATE_LIMIT_WINDOW_SECONDS")
+	env.int(&cfg.RateLimit.LikesPerMinute, "RATE_LIMIT_LIKES_PER_MINUTE")
+	env.bool(&cfg.RateLimit.LikesRequireAuth, "LIKES_REQUIRE_AUTH")
+	env.int(&cfg.RateLimit.LoginMaxFailures, "LOGIN_MAX_FAILURES")
+	env.int(&cfg.RateLimit.LoginIPMaxFailures, "LOGIN_IP_MAX_FAILURES")
+	env.int(&cfg.RateLimit.LoginWindowMinutes, "LOGIN_WINDOW_MINUTES")
+	env.list(&cfg.RateLimit.ExemptRoles, "RATE_LIMIT_EXEMPT_ROLES")
+	return env.problems
 }
 
 func (d DatabaseConfig) DSN() string {
+	if d.Driver == "sqlite" {
+		// WAL 允许读写并发；事务以 IMMEDIATE 开始并设置 busy_timeout，并发写入时排队等待而不是返回 database is locked
+		return fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on&_txlock=immediate", d.SQLitePath)
+	}
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
 }
@@ -127,21 +758,123 @@ func (r RedisConfig) Addr() string {
 	return fmt.Sprintf("%s:%s", r.Host, r.Port)
 }
 
-func getEnv(key, defaultValue string) string {
+// 未配置（为 0）时使用的超时默认值，与 defaultConfig 一致
+const (
+	defaultRedisReadTimeout     = 200 * time.Millisecond
+	defaultRedisWriteTimeout    = 500 * time.Millisecond
+	defaultElasticsearchTimeout = 5 * time.Second
+)
+
+// ReadTimeout 单条 Redis 读命令的超时时间
+func (r RedisConfig) ReadTimeout() time.Duration {
+	if r.ReadTimeoutMs <= 0 {
+		return defaultRedisReadTimeout
+	}
+	return time.Duration(r.ReadTimeoutMs) * time.Millisecond
+}
+
+// WriteTimeout 单条 Redis 写命令的超时时间
+func (r RedisConfig) WriteTimeout() time.Duration {
+	if r.WriteTimeoutMs <= 0 {
+		return defaultRedisWriteTimeout
+	}
+	return time.Duration(r.WriteTimeoutMs) * time.Millisecond
+}
+
+// RequestTimeout 单次 Elasticsearch 请求的超时时间
+func (e ElasticsearchConfig) RequestTimeout() time.Duration {
+	if e.RequestTimeoutMs <= 0 {
+		return defaultElasticsearchTimeout
+	}
+	return time.Duration(e.RequestTimeoutMs) * time.Millisecond
+}
+
+// envOverlay 读取环境变量覆盖配置，并记录无法解析的值
+type envOverlay struct {
+	problems []string
+}
+
+func (o *envOverlay) string(dst *string, key string) {
+	if value := os.Getenv(key); value != "" {
+		*dst = value
+	}
+}
+
+func (o *envOverlay) int(dst *int, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		o.problems = append(o.problems, fmt.Sprintf("%s: %q is not a valid integer", key, value))
+		return
+	}
+	*dst = n
+}
+
+func (o *envOverlay) int64(dst *int64, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		o.problems = append(o.problems, fmt.Sprintf("%s: %q is not a valid integer", key, value))
+		return
+	}
+	*dst = n
+}
+
+// bool 只有 "true" 视为开启，其他非空值均为关闭
+func (o *envOverlay) bool(dst *bool, key string) {
 	if value := os.Getenv(key); value != "" {
-		return value
+		*dst = value == "true"
+	}
+}
+
+// list 读取逗号分隔的环境变量，忽略空项
+func (o *envOverlay) list(dst *[]string, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
 	}
-	return defaultValue
+	*dst = list
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+// keyValues 读取逗号分隔的 key=value 环境变量（如 search=debug,database=warn）
+func (o *envOverlay) keyValues(dst *map[string]string, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	values := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" {
+			o.problems = append(o.problems, fmt.Sprintf("%s: %q is not a key=value pair", key, item))
+			return
+		}
+		values[k] = v
 	}
-	return defaultValue
+	*dst = values
 }
 
 func (j JWTConfig) ExpireDuration() time.Duration {
 	return time.Duration(j.ExpireHours) * time.Hour
 }
+
+// RefreshExpireDuration 刷新 token 的有效期
+func (j JWTConfig) RefreshExpireDuration() time.Duration {
+	return time.Duration(j.RefreshExpireHours) * time.Hour
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFiles 未设置 CONFIG_FILE 时依次查找的配置文件（相对工作目录）
+var defaultConfigFiles = []string{"config.yaml", "config.yml", "config/config.yaml"}
+
+// findConfigFile 查找配置文件
+// 返回: 配置文件路径；未设置 CONFIG_FILE 且默认位置都不存在时返回空字符串
+// 注意: CONFIG_FILE 指定的文件不存在时返回错误，避免拼写错误时静默使用默认值
+func findConfigFile() (string, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file %s (CONFIG_FILE): %w", path, err)
+		}
+		return path, nil
+	}
+	for _, path := range defaultConfigFiles {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// yamlErrorLine 匹配 yaml.v3 错误中的行号，如 "line 3: cannot unmarshal ..."
+var yamlErrorLine = regexp.MustCompile(`line (\d+): `)
+
+// loadConfigFile 读取 YAML 配置文件并覆盖 cfg 中对应的字段，文件中未出现的字段保持原值
+// 返回: 语法错误、未知键或类型不匹配时返回错误，错误中包含文件路径和出错的键（如 upload.max_size）
+func loadConfigFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err = dec.Decode(cfg)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	keys := yamlKeysByLine(&root)
+	msgs := make([]string, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			if key, ok := keys[line]; ok {
+				msg = fmt.Sprintf("key %q: %s", key, msg)
+			}
+		}
+		msgs = append(msgs, msg)
+	}
+	return fmt.Errorf("config file %s: %s", path, strings.Join(msgs, "; "))
+}
+
+// yamlKeysByLine 记录每一行对应的完整键路径（如 upload.max_size、upload.allowed_exts[1]），同一行取最外层的键
+func yamlKeysByLine(root *yaml.Node) map[int]string {
+	keys := make(map[int]string)
+	record := func(line int, key string) {
+		if _, ok := keys[line]; !ok {
+			keys[line] = key
+		}
+	}
+	var walk func(node *yaml.Node, prefix string)
+	walk = func(node *yaml.Node, prefix string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, prefix)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				record(node.Content[i].Line, key)
+				walk(node.Content[i+1], key)
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				item := fmt.Sprintf("%s[%d]", prefix, i)
+				record(child.Line, item)
+				walk(child, item)
+			}
+		}
+	}
+	walk(root, "")
+	return keys
+}
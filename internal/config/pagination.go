@@ -0,0 +1,88 @@
+package config
+
+import "enterprise-blog/internal/models"
+
+// PageResource 分页策略对应的资源类型
+type PageResource string
+
+const (
+	// PageArticles 文章列表（REST、全文搜索、Elasticsearch 和 GraphQL）
+	PageArticles PageResource = "articles"
+	// PageComments 评论列表
+	PageComments PageResource = "comments"
+	// PageImages 图片列表
+	PageImages PageResource = "images"
+	// PageUsers 用户列表（管理端）
+	PageUsers PageResource = "users"
+	// PageOther 其余列表：审计日志、通知、回收站、Webhook 投递记录、订阅者和邮件活动
+	PageOther PageResource = "other"
+)
+
+// PageSizeLimits 每页条数的默认值和上限
+type PageSizeLimits struct {
+	// Default 未传 page_size 或取值小于 1 时使用的每页条数
+	Default int `yaml:"default"`
+	// Max 每页条数上限，超过时按上限返回；不能超过 models.MaxPageSize
+	Max int `yaml:"max"`
+}
+
+// PaginationConfig 各类列表接口的分页策略
+type PaginationConfig struct {
+	Articles PageSizeLimits `yaml:"articles"`
+	Comments PageSizeLimits `yaml:"comments"`
+	Images   PageSizeLimits `yaml:"images"`
+	Users    PageSizeLimits `yaml:"users"`
+	Other    PageSizeLimits `yaml:"other"`
+}
+
+// defaultPagination 未配置时的分页策略
+func defaultPagination() PaginationConfig {
+	return PaginationConfig{
+		Articles: PageSizeLimits{Default: 10, Max: models.MaxPageSize},
+		Comments: PageSizeLimits{Default: 20, Max: models.MaxPageSize},
+		Images:   PageSizeLimits{Default: 20, Max: models.MaxPageSize},
+		Users:    PageSizeLimits{Default: 10, Max: models.MaxPageSize},
+		Other:    PageSizeLimits{Default: 20, Max: models.MaxPageSize},
+	}
+}
+
+// Limits 返回资源类型的分页策略，未知类型按 Other 处理
+func (p PaginationConfig) Limits(resource PageResource) PageSizeLimits {
+	switch resource {
+	case PageArticles:
+		return p.Articles
+	case PageComments:
+		return p.Comments
+	case PageImages:
+		return p.Images
+	case PageUsers:
+		return p.Users
+	default:
+		return p.Other
+	}
+}
+
+// PageLimits 返回当前配置中资源类型的分页策略，配置未加载或未配置（为 0）的值使用默认值
+func PageLimits(resource PageResource) PageSizeLimits {
+	limits := defaultPagination().Limits(resource)
+	current := Get()
+	if current == nil {
+		return limits
+	}
+	configured := current.Pagination.Limits(resource)
+	if configured.Default > 0 {
+		limits.Default = configured.Default
+	}
+	if configured.Max > 0 {
+		limits.Max = configured.Max
+	}
+	return limits
+}
+
+// NormalizePage 按资源类型的分页策略规范化分页参数，handler、service、repository 和搜索层共用，
+// 保证同一请求无论走哪条查询路径得到的每页条数都相同
+// 返回: 规范化后的 page、pageSize，规则见 models.NormalizePageWithin
+func NormalizePage(resource PageResource, page, pageSize int) (int, int) {
+	limits := PageLimits(resource)
+	return models.NormalizePageWithin(page, pageSize, limits.Default, limits.Max)
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// liveReloadKeys 可以在运行中重新加载的配置键（含其下的全部子键）
+// 其余配置（监听地址、数据库、Redis 连接等）只在启动时生效，修改后需要重启
+var liveReloadKeys = []string{
+	"log.level",
+	"log.module_levels",
+	"cors",
+	"rate_limit",
+	"site",
+	"feature_flags",
+	"newsletter.subscribe_limit",
+	"seo",
+	"i18n",
+	"content_filter",
+	"pagination",
+	"health",
+}
+
+// ReloadResult 重新加载配置的结果
+type ReloadResult struct {
+	// Applied 已生效的配置键
+	Applied []string `json:"applied"`
+	// Skipped 已修改但需要重启才能生效的配置键（当前进程仍使用旧值）
+	Skipped []string `json:"skipped"`
+}
+
+// reloadMu 串行执行 Reload，避免两次重新加载基于同一份旧配置合并，后写入的覆盖先写入的
+var reloadMu sync.Mutex
+
+// Reload 按 Load 的规则重新读取配置，只把可在运行中修改的配置（见 liveReloadKeys）合并为新的当前配置
+// 返回: 已生效和被跳过的配置键；配置文件错误或校验失败时返回错误，当前配置保持不变
+// 注意: 合并到副本后整体替换（见 Get），日志级别、跨域、限流等运行时组件由调用方根据新配置更新
+func Reload() (*ReloadResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	next, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	current := Get()
+	if current == nil {
+		current = defaultConfig()
+	}
+	merged := *current
+
+	result := &ReloadResult{Applied: []string{}, Skipped: []string{}}
+	for _, key := range changedKeys(reflect.ValueOf(*current), reflect.ValueOf(*next), "") {
+		if !isLiveReloadKey(key) {
+			result.Skipped = append(result.Skipped, key)
+			continue
+		}
+		fieldByKey(reflect.ValueOf(&merged).Elem(), key).Set(fieldByKey(reflect.ValueOf(*next), key))
+		result.Applied = append(result.Applied, key)
+	}
+	sort.Strings(result.Applied)
+	sort.Strings(result.Skipped)
+
+	Set(&merged)
+	return result, nil
+}
+
+// isLiveReloadKey 判断配置键是否可以在运行中修改
+func isLiveReloadKey(key string) bool {
+	for _, live := range liveReloadKeys {
+		if key == live || strings.HasPrefix(key, live+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlKey 返回结构体字段的配置文件键名
+func yamlKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
+
+// changedKeys 比较两份配置，返回取值不同的叶子配置键（如 database.host）
+// 注意: nil 和空的 slice / map 视为相同
+func changedKeys(old, next reflect.Value, prefix string) []string {
+	var keys []string
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		name := yamlKey(field)
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		a, b := old.Field(i), next.Field(i)
+		switch {
+		case a.Kind() == reflect.Struct:
+			keys = append(keys, changedKeys(a, b, key)...)
+		case (a.Kind() == reflect.Slice || a.Kind() == reflect.Map) && a.Len() == 0 && b.Len() == 0:
+		case !reflect.DeepEqual(a.Interface(), b.Interface()):
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// fieldByKey 按配置键查找字段，key 必须来自 changedKeys
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	for _, name := range strings.Split(key, ".") {
+		for i := 0; i < v.NumField(); i++ {
+			if yamlKey(v.Type().Field(i)) == name {
+				v = v.Field(i)
+				break
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,40 @@
+package config
+
+import "time"
+
+// Location 返回配置的业务时区（timezone / TIMEZONE），按天、按周分组统计和定时任务都以此为准
+// 配置未加载时为 UTC
+func Location() *time.Location {
+	if current := Get(); current != nil {
+		return current.Location()
+	}
+	return time.UTC
+}
+
+// Location 返回配置的业务时区，Load 时已解析；时区无效时为 UTC
+func (c *Config) Location() *time.Location {
+	// 复制配置后修改 Timezone 时缓存的 location 已过期，需重新解析
+	if c.location != nil && c.location.String() == c.Timezone {
+		return c.location
+	}
+	if loc, err := time.LoadLocation(c.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// StartOfDay 返回 t 在业务时区中所在自然日的零点
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(Location())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// MonthRange 返回业务时区中 year 年 month 月的起止时间 [from, to)，month 为 0 时为整年（归档筛选使用）
+func MonthRange(year, month int) (time.Time, time.Time) {
+	if month == 0 {
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, Location())
+		return from, from.AddDate(1, 0, 0)
+	}
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, Location())
+	return from, from.AddDate(0, 1, 0)
+}
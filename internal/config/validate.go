@@ -0,0 +1,307 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
+)
+
+// defaultJWTSecret 默认 JWT 密钥，仅用于本地开发
+const defaultJWTSecret = "your-secret-key-change-in-production"
+
+// minReleaseJWTSecretLength release 模式下 JWT 密钥的最小长度
+const minReleaseJWTSecretLength = 32
+
+// ValidationError 配置校验失败，Problems 包含全部问题（每条注明配置文件键和环境变量名）
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate 校验配置，一次返回全部问题
+// 返回: 有问题时返回 *ValidationError，否则返回 nil
+// 注意: 上传目录不存在时检查能否在最近的已存在上级目录中创建，不会创建目录
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Server.Mode == "release" {
+		switch {
+		case c.JWT.Secret == defaultJWTSecret:
+			addf("jwt.secret (JWT_SECRET): the default secret must not be used in release mode")
+		case len(c.JWT.Secret) < minReleaseJWTSecretLength:
+			addf("jwt.secret (JWT_SECRET): must be at least %d characters in release mode", minReleaseJWTSecretLength)
+		}
+		if c.Database.Driver != "sqlite" && c.Database.Password == "" {
+			addf("database.password (DB_PASSWORD): must not be empty in release mode")
+		}
+	}
+
+	checkPort := func(key, env, port string) {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			addf("%s (%s): %q is not a valid port", key, env, port)
+		}
+	}
+	checkPort("server.port", "SERVER_PORT", c.Server.Port)
+	if c.Database.Driver != "sqlite" {
+		checkPort("database.port", "DB_PORT", c.Database.Port)
+	}
+	checkPort("redis.port", "REDIS_PORT", c.Redis.Port)
+	if c.Email.SMTPHost != "" {
+		checkPort("email.smtp_port", "SMTP_PORT", strconv.Itoa(c.Email.SMTPPort))
+	}
+
+	if err := checkWritableDir(c.Upload.Dir); err != nil {
+		addf("upload.dir (UPLOAD_DIR): %v", err)
+	}
+
+	for _, module := range sortedKeys(c.Log.ModuleLevels) {
+		if _, err := zerolog.ParseLevel(c.Log.ModuleLevels[module]); err != nil || c.Log.ModuleLevels[module] == "" {
+			addf("log.module_levels.%s (LOG_MODULE_LEVELS): %q is not a valid log level", module, c.Log.ModuleLevels[module])
+		}
+	}
+	if c.Log.SampleRate < 0 {
+		addf("log.sample_rate (LOG_SAMPLE_RATE): must not be negative")
+	}
+
+	if c.Redis.PoolSize < 0 {
+		addf("redis.pool_size (REDIS_POOL_SIZE): must not be negative")
+	}
+	if c.Redis.MinIdleConns < 0 {
+		addf("redis.min_idle_conns (REDIS_MIN_IDLE_CONNS): must not be negative")
+	}
+	if c.Redis.ReadTimeoutMs < 0 {
+		addf("redis.read_timeout_ms (REDIS_READ_TIMEOUT_MS): must not be negative")
+	}
+	if c.Redis.WriteTimeoutMs < 0 {
+		addf("redis.write_timeout_ms (REDIS_WRITE_TIMEOUT_MS): must not be negative")
+	}
+	if c.Redis.MaxRetries < -1 {
+		addf("redis.max_retries (REDIS_MAX_RETRIES): must be -1 (no retries) or greater")
+	}
+
+	if c.Elasticsearch.Enabled && c.Elasticsearch.URL == "" {
+		addf("elasticsearch.url (ELASTICSEARCH_URL): required when Elasticsearch is enabled")
+	}
+	if c.Elasticsearch.RequestTimeoutMs < 0 {
+		addf("elasticsearch.request_timeout_ms (ELASTICSEARCH_REQUEST_TIMEOUT_MS): must not be negative")
+	}
+	if c.Elasticsearch.MaxRetries < 0 {
+		addf("elasticsearch.max_retries (ELASTICSEARCH_MAX_RETRIES): must not be negative")
+	}
+	if c.Elasticsearch.Enabled && c.Elasticsearch.CACert != "" {
+		if _, err := os.Stat(c.Elasticsearch.CACert); err != nil {
+			addf("elasticsearch.ca_cert (ELASTICSEARCH_CA_CERT): %v", err)
+		}
+	}
+
+	if c.Webhook.TimeoutMs < 1 {
+		addf("webhook.timeout_ms (WEBHOOK_TIMEOUT_MS): must be at least 1")
+	}
+	if c.Webhook.MaxAttempts < 1 {
+		addf("webhook.max_attempts (WEBHOOK_MAX_ATTEMPTS): must be at least 1")
+	}
+	if c.Webhook.RetryBackoffMs < 0 {
+		addf("webhook.retry_backoff_ms (WEBHOOK_RETRY_BACKOFF_MS): must not be negative")
+	}
+
+	checkURL := func(key, env, value string) {
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			addf("%s (%s): %q is not an absolute http or https URL", key, env, value)
+		}
+	}
+	checkURL("server.public_url", "PUBLIC_URL", c.Server.PublicURL)
+	checkURL("server.frontend_url", "FRONTEND_URL", c.Server.FrontendURL)
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				addf("server.trusted_proxies (TRUSTED_PROXIES): %q is not an IP or CIDR", proxy)
+			}
+		}
+	}
+
+	if c.Newsletter.BatchSize < 1 {
+		addf("newsletter.batch_size (NEWSLETTER_BATCH_SIZE): must be at least 1")
+	}
+	for _, provider := range sortedKeys(c.Newsletter.ProviderRates) {
+		if n, err := strconv.Atoi(c.Newsletter.ProviderRates[provider]); err != nil || n < 0 {
+			addf("newsletter.provider_rates.%s (NEWSLETTER_PROVIDER_RATES): %q is not a non-negative integer", provider, c.Newsletter.ProviderRates[provider])
+		}
+	}
+	if c.Newsletter.SubscribeLimit < 1 {
+		addf("newsletter.subscribe_limit (NEWSLETTER_SUBSCRIBE_LIMIT): must be at least 1")
+	}
+	if c.Newsletter.ConfirmTTLHours < 1 {
+		addf("newsletter.confirm_ttl_hours (NEWSLETTER_CONFIRM_TTL_HOURS): must be at least 1")
+	}
+
+	if c.GraphQL.MaxDepth < 1 {
+		addf("graphql.max_depth (GRAPHQL_MAX_DEPTH): must be at least 1")
+	}
+	if c.GraphQL.MaxComplexity < 1 {
+		addf("graphql.max_complexity (GRAPHQL_MAX_COMPLEXITY): must be at least 1")
+	}
+
+	if i18n.Match(c.I18n.DefaultLanguage) == "" {
+		addf("i18n.default_language (I18N_DEFAULT_LANGUAGE): %q is not supported (supported: %s)", c.I18n.DefaultLanguage, strings.Join(i18n.Languages, ", "))
+	}
+
+	switch c.Site.ContentFilterPolicy {
+	case "reject", "replace", "review":
+	default:
+		addf("site.content_filter_policy (CONTENT_FILTER_POLICY): %q is not one of reject, replace, review", c.Site.ContentFilterPolicy)
+	}
+	if c.ContentFilter.WordsFile != "" {
+		if _, err := os.Stat(c.ContentFilter.WordsFile); err != nil {
+			addf("content_filter.words_file (CONTENT_FILTER_WORDS_FILE): %v", err)
+		}
+	}
+
+	if c.Summarizer.BaseURL != "" {
+		checkURL("summarizer.base_url", "SUMMARIZER_BASE_URL", c.Summarizer.BaseURL)
+		if c.Summarizer.Model == "" {
+			addf("summarizer.model (SUMMARIZER_MODEL): required when the summarizer is enabled")
+		}
+	}
+	if c.Summarizer.TimeoutMs < 1 {
+		addf("summarizer.timeout_ms (SUMMARIZER_TIMEOUT_MS): must be at least 1")
+	}
+	if c.Health.TimeoutMs < 1 {
+		addf("health.timeout_ms (HEALTH_TIMEOUT_MS): must be at least 1")
+	}
+	if c.Summarizer.MaxInputTokens < 1 {
+		addf("summarizer.max_input_tokens (SUMMARIZER_MAX_INPUT_TOKENS): must be at least 1")
+	}
+
+	if c.Backup.S3Endpoint != "" {
+		checkURL("backup.s3_endpoint", "BACKUP_S3_ENDPOINT", c.Backup.S3Endpoint)
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		addf("timezone (TIMEZONE): %q is not a valid IANA time zone", c.Timezone)
+	}
+
+	if c.JWT.RefreshExpireHours < 1 {
+		addf("jwt.refresh_expire_hours (JWT_REFRESH_EXPIRE_HOURS): must be at least 1")
+	}
+
+	if c.RateLimit.Requests < 1 {
+		addf("rate_limit.requests (RATE_LIMIT_REQUESTS): must be at least 1")
+	}
+	if c.RateLimit.WindowSeconds < 1 {
+		addf("rate_limit.window_seconds (RATE_LIMIT_WINDOW_SECONDS): must be at least 1")
+	}
+	if c.RateLimit.LikesPerMinute < 1 {
+		addf("rate_limit.likes_per_minute (RATE_LIMIT_LIKES_PER_MINUTE): must be at least 1")
+	}
+	if c.RateLimit.LoginMaxFailures < 1 {
+		addf("rate_limit.login_max_failures (LOGIN_MAX_FAILURES): must be at least 1")
+	}
+	if c.RateLimit.LoginIPMaxFailures < c.RateLimit.LoginMaxFailures {
+		addf("rate_limit.login_ip_max_failures (LOGIN_IP_MAX_FAILURES): must not be less than rate_limit.login_max_failures")
+	}
+	if c.RateLimit.LoginWindowMinutes < 1 {
+		addf("rate_limit.login_window_minutes (LOGIN_WINDOW_MINUTES): must be at least 1")
+	}
+	for _, name := range sortedKeys(c.RateLimit.Buckets) {
+		bucket := c.RateLimit.Buckets[name]
+		if bucket.Requests < 1 {
+			addf("rate_limit.buckets.%s.requests: must be at least 1", name)
+		}
+		if bucket.WindowSeconds < 1 {
+			addf("rate_limit.buckets.%s.window_seconds: must be at least 1", name)
+		}
+	}
+
+	checkPageSize := func(key, defaultEnv, maxEnv string, limits PageSizeLimits) {
+		if limits.Max < 1 || limits.Max > models.MaxPageSize {
+			addf("pagination.%s.max (%s): must be between 1 and %d", key, maxEnv, models.MaxPageSize)
+		}
+		if limits.Default < 1 || limits.Default > limits.Max {
+			addf("pagination.%s.default (%s): must be between 1 and pagination.%s.max", key, defaultEnv, key)
+		}
+	}
+	checkPageSize("articles", "ARTICLES_PAGE_SIZE", "ARTICLES_MAX_PAGE_SIZE", c.Pagination.Articles)
+	checkPageSize("comments", "COMMENTS_PAGE_SIZE", "COMMENTS_MAX_PAGE_SIZE", c.Pagination.Comments)
+	checkPageSize("images", "IMAGES_PAGE_SIZE", "IMAGES_MAX_PAGE_SIZE", c.Pagination.Images)
+	checkPageSize("users", "USERS_PAGE_SIZE", "USERS_MAX_PAGE_SIZE", c.Pagination.Users)
+	checkPageSize("other", "DEFAULT_PAGE_SIZE", "MAX_PAGE_SIZE", c.Pagination.Other)
+
+	for _, name := range sortedKeys(c.FeatureFlags) {
+		if _, _, err := ParseFeatureFlag(c.FeatureFlags[name]); err != nil {
+			addf("feature_flags.%s (FEATURE_FLAGS): %v", name, err)
+		}
+	}
+
+	if c.Sentry.DSN != "" {
+		if _, err := sentry.NewDsn(c.Sentry.DSN); err != nil {
+			addf("sentry.dsn (SENTRY_DSN): %v", err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// sortedKeys 按字典序返回 map 的键，保证问题列表顺序稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkWritableDir 检查目录可写；目录不存在时检查最近的已存在上级目录可写（启动后会自动创建）
+func checkWritableDir(dir string) error {
+	if dir == "" {
+		return errors.New("must not be empty")
+	}
+
+	path := filepath.Clean(dir)
+	for {
+		info, err := os.Stat(path)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", path)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return fmt.Errorf("no existing parent directory for %s", dir)
+		}
+		path = parent
+	}
+
+	f, err := os.CreateTemp(path, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", path, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// articleTag 文章标签关联表（仓库层只通过原生 SQL 访问，此处仅用于建表）
+type articleTag struct {
+	ArticleID uuid.UUID `gorm:"primaryKey"`
+	TagID     uuid.UUID `gorm:"primaryKey;index"`
+}
+
+func (articleTag) TableName() string { return "article_tags" }
+
+// articleViewDaily 文章浏览量按天汇总表（仅用于建表）
+type articleViewDaily struct {
+	ArticleID uuid.UUID `gorm:"primaryKey"`
+	Day       time.Time `gorm:"primaryKey;type:date;index"`
+	Views     int64     `gorm:"not null;default:0"`
+}
+
+func (articleViewDaily) TableName() string { return "article_view_daily" }
+
+// AutoMigrate 按 GORM 模型创建缺失的表和索引，仅用于 SQLite（本地开发和测试）
+// 注意: PostgreSQL 的表结构以 migrations 目录为准（cmd/migrate），全文检索列、触发器等不在模型中体现
+func AutoMigrate() error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return DB.AutoMigrate(
+		&models.User{},
+		&models.Category{},
+		&models.Tag{},
+		&models.Article{},
+		&articleTag{},
+		&articleViewDaily{},
+		&models.Comment{},
+		&models.SMSCode{},
+		&models.Image{},
+		&models.Setting{},
+		&models.AuditLog{},
+		&models.FeatureFlag{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.Subscriber{},
+		&models.NewsletterCampaign{},
+		&models.Notification{},
+		&models.ArticleStatusChange{},
+		&models.TagAlias{},
+		&models.RefreshToken{},
+		&models.EmailVerification{},
+		&models.PasswordReset{},
+		&models.ArticleLike{},
+		&models.ArticleRevision{},
+		&models.ArticleSlugRedirect{},
+	)
+}
@@ -5,23 +5,57 @@ import (
 	"time"
 
 	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database/sqlite"
 	"enterprise-blog/pkg/logger"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// 支持的数据库驱动（DB_DRIVER）
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = sqlite.DriverName
+)
+
 // DB 是全局的 GORM 数据库连接
 var DB *gorm.DB
 
+// preparedStmtCacheSize 开启 PrepareStmt 时最多缓存的语句数，超出后按 LRU 淘汰
+// 动态拼接的 SQL（多行 INSERT、可选筛选条件）会产生多种语句文本，需要上限避免无限增长
+const preparedStmtCacheSize = 500
+
 func Init() error {
-	dsn := config.AppConfig.Database.DSN()
+	cfg := config.Get().Database
+	dsn := cfg.DSN()
+
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case DriverPostgres, "":
+		dialector = postgres.Open(dsn)
+	case DriverSQLite:
+		dialector = sqlite.Open(dsn)
+	default:
+		return fmt.Errorf("unsupported database driver %q, must be %s or %s", cfg.Driver, DriverPostgres, DriverSQLite)
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// 注意: 开启 PrepareStmt 后每次 Exec 都会先 Prepare，不能执行包含多条语句的 SQL（如迁移文件）
+	db, err := gorm.Open(dialector, &gorm.Config{
+		PrepareStmt:            cfg.PrepareStmt,
+		PrepareStmtMaxSize:     preparedStmtCacheSize,
+		SkipDefaultTransaction: cfg.SkipDefaultTx,
+		// SQLite 不支持建表后追加外键约束，外键只对 PostgreSQL 迁移文件生效
+		DisableForeignKeyConstraintWhenMigrating: cfg.Driver == DriverSQLite,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// 记录每条 SQL 的耗时到 Prometheus（db_query_duration_seconds 等）
+	if err := db.Use(MetricsPlugin{}); err != nil {
+		return fmt.Errorf("failed to register metrics plugin: %w", err)
+	}
+
 	// 配置连接池（通过底层 *sql.DB）
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -29,9 +63,9 @@ func Init() error {
 	}
 
 	// 使用配置中的连接池参数
-	maxOpen := config.AppConfig.Database.MaxOpenConns
-	maxIdle := config.AppConfig.Database.MaxIdleConns
-	lifeMinutes := config.AppConfig.Database.ConnMaxLifetimeMinutes
+	maxOpen := cfg.MaxOpenConns
+	maxIdle := cfg.MaxIdleConns
+	lifeMinutes := cfg.ConnMaxLifetimeMinutes
 
 	if maxOpen > 0 {
 		sqlDB.SetMaxOpenConns(maxOpen)
@@ -48,12 +82,18 @@ func Init() error {
 	}
 
 	DB = db
-	l := logger.GetLogger()
+	l := logger.GetLogger("database")
 	l.Info().Msg("Database connected successfully")
 
 	return nil
 }
 
+// IsSQLite 当前连接是否为 SQLite（DB_DRIVER=sqlite）
+// 注意: 仓库层只在 PostgreSQL 专有语法（全文检索、JSONB 等）处据此选择等价写法
+func IsSQLite() bool {
+	return DB != nil && DB.Dialector.Name() == DriverSQLite
+}
+
 func Close() error {
 	if DB == nil {
 		return nil
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRedisUnavailable Redis 未初始化
+var ErrRedisUnavailable = errors.New("redis not initialized")
+
+// 仅当锁仍由自己持有（token 一致）时才删除 / 续期，避免误操作其他实例的锁
+var (
+	releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+	refreshLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// AcquireLock 尝试获取分布式锁（SET NX + 过期时间）
+// 返回: 锁 token（释放 / 续期时使用）和是否获取成功
+func AcquireLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	if RedisClient == nil {
+		return "", false, ErrRedisUnavailable
+	}
+	token := uuid.NewString()
+	ok, err := RedisClient.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+	return token, ok, nil
+}
+
+// RefreshLock 为仍由自己持有的锁续期
+// 返回: 锁是否仍由自己持有
+func RefreshLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	if RedisClient == nil {
+		return false, ErrRedisUnavailable
+	}
+	n, err := refreshLockScript.Run(ctx, RedisClient, []string{key}, token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseLock 释放由自己持有的锁
+func ReleaseLock(ctx context.Context, key, token string) error {
+	if RedisClient == nil {
+		return ErrRedisUnavailable
+	}
+	return releaseLockScript.Run(ctx, RedisClient, []string{key}, token).Err()
+}
@@ -0,0 +1,205 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"enterprise-blog/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+// metricsStartKey 语句开始时间在 Statement.Settings 中的键
+type metricsStartKey struct{}
+
+// MetricsPlugin GORM 插件，记录每条 SQL 的耗时、操作类型和主表到 Prometheus
+//
+// 设计考虑：
+// - 仓库层大多使用 Raw / Exec 手写 SQL，GORM 无法从模型得到表名，此时解析 SQL 中 FROM / INTO / UPDATE 之后的第一张表
+// - 只在回调中做一次 time.Now 和一次线性扫描 SQL，开销远小于一次网络往返
+// - gorm.ErrRecordNotFound 不计入错误
+type MetricsPlugin struct{}
+
+// Name 插件名称
+func (MetricsPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize 在 create / query / update / delete / row / raw 回调链上注册计时回调
+func (MetricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	// Row / Raw 用于手写 SQL，操作类型从语句中解析
+	registrations := []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startMetricsTimer),
+		cb.Create().After("gorm:create").Register("metrics:after_create", recordMetrics("insert")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startMetricsTimer),
+		cb.Query().After("gorm:query").Register("metrics:after_query", recordMetrics("select")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startMetricsTimer),
+		cb.Update().After("gorm:update").Register("metrics:after_update", recordMetrics("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startMetricsTimer),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", recordMetrics("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startMetricsTimer),
+		cb.Row().After("gorm:row").Register("metrics:after_row", recordMetrics("")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startMetricsTimer),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", recordMetrics("")),
+	}
+	return errors.Join(registrations...)
+}
+
+func startMetricsTimer(db *gorm.DB) {
+	db.Statement.Settings.Store(metricsStartKey{}, time.Now())
+}
+
+func recordMetrics(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.Statement.Settings.LoadAndDelete(metricsStartKey{})
+		if !ok {
+			return
+		}
+		duration := time.Since(v.(time.Time))
+
+		op, table := ParseSQLLabels(db.Statement.SQL.String())
+		if operation != "" {
+			op = operation
+		}
+		if db.Statement.Table != "" {
+			table = normalizeTableName(db.Statement.Table)
+		}
+
+		metrics.RecordDBQuery(op, table, duration)
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			metrics.RecordDBQueryError(op, table)
+		}
+	}
+}
+
+// ParseSQLLabels 从 SQL 中解析指标标签
+// sql: 原始 SQL（支持多行、注释、字符串字面量和子查询）
+// 返回: 操作类型（select / insert / update / delete，其他语句为 other），
+// FROM / INTO / UPDATE 之后的第一张表（去掉引号和 schema 前缀，小写），解析不到时为 unknown
+// 注意: WITH 语句取顶层的第一个 select / insert / update / delete 作为操作类型
+func ParseSQLLabels(sql string) (operation, table string) {
+	depth := 0
+	expectTable := false
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			// 行注释
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 4
+			}
+			continue
+		case c == '\'':
+			// 字符串字面量（'' 转义按两个相邻字面量处理即可）
+			end := strings.IndexByte(sql[i+1:], '\'')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 2
+			}
+			expectTable = false
+			continue
+		case c == '(':
+			depth++
+			expectTable = false
+			i++
+			continue
+		case c == ')':
+			depth--
+			i++
+			continue
+		case isIdentStart(c):
+			start := i
+			for i < len(sql) && isIdentPart(sql[i]) {
+				i++
+			}
+			word := sql[start:i]
+
+			if expectTable {
+				// DELETE FROM ONLY t / UPDATE ONLY t
+				if isSQLKeyword(word, "only") {
+					continue
+				}
+				expectTable = false
+				table = normalizeTableName(word)
+				if operation != "with" {
+					return operation, table
+				}
+				continue
+			}
+
+			if operation == "" {
+				switch {
+				case isSQLKeyword(word, "with"):
+					operation = "with"
+				case isSQLKeyword(word, "select"), isSQLKeyword(word, "insert"),
+					isSQLKeyword(word, "update"), isSQLKeyword(word, "delete"):
+					operation = strings.ToLower(word)
+				default:
+					operation = "other"
+				}
+			} else if operation == "with" && depth == 0 {
+				for _, op := range []string{"select", "insert", "update", "delete"} {
+					if isSQLKeyword(word, op) {
+						operation = op
+						break
+					}
+				}
+			}
+
+			if table == "" && (isSQLKeyword(word, "from") || isSQLKeyword(word, "into") || isSQLKeyword(word, "update")) {
+				expectTable = true
+			} else if table != "" && operation != "with" {
+				// WITH 语句的表已在 CTE 中解析到，顶层操作类型也已确定
+				return operation, table
+			}
+			continue
+		default:
+			i++
+		}
+	}
+
+	if operation == "" || operation == "with" {
+		operation = "other"
+	}
+	if table == "" {
+		table = "unknown"
+	}
+	return operation, table
+}
+
+// normalizeTableName 去掉引号和 schema 前缀并转为小写，如 "public"."articles" -> articles
+func normalizeTableName(name string) string {
+	name = strings.ReplaceAll(name, `"`, "")
+	if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+		name = name[dot+1:]
+	}
+	// 去掉 Table("articles a") 形式的别名
+	if sp := strings.IndexByte(name, ' '); sp >= 0 {
+		name = name[:sp]
+	}
+	return strings.ToLower(name)
+}
+
+func isSQLKeyword(word, keyword string) bool {
+	return len(word) == len(keyword) && strings.EqualFold(word, keyword)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '"' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c == '.' || (c >= '0' && c <= '9')
+}
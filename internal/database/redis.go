@@ -12,23 +12,59 @@ import (
 
 var RedisClient *redis.Client
 
+// InitRedis 按配置创建 Redis 客户端并检查连接
+// 注意: 启用 ContextTimeoutEnabled，调用方通过 context 设置的超时（见 services 中的 redisReadContext 等）对单条命令生效
+// 注意: go-redis 在 NewClient 中即按 MinIdleConns 启动后台建连，建连协程会读取钩子链，此后再 AddHook 存在数据竞争；
+// 因此先以 MinIdleConns=0 创建客户端并注册指标钩子，再由 warmUpRedisPool 预建空闲连接
 func InitRedis() error {
+	cfg := config.Get().Redis
 	RedisClient = redis.NewClient(&redis.Options{
-		Addr:     config.AppConfig.Redis.Addr(),
-		Password: config.AppConfig.Redis.Password,
-		DB:       config.AppConfig.Redis.DB,
+		Addr:     cfg.Addr(),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+
+		PoolSize:              cfg.PoolSize,
+		ReadTimeout:           cfg.ReadTimeout(),
+		WriteTimeout:          cfg.WriteTimeout(),
+		MaxRetries:            cfg.MaxRetries,
+		ContextTimeoutEnabled: true,
 	})
+	RedisClient.AddHook(RedisMetricsHook{})
 
 	ctx := context.Background()
 	if err := RedisClient.Ping(ctx).Err(); err != nil {
 		return fmt.Errorf("failed to connect to redis: %w", err)
 	}
+	if err := warmUpRedisPool(ctx, RedisClient, cfg.MinIdleConns); err != nil {
+		return fmt.Errorf("failed to warm up redis pool: %w", err)
+	}
 
-	l := logger.GetLogger()
+	l := logger.GetLogger("database")
 	l.Info().Msg("Redis connected successfully")
 	return nil
 }
 
+// warmUpRedisPool 预建 n 个空闲连接（不超过连接池大小）：同时占用 n 个连接，全部建立后一并归还连接池
+func warmUpRedisPool(ctx context.Context, client *redis.Client, n int) error {
+	if size := client.Options().PoolSize; n > size {
+		n = size
+	}
+	conns := make([]*redis.Conn, 0, n)
+	defer func() {
+		for _, cn := range conns {
+			cn.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		cn := client.Conn()
+		conns = append(conns, cn)
+		if err := cn.Ping(ctx).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func CloseRedis() error {
 	if RedisClient != nil {
 		return RedisClient.Close()
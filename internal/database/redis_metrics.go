@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"enterprise-blog/pkg/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisMetricsHook go-redis 钩子，记录每条命令（管道按一次操作）的耗时到 Prometheus
+//
+// 设计考虑：
+// - 操作标签为命令名（小写，如 get、evalsha），取值范围是 Redis 命令集合，不会随键名增长
+// - 管道和事务整体记为 pipeline，其中的单条命令不单独计时
+// - 命令返回 redis.Nil（键不存在）属于正常结果，与成功的命令一样只记录耗时
+type RedisMetricsHook struct{}
+
+var _ redis.Hook = RedisMetricsHook{}
+
+// DialHook 建立连接不计入命令指标
+func (RedisMetricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 记录单条命令的耗时
+func (RedisMetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		metrics.RecordRedisOperation(cmd.Name(), time.Since(start))
+		return err
+	}
+}
+
+// ProcessPipelineHook 记录整个管道的耗时
+func (RedisMetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		metrics.RecordRedisOperation("pipeline", time.Since(start))
+		return err
+	}
+}
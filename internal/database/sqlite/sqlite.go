@@ -0,0 +1,298 @@
+// Package sqlite 提供基于 mattn/go-sqlite3 的最小 GORM 方言，用于本地开发和测试（DB_DRIVER=sqlite）
+//
+// 设计考虑：
+//   - 只覆盖本项目用到的能力：原生 SQL 执行、模型查询和按模型新建表（AutoMigrate 只建表、不改表）
+//   - 仓库层的 SQL 大量使用 PostgreSQL 风格的 $1、$2 占位符，执行前改写为 SQLite 的 ?1、?2，
+//     按编号而不是出现顺序绑定参数
+//   - 生产环境仍只支持 PostgreSQL，迁移文件不适用于 SQLite
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // 注册 sqlite3 驱动
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// DriverName 方言名称，与 gorm.Dialector.Name() 一致
+const DriverName = "sqlite"
+
+// Dialector SQLite 方言
+type Dialector struct {
+	DSN string
+}
+
+// Open 创建 SQLite 方言
+// dsn: go-sqlite3 的连接串，如 file:blog.db?_busy_timeout=5000&_journal_mode=WAL
+func Open(dsn string) gorm.Dialector {
+	return &Dialector{DSN: dsn}
+}
+
+// Name 方言名称
+func (Dialector) Name() string {
+	return DriverName
+}
+
+// Initialize 注册默认回调并打开连接
+func (d Dialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
+		CreateClauses:        []string{"INSERT", "VALUES", "ON CONFLICT", "RETURNING"},
+		UpdateClauses:        []string{"UPDATE", "SET", "FROM", "WHERE", "RETURNING"},
+		DeleteClauses:        []string{"DELETE", "FROM", "WHERE", "RETURNING"},
+		LastInsertIDReversed: true,
+	})
+
+	conn, err := sql.Open("sqlite3", d.DSN)
+	if err != nil {
+		return err
+	}
+	db.ConnPool = conn
+
+	db.ClauseBuilders["LIMIT"] = buildLimit
+
+	// 原生 SQL 在 gorm 构建完成后、执行前改写占位符
+	for _, register := range []error{
+		db.Callback().Query().Before("gorm:query").Register("sqlite:rewrite_placeholders", rewritePlaceholders),
+		db.Callback().Row().Before("gorm:row").Register("sqlite:rewrite_placeholders", rewritePlaceholders),
+		db.Callback().Raw().Before("gorm:raw").Register("sqlite:rewrite_placeholders", rewritePlaceholders),
+	} {
+		if register != nil {
+			return register
+		}
+	}
+	return nil
+}
+
+// buildLimit SQLite 不支持单独的 OFFSET，需要 LIMIT -1
+func buildLimit(c clause.Clause, builder clause.Builder) {
+	limit, ok := c.Expression.(clause.Limit)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+	if limit.Limit != nil && *limit.Limit >= 0 {
+		builder.WriteString("LIMIT ")
+		builder.WriteString(strconv.Itoa(*limit.Limit))
+	} else if limit.Offset > 0 {
+		builder.WriteString("LIMIT -1")
+	}
+	if limit.Offset > 0 {
+		builder.WriteString(" OFFSET ")
+		builder.WriteString(strconv.Itoa(limit.Offset))
+	}
+}
+
+func rewritePlaceholders(db *gorm.DB) {
+	if db.Statement.SQL.Len() == 0 {
+		return
+	}
+	sql := db.Statement.SQL.String()
+	if rewritten, changed := RewritePlaceholders(sql); changed {
+		db.Statement.SQL.Reset()
+		db.Statement.SQL.WriteString(rewritten)
+	}
+}
+
+// RewritePlaceholders 将 PostgreSQL 的 $n 占位符改写为 SQLite 的 ?n，字符串字面量和引号标识符中的内容保持不变
+// 返回: 改写后的 SQL，以及是否发生了改写
+func RewritePlaceholders(sql string) (string, bool) {
+	if !strings.Contains(sql, "$") {
+		return sql, false
+	}
+
+	var b strings.Builder
+	b.Grow(len(sql))
+	changed := false
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+			c = '?'
+			changed = true
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), changed
+}
+
+// Migrator 按模型建表
+func (d Dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return Migrator{migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+// DataTypeOf 模型字段对应的 SQLite 列类型
+func (Dialector) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "numeric"
+	case schema.Int, schema.Uint:
+		return "integer"
+	case schema.Float:
+		return "real"
+	case schema.String:
+		return "text"
+	case schema.Time:
+		return "datetime"
+	case schema.Bytes:
+		return "blob"
+	}
+	return string(field.DataType)
+}
+
+// DefaultValueOf 插入时未赋值字段的默认值
+func (Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
+	if field.AutoIncrement {
+		return clause.Expr{SQL: "NULL"}
+	}
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+// BindVarTo 模型查询使用 ? 占位符
+func (Dialector) BindVarTo(writer clause.Writer, _ *gorm.Statement, _ interface{}) {
+	writer.WriteByte('?')
+}
+
+// QuoteTo 使用双引号引用标识符，支持 schema.table 形式
+func (Dialector) QuoteTo(writer clause.Writer, str string) {
+	for i, part := range strings.Split(str, ".") {
+		if i > 0 {
+			writer.WriteByte('.')
+		}
+		writer.WriteByte('"')
+		writer.WriteString(part)
+		writer.WriteByte('"')
+	}
+}
+
+// Explain 日志中展示带参数的 SQL
+func (Dialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, nil, `'`, vars...)
+}
+
+// SavePoint 嵌套事务保存点
+func (Dialector) SavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("SAVEPOINT " + name).Error
+}
+
+// RollbackTo 回滚到保存点
+func (Dialector) RollbackTo(tx *gorm.DB, name string) error {
+	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
+}
+
+// Migrator SQLite 迁移器，只实现建表所需的元数据查询
+type Migrator struct {
+	migrator.Migrator
+}
+
+// AutoMigrate 只创建缺失的表及其索引，已存在的表不做修改
+// 注意: SQLite 不支持 ALTER COLUMN，模型变更后需删除数据库文件重建
+func (m Migrator) AutoMigrate(values ...interface{}) error {
+	for _, value := range m.ReorderModels(values, true) {
+		if m.HasTable(value) {
+			continue
+		}
+		if err := m.CreateTable(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateIndex 创建索引，支持表达式索引和部分索引（WHERE 条件）
+func (m Migrator) CreateIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema == nil {
+			return errors.New("failed to get schema")
+		}
+		idx := stmt.Schema.LookIndex(name)
+		if idx == nil {
+			return fmt.Errorf("failed to create index with name %s", name)
+		}
+
+		opts := m.BuildIndexOptions(idx.Fields, stmt)
+		values := []interface{}{clause.Column{Name: idx.Name}, m.CurrentTable(stmt), opts}
+
+		createIndexSQL := "CREATE "
+		if idx.Class != "" {
+			createIndexSQL += idx.Class + " "
+		}
+		createIndexSQL += "INDEX IF NOT EXISTS ? ON ??"
+		if idx.Where != "" {
+			createIndexSQL += " WHERE " + idx.Where
+		}
+		return m.DB.Exec(createIndexSQL, values...).Error
+	})
+}
+
+// CurrentDatabase SQLite 只有 main 库
+func (Migrator) CurrentDatabase() string {
+	return "main"
+}
+
+// HasTable 表是否存在
+func (m Migrator) HasTable(value interface{}) bool {
+	var count int64
+	_ = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", stmt.Table).
+			Scan(&count).Error
+	})
+	return count > 0
+}
+
+// HasColumn 列是否存在
+func (m Migrator) HasColumn(value interface{}, name string) bool {
+	var count int64
+	_ = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if field := stmt.Schema.LookUpField(name); field != nil {
+			name = field.DBName
+		}
+		return m.DB.Raw("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", stmt.Table, name).
+			Scan(&count).Error
+	})
+	return count > 0
+}
+
+// HasIndex 索引是否存在
+func (m Migrator) HasIndex(value interface{}, name string) bool {
+	var count int64
+	_ = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema != nil {
+			if idx := stmt.Schema.LookIndex(name); idx != nil {
+				name = idx.Name
+			}
+		}
+		return m.DB.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND name = ?", stmt.Table, name).
+			Scan(&count).Error
+	})
+	return count > 0
+}
+
+// HasConstraint 约束随建表语句一起创建，表存在即视为存在
+func (m Migrator) HasConstraint(value interface{}, _ string) bool {
+	return m.HasTable(value)
+}
+
+// CreateConstraint SQLite 不支持 ALTER TABLE ADD CONSTRAINT，约束只在建表时创建
+func (Migrator) CreateConstraint(interface{}, string) error {
+	return nil
+}
@@ -0,0 +1,14 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// WithTx 在一个数据库事务中执行 fn
+// fn 返回错误或发生 panic 时回滚，否则提交
+// 注意: fn 中的所有数据库操作都必须使用传入的 tx；搜索索引、缓存等外部副作用应在 WithTx 成功返回后再执行
+func WithTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return DB.WithContext(ctx).Transaction(fn)
+}
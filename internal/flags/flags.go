@@ -0,0 +1,293 @@
+// Package flags 提供功能开关：按名称判断功能是否开启，支持按用户 ID 灰度放量
+package flags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnknownFlag 功能开关不存在
+	ErrUnknownFlag = errors.New("unknown feature flag")
+	// ErrInvalidFlagValue 灰度百分比不合法
+	ErrInvalidFlagValue = errors.New("invalid feature flag value")
+)
+
+// redisChannel 功能开关变更通知频道（与 services 中的 Redis 键使用同一命名空间）
+const redisChannel = "blog:flags:changed"
+
+// definition 功能开关定义：说明和代码中的默认状态（灰度百分比默认 100）
+type definition struct {
+	Description string
+	Default     bool
+}
+
+var definitions = map[string]definition{
+	models.FlagNewSearch:       {Description: "新版搜索查询路径"},
+	models.FlagCommentMarkdown: {Description: "评论内容按 Markdown 渲染"},
+	models.FlagCookieAuth:      {Description: "基于 Cookie 的登录态"},
+}
+
+// defaultService 当前进程使用的功能开关服务，由 NewService 注册
+// 未注册时（例如单元测试）各开关使用配置和代码中的默认值
+var defaultService atomic.Pointer[Service]
+
+// Service 功能开关服务
+//
+// 设计考虑：
+// - 开关定义和默认值在代码中，配置（feature_flags / FEATURE_FLAGS）覆盖默认值，管理后台修改（feature_flags 表）优先级最高
+// - 读取走进程内缓存，判断开关不访问数据库和 Redis
+// - 修改后通过 Redis pub/sub 通知所有实例重新加载，与设置服务一致
+// - 灰度按 开关名+用户 ID 的哈希取模，同一用户的结果稳定，不同开关的灰度人群互相独立
+type Service struct {
+	flagRepo *repository.FeatureFlagRepository
+
+	mu        sync.RWMutex
+	overrides map[string]*models.FeatureFlag
+}
+
+// NewService 创建新的功能开关服务实例，并注册为当前进程的默认开关来源
+// flagRepo: 功能开关数据访问层仓库
+func NewService(flagRepo *repository.FeatureFlagRepository) *Service {
+	s := &Service{
+		flagRepo:  flagRepo,
+		overrides: make(map[string]*models.FeatureFlag),
+	}
+	defaultService.Store(s)
+	return s
+}
+
+// Init 加载管理后台的覆盖值，并对配置中未定义的开关名输出警告
+func (s *Service) Init(ctx context.Context) error {
+	for name := range configFlags() {
+		if _, ok := definitions[name]; !ok {
+			l := logger.GetLogger()
+			l.Warn().Str("flag", name).Msg("Unknown feature flag in configuration, ignored")
+		}
+	}
+	return s.Reload(ctx)
+}
+
+// Reload 从数据库重新加载全部覆盖值到本地缓存
+// 注意: 不再定义的开关和非法的灰度百分比会被忽略，回退到配置和代码中的默认值
+func (s *Service) Reload(ctx context.Context) error {
+	stored, err := s.flagRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]*models.FeatureFlag, len(stored))
+	for _, flag := range stored {
+		if _, ok := definitions[flag.Name]; !ok || flag.RolloutPercent < 0 || flag.RolloutPercent > 100 {
+			continue
+		}
+		overrides[flag.Name] = flag
+	}
+
+	s.mu.Lock()
+	s.overrides = overrides
+	s.mu.Unlock()
+	return nil
+}
+
+// List 获取全部功能开关当前生效的状态（按名称排序）
+func (s *Service) List() []*models.FeatureFlagStatus {
+	result := make([]*models.FeatureFlagStatus, 0, len(definitions))
+	for name := range definitions {
+		result = append(result, s.status(name))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Update 批量修改功能开关
+// changes: 开关名到修改内容的映射，未提供的字段保持当前生效的值
+// updatedBy: 操作人用户ID
+// 返回: 修改后的全部开关状态，如果存在未知开关或非法值则整体不生效并返回错误
+// 注意: 修改成功后会通知其他实例刷新缓存
+func (s *Service) Update(ctx context.Context, changes map[string]models.FeatureFlagChange, updatedBy *uuid.UUID) ([]*models.FeatureFlagStatus, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("%w: no flags provided", ErrInvalidFlagValue)
+	}
+
+	flags := make([]*models.FeatureFlag, 0, len(changes))
+	for name, change := range changes {
+		if _, ok := definitions[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFlag, name)
+		}
+		current := s.status(name)
+		flag := &models.FeatureFlag{Name: name, Enabled: current.Enabled, RolloutPercent: current.RolloutPercent}
+		if change.Enabled != nil {
+			flag.Enabled = *change.Enabled
+		}
+		if change.RolloutPercent != nil {
+			if *change.RolloutPercent < 0 || *change.RolloutPercent > 100 {
+				return nil, fmt.Errorf("%w: %s: rollout_percent must be between 0 and 100", ErrInvalidFlagValue, name)
+			}
+			flag.RolloutPercent = *change.RolloutPercent
+		}
+		flags = append(flags, flag)
+	}
+
+	if err := s.flagRepo.Upsert(ctx, flags, updatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+	publishFlagsChanged()
+
+	return s.List(), nil
+}
+
+// Enabled 判断功能开关对 ctx 中的用户是否开启
+// 注意: 灰度百分比小于 100 时按 ctx 中的用户 ID（见 logger.WithUserID）判断，匿名请求视为未命中
+func (s *Service) Enabled(ctx context.Context, name string) bool {
+	if _, ok := definitions[name]; !ok {
+		return false
+	}
+	status := s.status(name)
+	return evaluate(name, status.Enabled, status.RolloutPercent, logger.ContextFields(ctx).UserID)
+}
+
+// status 返回开关当前生效的状态，name 必须已定义
+func (s *Service) status(name string) *models.FeatureFlagStatus {
+	s.mu.RLock()
+	override, ok := s.overrides[name]
+	s.mu.RUnlock()
+	if !ok {
+		return defaultStatus(name)
+	}
+
+	status := defaultStatus(name)
+	status.Enabled = override.Enabled
+	status.RolloutPercent = override.RolloutPercent
+	status.Source = models.FlagSourceDatabase
+	status.UpdatedBy = override.UpdatedBy
+	updatedAt := override.UpdatedAt
+	status.UpdatedAt = &updatedAt
+	return status
+}
+
+// Subscribe 监听功能开关变更通知并刷新本地缓存，直到 ctx 取消
+// 注意: Redis 未初始化时直接返回，此时只有本实例的修改会立即生效
+func (s *Service) Subscribe(ctx context.Context) {
+	if database.RedisClient == nil {
+		return
+	}
+
+	pubsub := database.RedisClient.Subscribe(ctx, redisChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+			reloadCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if err := s.Reload(reloadCtx); err != nil {
+				l := logger.GetLogger()
+				l.Warn().Err(err).Msg("failed to reload feature flags after change notification")
+			}
+			cancel()
+		}
+	}
+}
+
+// Enabled 通过当前进程的功能开关服务判断开关是否开启
+// 服务未初始化时使用配置和代码中的默认值；未定义的开关始终返回 false
+func Enabled(ctx context.Context, name string) bool {
+	if s := defaultService.Load(); s != nil {
+		return s.Enabled(ctx, name)
+	}
+	if _, ok := definitions[name]; !ok {
+		return false
+	}
+	status := defaultStatus(name)
+	return evaluate(name, status.Enabled, status.RolloutPercent, logger.ContextFields(ctx).UserID)
+}
+
+// evaluate 按开关状态和灰度百分比判断是否对 userID 开启
+func evaluate(name string, enabled bool, rolloutPercent int, userID string) bool {
+	switch {
+	case !enabled || rolloutPercent <= 0:
+		return false
+	case rolloutPercent >= 100:
+		return true
+	case userID == "":
+		return false
+	}
+	return bucket(name, userID) < rolloutPercent
+}
+
+// bucket 将用户映射到 [0, 100) 的灰度分桶
+func bucket(name, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{':'})
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
+
+// defaultStatus 返回开关在配置和代码中的默认状态，name 必须已定义
+func defaultStatus(name string) *models.FeatureFlagStatus {
+	def := definitions[name]
+	status := &models.FeatureFlagStatus{
+		Name:           name,
+		Description:    def.Description,
+		Enabled:        def.Default,
+		RolloutPercent: 100,
+		Source:         models.FlagSourceDefault,
+	}
+	if value, ok := configFlags()[name]; ok {
+		if enabled, percent, err := config.ParseFeatureFlag(value); err == nil {
+			status.Enabled = enabled
+			status.RolloutPercent = percent
+			status.Source = models.FlagSourceConfig
+		}
+	}
+	return status
+}
+
+// configFlags 返回配置中的功能开关（随配置热加载更新）
+func configFlags() map[string]string {
+	if current := config.Get(); current != nil {
+		return current.FeatureFlags
+	}
+	return nil
+}
+
+func publishFlagsChanged() {
+	if database.RedisClient == nil {
+		return
+	}
+	timeout := config.RedisConfig{}.WriteTimeout()
+	if current := config.Get(); current != nil {
+		timeout = current.Redis.WriteTimeout()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := database.RedisClient.Publish(ctx, redisChannel, time.Now().Unix()).Err(); err != nil {
+		l := logger.GetLogger()
+		l.Warn().Err(err).Msg("failed to publish feature flag change notification")
+	}
+}
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"enterprise-blog/internal/models"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"github.com/graph-gophers/graphql-go/trace/tracer"
+)
+
+// listSizeArgs 决定列表大小的参数及其上限（与解析器中的裁剪一致）
+var listSizeArgs = map[string]int{
+	"pageSize": models.MaxPageSize,
+	"first":    maxCommentsPerArticle,
+}
+
+// complexityBudget 单次查询的复杂度预算
+type complexityBudget struct {
+	limit    int64
+	resolved atomic.Int64 // 已计入的复杂度
+	cancel   context.CancelFunc
+}
+
+// exceeded 查询是否因超出复杂度限制而被中止
+func (b *complexityBudget) exceeded() bool {
+	return b.resolved.Load() > b.limit
+}
+
+type budgetKey struct{}
+
+// withComplexityBudget 为一次查询设置复杂度预算，超出时取消返回的 ctx
+func withComplexityBudget(ctx context.Context, limit int) (context.Context, *complexityBudget) {
+	ctx, cancel := context.WithCancel(ctx)
+	budget := &complexityBudget{limit: int64(limit), cancel: cancel}
+	return context.WithValue(ctx, budgetKey{}, budget), budget
+}
+
+// complexityTracer 在执行过程中统计查询复杂度：每解析一个字段计 1（列表中每一项的字段分别计数），
+// 带 pageSize / first 参数的字段在调用解析器之前先按请求的数量计入，避免一次批量查询取回远超预算的数据
+// 注意: graph-gophers/graphql-go 不公开查询的语法树，无法在执行前估算整个查询的复杂度；
+// 执行器在调用解析器前先调用 TraceField，超出预算时取消 ctx，之后的解析器都不会再执行
+type complexityTracer struct{}
+
+func (complexityTracer) TraceQuery(ctx context.Context, _ string, _ string, _ map[string]interface{}, _ map[string]*introspection.Type) (context.Context, tracer.QueryFinishFunc) {
+	return ctx, func([]*gqlerrors.QueryError) {}
+}
+
+func (complexityTracer) TraceField(ctx context.Context, _, _, _ string, _ bool, args map[string]interface{}) (context.Context, tracer.FieldFinishFunc) {
+	if budget, ok := ctx.Value(budgetKey{}).(*complexityBudget); ok {
+		cost := int64(1)
+		for name, limit := range listSizeArgs {
+			if v, ok := args[name]; ok {
+				cost += int64(listSize(v, limit))
+			}
+		}
+		if budget.resolved.Add(cost) > budget.limit {
+			budget.cancel()
+		}
+	}
+	return ctx, func(*gqlerrors.QueryError) {}
+}
+
+// listSize 将参数值转换为列表大小，超过上限按上限计；非正数时解析器使用默认值，这里保守地按上限计
+func listSize(v interface{}, limit int) int {
+	var n float64
+	switch v := v.(type) {
+	case int32:
+		n = float64(v)
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	case json.Number:
+		n, _ = v.Float64()
+	default:
+		return limit
+	}
+	if n < 1 || n > float64(limit) {
+		return limit
+	}
+	return int(n)
+}
@@ -0,0 +1,123 @@
+// Package graph 提供只读的 GraphQL 接口，解析器委托给现有的服务层
+//
+// 设计考虑：
+// - schema 优先（schema.graphql），由 graph-gophers/graphql-go 按方法名绑定解析器，不需要代码生成
+// - 没有使用 gqlgen：它需要生成代码并提交生成的文件，只读的小型 schema 手写解析器更简单；查询的解析、校验和执行都只用这一个库
+// - 文章的作者、分类和标签由服务层随文章一起加载（与 REST 接口一致）；评论及评论用户通过请求级的 loader 批量查询
+// - 执行前校验嵌套深度，超出限制的查询不会访问数据库；复杂度在执行过程中统计，超出时中止执行（见 complexityTracer）
+// - 只读：schema 中没有 mutation，写操作继续使用 REST 接口
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// loaderWait 合并同一批 Load 的时间窗口
+	loaderWait = 2 * time.Millisecond
+	// maxCommentsPerArticle 每篇文章最多返回的评论数（comments 的 first 参数上限）
+	maxCommentsPerArticle = 100
+	// defaultCommentsPerArticle first 参数缺省或不合法时返回的评论数
+	defaultCommentsPerArticle = 20
+)
+
+// Viewer 当前请求的调用者，匿名请求为零值
+type Viewer struct {
+	UserID uuid.UUID
+	Role   models.UserRole
+}
+
+// Anonymous 是否为匿名请求
+func (v Viewer) Anonymous() bool {
+	return v.UserID == uuid.Nil
+}
+
+// IsAdmin 是否为管理员
+func (v Viewer) IsAdmin() bool {
+	return v.Role == models.RoleAdmin
+}
+
+type viewerKey struct{}
+
+// WithViewer 将调用者写入 ctx，解析器据此判断文章可见性
+func WithViewer(ctx context.Context, viewer Viewer) context.Context {
+	return context.WithValue(ctx, viewerKey{}, viewer)
+}
+
+// viewerFromContext 读取调用者，未设置时为匿名
+func viewerFromContext(ctx context.Context) Viewer {
+	viewer, _ := ctx.Value(viewerKey{}).(Viewer)
+	return viewer
+}
+
+// Request GraphQL 请求体
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Server GraphQL 执行器
+type Server struct {
+	schema        *graphql.Schema
+	maxComplexity int
+
+	articleService  *services.ArticleService
+	categoryService *services.CategoryService
+	tagService      *services.TagService
+	commentRepo     *repository.CommentRepository
+	userRepo        *repository.UserRepository
+}
+
+// NewServer 创建 GraphQL 执行器
+// cfg: 查询深度和复杂度限制
+// 注意: schema 与解析器不匹配时 panic（启动时即可发现）
+func NewServer(articleService *services.ArticleService, categoryService *services.CategoryService, tagService *services.TagService,
+	commentRepo *repository.CommentRepository, userRepo *repository.UserRepository, cfg config.GraphQLConfig) *Server {
+	s := &Server{
+		maxComplexity:   cfg.MaxComplexity,
+		articleService:  articleService,
+		categoryService: categoryService,
+		tagService:      tagService,
+		commentRepo:     commentRepo,
+		userRepo:        userRepo,
+	}
+	s.schema = graphql.MustParseSchema(schemaSDL, &queryResolver{s: s},
+		graphql.MaxDepth(cfg.MaxDepth),
+		// 列表中每一项的字段并发解析，loader 才能把整页合并为一次查询
+		graphql.MaxParallelism(models.MaxPageSize),
+		graphql.Tracer(complexityTracer{}),
+	)
+	return s
+}
+
+// Exec 执行查询
+// 返回: GraphQL 响应，查询错误和解析器错误都在 Errors 中
+// 注意: 超出复杂度限制时丢弃已解析的部分数据，只返回复杂度错误
+func (s *Server) Exec(ctx context.Context, req *Request) *graphql.Response {
+	ctx, budget := withComplexityBudget(ctx, s.maxComplexity)
+	defer budget.cancel()
+
+	ctx = withLoaders(ctx, s)
+	resp := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if budget.exceeded() {
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{
+			gqlerrors.Errorf("query complexity exceeds the limit of %d", s.maxComplexity),
+		}}
+	}
+	return resp
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loader 请求级的批量数据加载器（dataloader）
+//
+// 设计考虑：
+// - 列表中每一项的字段由执行器并发解析，loader 把 wait 时间窗口内（或凑满 maxBatch 个）的 Load 合并成一次批量查询，避免 N+1
+// - 结果按键缓存到请求结束，同一请求中重复的键只查询一次
+// - 批量查询失败时，这一批的每个 Load 都返回该错误；查询结果中缺少的键返回零值
+type loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	entries map[K]*loaderEntry[V]
+	batch   *loaderBatch[K]
+}
+
+type loaderEntry[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable] struct {
+	ctx  context.Context
+	keys []K
+	once sync.Once
+}
+
+// newLoader 创建批量数据加载器
+// fetch: 批量查询，返回按键索引的结果
+// wait: 合并 Load 的时间窗口
+// maxBatch: 每批最多的键数，凑满后立即查询
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error), wait time.Duration, maxBatch int) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		entries:  make(map[K]*loaderEntry[V]),
+	}
+}
+
+// Load 获取 key 对应的值，与同一时间窗口内的其他 Load 合并查询
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &loaderEntry[V]{done: make(chan struct{})}
+		l.entries[key] = entry
+
+		if l.batch == nil {
+			b := &loaderBatch[K]{ctx: ctx}
+			l.batch = b
+			time.AfterFunc(l.wait, func() { l.dispatch(b) })
+		}
+		b := l.batch
+		b.keys = append(b.keys, key)
+		if len(b.keys) >= l.maxBatch {
+			l.batch = nil
+			go l.dispatch(b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-entry.done:
+		return entry.value, entry.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch 执行一批查询（时间窗口到期和凑满一批可能同时触发，只执行一次）
+func (l *loader[K, V]) dispatch(b *loaderBatch[K]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		keys := b.keys
+		l.mu.Unlock()
+
+		values, err := l.fetch(b.ctx, keys)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for _, key := range keys {
+			entry := l.entries[key]
+			entry.value, entry.err = values[key], err
+			close(entry.done)
+		}
+	})
+}
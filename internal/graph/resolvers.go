@@ -0,0 +1,375 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// loaders 请求级的数据加载器，每次 Exec 创建一组，缓存只在请求内有效
+type loaders struct {
+	comments *loader[commentsKey, []*models.Comment]
+	users    *loader[uuid.UUID, *models.User]
+}
+
+// commentsKey 评论按文章和数量加载，同一批中 first 相同的文章合并为一次查询
+type commentsKey struct {
+	ArticleID uuid.UUID
+	First     int
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, s *Server) context.Context {
+	l := &loaders{
+		comments: newLoader(func(ctx context.Context, keys []commentsKey) (map[commentsKey][]*models.Comment, error) {
+			byFirst := make(map[int][]uuid.UUID)
+			for _, key := range keys {
+				byFirst[key.First] = append(byFirst[key.First], key.ArticleID)
+			}
+			result := make(map[commentsKey][]*models.Comment, len(keys))
+			for first, articleIDs := range byFirst {
+				comments, err := s.commentRepo.ListApprovedByArticleIDs(ctx, articleIDs, first)
+				if err != nil {
+					return nil, err
+				}
+				for articleID, list := range comments {
+					result[commentsKey{ArticleID: articleID, First: first}] = list
+				}
+			}
+			return result, nil
+		}, loaderWait, models.MaxPageSize),
+		users: newLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+			users, err := s.userRepo.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uuid.UUID]*models.User, len(users))
+			for _, u := range users {
+				result[u.ID] = u
+			}
+			return result, nil
+		}, loaderWait, models.MaxPageSize),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// canView 调用者是否可以查看文章：已发布文章对所有人可见，其他状态仅作者本人和管理员可见
+func canView(viewer Viewer, article *models.Article) bool {
+	return article.Status == models.StatusPublished || viewer.IsAdmin() ||
+		(!viewer.Anonymous() && article.AuthorID == viewer.UserID)
+}
+
+type queryResolver struct {
+	s *Server
+}
+
+type articlesArgs struct {
+	Page        int32
+	PageSize    int32
+	Status      *string
+	CategoryID  *graphql.ID
+	TagID       *graphql.ID
+	AuthorID    *graphql.ID
+	Search      *string
+	SortBy      *string
+	Order       *string
+	CreatedFrom *string
+	CreatedTo   *string
+}
+
+// Articles 文章列表，筛选条件与 REST 列表相同
+// 注意: 非 published 状态只对管理员和查询自己文章的作者生效，其他调用者按 published 处理
+func (r *queryResolver) Articles(ctx context.Context, args articlesArgs) (*articlePageResolver, error) {
+	query := models.ArticleQuery{Status: models.StatusPublished}
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, int(args.Page), int(args.PageSize))
+
+	var err error
+	if query.CategoryID, err = parseOptionalID("categoryId", args.CategoryID); err != nil {
+		return nil, err
+	}
+	if query.TagID, err = parseOptionalID("tagId", args.TagID); err != nil {
+		return nil, err
+	}
+	if query.AuthorID, err = parseOptionalID("authorId", args.AuthorID); err != nil {
+		return nil, err
+	}
+	if query.CreatedFrom, err = parseOptionalDate("createdFrom", args.CreatedFrom, false); err != nil {
+		return nil, err
+	}
+	if query.CreatedTo, err = parseOptionalDate("createdTo", args.CreatedTo, true); err != nil {
+		return nil, err
+	}
+	if args.Search != nil {
+		query.Search = *args.Search
+	}
+	if args.SortBy != nil {
+		query.SortBy = *args.SortBy
+	}
+	if args.Order != nil {
+		query.Order = *args.Order
+	}
+
+	if args.Status != nil && *args.Status != "" {
+		viewer := viewerFromContext(ctx)
+		ownArticles := !viewer.Anonymous() && query.AuthorID != nil && *query.AuthorID == viewer.UserID
+		if viewer.IsAdmin() || ownArticles {
+			query.Status = models.ArticleStatus(*args.Status)
+		}
+	}
+
+	articles, total, err := r.s.articleService.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &articlePageResolver{articles: articles, total: total, page: query.Page, pageSize: query.PageSize}, nil
+}
+
+// Article 按 slug 获取文章，不存在或无权查看时返回 null
+func (r *queryResolver) Article(ctx context.Context, args struct{ Slug string }) (*articleResolver, error) {
+	article, err := r.s.articleService.GetBySlug(ctx, args.Slug)
+	if errors.Is(err, repository.ErrArticleNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !canView(viewerFromContext(ctx), article) {
+		return nil, nil
+	}
+	return &articleResolver{a: article}, nil
+}
+
+// Categories 分类树（含已发布文章数）
+func (r *queryResolver) Categories(ctx context.Context) ([]*categoryNodeResolver, error) {
+	nodes, err := r.s.categoryService.Tree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newCategoryNodeResolvers(nodes), nil
+}
+
+// Tags 标签列表（含已发布文章数）
+func (r *queryResolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	tags, err := r.s.tagService.List(ctx, models.TaxonomyListQuery{PublishedOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*tagResolver, len(tags))
+	for i, t := range tags {
+		result[i] = &tagResolver{t: t}
+	}
+	return result, nil
+}
+
+type articlePageResolver struct {
+	articles []*models.Article
+	total    int64
+	page     int
+	pageSize int
+}
+
+func (r *articlePageResolver) Items() []*articleResolver {
+	result := make([]*articleResolver, len(r.articles))
+	for i, a := range r.articles {
+		result[i] = &articleResolver{a: a}
+	}
+	return result
+}
+
+func (r *articlePageResolver) Total() int32    { return int32(r.total) }
+func (r *articlePageResolver) Page() int32     { return int32(r.page) }
+func (r *articlePageResolver) PageSize() int32 { return int32(r.pageSize) }
+
+type articleResolver struct {
+	a *models.Article
+}
+
+func (r *articleResolver) ID() graphql.ID          { return graphql.ID(r.a.ID.String()) }
+func (r *articleResolver) Title() string           { return r.a.Title }
+func (r *articleResolver) Slug() string            { return r.a.Slug }
+func (r *articleResolver) Content() string         { return r.a.Content }
+func (r *articleResolver) Excerpt() string         { return r.a.Excerpt }
+func (r *articleResolver) CoverImage() string      { return r.a.CoverImage }
+func (r *articleResolver) Status() string          { return string(r.a.Status) }
+func (r *articleResolver) ViewCount() int32        { return int32(r.a.ViewCount) }
+func (r *articleResolver) LikeCount() int32        { return int32(r.a.LikeCount) }
+func (r *articleResolver) CommentCount() int32     { return int32(r.a.CommentCount) }
+func (r *articleResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.a.CreatedAt} }
+func (r *articleResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.a.UpdatedAt} }
+
+func (r *articleResolver) PublishedAt() *graphql.Time {
+	if r.a.PublishedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.a.PublishedAt}
+}
+
+func (r *articleResolver) Author() *authorResolver {
+	if r.a.Author == nil || r.a.Author.ID == uuid.Nil {
+		return nil
+	}
+	return &authorResolver{u: r.a.Author}
+}
+
+func (r *articleResolver) Category() *categoryResolver {
+	if r.a.Category == nil {
+		return nil
+	}
+	return &categoryResolver{c: r.a.Category}
+}
+
+func (r *articleResolver) Tags() []*tagResolver {
+	result := make([]*tagResolver, len(r.a.Tags))
+	for i := range r.a.Tags {
+		result[i] = &tagResolver{t: &r.a.Tags[i]}
+	}
+	return result
+}
+
+// Comments 已通过审核的评论，同一页文章的评论合并为一次查询
+func (r *articleResolver) Comments(ctx context.Context, args struct{ First int32 }) ([]*commentResolver, error) {
+	first := defaultCommentsPerArticle
+	if args.First > 0 {
+		first = min(int(args.First), maxCommentsPerArticle)
+	}
+	comments, err := loadersFromContext(ctx).comments.Load(ctx, commentsKey{ArticleID: r.a.ID, First: first})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*commentResolver, len(comments))
+	for i, c := range comments {
+		result[i] = &commentResolver{c: c}
+	}
+	return result, nil
+}
+
+type authorResolver struct {
+	u *models.User
+}
+
+func (r *authorResolver) ID() graphql.ID   { return graphql.ID(r.u.ID.String()) }
+func (r *authorResolver) Username() string { return r.u.Username }
+func (r *authorResolver) Avatar() string   { return r.u.Avatar }
+
+type categoryResolver struct {
+	c *models.Category
+}
+
+func (r *categoryResolver) ID() graphql.ID { return graphql.ID(r.c.ID.String()) }
+func (r *categoryResolver) Name() string   { return r.c.Name }
+func (r *categoryResolver) Slug() string   { return r.c.Slug }
+
+type categoryNodeResolver struct {
+	n *models.CategoryNode
+}
+
+func newCategoryNodeResolvers(nodes []*models.CategoryNode) []*categoryNodeResolver {
+	result := make([]*categoryNodeResolver, len(nodes))
+	for i, n := range nodes {
+		result[i] = &categoryNodeResolver{n: n}
+	}
+	return result
+}
+
+func (r *categoryNodeResolver) ID() graphql.ID      { return graphql.ID(r.n.ID.String()) }
+func (r *categoryNodeResolver) Name() string        { return r.n.Name }
+func (r *categoryNodeResolver) Slug() string        { return r.n.Slug }
+func (r *categoryNodeResolver) Description() string { return r.n.Description }
+func (r *categoryNodeResolver) ParentID() *graphql.ID {
+	return optionalID(r.n.ParentID)
+}
+
+func (r *categoryNodeResolver) ArticleCount() int32 {
+	if r.n.ArticleCount == nil {
+		return 0
+	}
+	return int32(*r.n.ArticleCount)
+}
+
+func (r *categoryNodeResolver) Children() []*categoryNodeResolver {
+	return newCategoryNodeResolvers(r.n.Children)
+}
+
+type tagResolver struct {
+	t *models.Tag
+}
+
+func (r *tagResolver) ID() graphql.ID { return graphql.ID(r.t.ID.String()) }
+func (r *tagResolver) Name() string   { return r.t.Name }
+func (r *tagResolver) Slug() string   { return r.t.Slug }
+func (r *tagResolver) Color() string  { return r.t.Color }
+
+func (r *tagResolver) ArticleCount() *int32 {
+	if r.t.ArticleCount == nil {
+		return nil
+	}
+	n := int32(*r.t.ArticleCount)
+	return &n
+}
+
+type commentResolver struct {
+	c *models.Comment
+}
+
+func (r *commentResolver) ID() graphql.ID          { return graphql.ID(r.c.ID.String()) }
+func (r *commentResolver) ParentID() *graphql.ID   { return optionalID(r.c.ParentID) }
+func (r *commentResolver) Author() string          { return r.c.Author }
+func (r *commentResolver) Website() string         { return r.c.Website }
+func (r *commentResolver) Content() string         { return r.c.Content }
+func (r *commentResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.c.CreatedAt} }
+
+// User 评论用户，同一批评论的用户合并为一次查询
+func (r *commentResolver) User(ctx context.Context) (*authorResolver, error) {
+	if r.c.UserID == nil {
+		return nil, nil
+	}
+	user, err := loadersFromContext(ctx).users.Load(ctx, *r.c.UserID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &authorResolver{u: user}, nil
+}
+
+func optionalID(id *uuid.UUID) *graphql.ID {
+	if id == nil {
+		return nil
+	}
+	gid := graphql.ID(id.String())
+	return &gid
+}
+
+func parseOptionalID(name string, id *graphql.ID) (*uuid.UUID, error) {
+	if id == nil {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(string(*id))
+	if err != nil {
+		return nil, errors.New("invalid " + name)
+	}
+	return &parsed, nil
+}
+
+// parseOptionalDate 解析 RFC3339 时间或 YYYY-MM-DD 日期（与 REST 列表的 created_from / created_to 相同）
+// end: 是否为范围终点，日期作为终点时包含整天
+func parseOptionalDate(name string, value *string, end bool) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	t, err := models.ParseTimeBound(*value, end)
+	if err != nil {
+		return nil, errors.New(name + " must be an RFC3339 time or a YYYY-MM-DD date")
+	}
+	return &t, nil
+}
@@ -1,55 +1,118 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AdminHandler 提供仪表盘和系统配置等后台管理接口
-type AdminHandler struct{}
-
-func NewAdminHandler() *AdminHandler {
-	return &AdminHandler{}
+type AdminHandler struct {
+	dashboardService *services.DashboardService
+	reloadService    *services.ReloadService
 }
 
-// AdminDashboardData 仪表盘统计数据
-type AdminDashboardData struct {
-	TotalUsers          int64 `json:"total_users"`
-	TotalArticles       int64 `json:"total_articles"`
-	PublishedArticles   int64 `json:"published_articles"`
-	DraftArticles       int64 `json:"draft_articles"`
-	ArchivedArticles    int64 `json:"archived_articles"`
-	TotalComments       int64 `json:"total_comments"`
-	TotalArticleViews   int64 `json:"total_article_views"`
-	TotalArticleLikes   int64 `json:"total_article_likes"`
-	TodayPublishedCount int64 `json:"today_published_count"`
+func NewAdminHandler(dashboardService *services.DashboardService, reloadService *services.ReloadService) *AdminHandler {
+	return &AdminHandler{dashboardService: dashboardService, reloadService: reloadService}
 }
 
-// Dashboard 返回后台仪表盘核心统计
+// Dashboard 返回后台仪表盘核心统计（含待处理队列计数）
 func (h *AdminHandler) Dashboard(c *gin.Context) {
-	var data AdminDashboardData
+	data, err := h.dashboardService.Overview(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(data))
+}
+
+// DashboardTopQuery 仪表盘排行榜查询参数
+type DashboardTopQuery struct {
+	Kind   string `form:"kind" binding:"required"`
+	Period string `form:"period"`
+	Limit  int    `form:"limit"`
+}
 
-	// 用户总数
-	_ = database.DB.Raw("SELECT COUNT(*) FROM users WHERE deleted_at IS NULL").Scan(&data.TotalUsers).Error
+// DashboardTop 返回仪表盘排行榜（热门文章 / 评论最多文章 / 活跃作者）
+func (h *AdminHandler) DashboardTop(c *gin.Context) {
+	var query DashboardTopQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.dashboardService.Top(c.Request.Context(), models.DashboardTopKind(query.Kind), query.Period, query.Limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTopKind) || errors.Is(err, services.ErrInvalidTopPeriod) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
 
-	// 文章相关统计
-	_ = database.DB.Raw("SELECT COUNT(*) FROM articles WHERE deleted_at IS NULL").Scan(&data.TotalArticles).Error
-	_ = database.DB.Raw("SELECT COUNT(*) FROM articles WHERE status = 'published' AND deleted_at IS NULL").Scan(&data.PublishedArticles).Error
-	_ = database.DB.Raw("SELECT COUNT(*) FROM articles WHERE status = 'draft' AND deleted_at IS NULL").Scan(&data.DraftArticles).Error
-	_ = database.DB.Raw("SELECT COUNT(*) FROM articles WHERE status = 'archived' AND deleted_at IS NULL").Scan(&data.ArchivedArticles).Error
-	_ = database.DB.Raw("SELECT COALESCE(SUM(view_count), 0) FROM articles WHERE deleted_at IS NULL").Scan(&data.TotalArticleViews).Error
-	_ = database.DB.Raw("SELECT COALESCE(SUM(like_count), 0) FROM articles WHERE deleted_at IS NULL").Scan(&data.TotalArticleLikes).Error
-	_ = database.DB.Raw("SELECT COUNT(*) FROM articles WHERE status = 'published' AND deleted_at IS NULL AND DATE(published_at) = CURRENT_DATE").Scan(&data.TodayPublishedCount).Error
+	c.JSON(http.StatusOK, models.Success(result))
+}
 
-	// 评论总数
-	_ = database.DB.Raw("SELECT COUNT(*) FROM comments WHERE deleted_at IS NULL").Scan(&data.TotalComments).Error
+// CacheFlushRequest 缓存清理请求参数（支持 query 或 JSON body）
+type CacheFlushRequest struct {
+	Scope string `form:"scope" json:"scope"`
+}
 
-	c.JSON(http.StatusOK, models.Success(data))
+// CacheFlushResult 缓存清理结果
+type CacheFlushResult struct {
+	Scope   string           `json:"scope"`
+	Removed map[string]int64 `json:"removed"`
+	Total   int64            `json:"total"`
+}
+
+// FlushCache 按范围清理应用缓存（不影响限流和短信验证码）
+// POST /api/v1/admin/cache/flush?scope=article_detail|article_list|dashboard|all_app_caches
+func (h *AdminHandler) FlushCache(c *gin.Context) {
+	var req CacheFlushRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBind(&req); err != nil {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if req.Scope == "" {
+		req.Scope = c.Query("scope")
+	}
+
+	removed, err := services.FlushCache(c.Request.Context(), services.CacheScope(req.Scope))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCacheScope):
+			respondServiceError(c, http.StatusBadRequest, err)
+		case errors.Is(err, database.ErrRedisUnavailable):
+			respondServiceError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	result := CacheFlushResult{Scope: req.Scope, Removed: make(map[string]int64, len(removed))}
+	for scope, n := range removed {
+		result.Removed[string(scope)] = n
+		result.Total += n
+	}
+	c.JSON(http.StatusOK, models.Success(result))
+}
+
+// SystemStatus 返回当前实例的连接池、索引队列、后台任务和进程状态
+// GET /api/v1/admin/system/status
+func (h *AdminHandler) SystemStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Success(services.CollectSystemStatus()))
 }
 
 // SystemConfigInfo 对外暴露的系统配置（脱敏）
@@ -89,9 +152,9 @@ type SystemConfigInfo struct {
 
 // SystemConfig 返回当前运行时的系统配置（只读，敏感字段已脱敏）
 func (h *AdminHandler) SystemConfig(c *gin.Context) {
-	cfg := config.AppConfig
+	cfg := config.Get()
 	if cfg == nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, "config not loaded"))
+		respondError(c, http.StatusInternalServerError, i18n.CodeInternal)
 		return
 	}
 
@@ -124,4 +187,19 @@ func (h *AdminHandler) SystemConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, models.Success(info))
 }
 
-
+// ReloadConfig 重新加载配置（与向进程发送 SIGHUP 相同），返回已生效和需要重启才能生效的配置键
+// POST /api/v1/admin/system/reload
+// 注意: 只作用于处理该请求的实例，多实例部署时需逐个触发
+func (h *AdminHandler) ReloadConfig(c *gin.Context) {
+	result, err := h.reloadService.Reload(c.Request.Context())
+	if err != nil {
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			respondErrorWithData(c, http.StatusBadRequest, i18n.CodeInvalidConfiguration, validationErr.Problems)
+			return
+		}
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.Success(result))
+}
@@ -1,9 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
 	"enterprise-blog/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -23,21 +33,27 @@ func NewArticleHandler(articleService *services.ArticleService) *ArticleHandler
 func (h *ArticleHandler) Create(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
 	var req models.ArticleCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
-	// 非管理员创建文章时不允许直接发布，只能是草稿或待审核
+	// 非管理员创建文章时不允许直接发布，只能是草稿、待审核或定时发布
 	if roleVal, ok := c.Get("role"); ok {
 		if roleStr, ok2 := roleVal.(string); ok2 && roleStr != string(models.RoleAdmin) {
-			if req.Status == models.StatusReview {
-				// 保留“待审核”状态
+			if req.Status == models.StatusScheduled && req.ScheduledAt != nil && !req.ScheduledAt.After(time.Now()) {
+				// 定时发布时间已过去时会立即发布，改为提交审核
+				req.Status = models.StatusReview
+			} else if req.Status == models.StatusReview || req.Status == models.StatusScheduled {
+				// 保留“待审核”和“定时发布”状态
 			} else {
 				// 其余情况一律归为草稿
 				req.Status = models.StatusDraft
@@ -45,60 +61,139 @@ func (h *ArticleHandler) Create(c *gin.Context) {
 		}
 	}
 
-	article, err := h.articleService.Create(userID.(uuid.UUID), &req)
+	article, err := h.articleService.Create(c.Request.Context(), userID.(uuid.UUID), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		if writeTagsNotFoundError(c, err) || writeContentRejectedError(c, err) {
+			return
+		}
+		respondServiceError(c, conflictErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.Success(article))
+	respondCreated(c, "/articles/"+article.ID.String(), article)
 }
 
 func (h *ArticleHandler) GetByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
 		return
 	}
 
-	article, err := h.articleService.GetByID(id)
+	article, err := h.articleService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
+	h.setLiked(c, article)
 
+	setArticleETag(c, article)
 	c.JSON(http.StatusOK, models.Success(article))
 }
 
+// GetBySlug 根据 slug 获取文章详情
+// GET /api/v1/articles/slug/:slug
+// 文章修改 slug 后按旧 slug 访问返回 301，Location 指向新地址，响应体仍包含文章
 func (h *ArticleHandler) GetBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 	
-	article, err := h.articleService.GetBySlug(slug)
+	article, err := h.articleService.GetBySlug(c.Request.Context(), slug)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
+	h.setLiked(c, article)
 
+	if article.Slug != slug {
+		c.Header("Location", "/api/v1/articles/slug/"+url.PathEscape(article.Slug))
+		c.JSON(http.StatusMovedPermanently, models.Success(article))
+		return
+	}
 	c.JSON(http.StatusOK, models.Success(article))
 }
 
+// GetMeta 获取已发布文章的 SEO 字段（不含正文，不计入浏览次数）
+// GET /api/v1/articles/slug/:slug/meta
+func (h *ArticleHandler) GetMeta(c *gin.Context) {
+	meta, err := h.articleService.GetMeta(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(meta))
+}
+
+// ExportHTML 下载文章的独立 HTML（阅读 / 打印视图）
+// GET /api/v1/articles/:id/export.html
+// 注意: 未发布的文章只有作者本人可以导出，其他情况按不存在返回 404
+func (h *ArticleHandler) ExportHTML(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	var userID *uuid.UUID
+	if uid, _, ok := currentUser(c); ok {
+		userID = &uid
+	}
+
+	export, err := h.articleService.ExportHTML(c.Request.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.Filename}))
+	// 草稿只能由作者导出，不允许共享缓存
+	c.Header("Cache-Control", "private")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", export.HTML)
+}
+
+// Update 更新文章（作者本人、编辑和管理员）
+// PUT /api/v1/articles/:id
 func (h *ArticleHandler) Update(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
 	var req models.ArticleUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
+	// 请求体未带 expected_version 时使用 If-Match 请求头
+	if req.ExpectedVersion == nil {
+		version, err := parseIfMatchVersion(c)
+		if err != nil {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		req.ExpectedVersion = version
+	}
 
-	// 非管理员更新文章时不允许自行改为已发布 / 归档，仅允许草稿或待审核
+	// 非管理员更新文章时不允许自行改为已发布 / 归档，仅允许草稿、待审核或定时发布
 	if roleVal, ok := c.Get("role"); ok && req.Status != nil {
 		if roleStr, ok2 := roleVal.(string); ok2 && roleStr != string(models.RoleAdmin) {
-			if *req.Status == models.StatusReview || *req.Status == models.StatusDraft {
+			if *req.Status == models.StatusReview || *req.Status == models.StatusDraft || *req.Status == models.StatusScheduled {
 				// 保留可允许的状态
 			} else {
 				// 其余状态重置为草稿
@@ -108,62 +203,417 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 		}
 	}
 
-	article, err := h.articleService.Update(id, &req)
+	article, err := h.articleService.UpdateAsUser(c.Request.Context(), id, userID, role, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		writeArticleUpdateError(c, err)
 		return
 	}
 
+	setArticleETag(c, article)
 	c.JSON(http.StatusOK, models.Success(article))
 }
 
+// GenerateSummary 为文章生成摘要和 SEO 描述建议，不保存（作者只能为自己的文章生成）
+// POST /api/v1/articles/:id/generate-summary
+func (h *ArticleHandler) GenerateSummary(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+	role, _ := c.Get("role")
+	canEditOthers := role == string(models.RoleAdmin) || role == string(models.RoleEditor)
+
+	summary, err := h.articleService.GenerateSummary(c.Request.Context(), id, userID.(uuid.UUID), canEditOthers)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, services.ErrNotArticleAuthor) {
+			status = http.StatusForbidden
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(summary))
+}
+
+// StatusHistory 获取文章的状态变更历史（作者只能查看自己的文章，编辑和管理员可查看全部）
+// GET /api/v1/articles/:id/status-history
+func (h *ArticleHandler) StatusHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+	role, _ := c.Get("role")
+	canViewOthers := role == string(models.RoleAdmin) || role == string(models.RoleEditor)
+
+	history, err := h.articleService.StatusHistory(c.Request.Context(), id, userID.(uuid.UUID), canViewOthers)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, services.ErrNotArticleAuthor) {
+			status = http.StatusForbidden
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(history))
+}
+
+// ListRevisions 分页获取文章的修订记录（作者本人、编辑和管理员），只含元数据
+// GET /api/v1/articles/:id/revisions?page=&page_size=
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	revisions, total, err := h.articleService.ListRevisions(c.Request.Context(), id, userID, role, page, pageSize)
+	if err != nil {
+		respondServiceError(c, articleAccessErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(revisions, page, pageSize, total))
+}
+
+// GetRevision 获取一条修订记录（含正文）
+// GET /api/v1/articles/:id/revisions/:rev_id
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	id, revisionID, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	revision, err := h.articleService.GetRevision(c.Request.Context(), id, revisionID, userID, role)
+	if err != nil {
+		respondServiceError(c, articleAccessErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(revision))
+}
+
+// RestoreRevision 将文章的标题、正文和摘要恢复为修订记录中的内容，恢复前的内容会写入新的修订记录
+// POST /api/v1/articles/:id/revisions/:rev_id/restore
+func (h *ArticleHandler) RestoreRevision(c *gin.Context) {
+	id, revisionID, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	article, err := h.articleService.RestoreRevision(c.Request.Context(), id, revisionID, userID, role)
+	if err != nil {
+		writeArticleUpdateError(c, err)
+		return
+	}
+
+	setArticleETag(c, article)
+	c.JSON(http.StatusOK, models.Success(article))
+}
+
+// DiffRevisions 比较两条修订记录（:rev_id 为旧版本，:to_id 为新版本）的标题、摘要和正文
+// GET /api/v1/articles/:id/revisions/:rev_id/diff/:to_id?format=html
+// 默认返回按词的差异片段，format=html 时返回带 <ins> / <del> 标记、已转义的 HTML
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	id, fromID, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+	toID, err := uuid.Parse(c.Param("to_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidRevisionID)
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "html" {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidRequest)
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	diff, err := h.articleService.DiffRevisions(c.Request.Context(), id, fromID, toID, userID, role)
+	if err != nil {
+		status := articleAccessErrorStatus(err, http.StatusInternalServerError)
+		if errors.Is(err, services.ErrRevisionDiffTooLarge) {
+			status = http.StatusUnprocessableEntity
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	if format == "html" {
+		c.JSON(http.StatusOK, models.Success(diff.HTML()))
+		return
+	}
+	c.JSON(http.StatusOK, models.Success(diff))
+}
+
+// parseRevisionParams 解析路径中的文章 ID 和修订记录 ID，格式错误时写入 400 响应
+func parseRevisionParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	revisionID, err := uuid.Parse(c.Param("rev_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidRevisionID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, revisionID, true
+}
+
+// Delete 删除文章（作者本人、编辑和管理员）
+// DELETE /api/v1/articles/:id
 func (h *ArticleHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
-	if err := h.articleService.Delete(id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := h.articleService.DeleteAsUser(c.Request.Context(), id, userID, role); err != nil {
+		respondServiceError(c, articleAccessErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	respondNoContent(c)
 }
 
+// LikeRateLimitKey 点赞限流的计数键：按文章 ID 计数，ID 的大小写等不同写法计为同一篇文章
+func LikeRateLimitKey(c *gin.Context) string {
+	if id, err := uuid.Parse(c.Param("id")); err == nil {
+		return "like:" + id.String()
+	}
+	return "like:" + c.Param("id")
+}
+
+// articleLiker 当前请求的点赞者：登录用户按用户 ID，匿名用户按客户端 IP
+func articleLiker(c *gin.Context) models.ArticleLiker {
+	if v, ok := c.Get("user_id"); ok {
+		if userID, ok := v.(uuid.UUID); ok {
+			return services.NewArticleLiker(&userID, "")
+		}
+	}
+	return services.NewArticleLiker(nil, c.ClientIP())
+}
+
+// setLiked 在文章详情中标记当前用户是否已点赞，查询失败时不返回该字段
+func (h *ArticleHandler) setLiked(c *gin.Context, article *models.Article) {
+	liked, err := h.articleService.HasLiked(c.Request.Context(), article.ID, articleLiker(c))
+	if err != nil {
+		return
+	}
+	article.Liked = &liked
+}
+
+// ArticleStates 批量查询当前用户对文章的点赞状态
+// POST /api/v1/users/article-states
+func (h *ArticleHandler) ArticleStates(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+	var req models.ArticleStatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.articleService.ArticleStates(c.Request.Context(), userID, req.ArticleIDs)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(result))
+}
+
+// Like 点赞文章，重复点赞不增加计数
+// POST /api/v1/articles/:id/like
 func (h *ArticleHandler) Like(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+
+	if _, err := h.articleService.Like(c.Request.Context(), id, articleLiker(c)); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			status = http.StatusNotFound
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(gin.H{"liked": true}))
+}
+
+// Unlike 取消点赞，未点过赞时不减少计数
+// DELETE /api/v1/articles/:id/like
+func (h *ArticleHandler) Unlike(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
 		return
 	}
 
-	if err := h.articleService.Like(id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if _, err := h.articleService.Unlike(c.Request.Context(), id, articleLiker(c)); err != nil {
+		respondServiceError(c, articleAccessErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	c.JSON(http.StatusOK, models.Success(gin.H{"liked": false}))
 }
 
 func (h *ArticleHandler) List(c *gin.Context) {
 	var query models.ArticleQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := bindArticleQuery(c, &query); err != nil {
+		respondBindError(c, err)
 		return
 	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
+
+	// 公开文章列表：只展示已发布文章
+	// 草稿等其他状态只能通过 /users/articles（本人）或管理后台查看，按作者缓存的草稿列表也不会被公开接口读到
+	query.Status = models.StatusPublished
 
-	// 公开文章列表：默认只展示已发布文章
-	if query.Status == "" {
-		query.Status = models.StatusPublished
+	// 提供 search 参数时走全文搜索：Elasticsearch 已初始化时使用 Elasticsearch（带上分类、标签、作者和排序条件），
+	// 未初始化或请求失败时回退到数据库全文索引
+	articles, total, err := h.articleService.List(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
 	}
 
-	// 全文搜索已完全使用Elasticsearch
-	// 如果提供了search参数，会自动使用Elasticsearch搜索
-	articles, total, err := h.articleService.List(query)
+	c.JSON(http.StatusOK, models.Paginated(articles, query.Page, query.PageSize, total))
+}
+
+// Archive 按发布月份统计已发布文章数（归档页面使用）
+// GET /api/v1/articles/archive
+// 返回: [{year, month, count}]，按月份倒序；某月的文章列表使用 GET /api/v1/articles?year=&month=
+func (h *ArticleHandler) Archive(c *gin.Context) {
+	months, err := h.articleService.Archive(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(months))
+}
+
+// bindArticleQuery 绑定文章列表查询参数，并解析 category_id、tag_id、author_id 和时间范围
+// 返回: 参数格式错误（含 ID 不是合法 UUID、时间不是 RFC3339 或 YYYY-MM-DD）时返回错误
+func bindArticleQuery(c *gin.Context, query *models.ArticleQuery) error {
+	if err := c.ShouldBindQuery(query); err != nil {
+		return err
+	}
+	for _, f := range []struct {
+		name string
+		dst  **uuid.UUID
+	}{
+		{"category_id", &query.CategoryID},
+		{"tag_id", &query.TagID},
+		{"author_id", &query.AuthorID},
+	} {
+		v := c.Query(f.name)
+		if v == "" {
+			continue
+		}
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", f.name, err)
+		}
+		*f.dst = &id
+	}
+	for _, f := range []struct {
+		name string
+		end  bool
+		dst  **time.Time
+	}{
+		{"created_from", false, &query.CreatedFrom},
+		{"created_to", true, &query.CreatedTo},
+		{"published_from", false, &query.PublishedFrom},
+		{"published_to", true, &query.PublishedTo},
+	} {
+		v := c.Query(f.name)
+		if v == "" {
+			continue
+		}
+		t, err := models.ParseTimeBound(v, f.end)
+		if err != nil {
+			return &queryParamError{Field: f.name, Rule: "datetime", Err: err}
+		}
+		*f.dst = &t
+	}
+	return nil
+}
+
+// ListMine 当前用户自己的文章列表：包含草稿、待审核、定时发布、归档等全部状态，支持按状态过滤
+// GET /api/v1/users/articles
+func (h *ArticleHandler) ListMine(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	var query models.ArticleQuery
+	if err := bindArticleQuery(c, &query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
+
+	// 作者固定为当前用户，忽略请求中的 author_id
+	authorID := userID.(uuid.UUID)
+	query.AuthorID = &authorID
+
+	articles, total, err := h.articleService.List(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -173,14 +623,15 @@ func (h *ArticleHandler) List(c *gin.Context) {
 // AdminList 管理后台文章列表：包含所有状态、支持按作者/状态/搜索过滤
 func (h *ArticleHandler) AdminList(c *gin.Context) {
 	var query models.ArticleQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := bindArticleQuery(c, &query); err != nil {
+		respondBindError(c, err)
 		return
 	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
 
-	articles, total, err := h.articleService.List(query)
+	articles, total, err := h.articleService.List(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -191,13 +642,13 @@ func (h *ArticleHandler) AdminList(c *gin.Context) {
 func (h *ArticleHandler) AdminGetByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
 		return
 	}
 
-	article, err := h.articleService.GetByID(id)
+	article, err := h.articleService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
 
@@ -208,28 +659,35 @@ func (h *ArticleHandler) AdminGetByID(c *gin.Context) {
 func (h *ArticleHandler) AdminUpdateStatus(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
 		return
 	}
 
-	var payload struct {
-		Status models.ArticleStatus `json:"status"`
-	}
+	var payload models.ArticleStatusUpdate
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
-
-	req := models.ArticleUpdate{
-		Status: &payload.Status,
+	if !validateRequest(c, &payload) {
+		return
+	}
+	if payload.ExpectedVersion == nil {
+		version, err := parseIfMatchVersion(c)
+		if err != nil {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		payload.ExpectedVersion = version
 	}
 
-	article, err := h.articleService.Update(id, &req)
+	// 只修改状态，版本号可选：未提供时以服务端当前版本为准
+	article, err := h.articleService.UpdateStatus(c.Request.Context(), id, payload.Status, payload.ExpectedVersion, payload.Reason)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		writeArticleUpdateError(c, err)
 		return
 	}
 
+	setArticleETag(c, article)
 	c.JSON(http.StatusOK, models.Success(article))
 }
 
@@ -237,15 +695,113 @@ func (h *ArticleHandler) AdminUpdateStatus(c *gin.Context) {
 func (h *ArticleHandler) AdminDelete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
 		return
 	}
 
-	if err := h.articleService.Delete(id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := h.articleService.Delete(c.Request.Context(), id); err != nil {
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	respondNoContent(c)
 }
 
+// BackfillReadingStats 为已有文章回填字数和阅读时长（功能上线后执行一次，可重复执行）
+// POST /api/v1/admin/articles/reading-stats/backfill
+// 返回: {"updated": n}，n 为数值有变化的文章数
+func (h *ArticleHandler) BackfillReadingStats(c *gin.Context) {
+	updated, err := h.articleService.BackfillReadingStats(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(gin.H{"updated": updated}))
+}
+
+// writeArticleUpdateError 更新文章的错误响应：版本冲突返回 409 并附带服务端当前文章，未提供版本号返回 428，内容命中敏感词返回 422，
+// 文章不存在返回 404，无权修改返回 403，其余返回 400
+func writeArticleUpdateError(c *gin.Context, err error) {
+	if writeTagsNotFoundError(c, err) || writeContentRejectedError(c, err) {
+		return
+	}
+	var conflict *services.ArticleVersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		setArticleETag(c, conflict.Current)
+		respondErrorWithData(c, http.StatusConflict, i18n.CodeArticleVersionConflict, conflict.Current)
+	case errors.Is(err, services.ErrArticleVersionRequired):
+		respondError(c, http.StatusPreconditionRequired, i18n.CodeArticleVersionRequired)
+	default:
+		respondServiceError(c, articleAccessErrorStatus(err, http.StatusBadRequest), err)
+	}
+}
+
+// articleAccessErrorStatus 文章或修订记录不存在返回 404，无权操作返回 403，唯一约束冲突（如 slug 重复）返回 409，其余返回 fallback
+func articleAccessErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, repository.ErrArticleNotFound), errors.Is(err, repository.ErrArticleRevisionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNotArticleAuthor):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
+
+// currentUser 当前登录用户的 ID 和角色
+// 返回: 未登录时 ok 为 false
+func currentUser(c *gin.Context) (uuid.UUID, models.UserRole, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		return uuid.Nil, "", false
+	}
+	userID, ok := v.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, "", false
+	}
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return userID, models.UserRole(roleStr), true
+}
+
+// writeTagsNotFoundError 关联的标签不存在时返回 400，message 和 data.missing_tag_ids 中列出缺失的标签 ID
+// 返回: 是否已写入响应
+func writeTagsNotFoundError(c *gin.Context, err error) bool {
+	var missing *services.TagsNotFoundError
+	if !errors.As(err, &missing) {
+		return false
+	}
+	ids := make([]string, len(missing.IDs))
+	for i, id := range missing.IDs {
+		ids[i] = id.String()
+	}
+	message := i18n.T(requestLanguage(c), i18n.CodeTagsNotFound) + ": " + strings.Join(ids, ", ")
+	resp := models.ErrorWithData(http.StatusBadRequest, message, gin.H{"missing_tag_ids": missing.IDs})
+	resp.ErrorCode = i18n.CodeTagsNotFound
+	c.JSON(http.StatusBadRequest, resp)
+	return true
+}
+
+// setArticleETag 以文章版本号作为 ETag，客户端更新时可通过 If-Match 回传
+func setArticleETag(c *gin.Context, article *models.Article) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(article.Version)))
+}
+
+// parseIfMatchVersion 解析 If-Match 请求头中的文章版本号，支持 "3"、W/"3" 和 3
+// 返回: 未提供请求头时返回 nil
+func parseIfMatchVersion(c *gin.Context) (*int, error) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" {
+		return nil, nil
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	version, err := strconv.Atoi(v)
+	if err != nil || version < 1 {
+		return nil, errors.New("invalid If-Match header, expected the article version")
+	}
+	return &version, nil
+}
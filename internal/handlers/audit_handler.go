@@ -0,0 +1,64 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"net/http"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditHandler 审计日志处理器（管理后台）
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler 创建新的审计日志处理器实例
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List 分页查询审计日志
+// GET /api/v1/admin/audit-logs?actor_id=&path_prefix=&method=&from=&to=&page=&page_size=
+func (h *AuditHandler) List(c *gin.Context) {
+	var query models.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageOther, query.Page, query.PageSize)
+
+	logs, total, err := h.auditService.List(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(logs, query.Page, query.PageSize, total))
+}
+
+// GetByID 获取审计日志详情（含脱敏后的请求体）
+// GET /api/v1/admin/audit-logs/:id
+func (h *AuditHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidAuditLogID)
+		return
+	}
+
+	log, err := h.auditService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(log))
+}
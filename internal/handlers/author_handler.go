@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuthorHandler 作者公开主页处理器（公开资料和已发布文章列表，不需要登录）
+type AuthorHandler struct {
+	userService    *services.UserService
+	articleService *services.ArticleService
+}
+
+// NewAuthorHandler 创建新的作者主页处理器实例
+func NewAuthorHandler(userService *services.UserService, articleService *services.ArticleService) *AuthorHandler {
+	return &AuthorHandler{userService: userService, articleService: articleService}
+}
+
+// Profile 作者公开资料
+// GET /api/v1/users/:id/public
+// 返回: {id, username, avatar, bio, article_count, joined_at}，不包含邮箱和手机号；用户不存在、已删除或未激活时返回 404
+func (h *AuthorHandler) Profile(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return
+	}
+
+	profile, err := h.userService.GetPublicProfile(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(profile))
+}
+
+// Articles 作者已发布的文章列表，分页、排序等参数与公开文章列表相同
+// GET /api/v1/users/:id/articles
+// 注意: 作者和状态固定为路径中的用户和 published，忽略请求中的 author_id、status；用户不存在、已删除或未激活时返回 404
+func (h *AuthorHandler) Articles(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return
+	}
+
+	var query models.ArticleQuery
+	if err := bindArticleQuery(c, &query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
+
+	if _, err := h.userService.GetPublicProfile(c.Request.Context(), id); err != nil {
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	query.AuthorID = &id
+	query.Status = models.StatusPublished
+	articles, total, err := h.articleService.List(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(articles, query.Page, query.PageSize, total))
+}
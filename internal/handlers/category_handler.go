@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/services"
 
@@ -23,40 +25,93 @@ func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHand
 func (h *CategoryHandler) Create(c *gin.Context) {
 	var req models.CategoryCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
-	category, err := h.categoryService.Create(&req)
+	category, err := h.categoryService.Create(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		writeCategoryWriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.Success(category))
+	respondCreated(c, "/admin/categories/"+category.ID.String(), category)
 }
 
+// List 公开分类列表，article_count 只统计已发布文章
 func (h *CategoryHandler) List(c *gin.Context) {
-	categories, err := h.categoryService.List()
+	categories, err := h.categoryService.List(c.Request.Context(), models.TaxonomyListQuery{PublishedOnly: true})
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(categories))
+}
+
+// Autocomplete 编辑器分类自动补全，按使用量排序
+// GET /api/v1/categories/autocomplete?q=tech&limit=10
+func (h *CategoryHandler) Autocomplete(c *gin.Context) {
+	var query models.TaxonomyAutocompleteQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	suggestions, err := h.categoryService.Autocomplete(c.Request.Context(), query.Q, query.Limit)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(suggestions))
+}
+
+// AdminList 管理后台分类列表，article_count 统计所有状态的文章
+// GET /api/v1/admin/categories?sort=article_count
+func (h *CategoryHandler) AdminList(c *gin.Context) {
+	var query models.TaxonomyListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	categories, err := h.categoryService.List(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Success(categories))
 }
 
+// Tree 分类树（嵌套 children，每个节点带已发布文章数）
+// GET /api/v1/categories/tree
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	tree, err := h.categoryService.Tree(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(tree))
+}
+
 // GetByID 获取分类详情（管理后台使用）
 func (h *CategoryHandler) GetByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid category id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCategoryID)
 		return
 	}
 
-	category, err := h.categoryService.GetByID(id)
+	category, err := h.categoryService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
 		return
 	}
 
@@ -67,38 +122,103 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 func (h *CategoryHandler) Update(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid category id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCategoryID)
 		return
 	}
 
 	var req models.CategoryUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
-	category, err := h.categoryService.Update(id, &req)
+	category, err := h.categoryService.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		writeCategoryWriteError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Success(category))
 }
 
-// Delete 删除分类（管理后台使用）
+// Delete 删除分类（管理后台使用，软删除）
+// DELETE /api/v1/admin/categories/:id?reassign_to=
+// 分类仍被文章或子分类引用时必须通过 reassign_to 指定新分类，否则返回 409 并附带引用计数
 func (h *CategoryHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid category id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCategoryID)
+		return
+	}
+
+	reassignTo, err := parseReassignTo(c)
+	if err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.categoryService.Delete(c.Request.Context(), id, reassignTo); err != nil {
+		var inUse *services.CategoryInUseError
+		if errors.As(err, &inUse) {
+			respondErrorWithData(c, http.StatusConflict, i18n.CodeCategoryInUse, inUse)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidReassignTarget) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
+		return
+	}
+
+	respondNoContent(c)
+}
+
+// parseReassignTo 解析可选的 reassign_to 查询参数（分类 / 标签删除时使用），格式错误时返回 services.ErrInvalidReassignTarget
+func parseReassignTo(c *gin.Context) (*uuid.UUID, error) {
+	v := c.Query("reassign_to")
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, services.ErrInvalidReassignTarget
+	}
+	return &id, nil
+}
+
+// Stats 内容统计（管理后台使用）
+// GET /api/v1/admin/stats/categories?sort_by=&order=
+func (h *CategoryHandler) Stats(c *gin.Context) {
+	var query models.ContentStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
 		return
 	}
 
-	if err := h.categoryService.Delete(id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	stats, err := h.categoryService.Stats(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	c.JSON(http.StatusOK, models.Success(stats))
 }
 
+// writeCategoryWriteError 新建 / 更新分类的错误响应：父分类不合法返回 422，slug 冲突返回 409，分类不存在返回 404，其余返回 400
+func writeCategoryWriteError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidParentCategory) {
+		respondServiceError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if errors.Is(err, services.ErrCategorySlugExists) {
+		respondServiceError(c, http.StatusConflict, err)
+		return
+	}
+	status := serviceErrorStatus(err, http.StatusBadRequest)
+	respondServiceError(c, status, err)
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"net/http"
 
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/services"
 
@@ -29,25 +31,49 @@ func (h *CommentHandler) Create(c *gin.Context) {
 
 	var req models.CommentCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
 	ip := c.ClientIP()
-	comment, err := h.commentService.Create(userID, ip, &req)
+	comment, err := h.commentService.Create(c.Request.Context(), userID, ip, &req)
+	if err != nil {
+		if writeContentRejectedError(c, err) {
+			return
+		}
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	respondCreated(c, "/comments/"+comment.ID.String(), comment)
+}
+
+// GetByID 获取单条评论（创建评论返回的 Location 指向这里）
+// GET /api/v1/comments/:id
+func (h *CommentHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCommentID)
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.Success(comment))
+	comment, err := h.commentService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(comment))
 }
 
 func (h *CommentHandler) GetByArticleID(c *gin.Context) {
 	// 路由为 /articles/:id/comments，这里从参数 id 读取文章 ID
 	articleID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid article id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
 		return
 	}
 
@@ -56,11 +82,33 @@ func (h *CommentHandler) GetByArticleID(c *gin.Context) {
 		PageSize int `form:"page_size"`
 	}
 
-	c.ShouldBindQuery(&query)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageComments, query.Page, query.PageSize)
+
+	comments, total, err := h.commentService.GetByArticleID(c.Request.Context(), articleID, query.Page, query.PageSize)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(comments, query.Page, query.PageSize, total))
+}
 
-	comments, total, err := h.commentService.GetByArticleID(articleID, query.Page, query.PageSize)
+// AdminList 管理后台评论列表，status 参数按状态筛选（仪表盘待处理队列的链接指向这里）
+func (h *CommentHandler) AdminList(c *gin.Context) {
+	var query models.CommentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageComments, query.Page, query.PageSize)
+
+	comments, total, err := h.commentService.List(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -70,19 +118,22 @@ func (h *CommentHandler) GetByArticleID(c *gin.Context) {
 func (h *CommentHandler) Update(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid comment id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCommentID)
 		return
 	}
 
 	var req models.CommentUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
-	comment, err := h.commentService.Update(id, &req)
+	comment, err := h.commentService.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondServiceError(c, serviceErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
@@ -92,15 +143,15 @@ func (h *CommentHandler) Update(c *gin.Context) {
 func (h *CommentHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid comment id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCommentID)
 		return
 	}
 
-	if err := h.commentService.Delete(id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := h.commentService.Delete(c.Request.Context(), id); err != nil {
+		respondServiceError(c, serviceErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	respondNoContent(c)
 }
 
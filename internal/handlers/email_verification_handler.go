@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationHandler 注册邮箱验证处理器
+type EmailVerificationHandler struct {
+	verificationService *services.EmailVerificationService
+}
+
+// NewEmailVerificationHandler 创建新的邮箱验证处理器实例
+func NewEmailVerificationHandler(verificationService *services.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{verificationService: verificationService}
+}
+
+// Verify 使用验证邮件中的令牌激活账号
+// POST /api/v1/auth/verify-email
+// 返回: 激活后的用户；令牌无效、已使用或已过期时返回 400（invalid_verification_token）
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	user, err := h.verificationService.Verify(c.Request.Context(), req.Token)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			status = http.StatusBadRequest
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(user))
+}
+
+// Resend 重新发送验证邮件
+// POST /api/v1/auth/resend-verification
+// 邮箱不存在或已验证时同样返回 200（不发送邮件），避免探测邮箱是否注册；1 分钟内重复请求返回 429
+func (h *EmailVerificationHandler) Resend(c *gin.Context) {
+	var req models.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	if err := h.verificationService.Resend(c.Request.Context(), req.Email); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrVerificationTooFrequent) {
+			status = http.StatusTooManyRequests
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(nil))
+}
@@ -0,0 +1,346 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/flags"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// serviceErrorCodes 服务层错误到错误码的映射，按 errors.Is 匹配
+var serviceErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{repository.ErrUserNotFound, i18n.CodeUserNotFound},
+	{repository.ErrArticleNotFound, i18n.CodeArticleNotFound},
+	{repository.ErrArticleRevisionNotFound, i18n.CodeRevisionNotFound},
+	{repository.ErrCategoryNotFound, i18n.CodeCategoryNotFound},
+	{services.ErrRegistrationClosed, i18n.CodeRegistrationClosed},
+	{services.ErrEmailExists, i18n.CodeEmailExists},
+	{services.ErrUsernameExists, i18n.CodeUsernameExists},
+	{services.ErrInvalidCredentials, i18n.CodeInvalidCredentials},
+	{services.ErrEmailNotVerified, i18n.CodeEmailNotVerified},
+	{services.ErrAccountInactive, i18n.CodeAccountInactive},
+	{services.ErrInvalidOldPassword, i18n.CodeInvalidOldPassword},
+	{services.ErrCannotImpersonateAdmin, i18n.CodeCannotImpersonateAdmin},
+	{services.ErrNotImpersonating, i18n.CodeNotImpersonating},
+	{services.ErrImpersonatorNotAdmin, i18n.CodeImpersonatorNotAdmin},
+	{services.ErrSMSTooFrequent, i18n.CodeSMSTooFrequent},
+	{services.ErrInvalidSMSCode, i18n.CodeSMSCodeInvalid},
+	{services.ErrInvalidRefreshToken, i18n.CodeInvalidRefreshToken},
+	{services.ErrLoginLocked, i18n.CodeLoginLocked},
+	{services.ErrInvalidVerificationToken, i18n.CodeInvalidVerificationToken},
+	{services.ErrVerificationTooFrequent, i18n.CodeVerificationTooFrequent},
+	{services.ErrInvalidResetToken, i18n.CodeInvalidResetToken},
+	{services.ErrArticleVersionRequired, i18n.CodeArticleVersionRequired},
+	{services.ErrNotArticleAuthor, i18n.CodeNotArticleAuthor},
+	{services.ErrScheduledAtRequired, i18n.CodeScheduledAtRequired},
+	{services.ErrRevisionDiffTooLarge, i18n.CodeRevisionDiffTooLarge},
+	{services.ErrInvalidPreviewToken, i18n.CodeInvalidPreviewToken},
+	{services.ErrPreviewTokenNotFound, i18n.CodePreviewTokenNotFound},
+	{services.ErrCategorySlugExists, i18n.CodeCategorySlugExists},
+	{services.ErrInvalidParentCategory, i18n.CodeInvalidParentCategory},
+	{services.ErrCategoryInUse, i18n.CodeCategoryInUse},
+	{repository.ErrTagNotFound, i18n.CodeTagNotFound},
+	{services.ErrTagSlugExists, i18n.CodeTagSlugExists},
+	{services.ErrTagNameExists, i18n.CodeTagNameExists},
+	{services.ErrTagInUse, i18n.CodeTagInUse},
+	{services.ErrInvalidMergeTarget, i18n.CodeInvalidMergeTarget},
+	{services.ErrInvalidReassignTarget, i18n.CodeInvalidReassignTarget},
+	{repository.ErrImageNotFound, i18n.CodeImageNotFound},
+	{services.ErrUnsupportedImageFormat, i18n.CodeUnsupportedImageFormat},
+	{services.ErrImageTooLarge, i18n.CodeImageTooLarge},
+	{services.ErrInvalidImage, i18n.CodeInvalidImage},
+	{services.ErrNotificationNotFound, i18n.CodeNotificationNotFound},
+	{services.ErrInvalidConfirmToken, i18n.CodeInvalidConfirmToken},
+	{services.ErrInvalidUnsubscribeLink, i18n.CodeInvalidUnsubscribeLink},
+	{services.ErrSubscriberNotFound, i18n.CodeSubscriberNotFound},
+	{services.ErrInvalidCampaign, i18n.CodeInvalidCampaign},
+	{services.ErrCampaignNotFound, i18n.CodeCampaignNotFound},
+	{services.ErrExportJobNotFound, i18n.CodeExportJobNotFound},
+	{services.ErrExportFileMissing, i18n.CodeExportJobNotFound},
+	{services.ErrExportJobNotReady, i18n.CodeExportJobNotReady},
+	{services.ErrReindexRunning, i18n.CodeReindexRunning},
+	{services.ErrReindexJobNotFound, i18n.CodeReindexJobNotFound},
+	{search.ErrSearchDisabled, i18n.CodeSearchDisabled},
+	{services.ErrInvalidWebhook, i18n.CodeInvalidWebhook},
+	{services.ErrWebhookNotFound, i18n.CodeWebhookNotFound},
+	{services.ErrWebhookDeliveryNotFound, i18n.CodeWebhookDeliveryNotFound},
+	{repository.ErrAuditLogNotFound, i18n.CodeAuditLogNotFound},
+	{services.ErrInvalidTrashType, i18n.CodeInvalidTrashType},
+	{services.ErrTrashItemNotFound, i18n.CodeTrashItemNotFound},
+	{services.ErrRestoreConflict, i18n.CodeRestoreConflict},
+	{services.ErrImageFileMissing, i18n.CodeImageFileMissing},
+	{services.ErrUnknownSetting, i18n.CodeUnknownSetting},
+	{services.ErrInvalidSettingValue, i18n.CodeInvalidSettingValue},
+	{flags.ErrUnknownFlag, i18n.CodeUnknownFlag},
+	{flags.ErrInvalidFlagValue, i18n.CodeInvalidFlagValue},
+	{services.ErrInvalidTopKind, i18n.CodeInvalidTopKind},
+	{services.ErrInvalidTopPeriod, i18n.CodeInvalidTopPeriod},
+	{services.ErrInvalidCacheScope, i18n.CodeInvalidCacheScope},
+	{services.ErrNoReportRecipients, i18n.CodeNoReportRecipients},
+	{services.ErrSitemapNotFound, i18n.CodeSitemapNotFound},
+	{database.ErrRedisUnavailable, i18n.CodeUnavailable},
+}
+
+// statusErrorCodes 未登记的错误按 HTTP 状态码使用的通用错误码
+var statusErrorCodes = map[int]string{
+	http.StatusUnauthorized:       i18n.CodeUnauthorized,
+	http.StatusForbidden:          i18n.CodeForbidden,
+	http.StatusNotFound:           i18n.CodeNotFound,
+	http.StatusConflict:           i18n.CodeConflict,
+	http.StatusTooManyRequests:    i18n.CodeTooManyRequests,
+	http.StatusServiceUnavailable: i18n.CodeUnavailable,
+}
+
+// bindingValidator gin 参数绑定（binding 标签）使用的校验器，首次使用时注册翻译
+var bindingValidator = sync.OnceValue(func() *i18n.Validator {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil
+	}
+	v, err := i18n.NewValidator(engine)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error().Err(err).Msg("Failed to register validator translations")
+		return nil
+	}
+	return v
+})
+
+// newValidator 创建处理器自己使用的校验器（validate 标签），注册失败时校验仍可用，只是错误信息不翻译
+func newValidator() *i18n.Validator {
+	v := validator.New()
+	translated, err := i18n.NewValidator(v)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error().Err(err).Msg("Failed to register validator translations")
+		translated = &i18n.Validator{Validate: v}
+	}
+	if err := registerValidationRules(translated); err != nil {
+		l := logger.GetLogger()
+		l.Error().Err(err).Msg("Failed to register validation rules")
+	}
+	return translated
+}
+
+// hexColorPattern 十六进制颜色：#RGB、#RGBA、#RRGGBB 或 #RRGGBBAA
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// registerValidationRules 注册请求体使用的自定义校验规则
+// - article_status: 文章状态必须是已定义的状态（draft、review、published、archived、scheduled）
+// - color: 空字符串（不设置 / 清除颜色）或十六进制颜色
+func registerValidationRules(v *i18n.Validator) error {
+	err := v.RegisterRule("article_status", func(fl validator.FieldLevel) bool {
+		return models.ArticleStatus(fl.Field().String()).Valid()
+	}, map[string]string{
+		i18n.EN:   "{0} must be one of draft, review, published, archived, scheduled",
+		i18n.ZhCN: "{0}必须是draft、review、published、archived、scheduled之一",
+	})
+	if err != nil {
+		return err
+	}
+	return v.RegisterRule("color", func(fl validator.FieldLevel) bool {
+		color := fl.Field().String()
+		return color == "" || hexColorPattern.MatchString(color)
+	}, map[string]string{
+		i18n.EN:   "{0} must be a hex color such as #00ADD8",
+		i18n.ZhCN: "{0}必须是十六进制颜色，如#00ADD8",
+	})
+}
+
+// requestValidator 处理器共用的校验器，用于文章、分类、标签和评论的请求体
+var requestValidator = sync.OnceValue(newValidator)
+
+// validateRequest 按 validate 标签校验已绑定的请求体，失败时返回 400（见 respondValidationError）
+// 返回: 校验是否通过
+func validateRequest(c *gin.Context, req interface{}) bool {
+	v := requestValidator()
+	if err := v.Struct(req); err != nil {
+		respondValidationError(c, v, err)
+		return false
+	}
+	return true
+}
+
+// requestLanguage 当前请求的响应语言，与中间件的错误响应一致（见 middleware.RequestLanguage）
+func requestLanguage(c *gin.Context) string {
+	return middleware.RequestLanguage(c)
+}
+
+// respondError 按错误码返回本地化的错误响应
+// status: HTTP 状态码（同时作为响应体中的 code），code: i18n 错误码
+func respondError(c *gin.Context, status int, code string) {
+	c.JSON(status, models.ErrorWithCode(status, code, i18n.T(requestLanguage(c), code)))
+}
+
+// respondErrorWithData 按错误码返回本地化的错误响应，附带便于客户端处理的数据
+func respondErrorWithData(c *gin.Context, status int, code string, data interface{}) {
+	resp := models.ErrorWithData(status, i18n.T(requestLanguage(c), code), data)
+	resp.ErrorCode = code
+	c.JSON(status, resp)
+}
+
+// respondServiceError 服务层错误响应，message 总是本地化的
+// 错误码: 已在 serviceErrorCodes 中登记的错误使用对应的错误码，其余按状态码使用通用错误码（见 statusErrorCodes，400 为 invalid_request）
+// 注意: 5xx 只记录日志，不返回原始错误信息（错误码为 internal_error 或 service_unavailable）；
+// 4xx 的错误带有附加说明（如包装了具体字段）时，原始错误信息放在 data.detail 中，便于排查
+func respondServiceError(c *gin.Context, status int, err error) {
+	code, detail := "", err.Error()
+	for _, m := range serviceErrorCodes {
+		if errors.Is(err, m.err) {
+			code = m.code
+			if detail == m.err.Error() {
+				detail = ""
+			}
+			break
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		l := logger.FromContext(c.Request.Context())
+		l.Error().Err(err).Int("status", status).Msg("request failed")
+		if code == "" || status == http.StatusInternalServerError {
+			code = statusErrorCodes[status]
+		}
+		if code == "" {
+			code = i18n.CodeInternal
+		}
+		respondError(c, status, code)
+		return
+	}
+	if code == "" {
+		if code = statusErrorCodes[status]; code == "" {
+			code = i18n.CodeInvalidRequest
+		}
+	}
+	if detail == "" {
+		respondError(c, status, code)
+		return
+	}
+	respondErrorWithData(c, status, code, gin.H{"detail": detail})
+}
+
+// serviceErrorStatus 按仓储层的错误类型选择 HTTP 状态码
+// 资源不存在（repository.ErrNotFound）返回 404，唯一约束冲突（repository.ErrConflict）返回 409，其余返回 fallback
+// 注意: 只用于错误来自请求路径中的资源时；写操作中引用的其他资源不存在属于参数错误，不应使用
+func serviceErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
+
+// conflictErrorStatus 写操作的状态码：唯一约束冲突返回 409，其余返回 fallback
+func conflictErrorStatus(err error, fallback int) int {
+	if errors.Is(err, repository.ErrConflict) {
+		return http.StatusConflict
+	}
+	return fallback
+}
+
+// queryParamError 查询参数格式错误（由 handlers 单独解析的参数，如时间范围）
+// respondValidationError 将其作为该字段的校验错误返回
+type queryParamError struct {
+	Field string
+	Rule  string
+	Err   error
+}
+
+func (e *queryParamError) Error() string {
+	return "invalid " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *queryParamError) Unwrap() error {
+	return e.Err
+}
+
+// respondBindError ShouldBindJSON / ShouldBindQuery 失败的响应，见 respondValidationError
+func respondBindError(c *gin.Context, err error) {
+	respondValidationError(c, bindingValidator(), err)
+}
+
+// respondValidationError 参数错误响应（400）
+// v: 产生该错误的校验器；校验错误和字段类型错误的错误码为 validation_failed，其余（如 JSON 格式错误）为 invalid_request
+// 校验错误逐字段翻译，message 为各字段错误以 "; " 连接（兼容旧客户端），data.errors 为逐字段的错误列表：
+// {"errors": [{"field": "email", "rule": "email", "message": "email must be a valid email address"}]}
+func respondValidationError(c *gin.Context, v *i18n.Validator, err error) {
+	lang := requestLanguage(c)
+	fieldErrs, ok := v.FieldErrors(lang, err)
+	var paramErr *queryParamError
+	if !ok && errors.As(err, &paramErr) {
+		fieldErrs, ok = []i18n.FieldError{{
+			Field:   paramErr.Field,
+			Rule:    paramErr.Rule,
+			Message: paramErr.Field + " " + i18n.T(lang, i18n.CodeInvalidDateTime),
+		}}, true
+	}
+	if !ok {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || typeErr.Field == "" {
+			respondError(c, http.StatusBadRequest, i18n.CodeInvalidRequest)
+			return
+		}
+		fieldErrs = []i18n.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: typeErr.Field + " " + i18n.T(lang, i18n.CodeInvalidFieldType),
+		}}
+	}
+
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		messages = append(messages, fe.Message)
+	}
+	resp := models.ErrorWithData(http.StatusBadRequest, strings.Join(messages, "; "), gin.H{"errors": fieldErrs})
+	resp.ErrorCode = i18n.CodeValidationFailed
+	c.JSON(http.StatusBadRequest, resp)
+}
+
+// writeContentRejectedError 内容命中敏感词被拒绝时返回 422，data 中列出命中的分类
+// 返回: 是否已写入响应
+func writeContentRejectedError(c *gin.Context, err error) bool {
+	var rejected *services.ContentRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	respondErrorWithData(c, http.StatusUnprocessableEntity, i18n.CodeContentRejected, gin.H{"categories": rejected.Categories})
+	return true
+}
+
+// writeLoginLockedError 登录失败次数过多被锁定时返回 429，Retry-After 头和 data.retry_after 为需要等待的秒数
+// 返回: 是否已写入响应
+func writeLoginLockedError(c *gin.Context, err error) bool {
+	var locked *services.LoginLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	// 向上取整，至少 1 秒
+	seconds := int64(math.Ceil(math.Max(locked.RetryAfter.Seconds(), 1)))
+	c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+	respondErrorWithData(c, http.StatusTooManyRequests, i18n.CodeLoginLocked, gin.H{"retry_after": seconds})
+	return true
+}
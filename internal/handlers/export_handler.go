@@ -0,0 +1,158 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportHandler 管理后台数据导出处理器
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler 创建新的导出处理器实例
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportUsers 导出用户 CSV
+// GET /api/v1/admin/export/users.csv
+// 支持与用户列表相同的筛选条件：role、status、created_from、created_to（YYYY-MM-DD）
+// 数据量超过上限时返回 202 和异步任务信息
+func (h *ExportHandler) ExportUsers(c *gin.Context) {
+	var query models.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	total, err := h.exportService.CountUsers(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if total > int64(h.exportService.MaxRows()) {
+		job, err := h.exportService.StartUsersJob(c.Request.Context(), query)
+		if err != nil {
+			respondExportJobError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, models.Success(job))
+		return
+	}
+
+	h.streamCSV(c, services.ExportKindUsers, func() (int64, error) {
+		return h.exportService.WriteUsersCSV(c.Request.Context(), c.Writer, query, int64(h.exportService.MaxRows()))
+	})
+}
+
+// ExportArticles 导出文章 CSV
+// GET /api/v1/admin/export/articles.csv
+// 支持与文章列表相同的筛选条件：status、category_id、tag_id、author_id、created_from、created_to、published_from、published_to（RFC3339 或 YYYY-MM-DD）
+// 数据量超过上限时返回 202 和异步任务信息
+func (h *ExportHandler) ExportArticles(c *gin.Context) {
+	var query models.ArticleQuery
+	if err := bindArticleQuery(c, &query); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	total, err := h.exportService.CountArticles(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if total > int64(h.exportService.MaxRows()) {
+		job, err := h.exportService.StartArticlesJob(c.Request.Context(), query)
+		if err != nil {
+			respondExportJobError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, models.Success(job))
+		return
+	}
+
+	h.streamCSV(c, services.ExportKindArticles, func() (int64, error) {
+		return h.exportService.WriteArticlesCSV(c.Request.Context(), c.Writer, query, int64(h.exportService.MaxRows()))
+	})
+}
+
+// GetJob 查询异步导出任务状态
+// GET /api/v1/admin/export/jobs/:id
+func (h *ExportHandler) GetJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidJobID)
+		return
+	}
+
+	job, err := h.exportService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		respondExportJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(job))
+}
+
+// DownloadJob 下载已完成的异步导出文件
+// GET /api/v1/admin/export/jobs/:id/download
+func (h *ExportHandler) DownloadJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidJobID)
+		return
+	}
+
+	job, path, err := h.exportService.JobFile(c.Request.Context(), id)
+	if err != nil {
+		respondExportJobError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, job.FileName)
+}
+
+// respondExportJobError 将异步导出任务的错误转换为响应
+func respondExportJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrExportJobNotFound), errors.Is(err, services.ErrExportFileMissing):
+		respondServiceError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrExportJobNotReady):
+		respondServiceError(c, http.StatusConflict, err)
+	case errors.Is(err, database.ErrRedisUnavailable):
+		respondServiceError(c, http.StatusServiceUnavailable, err)
+	default:
+		respondServiceError(c, http.StatusInternalServerError, err)
+	}
+}
+
+// streamCSV 设置下载响应头并流式写出 CSV
+func (h *ExportHandler) streamCSV(c *gin.Context, kind string, write func() (int64, error)) {
+	filename := services.ExportFileName(kind, time.Now())
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// 响应头已发送，中途出错只能记录日志
+	if rows, err := write(); err != nil {
+		l := logger.FromContext(c.Request.Context())
+		l.Error().Err(err).Str("kind", kind).Int64("rows", rows).Msg("CSV export interrupted")
+	}
+}
@@ -0,0 +1,61 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"enterprise-blog/internal/flags"
+	"enterprise-blog/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FlagHandler 功能开关处理器（管理后台）
+type FlagHandler struct {
+	flagService *flags.Service
+}
+
+// NewFlagHandler 创建新的功能开关处理器实例
+func NewFlagHandler(flagService *flags.Service) *FlagHandler {
+	return &FlagHandler{
+		flagService: flagService,
+	}
+}
+
+// List 获取全部功能开关及当前生效的状态
+// GET /api/v1/admin/flags
+func (h *FlagHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Success(h.flagService.List()))
+}
+
+// Update 批量修改功能开关
+// PUT /api/v1/admin/flags
+// 请求体: {"flags": {"new_search": {"enabled": true, "rollout_percent": 20}}}
+func (h *FlagHandler) Update(c *gin.Context) {
+	var req models.FeatureFlagsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var updatedBy *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok := v.(uuid.UUID); ok {
+			updatedBy = &id
+		}
+	}
+
+	result, err := h.flagService.Update(c.Request.Context(), req.Flags, updatedBy)
+	if err != nil {
+		if errors.Is(err, flags.ErrUnknownFlag) || errors.Is(err, flags.ErrInvalidFlagValue) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(result))
+}
@@ -0,0 +1,48 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"net/http"
+
+	"enterprise-blog/internal/graph"
+	"enterprise-blog/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GraphQLHandler GraphQL 只读接口处理器
+type GraphQLHandler struct {
+	server *graph.Server
+}
+
+// NewGraphQLHandler 创建新的 GraphQL 处理器实例
+func NewGraphQLHandler(server *graph.Server) *GraphQLHandler {
+	return &GraphQLHandler{
+		server: server,
+	}
+}
+
+// Query 执行 GraphQL 查询
+// POST /api/v1/graphql
+// 请求体: {"query": "...", "operationName": "...", "variables": {...}}
+// 注意: 认证可选，调用者身份由可选认证中间件写入；响应为标准 GraphQL 格式（data / errors），不使用统一响应结构
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graph.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "request body must be JSON with a query"}}})
+		return
+	}
+
+	var viewer graph.Viewer
+	if v, ok := c.Get("user_id"); ok {
+		viewer.UserID, _ = v.(uuid.UUID)
+	}
+	if v, ok := c.Get("role"); ok {
+		role, _ := v.(string)
+		viewer.Role = models.UserRole(role)
+	}
+
+	ctx := graph.WithViewer(c.Request.Context(), viewer)
+	c.JSON(http.StatusOK, h.server.Exec(ctx, &req))
+}
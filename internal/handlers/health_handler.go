@@ -0,0 +1,42 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"net/http"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 存活和就绪检查接口处理器（供负载均衡和容器编排使用，不需要认证）
+type HealthHandler struct{}
+
+// NewHealthHandler 创建新的健康检查处理器实例
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+// Live 存活检查：进程能处理请求即返回 200，不访问任何依赖
+// GET /health/live
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": models.HealthOK})
+}
+
+// Ready 就绪检查：返回数据库、Redis、Elasticsearch 的状态，有必需依赖不可用时返回 503
+// GET /health、GET /readyz
+// 注意: 每次请求读取当前配置，health 配置热加载后立即生效
+func (h *HealthHandler) Ready(c *gin.Context) {
+	var cfg config.HealthConfig
+	if current := config.Get(); current != nil {
+		cfg = current.Health
+	}
+	report := services.CheckHealth(c.Request.Context(), cfg)
+	status := http.StatusOK
+	if report.Status == models.HealthUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
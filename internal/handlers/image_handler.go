@@ -2,11 +2,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/services"
 
@@ -37,14 +40,14 @@ func NewImageHandler(imageService *services.ImageService) *ImageHandler {
 func (h *ImageHandler) Upload(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
 	// 获取上传的文件
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "file is required"))
+		respondError(c, http.StatusBadRequest, i18n.CodeImageFileRequired)
 		return
 	}
 
@@ -62,11 +65,16 @@ func (h *ImageHandler) Upload(c *gin.Context) {
 	// 上传图片
 	image, err := h.imageService.Upload(c.Request.Context(), userID.(uuid.UUID), file, description, tags)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUnsupportedImageFormat) || errors.Is(err, services.ErrImageTooLarge) ||
+			errors.Is(err, services.ErrInvalidImage) {
+			status = http.StatusBadRequest
+		}
+		respondServiceError(c, status, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.Success(image))
+	respondCreated(c, "/images/"+image.ID.String(), image)
 }
 
 // GetByID 根据ID获取图片详情
@@ -74,13 +82,14 @@ func (h *ImageHandler) Upload(c *gin.Context) {
 func (h *ImageHandler) GetByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid image id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidImageID)
 		return
 	}
 
 	image, err := h.imageService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
 		return
 	}
 
@@ -92,13 +101,14 @@ func (h *ImageHandler) GetByID(c *gin.Context) {
 func (h *ImageHandler) List(c *gin.Context) {
 	var query models.ImageQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageImages, query.Page, query.PageSize)
 
 	images, total, err := h.imageService.List(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -111,21 +121,22 @@ func (h *ImageHandler) List(c *gin.Context) {
 func (h *ImageHandler) Update(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid image id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidImageID)
 		return
 	}
 
 	// 检查权限：只能更新自己上传的图片
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
 	// 验证图片所有权
 	image, err := h.imageService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
 		return
 	}
 
@@ -133,20 +144,21 @@ func (h *ImageHandler) Update(c *gin.Context) {
 	if roleVal, ok := c.Get("role"); ok {
 		roleStr, _ := roleVal.(string)
 		if roleStr != string(models.RoleAdmin) && image.UploaderID != userID.(uuid.UUID) {
-			c.JSON(http.StatusForbidden, models.Error(403, "forbidden: can only update your own images"))
+			respondError(c, http.StatusForbidden, i18n.CodeNotImageOwner)
 			return
 		}
 	}
 
 	var req models.ImageUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
 	updated, err := h.imageService.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		status := serviceErrorStatus(err, http.StatusBadRequest)
+		respondServiceError(c, status, err)
 		return
 	}
 
@@ -159,21 +171,22 @@ func (h *ImageHandler) Update(c *gin.Context) {
 func (h *ImageHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid image id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidImageID)
 		return
 	}
 
 	// 检查权限：只能删除自己上传的图片
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
 	// 验证图片所有权
 	image, err := h.imageService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
 		return
 	}
 
@@ -181,17 +194,18 @@ func (h *ImageHandler) Delete(c *gin.Context) {
 	if roleVal, ok := c.Get("role"); ok {
 		roleStr, _ := roleVal.(string)
 		if roleStr != string(models.RoleAdmin) && image.UploaderID != userID.(uuid.UUID) {
-			c.JSON(http.StatusForbidden, models.Error(403, "forbidden: can only delete your own images"))
+			respondError(c, http.StatusForbidden, i18n.CodeNotImageOwner)
 			return
 		}
 	}
 
 	if err := h.imageService.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		status := serviceErrorStatus(err, http.StatusBadRequest)
+		respondServiceError(c, status, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	respondNoContent(c)
 }
 
 // ServeImage 提供图片文件服务
@@ -202,7 +216,7 @@ func (h *ImageHandler) ServeImage(c *gin.Context) {
 	
 	// 安全检查：防止路径遍历攻击
 	if strings.Contains(filename, "..") || strings.Contains(filename, "/") {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid filename"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidFilename)
 		return
 	}
 
@@ -211,7 +225,7 @@ func (h *ImageHandler) ServeImage(c *gin.Context) {
 	
 	// 检查文件是否存在
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, models.Error(404, "image not found"))
+		respondError(c, http.StatusNotFound, i18n.CodeImageNotFound)
 		return
 	}
 
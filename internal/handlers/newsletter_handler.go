@@ -0,0 +1,164 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NewsletterHandler 邮件订阅处理器
+type NewsletterHandler struct {
+	newsletterService *services.NewsletterService
+}
+
+// NewNewsletterHandler 创建新的邮件订阅处理器实例
+func NewNewsletterHandler(newsletterService *services.NewsletterService) *NewsletterHandler {
+	return &NewsletterHandler{
+		newsletterService: newsletterService,
+	}
+}
+
+// Subscribe 提交订阅，发送确认邮件
+// POST /api/v1/newsletter/subscribe
+// 请求体: {"email": "reader@example.com"}
+// 注意: 无论邮箱是否已订阅都返回相同的结果，不返回订阅者信息
+func (h *NewsletterHandler) Subscribe(c *gin.Context) {
+	var req models.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.newsletterService.Subscribe(c.Request.Context(), req.Email, c.ClientIP()); err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.SuccessWithMessage("please check your inbox to confirm the subscription", nil))
+}
+
+// Confirm 确认订阅
+// GET /api/v1/newsletter/confirm?token=
+func (h *NewsletterHandler) Confirm(c *gin.Context) {
+	if err := h.newsletterService.Confirm(c.Request.Context(), c.Query("token")); err != nil {
+		respondNewsletterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessWithMessage("subscription confirmed", nil))
+}
+
+// Unsubscribe 通过邮件中的签名链接退订
+// GET /api/v1/newsletter/unsubscribe?id=&sig=
+// POST /api/v1/newsletter/unsubscribe?id=&sig=（RFC 8058 一键退订）
+func (h *NewsletterHandler) Unsubscribe(c *gin.Context) {
+	id, err := uuid.Parse(c.Query("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidUnsubscribeLink)
+		return
+	}
+
+	if err := h.newsletterService.Unsubscribe(c.Request.Context(), id, c.Query("sig")); err != nil {
+		respondNewsletterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessWithMessage("unsubscribed", nil))
+}
+
+// ListSubscribers 分页获取订阅者（含邮箱）
+// GET /api/v1/admin/newsletter/subscribers?status=&page=&page_size=
+func (h *NewsletterHandler) ListSubscribers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+
+	subscribers, total, err := h.newsletterService.ListSubscribers(c.Request.Context(), c.Query("status"), page, pageSize)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(subscribers, page, pageSize, total))
+}
+
+// CreateCampaign 创建群发并在后台发送
+// POST /api/v1/admin/newsletter/campaigns
+// 请求体: {"kind": "article", "article_id": "..."} 或 {"kind": "digest", "digest_days": 7}，可选 subject
+func (h *NewsletterHandler) CreateCampaign(c *gin.Context) {
+	var req models.CampaignCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok := v.(uuid.UUID); ok {
+			createdBy = &id
+		}
+	}
+
+	campaign, err := h.newsletterService.CreateCampaign(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		respondNewsletterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.Success(campaign))
+}
+
+// ListCampaigns 分页获取群发记录
+// GET /api/v1/admin/newsletter/campaigns?page=&page_size=
+func (h *NewsletterHandler) ListCampaigns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+
+	campaigns, total, err := h.newsletterService.ListCampaigns(c.Request.Context(), page, pageSize)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(campaigns, page, pageSize, total))
+}
+
+// GetCampaign 获取群发记录和发送进度
+// GET /api/v1/admin/newsletter/campaigns/:id
+func (h *NewsletterHandler) GetCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidCampaignID)
+		return
+	}
+
+	campaign, err := h.newsletterService.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		respondNewsletterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(campaign))
+}
+
+func respondNewsletterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCampaign), errors.Is(err, services.ErrInvalidConfirmToken),
+		errors.Is(err, services.ErrInvalidUnsubscribeLink):
+		respondServiceError(c, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrSubscriberNotFound), errors.Is(err, services.ErrCampaignNotFound):
+		respondServiceError(c, http.StatusNotFound, err)
+	default:
+		respondServiceError(c, http.StatusInternalServerError, err)
+	}
+}
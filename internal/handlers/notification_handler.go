@@ -0,0 +1,114 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler 站内通知处理器
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler 创建新的站内通知处理器实例
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List 分页获取当前用户的通知（最新的在前）
+// GET /api/v1/users/notifications?unread=true&page=&page_size=
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, total, err := h.notificationService.List(c.Request.Context(), userID.(uuid.UUID), unreadOnly, page, pageSize)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(notifications, page, pageSize, total))
+}
+
+// UnreadCount 获取当前用户的未读通知数
+// GET /api/v1/users/notifications/unread-count
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	n, err := h.notificationService.UnreadCount(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(gin.H{"unread_count": n}))
+}
+
+// MarkRead 将一条通知标记为已读
+// POST /api/v1/users/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidNotificationID)
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), userID.(uuid.UUID), id); err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessWithMessage("notification marked as read", nil))
+}
+
+// MarkAllRead 将当前用户的全部通知标记为已读
+// POST /api/v1/users/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	n, err := h.notificationService.MarkAllRead(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(gin.H{"marked": n}))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordResetHandler 找回密码处理器
+type PasswordResetHandler struct {
+	resetService *services.PasswordResetService
+}
+
+// NewPasswordResetHandler 创建新的找回密码处理器实例
+func NewPasswordResetHandler(resetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{resetService: resetService}
+}
+
+// Forgot 申请找回密码，向邮箱发送重置链接
+// POST /api/v1/auth/forgot-password
+// 无论邮箱是否存在、是否超过频率限制都返回相同的 200 响应，避免探测邮箱是否注册
+func (h *PasswordResetHandler) Forgot(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	if err := h.resetService.RequestReset(c.Request.Context(), req.Email); err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(nil))
+}
+
+// Reset 使用重置链接中的令牌设置新密码，成功后已登录的会话（刷新 token）全部失效
+// POST /api/v1/auth/reset-password
+// 令牌无效、已使用或已过期时返回 400（invalid_reset_token）
+func (h *PasswordResetHandler) Reset(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	if err := h.resetService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			status = http.StatusBadRequest
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(nil))
+}
@@ -0,0 +1,120 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PreviewHandler 草稿预览链接处理器
+type PreviewHandler struct {
+	previewService *services.PreviewService
+}
+
+// NewPreviewHandler 创建新的草稿预览处理器实例
+func NewPreviewHandler(previewService *services.PreviewService) *PreviewHandler {
+	return &PreviewHandler{previewService: previewService}
+}
+
+// CreateToken 为文章生成预览 token（作者本人和管理员）
+// POST /api/v1/articles/:id/preview-token
+// 请求体可选，expires_in 为有效期（秒），缺省 24 小时
+func (h *PreviewHandler) CreateToken(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	var req models.ArticlePreviewTokenRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	token, err := h.previewService.Issue(c.Request.Context(), id, userID, role == models.RoleAdmin, time.Duration(req.ExpiresIn)*time.Second)
+	if err != nil {
+		respondServiceError(c, previewErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Created(token))
+}
+
+// RevokeToken 吊销文章的预览 token（作者本人和管理员）
+// DELETE /api/v1/articles/:id/preview-token/:jti
+func (h *PreviewHandler) RevokeToken(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidArticleID)
+		return
+	}
+	jti, err := uuid.Parse(c.Param("jti"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, i18n.CodePreviewTokenNotFound)
+		return
+	}
+	userID, role, ok := currentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	if err := h.previewService.Revoke(c.Request.Context(), id, jti, userID, role == models.RoleAdmin); err != nil {
+		respondServiceError(c, previewErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	respondNoContent(c)
+}
+
+// Preview 按预览 token 查看文章（公开，不论文章状态）
+// GET /api/v1/articles/preview?token=...
+// 注意: token 无效、已过期或已吊销时返回 401；响应禁止缓存，避免草稿内容被共享缓存保存
+func (h *PreviewHandler) Preview(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondError(c, http.StatusUnauthorized, i18n.CodeInvalidPreviewToken)
+		return
+	}
+
+	article, err := h.previewService.Preview(c.Request.Context(), token)
+	if err != nil {
+		respondServiceError(c, previewErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.JSON(http.StatusOK, models.Success(article))
+}
+
+// previewErrorStatus 预览相关错误对应的 HTTP 状态码，其余错误同 articleAccessErrorStatus
+func previewErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidPreviewToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrPreviewTokenNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, database.ErrRedisUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return articleAccessErrorStatus(err, fallback)
+	}
+}
@@ -0,0 +1,53 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler 定时报表处理器（管理后台）
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+// NewReportHandler 创建新的报表处理器实例
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// SendWeeklyTestRequest 周报测试发送参数
+type SendWeeklyTestRequest struct {
+	Recipients []string `json:"recipients" binding:"omitempty,dive,email"` // 为空时发送给配置的收件人
+}
+
+// SendWeeklyTest 立即生成并发送一次周报，用于验证邮件配置
+// POST /api/v1/admin/reports/weekly/send-test
+func (h *ReportHandler) SendWeeklyTest(c *gin.Context) {
+	var req SendWeeklyTestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	report, err := h.reportService.SendWeekly(c.Request.Context(), req.Recipients)
+	if err != nil {
+		if errors.Is(err, services.ErrNoReportRecipients) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(report))
+}
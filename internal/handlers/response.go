@@ -0,0 +1,25 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"net/http"
+
+	"enterprise-blog/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiPrefix REST 接口的路径前缀，用于生成 Location 头
+const apiPrefix = "/api/v1"
+
+// respondCreated 资源创建成功的响应：201、指向新资源的 Location 头，响应体为新资源
+// location: 新资源的路径（不含 apiPrefix），客户端可以直接对其发起 GET
+func respondCreated(c *gin.Context, location string, data interface{}) {
+	c.Header("Location", apiPrefix+location)
+	c.JSON(http.StatusCreated, models.Created(data))
+}
+
+// respondNoContent 删除成功的响应：204，无响应体
+func respondNoContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
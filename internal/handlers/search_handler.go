@@ -0,0 +1,88 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SearchHandler 搜索索引管理处理器（管理后台）
+type SearchHandler struct {
+	reindexService *services.ReindexService
+}
+
+// NewSearchHandler 创建新的搜索索引管理处理器实例
+func NewSearchHandler(reindexService *services.ReindexService) *SearchHandler {
+	return &SearchHandler{
+		reindexService: reindexService,
+	}
+}
+
+// Reindex 异步重建 Elasticsearch 索引
+// POST /api/v1/admin/search/reindex?since=2024-01-02T15:04:05Z
+// since 可选（RFC3339 或 YYYY-MM-DD），只重建该时间之后更新过的文章
+func (h *SearchHandler) Reindex(c *gin.Context) {
+	var since *time.Time
+	if v := c.Query("since"); v != "" {
+		t, err := parseSinceParam(v)
+		if err != nil {
+			respondBindError(c, &queryParamError{Field: "since", Rule: "datetime", Err: err})
+			return
+		}
+		since = &t
+	}
+
+	job, err := h.reindexService.Start(c.Request.Context(), since)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrReindexRunning):
+			respondServiceError(c, http.StatusConflict, err)
+		case errors.Is(err, search.ErrSearchDisabled), errors.Is(err, database.ErrRedisUnavailable):
+			respondServiceError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.Success(job))
+}
+
+// ReindexStatus 查询索引重建任务进度
+// GET /api/v1/admin/search/reindex/:job
+func (h *SearchHandler) ReindexStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("job"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidJobID)
+		return
+	}
+
+	job, err := h.reindexService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrReindexJobNotFound) {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(job))
+}
+
+func parseSinceParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
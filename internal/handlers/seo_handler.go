@@ -0,0 +1,75 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SEOHandler 搜索引擎相关的公开接口处理器
+type SEOHandler struct {
+	sitemapService *services.SitemapService
+}
+
+// NewSEOHandler 创建新的 SEO 处理器实例
+func NewSEOHandler(sitemapService *services.SitemapService) *SEOHandler {
+	return &SEOHandler{sitemapService: sitemapService}
+}
+
+// Robots 根据配置生成 robots.txt
+// GET /robots.txt
+// 注意: 非 release 模式或开启 seo.disallow_all 时禁止抓取全部路径，避免测试环境被收录；
+// 每次请求读取当前配置，seo 配置热加载后立即生效
+func (h *SEOHandler) Robots(c *gin.Context) {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+
+	cfg := config.Get()
+	if cfg == nil || cfg.Server.Mode != "release" || cfg.SEO.DisallowAll {
+		b.WriteString("Disallow: /\n")
+	} else {
+		b.WriteString("Allow: /\n")
+		b.WriteString("\nSitemap: " + strings.TrimRight(cfg.Server.PublicURL, "/") + "/sitemap.xml\n")
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(b.String()))
+}
+
+// Sitemap 站点地图，地址数超过 50000 时返回站点地图索引
+// GET /sitemap.xml
+func (h *SEOHandler) Sitemap(c *gin.Context) {
+	h.writeSitemap(c, 0)
+}
+
+// SitemapPage 站点地图索引中的分页
+// GET /sitemaps/:file（如 /sitemaps/1.xml）
+func (h *SEOHandler) SitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("file"), ".xml"))
+	if err != nil || page < 1 || !strings.HasSuffix(c.Param("file"), ".xml") {
+		respondError(c, http.StatusNotFound, i18n.CodeSitemapNotFound)
+		return
+	}
+	h.writeSitemap(c, page)
+}
+
+func (h *SEOHandler) writeSitemap(c *gin.Context, page int) {
+	data, err := h.sitemapService.Sitemap(c.Request.Context(), page)
+	if err != nil {
+		if errors.Is(err, services.ErrSitemapNotFound) {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
@@ -0,0 +1,97 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SettingsHandler 系统设置处理器（管理后台）
+type SettingsHandler struct {
+	settingsService *services.SettingsService
+}
+
+// NewSettingsHandler 创建新的系统设置处理器实例
+func NewSettingsHandler(settingsService *services.SettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+// List 获取全部系统设置
+// GET /api/v1/admin/settings
+func (h *SettingsHandler) List(c *gin.Context) {
+	settings, err := h.settingsService.List(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(settings))
+}
+
+// Update 批量修改系统设置
+// PUT /api/v1/admin/settings
+// 请求体: {"settings": {"comment_moderation": false, "excerpt_length": 300}}
+func (h *SettingsHandler) Update(c *gin.Context) {
+	var req models.SettingsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var updatedBy *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok := v.(uuid.UUID); ok {
+			updatedBy = &id
+		}
+	}
+
+	settings, err := h.settingsService.Update(c.Request.Context(), req.Settings, updatedBy)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownSetting) || errors.Is(err, services.ErrInvalidSettingValue) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(settings))
+}
+
+// SetMaintenance 开启或关闭维护模式
+// POST /api/v1/admin/system/maintenance
+// 请求体: {"enabled": true, "message": "数据库升级中", "retry_after": 600}
+func (h *SettingsHandler) SetMaintenance(c *gin.Context) {
+	var req models.MaintenanceUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var updatedBy *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok := v.(uuid.UUID); ok {
+			updatedBy = &id
+		}
+	}
+
+	status, err := h.settingsService.SetMaintenance(c.Request.Context(), &req, updatedBy)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSettingValue) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(status))
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/services"
 
@@ -23,23 +25,79 @@ func NewTagHandler(tagService *services.TagService) *TagHandler {
 func (h *TagHandler) Create(c *gin.Context) {
 	var req models.TagCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
-	tag, err := h.tagService.Create(&req)
+	tag, err := h.tagService.Create(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		writeTagWriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.Success(tag))
+	respondCreated(c, "/admin/tags/"+tag.ID.String(), tag)
 }
 
+// List 公开标签列表，article_count 只统计已发布文章
 func (h *TagHandler) List(c *gin.Context) {
-	tags, err := h.tagService.List()
+	tags, err := h.tagService.List(c.Request.Context(), models.TaxonomyListQuery{PublishedOnly: true})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(tags))
+}
+
+// Autocomplete 编辑器标签自动补全，按使用量排序
+// GET /api/v1/tags/autocomplete?q=go&limit=10&create_if_missing=true
+// create_if_missing 仅对编辑和管理员开放（需登录），没有同名标签时新建
+func (h *TagHandler) Autocomplete(c *gin.Context) {
+	var query models.TaxonomyAutocompleteQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if query.CreateIfMissing {
+		role, exists := c.Get("role")
+		if !exists {
+			respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+			return
+		}
+		if role != string(models.RoleAdmin) && role != string(models.RoleEditor) {
+			respondError(c, http.StatusForbidden, i18n.CodeForbidden)
+			return
+		}
+	}
+
+	suggestions, err := h.tagService.Autocomplete(c.Request.Context(), query.Q, query.Limit, query.CreateIfMissing)
+	if err != nil {
+		if errors.Is(err, services.ErrTagNameExists) || errors.Is(err, services.ErrTagSlugExists) {
+			respondServiceError(c, http.StatusConflict, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(suggestions))
+}
+
+// AdminList 管理后台标签列表，article_count 统计所有状态的文章
+// GET /api/v1/admin/tags?sort=article_count
+func (h *TagHandler) AdminList(c *gin.Context) {
+	var query models.TaxonomyListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tags, err := h.tagService.List(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -50,13 +108,14 @@ func (h *TagHandler) List(c *gin.Context) {
 func (h *TagHandler) GetByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid tag id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidTagID)
 		return
 	}
 
-	tag, err := h.tagService.GetByID(id)
+	tag, err := h.tagService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
 		return
 	}
 
@@ -67,38 +126,129 @@ func (h *TagHandler) GetByID(c *gin.Context) {
 func (h *TagHandler) Update(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid tag id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidTagID)
 		return
 	}
 
 	var req models.TagUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
+		return
+	}
+	if !validateRequest(c, &req) {
 		return
 	}
 
-	tag, err := h.tagService.Update(id, &req)
+	tag, err := h.tagService.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		writeTagWriteError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Success(tag))
 }
 
-// Delete 删除标签（管理后台使用）
+// Delete 删除标签（管理后台使用，软删除）
+// DELETE /api/v1/admin/tags/:id?reassign_to=&detach=
+// 标签仍被已发布文章使用时，必须通过 reassign_to 转移关联或 detach=true 移除关联，否则返回 409
 func (h *TagHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid tag id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidTagID)
 		return
 	}
 
-	if err := h.tagService.Delete(id); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	reassignTo, err := parseReassignTo(c)
+	if err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+	detach := c.Query("detach") == "true"
+
+	if err := h.tagService.Delete(c.Request.Context(), id, reassignTo, detach); err != nil {
+		if errors.Is(err, services.ErrTagInUse) {
+			respondServiceError(c, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidReassignTarget) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(nil))
+	respondNoContent(c)
 }
 
+// Merge 合并重复标签（管理后台使用）
+// POST /api/v1/admin/tags/:id/merge
+// 文章关联转移到 target_tag_id 后软删除该标签，原标签的 ID 和 slug 继续解析到目标标签；返回受影响的文章数
+func (h *TagHandler) Merge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidTagID)
+		return
+	}
+
+	var req models.TagMerge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.tagService.Merge(c.Request.Context(), id, req.TargetTagID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMergeTarget) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(result))
+}
+
+// GetBySlug 根据 slug 获取标签，已合并标签的 slug 返回合并的目标标签
+// GET /api/v1/tags/slug/:slug
+func (h *TagHandler) GetBySlug(c *gin.Context) {
+	tag, err := h.tagService.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(tag))
+}
+
+// Stats 内容统计（管理后台使用）
+// GET /api/v1/admin/stats/tags?sort_by=&order=
+func (h *TagHandler) Stats(c *gin.Context) {
+	var query models.ContentStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	stats, err := h.tagService.Stats(c.Request.Context(), query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(stats))
+}
+
+// writeTagWriteError 新建 / 更新标签的错误响应：名称或 slug 冲突返回 409，标签不存在返回 404，其余返回 400
+func writeTagWriteError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrTagNameExists) || errors.Is(err, services.ErrTagSlugExists) {
+		respondServiceError(c, http.StatusConflict, err)
+		return
+	}
+	status := serviceErrorStatus(err, http.StatusBadRequest)
+	respondServiceError(c, status, err)
+}
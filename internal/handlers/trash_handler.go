@@ -0,0 +1,154 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TrashHandler 回收站处理器（管理后台）
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+// NewTrashHandler 创建新的回收站处理器实例
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// List 查询回收站中已删除的文章 / 用户 / 图片 / 分类 / 标签
+// GET /api/v1/admin/trash?type=articles|users|images|categories|tags&page=1&page_size=20
+func (h *TrashHandler) List(c *gin.Context) {
+	var query models.TrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageOther, query.Page, query.PageSize)
+
+	items, total, err := h.trashService.List(c.Request.Context(), &query)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTrashType) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(items, query.Page, query.PageSize, total))
+}
+
+// Restore 从回收站恢复
+// POST /api/v1/admin/trash/:type/:id/restore
+func (h *TrashHandler) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidID)
+		return
+	}
+
+	if err := h.trashService.Restore(c.Request.Context(), models.TrashType(c.Param("type")), id); err != nil {
+		writeTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(nil))
+}
+
+// Purge 永久删除回收站条目
+// DELETE /api/v1/admin/trash/:type/:id
+func (h *TrashHandler) Purge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidID)
+		return
+	}
+
+	if err := h.trashService.Purge(c.Request.Context(), models.TrashType(c.Param("type")), id); err != nil {
+		writeTrashError(c, err)
+		return
+	}
+
+	respondNoContent(c)
+}
+
+// ListArticles 查询已删除的文章
+// GET /api/v1/admin/articles/trash?page=1&page_size=20
+func (h *TrashHandler) ListArticles(c *gin.Context) {
+	var page struct {
+		Page     int `form:"page"`
+		PageSize int `form:"page_size"`
+	}
+	if err := c.ShouldBindQuery(&page); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+	query := models.TrashQuery{Type: models.TrashArticles}
+	query.Page, query.PageSize = config.NormalizePage(config.PageOther, page.Page, page.PageSize)
+
+	items, total, err := h.trashService.List(c.Request.Context(), &query)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(items, query.Page, query.PageSize, total))
+}
+
+// RestoreArticle 恢复已删除的文章，文章未被删除时直接返回成功
+// POST /api/v1/admin/articles/:id/restore
+func (h *TrashHandler) RestoreArticle(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidID)
+		return
+	}
+
+	if err := h.trashService.RestoreArticle(c.Request.Context(), id); err != nil {
+		writeTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(nil))
+}
+
+// PurgeArticle 永久删除已删除的文章（含评论、标签关联和搜索索引），未删除的文章需要先删除
+// DELETE /api/v1/admin/articles/:id/purge
+func (h *TrashHandler) PurgeArticle(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidID)
+		return
+	}
+
+	if err := h.trashService.Purge(c.Request.Context(), models.TrashArticles, id); err != nil {
+		writeTrashError(c, err)
+		return
+	}
+
+	respondNoContent(c)
+}
+
+func writeTrashError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTrashType):
+		respondServiceError(c, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrTrashItemNotFound):
+		respondServiceError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrRestoreConflict), errors.Is(err, services.ErrImageFileMissing):
+		respondServiceError(c, http.StatusConflict, err)
+	default:
+		respondServiceError(c, http.StatusInternalServerError, err)
+	}
+}
@@ -1,88 +1,163 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/services"
-	"enterprise-blog/pkg/jwt"
+	"enterprise-blog/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type UserHandler struct {
 	userService *services.UserService
 	smsService *services.SMSService
-	jwtMgr      *jwt.JWTManager
-	validator   *validator.Validate
+	jwtMgr      services.TokenManager
+	validator   *i18n.Validator
 }
 
-func NewUserHandler(userService *services.UserService, smsService *services.SMSService, jwtMgr *jwt.JWTManager) *UserHandler {
+// NewUserHandler 创建用户处理器
+// jwtMgr: token 签发和校验（通常为 *jwt.JWTManager）
+func NewUserHandler(userService *services.UserService, smsService *services.SMSService, jwtMgr services.TokenManager) *UserHandler {
 	return &UserHandler{
 		userService: userService,
 		smsService:  smsService,
 		jwtMgr:      jwtMgr,
-		validator:   validator.New(),
+		validator:   newValidator(),
 	}
 }
 
 func (h *UserHandler) Register(c *gin.Context) {
 	var req models.UserCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondValidationError(c, h.validator, err)
 		return
 	}
 
-	user, err := h.userService.Register(&req)
+	user, err := h.userService.Register(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		if errors.Is(err, services.ErrRegistrationClosed) {
+			respondServiceError(c, http.StatusForbidden, err)
+			return
+		}
+		respondServiceError(c, userWriteErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.Success(user))
+	respondCreated(c, "/users/"+user.ID.String()+"/public", user)
 }
 
+// Login 邮箱密码登录
+// POST /api/v1/auth/login
+// 密码错误次数过多时返回 429（login_locked），Retry-After 头为需要等待的秒数
 func (h *UserHandler) Login(c *gin.Context) {
 	var req models.UserLogin
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondValidationError(c, h.validator, err)
 		return
 	}
 
-	token, user, err := h.userService.Login(&req)
+	tokens, user, err := h.userService.Login(c.Request.Context(), &req, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.Error(401, err.Error()))
+		if writeLoginLockedError(c, err) {
+			return
+		}
+		respondServiceError(c, http.StatusUnauthorized, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Success(map[string]interface{}{
-		"token": token,
-		"user":  user,
-	}))
+	c.JSON(http.StatusOK, models.Success(loginResponse(tokens, user)))
+}
+
+// loginResponse 登录接口的响应数据：token 字段与原有响应保持一致，另外带上刷新 token
+func loginResponse(tokens *models.AuthTokens, user *models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"token":                    tokens.Token,
+		"expires_at":               tokens.ExpiresAt,
+		"refresh_token":            tokens.RefreshToken,
+		"refresh_token_expires_at": tokens.RefreshTokenExpiresAt,
+		"user":                     user,
+	}
+}
+
+// Refresh 使用刷新 token 换取新的访问 token
+// POST /api/v1/auth/refresh
+// 刷新 token 无效、已过期或已吊销时返回 401
+func (h *UserHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		respondValidationError(c, h.validator, err)
+		return
+	}
+
+	tokens, err := h.userService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrAccountInactive) {
+			status = http.StatusUnauthorized
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(tokens))
+}
+
+// Logout 退出登录，吊销刷新 token
+// POST /api/v1/auth/logout
+// 不要求访问 token（访问 token 可能已过期）；刷新 token 无效或已吊销时返回 401
+func (h *UserHandler) Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		respondValidationError(c, h.validator, err)
+		return
+	}
+
+	if err := h.userService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			status = http.StatusUnauthorized
+		}
+		respondServiceError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(nil))
 }
 
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
-	user, err := h.userService.GetByID(userID.(uuid.UUID))
+	user, err := h.userService.GetByID(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
 
@@ -92,19 +167,19 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
 	var req models.UserUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
-	user, err := h.userService.Update(userID.(uuid.UUID), &req)
+	user, err := h.userService.Update(c.Request.Context(), userID.(uuid.UUID), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondServiceError(c, userWriteErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
@@ -115,7 +190,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 func (h *UserHandler) ChangePassword(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "unauthorized"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 		return
 	}
 
@@ -124,17 +199,17 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 		NewPassword string `json:"new_password" validate:"required,min=6"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondValidationError(c, h.validator, err)
 		return
 	}
 
-	if err := h.userService.ChangePassword(userID.(uuid.UUID), req.OldPassword, req.NewPassword); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := h.userService.ChangePassword(c.Request.Context(), userID.(uuid.UUID), req.OldPassword, req.NewPassword); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -144,13 +219,13 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid user id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
 		return
 	}
 
-	user, err := h.userService.GetByID(id)
+	user, err := h.userService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error(404, err.Error()))
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
 		return
 	}
 
@@ -158,19 +233,17 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 }
 
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	var query struct {
-		Page     int `form:"page"`
-		PageSize int `form:"page_size"`
-	}
+	var query models.UserQuery
 
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageUsers, query.Page, query.PageSize)
 
-	users, total, err := h.userService.List(query.Page, query.PageSize)
+	users, total, err := h.userService.ListByQuery(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, err.Error()))
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -181,40 +254,97 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 func (h *UserHandler) AdminUpdateUser(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, "invalid user id"))
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
 		return
 	}
 
 	var req models.UserUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
-	user, err := h.userService.Update(id, &req)
+	user, err := h.userService.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondServiceError(c, userWriteErrorStatus(err, http.StatusBadRequest), err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Success(user))
 }
 
+// Impersonate 管理员以指定用户身份登录（不允许模拟管理员）
+// POST /api/v1/admin/users/:id/impersonate
+func (h *UserHandler) Impersonate(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return
+	}
+
+	token, user, err := h.userService.Impersonate(c.Request.Context(), adminID.(uuid.UUID), id)
+	if err != nil {
+		if errors.Is(err, services.ErrCannotImpersonateAdmin) {
+			respondServiceError(c, http.StatusForbidden, err)
+			return
+		}
+		respondServiceError(c, serviceErrorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(map[string]interface{}{
+		"token":           token,
+		"user":            user,
+		"impersonator_id": adminID,
+	}))
+}
+
+// StopImpersonation 结束模拟登录，返回发起模拟的管理员的 token
+// POST /api/v1/auth/stop-impersonation
+func (h *UserHandler) StopImpersonation(c *gin.Context) {
+	impersonatorID, exists := c.Get("impersonator_id")
+	if !exists {
+		respondError(c, http.StatusBadRequest, i18n.CodeNotImpersonating)
+		return
+	}
+
+	token, user, err := h.userService.StopImpersonation(c.Request.Context(), impersonatorID.(uuid.UUID))
+	if err != nil {
+		if errors.Is(err, services.ErrImpersonatorNotAdmin) {
+			respondServiceError(c, http.StatusForbidden, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(map[string]interface{}{
+		"token": token,
+		"user":  user,
+	}))
+}
+
 // SendSMSCode 发送短信验证码
 func (h *UserHandler) SendSMSCode(c *gin.Context) {
 	var req models.SendSMSCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondValidationError(c, h.validator, err)
 		return
 	}
 
-	if err := h.smsService.SendCode(req.Phone); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+	if err := h.smsService.SendCode(c.Request.Context(), req.Phone); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -227,40 +357,50 @@ func (h *UserHandler) SendSMSCode(c *gin.Context) {
 func (h *UserHandler) LoginWithPhone(c *gin.Context) {
 	var req models.PhoneLogin
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.validator.Struct(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error(400, err.Error()))
+		respondValidationError(c, h.validator, err)
 		return
 	}
 
-	user, err := h.smsService.VerifyCode(req.Phone, req.Code)
+	user, err := h.smsService.VerifyCode(c.Request.Context(), req.Phone, req.Code)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.Error(401, err.Error()))
+		// 手机号未注册且关闭了注册时不会自动创建账号
+		if errors.Is(err, services.ErrRegistrationClosed) {
+			respondServiceError(c, http.StatusForbidden, err)
+			return
+		}
+		respondServiceError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	// 检查用户状态
 	if user.Status != "active" {
-		c.JSON(http.StatusUnauthorized, models.Error(401, "user account is not active"))
+		respondError(c, http.StatusUnauthorized, i18n.CodeAccountInactive)
 		return
 	}
 
-	// 生成 JWT token
-	token, err := h.jwtMgr.GenerateToken(user.ID, user.Username, string(user.Role))
+	// 签发访问 token 和刷新 token
+	tokens, err := h.userService.IssueTokens(c.Request.Context(), user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error(500, "failed to generate token"))
+		respondError(c, http.StatusInternalServerError, i18n.CodeInternal)
 		return
 	}
+	metrics.RecordUserLogin("sms")
 
 	// 清除密码
 	user.Password = ""
 
-	c.JSON(http.StatusOK, models.Success(map[string]interface{}{
-		"token": token,
-		"user":  user,
-	}))
+	c.JSON(http.StatusOK, models.Success(loginResponse(tokens, user)))
 }
 
+// userWriteErrorStatus 注册 / 更新用户的错误状态码：邮箱或用户名已被占用返回 409，其余同 serviceErrorStatus
+func userWriteErrorStatus(err error, fallback int) int {
+	if errors.Is(err, services.ErrEmailExists) || errors.Is(err, services.ErrUsernameExists) {
+		return http.StatusConflict
+	}
+	return serviceErrorStatus(err, fallback)
+}
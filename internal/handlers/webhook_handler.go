@@ -0,0 +1,184 @@
+// Package handlers 提供HTTP处理器
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler Webhook 处理器（管理后台）
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler 创建新的 Webhook 处理器实例
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// List 获取全部 Webhook
+// GET /api/v1/admin/webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	webhooks, err := h.webhookService.List(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(webhooks))
+}
+
+// GetByID 获取 Webhook 详情
+// GET /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(webhook))
+}
+
+// Create 创建 Webhook
+// POST /api/v1/admin/webhooks
+// 请求体: {"url": "https://example.com/hook", "events": ["article.published"], "secret": "可选"}
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req models.WebhookCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok := v.(uuid.UUID); ok {
+			createdBy = &id
+		}
+	}
+
+	webhook, err := h.webhookService.Create(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	respondCreated(c, "/admin/webhooks/"+webhook.ID.String(), webhook)
+}
+
+// Update 修改 Webhook
+// PUT /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, err := h.webhookService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Success(webhook))
+}
+
+// Delete 删除 Webhook 及其投递记录
+// DELETE /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), id); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	respondNoContent(c)
+}
+
+// ListDeliveries 分页获取 Webhook 的投递记录
+// GET /api/v1/admin/webhooks/:id/deliveries?page=&page_size=
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+
+	deliveries, total, err := h.webhookService.ListDeliveries(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Paginated(deliveries, page, pageSize, total))
+}
+
+// Redeliver 按原请求体重新投递
+// POST /api/v1/admin/webhooks/:id/deliveries/:delivery_id/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidDeliveryID)
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.Success(delivery))
+}
+
+func parseWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.CodeInvalidWebhookID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook):
+		respondServiceError(c, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrWebhookNotFound), errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		respondServiceError(c, http.StatusNotFound, err)
+	default:
+		respondServiceError(c, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,348 @@
+package i18n
+
+// 错误码，作为响应中的 error_code 返回，不随语言变化
+const (
+	// 通用
+	CodeInvalidRequest   = "invalid_request"
+	CodeValidationFailed = "validation_failed"
+	CodeInvalidFieldType = "invalid_field_type"
+	CodeInvalidDateTime  = "invalid_datetime"
+	CodeUnauthorized     = "unauthorized"
+	CodeInternal         = "internal_error"
+	CodeInvalidToken     = "invalid_token"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeUnavailable      = "service_unavailable"
+	CodeMaintenance      = "maintenance"
+	CodeInvalidID        = "invalid_id"
+
+	// 用户和认证
+	CodeInvalidUserID          = "invalid_user_id"
+	CodeUserNotFound           = "user_not_found"
+	CodeInvalidCredentials     = "invalid_credentials"
+	CodeAccountInactive        = "account_inactive"
+	CodeEmailExists            = "email_exists"
+	CodeUsernameExists         = "username_exists"
+	CodeInvalidOldPassword     = "invalid_old_password"
+	CodeRegistrationClosed     = "registration_closed"
+	CodeCannotImpersonateAdmin = "cannot_impersonate_admin"
+	CodeNotImpersonating       = "not_impersonating"
+	CodeImpersonatorNotAdmin   = "impersonator_not_admin"
+	CodeSMSTooFrequent         = "sms_too_frequent"
+	CodeSMSCodeInvalid         = "sms_code_invalid"
+	CodeInvalidRefreshToken    = "invalid_refresh_token"
+	CodeLoginLocked            = "login_locked"
+
+	// 邮箱验证和找回密码
+	CodeEmailNotVerified         = "email_not_verified"
+	CodeInvalidVerificationToken = "invalid_verification_token"
+	CodeVerificationTooFrequent  = "verification_too_frequent"
+	CodeInvalidResetToken        = "invalid_reset_token"
+
+	// 文章和评论
+	CodeInvalidArticleID       = "invalid_article_id"
+	CodeArticleNotFound        = "article_not_found"
+	CodeArticleVersionRequired = "article_version_required"
+	CodeArticleVersionConflict = "article_version_conflict"
+	CodeTagsNotFound           = "tags_not_found"
+	CodeCategoryNotFound       = "category_not_found"
+	CodeNotArticleAuthor       = "not_article_author"
+	CodeScheduledAtRequired    = "scheduled_at_required"
+	CodeInvalidRevisionID      = "invalid_revision_id"
+	CodeRevisionNotFound       = "revision_not_found"
+	CodeRevisionDiffTooLarge   = "revision_diff_too_large"
+	CodeInvalidCommentID       = "invalid_comment_id"
+	CodeContentRejected        = "content_rejected"
+	CodeInvalidPreviewToken    = "invalid_preview_token"
+	CodePreviewTokenNotFound   = "preview_token_not_found"
+
+	// 分类和标签（slug / 名称冲突沿用文档中已有的标识）
+	CodeInvalidCategoryID     = "invalid_category_id"
+	CodeCategorySlugExists    = "category.slug_exists"
+	CodeInvalidParentCategory = "invalid_parent_category"
+	CodeCategoryInUse         = "category_in_use"
+	CodeInvalidTagID          = "invalid_tag_id"
+	CodeTagNotFound           = "tag_not_found"
+	CodeTagSlugExists         = "tag.slug_exists"
+	CodeTagNameExists         = "tag.name_exists"
+	CodeTagInUse              = "tag_in_use"
+	CodeInvalidMergeTarget    = "invalid_merge_target"
+	CodeInvalidReassignTarget = "invalid_reassign_target"
+
+	// 图片
+	CodeInvalidImageID         = "invalid_image_id"
+	CodeImageNotFound          = "image_not_found"
+	CodeImageFileRequired      = "image_file_required"
+	CodeUnsupportedImageFormat = "unsupported_image_format"
+	CodeImageTooLarge          = "image_too_large"
+	CodeInvalidImage           = "invalid_image"
+	CodeNotImageOwner          = "not_image_owner"
+	CodeInvalidFilename        = "invalid_filename"
+
+	// 通知和邮件订阅
+	CodeInvalidNotificationID  = "invalid_notification_id"
+	CodeNotificationNotFound   = "notification_not_found"
+	CodeInvalidConfirmToken    = "invalid_confirm_token"
+	CodeInvalidUnsubscribeLink = "invalid_unsubscribe_link"
+	CodeSubscriberNotFound     = "subscriber_not_found"
+	CodeInvalidCampaignID      = "invalid_campaign_id"
+	CodeInvalidCampaign        = "invalid_campaign"
+	CodeCampaignNotFound       = "campaign_not_found"
+
+	// 管理后台
+	CodeInvalidJobID            = "invalid_job_id"
+	CodeExportJobNotFound       = "export_job_not_found"
+	CodeExportJobNotReady       = "export_job_not_ready"
+	CodeReindexRunning          = "reindex_running"
+	CodeReindexJobNotFound      = "reindex_job_not_found"
+	CodeSearchDisabled          = "search_disabled"
+	CodeInvalidWebhookID        = "invalid_webhook_id"
+	CodeInvalidDeliveryID       = "invalid_delivery_id"
+	CodeInvalidWebhook          = "invalid_webhook"
+	CodeWebhookNotFound         = "webhook_not_found"
+	CodeWebhookDeliveryNotFound = "webhook_delivery_not_found"
+	CodeInvalidAuditLogID       = "invalid_audit_log_id"
+	CodeAuditLogNotFound        = "audit_log_not_found"
+	CodeInvalidTrashType        = "invalid_trash_type"
+	CodeTrashItemNotFound       = "trash_item_not_found"
+	CodeRestoreConflict         = "restore_conflict"
+	CodeImageFileMissing        = "image_file_missing"
+	CodeUnknownSetting          = "unknown_setting"
+	CodeInvalidSettingValue     = "invalid_setting_value"
+	CodeUnknownFlag             = "unknown_flag"
+	CodeInvalidFlagValue        = "invalid_flag_value"
+	CodeInvalidTopKind          = "invalid_top_kind"
+	CodeInvalidTopPeriod        = "invalid_top_period"
+	CodeInvalidCacheScope       = "invalid_cache_scope"
+	CodeInvalidConfiguration    = "invalid_configuration"
+	CodeNoReportRecipients      = "no_report_recipients"
+	CodeSitemapNotFound         = "sitemap_not_found"
+)
+
+// catalogs 各语言的消息目录，新增错误码时需要同时补充全部语言
+var catalogs = map[string]map[string]string{
+	ZhCN: {
+		CodeInvalidRequest:   "请求格式错误",
+		CodeValidationFailed: "参数校验失败",
+		CodeInvalidFieldType: "类型不正确",
+		CodeInvalidDateTime:  "必须是 RFC3339 时间或 YYYY-MM-DD 日期",
+		CodeUnauthorized:     "未登录或登录已过期",
+		CodeInternal:         "服务器内部错误，请稍后再试",
+		CodeInvalidToken:     "登录凭证无效或已过期，请重新登录",
+		CodeForbidden:        "没有权限执行该操作",
+		CodeNotFound:         "资源不存在",
+		CodeConflict:         "资源已存在或与现有数据冲突",
+		CodeTooManyRequests:  "请求过于频繁，请稍后再试",
+		CodeUnavailable:      "依赖的服务暂不可用，请稍后再试",
+		CodeMaintenance:      "系统维护中，请稍后再试",
+		CodeInvalidID:        "ID 格式错误",
+
+		CodeInvalidUserID:          "用户 ID 格式错误",
+		CodeUserNotFound:           "用户不存在",
+		CodeInvalidCredentials:     "邮箱或密码错误",
+		CodeAccountInactive:        "账号已被禁用",
+		CodeEmailExists:            "邮箱已被注册",
+		CodeUsernameExists:         "用户名已被占用",
+		CodeInvalidOldPassword:     "原密码错误",
+		CodeRegistrationClosed:     "站点已关闭注册",
+		CodeCannotImpersonateAdmin: "不能以管理员身份登录",
+		CodeNotImpersonating:       "当前不是模拟登录会话",
+		CodeImpersonatorNotAdmin:   "发起模拟登录的账号已不是有效的管理员，请重新登录",
+		CodeSMSTooFrequent:         "验证码发送过于频繁，请稍后再试",
+		CodeSMSCodeInvalid:         "验证码无效或已过期",
+		CodeInvalidRefreshToken:    "登录已失效，请重新登录",
+		CodeLoginLocked:            "登录失败次数过多，请稍后再试",
+
+		CodeEmailNotVerified:         "邮箱尚未验证，请点击验证邮件中的链接",
+		CodeInvalidVerificationToken: "验证链接无效或已过期",
+		CodeVerificationTooFrequent:  "验证邮件发送过于频繁，请稍后再试",
+		CodeInvalidResetToken:        "重置密码链接无效或已过期，请重新申请",
+
+		CodeInvalidArticleID:       "文章 ID 格式错误",
+		CodeArticleNotFound:        "文章不存在",
+		CodeArticleVersionRequired: "请提供文章版本号（expected_version 或 If-Match 请求头）",
+		CodeArticleVersionConflict: "文章已被他人修改，请刷新后重试",
+		CodeTagsNotFound:           "部分标签不存在或已删除",
+		CodeCategoryNotFound:       "分类不存在或已删除",
+		CodeNotArticleAuthor:       "只能操作自己的文章",
+		CodeScheduledAtRequired:    "定时发布需要指定发布时间（scheduled_at）",
+		CodeInvalidRevisionID:      "修订记录 ID 格式错误",
+		CodeRevisionNotFound:       "修订记录不存在",
+		CodeRevisionDiffTooLarge:   "修订内容过大，无法比较",
+		CodeInvalidCommentID:       "评论 ID 格式错误",
+		CodeContentRejected:        "内容包含违规词语，请修改后重新提交",
+		CodeInvalidPreviewToken:    "预览链接无效或已过期",
+		CodePreviewTokenNotFound:   "预览链接不存在或已失效",
+
+		CodeInvalidCategoryID:     "分类 ID 格式错误",
+		CodeCategorySlugExists:    "分类 slug 已被占用",
+		CodeInvalidParentCategory: "父分类不存在、是分类自身或会形成循环",
+		CodeCategoryInUse:         "分类仍被文章或子分类使用，请通过 reassign_to 指定新分类",
+		CodeInvalidTagID:          "标签 ID 格式错误",
+		CodeTagNotFound:           "标签不存在或已删除",
+		CodeTagSlugExists:         "标签 slug 已被占用",
+		CodeTagNameExists:         "标签名称已被使用",
+		CodeTagInUse:              "标签仍被已发布文章使用，请通过 reassign_to 转移或 detach=true 移除关联",
+		CodeInvalidMergeTarget:    "合并的目标标签不存在、已删除或与当前标签相同",
+		CodeInvalidReassignTarget: "转移的目标不存在、已删除或与当前相同",
+
+		CodeInvalidImageID:         "图片 ID 格式错误",
+		CodeImageNotFound:          "图片不存在",
+		CodeImageFileRequired:      "请选择要上传的图片文件（file）",
+		CodeUnsupportedImageFormat: "不支持的图片格式，只允许 JPEG、PNG、GIF、WebP",
+		CodeImageTooLarge:          "图片大小超过 10MB 限制",
+		CodeInvalidImage:           "文件不是有效的图片",
+		CodeNotImageOwner:          "只能修改或删除自己上传的图片",
+		CodeInvalidFilename:        "文件名不合法",
+
+		CodeInvalidNotificationID:  "通知 ID 格式错误",
+		CodeNotificationNotFound:   "通知不存在",
+		CodeInvalidConfirmToken:    "确认链接无效或已过期",
+		CodeInvalidUnsubscribeLink: "退订链接无效",
+		CodeSubscriberNotFound:     "订阅者不存在",
+		CodeInvalidCampaignID:      "群发 ID 格式错误",
+		CodeInvalidCampaign:        "群发参数不合法",
+		CodeCampaignNotFound:       "群发记录不存在",
+
+		CodeInvalidJobID:            "任务 ID 格式错误",
+		CodeExportJobNotFound:       "导出任务或导出文件不存在",
+		CodeExportJobNotReady:       "导出任务尚未完成",
+		CodeReindexRunning:          "已有索引重建任务正在运行",
+		CodeReindexJobNotFound:      "索引重建任务不存在",
+		CodeSearchDisabled:          "未启用搜索服务",
+		CodeInvalidWebhookID:        "Webhook ID 格式错误",
+		CodeInvalidDeliveryID:       "投递记录 ID 格式错误",
+		CodeInvalidWebhook:          "Webhook 地址或订阅事件不合法",
+		CodeWebhookNotFound:         "Webhook 不存在",
+		CodeWebhookDeliveryNotFound: "投递记录不存在",
+		CodeInvalidAuditLogID:       "审计日志 ID 格式错误",
+		CodeAuditLogNotFound:        "审计日志不存在",
+		CodeInvalidTrashType:        "类型不合法，只能是 articles、users、images、categories、tags",
+		CodeTrashItemNotFound:       "回收站中没有该条目",
+		CodeRestoreConflict:         "恢复后会与现有数据冲突",
+		CodeImageFileMissing:        "图片文件已不存在，无法恢复",
+		CodeUnknownSetting:          "未知的设置项",
+		CodeInvalidSettingValue:     "设置值不合法",
+		CodeUnknownFlag:             "未知的功能开关",
+		CodeInvalidFlagValue:        "功能开关的值不合法",
+		CodeInvalidTopKind:          "排行榜类型不合法，只能是 articles_views、articles_comments、authors",
+		CodeInvalidTopPeriod:        "统计周期不合法，只能是 7d、30d",
+		CodeInvalidCacheScope:       "清理范围不合法，只能是 article_detail、article_list、dashboard、all_app_caches",
+		CodeInvalidConfiguration:    "配置不合法，未生效",
+		CodeNoReportRecipients:      "未配置周报收件人",
+		CodeSitemapNotFound:         "站点地图不存在",
+	},
+	EN: {
+		CodeInvalidRequest:   "Invalid request",
+		CodeValidationFailed: "Validation failed",
+		CodeInvalidFieldType: "has an invalid type",
+		CodeInvalidDateTime:  "must be an RFC3339 time or a YYYY-MM-DD date",
+		CodeUnauthorized:     "Authentication required",
+		CodeInternal:         "Internal server error, please try again later",
+		CodeInvalidToken:     "The access token is invalid or has expired, please sign in again",
+		CodeForbidden:        "You do not have permission to perform this action",
+		CodeNotFound:         "Resource not found",
+		CodeConflict:         "The resource already exists or conflicts with existing data",
+		CodeTooManyRequests:  "Too many requests, please try again later",
+		CodeUnavailable:      "A required service is temporarily unavailable, please try again later",
+		CodeMaintenance:      "The service is temporarily unavailable for maintenance, please try again later",
+		CodeInvalidID:        "Invalid ID",
+
+		CodeInvalidUserID:          "Invalid user ID",
+		CodeUserNotFound:           "User not found",
+		CodeInvalidCredentials:     "Invalid email or password",
+		CodeAccountInactive:        "The account is not active",
+		CodeEmailExists:            "Email is already registered",
+		CodeUsernameExists:         "Username is already taken",
+		CodeInvalidOldPassword:     "The current password is incorrect",
+		CodeRegistrationClosed:     "Registration is closed",
+		CodeCannotImpersonateAdmin: "Cannot sign in as an administrator",
+		CodeNotImpersonating:       "Not an impersonation session",
+		CodeImpersonatorNotAdmin:   "The account that started this impersonation is no longer an active admin, please sign in again",
+		CodeSMSTooFrequent:         "Verification codes are requested too often, please try again later",
+		CodeSMSCodeInvalid:         "The verification code is invalid or has expired",
+		CodeInvalidRefreshToken:    "The session has expired, please sign in again",
+		CodeLoginLocked:            "Too many failed login attempts, please try again later",
+
+		CodeEmailNotVerified:         "The email address has not been verified, please follow the link in the verification email",
+		CodeInvalidVerificationToken: "The verification link is invalid or has expired",
+		CodeVerificationTooFrequent:  "Verification emails are requested too often, please try again later",
+		CodeInvalidResetToken:        "The password reset link is invalid or has expired, please request a new one",
+
+		CodeInvalidArticleID:       "Invalid article ID",
+		CodeArticleNotFound:        "Article not found",
+		CodeArticleVersionRequired: "The article version is required (expected_version or If-Match header)",
+		CodeArticleVersionConflict: "The article was modified by someone else, please reload and try again",
+		CodeTagsNotFound:           "Some tags do not exist or have been deleted",
+		CodeCategoryNotFound:       "The category does not exist or has been deleted",
+		CodeNotArticleAuthor:       "You can only perform this action on your own articles",
+		CodeScheduledAtRequired:    "A publish time (scheduled_at) is required for scheduled articles",
+		CodeInvalidRevisionID:      "Invalid revision ID",
+		CodeRevisionNotFound:       "Revision not found",
+		CodeRevisionDiffTooLarge:   "The revisions are too large to compare",
+		CodeInvalidCommentID:       "Invalid comment ID",
+		CodeContentRejected:        "The content contains prohibited words, please revise and resubmit",
+		CodeInvalidPreviewToken:    "The preview link is invalid or has expired",
+		CodePreviewTokenNotFound:   "The preview link does not exist or is no longer valid",
+
+		CodeInvalidCategoryID:     "Invalid category ID",
+		CodeCategorySlugExists:    "The category slug is already taken",
+		CodeInvalidParentCategory: "The parent category does not exist, is the category itself or would create a cycle",
+		CodeCategoryInUse:         "The category is still used by articles or child categories, pass reassign_to to move them",
+		CodeInvalidTagID:          "Invalid tag ID",
+		CodeTagNotFound:           "The tag does not exist or has been deleted",
+		CodeTagSlugExists:         "The tag slug is already taken",
+		CodeTagNameExists:         "The tag name is already in use",
+		CodeTagInUse:              "The tag is still used by published articles, pass reassign_to or detach=true",
+		CodeInvalidMergeTarget:    "The merge target does not exist, has been deleted or is the same tag",
+		CodeInvalidReassignTarget: "The reassign target does not exist, has been deleted or is the same item",
+
+		CodeInvalidImageID:         "Invalid image ID",
+		CodeImageNotFound:          "Image not found",
+		CodeImageFileRequired:      "An image file (file) is required",
+		CodeUnsupportedImageFormat: "Unsupported image format, only JPEG, PNG, GIF and WebP are allowed",
+		CodeImageTooLarge:          "The image exceeds the 10MB size limit",
+		CodeInvalidImage:           "The file is not a valid image",
+		CodeNotImageOwner:          "You can only change or delete your own images",
+		CodeInvalidFilename:        "Invalid file name",
+
+		CodeInvalidNotificationID:  "Invalid notification ID",
+		CodeNotificationNotFound:   "Notification not found",
+		CodeInvalidConfirmToken:    "The confirmation link is invalid or has expired",
+		CodeInvalidUnsubscribeLink: "The unsubscribe link is invalid",
+		CodeSubscriberNotFound:     "Subscriber not found",
+		CodeInvalidCampaignID:      "Invalid campaign ID",
+		CodeInvalidCampaign:        "Invalid newsletter campaign",
+		CodeCampaignNotFound:       "Newsletter campaign not found",
+
+		CodeInvalidJobID:            "Invalid job ID",
+		CodeExportJobNotFound:       "The export job or its file does not exist",
+		CodeExportJobNotReady:       "The export job has not finished yet",
+		CodeReindexRunning:          "A reindex job is already running",
+		CodeReindexJobNotFound:      "Reindex job not found",
+		CodeSearchDisabled:          "Search is not enabled",
+		CodeInvalidWebhookID:        "Invalid webhook ID",
+		CodeInvalidDeliveryID:       "Invalid delivery ID",
+		CodeInvalidWebhook:          "Invalid webhook URL or events",
+		CodeWebhookNotFound:         "Webhook not found",
+		CodeWebhookDeliveryNotFound: "Webhook delivery not found",
+		CodeInvalidAuditLogID:       "Invalid audit log ID",
+		CodeAuditLogNotFound:        "Audit log not found",
+		CodeInvalidTrashType:        "Invalid type, must be one of articles, users, images, categories, tags",
+		CodeTrashItemNotFound:       "The item is not in the trash",
+		CodeRestoreConflict:         "Restoring the item would conflict with existing data",
+		CodeImageFileMissing:        "The image file no longer exists and cannot be restored",
+		CodeUnknownSetting:          "Unknown setting",
+		CodeInvalidSettingValue:     "Invalid setting value",
+		CodeUnknownFlag:             "Unknown feature flag",
+		CodeInvalidFlagValue:        "Invalid feature flag value",
+		CodeInvalidTopKind:          "Invalid kind, must be one of articles_views, articles_comments, authors",
+		CodeInvalidTopPeriod:        "Invalid period, must be one of 7d, 30d",
+		CodeInvalidCacheScope:       "Invalid scope, must be one of article_detail, article_list, dashboard, all_app_caches",
+		CodeInvalidConfiguration:    "Invalid configuration, nothing was applied",
+		CodeNoReportRecipients:      "No report recipients are configured",
+		CodeSitemapNotFound:         "Sitemap not found",
+	},
+}
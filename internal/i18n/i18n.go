@@ -0,0 +1,79 @@
+// Package i18n 提供接口错误信息的多语言支持
+//
+// 设计考虑：
+// - 错误码（error_code）是稳定的机器可读标识，消息按错误码从消息目录中查找，客户端可自行按错误码处理或直接展示消息
+// - 语言按请求的 Accept-Language 协商，没有可用语言时使用配置的默认语言（i18n.default_language）
+// - 参数校验错误使用 go-playground/validator 的翻译包逐字段翻译，见 Validator
+package i18n
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN"
+	EN   = "en"
+)
+
+// Languages 支持的语言列表
+var Languages = []string{ZhCN, EN}
+
+// Match 将语言标签匹配到支持的语言
+// tag: 语言标签，忽略大小写，zh / zh-Hans / zh_CN 等匹配 zh-CN，en-US / en-GB 等匹配 en
+// 返回: 不支持的语言返回空字符串
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	switch base {
+	case "zh":
+		return ZhCN
+	case "en":
+		return EN
+	}
+	return ""
+}
+
+// Negotiate 按 Accept-Language 请求头选择响应语言
+// acceptLanguage: 请求头的值，如 "en-US,en;q=0.9,zh-CN;q=0.8"
+// fallback: 请求头缺失、为 * 或没有支持的语言时使用；fallback 也不支持时使用 zh-CN
+// 注意: q 值相同时按请求头中的顺序选择
+func Negotiate(acceptLanguage, fallback string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		lang := Match(tag)
+		if lang == "" || q <= bestQ {
+			continue
+		}
+		best, bestQ = lang, q
+	}
+	if best != "" {
+		return best
+	}
+	if lang := Match(fallback); lang != "" {
+		return lang
+	}
+	return ZhCN
+}
+
+// T 返回错误码在指定语言下的消息
+// 注意: 该语言缺少条目时使用中文，错误码未登记时返回错误码本身
+func T(lang, code string) string {
+	if msg, ok := catalogs[Match(lang)][code]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[ZhCN][code]; ok {
+		return msg
+	}
+	return code
+}
@@ -0,0 +1,128 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// Validator 注册了中英文翻译的参数校验器
+// 注意: 翻译注册在具体的 validator.Validate 实例上，校验错误需要用产生它的 Validator 翻译
+type Validator struct {
+	*validator.Validate
+	translators map[string]ut.Translator
+}
+
+// NewValidator 为校验器注册中英文翻译
+// v: 要注册翻译的校验器（如 gin 绑定使用的校验器或处理器自己的校验器）
+// 注意: 字段名改为取 json 标签（其次 form 标签），错误信息中的字段名与请求中的一致
+func NewValidator(v *validator.Validate) (*Validator, error) {
+	v.RegisterTagNameFunc(fieldName)
+
+	// 每个校验器使用独立的翻译器，同一翻译器重复注册会报冲突
+	uni := ut.New(en.New(), en.New(), zh.New())
+	enTrans, _ := uni.GetTranslator("en")
+	zhTrans, _ := uni.GetTranslator("zh")
+	if err := enTranslations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return nil, err
+	}
+	if err := zhTranslations.RegisterDefaultTranslations(v, zhTrans); err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		Validate:    v,
+		translators: map[string]ut.Translator{EN: enTrans, ZhCN: zhTrans},
+	}, nil
+}
+
+// RegisterRule 注册自定义校验规则及其各语言的错误信息
+// messages: 语言 -> 错误信息模板，{0} 为字段名，如 {EN: "{0} must be a valid status"}；缺少的语言使用校验器默认的错误信息
+func (v *Validator) RegisterRule(tag string, fn validator.Func, messages map[string]string) error {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+	for lang, trans := range v.translators {
+		msg, ok := messages[lang]
+		if !ok {
+			continue
+		}
+		register := func(ut ut.Translator) error { return ut.Add(tag, msg, true) }
+		translate := func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T(tag, fe.Field())
+			return t
+		}
+		if err := v.RegisterTranslation(tag, trans, register, translate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	// Field 请求中的字段名（json 标签，其次 form 标签），嵌套字段以 "." 连接，如 items[0].name
+	Field string `json:"field"`
+	// Rule 未通过的校验规则，如 required、email、max
+	Rule string `json:"rule"`
+	// Message 按请求语言翻译的错误信息
+	Message string `json:"message"`
+}
+
+// FieldErrors 将参数校验错误转换为逐字段的错误列表，错误信息翻译为指定语言
+// 返回: err 不是校验错误（如 JSON 格式错误）时 ok 为 false
+func (v *Validator) FieldErrors(lang string, err error) ([]FieldError, bool) {
+	var fieldErrs validator.ValidationErrors
+	if v == nil || !errors.As(err, &fieldErrs) {
+		return nil, false
+	}
+	trans, ok := v.translators[Match(lang)]
+	if !ok {
+		trans = v.translators[ZhCN]
+	}
+	result := make([]FieldError, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		// Namespace 以结构体名开头（如 UserCreate.email），去掉后与请求中的字段路径一致
+		_, field, found := strings.Cut(fe.Namespace(), ".")
+		if !found {
+			field = fe.Field()
+		}
+		result = append(result, FieldError{Field: field, Rule: fe.Tag(), Message: fe.Translate(trans)})
+	}
+	return result, true
+}
+
+// Translate 将参数校验错误翻译为指定语言，多个字段的错误以 "; " 连接
+// 返回: err 不是校验错误（如 JSON 格式错误）时 ok 为 false
+func (v *Validator) Translate(lang string, err error) (string, bool) {
+	fieldErrs, ok := v.FieldErrors(lang, err)
+	if !ok {
+		return "", false
+	}
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		messages = append(messages, fe.Message)
+	}
+	return strings.Join(messages, "; "), true
+}
+
+// fieldName 校验错误中使用的字段名
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// auditMaxBodySize 审计记录的请求体上限，超过部分不记录
+const auditMaxBodySize = 64 << 10
+
+// 审计日志各列的长度上限（与 audit_logs 表的 VARCHAR 长度一致），超出部分截断
+const (
+	auditMaxPathLen       = 500
+	auditMaxActionLen     = 200
+	auditMaxResourceIDLen = 100
+	auditMaxUserAgentLen  = 500
+)
+
+// AuditMiddleware 记录管理后台的写操作（POST/PUT/PATCH/DELETE）
+// 需放在 AuthMiddleware 之后，以便获取操作人 user_id
+func AuditMiddleware(auditService *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auditRequest(c, auditService)
+	}
+}
+
+// ImpersonationAuditMiddleware 仅记录模拟登录会话中的写操作
+// 用于非管理后台路由：管理员以其他用户身份进行的修改同样需要留痕
+// 需放在 AuthMiddleware 之后
+func ImpersonationAuditMiddleware(auditService *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := impersonatorID(c); !ok {
+			c.Next()
+			return
+		}
+		auditRequest(c, auditService)
+	}
+}
+
+// auditRequest 执行后续处理器，并异步写入本次写操作的审计日志
+func auditRequest(c *gin.Context, auditService *services.AuditService) {
+	method := c.Request.Method
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		c.Next()
+		return
+	}
+
+	// 读取请求体后放回，保证后续处理器仍可读取
+	var body []byte
+	if c.Request.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(c.Request.Body, auditMaxBodySize+1))
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		if len(body) > auditMaxBodySize {
+			body = nil
+		}
+	}
+
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+
+	entry := &models.AuditLog{
+		Method:       method,
+		Path:         truncate(c.Request.URL.Path, auditMaxPathLen),
+		Action:       truncate(method+" "+route, auditMaxActionLen),
+		ResourceType: auditResourceType(route),
+		ResourceID:   truncate(c.Param("id"), auditMaxResourceIDLen),
+		StatusCode:   c.Writer.Status(),
+		IP:           c.ClientIP(),
+		UserAgent:    truncate(c.Request.UserAgent(), auditMaxUserAgentLen),
+		Payload:      services.RedactAuditPayload(body, c.ContentType()),
+		CreatedAt:    time.Now(),
+	}
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok := v.(uuid.UUID); ok {
+			entry.ActorID = &id
+		}
+	}
+	if id, ok := impersonatorID(c); ok {
+		entry.ImpersonatorID = &id
+	}
+
+	// 异步写入，避免影响接口响应时间
+	l := logger.FromContext(c.Request.Context())
+	go func(entry *models.AuditLog) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := auditService.Record(ctx, entry); err != nil {
+			l.Warn().Err(err).Str("action", entry.Action).Msg("failed to write audit log")
+		}
+	}(entry)
+}
+
+// auditResourceType 从路由中提取资源类型，例如 /api/v1/admin/users/:id -> users，/api/v1/articles/:id -> articles
+func auditResourceType(route string) string {
+	parts := strings.Split(strings.Trim(route, "/"), "/")
+	for i, p := range parts {
+		if p == "admin" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	for i, p := range parts {
+		if p == "v1" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// truncate 截断到最多 max 个字节，不会截断在多字节字符中间
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
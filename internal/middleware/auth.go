@@ -3,34 +3,35 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"sync/atomic"
 
-	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/services"
 	"enterprise-blog/pkg/jwt"
+	"enterprise-blog/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func AuthMiddleware(jwtMgr *jwt.JWTManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.Error(401, "authorization header required"))
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, models.Error(401, "invalid authorization header format"))
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, i18n.CodeInvalidToken)
 			return
 		}
 
 		token := parts[1]
 		claims, err := jwtMgr.ValidateToken(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.Error(401, "invalid token"))
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, i18n.CodeInvalidToken)
 			return
 		}
 
@@ -38,17 +39,79 @@ func AuthMiddleware(jwtMgr *jwt.JWTManager) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		ctx := logger.WithUserID(c.Request.Context(), claims.UserID.String())
+		// 模拟登录会话：记录发起模拟的管理员，供审计和日志标记；管理员代为操作不计入用户活跃
+		if claims.ImpersonatorID != nil {
+			c.Set("impersonator_id", *claims.ImpersonatorID)
+			ctx = logger.WithImpersonatorID(ctx, claims.ImpersonatorID.String())
+		} else {
+			services.TrackActiveUser(ctx, claims.UserID)
+		}
+		c.Request = c.Request.WithContext(ctx)
 
 		c.Next()
 	}
 }
 
+// OptionalAuthMiddleware 可选认证：没有 Authorization 头时按匿名请求继续处理，
+// 带有 Authorization 头时与 AuthMiddleware 相同（token 无效返回 401，不会静默降级为匿名）
+func OptionalAuthMiddleware(jwtMgr *jwt.JWTManager) gin.HandlerFunc {
+	auth := AuthMiddleware(jwtMgr)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
+
+// AuthSwitch 可在运行中切换的认证要求（配置热加载）
+type AuthSwitch struct {
+	required atomic.Bool
+}
+
+// NewAuthSwitch 创建认证开关
+// required: 是否要求登录
+func NewAuthSwitch(required bool) *AuthSwitch {
+	s := &AuthSwitch{}
+	s.SetRequired(required)
+	return s
+}
+
+// SetRequired 修改是否要求登录，对之后的请求生效
+func (s *AuthSwitch) SetRequired(required bool) {
+	s.required.Store(required)
+}
+
+// Middleware 返回认证中间件：要求登录时与 AuthMiddleware 相同，否则与 OptionalAuthMiddleware 相同
+func (s *AuthSwitch) Middleware(jwtMgr *jwt.JWTManager) gin.HandlerFunc {
+	auth := AuthMiddleware(jwtMgr)
+	optional := OptionalAuthMiddleware(jwtMgr)
+	return func(c *gin.Context) {
+		if s.required.Load() {
+			auth(c)
+			return
+		}
+		optional(c)
+	}
+}
+
+// impersonatorID 返回当前请求的模拟发起人（非模拟会话返回 false）
+func impersonatorID(c *gin.Context) (uuid.UUID, bool) {
+	v, ok := c.Get("impersonator_id")
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, ok := v.(uuid.UUID)
+	return id, ok
+}
+
 func RoleMiddleware(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusForbidden, models.Error(403, "role not found"))
-			c.Abort()
+			abortWithError(c, http.StatusForbidden, i18n.CodeForbidden)
 			return
 		}
 
@@ -60,8 +123,7 @@ func RoleMiddleware(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, models.Error(403, "insufficient permissions"))
-		c.Abort()
+		abortWithError(c, http.StatusForbidden, i18n.CodeForbidden)
 	}
 }
 
@@ -2,40 +2,72 @@ package middleware
 
 import (
 	"net/http"
-	"os"
-	"strings"
+	"sync/atomic"
+
+	"enterprise-blog/internal/config"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
-	allowedOrigins := map[string]struct{}{
-		"http://localhost:3000": {},
-		"http://127.0.0.1:3000": {},
-		"http://localhost:5173": {},
-		"http://127.0.0.1:5173": {},
-	}
+// devOrigins 本地开发前端地址，始终允许
+var devOrigins = []string{
+	"http://localhost:3000",
+	"http://127.0.0.1:3000",
+	"http://localhost:5173",
+	"http://127.0.0.1:5173",
+}
 
-	if extra := os.Getenv("CORS_ALLOWED_ORIGINS"); extra != "" {
-		for _, o := range strings.Split(extra, ",") {
-			o = strings.TrimSpace(o)
-			if o != "" {
-				allowedOrigins[o] = struct{}{}
-			}
+// CORSPolicy 跨域策略，允许的来源可以在运行中替换（配置热加载）
+type CORSPolicy struct {
+	allowedOrigins atomic.Pointer[map[string]struct{}]
+}
+
+// NewCORSPolicy 创建跨域策略
+// 参数:
+//   - origins: 在本地开发地址之外额外允许的来源
+func NewCORSPolicy(origins []string) *CORSPolicy {
+	p := &CORSPolicy{}
+	p.SetAllowedOrigins(origins)
+	return p
+}
+
+// SetAllowedOrigins 替换额外允许的来源，对之后的请求生效
+func (p *CORSPolicy) SetAllowedOrigins(origins []string) {
+	allowed := make(map[string]struct{}, len(devOrigins)+len(origins))
+	for _, o := range devOrigins {
+		allowed[o] = struct{}{}
+	}
+	for _, o := range origins {
+		if o != "" {
+			allowed[o] = struct{}{}
 		}
 	}
+	p.allowedOrigins.Store(&allowed)
+}
+
+// CORSMiddleware 使用配置（cors.allowed_origins）中的来源创建跨域中间件
+func CORSMiddleware() gin.HandlerFunc {
+	var origins []string
+	if current := config.Get(); current != nil {
+		origins = current.CORS.AllowedOrigins
+	}
+	return NewCORSPolicy(origins).Middleware()
+}
 
+// Middleware 返回跨域中间件
+func (p *CORSPolicy) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 		if origin != "" {
-			if _, ok := allowedOrigins[origin]; ok {
+			if _, ok := (*p.allowedOrigins.Load())[origin]; ok {
 				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 				c.Writer.Header().Set("Vary", "Origin")
 			}
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLanguage 当前请求的响应语言：按 Accept-Language 协商，缺省使用 i18n.default_language
+// 注意: 中间件和处理器的错误响应共用，保证同一请求的错误信息语言一致
+func RequestLanguage(c *gin.Context) string {
+	fallback := i18n.ZhCN
+	if current := config.Get(); current != nil {
+		fallback = current.I18n.DefaultLanguage
+	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"), fallback)
+}
+
+// abortWithError 按错误码返回本地化的错误响应并中断后续处理
+// status: HTTP 状态码（同时作为响应体中的 code），code: i18n 错误码
+func abortWithError(c *gin.Context, status int, code string) {
+	c.AbortWithStatusJSON(status, models.ErrorWithCode(status, code, i18n.T(RequestLanguage(c), code)))
+}
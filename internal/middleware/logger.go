@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"enterprise-blog/pkg/logger"
@@ -8,7 +11,72 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// RequestSampler 请求日志采样器
+//
+// 设计考虑：
+// - 只对匹配路由的 2xx GET/HEAD 请求采样，写操作、4xx/5xx、带错误和慢请求始终记录
+// - 每个路由一个原子计数器，第 1、N+1、2N+1... 个请求被记录，无锁且结果确定，便于测试
+type RequestSampler struct {
+	rate   uint64
+	slow   time.Duration
+	routes []*sampledRoute
+}
+
+// sampledRoute 采样路由；pattern 以 "/*" 结尾时按前缀匹配
+type sampledRoute struct {
+	pattern string
+	prefix  bool
+	count   atomic.Uint64
+}
+
+// NewRequestSampler 创建请求日志采样器
+// 参数:
+//   - rate: 每 rate 个请求记录一条，小于等于 1 时不采样
+//   - routes: 采样的路径，如 "/health"、"/uploads/images/*"
+//   - slow: 慢请求阈值，耗时达到阈值的请求始终记录，0 表示不按耗时判断
+func NewRequestSampler(rate int, routes []string, slow time.Duration) *RequestSampler {
+	s := &RequestSampler{slow: slow}
+	if rate > 1 {
+		s.rate = uint64(rate)
+	}
+	for _, pattern := range routes {
+		route := &sampledRoute{pattern: pattern}
+		if strings.HasSuffix(pattern, "/*") {
+			route.pattern = strings.TrimSuffix(pattern, "*")
+			route.prefix = true
+		}
+		s.routes = append(s.routes, route)
+	}
+	return s
+}
+
+// ShouldLog 判断请求是否需要记录日志
+// 注意: 每次对匹配路由的 2xx 请求调用都会推进该路由的计数器
+func (s *RequestSampler) ShouldLog(method, path string, status int, latency time.Duration) bool {
+	if s == nil || s.rate == 0 {
+		return true
+	}
+	if status < 200 || status >= 300 || (method != http.MethodGet && method != http.MethodHead) {
+		return true
+	}
+	if s.slow > 0 && latency >= s.slow {
+		return true
+	}
+	for _, route := range s.routes {
+		if route.pattern == path || (route.prefix && strings.HasPrefix(path, route.pattern)) {
+			return (route.count.Add(1)-1)%s.rate == 0
+		}
+	}
+	return true
+}
+
+// LoggerMiddleware 记录全部请求的日志
 func LoggerMiddleware() gin.HandlerFunc {
+	return SampledLoggerMiddleware(nil)
+}
+
+// SampledLoggerMiddleware 按采样器记录请求日志，sampler 为 nil 时记录全部请求
+func SampledLoggerMiddleware(sampler *RequestSampler) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
@@ -22,7 +90,11 @@ func LoggerMiddleware() gin.HandlerFunc {
 		method := c.Request.Method
 		errorMessage := c.Errors.ByType(gin.ErrorTypePrivate).String()
 
-		l := logger.GetLogger()
+		if len(errorMessage) == 0 && !sampler.ShouldLog(method, path, status, latency) {
+			return
+		}
+
+		l := logger.FromContext(c.Request.Context())
 		event := l.Info()
 		if len(errorMessage) > 0 {
 			event = l.Error()
@@ -32,6 +104,12 @@ func LoggerMiddleware() gin.HandlerFunc {
 			path = path + "?" + raw
 		}
 
+		// 模拟登录会话的请求带上发起模拟的管理员 ID
+		impersonator, impersonated := impersonatorID(c)
+		if impersonated {
+			event = event.Str("impersonator_id", impersonator.String())
+		}
+
 		event.
 			Str("method", method).
 			Str("path", path).
@@ -43,8 +121,11 @@ func LoggerMiddleware() gin.HandlerFunc {
 
 		// 5xx 错误额外记一条错误日志
 		if status >= 500 {
-			l2 := logger.GetLogger()
-			l2.Error().
+			errEvent := l.Error()
+			if impersonated {
+				errEvent = errEvent.Str("impersonator_id", impersonator.String())
+			}
+			errEvent.
 				Str("method", method).
 				Str("path", path).
 				Int("status", status).
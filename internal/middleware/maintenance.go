@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceMiddleware 维护模式：开启后返回 503（错误码 maintenance）、维护提示和 Retry-After
+// 参数:
+//   - jwtMgr: 用于识别管理员，携带有效管理员 token 的请求照常处理，便于维护期间验证修复
+//   - exempt: 不受维护模式影响的路径（完整匹配，如 /health 和维护模式开关本身）
+//
+// 注意: 维护状态来自系统设置的进程内缓存，修改后经 Redis 通知各实例刷新
+func MaintenanceMiddleware(jwtMgr *jwt.JWTManager, exempt ...string) gin.HandlerFunc {
+	exemptPaths := make(map[string]struct{}, len(exempt))
+	for _, p := range exempt {
+		exemptPaths[p] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		status := services.CurrentMaintenance()
+		if !status.Enabled {
+			c.Next()
+			return
+		}
+		if _, ok := exemptPaths[strings.TrimSuffix(c.Request.URL.Path, "/")]; ok {
+			c.Next()
+			return
+		}
+		if isAdminRequest(c, jwtMgr) {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(status.RetryAfter))
+		// 管理员设置的提示原样返回，未设置时按请求语言返回默认提示
+		if status.Message == services.DefaultMaintenanceMessage {
+			abortWithError(c, http.StatusServiceUnavailable, i18n.CodeMaintenance)
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.ErrorWithCode(http.StatusServiceUnavailable, i18n.CodeMaintenance, status.Message))
+	}
+}
+
+// isAdminRequest 请求是否携带有效的管理员 token（不写入上下文，后续仍由路由上的认证中间件处理）
+func isAdminRequest(c *gin.Context, jwtMgr *jwt.JWTManager) bool {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	claims, err := jwtMgr.ValidateToken(token)
+	return err == nil && claims.Role == string(models.RoleAdmin)
+}
@@ -3,52 +3,314 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
-	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
+// rateLimitTimeout 单次限流计数的 Redis 超时，超时后按 Redis 不可用处理
+const rateLimitTimeout = 500 * time.Millisecond
+
+// memoryBucketSweepInterval 内存令牌桶的清理间隔
+const memoryBucketSweepInterval = time.Minute
+
+// rateLimitScript 原子地检查并增加计数：未达到上限时加 1，达到上限时不再累计（被拒绝的请求不计数）
+// KEYS[1]: 计数键；ARGV[1]: 上限；ARGV[2]: 窗口（毫秒），从第一次计数开始计算
+// 返回: {是否允许(1/0), 当前计数, 计数键剩余的毫秒数}
+var rateLimitScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+local allowed = 0
+if count < limit then
+	count = redis.call('INCR', KEYS[1])
+	allowed = 1
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], window)
+	ttl = window
+end
+return {allowed, count, ttl}
+`)
+
+// RateLimiter 按 IP（或登录用户）和路径限流，限额、窗口和不限流的角色可以在运行中修改（配置热加载）
+//
+// 设计考虑：
+// - Redis 可用时多个实例共用计数，检查和累计在一个 Lua 脚本中完成，并发请求不会超出限额
+// - Redis 未初始化或读写失败时退回进程内的令牌桶（每个实例单独计数），不会因 Redis 故障而放开限流
+// - 令牌桶按 限额/窗口 的速率补充，空闲超过一个窗口的桶（已补满）定期清理
+type RateLimiter struct {
+	limit  atomic.Int64
+	window atomic.Int64
+	// perUser 登录用户按用户 ID 计数，未登录时按 IP
+	perUser atomic.Bool
+	// exemptRoles 不限流的角色
+	exemptRoles atomic.Pointer[map[string]struct{}]
+	// key 计数键中计数对象（IP 或用户）之后的部分，默认为请求路径
+	key func(c *gin.Context) string
+
+	memory memoryLimiter
+}
+
+// NewRateLimiter 创建限流器
+// 参数:
+//   - limit: 每个窗口内允许的请求数
+//   - window: 限流窗口
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	r := &RateLimiter{}
+	r.SetLimit(limit, window)
+	return r
+}
+
+// SetLimit 修改限额和窗口，对之后的请求生效（已有计数键按原窗口过期）
+func (r *RateLimiter) SetLimit(limit int, window time.Duration) {
+	r.limit.Store(int64(limit))
+	r.window.Store(int64(window))
+}
+
+// SetPerUser 设置登录用户是否按用户 ID 计数（需在认证中间件之后使用），对之后的请求生效
+func (r *RateLimiter) SetPerUser(perUser bool) {
+	r.perUser.Store(perUser)
+}
+
+// SetExemptRoles 设置不限流的角色（按认证中间件写入的 role 判断），对之后的请求生效
+func (r *RateLimiter) SetExemptRoles(roles []string) {
+	exempt := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		exempt[role] = struct{}{}
+	}
+	r.exemptRoles.Store(&exempt)
+}
+
+// WithKey 设置计数键中计数对象之后的部分（默认为请求路径），如按文章 ID 计数，避免同一资源换一种写法绕过限流
+// 返回: 限流器本身，便于链式调用
+func (r *RateLimiter) WithKey(key func(c *gin.Context) string) *RateLimiter {
+	r.key = key
+	return r
+}
+
 func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	return NewRateLimiter(limit, window).Middleware()
+}
+
+// rateLimitResult 一次限流检查的结果
+type rateLimitResult struct {
+	allowed   bool
+	remaining int
+	// reset 计数恢复的时间：Redis 计数为窗口结束时间，令牌桶为补满的时间
+	reset time.Time
+	// retryAfter 被拒绝时需要等待的时间
+	retryAfter time.Duration
+}
+
+// Middleware 返回限流中间件
+func (r *RateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		key := fmt.Sprintf("ratelimit:%s:%s", clientIP, c.Request.URL.Path)
-
-		ctx := context.Background()
-		
-		// 获取当前计数
-		count, err := database.RedisClient.Get(ctx, key).Int()
-		if err != nil && err.Error() != "redis: nil" {
+		if r.exempt(c) {
 			c.Next()
 			return
 		}
+		limit := int(r.limit.Load())
+		window := time.Duration(r.window.Load())
 
-		if count >= limit {
-			c.JSON(http.StatusTooManyRequests, models.Error(429, "too many requests"))
-			c.Abort()
-			return
+		suffix := c.Request.URL.Path
+		if r.key != nil {
+			suffix = r.key(c)
 		}
+		key := fmt.Sprintf("ratelimit:%s:%s", r.identity(c), suffix)
 
-		// 增加计数
-		pipe := database.RedisClient.Pipeline()
-		pipe.Incr(ctx, key)
-		pipe.Expire(ctx, key, window)
-		_, err = pipe.Exec(ctx)
-		if err != nil {
-			c.Next()
-			return
+		result, ok := r.takeRedis(c.Request.Context(), key, limit, window)
+		if !ok {
+			result = r.memory.take(key, limit, window, time.Now())
 		}
 
 		// 设置响应头
 		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(limit-count-1))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(window).Unix(), 10))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(result.reset.Unix(), 10))
+
+		if !result.allowed {
+			// Retry-After 为剩余秒数（向上取整，至少 1 秒）
+			c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(math.Max(result.retryAfter.Seconds(), 1))), 10))
+			abortWithError(c, http.StatusTooManyRequests, i18n.CodeTooManyRequests)
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// exempt 当前用户的角色是否不限流
+func (r *RateLimiter) exempt(c *gin.Context) bool {
+	exempt := r.exemptRoles.Load()
+	if exempt == nil {
+		return false
+	}
+	role, ok := c.Get("role")
+	if !ok {
+		return false
+	}
+	name, _ := role.(string)
+	_, ok = (*exempt)[name]
+	return ok
+}
+
+// identity 计数对象：按用户计数且已登录时为 user:<用户 ID>，否则为客户端 IP
+func (r *RateLimiter) identity(c *gin.Context) string {
+	if r.perUser.Load() {
+		if id, ok := c.Get("user_id"); ok {
+			return fmt.Sprintf("user:%v", id)
+		}
+	}
+	return c.ClientIP()
+}
+
+// RateLimitBuckets 按名称配置额度的一组限流器（rate_limit.buckets），同名路由共用一个限流器
+//
+// 设计考虑：
+// - 同一个桶内的路由合并计数（计数键为桶名而不是请求路径），如多个发送短信的接口共用额度
+// - 额度、是否按用户计数和不限流的角色都来自配置，重新加载配置后通过 SetConfig 更新已创建的限流器
+type RateLimitBuckets struct {
+	mu       sync.Mutex
+	cfg      config.RateLimitConfig
+	limiters map[string]*RateLimiter
+}
+
+// NewRateLimitBuckets 创建命名限流桶
+func NewRateLimitBuckets(cfg config.RateLimitConfig) *RateLimitBuckets {
+	return &RateLimitBuckets{cfg: cfg, limiters: make(map[string]*RateLimiter)}
+}
+
+// Middleware 返回名为 name 的限流桶的中间件，按用户计数的桶需在认证中间件（或可选认证中间件）之后使用
+func (b *RateLimitBuckets) Middleware(name string) gin.HandlerFunc {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	limiter, ok := b.limiters[name]
+	if !ok {
+		limiter = NewRateLimiter(0, 0).WithKey(func(*gin.Context) string { return "bucket:" + name })
+		b.apply(limiter, name)
+		b.limiters[name] = limiter
+	}
+	return limiter.Middleware()
+}
+
+// SetConfig 按新的配置更新所有已创建的限流器，对之后的请求生效
+func (b *RateLimitBuckets) SetConfig(cfg config.RateLimitConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = cfg
+	for name, limiter := range b.limiters {
+		b.apply(limiter, name)
+	}
+}
+
+// apply 把当前配置中名为 name 的桶应用到限流器，调用方持有锁
+func (b *RateLimitBuckets) apply(limiter *RateLimiter, name string) {
+	bucket := b.cfg.Bucket(name)
+	limiter.SetLimit(bucket.Requests, bucket.Window())
+	limiter.SetPerUser(bucket.PerUser)
+	limiter.SetExemptRoles(b.cfg.ExemptRoles)
+}
+
+// takeRedis 在 Redis 中检查并累计一次请求
+// 返回: 检查结果；Redis 未初始化或执行失败时第二个返回值为 false，由调用方退回内存令牌桶
+func (r *RateLimiter) takeRedis(ctx context.Context, key string, limit int, window time.Duration) (rateLimitResult, bool) {
+	if database.RedisClient == nil {
+		return rateLimitResult{}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, rateLimitTimeout)
+	defer cancel()
+
+	values, err := rateLimitScript.Run(ctx, database.RedisClient, []string{key}, limit, window.Milliseconds()).Int64Slice()
+	if err != nil || len(values) != 3 {
+		l := logger.FromContext(ctx)
+		l.Debug().Err(err).Msg("rate limit falls back to in-memory buckets")
+		return rateLimitResult{}, false
+	}
+	ttl := time.Duration(values[2]) * time.Millisecond
+	return rateLimitResult{
+		allowed:    values[0] == 1,
+		remaining:  max(limit-int(values[1]), 0),
+		reset:      time.Now().Add(ttl),
+		retryAfter: ttl,
+	}, true
+}
+
+// memoryBucket 进程内的令牌桶
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// memoryLimiter Redis 不可用时使用的进程内令牌桶，每个计数键一个桶
+type memoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// take 从计数键对应的令牌桶中取一个令牌，桶容量为 limit，每个 window 补满
+func (m *memoryLimiter) take(key string, limit int, window time.Duration, now time.Time) rateLimitResult {
+	if limit <= 0 || window <= 0 {
+		return rateLimitResult{allowed: limit > 0, reset: now}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweepLocked(window, now)
+
+	capacity := float64(limit)
+	// 每秒补充的令牌数
+	rate := capacity / window.Seconds()
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: capacity, last: now}
+		m.buckets[key] = bucket
+	} else {
+		bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+		bucket.last = now
+	}
+
+	seconds := func(tokens float64) time.Duration {
+		return time.Duration(tokens / rate * float64(time.Second))
+	}
+	if bucket.tokens < 1 {
+		return rateLimitResult{
+			reset:      now.Add(seconds(capacity - bucket.tokens)),
+			retryAfter: seconds(1 - bucket.tokens),
+		}
+	}
+	bucket.tokens--
+	return rateLimitResult{
+		allowed:   true,
+		remaining: int(bucket.tokens),
+		reset:     now.Add(seconds(capacity - bucket.tokens)),
+	}
+}
+
+// sweepLocked 定期清理空闲超过一个窗口的令牌桶（这些桶已补满，删除后与新建的桶相同），调用方持有锁
+func (m *memoryLimiter) sweepLocked(window time.Duration, now time.Time) {
+	if m.buckets == nil {
+		m.buckets = make(map[string]*memoryBucket)
+	}
+	if now.Sub(m.lastSweep) < memoryBucketSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, bucket := range m.buckets {
+		if now.Sub(bucket.last) >= window {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/pkg/errorreport"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware 恢复处理函数中的 panic，记录日志并上报（带请求 ID、用户 ID 和路由），返回 500
+// 注意: 客户端断开（http.ErrAbortHandler）时不上报，直接中断请求
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				c.Abort()
+				return
+			}
+
+			stack := debug.Stack()
+			ctx := c.Request.Context()
+			errorreport.CapturePanic(ctx, recovered, stack)
+
+			l := logger.FromContext(errorreport.WithoutLogReport(ctx))
+			l.Error().
+				Interface("panic", recovered).
+				Str("stack", string(stack)).
+				Msg("Panic recovered")
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			abortWithError(c, http.StatusInternalServerError, i18n.CodeInternal)
+		}()
+		c.Next()
+	}
+}
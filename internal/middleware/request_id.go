@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 请求 ID 的请求头 / 响应头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 接受的上游请求 ID 最大长度，超过或含非法字符时重新生成
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware 为每个请求分配请求 ID，写入响应头、gin 上下文（request_id）和请求上下文（供 logger.FromContext 使用）；
+// JSON 错误响应（4xx/5xx）的响应体中同时带上 request_id 字段，便于用户反馈问题时与服务端日志对应
+// 注意: 上游（如网关）已传入合法的 X-Request-ID 时沿用，便于跨服务关联日志
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		ctx := logger.WithRequestID(c.Request.Context(), requestID)
+		if route := c.FullPath(); route != "" {
+			ctx = logger.WithRoute(ctx, route)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &requestIDWriter{ResponseWriter: c.Writer, requestID: requestID}
+
+		c.Next()
+	}
+}
+
+// requestIDWriter 在 JSON 错误响应体的最前面插入 request_id 字段
+// 错误响应都由 c.JSON 一次写出，因此只处理第一次写入，不需要缓冲响应体
+type requestIDWriter struct {
+	gin.ResponseWriter
+	requestID string
+	written   bool
+}
+
+// Write 第一次写入 4xx/5xx 的 JSON 对象时插入 request_id，其余原样写出
+func (w *requestIDWriter) Write(data []byte) (int, error) {
+	if w.written {
+		return w.ResponseWriter.Write(data)
+	}
+	w.written = true
+	if w.Status() < 400 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(data)
+	}
+	body := bytes.TrimLeft(data, " \t\r\n")
+	if len(body) == 0 || body[0] != '{' {
+		return w.ResponseWriter.Write(data)
+	}
+
+	id, _ := json.Marshal(w.requestID)
+	field := append(append([]byte(`{"request_id":`), id...), ',')
+	if rest := bytes.TrimLeft(body[1:], " \t\r\n"); len(rest) > 0 && rest[0] == '}' {
+		field = field[:len(field)-1]
+	}
+	if _, err := w.ResponseWriter.Write(append(field, body[1:]...)); err != nil {
+		return 0, err
+	}
+	// 按调用方写入的长度返回，避免被当作短写
+	return len(data), nil
+}
+
+// WriteString 同 Write
+func (w *requestIDWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// validRequestID 只接受长度合理的可打印 ASCII 字符，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
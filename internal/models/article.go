@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql/driver"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,15 +16,18 @@ const (
 	StatusReview       ArticleStatus = "review"
 	StatusPublished    ArticleStatus = "published"
 	StatusArchived     ArticleStatus = "archived"
+	StatusScheduled    ArticleStatus = "scheduled"
 )
 
 type Article struct {
 	ID           uuid.UUID     `json:"id" db:"id"`
 	Title        string        `json:"title" db:"title"`
-	Slug         string        `json:"slug" db:"slug"`
+	Slug         string        `json:"slug" db:"slug" gorm:"uniqueIndex:articles_slug_key"`
 	Content      string        `json:"content" db:"content"`
 	Excerpt      string        `json:"excerpt" db:"excerpt"`
 	CoverImage   string        `json:"cover_image" db:"cover_image"`
+	// MetaDescription SEO 描述，为空时使用摘要
+	MetaDescription string     `json:"meta_description" db:"meta_description" gorm:"type:varchar(300);not null;default:''"`
 	Status       ArticleStatus `json:"status" db:"status"`
 	AuthorID     uuid.UUID     `json:"author_id" db:"author_id"`
 	Author       *User         `json:"author,omitempty"`
@@ -34,42 +38,212 @@ type Article struct {
 	ViewCount    int           `json:"view_count" db:"view_count"`
 	LikeCount    int           `json:"like_count" db:"like_count"`
 	CommentCount int           `json:"comment_count" db:"comment_count"`
+	// WordCount 正文字数（中日韩文字按字、其他文字按单词计），ReadingTimeMinutes 预计阅读分钟数，保存时计算
+	WordCount          int `json:"word_count" db:"word_count" gorm:"not null;default:0"`
+	ReadingTimeMinutes int `json:"reading_time_minutes" db:"reading_time_minutes" gorm:"not null;default:0"`
 	PublishedAt  *time.Time    `json:"published_at,omitempty" db:"published_at"`
+	// ScheduledAt 定时发布时间，状态为 scheduled 时到达该时间后自动发布
+	ScheduledAt  *time.Time    `json:"scheduled_at,omitempty" db:"scheduled_at"`
 	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
 	DeletedAt    *time.Time    `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Version 乐观锁版本号，每次更新 +1；更新时需回传读取到的版本号
+	Version      int           `json:"version" db:"version" gorm:"default:1"`
+	// CommentsMeta 已通过评论的统计，仅列表请求 include=comments_meta 时加载
+	CommentsMeta *ArticleCommentsMeta `json:"comments_meta,omitempty" gorm:"-"`
+	// Liked 当前用户（匿名用户按 IP）是否已点赞，仅文章详情返回
+	Liked *bool `json:"liked,omitempty" gorm:"-"`
 }
 
+// ArticleCommentsMeta 文章已通过评论的数量和最新评论时间（文章卡片展示“12 条评论 · 最后回复 3 小时前”）
+type ArticleCommentsMeta struct {
+	ApprovedCount int64 `json:"approved_count"`
+	// LastCommentAt 最新一条已通过评论的时间，没有评论时为 null
+	LastCommentAt *time.Time `json:"last_comment_at"`
+}
+
+// ArticleLike 文章点赞记录，同一用户（匿名用户按 IP 指纹）对同一篇文章只有一条
+type ArticleLike struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	ArticleID uuid.UUID  `json:"article_id" db:"article_id" gorm:"uniqueIndex:idx_article_likes_user,priority:1;uniqueIndex:idx_article_likes_fingerprint,priority:1"`
+	UserID    *uuid.UUID `json:"user_id,omitempty" db:"user_id" gorm:"uniqueIndex:idx_article_likes_user,priority:2"`
+	// Fingerprint 匿名点赞者 IP 的 SHA-256，不保存原始 IP
+	Fingerprint *string   `json:"-" db:"fingerprint" gorm:"type:varchar(64);uniqueIndex:idx_article_likes_fingerprint,priority:2"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ArticleSlugRedirect 文章修改 slug 后保留的旧 slug，按旧 slug 访问时解析到文章
+type ArticleSlugRedirect struct {
+	OldSlug   string    `json:"old_slug" db:"old_slug" gorm:"primaryKey;type:varchar(200)"`
+	ArticleID uuid.UUID `json:"article_id" db:"article_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func (ArticleSlugRedirect) TableName() string { return "article_slug_redirects" }
+
+// ArticleLiker 点赞者：登录用户为 UserID，匿名用户为 Fingerprint（二者只设置其一）
+type ArticleLiker struct {
+	UserID      *uuid.UUID
+	Fingerprint string
+}
+
+// ArticleStatesRequest 批量查询当前用户对文章的状态（列表页标记已点赞的文章）
+type ArticleStatesRequest struct {
+	// ArticleIDs 文章 ID，最多 100 个；不是合法 UUID 的条目在结果的 invalid_ids 中单独列出
+	ArticleIDs []string `json:"article_ids" binding:"required,min=1,max=100"`
+}
+
+// ArticleState 当前用户对一篇文章的状态
+type ArticleState struct {
+	Liked bool `json:"liked"`
+	// Bookmarked 是否已收藏；服务端尚未保存收藏（目前收藏只保存在前端本地），始终为 false
+	Bookmarked bool `json:"bookmarked"`
+}
+
+// ArticleStatesResult 批量查询文章状态的结果
+type ArticleStatesResult struct {
+	// States 按文章 ID 索引，请求中每个合法的 ID 都有对应条目（文章不存在时各项为 false）
+	States map[uuid.UUID]*ArticleState `json:"states"`
+	// InvalidIDs 不是合法 UUID 的输入，原样返回
+	InvalidIDs []string `json:"invalid_ids"`
+}
+
+// ArticleIncludeCommentsMeta 文章列表 include 参数：附带已通过评论的统计
+const ArticleIncludeCommentsMeta = "comments_meta"
+
 type ArticleCreate struct {
 	Title      string        `json:"title" validate:"required,min=1,max=200"`
 	Content    string        `json:"content" validate:"required"`
 	Excerpt    string        `json:"excerpt"`
 	CoverImage string        `json:"cover_image"`
-	Status     ArticleStatus `json:"status"`
+	MetaDescription string   `json:"meta_description" validate:"max=300"`
+	Status     ArticleStatus `json:"status" validate:"omitempty,article_status"`
+	// ScheduledAt 定时发布时间，status 为 scheduled 时必填，已过去的时间管理员立即发布，其他用户改为待审核
+	ScheduledAt *time.Time   `json:"scheduled_at"`
 	CategoryID *uuid.UUID    `json:"category_id"`
 	TagIDs     []uuid.UUID   `json:"tag_ids"`
 }
 
 type ArticleUpdate struct {
 	Title      *string        `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	// Slug 新的 slug（按 GenerateSlug 规范化），为空字符串时按标题重新生成；不传时保持不变（修改标题不影响 slug）
+	Slug       *string        `json:"slug,omitempty" validate:"omitempty,max=200"`
 	Content    *string        `json:"content,omitempty"`
 	Excerpt    *string        `json:"excerpt,omitempty"`
 	CoverImage *string        `json:"cover_image,omitempty"`
-	Status     *ArticleStatus `json:"status,omitempty"`
+	MetaDescription *string   `json:"meta_description,omitempty" validate:"omitempty,max=300"`
+	Status     *ArticleStatus `json:"status,omitempty" validate:"omitempty,article_status"`
+	// ScheduledAt 定时发布时间（修改定时发布的文章时可单独修改），规则同 ArticleCreate
+	ScheduledAt *time.Time    `json:"scheduled_at,omitempty"`
 	CategoryID *uuid.UUID     `json:"category_id,omitempty"`
+	// TagIDs 不传时不修改标签；传入时替换全部标签，空数组表示清除
 	TagIDs     []uuid.UUID    `json:"tag_ids,omitempty"`
+	// StatusReason 状态变更说明（如审核退回原因），状态改变时记录在状态历史中
+	StatusReason *string `json:"status_reason,omitempty"`
+	// ExpectedVersion 客户端读取到的版本号，也可以通过 If-Match 请求头传入
+	ExpectedVersion *int `json:"expected_version,omitempty"`
+}
+
+// ArticleStatusUpdate 管理后台只修改文章状态的请求体
+type ArticleStatusUpdate struct {
+	Status ArticleStatus `json:"status" validate:"required,article_status"`
+	// Reason 状态变更说明，记录在状态历史中
+	Reason string `json:"reason"`
+	// ExpectedVersion 期望的版本号，也可以通过 If-Match 请求头传入；都未提供时以服务端当前版本为准
+	ExpectedVersion *int `json:"expected_version"`
+}
+
+// ArticleMeta 文章的 SEO 字段，SSR 前端渲染 head 标签使用（不含正文）
+type ArticleMeta struct {
+	Title           string     `json:"title"`
+	MetaDescription string     `json:"meta_description"`
+	OGImage         string     `json:"og_image"`
+	Canonical       string     `json:"canonical"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ArchiveMonth 归档中的一个月份及该月发布的文章数
+type ArchiveMonth struct {
+	Year  int   `json:"year"`
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// ArticleHTMLExport 文章的独立 HTML 文档（阅读 / 打印视图）
+type ArticleHTMLExport struct {
+	// Filename 下载文件名（slug.html）
+	Filename string
+	HTML     []byte
+}
+
+// ArticleSummary 生成的摘要建议（不保存），Generated 为 false 表示摘要服务不可用，Excerpt 为截取正文得到的摘要
+type ArticleSummary struct {
+	Excerpt         string `json:"excerpt"`
+	MetaDescription string `json:"meta_description"`
+	Generated       bool   `json:"generated"`
 }
 
 type ArticleQuery struct {
 	Page       int           `form:"page"`
 	PageSize   int           `form:"page_size"`
 	Status     ArticleStatus `form:"status"`
-	CategoryID *uuid.UUID    `form:"category_id"`
-	TagID      *uuid.UUID    `form:"tag_id"`
-	AuthorID   *uuid.UUID    `form:"author_id"`
+	// CategoryID、TagID、AuthorID 对应 category_id、tag_id、author_id 查询参数
+	// gin 无法绑定 uuid.UUID，由 handlers 在绑定后单独解析
+	CategoryID *uuid.UUID    `form:"-"`
+	TagID      *uuid.UUID    `form:"-"`
+	AuthorID   *uuid.UUID    `form:"-"`
 	Search     string        `form:"search"`
 	SortBy     string        `form:"sort_by"`
 	Order      string        `form:"order"`
+	// 创建时间 / 发布时间范围（含首尾），对应 created_from、created_to、published_from、published_to 查询参数
+	// 参数可以是 RFC3339 时间或 YYYY-MM-DD 日期（整天），由 handlers 使用 ParseTimeBound 解析；起点晚于终点时结果为空
+	CreatedFrom   *time.Time `form:"-"`
+	CreatedTo     *time.Time `form:"-"`
+	PublishedFrom *time.Time `form:"-"`
+	PublishedTo   *time.Time `form:"-"`
+	// 按发布时间的年份 / 月份筛选（归档页面使用），指定月份时必须同时指定年份
+	Year  int `form:"year" binding:"required_with=Month,omitempty,min=1,max=9999"`
+	Month int `form:"month" binding:"omitempty,min=1,max=12"`
+	// Include 列表附带的额外数据，逗号分隔，目前支持 comments_meta
+	Include string `form:"include"`
+}
+
+// ParseTimeBound 解析时间范围的起点或终点
+// value: RFC3339 时间（如 2024-05-01T08:00:00+08:00）或 YYYY-MM-DD 日期
+// end: 是否为终点；日期作为终点时取当天最后一刻，使范围包含整天
+func ParseTimeBound(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// Includes 判断 include 参数中是否包含 name
+func (q ArticleQuery) Includes(name string) bool {
+	for _, v := range strings.Split(q.Include, ",") {
+		if strings.TrimSpace(v) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid 是否为已定义的文章状态
+func (s ArticleStatus) Valid() bool {
+	switch s {
+	case StatusDraft, StatusReview, StatusPublished, StatusArchived, StatusScheduled:
+		return true
+	default:
+		return false
+	}
 }
 
 func (s ArticleStatus) Value() (driver.Value, error) {
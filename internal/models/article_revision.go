@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"enterprise-blog/pkg/textdiff"
+
+	"github.com/google/uuid"
+)
+
+// ArticleRevisionMeta 文章修订记录的元数据（修订列表使用，不含正文）
+type ArticleRevisionMeta struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ArticleID uuid.UUID `json:"article_id" db:"article_id" gorm:"index:idx_article_revisions_article_created,priority:1"`
+	// Version 快照时文章的版本号
+	Version int           `json:"version" db:"version"`
+	Title   string        `json:"title" db:"title"`
+	Status  ArticleStatus `json:"status" db:"status"`
+	// EditorID 进行这次修改的用户，即用新内容覆盖此快照的人
+	EditorID *uuid.UUID `json:"editor_id" db:"editor_id"`
+	// EditorName 修改者的用户名（查询时关联得到，不存储）
+	EditorName string `json:"editor_name" db:"editor_name" gorm:"-:migration;->"`
+	// CreatedAt 快照时间，即这次修改的时间
+	CreatedAt time.Time `json:"created_at" db:"created_at" gorm:"index:idx_article_revisions_article_created,priority:2"`
+}
+
+// ArticleRevision 文章修订记录：修改文章前保存的标题、正文、摘要和状态
+type ArticleRevision struct {
+	ArticleRevisionMeta
+	Content string `json:"content" db:"content"`
+	Excerpt string `json:"excerpt" db:"excerpt"`
+}
+
+func (ArticleRevision) TableName() string { return "article_revisions" }
+
+// ArticleRevisionDiff 两条修订记录之间标题、摘要和正文的按词差异
+// 依次拼接 equal 和 delete 片段得到 from 的内容，拼接 equal 和 insert 片段得到 to 的内容
+type ArticleRevisionDiff struct {
+	From    ArticleRevisionMeta `json:"from"`
+	To      ArticleRevisionMeta `json:"to"`
+	Title   []textdiff.Hunk     `json:"title"`
+	Excerpt []textdiff.Hunk     `json:"excerpt"`
+	Content []textdiff.Hunk     `json:"content"`
+}
+
+// ArticleRevisionDiffHTML 差异的 HTML 形式（format=html）：新增包裹在 <ins> 中，删除包裹在 <del> 中，其余文本全部转义
+type ArticleRevisionDiffHTML struct {
+	From    ArticleRevisionMeta `json:"from"`
+	To      ArticleRevisionMeta `json:"to"`
+	Title   string              `json:"title"`
+	Excerpt string              `json:"excerpt"`
+	Content string              `json:"content"`
+}
+
+// HTML 把差异渲染为 HTML
+func (d *ArticleRevisionDiff) HTML() *ArticleRevisionDiffHTML {
+	return &ArticleRevisionDiffHTML{
+		From:    d.From,
+		To:      d.To,
+		Title:   textdiff.HTML(d.Title),
+		Excerpt: textdiff.HTML(d.Excerpt),
+		Content: textdiff.HTML(d.Content),
+	}
+}
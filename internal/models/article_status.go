@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticleStatusChange 文章状态变更记录
+type ArticleStatusChange struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ArticleID uuid.UUID `json:"article_id" db:"article_id" gorm:"index:idx_article_status_history_article_created,priority:1"`
+	// FromStatus 变更前的状态，创建文章时为空
+	FromStatus *ArticleStatus `json:"from_status" db:"from_status"`
+	ToStatus   ArticleStatus  `json:"to_status" db:"to_status"`
+	ActorID    *uuid.UUID     `json:"actor_id" db:"actor_id"`
+	// ActorName 操作者的用户名（查询时关联得到，不存储）
+	ActorName string `json:"actor_name" db:"actor_name" gorm:"-:migration;->"`
+	// Reason 退回原因等说明
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" gorm:"index:idx_article_status_history_article_created,priority:2;index"`
+}
+
+func (ArticleStatusChange) TableName() string { return "article_status_history" }
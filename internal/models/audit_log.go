@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog 管理后台操作审计日志
+type AuditLog struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	ActorID        *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`
+	ActorUsername  string          `json:"actor_username,omitempty" db:"actor_username" gorm:"-:migration"`
+	ImpersonatorID *uuid.UUID      `json:"impersonator_id,omitempty" db:"impersonator_id"` // 模拟登录时发起模拟的管理员
+	Method         string          `json:"method" db:"method"`
+	Path           string          `json:"path" db:"path"`
+	Action         string          `json:"action" db:"action"`
+	ResourceType   string          `json:"resource_type,omitempty" db:"resource_type"`
+	ResourceID     string          `json:"resource_id,omitempty" db:"resource_id"`
+	StatusCode     int             `json:"status_code" db:"status_code"`
+	IP             string          `json:"ip" db:"ip"`
+	UserAgent      string          `json:"user_agent,omitempty" db:"user_agent"`
+	Payload        json.RawMessage `json:"payload,omitempty" db:"payload"` // 已脱敏的请求体，仅详情接口返回
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditLogQuery 审计日志查询条件
+type AuditLogQuery struct {
+	Page       int        `form:"page"`
+	PageSize   int        `form:"page_size"`
+	ActorID    *uuid.UUID `form:"actor_id"`
+	PathPrefix string     `form:"path_prefix"`
+	Method     string     `form:"method"`
+	From       *time.Time `form:"from" time_format:"2006-01-02"`
+	To         *time.Time `form:"to" time_format:"2006-01-02"`
+}
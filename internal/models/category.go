@@ -9,12 +9,22 @@ import (
 type Category struct {
 	ID          uuid.UUID  `json:"id" db:"id"`
 	Name        string     `json:"name" db:"name"`
-	Slug        string     `json:"slug" db:"slug"`
+	Slug        string     `json:"slug" db:"slug" gorm:"uniqueIndex:uniq_categories_slug_lower,expression:LOWER(slug),where:deleted_at IS NULL"`
 	Description string     `json:"description" db:"description"`
 	ParentID    *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
 	Order       int        `json:"order" db:"order"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// ArticleCount 分类下的文章数（只读，仅列表接口返回）
+	ArticleCount *int64 `json:"article_count,omitempty" db:"article_count" gorm:"-:migration"`
+}
+
+// CategoryNode 分类树节点，children 按 order、名称排序
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
 }
 
 type CategoryCreate struct {
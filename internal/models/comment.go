@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// 评论状态
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusReported = "reported"
+)
+
 type Comment struct {
 	ID        uuid.UUID  `json:"id" db:"id"`
 	ArticleID uuid.UUID  `json:"article_id" db:"article_id"`
@@ -20,6 +28,10 @@ type Comment struct {
 	Status    string     `json:"status" db:"status"`
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	// DeletedAt 软删除时间；随文章一起删除的评论与文章的删除时间相同，恢复文章时据此一并恢复
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at" gorm:"index"`
+	// Replies 回复列表（只在文章评论列表中填充）：只有一层，回复的回复也归到最上层的父评论下，按创建时间正序
+	Replies []*Comment `json:"replies,omitempty" gorm:"-"`
 }
 
 type CommentCreate struct {
@@ -27,12 +39,21 @@ type CommentCreate struct {
 	ParentID  *uuid.UUID `json:"parent_id"`
 	Content   string     `json:"content" validate:"required,min=1"`
 	Author    string     `json:"author" validate:"required"`
-	Email     string     `json:"email" validate:"required,email"`
+	// Email 选填（匿名评论可以不留邮箱），填写时必须是合法邮箱
+	Email     string     `json:"email" validate:"omitempty,email"`
 	Website   string     `json:"website"`
 }
 
+// CommentQuery 管理后台评论列表的查询条件
+type CommentQuery struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+	// Status 按评论状态筛选（pending、approved、rejected、reported），为空时不筛选
+	Status string `form:"status"`
+}
+
 type CommentUpdate struct {
 	Content *string `json:"content,omitempty" validate:"omitempty,min=1"`
-	Status  *string `json:"status,omitempty"`
+	Status  *string `json:"status,omitempty" validate:"omitempty,oneof=pending approved rejected"`
 }
 
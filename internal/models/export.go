@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportJobStatus 异步导出任务状态
+type ExportJobStatus string
+
+const (
+	ExportJobPending ExportJobStatus = "pending"
+	ExportJobRunning ExportJobStatus = "running"
+	ExportJobDone    ExportJobStatus = "done"
+	ExportJobFailed  ExportJobStatus = "failed"
+)
+
+// ExportJob 异步导出任务（数据量超过同步导出上限时创建）
+type ExportJob struct {
+	ID         uuid.UUID       `json:"id"`
+	Kind       string          `json:"kind"` // users / articles
+	Status     ExportJobStatus `json:"status"`
+	Rows       int64           `json:"rows"`
+	FileName   string          `json:"file_name"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 功能开关（定义和默认值见 internal/flags）
+const (
+	FlagNewSearch       = "new_search"
+	FlagCommentMarkdown = "comment_markdown"
+	FlagCookieAuth      = "cookie_auth"
+)
+
+// 功能开关状态来源
+const (
+	FlagSourceDefault  = "default"  // 代码中的默认值
+	FlagSourceConfig   = "config"   // 配置文件或 FEATURE_FLAGS 环境变量
+	FlagSourceDatabase = "database" // 管理后台修改（feature_flags 表）
+)
+
+// FeatureFlag 管理后台对功能开关的覆盖值
+type FeatureFlag struct {
+	Name           string     `json:"name" db:"name" gorm:"primaryKey"`
+	Enabled        bool       `json:"enabled" db:"enabled"`
+	RolloutPercent int        `json:"rollout_percent" db:"rollout_percent"`
+	UpdatedBy      *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// FeatureFlagStatus 功能开关当前生效的状态
+type FeatureFlagStatus struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	// RolloutPercent 开启时按用户 ID 哈希灰度的百分比，100 表示全部用户
+	RolloutPercent int        `json:"rollout_percent"`
+	Source         string     `json:"source"`
+	UpdatedBy      *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// FeatureFlagChange 单个功能开关的修改，未提供的字段保持当前值
+type FeatureFlagChange struct {
+	Enabled        *bool `json:"enabled"`
+	RolloutPercent *int  `json:"rollout_percent"`
+}
+
+// FeatureFlagsUpdate 批量修改功能开关请求
+type FeatureFlagsUpdate struct {
+	Flags map[string]FeatureFlagChange `json:"flags" binding:"required"`
+}
@@ -21,7 +21,7 @@ type Image struct {
 	UploaderID  uuid.UUID  `json:"uploader_id" db:"uploader_id"` // 上传者ID
 	Uploader    *User      `json:"uploader,omitempty"`        // 上传者信息
 	Description string     `json:"description" db:"description"` // 图片描述
-	Tags        []string   `json:"tags" db:"tags"`            // 标签（JSON数组）
+	Tags        []string   `json:"tags" db:"tags" gorm:"serializer:json"` // 标签（JSON数组）
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
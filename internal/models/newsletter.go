@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 订阅者状态
+const (
+	SubscriberPending      = "pending"
+	SubscriberConfirmed    = "confirmed"
+	SubscriberUnsubscribed = "unsubscribed"
+	SubscriberBounced      = "bounced" // 发信时收到永久性错误，不再发送
+)
+
+// 群发类型
+const (
+	CampaignKindArticle = "article" // 单篇文章
+	CampaignKindDigest  = "digest"  // 最近 N 天发布的文章摘要
+)
+
+// 群发状态
+const (
+	CampaignSending = "sending"
+	CampaignSent    = "sent"
+	CampaignFailed  = "failed"
+)
+
+// Subscriber 邮件订阅者
+// 注意: 邮箱只在管理后台返回，公开接口不返回订阅者信息
+type Subscriber struct {
+	ID    uuid.UUID `json:"id" db:"id"`
+	Email string    `json:"email" db:"email" gorm:"uniqueIndex:subscribers_email_key"`
+	// Status pending / confirmed / unsubscribed / bounced
+	Status           string     `json:"status" db:"status" gorm:"index"`
+	ConfirmTokenHash *string    `json:"-" db:"confirm_token_hash" gorm:"index"`
+	ConfirmExpiresAt *time.Time `json:"-" db:"confirm_expires_at"`
+	IPAddress        string     `json:"ip_address" db:"ip_address"` // 提交订阅时的 IP
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	UnsubscribedAt   *time.Time `json:"unsubscribed_at,omitempty" db:"unsubscribed_at"`
+	BouncedAt        *time.Time `json:"bounced_at,omitempty" db:"bounced_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// SubscribeRequest 订阅请求
+type SubscribeRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// NewsletterCampaign 一次邮件群发
+type NewsletterCampaign struct {
+	ID      uuid.UUID `json:"id" db:"id"`
+	Subject string    `json:"subject" db:"subject"`
+	// Kind article / digest
+	Kind       string     `json:"kind" db:"kind"`
+	ArticleID  *uuid.UUID `json:"article_id,omitempty" db:"article_id"`
+	DigestDays int        `json:"digest_days,omitempty" db:"digest_days"`
+	// Status sending / sent / failed
+	Status string `json:"status" db:"status"`
+	// Recipients 开始发送时已确认的订阅者数，SentCount / FailedCount / BouncedCount 随发送进度更新
+	Recipients   int        `json:"recipients" db:"recipients"`
+	SentCount    int        `json:"sent_count" db:"sent_count"`
+	FailedCount  int        `json:"failed_count" db:"failed_count"`
+	BouncedCount int        `json:"bounced_count" db:"bounced_count"`
+	Error        string     `json:"error,omitempty" db:"error"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at" gorm:"index"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// CampaignCreate 创建群发请求
+// Kind 为 article 时必须提供 ArticleID（已发布的文章）；为 digest 时汇总最近 DigestDays 天（默认 7）发布的文章
+// Subject 为空时使用文章标题或默认的摘要标题
+type CampaignCreate struct {
+	Kind       string     `json:"kind" binding:"required"`
+	Subject    string     `json:"subject"`
+	ArticleID  *uuid.UUID `json:"article_id"`
+	DigestDays int        `json:"digest_days"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 通知类型
+const (
+	NotificationCommentOnArticle = "comment_on_article" // 自己的文章有新评论（评论审核通过后）
+	NotificationCommentReply     = "comment_reply"      // 自己的评论被回复（回复审核通过后）
+	NotificationArticleApproved  = "article_approved"   // 提交审核的文章已发布
+	NotificationArticleRejected  = "article_rejected"   // 提交审核的文章被退回
+)
+
+// 通知对象类型
+const (
+	NotificationSubjectArticle = "article"
+	NotificationSubjectComment = "comment"
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	RecipientID uuid.UUID  `json:"recipient_id" db:"recipient_id" gorm:"index:idx_notifications_recipient_created,priority:1"`
+	Type        string     `json:"type" db:"type"`
+	ActorID     *uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName   string     `json:"actor_name,omitempty" db:"actor_name"`
+	SubjectType string     `json:"subject_type" db:"subject_type"`
+	SubjectID   uuid.UUID  `json:"subject_id" db:"subject_id"`
+	ArticleID   *uuid.UUID `json:"article_id,omitempty" db:"article_id"`
+	Summary     string     `json:"summary" db:"summary"` // 文章标题或评论内容摘要
+	ReadAt      *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at" gorm:"index:idx_notifications_recipient_created,priority:2"`
+}
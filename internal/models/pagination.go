@@ -0,0 +1,41 @@
+package models
+
+import "math"
+
+// MaxPageSize 列表接口单页条数的硬上限，配置的分页上限（pagination.*.max）不能超过它
+const MaxPageSize = 100
+
+// DefaultPageSize 没有资源分页策略可用时（如 Paginated 兜底）的每页条数
+const DefaultPageSize = 20
+
+// NormalizePage 规范化分页参数，上限为 MaxPageSize
+// page 小于 1 时取 1；pageSize 小于 1 时取 defaultPageSize，超过 MaxPageSize 时取 MaxPageSize；
+// page 过大导致偏移量溢出时收敛到不溢出的最大页码
+// 返回: 规范化后的 page、pageSize
+func NormalizePage(page, pageSize, defaultPageSize int) (int, int) {
+	return NormalizePageWithin(page, pageSize, defaultPageSize, MaxPageSize)
+}
+
+// NormalizePageWithin 规范化分页参数，规则同 NormalizePage，上限为 maxPageSize
+// 注意: maxPageSize 小于 1 或超过 MaxPageSize 时按 MaxPageSize 处理
+func NormalizePageWithin(page, pageSize, defaultPageSize, maxPageSize int) (int, int) {
+	if maxPageSize < 1 || maxPageSize > MaxPageSize {
+		maxPageSize = MaxPageSize
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	if page < 1 {
+		page = 1
+	}
+	if maxPage := math.MaxInt32/pageSize + 1; page > maxPage {
+		page = maxPage
+	}
+	return page, pageSize
+}
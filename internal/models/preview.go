@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticlePreviewTokenRequest 生成草稿预览 token 的请求
+// ExpiresIn 有效期（秒），缺省 24 小时，最长 7 天
+type ArticlePreviewTokenRequest struct {
+	ExpiresIn int `json:"expires_in" binding:"omitempty,min=60,max=604800"`
+}
+
+// ArticlePreviewToken 生成的草稿预览 token
+// JTI 用于吊销（DELETE /articles/:id/preview-token/:jti）
+type ArticlePreviewToken struct {
+	Token     string    `json:"token"`
+	JTI       uuid.UUID `json:"jti"`
+	ArticleID uuid.UUID `json:"article_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
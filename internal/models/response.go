@@ -1,9 +1,11 @@
 package models
 
 type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// ErrorCode 机器可读的错误码（见 i18n 包），不随语言变化；Message 按请求语言本地化
+	ErrorCode string      `json:"error_code,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
 }
 
 type PaginationResponse struct {
@@ -28,6 +30,15 @@ func Success(data interface{}) *Response {
 	}
 }
 
+// Created 资源创建成功的响应，code 与 HTTP 状态码 201 一致
+func Created(data interface{}) *Response {
+	return &Response{
+		Code:    201,
+		Message: "success",
+		Data:    data,
+	}
+}
+
 func SuccessWithMessage(message string, data interface{}) *Response {
 	return &Response{
 		Code:    200,
@@ -43,12 +54,35 @@ func Error(code int, message string) *Response {
 	}
 }
 
+// ErrorWithCode 带错误码的错误响应
+// errorCode: 机器可读的错误码，message: 已按请求语言本地化的消息
+func ErrorWithCode(code int, errorCode, message string) *Response {
+	return &Response{
+		Code:      code,
+		Message:   message,
+		ErrorCode: errorCode,
+	}
+}
+
+// ErrorWithData 错误响应，附带便于客户端处理的数据（例如冲突的详细计数）
+func ErrorWithData(code int, message string, data interface{}) *Response {
+	return &Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// Paginated 分页响应
+// 注意: handler 应先用 config.NormalizePage 规范化参数并传入实际查询使用的值；这里仍做兜底，
+// page 小于 1 时取 1，page_size 小于 1 时取 DefaultPageSize、超过 MaxPageSize 时取 MaxPageSize，避免除零和返回 0 值
 func Paginated(data interface{}, page, pageSize int, total int64) *PaginationResponse {
-	totalPage := int(total) / pageSize
-	if int(total)%pageSize > 0 {
+	page, pageSize = NormalizePage(page, pageSize, DefaultPageSize)
+	totalPage := int(total / int64(pageSize))
+	if total%int64(pageSize) > 0 {
 		totalPage++
 	}
-	
+
 	return &PaginationResponse{
 		Code:    200,
 		Message: "success",
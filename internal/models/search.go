@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReindexJobState 搜索索引重建任务状态
+type ReindexJobState string
+
+const (
+	ReindexJobRunning   ReindexJobState = "running"
+	ReindexJobCompleted ReindexJobState = "completed"
+	ReindexJobFailed    ReindexJobState = "failed"
+)
+
+// ReindexJob 搜索索引重建任务（状态保存在 Redis 中，任意实例均可查询）
+type ReindexJob struct {
+	ID         uuid.UUID       `json:"id"`
+	State      ReindexJobState `json:"state"`
+	Since      *time.Time      `json:"since,omitempty"`
+	Total      int64           `json:"total"`
+	Processed  int64           `json:"processed"`
+	Failed     int64           `json:"failed"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
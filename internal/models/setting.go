@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettingType 设置值类型
+type SettingType string
+
+const (
+	SettingTypeBool   SettingType = "bool"
+	SettingTypeInt    SettingType = "int"
+	SettingTypeString SettingType = "string"
+)
+
+// 可在运行时调整的设置项
+const (
+	SettingCommentModeration     = "comment_moderation"
+	SettingRegistrationMode      = "registration_mode"
+	SettingExcerptLength         = "excerpt_length"
+	SettingArticleDetailCacheTTL = "article_detail_cache_ttl"
+	SettingArticleListCacheTTL   = "article_list_cache_ttl"
+	SettingAuditLogRetentionDays = "audit_log_retention_days"
+	SettingContentFilterPolicy   = "content_filter_policy"
+	SettingContentFilterWords    = "content_filter_words"
+	SettingAutoSummary           = "auto_summary"
+	SettingMaintenanceMode       = "maintenance_mode"
+	SettingMaintenanceMessage    = "maintenance_message"
+	SettingMaintenanceRetryAfter = "maintenance_retry_after"
+)
+
+// 注册模式
+const (
+	RegistrationOpen   = "open"
+	RegistrationClosed = "closed"
+)
+
+// 敏感词命中后的处理方式
+const (
+	// ContentFilterReject 拒绝提交，返回命中的分类
+	ContentFilterReject = "reject"
+	// ContentFilterReplace 将命中的词替换为等长的 *
+	ContentFilterReplace = "replace"
+	// ContentFilterReview 保存内容但转入人工审核（文章为待审核，评论为待审）
+	ContentFilterReview = "review"
+)
+
+// Setting 系统设置项（值统一以字符串存储，按 Type 解析）
+type Setting struct {
+	Key       string      `json:"key" db:"key" gorm:"primaryKey"`
+	Value     string      `json:"value" db:"value"`
+	Type      SettingType `json:"type" db:"type"`
+	UpdatedBy *uuid.UUID  `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// SettingsUpdate 批量更新设置请求，值需与设置项类型一致
+type SettingsUpdate struct {
+	Settings map[string]interface{} `json:"settings" binding:"required"`
+}
+
+// MaintenanceUpdate 开关维护模式请求，未提供的提示信息和重试间隔保持不变
+type MaintenanceUpdate struct {
+	Enabled    *bool   `json:"enabled" binding:"required"`
+	Message    *string `json:"message"`
+	RetryAfter *int    `json:"retry_after"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SitemapEntry 站点地图中的一条记录，只包含生成地址所需的字段
+// 文章使用 ID（与 canonical 地址一致），分类和标签使用 Slug
+type SitemapEntry struct {
+	ID        uuid.UUID `json:"id,omitempty" db:"id"`
+	Slug      string    `json:"slug,omitempty" db:"slug"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
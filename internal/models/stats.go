@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminDashboardData 仪表盘统计数据
+type AdminDashboardData struct {
+	TotalUsers          int64 `json:"total_users" db:"total_users"`
+	TotalArticles       int64 `json:"total_articles" db:"total_articles"`
+	PublishedArticles   int64 `json:"published_articles" db:"published_articles"`
+	DraftArticles       int64 `json:"draft_articles" db:"draft_articles"`
+	ArchivedArticles    int64 `json:"archived_articles" db:"archived_articles"`
+	TotalComments       int64 `json:"total_comments" db:"total_comments"`
+	TotalArticleViews   int64 `json:"total_article_views" db:"total_article_views"`
+	TotalArticleLikes   int64 `json:"total_article_likes" db:"total_article_likes"`
+	TodayPublishedCount int64 `json:"today_published_count" db:"today_published_count"`
+
+	// 待处理队列
+	PendingComments   int64 `json:"pending_comments" db:"pending_comments"`
+	ReportedComments  int64 `json:"reported_comments" db:"reported_comments"`
+	ReviewArticles    int64 `json:"review_articles" db:"review_articles"`
+	ScheduledArticles int64 `json:"scheduled_articles" db:"scheduled_articles"`
+
+	// 审核耗时：近 30 天审核通过的文章从提交审核到通过的中位时长（秒），没有样本时为 null
+	MedianReviewSeconds *int64 `json:"median_review_seconds"`
+	ReviewSampleSize    int    `json:"review_sample_size"`
+
+	// Links 待处理队列对应的筛选列表（key 与上面的统计字段名一致）
+	Links map[string]DashboardQueueLink `json:"links"`
+}
+
+// DashboardQueueLink 仪表盘待处理队列跳转信息，管理后台据此打开对应的筛选列表
+type DashboardQueueLink struct {
+	Resource string `json:"resource"` // articles / comments
+	Status   string `json:"status"`
+	Path     string `json:"path"`
+}
+
+// DashboardTopKind 仪表盘排行榜类型
+type DashboardTopKind string
+
+const (
+	TopArticlesByViews    DashboardTopKind = "articles_views"
+	TopArticlesByComments DashboardTopKind = "articles_comments"
+	TopAuthors            DashboardTopKind = "authors"
+)
+
+// DashboardTopItem 排行榜条目（文章排行返回 title，作者排行返回 username）
+type DashboardTopItem struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	Title    string    `json:"title,omitempty" db:"title"`
+	Username string    `json:"username,omitempty" db:"username"`
+	Value    int64     `json:"value" db:"value"`
+}
+
+// DashboardTopResult 排行榜查询结果
+type DashboardTopResult struct {
+	Kind   DashboardTopKind    `json:"kind"`
+	Period string              `json:"period"`
+	Since  time.Time           `json:"since"`
+	Items  []*DashboardTopItem `json:"items"`
+}
+
+// ContentStats 分类 / 标签维度的内容统计
+type ContentStats struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	Name              string     `json:"name" db:"name"`
+	Slug              string     `json:"slug" db:"slug"`
+	PublishedArticles int64      `json:"published_articles" db:"published_articles"`
+	TotalViews        int64      `json:"total_views" db:"total_views"`
+	TotalComments     int64      `json:"total_comments" db:"total_comments"`
+	LastPublishedAt   *time.Time `json:"last_published_at,omitempty" db:"last_published_at"`
+}
+
+// ContentStatsQuery 内容统计排序参数
+type ContentStatsQuery struct {
+	SortBy string `form:"sort_by"` // published_articles / total_views / total_comments / last_published_at
+	Order  string `form:"order"`   // asc / desc
+}
+
+// PeriodCounts 时间窗口内的新增统计
+type PeriodCounts struct {
+	NewUsers          int64 `json:"new_users" db:"new_users"`
+	PublishedArticles int64 `json:"published_articles" db:"published_articles"`
+	Views             int64 `json:"views" db:"views"`
+}
+
+// WeeklyReport 每周统计报表（邮件内容）
+type WeeklyReport struct {
+	From        time.Time           `json:"from"` // 含
+	To          time.Time           `json:"to"`   // 不含
+	Counts      PeriodCounts        `json:"counts"`
+	TopArticles []*DashboardTopItem `json:"top_articles"`
+
+	// 生成报表时的待处理积压
+	PendingComments  int64 `json:"pending_comments"`
+	ReportedComments int64 `json:"reported_comments"`
+	ReviewArticles   int64 `json:"review_articles"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// SystemStatus 基础设施运行状态（连接池、队列、后台任务、进程、维护模式）
+type SystemStatus struct {
+	Database       DBPoolStatus      `json:"database"`
+	Redis          *RedisPoolStatus  `json:"redis,omitempty"` // Redis 未连接时为空
+	Search         SearchQueueStatus `json:"search"`
+	BackgroundJobs map[string]int64  `json:"background_jobs"`
+	Process        ProcessStatus     `json:"process"`
+	Maintenance    MaintenanceStatus `json:"maintenance"`
+	CollectedAt    time.Time         `json:"collected_at"`
+}
+
+// DBPoolStatus 数据库连接池状态（来自 sql.DBStats）
+type DBPoolStatus struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`       // 累计等待连接次数
+	WaitDurationMs     int64 `json:"wait_duration_ms"` // 累计等待时长
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// RedisPoolStatus Redis 连接池状态（来自 redis.PoolStats）
+type RedisPoolStatus struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+}
+
+// SearchQueueStatus Elasticsearch 异步索引状态
+type SearchQueueStatus struct {
+	Enabled           bool  `json:"enabled"`
+	PendingOperations int64 `json:"pending_operations"` // 尚未完成的异步索引 / 删除操作数
+}
+
+// ProcessStatus 进程运行状态
+type ProcessStatus struct {
+	Goroutines     int     `json:"goroutines"`
+	HeapAllocBytes uint64  `json:"heap_alloc_bytes"`
+	HeapInuseBytes uint64  `json:"heap_inuse_bytes"`
+	SysBytes       uint64  `json:"sys_bytes"`
+	NumGC          uint32  `json:"num_gc"`
+	UptimeSeconds  float64 `json:"uptime_seconds"`
+}
+
+// MaintenanceStatus 维护模式状态（来自系统设置，所有实例一致）
+type MaintenanceStatus struct {
+	Enabled    bool   `json:"enabled"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after"` // 503 响应中 Retry-After 的秒数
+}
+
+// 就绪检查的状态取值
+const (
+	HealthOK          = "ok"          // 依赖可用
+	HealthDegraded    = "degraded"    // 可选依赖不可用，服务降级运行
+	HealthDown        = "down"        // 必需依赖不可用
+	HealthDisabled    = "disabled"    // 依赖未配置或未启用
+	HealthUnavailable = "unavailable" // 整体状态：有必需依赖不可用
+)
+
+// HealthReport 就绪检查结果（/health、/readyz）
+type HealthReport struct {
+	Status string                      `json:"status"` // ok / degraded / unavailable，unavailable 时返回 503
+	Checks map[string]DependencyHealth `json:"checks"` // 键为 database / redis / elasticsearch
+}
+
+// DependencyHealth 单项依赖的检查结果
+type DependencyHealth struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
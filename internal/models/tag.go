@@ -7,21 +7,71 @@ import (
 )
 
 type Tag struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	Slug      string    `json:"slug" db:"slug"`
-	Color     string    `json:"color" db:"color"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        uuid.UUID  `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name" gorm:"uniqueIndex:uniq_tags_name_active,where:deleted_at IS NULL"`
+	Slug      string     `json:"slug" db:"slug" gorm:"uniqueIndex:uniq_tags_slug_lower,expression:LOWER(slug),where:deleted_at IS NULL"`
+	Color     string     `json:"color" db:"color"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// ArticleCount 使用该标签的文章数（只读，仅列表接口返回）
+	ArticleCount *int64 `json:"article_count,omitempty" db:"article_count" gorm:"-:migration"`
 }
 
 type TagCreate struct {
 	Name  string `json:"name" validate:"required,min=1,max=50"`
-	Color string `json:"color"`
+	Color string `json:"color" validate:"color"`
 }
 
 type TagUpdate struct {
 	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=50"`
-	Color *string `json:"color,omitempty"`
+	// Color 十六进制颜色，如 #00ADD8；传空字符串表示清除
+	Color *string `json:"color,omitempty" validate:"omitempty,color"`
+}
+
+// TaxonomyListQuery 分类 / 标签列表查询参数
+type TaxonomyListQuery struct {
+	Sort          string `form:"sort" binding:"omitempty,oneof=article_count"` // article_count: 按文章数倒序（仅管理后台）
+	PublishedOnly bool   `form:"-"`                                            // 只统计已发布文章（公开接口）
+}
+
+// TaxonomyAutocompleteQuery 分类 / 标签自动补全查询参数
+type TaxonomyAutocompleteQuery struct {
+	Q               string `form:"q" binding:"required,max=50"`
+	Limit           int    `form:"limit"`
+	CreateIfMissing bool   `form:"create_if_missing"` // 仅标签：没有同名标签时新建（编辑、管理员）
+}
+
+// TaxonomySuggestion 分类 / 标签自动补全结果
+type TaxonomySuggestion struct {
+	ID   uuid.UUID `json:"id" db:"id"`
+	Name string    `json:"name" db:"name"`
+	Slug string    `json:"slug" db:"slug"`
+	// UsageCount 使用该分类 / 标签的已发布文章数
+	UsageCount int64 `json:"usage_count" db:"usage_count"`
+	// Created 是否为本次请求新建的标签
+	Created bool `json:"created,omitempty" db:"-" gorm:"-"`
 }
 
+// TagAlias 标签合并后保留的别名：被合并标签的 ID 和 slug 继续解析到目标标签
+type TagAlias struct {
+	SourceTagID uuid.UUID `json:"source_tag_id" db:"source_tag_id" gorm:"primaryKey"`
+	SourceSlug  string    `json:"source_slug" db:"source_slug" gorm:"index:idx_tag_aliases_slug_lower,expression:LOWER(source_slug)"`
+	TargetTagID uuid.UUID `json:"target_tag_id" db:"target_tag_id" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+func (TagAlias) TableName() string { return "tag_aliases" }
+
+// TagMerge 合并标签请求
+type TagMerge struct {
+	TargetTagID uuid.UUID `json:"target_tag_id"`
+}
+
+// TagMergeResult 合并标签结果
+type TagMergeResult struct {
+	Target *Tag `json:"target"`
+	// ArticlesTouched 原先使用被合并标签的文章数（含已同时带有目标标签的文章）
+	ArticlesTouched int64 `json:"articles_touched"`
+}
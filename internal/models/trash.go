@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashType 回收站内容类型
+type TrashType string
+
+const (
+	TrashArticles   TrashType = "articles"
+	TrashUsers      TrashType = "users"
+	TrashImages     TrashType = "images"
+	TrashCategories TrashType = "categories"
+	TrashTags       TrashType = "tags"
+)
+
+// TrashItem 回收站条目（已软删除的文章 / 用户 / 图片 / 分类 / 标签）
+type TrashItem struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Type      TrashType `json:"type" db:"-"`
+	Name      string    `json:"name" db:"name"` // 文章标题 / 用户名 / 图片原始文件名 / 分类或标签名称
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+// TrashQuery 回收站列表查询参数
+type TrashQuery struct {
+	Type     TrashType `form:"type" binding:"required"`
+	Page     int       `form:"page"`
+	PageSize int       `form:"page_size"`
+}
@@ -20,8 +20,8 @@ const (
 
 type User struct {
 	ID        uuid.UUID  `json:"id" db:"id"`
-	Username  string     `json:"username" db:"username"`
-	Email     string     `json:"email" db:"email"`
+	Username  string     `json:"username" db:"username" gorm:"uniqueIndex:users_username_key"`
+	Email     string     `json:"email" db:"email" gorm:"uniqueIndex:users_email_key"`
 	Phone     string     `json:"phone,omitempty" db:"phone"`
 	Password  string     `json:"-" db:"password"`
 	Role      UserRole   `json:"role" db:"role"`
@@ -33,6 +33,18 @@ type User struct {
 	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+// PublicProfile 作者公开资料：不包含邮箱、手机号、角色等私密信息
+type PublicProfile struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Avatar   string    `json:"avatar"`
+	Bio      string    `json:"bio"`
+	// ArticleCount 已发布文章数
+	ArticleCount int64 `json:"article_count"`
+	// JoinedAt 注册时间
+	JoinedAt time.Time `json:"joined_at"`
+}
+
 type UserCreate struct {
 	Username string   `json:"username" validate:"required,min=3,max=50"`
 	Email    string   `json:"email" validate:"required,email"`
@@ -49,6 +61,16 @@ type UserUpdate struct {
 	Status   *string   `json:"status,omitempty"`
 }
 
+// UserQuery 用户列表查询条件（管理后台列表与导出共用）
+type UserQuery struct {
+	Page        int        `form:"page"`
+	PageSize    int        `form:"page_size"`
+	Role        UserRole   `form:"role"`
+	Status      string     `form:"status"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02"`
+}
+
 type UserLogin struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
@@ -59,6 +81,73 @@ type PhoneLogin struct {
 	Code  string `json:"code" validate:"required,len=6"`
 }
 
+// RefreshToken 已签发的刷新 token（只保存 jti，不保存 token 本身），用于吊销
+type RefreshToken struct {
+	// ID 刷新 token 的 jti
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id" gorm:"index"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at" gorm:"index"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// EmailVerification 注册邮箱验证令牌（只保存令牌的哈希），验证后或重新发送时作废
+type EmailVerification struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id" gorm:"index"`
+	TokenHash string     `json:"-" db:"token_hash" gorm:"uniqueIndex:email_verifications_token_hash_key"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// VerifyEmailRequest 邮箱验证请求，token 为验证邮件链接中的令牌
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest 重新发送验证邮件请求
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// PasswordReset 找回密码令牌（只保存令牌的哈希），使用一次后作废
+type PasswordReset struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id" gorm:"index"`
+	TokenHash string     `json:"-" db:"token_hash" gorm:"uniqueIndex:password_resets_token_hash_key"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest 重置密码请求，token 为找回密码邮件链接中的令牌
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=6"`
+}
+
+// AuthTokens 登录和刷新接口返回的 token
+type AuthTokens struct {
+	// Token 访问 token
+	Token string `json:"token"`
+	// ExpiresAt 访问 token 的过期时间
+	ExpiresAt time.Time `json:"expires_at"`
+	// RefreshToken 刷新 token，过期时间在登录时确定，刷新访问 token 时不会延长
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// RefreshTokenRequest 刷新访问 token / 退出登录请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
 type SendSMSCodeRequest struct {
 	Phone string `json:"phone" validate:"required"`
 }
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 可订阅的 Webhook 事件
+const (
+	WebhookEventArticlePublished = "article.published"
+	WebhookEventArticleUpdated   = "article.updated"
+	WebhookEventCommentCreated   = "comment.created"
+	WebhookEventCommentApproved  = "comment.approved"
+	WebhookEventUserRegistered   = "user.registered"
+)
+
+// WebhookEvents 全部可订阅的事件
+var WebhookEvents = []string{
+	WebhookEventArticlePublished,
+	WebhookEventArticleUpdated,
+	WebhookEventCommentCreated,
+	WebhookEventCommentApproved,
+	WebhookEventUserRegistered,
+}
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook 管理后台配置的外部回调地址
+type Webhook struct {
+	ID  uuid.UUID `json:"id" db:"id"`
+	URL string    `json:"url" db:"url"`
+	// Secret 签名密钥，只在创建和修改密钥时返回
+	Secret    string     `json:"secret,omitempty" db:"secret"`
+	Events    []string   `json:"events" db:"events" gorm:"serializer:json;type:text"` // 订阅的事件（JSON 数组）
+	Active    bool       `json:"active" db:"active"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribed 判断是否订阅了事件
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookCreate 创建 Webhook 请求，Secret 为空时自动生成
+type WebhookCreate struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookUpdate 修改 Webhook 请求，未提供的字段保持不变
+type WebhookUpdate struct {
+	URL    *string  `json:"url"`
+	Secret *string  `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookDelivery 一次事件投递（含重试）的记录
+type WebhookDelivery struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	WebhookID uuid.UUID `json:"webhook_id" db:"webhook_id"`
+	Event     string    `json:"event" db:"event"`
+	// Payload 发送的请求体（JSON），重新投递时原样发送
+	Payload        string     `json:"payload" db:"payload"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty" db:"response_status"`
+	ResponseBody   string     `json:"response_body,omitempty" db:"response_body"` // 截断到前 2KB
+	Error          string     `json:"error,omitempty" db:"error"`
+	RedeliveryOf   *uuid.UUID `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// WebhookPayload 投递的请求体
+type WebhookPayload struct {
+	ID        uuid.UUID   `json:"id"` // 事件 ID，重新投递时不变，接收方可据此去重
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 
@@ -14,6 +17,33 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrArticleNotFound 文章不存在或已删除
+	ErrArticleNotFound = fmt.Errorf("article %w", ErrNotFound)
+	// ErrArticleVersionConflict 更新时文章版本号与期望值不一致（已被其他请求修改）
+	ErrArticleVersionConflict = errors.New("article version conflict")
+	// ErrTagsNotFound 关联的标签不存在或已删除
+	ErrTagsNotFound = errors.New("tags not found")
+)
+
+// TagsNotFoundError 关联标签时列出不存在（或已删除）的标签 ID
+// errors.Is(err, ErrTagsNotFound) 为 true
+type TagsNotFoundError struct {
+	IDs []uuid.UUID
+}
+
+func (e *TagsNotFoundError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = id.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrTagsNotFound, strings.Join(ids, ", "))
+}
+
+func (e *TagsNotFoundError) Is(target error) bool {
+	return target == ErrTagsNotFound
+}
+
 type ArticleRepository struct{}
 
 func NewArticleRepository() *ArticleRepository {
@@ -21,19 +51,16 @@ func NewArticleRepository() *ArticleRepository {
 }
 
 func (r *ArticleRepository) Create(ctx context.Context, article *models.Article) error {
-	tx := database.DB.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	return database.WithTx(ctx, func(tx *gorm.DB) error {
+		return r.CreateTx(tx, article)
+	})
+}
 
+// CreateTx 在调用方的事务中插入文章
+func (r *ArticleRepository) CreateTx(tx *gorm.DB, article *models.Article) error {
 	query := `
-		INSERT INTO articles (id, title, slug, content, excerpt, cover_image, status, author_id, category_id, view_count, like_count, comment_count, published_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		INSERT INTO articles (id, title, slug, content, excerpt, cover_image, status, author_id, category_id, view_count, like_count, comment_count, published_at, created_at, updated_at, meta_description, scheduled_at, word_count, reading_time_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 		RETURNING id
 	`
 	
@@ -45,32 +72,26 @@ func (r *ArticleRepository) Create(ctx context.Context, article *models.Article)
 		article.PublishedAt = &now
 	}
 
+	// version 由数据库默认值初始化为 1
+	article.Version = 1
+
 	row := tx.Raw(
 		query,
 		article.ID, article.Title, article.Slug, article.Content, article.Excerpt,
 		article.CoverImage, article.Status, article.AuthorID, article.CategoryID,
 		article.ViewCount, article.LikeCount, article.CommentCount,
-		article.PublishedAt, article.CreatedAt, article.UpdatedAt,
+		article.PublishedAt, article.CreatedAt, article.UpdatedAt, article.MetaDescription,
+		localTimePtr(article.ScheduledAt), article.WordCount, article.ReadingTimeMinutes,
 	).Row()
-	if err := row.Scan(&article.ID); err != nil {
-		return err
-	}
-
-	return tx.Commit().Error
-}
-
-func (r *ArticleRepository) GetByID(id uuid.UUID) (*models.Article, error) {
-	// 默认使用背景上下文，以兼容旧调用；推荐通过带 ctx 的方法调用
-	ctx := context.Background()
-	return r.GetByIDWithContext(ctx, id)
+	return wrapConflict(row.Scan(&article.ID))
 }
 
-func (r *ArticleRepository) GetByIDWithContext(ctx context.Context, id uuid.UUID) (*models.Article, error) {
+func (r *ArticleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Article, error) {
 	article := &models.Article{}
 	query := `
-		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.status,
-			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count,
-			   a.published_at, a.created_at, a.updated_at, a.deleted_at
+		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.meta_description, a.status,
+			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time_minutes,
+			   a.published_at, a.scheduled_at, a.created_at, a.updated_at, a.deleted_at, a.version
 		FROM articles a
 		WHERE a.id = $1 AND a.deleted_at IS NULL
 	`
@@ -80,28 +101,138 @@ func (r *ArticleRepository) GetByIDWithContext(ctx context.Context, id uuid.UUID
 		return nil, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return nil, errors.New("article not found")
+		return nil, ErrArticleNotFound
 	}
 
 	// 加载作者信息
-	if err := r.loadArticleRelations(article); err != nil {
+	if err := r.loadArticleRelations(ctx, article); err != nil {
 		return nil, err
 	}
 
 	return article, nil
 }
 
-func (r *ArticleRepository) GetBySlug(slug string) (*models.Article, error) {
-	ctx := context.Background()
-	return r.GetBySlugWithContext(ctx, slug)
+// GetByIDs 批量获取文章详情（含作者、分类、标签）
+// ids: 文章ID列表
+// 返回: 按 ids 的顺序排列的文章，不存在或已删除的文章跳过（不返回错误）
+// 注意: 文章一次查询，作者、分类、标签各一次批量查询，不随文章数量增加查询次数
+func (r *ArticleRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Article, error) {
+	if len(ids) == 0 {
+		return []*models.Article{}, nil
+	}
+
+	var found []*models.Article
+	query := `
+		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.meta_description, a.status,
+			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time_minutes,
+			   a.published_at, a.scheduled_at, a.created_at, a.updated_at, a.deleted_at, a.version
+		FROM articles a
+		WHERE a.id IN ? AND a.deleted_at IS NULL
+	`
+	if err := database.DB.WithContext(ctx).Raw(query, ids).Scan(&found).Error; err != nil {
+		return nil, err
+	}
+	if err := r.loadArticleRelationsBatch(ctx, found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Article, len(found))
+	for _, article := range found {
+		byID[article.ID] = article
+	}
+	articles := make([]*models.Article, 0, len(found))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			articles = append(articles, article)
+			// 重复的 ID 只返回一次
+			delete(byID, id)
+		}
+	}
+	return articles, nil
+}
+
+// CommentsMeta 批量统计文章已通过评论的数量和最新评论时间
+// ids: 文章 ID 列表
+// 返回: 文章 ID 到统计的映射，没有已通过评论的文章不在映射中
+// 注意: 使用一次分组查询完成，避免逐篇查询
+func (r *ArticleRepository) CommentsMeta(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.ArticleCommentsMeta, error) {
+	meta := make(map[uuid.UUID]*models.ArticleCommentsMeta, len(ids))
+	if len(ids) == 0 {
+		return meta, nil
+	}
+	var rows []struct {
+		ArticleID     uuid.UUID
+		ApprovedCount int64
+		LastCommentAt aggregateTime
+	}
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT article_id, COUNT(*) AS approved_count, MAX(created_at) AS last_comment_at
+		FROM comments
+		WHERE article_id IN ? AND status = ? AND deleted_at IS NULL
+		GROUP BY article_id
+	`, ids, models.CommentStatusApproved).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		meta[row.ArticleID] = &models.ArticleCommentsMeta{ApprovedCount: row.ApprovedCount, LastCommentAt: row.LastCommentAt.Time}
+	}
+	return meta, nil
+}
+
+// aggregateTime 扫描 MAX / MIN 等聚合出的时间列
+// 注意: SQLite 的聚合结果不带列类型，时间以文本返回，需要自行解析；PostgreSQL 直接返回 time.Time
+type aggregateTime struct {
+	Time *time.Time
+}
+
+// sqliteTimeLayouts SQLite 驱动写入时间列使用的文本格式
+var sqliteTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+// Value 实现 driver.Valuer（GORM 需要字段同时实现 Scanner 和 Valuer 才按普通列处理）
+func (t aggregateTime) Value() (driver.Value, error) {
+	if t.Time == nil {
+		return nil, nil
+	}
+	return *t.Time, nil
+}
+
+// Scan 实现 sql.Scanner
+func (t *aggregateTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = nil
+		return nil
+	case time.Time:
+		t.Time = &v
+		return nil
+	case []byte:
+		return t.Scan(string(v))
+	case string:
+		for _, layout := range sqliteTimeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				t.Time = &parsed
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot parse %q as time", v)
+	default:
+		return fmt.Errorf("cannot scan %T into time", value)
+	}
 }
 
-func (r *ArticleRepository) GetBySlugWithContext(ctx context.Context, slug string) (*models.Article, error) {
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug string) (*models.Article, error) {
 	article := &models.Article{}
 	query := `
-		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.status,
-			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count,
-			   a.published_at, a.created_at, a.updated_at, a.deleted_at
+		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.meta_description, a.status,
+			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time_minutes,
+			   a.published_at, a.scheduled_at, a.created_at, a.updated_at, a.deleted_at, a.version
 		FROM articles a
 		WHERE a.slug = $1 AND a.deleted_at IS NULL
 	`
@@ -111,33 +242,32 @@ func (r *ArticleRepository) GetBySlugWithContext(ctx context.Context, slug strin
 		return nil, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return nil, errors.New("article not found")
+		return nil, ErrArticleNotFound
 	}
 
-	if err := r.loadArticleRelations(article); err != nil {
+	if err := r.loadArticleRelations(ctx, article); err != nil {
 		return nil, err
 	}
 
 	return article, nil
 }
 
-func (r *ArticleRepository) Update(article *models.Article) error {
-	ctx := context.Background()
-	tx := database.DB.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+func (r *ArticleRepository) Update(ctx context.Context, article *models.Article) error {
+	return database.WithTx(ctx, func(tx *gorm.DB) error {
+		return r.UpdateTx(tx, article)
+	})
+}
 
+// UpdateTx 在调用方的事务中更新文章；article.Tags 非空时同时替换标签关联
+// 乐观锁：只有数据库中的版本号等于 article.Version 时才更新，成功后版本号 +1 并回写到 article.Version
+// 返回: 版本号不一致时返回 ErrArticleVersionConflict
+func (r *ArticleRepository) UpdateTx(tx *gorm.DB, article *models.Article) error {
 	query := `
 		UPDATE articles 
 		SET title = $2, slug = $3, content = $4, excerpt = $5, cover_image = $6,
-			status = $7, category_id = $8, updated_at = $9, published_at = $10
-		WHERE id = $1 AND deleted_at IS NULL
+			status = $7, category_id = $8, updated_at = $9, published_at = $10, meta_description = $12, scheduled_at = $13,
+			word_count = $14, reading_time_minutes = $15, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND version = $11
 	`
 	
 	article.UpdatedAt = time.Now()
@@ -148,14 +278,25 @@ func (r *ArticleRepository) Update(article *models.Article) error {
 
 	result := tx.Exec(query, article.ID, article.Title, article.Slug, article.Content,
 		article.Excerpt, article.CoverImage, article.Status, article.CategoryID,
-		article.UpdatedAt, article.PublishedAt)
+		article.UpdatedAt, article.PublishedAt, article.Version, article.MetaDescription,
+		localTimePtr(article.ScheduledAt), article.WordCount, article.ReadingTimeMinutes)
 	if result.Error != nil {
-		return result.Error
+		return wrapConflict(result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("article not found")
+		// 区分文章不存在和版本号不一致
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM articles WHERE id = $1 AND deleted_at IS NULL", article.ID).
+			Scan(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrArticleVersionConflict
+		}
+		return ErrArticleNotFound
 	}
+	article.Version++
 
 	// 更新标签关联
 	if len(article.Tags) > 0 {
@@ -168,37 +309,166 @@ func (r *ArticleRepository) Update(article *models.Article) error {
 			return err
 		}
 	}
+	return nil
+}
 
-	return tx.Commit().Error
+// Delete 软删除文章，同一事务内以相同的删除时间软删除其未删除的评论（恢复文章时据此一并恢复）
+func (r *ArticleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	return database.WithTx(ctx, func(tx *gorm.DB) error {
+		result := tx.Exec(`UPDATE articles SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, now, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrArticleNotFound
+		}
+		return tx.Exec(`UPDATE comments SET deleted_at = $1 WHERE article_id = $2 AND deleted_at IS NULL`, now, id).Error
+	})
 }
 
-func (r *ArticleRepository) Delete(id uuid.UUID) error {
-	ctx := context.Background()
-	query := `UPDATE articles SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
-	result := database.DB.WithContext(ctx).Exec(query, time.Now(), id)
-	if result.Error != nil {
-		return result.Error
+func (r *ArticleRepository) List(ctx context.Context, query models.ArticleQuery) ([]*models.Article, int64, error) {
+	var articles []*models.Article
+	var total int64
+
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
+	offset := (query.Page - 1) * query.PageSize
+
+	// 构建查询条件
+	where, args := articleFilters(query)
+	whereClause := strings.Join(where, " AND ")
+
+	// 获取总数 - 使用参数化查询，避免 SQL 注入
+	countQuery := "SELECT COUNT(*) FROM articles a WHERE " + whereClause
+	err := database.DB.WithContext(ctx).Raw(countQuery, args...).Scan(&total).Error
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("article not found")
+	// 排序 - 使用白名单验证，防止 SQL 注入；无效的排序字段使用默认排序（按创建时间倒序）
+	orderBy := articleOrderBy(query)
+	if orderBy == "" {
+		orderBy = "a.created_at DESC"
 	}
-	return nil
+
+	// 获取列表 - 使用参数化查询，避免 SQL 注入
+	listQuery := fmt.Sprintf(`
+		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.meta_description, a.status,
+			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time_minutes,
+			   a.published_at, a.scheduled_at, a.created_at, a.updated_at, a.version
+		FROM articles a
+		WHERE %s
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, whereClause, orderBy)
+
+	args = append(args, query.PageSize, offset)
+	err = database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&articles).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 加载关联数据
+	for _, article := range articles {
+		if err := r.loadArticleRelations(ctx, article); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return articles, total, nil
 }
 
-func (r *ArticleRepository) List(ctx context.Context, query models.ArticleQuery) ([]*models.Article, int64, error) {
+// SearchFullText 使用 PostgreSQL 全文索引（search_vector）搜索文章，Elasticsearch 不可用时的默认搜索路径
+// query: 搜索关键词（websearch 语法：空格为 AND，"短语"、or、-排除）及状态 / 分类 / 标签 / 作者 / 时间筛选
+// 返回: 默认按相关度（ts_rank）倒序、同分按创建时间倒序的文章列表和匹配总数；指定 SortBy 时按该字段排序
+// 注意: 分词使用 english 配置，与 search_vector 触发器保持一致；SQLite 下退化为标题 / 摘要 / 正文的 LIKE 匹配，按创建时间倒序
+func (r *ArticleRepository) SearchFullText(ctx context.Context, query models.ArticleQuery) ([]*models.Article, int64, error) {
 	var articles []*models.Article
 	var total int64
 
-	if query.Page <= 0 {
-		query.Page = 1
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
+	offset := (query.Page - 1) * query.PageSize
+
+	where, args := articleFilters(query)
+	orderBy := "ts_rank(a.search_vector, websearch_to_tsquery('english', ?)) DESC, a.created_at DESC"
+	orderArgs := []interface{}{query.Search}
+	if database.IsSQLite() {
+		pattern := "%" + query.Search + "%"
+		where = append(where, "(a.title LIKE ? OR a.excerpt LIKE ? OR a.content LIKE ?)")
+		args = append(args, pattern, pattern, pattern)
+		orderBy, orderArgs = "a.created_at DESC", nil
+	} else {
+		where = append(where, "a.search_vector @@ websearch_to_tsquery('english', ?)")
+		args = append(args, query.Search)
 	}
-	if query.PageSize <= 0 {
-		query.PageSize = 10
+	// 指定了排序字段时按该字段排序，否则按相关度排序
+	if sorted := articleOrderBy(query); sorted != "" {
+		orderBy, orderArgs = sorted, nil
 	}
-	offset := (query.Page - 1) * query.PageSize
+	whereClause := strings.Join(where, " AND ")
 
-	// 构建查询条件
+	countQuery := "SELECT COUNT(*) FROM articles a WHERE " + whereClause
+	if err := database.DB.WithContext(ctx).Raw(countQuery, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*models.Article{}, 0, nil
+	}
+
+	listQuery := `
+		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.meta_description, a.status,
+			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count, a.word_count, a.reading_time_minutes,
+			   a.published_at, a.scheduled_at, a.created_at, a.updated_at, a.version
+		FROM articles a
+		WHERE ` + whereClause + `
+		ORDER BY ` + orderBy + `
+		LIMIT ? OFFSET ?
+	`
+	args = append(append(args, orderArgs...), query.PageSize, offset)
+	if err := database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, article := range articles {
+		if err := r.loadArticleRelations(ctx, article); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return articles, total, nil
+}
+
+// articleOrderBy 根据 SortBy / Order 构建 ORDER BY 子句（字段和方向都经过白名单校验，防止 SQL 注入）
+// 返回: 未指定排序字段或字段不在白名单中时返回空字符串，由调用方使用默认排序
+func articleOrderBy(query models.ArticleQuery) string {
+	// 白名单：允许的排序字段
+	allowedSortFields := map[string]string{
+		"id":                   "a.id",
+		"title":                "a.title",
+		"created_at":           "a.created_at",
+		"updated_at":           "a.updated_at",
+		"published_at":         "a.published_at",
+		"view_count":           "a.view_count",
+		"like_count":           "a.like_count",
+		"comment_count":        "a.comment_count",
+		"word_count":           "a.word_count",
+		"reading_time_minutes": "a.reading_time_minutes",
+	}
+	sortField, ok := allowedSortFields[query.SortBy]
+	if !ok {
+		return ""
+	}
+	// 验证排序方向，默认降序
+	order := strings.ToLower(query.Order)
+	if order != "asc" && order != "desc" {
+		order = "desc"
+	}
+	return fmt.Sprintf("%s %s", sortField, strings.ToUpper(order))
+}
+
+// articleFilters 根据查询条件构建文章列表的 WHERE 条件（不含搜索关键词）
+// 返回: 以 AND 连接的条件列表和对应的 ? 占位参数
+func articleFilters(query models.ArticleQuery) ([]string, []interface{}) {
 	where := []string{"a.deleted_at IS NULL"}
 	args := []interface{}{}
 
@@ -217,98 +487,278 @@ func (r *ArticleRepository) List(ctx context.Context, query models.ArticleQuery)
 		args = append(args, *query.AuthorID)
 	}
 
-	// 注意：全文搜索已完全迁移到Elasticsearch
-	// 如果query.Search不为空，应该在Service层使用Elasticsearch搜索
-	// 这里不再处理Search条件，只处理其他筛选条件
+	// 搜索关键词不在这里处理：由 Elasticsearch 或 SearchFullText 负责
 
 	if query.TagID != nil {
 		where = append(where, "EXISTS (SELECT 1 FROM article_tags WHERE article_id = a.id AND tag_id = ?)")
 		args = append(args, *query.TagID)
 	}
 
-	whereClause := strings.Join(where, " AND ")
+	// 时间范围含首尾；起点晚于终点时条件不成立，返回空列表
+	for _, r := range []struct {
+		column string
+		op     string
+		bound  *time.Time
+	}{
+		{"a.created_at", ">=", query.CreatedFrom},
+		{"a.created_at", "<=", query.CreatedTo},
+		{"a.published_at", ">=", query.PublishedFrom},
+		{"a.published_at", "<=", query.PublishedTo},
+	} {
+		if r.bound != nil {
+			where = append(where, r.column+" "+r.op+" ?")
+			args = append(args, localTime(*r.bound))
+		}
+	}
 
-	// 获取总数 - 使用参数化查询，避免 SQL 注入
-	countQuery := "SELECT COUNT(*) FROM articles a WHERE " + whereClause
-	err := database.DB.WithContext(ctx).Raw(countQuery, args...).Scan(&total).Error
+	// 年份 / 月份按业务时区的发布时间区间筛选，边界换算到本地时间后比较，可以使用索引
+	if query.Year > 0 {
+		from, to := config.MonthRange(query.Year, query.Month)
+		where = append(where, "a.published_at >= ? AND a.published_at < ?")
+		args = append(args, localTime(from), localTime(to))
+	}
+
+	return where, args
+}
+
+// ArchiveMonths 按发布月份统计已发布文章数（归档页面使用）
+// 返回: 按月份倒序排列的年、月和文章数
+// 注意: 月份按业务时区划分。published_at 保存的是本地时间，数据库无法换算时区，
+// 因此一次 GROUP BY 查询先按分钟分组（时区偏移都是整分钟，分组不会跨月），再换算到业务时区汇总成月份；
+// SQLite 下按 published_at 的前 16 个字符（YYYY-MM-DD HH:MM）分组
+func (r *ArticleRepository) ArchiveMonths(ctx context.Context) ([]*models.ArchiveMonth, error) {
+	period := "to_char(date_trunc('minute', published_at), 'YYYY-MM-DD HH24:MI')"
+	if database.IsSQLite() {
+		period = "substr(published_at, 1, 16)"
+	}
+	var rows []struct {
+		Period string
+		Count  int64
+	}
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT `+period+` AS period, COUNT(*) AS count
+		FROM articles
+		WHERE status = ? AND deleted_at IS NULL AND published_at IS NOT NULL
+		GROUP BY period
+	`, models.StatusPublished).Scan(&rows).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	// 排序 - 使用白名单验证，防止 SQL 注入
-	var orderBy string
-	// 白名单：允许的排序字段
-	allowedSortFields := map[string]string{
-		"id":          "a.id",
-		"title":       "a.title",
-		"created_at":  "a.created_at",
-		"updated_at":  "a.updated_at",
-		"published_at": "a.published_at",
-		"view_count":  "a.view_count",
-		"like_count":  "a.like_count",
-		"comment_count": "a.comment_count",
-	}
-
-	// 白名单：允许的排序方向
-	allowedOrders := map[string]bool{
-		"asc":  true,
-		"desc": true,
-	}
-
-	// 验证并构建 ORDER BY 子句
-	if query.SortBy != "" {
-		sortField, ok := allowedSortFields[query.SortBy]
-		if !ok {
-			// 无效的排序字段，使用默认排序
-			orderBy = "a.created_at DESC"
-		} else {
-			// 验证排序方向
-			order := strings.ToLower(query.Order)
-			if !allowedOrders[order] {
-				order = "desc" // 默认降序
-			}
-			orderBy = fmt.Sprintf("%s %s", sortField, strings.ToUpper(order))
+	loc := config.Location()
+	counts := make(map[[2]int]int64)
+	for _, row := range rows {
+		t, err := time.ParseInLocation("2006-01-02 15:04", strings.Replace(row.Period, "T", " ", 1), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive period %q: %w", row.Period, err)
 		}
-	} else {
-		// 默认按创建时间倒序
-		orderBy = "a.created_at DESC"
+		t = t.In(loc)
+		counts[[2]int{t.Year(), int(t.Month())}] += row.Count
 	}
 
-	// 获取列表 - 使用参数化查询，避免 SQL 注入
-	listQuery := fmt.Sprintf(`
-		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.status,
-			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count,
-			   a.published_at, a.created_at, a.updated_at
-		FROM articles a
-		WHERE %s
-		ORDER BY %s
-		LIMIT ? OFFSET ?
-	`, whereClause, orderBy)
+	months := make([]*models.ArchiveMonth, 0, len(counts))
+	for key, count := range counts {
+		months = append(months, &models.ArchiveMonth{Year: key[0], Month: key[1], Count: count})
+	}
+	sort.Slice(months, func(i, j int) bool {
+		if months[i].Year != months[j].Year {
+			return months[i].Year > months[j].Year
+		}
+		return months[i].Month > months[j].Month
+	})
+	return months, nil
+}
 
-	args = append(args, query.PageSize, offset)
-	err = database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&articles).Error
-	if err != nil {
-		return nil, 0, err
+// CountForIndexing 统计需要同步到搜索引擎的文章数（未删除，可按更新时间增量筛选）
+func (r *ArticleRepository) CountForIndexing(ctx context.Context, since *time.Time) (int64, error) {
+	var total int64
+	query := `SELECT COUNT(*) FROM articles WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	if since != nil {
+		query += ` AND updated_at >= ?`
+		args = append(args, *since)
 	}
+	err := database.DB.WithContext(ctx).Raw(query, args...).Scan(&total).Error
+	return total, err
+}
 
-	// 加载关联数据
-	for _, article := range articles {
-		if err := r.loadArticleRelations(article); err != nil {
-			return nil, 0, err
+// ListForIndexing 按 ID 游标分批读取需要同步到搜索引擎的文章（批量加载关联数据，索引文档中的标签 ID 依赖 Tags）
+// since: 仅返回该时间之后更新过的文章，nil 表示全部
+// afterID: 上一批最后一篇文章的 ID，首批传 uuid.Nil
+// limit: 每批数量
+func (r *ArticleRepository) ListForIndexing(ctx context.Context, since *time.Time, afterID uuid.UUID, limit int) ([]*models.Article, error) {
+	var articles []*models.Article
+
+	where := "deleted_at IS NULL AND id > ?"
+	args := []interface{}{afterID}
+	if since != nil {
+		where += " AND updated_at >= ?"
+		args = append(args, *since)
+	}
+
+	query := `
+		SELECT id, title, slug, content, excerpt, cover_image, status,
+			   author_id, category_id, view_count, like_count, comment_count, word_count, reading_time_minutes,
+			   published_at, created_at, updated_at
+		FROM articles
+		WHERE ` + where + `
+		ORDER BY id
+		LIMIT ?
+	`
+	args = append(args, limit)
+	if err := database.DB.WithContext(ctx).Raw(query, args...).Scan(&articles).Error; err != nil {
+		return nil, err
+	}
+	if err := r.loadArticleRelationsBatch(ctx, articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// UpdateReadingStats 只更新文章的字数和阅读时长（回填使用），不修改 updated_at 和版本号
+func (r *ArticleRepository) UpdateReadingStats(ctx context.Context, id uuid.UUID, wordCount, readingMinutes int) error {
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE articles SET word_count = $1, reading_time_minutes = $2 WHERE id = $3
+	`, wordCount, readingMinutes, id).Error
+}
+
+// ListPublishedSince 获取 since 之后发布的文章（最新发布的在前，不加载关联数据和正文）
+// limit: 最多返回的数量
+func (r *ArticleRepository) ListPublishedSince(ctx context.Context, since time.Time, limit int) ([]*models.Article, error) {
+	var articles []*models.Article
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT id, title, slug, excerpt, cover_image, status, author_id, category_id,
+			   published_at, created_at, updated_at
+		FROM articles
+		WHERE deleted_at IS NULL AND status = ? AND published_at >= ?
+		ORDER BY published_at DESC
+		LIMIT ?
+	`, models.StatusPublished, localTime(since), limit).Scan(&articles).Error
+	return articles, err
+}
+
+// ListDueScheduled 获取定时发布时间已到、仍为 scheduled 状态的文章（最早到期的在前，不加载关联数据和正文）
+// now: 当前时间
+// limit: 最多返回的数量
+func (r *ArticleRepository) ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]*models.Article, error) {
+	var articles []*models.Article
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT id, title, slug, status, author_id, scheduled_at, version
+		FROM articles
+		WHERE deleted_at IS NULL AND status = ? AND scheduled_at <= ?
+		ORDER BY scheduled_at, id
+		LIMIT ?
+	`, models.StatusScheduled, localTime(now), limit).Scan(&articles).Error
+	return articles, err
+}
+
+// localTimePtr 同 localTime，用于可为空的时间字段（客户端传入的时间可能带有其他时区）
+func localTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := localTime(*t)
+	return &local
+}
+
+func (r *ArticleRepository) IncrementViewCount(ctx context.Context, id uuid.UUID) error {
+	return r.AddViews(ctx, id, 1)
+}
+
+// AddViews 增加文章浏览数，并同步累加到按天汇总表 article_view_daily（用于排行榜统计）
+// 注意: 汇总日期按业务时区（config.Location）计算，而不是数据库服务器的 CURRENT_DATE
+func (r *ArticleRepository) AddViews(ctx context.Context, id uuid.UUID, delta int64) error {
+	day := dayParam(config.StartOfDay(time.Now()))
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE articles SET view_count = view_count + ? WHERE id = ?`, delta, id).Error; err != nil {
+			return err
 		}
+		return tx.Exec(`
+			INSERT INTO article_view_daily (article_id, day, views) VALUES (?, ?, ?)
+			ON CONFLICT (article_id, day) DO UPDATE SET views = article_view_daily.views + EXCLUDED.views
+		`, id, day, delta).Error
+	})
+}
+
+// AdjustLikeCount 调整点赞计数，delta 可为负（取消点赞），计数不会小于 0
+func (r *ArticleRepository) AdjustLikeCount(ctx context.Context, id uuid.UUID, delta int64) error {
+	query := `UPDATE articles SET like_count = CASE WHEN like_count + $1 < 0 THEN 0 ELSE like_count + $1 END WHERE id = $2`
+	return database.DB.WithContext(ctx).Exec(query, delta, id).Error
+}
+
+// likerCondition 点赞者对应的 article_likes 查询条件和参数
+func likerCondition(liker models.ArticleLiker) (string, interface{}) {
+	if liker.UserID != nil {
+		return "user_id = $2", *liker.UserID
 	}
+	return "fingerprint = $2", liker.Fingerprint
+}
 
-	return articles, total, nil
+// AddLike 记录点赞
+// 返回: 是否为新的点赞，已点过赞时返回 false；文章不存在或已删除时返回 ErrArticleNotFound
+func (r *ArticleRepository) AddLike(ctx context.Context, id uuid.UUID, liker models.ArticleLiker) (bool, error) {
+	db := database.DB.WithContext(ctx)
+	var count int64
+	if err := db.Raw(`SELECT COUNT(*) FROM articles WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, ErrArticleNotFound
+	}
+
+	like := models.ArticleLike{ID: uuid.New(), ArticleID: id, UserID: liker.UserID, CreatedAt: time.Now()}
+	if liker.UserID == nil {
+		like.Fingerprint = &liker.Fingerprint
+	}
+	// 唯一索引保证并发请求下也只记录一次
+	result := db.Exec(`
+		INSERT INTO article_likes (id, article_id, user_id, fingerprint, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+	`, like.ID, like.ArticleID, like.UserID, like.Fingerprint, like.CreatedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
 
-func (r *ArticleRepository) IncrementViewCount(id uuid.UUID) error {
-	query := `UPDATE articles SET view_count = view_count + 1 WHERE id = $1`
-	return database.DB.Exec(query, id).Error
+// RemoveLike 删除点赞记录
+// 返回: 是否删除了记录，未点过赞时返回 false
+func (r *ArticleRepository) RemoveLike(ctx context.Context, id uuid.UUID, liker models.ArticleLiker) (bool, error) {
+	cond, arg := likerCondition(liker)
+	result := database.DB.WithContext(ctx).Exec(`DELETE FROM article_likes WHERE article_id = $1 AND `+cond, id, arg)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
 
-func (r *ArticleRepository) IncrementLikeCount(id uuid.UUID) error {
-	query := `UPDATE articles SET like_count = like_count + 1 WHERE id = $1`
-	return database.DB.Exec(query, id).Error
+// LikedArticleIDs 在给定文章中筛选出用户已点赞的文章
+// 返回: 已点赞文章 ID 的集合
+func (r *ArticleRepository) LikedArticleIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	liked := make(map[uuid.UUID]bool)
+	if len(ids) == 0 {
+		return liked, nil
+	}
+	var rows []uuid.UUID
+	err := database.DB.WithContext(ctx).Raw(
+		`SELECT article_id FROM article_likes WHERE user_id = ? AND article_id IN ?`, userID, ids,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range rows {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// HasLiked 判断点赞者是否已点赞文章
+func (r *ArticleRepository) HasLiked(ctx context.Context, id uuid.UUID, liker models.ArticleLiker) (bool, error) {
+	cond, arg := likerCondition(liker)
+	var count int64
+	err := database.DB.WithContext(ctx).Raw(`SELECT COUNT(*) FROM article_likes WHERE article_id = $1 AND `+cond, id, arg).Scan(&count).Error
+	return count > 0, err
 }
 
 func (r *ArticleRepository) setArticleTags(tx *gorm.DB, articleID uuid.UUID, tags []models.Tag) error {
@@ -320,57 +770,107 @@ func (r *ArticleRepository) setArticleTags(tx *gorm.DB, articleID uuid.UUID, tag
 	for i, tag := range tags {
 		tagIDs[i] = tag.ID
 	}
+	return r.AddTagsTx(tx, articleID, tagIDs)
+}
 
-	query := `INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
-	for _, tagID := range tagIDs {
-		if err := tx.Exec(query, articleID, tagID).Error; err != nil {
-			return err
-		}
-	}
+// AddTags 为文章添加标签（用于创建后追加标签）
+func (r *ArticleRepository) AddTags(ctx context.Context, articleID uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.AddTagsTx(database.DB.WithContext(ctx), articleID, tagIDs)
+}
 
-	return nil
+// EnsureTagsExist 校验标签 ID 全部存在且未删除（重复的 ID 只校验一次），用于写入前尽早拒绝请求
+// 返回: 有标签不存在或已删除时返回 *TagsNotFoundError
+func (r *ArticleRepository) EnsureTagsExist(ctx context.Context, tagIDs []uuid.UUID) error {
+	tagIDs = uniqueUUIDs(tagIDs)
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	return ensureTagsExistTx(database.DB.WithContext(ctx), tagIDs)
 }
 
-// AddTags 为文章添加标签（用于创建后追加标签）
-func (r *ArticleRepository) AddTags(articleID uuid.UUID, tagIDs []uuid.UUID) error {
+// AddTagsTx 在调用方的事务中为文章添加标签
+// 先用一条查询校验标签全部存在，再用一条多行 INSERT 写入关联，已存在的关联忽略
+// 返回: 有标签不存在或已删除时返回 *TagsNotFoundError
+func (r *ArticleRepository) AddTagsTx(tx *gorm.DB, articleID uuid.UUID, tagIDs []uuid.UUID) error {
+	tagIDs = uniqueUUIDs(tagIDs)
 	if len(tagIDs) == 0 {
 		return nil
 	}
-	query := `INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
-	for _, tagID := range tagIDs {
-		if err := database.DB.Exec(query, articleID, tagID).Error; err != nil {
-			return err
+	if err := ensureTagsExistTx(tx, tagIDs); err != nil {
+		return err
+	}
+
+	// VALUES ($1, $2), ($1, $3), ...：文章 ID 只绑定一次
+	values := make([]string, len(tagIDs))
+	args := make([]interface{}, 0, len(tagIDs)+1)
+	args = append(args, articleID)
+	for i, tagID := range tagIDs {
+		values[i] = fmt.Sprintf("($1, $%d)", i+2)
+		args = append(args, tagID)
+	}
+	query := `INSERT INTO article_tags (article_id, tag_id) VALUES ` + strings.Join(values, ", ") + ` ON CONFLICT DO NOTHING`
+	return tx.Exec(query, args...).Error
+}
+
+// ensureTagsExistTx 一次查询校验标签 ID 全部存在且未删除
+// 返回: 缺失的标签按传入顺序列在 *TagsNotFoundError 中
+func ensureTagsExistTx(tx *gorm.DB, tagIDs []uuid.UUID) error {
+	var found []uuid.UUID
+	if err := tx.Raw("SELECT id FROM tags WHERE id IN ? AND deleted_at IS NULL", tagIDs).
+		Scan(&found).Error; err != nil {
+		return err
+	}
+	if len(found) == len(tagIDs) {
+		return nil
+	}
+
+	exists := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	var missing []uuid.UUID
+	for _, id := range tagIDs {
+		if !exists[id] {
+			missing = append(missing, id)
 		}
 	}
-	return nil
+	return &TagsNotFoundError{IDs: missing}
 }
 
-// ReplaceTags 替换文章的全部标签（用于更新）
-func (r *ArticleRepository) ReplaceTags(articleID uuid.UUID, tagIDs []uuid.UUID) error {
-	tx := database.DB.Begin()
-	if tx.Error != nil {
-		return tx.Error
+// uniqueUUIDs 去除重复 ID，保持首次出现的顺序
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
 	}
+	return result
+}
+
+// ReplaceTags 替换文章的全部标签（用于更新）
+func (r *ArticleRepository) ReplaceTags(ctx context.Context, articleID uuid.UUID, tagIDs []uuid.UUID) error {
+	return database.WithTx(ctx, func(tx *gorm.DB) error {
+		return r.ReplaceTagsTx(tx, articleID, tagIDs)
+	})
+}
+
+// ReplaceTagsTx 在调用方的事务中替换文章的全部标签
+func (r *ArticleRepository) ReplaceTagsTx(tx *gorm.DB, articleID uuid.UUID, tagIDs []uuid.UUID) error {
 	if err := tx.Exec("DELETE FROM article_tags WHERE article_id = $1", articleID).Error; err != nil {
-		tx.Rollback()
 		return err
 	}
-	if len(tagIDs) > 0 {
-		query := `INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
-		for _, tagID := range tagIDs {
-			if err := tx.Exec(query, articleID, tagID).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
-		}
-	}
-	return tx.Commit().Error
+	return r.AddTagsTx(tx, articleID, tagIDs)
 }
 
-func (r *ArticleRepository) loadArticleRelations(article *models.Article) error {
+func (r *ArticleRepository) loadArticleRelations(ctx context.Context, article *models.Article) error {
+	db := database.DB.WithContext(ctx)
+
 	// 加载作者
 	var author models.User
-	err := database.DB.Raw("SELECT id, username, email, avatar FROM users WHERE id = $1", article.AuthorID).Scan(&author).Error
+	err := db.Raw("SELECT id, username, email, avatar FROM users WHERE id = $1", article.AuthorID).Scan(&author).Error
 	if err == nil {
 		article.Author = &author
 	}
@@ -378,19 +878,19 @@ func (r *ArticleRepository) loadArticleRelations(article *models.Article) error
 	// 加载分类
 	if article.CategoryID != nil {
 		var category models.Category
-		err := database.DB.Raw("SELECT id, name, slug FROM categories WHERE id = $1", *article.CategoryID).Scan(&category).Error
-		if err == nil {
+		result := db.Raw("SELECT id, name, slug FROM categories WHERE id = $1 AND deleted_at IS NULL", *article.CategoryID).Scan(&category)
+		if result.Error == nil && result.RowsAffected > 0 {
 			article.Category = &category
 		}
 	}
 
 	// 加载标签
 	var tags []models.Tag
-	err = database.DB.Raw(`
+	err = db.Raw(`
 		SELECT t.id, t.name, t.slug, t.color
 		FROM tags t
 		INNER JOIN article_tags at ON t.id = at.tag_id
-		WHERE at.article_id = $1
+		WHERE at.article_id = $1 AND t.deleted_at IS NULL
 	`, article.ID).Scan(&tags).Error
 	if err == nil {
 		article.Tags = tags
@@ -399,3 +899,80 @@ func (r *ArticleRepository) loadArticleRelations(article *models.Article) error
 	return nil
 }
 
+// loadArticleRelationsBatch 为一组文章批量加载作者、分类和标签（与 loadArticleRelations 的结果一致）
+func (r *ArticleRepository) loadArticleRelationsBatch(ctx context.Context, articles []*models.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	db := database.DB.WithContext(ctx)
+
+	articleIDs := make([]uuid.UUID, 0, len(articles))
+	authorIDs := make([]uuid.UUID, 0, len(articles))
+	var categoryIDs []uuid.UUID
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+		authorIDs = append(authorIDs, article.AuthorID)
+		if article.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *article.CategoryID)
+		}
+	}
+
+	// 加载作者
+	var authors []models.User
+	if err := db.Raw("SELECT id, username, email, avatar FROM users WHERE id IN ?", authorIDs).Scan(&authors).Error; err != nil {
+		return err
+	}
+	authorByID := make(map[uuid.UUID]*models.User, len(authors))
+	for i := range authors {
+		authorByID[authors[i].ID] = &authors[i]
+	}
+
+	// 加载分类
+	categoryByID := make(map[uuid.UUID]*models.Category, len(categoryIDs))
+	if len(categoryIDs) > 0 {
+		var categories []models.Category
+		if err := db.Raw("SELECT id, name, slug FROM categories WHERE id IN ? AND deleted_at IS NULL", categoryIDs).Scan(&categories).Error; err != nil {
+			return err
+		}
+		for i := range categories {
+			categoryByID[categories[i].ID] = &categories[i]
+		}
+	}
+
+	// 加载标签
+	var tagRows []struct {
+		ArticleID uuid.UUID
+		models.Tag
+	}
+	if err := db.Raw(`
+		SELECT at.article_id, t.id, t.name, t.slug, t.color
+		FROM tags t
+		INNER JOIN article_tags at ON t.id = at.tag_id
+		WHERE at.article_id IN ? AND t.deleted_at IS NULL
+	`, articleIDs).Scan(&tagRows).Error; err != nil {
+		return err
+	}
+	tagsByArticle := make(map[uuid.UUID][]models.Tag, len(articles))
+	for _, row := range tagRows {
+		tagsByArticle[row.ArticleID] = append(tagsByArticle[row.ArticleID], row.Tag)
+	}
+
+	for _, article := range articles {
+		// 同一作者 / 分类的文章各自持有副本，避免调用方修改时相互影响
+		if author, ok := authorByID[article.AuthorID]; ok {
+			a := *author
+			article.Author = &a
+		}
+		if article.CategoryID != nil {
+			if category, ok := categoryByID[*article.CategoryID]; ok {
+				c := *category
+				article.Category = &c
+			}
+		}
+		article.Tags = tagsByArticle[article.ID]
+		if article.Tags == nil {
+			article.Tags = []models.Tag{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrArticleRevisionNotFound 修订记录不存在或不属于该文章
+var ErrArticleRevisionNotFound = fmt.Errorf("article revision %w", ErrNotFound)
+
+// AddRevisionTx 在事务中写入一条文章修订记录
+// revision: 修订记录，会设置 ID 和创建时间（未设置时）
+func (r *ArticleRepository) AddRevisionTx(tx *gorm.DB, revision *models.ArticleRevision) error {
+	if revision.ID == uuid.Nil {
+		revision.ID = uuid.New()
+	}
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	return tx.Exec(`
+		INSERT INTO article_revisions (id, article_id, version, title, content, excerpt, status, editor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, revision.ID, revision.ArticleID, revision.Version, revision.Title, revision.Content, revision.Excerpt,
+		revision.Status, revision.EditorID, revision.CreatedAt).Error
+}
+
+// ListRevisions 分页获取文章的修订记录（最新的在前，不含正文），附带修改者用户名
+func (r *ArticleRepository) ListRevisions(ctx context.Context, articleID uuid.UUID, page, pageSize int) ([]*models.ArticleRevisionMeta, int64, error) {
+	db := database.DB.WithContext(ctx)
+	var total int64
+	if err := db.Raw(`SELECT COUNT(*) FROM article_revisions WHERE article_id = $1`, articleID).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []*models.ArticleRevisionMeta
+	err := db.Raw(`
+		SELECT v.id, v.article_id, v.version, v.title, v.status, v.editor_id, v.created_at,
+			   COALESCE(u.username, '') AS editor_name
+		FROM article_revisions v
+		LEFT JOIN users u ON u.id = v.editor_id
+		WHERE v.article_id = $1
+		ORDER BY v.created_at DESC, v.id DESC
+		LIMIT $2 OFFSET $3
+	`, articleID, pageSize, (page-1)*pageSize).Scan(&revisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
+
+// GetRevision 获取文章的一条修订记录（含正文）
+// 返回: 修订记录不存在或不属于该文章时返回 ErrArticleRevisionNotFound
+func (r *ArticleRepository) GetRevision(ctx context.Context, articleID, revisionID uuid.UUID) (*models.ArticleRevision, error) {
+	revision := &models.ArticleRevision{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT v.id, v.article_id, v.version, v.title, v.content, v.excerpt, v.status, v.editor_id, v.created_at,
+			   COALESCE(u.username, '') AS editor_name
+		FROM article_revisions v
+		LEFT JOIN users u ON u.id = v.editor_id
+		WHERE v.id = $1 AND v.article_id = $2
+	`, revisionID, articleID).Scan(revision)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrArticleRevisionNotFound
+	}
+	return revision, nil
+}
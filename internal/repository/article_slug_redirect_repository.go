@@ -0,0 +1,39 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"time"
+
+	"enterprise-blog/internal/database"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AddSlugRedirectTx 在调用方的事务中记录文章改名前的 slug
+// oldSlug: 修改前的 slug，之后按它访问解析到该文章（已指向其他文章时改为指向该文章）
+// newSlug: 修改后的 slug，如曾是某篇文章的旧 slug，该记录删除（正在使用的 slug 优先）
+func (r *ArticleRepository) AddSlugRedirectTx(tx *gorm.DB, articleID uuid.UUID, oldSlug, newSlug string) error {
+	if err := tx.Exec(`DELETE FROM article_slug_redirects WHERE old_slug = $1`, newSlug).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO article_slug_redirects (old_slug, article_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (old_slug) DO UPDATE SET article_id = EXCLUDED.article_id, created_at = EXCLUDED.created_at
+	`, oldSlug, articleID, time.Now()).Error
+}
+
+// ResolveSlugRedirect 将文章的旧 slug 解析为文章 ID
+// 返回: 文章 ID；slug 不是旧 slug 时返回 false
+func (r *ArticleRepository) ResolveSlugRedirect(ctx context.Context, slug string) (uuid.UUID, bool, error) {
+	var ids []uuid.UUID
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT article_id FROM article_slug_redirects WHERE old_slug = $1
+	`, slug).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return uuid.Nil, false, err
+	}
+	return ids[0], true, nil
+}
@@ -0,0 +1,42 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AddStatusChangeTx 在事务中写入一条文章状态变更记录
+// change: 变更记录，会设置 ID 和创建时间（未设置时）
+func (r *ArticleRepository) AddStatusChangeTx(tx *gorm.DB, change *models.ArticleStatusChange) error {
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	return tx.Exec(`
+		INSERT INTO article_status_history (id, article_id, from_status, to_status, actor_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, change.ID, change.ArticleID, change.FromStatus, change.ToStatus, change.ActorID, change.Reason, change.CreatedAt).Error
+}
+
+// ListStatusHistory 获取文章的全部状态变更记录（按时间先后），附带操作者用户名
+func (r *ArticleRepository) ListStatusHistory(ctx context.Context, articleID uuid.UUID) ([]*models.ArticleStatusChange, error) {
+	var changes []*models.ArticleStatusChange
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT h.id, h.article_id, h.from_status, h.to_status, h.actor_id, h.reason, h.created_at,
+			   COALESCE(u.username, '') AS actor_name
+		FROM article_status_history h
+		LEFT JOIN users u ON u.id = h.actor_id
+		WHERE h.article_id = $1
+		ORDER BY h.created_at, h.id
+	`, articleID).Scan(&changes).Error
+	return changes, err
+}
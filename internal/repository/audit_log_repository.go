@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrAuditLogNotFound 审计日志不存在
+var ErrAuditLogNotFound = fmt.Errorf("audit log %w", ErrNotFound)
+
+type AuditLogRepository struct{}
+
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+// Create 写入一条审计日志
+func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	var payload interface{}
+	if len(log.Payload) > 0 {
+		payload = string(log.Payload)
+	}
+
+	// SQLite 没有 JSONB 类型，请求体按文本存储
+	payloadExpr := "CAST(? AS JSONB)"
+	if database.IsSQLite() {
+		payloadExpr = "?"
+	}
+
+	query := `
+		INSERT INTO audit_logs (id, actor_id, impersonator_id, method, path, action, resource_type, resource_id,
+			status_code, ip, user_agent, payload, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ` + payloadExpr + `, ?)
+	`
+	return database.DB.WithContext(ctx).Exec(query,
+		log.ID, log.ActorID, log.ImpersonatorID, log.Method, log.Path, log.Action, log.ResourceType, log.ResourceID,
+		log.StatusCode, log.IP, log.UserAgent, payload, log.CreatedAt,
+	).Error
+}
+
+// List 按条件分页查询审计日志（不含请求体）
+func (r *AuditLogRepository) List(ctx context.Context, query models.AuditLogQuery) ([]*models.AuditLog, int64, error) {
+	var logs []*models.AuditLog
+	var total int64
+
+	query.Page, query.PageSize = config.NormalizePage(config.PageOther, query.Page, query.PageSize)
+	offset := (query.Page - 1) * query.PageSize
+
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if query.ActorID != nil {
+		where = append(where, "l.actor_id = ?")
+		args = append(args, *query.ActorID)
+	}
+	if query.PathPrefix != "" {
+		// 转义 LIKE 通配符，按字面前缀匹配
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query.PathPrefix)
+		where = append(where, "l.path LIKE ?")
+		args = append(args, escaped+"%")
+	}
+	if query.Method != "" {
+		where = append(where, "l.method = ?")
+		args = append(args, strings.ToUpper(query.Method))
+	}
+	if query.From != nil {
+		where = append(where, "l.created_at >= ?")
+		args = append(args, *query.From)
+	}
+	if query.To != nil {
+		where = append(where, "l.created_at < ?")
+		args = append(args, query.To.AddDate(0, 0, 1))
+	}
+
+	whereClause := strings.Join(where, " AND ")
+
+	countQuery := "SELECT COUNT(*) FROM audit_logs l WHERE " + whereClause
+	if err := database.DB.WithContext(ctx).Raw(countQuery, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	listQuery := `
+		SELECT l.id, l.actor_id, COALESCE(u.username, '') AS actor_username, l.impersonator_id, l.method, l.path, l.action,
+			   l.resource_type, l.resource_id, l.status_code, l.ip, l.user_agent, l.created_at
+		FROM audit_logs l
+		LEFT JOIN users u ON u.id = l.actor_id
+		WHERE ` + whereClause + `
+		ORDER BY l.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, query.PageSize, offset)
+	err := database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&logs).Error
+	return logs, total, err
+}
+
+// GetByID 获取审计日志详情（含请求体）
+func (r *AuditLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error) {
+	log := &models.AuditLog{}
+	query := `
+		SELECT l.id, l.actor_id, COALESCE(u.username, '') AS actor_username, l.impersonator_id, l.method, l.path, l.action,
+			   l.resource_type, l.resource_id, l.status_code, l.ip, l.user_agent, l.payload, l.created_at
+		FROM audit_logs l
+		LEFT JOIN users u ON u.id = l.actor_id
+		WHERE l.id = ?
+	`
+	result := database.DB.WithContext(ctx).Raw(query, id).Scan(log)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAuditLogNotFound
+	}
+	return log, nil
+}
+
+// DeleteBefore 删除指定时间之前的审计日志
+// 返回: 删除的行数
+func (r *AuditLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := database.DB.WithContext(ctx).Exec(`DELETE FROM audit_logs WHERE created_at < ?`, before)
+	return result.RowsAffected, result.Error
+}
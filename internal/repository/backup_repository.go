@@ -0,0 +1,192 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"enterprise-blog/internal/database"
+)
+
+// BackupTable 参与备份与恢复的表
+type BackupTable struct {
+	Name string
+	// Keys 主键列，导出时按其做游标分页
+	Keys []string
+	// ParentColumn 指向本表的父级列（如 categories.parent_id），恢复时先写入 NULL，整表写入后再回填
+	ParentColumn string
+	// Skip 不导出的派生列（如由触发器维护的全文索引列）
+	Skip []string
+}
+
+// BackupTables 参与备份的表，按外键依赖排序（被引用的表在前），恢复时按此顺序写入
+var BackupTables = []BackupTable{
+	{Name: "users", Keys: []string{"id"}},
+	{Name: "categories", Keys: []string{"id"}, ParentColumn: "parent_id"},
+	{Name: "tags", Keys: []string{"id"}},
+	{Name: "tag_aliases", Keys: []string{"source_tag_id"}},
+	{Name: "articles", Keys: []string{"id"}, Skip: []string{"search_vector"}},
+	{Name: "article_tags", Keys: []string{"article_id", "tag_id"}},
+	{Name: "article_likes", Keys: []string{"id"}},
+	{Name: "comments", Keys: []string{"id"}, ParentColumn: "parent_id"},
+	{Name: "images", Keys: []string{"id"}},
+	{Name: "article_status_history", Keys: []string{"id"}},
+	{Name: "article_revisions", Keys: []string{"id"}},
+	{Name: "article_slug_redirects", Keys: []string{"old_slug"}},
+}
+
+// backupColumnPattern 允许写入的列名，归档中的列名会拼接进 SQL，必须先校验
+var backupColumnPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// LookupBackupTable 根据表名查找备份表定义
+func LookupBackupTable(name string) (BackupTable, bool) {
+	for _, t := range BackupTables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return BackupTable{}, false
+}
+
+// BackupRepository 备份与恢复的数据访问层，按表整行读写（包括已软删除的行）
+type BackupRepository struct{}
+
+// NewBackupRepository 创建新的备份仓库实例
+func NewBackupRepository() *BackupRepository {
+	return &BackupRepository{}
+}
+
+// Count 统计表中的全部行数（包括已软删除的行）
+func (r *BackupRepository) Count(ctx context.Context, table BackupTable) (int64, error) {
+	var total int64
+	err := database.DB.WithContext(ctx).Raw(`SELECT COUNT(*) FROM ` + table.Name).Scan(&total).Error
+	return total, err
+}
+
+// HasRows 表中是否有数据
+func (r *BackupRepository) HasRows(ctx context.Context, table BackupTable) (bool, error) {
+	var found []int
+	err := database.DB.WithContext(ctx).Raw(`SELECT 1 FROM ` + table.Name + ` LIMIT 1`).Scan(&found).Error
+	return len(found) > 0, err
+}
+
+// ExportBatch 按主键游标读取一批数据
+// after: 上一批最后一行的主键值（与 table.Keys 一一对应），首批传 nil
+// limit: 每批数量
+// 返回: 列名到值的映射，值保持驱动返回的类型；少于 limit 行表示已读完
+func (r *BackupRepository) ExportBatch(ctx context.Context, table BackupTable, after []interface{}, limit int) ([]map[string]interface{}, error) {
+	keys := strings.Join(table.Keys, ", ")
+	query := `SELECT * FROM ` + table.Name
+	args := make([]interface{}, 0, len(after)+1)
+	if len(after) > 0 {
+		placeholders := make([]string, len(after))
+		for i, v := range after {
+			args = append(args, v)
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		}
+		// 行值比较，PostgreSQL 和 SQLite 均支持
+		query += ` WHERE (` + keys + `) > (` + strings.Join(placeholders, ", ") + `)`
+	}
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d`, keys, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := database.DB.WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	skip := make(map[string]bool, len(table.Skip))
+	for _, c := range table.Skip {
+		skip[c] = true
+	}
+
+	var batch []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, c := range columns {
+			if !skip[c] {
+				row[c] = values[i]
+			}
+		}
+		batch = append(batch, row)
+	}
+	return batch, rows.Err()
+}
+
+// InsertBatch 批量写入一批数据，主键或唯一约束冲突的行跳过
+// rows: 列名到值的映射，同一批的行必须包含相同的列
+// 返回: 实际写入的行数
+func (r *BackupRepository) InsertBatch(ctx context.Context, table BackupTable, rows []map[string]interface{}) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	columns := make([]string, 0, len(rows[0]))
+	for c := range rows[0] {
+		if !backupColumnPattern.MatchString(c) {
+			return 0, fmt.Errorf("invalid column name %q in table %s", c, table.Name)
+		}
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	// 列名加引号，兼容 order 等保留字
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = `"` + c + `"`
+	}
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO ` + table.Name + ` (` + strings.Join(quoted, ", ") + `) VALUES `)
+	args := make([]interface{}, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("table %s: row %d has %d columns, expected %d", table.Name, i, len(row), len(columns))
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j, c := range columns {
+			v, ok := row[c]
+			if !ok {
+				return 0, fmt.Errorf("table %s: row %d is missing column %s", table.Name, i, c)
+			}
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, v)
+			fmt.Fprintf(&sb, "$%d", len(args))
+		}
+		sb.WriteString(")")
+	}
+	sb.WriteString(` ON CONFLICT DO NOTHING`)
+
+	result := database.DB.WithContext(ctx).Exec(sb.String(), args...)
+	return result.RowsAffected, result.Error
+}
+
+// SetParent 回填自引用的父级列
+// id: 行的主键；parentID: 父级主键
+func (r *BackupRepository) SetParent(ctx context.Context, table BackupTable, id, parentID interface{}) error {
+	if table.ParentColumn == "" {
+		return fmt.Errorf("table %s has no parent column", table.Name)
+	}
+	return database.DB.WithContext(ctx).Exec(
+		`UPDATE `+table.Name+` SET `+table.ParentColumn+` = $1 WHERE `+table.Keys[0]+` = $2`, parentID, id,
+	).Error
+}
@@ -1,23 +1,28 @@
 package repository
 
 import (
-	"database/sql"
-	"errors"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// ErrCategoryNotFound 分类不存在或已删除
+var ErrCategoryNotFound = fmt.Errorf("category %w", ErrNotFound)
+
 type CategoryRepository struct{}
 
 func NewCategoryRepository() *CategoryRepository {
 	return &CategoryRepository{}
 }
 
-func (r *CategoryRepository) Create(category *models.Category) error {
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
 	query := `
 		INSERT INTO categories (id, name, slug, description, parent_id, "order", created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
@@ -29,65 +34,203 @@ func (r *CategoryRepository) Create(category *models.Category) error {
 	category.CreatedAt = now
 	category.UpdatedAt = now
 
-	row := database.DB.Raw(
+	row := database.DB.WithContext(ctx).Raw(
 		query,
 		category.ID, category.Name, category.Slug, category.Description,
 		category.ParentID, category.Order, category.CreatedAt, category.UpdatedAt,
 	).Row()
-	return row.Scan(&category.ID)
+	return wrapConflict(row.Scan(&category.ID))
 }
 
-func (r *CategoryRepository) GetByID(id uuid.UUID) (*models.Category, error) {
+func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
 	category := &models.Category{}
 	query := `SELECT id, name, slug, description, parent_id, "order", created_at, updated_at
-			  FROM categories WHERE id = $1`
+			  FROM categories WHERE id = $1 AND deleted_at IS NULL`
 	
-	err := database.DB.Raw(query, id).Scan(category).Error
-	if err == sql.ErrNoRows {
-		return nil, errors.New("category not found")
+	result := database.DB.WithContext(ctx).Raw(query, id).Scan(category)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// GetBySlug 根据slug获取分类（不含已删除，大小写不敏感）
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	category := &models.Category{}
+	query := `SELECT id, name, slug, description, parent_id, "order", created_at, updated_at
+			  FROM categories WHERE LOWER(slug) = LOWER($1) AND deleted_at IS NULL`
+
+	result := database.DB.WithContext(ctx).Raw(query, slug).Scan(category)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCategoryNotFound
 	}
-	return category, err
+	return category, nil
 }
 
-func (r *CategoryRepository) Update(category *models.Category) error {
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
 	query := `
 		UPDATE categories 
 		SET name = $2, slug = $3, description = $4, parent_id = $5, "order" = $6, updated_at = $7
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	
 	category.UpdatedAt = time.Now()
-	result := database.DB.Exec(query, category.ID, category.Name, category.Slug,
+	result := database.DB.WithContext(ctx).Exec(query, category.ID, category.Name, category.Slug,
 		category.Description, category.ParentID, category.Order, category.UpdatedAt)
 	if result.Error != nil {
-		return result.Error
+		return wrapConflict(result.Error)
 	}
 	
 	if result.RowsAffected == 0 {
-		return errors.New("category not found")
+		return ErrCategoryNotFound
 	}
 	return nil
 }
 
-func (r *CategoryRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM categories WHERE id = $1`
-	result := database.DB.Exec(query, id)
+// Delete 删除分类（软删除，可在回收站中恢复）
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.DeleteTx(database.DB.WithContext(ctx), id)
+}
+
+// DeleteTx 在调用方的事务中软删除分类
+func (r *CategoryRepository) DeleteTx(tx *gorm.DB, id uuid.UUID) error {
+	query := `UPDATE categories SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result := tx.Exec(query, time.Now(), id)
 	if result.Error != nil {
 		return result.Error
 	}
 	
 	if result.RowsAffected == 0 {
-		return errors.New("category not found")
+		return ErrCategoryNotFound
 	}
 	return nil
 }
 
-func (r *CategoryRepository) List() ([]*models.Category, error) {
+// CountArticles 统计引用该分类的未删除文章数（含草稿）
+func (r *CategoryRepository) CountArticles(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM articles WHERE category_id = $1 AND deleted_at IS NULL`
+	err := database.DB.WithContext(ctx).Raw(query, id).Scan(&count).Error
+	return count, err
+}
+
+// CountChildren 统计未删除的直接子分类数
+func (r *CategoryRepository) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM categories WHERE parent_id = $1 AND deleted_at IS NULL`
+	err := database.DB.WithContext(ctx).Raw(query, id).Scan(&count).Error
+	return count, err
+}
+
+// IsDescendant 判断 id 是否为 ancestorID 的子孙分类（用于避免转移子分类时形成环）
+func (r *CategoryRepository) IsDescendant(ctx context.Context, ancestorID, id uuid.UUID) (bool, error) {
+	var count int64
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM categories WHERE parent_id = $1
+			UNION
+			SELECT c.id FROM categories c INNER JOIN descendants d ON c.parent_id = d.id
+		)
+		SELECT COUNT(*) FROM descendants WHERE id = $2
+	`
+	err := database.DB.WithContext(ctx).Raw(query, ancestorID, id).Scan(&count).Error
+	return count > 0, err
+}
+
+// ArticleIDsTx 在调用方的事务中获取分类下的全部文章 ID（含草稿和已删除的文章）
+func (r *CategoryRepository) ArticleIDsTx(tx *gorm.DB, categoryID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := tx.Raw(`SELECT id FROM articles WHERE category_id = $1`, categoryID).Scan(&ids).Error
+	return ids, err
+}
+
+// ReassignArticlesTx 在调用方的事务中将分类下的全部文章（含草稿和已删除）移动到另一个分类
+// 返回: 移动的文章数
+// 注意: 同时递增文章版本号，之前读取文章的编辑者保存时会得到版本冲突
+func (r *CategoryRepository) ReassignArticlesTx(tx *gorm.DB, from, to uuid.UUID) (int64, error) {
+	query := `UPDATE articles SET category_id = $1, updated_at = $2, version = version + 1 WHERE category_id = $3`
+	result := tx.Exec(query, to, time.Now(), from)
+	return result.RowsAffected, result.Error
+}
+
+// ReassignChildrenTx 在调用方的事务中将直接子分类（含已删除）挂到另一个父分类下
+// 返回: 移动的子分类数
+func (r *CategoryRepository) ReassignChildrenTx(tx *gorm.DB, from, to uuid.UUID) (int64, error) {
+	query := `UPDATE categories SET parent_id = $1, updated_at = $2 WHERE parent_id = $3`
+	result := tx.Exec(query, to, time.Now(), from)
+	return result.RowsAffected, result.Error
+}
+
+// List 获取全部未删除的分类及其文章数
+// query: PublishedOnly 为 true 时只统计已发布文章；Sort 为 article_count 时按文章数倒序，否则按 order 排序
+// 注意: 文章数通过一次分组联表查询得到，已删除的文章不计入
+func (r *CategoryRepository) List(ctx context.Context, query models.TaxonomyListQuery) ([]*models.Category, error) {
 	var categories []*models.Category
-	query := `SELECT id, name, slug, description, parent_id, "order", created_at, updated_at
-			  FROM categories ORDER BY "order" ASC, created_at DESC`
-	
-	err := database.DB.Raw(query).Scan(&categories).Error
+	articleFilter := ""
+	var args []interface{}
+	if query.PublishedOnly {
+		articleFilter = " AND a.status = ?"
+		args = append(args, models.StatusPublished)
+	}
+	orderBy := `c."order" ASC, c.created_at DESC`
+	if query.Sort == "article_count" {
+		orderBy = `article_count DESC, c."order" ASC`
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT c.id, c.name, c.slug, c.description, c.parent_id, c."order", c.created_at, c.updated_at,
+			   COUNT(a.id) AS article_count
+		FROM categories c
+		LEFT JOIN articles a ON a.category_id = c.id AND a.deleted_at IS NULL%s
+		WHERE c.deleted_at IS NULL
+		GROUP BY c.id
+		ORDER BY %s
+	`, articleFilter, orderBy)
+	err := database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&categories).Error
 	return categories, err
 }
 
+// Autocomplete 按名称查找分类（大小写不敏感的子串匹配），用于编辑器自动补全
+// q: 关键词；limit: 返回条数
+// 返回: 名称与关键词相同的分类排在最前，其余按已发布文章数倒序、名称排序
+func (r *CategoryRepository) Autocomplete(ctx context.Context, q string, limit int) ([]*models.TaxonomySuggestion, error) {
+	var suggestions []*models.TaxonomySuggestion
+	query := `
+		SELECT c.id, c.name, c.slug, COUNT(a.id) AS usage_count
+		FROM categories c
+		LEFT JOIN articles a ON a.category_id = c.id AND a.deleted_at IS NULL AND a.status = ?
+		WHERE c.deleted_at IS NULL AND LOWER(c.name) LIKE ? ESCAPE '\'
+		GROUP BY c.id, c.name, c.slug
+		ORDER BY CASE WHEN LOWER(c.name) = ? THEN 0 ELSE 1 END, usage_count DESC, c.name ASC
+		LIMIT ?
+	`
+	lower := strings.ToLower(q)
+	err := database.DB.WithContext(ctx).Raw(query, models.StatusPublished, containsPattern(lower), lower, limit).Scan(&suggestions).Error
+	return suggestions, err
+}
+
+// Stats 按分类统计已发布文章数、总浏览量、总评论数和最近发布时间
+// 注意: 使用一次分组联表查询完成，没有文章的分类也会返回（计数为 0）
+func (r *CategoryRepository) Stats(ctx context.Context, query models.ContentStatsQuery) ([]*models.ContentStats, error) {
+	var stats []*models.ContentStats
+	statsQuery := fmt.Sprintf(`
+		SELECT c.id, c.name, c.slug,
+			   COUNT(a.id) AS published_articles,
+			   COALESCE(SUM(a.view_count), 0) AS total_views,
+			   COALESCE(SUM(a.comment_count), 0) AS total_comments,
+			   MAX(a.published_at) AS last_published_at
+		FROM categories c
+		LEFT JOIN articles a ON a.category_id = c.id AND a.status = ? AND a.deleted_at IS NULL
+		WHERE c.deleted_at IS NULL
+		GROUP BY c.id, c.name, c.slug
+		ORDER BY %s
+	`, contentStatsOrderBy(query))
+	err := database.DB.WithContext(ctx).Raw(statsQuery, models.StatusPublished).Scan(&stats).Error
+	return stats, err
+}
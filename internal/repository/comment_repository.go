@@ -1,23 +1,34 @@
 package repository
 
 import (
-	"database/sql"
-	"errors"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// ErrCommentNotFound 评论不存在或已删除
+var ErrCommentNotFound = fmt.Errorf("comment %w", ErrNotFound)
+
 type CommentRepository struct{}
 
 func NewCommentRepository() *CommentRepository {
 	return &CommentRepository{}
 }
 
-func (r *CommentRepository) Create(comment *models.Comment) error {
+func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	return r.CreateTx(database.DB.WithContext(ctx), comment)
+}
+
+// CreateTx 在调用方的事务中插入评论
+func (r *CommentRepository) CreateTx(tx *gorm.DB, comment *models.Comment) error {
 	query := `
 		INSERT INTO comments (id, article_id, user_id, parent_id, content, author, email, website, ip, status, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
@@ -32,7 +43,7 @@ func (r *CommentRepository) Create(comment *models.Comment) error {
 		comment.Status = "pending"
 	}
 
-	row := database.DB.Raw(
+	row := tx.Raw(
 		query,
 		comment.ID, comment.ArticleID, comment.UserID, comment.ParentID,
 		comment.Content, comment.Author, comment.Email, comment.Website,
@@ -41,25 +52,25 @@ func (r *CommentRepository) Create(comment *models.Comment) error {
 	return row.Scan(&comment.ID)
 }
 
-func (r *CommentRepository) GetByID(id uuid.UUID) (*models.Comment, error) {
+func (r *CommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
 	comment := &models.Comment{}
 	query := `
 		SELECT id, article_id, user_id, parent_id, content, author, email, website, ip, status, created_at, updated_at
-		FROM comments WHERE id = $1
+		FROM comments WHERE id = $1 AND deleted_at IS NULL
 	`
 	
-	err := database.DB.Raw(query, id).Scan(comment).Error
-	if err == sql.ErrNoRows {
-		return nil, errors.New("comment not found")
+	result := database.DB.WithContext(ctx).Raw(query, id).Scan(comment)
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	if err != nil {
-		return nil, err
+	if result.RowsAffected == 0 {
+		return nil, ErrCommentNotFound
 	}
 
 	// 加载用户信息
 	if comment.UserID != nil {
 		var user models.User
-		err = database.DB.Raw("SELECT id, username, email, avatar FROM users WHERE id = $1", *comment.UserID).Scan(&user).Error
+		err := database.DB.WithContext(ctx).Raw("SELECT id, username, email, avatar FROM users WHERE id = $1", *comment.UserID).Scan(&user).Error
 		if err == nil {
 			comment.User = &user
 		}
@@ -68,15 +79,15 @@ func (r *CommentRepository) GetByID(id uuid.UUID) (*models.Comment, error) {
 	return comment, nil
 }
 
-func (r *CommentRepository) GetByArticleID(articleID uuid.UUID, page, pageSize int) ([]*models.Comment, int64, error) {
+func (r *CommentRepository) GetByArticleID(ctx context.Context, articleID uuid.UUID, page, pageSize int) ([]*models.Comment, int64, error) {
 	var comments []*models.Comment
 	var total int64
 
 	offset := (page - 1) * pageSize
 
 	// 获取总数
-	countQuery := `SELECT COUNT(*) FROM comments WHERE article_id = $1 AND parent_id IS NULL`
-	err := database.DB.Raw(countQuery, articleID).Scan(&total).Error
+	countQuery := `SELECT COUNT(*) FROM comments WHERE article_id = $1 AND parent_id IS NULL AND deleted_at IS NULL`
+	err := database.DB.WithContext(ctx).Raw(countQuery, articleID).Scan(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -85,64 +96,213 @@ func (r *CommentRepository) GetByArticleID(articleID uuid.UUID, page, pageSize i
 	query := `
 		SELECT id, article_id, user_id, parent_id, content, author, email, website, ip, status, created_at, updated_at
 		FROM comments
-		WHERE article_id = $1 AND parent_id IS NULL
+		WHERE article_id = $1 AND parent_id IS NULL AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 	
-	err = database.DB.Raw(query, articleID, pageSize, offset).Scan(&comments).Error
+	err = database.DB.WithContext(ctx).Raw(query, articleID, pageSize, offset).Scan(&comments).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// 加载用户信息和子评论
-	for i := range comments {
-		if comments[i].UserID != nil {
-			var user models.User
-			err = database.DB.Raw("SELECT id, username, email, avatar FROM users WHERE id = $1", *comments[i].UserID).Scan(&user).Error
-			if err == nil {
-				comments[i].User = &user
-			}
-		}
+	if err := r.attachReplies(ctx, comments); err != nil {
+		return nil, 0, err
+	}
+
+	// 加载用户信息（父评论和回复一起批量查询）
+	all := comments
+	for _, c := range comments {
+		all = append(all, c.Replies...)
+	}
+	r.attachUsers(ctx, all)
+
+	return comments, total, nil
+}
+
+// List 管理后台按条件分页查询评论（包括回复），按创建时间倒序，不加载回复列表
+func (r *CommentRepository) List(ctx context.Context, query models.CommentQuery) ([]*models.Comment, int64, error) {
+	var comments []*models.Comment
+	var total int64
+
+	query.Page, query.PageSize = config.NormalizePage(config.PageComments, query.Page, query.PageSize)
+	offset := (query.Page - 1) * query.PageSize
+
+	where := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+	if query.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, query.Status)
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	// 获取总数
+	countQuery := "SELECT COUNT(*) FROM comments WHERE " + whereClause
+	if err := database.DB.WithContext(ctx).Raw(countQuery, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
+	// 获取列表
+	listQuery := `SELECT id, article_id, user_id, parent_id, content, author, email, website, ip, status, created_at, updated_at
+			  FROM comments WHERE ` + whereClause + `
+			  ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, query.PageSize, offset)
+	if err := database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	r.attachUsers(ctx, comments)
 	return comments, total, nil
 }
 
-func (r *CommentRepository) Update(comment *models.Comment) error {
+// attachReplies 用一次查询加载 parents 下的全部回复，填充到各自的 Replies
+// 注意: 回复只保留一层，回复的回复同样挂在最上层的父评论下；回复按创建时间正序
+func (r *CommentRepository) attachReplies(ctx context.Context, parents []*models.Comment) error {
+	if len(parents) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Comment, len(parents))
+	ids := make([]uuid.UUID, 0, len(parents))
+	for _, p := range parents {
+		p.Replies = []*models.Comment{}
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	var rows []struct {
+		models.Comment
+		RootID uuid.UUID
+	}
+	err := database.DB.WithContext(ctx).Raw(`
+		WITH RECURSIVE thread(id, root_id) AS (
+			SELECT id, parent_id FROM comments WHERE parent_id IN ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, t.root_id FROM comments c INNER JOIN thread t ON c.parent_id = t.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT c.id, c.article_id, c.user_id, c.parent_id, c.content, c.author, c.email, c.website, c.ip, c.status,
+			c.created_at, c.updated_at, t.root_id
+		FROM comments c INNER JOIN thread t ON t.id = c.id
+		ORDER BY c.created_at ASC, c.id
+	`, ids).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		if parent, ok := byID[rows[i].RootID]; ok {
+			reply := rows[i].Comment
+			parent.Replies = append(parent.Replies, &reply)
+		}
+	}
+	return nil
+}
+
+// attachUsers 批量加载评论作者的用户信息，查询失败时不填充
+func (r *CommentRepository) attachUsers(ctx context.Context, comments []*models.Comment) {
+	var ids []uuid.UUID
+	for _, c := range comments {
+		if c.UserID != nil {
+			ids = append(ids, *c.UserID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	var users []*models.User
+	if err := database.DB.WithContext(ctx).Raw("SELECT id, username, email, avatar FROM users WHERE id IN ?", ids).Scan(&users).Error; err != nil {
+		return
+	}
+	byID := make(map[uuid.UUID]*models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	for _, c := range comments {
+		if c.UserID != nil {
+			c.User = byID[*c.UserID]
+		}
+	}
+}
+
+// ListApprovedByArticleIDs 批量获取多篇文章已通过审核的评论（含回复，GraphQL 数据加载器使用）
+// limit: 每篇文章最多返回的评论数，取最新的
+// 返回: 按文章 ID 分组的评论，组内按创建时间倒序；没有评论的文章不在结果中
+func (r *CommentRepository) ListApprovedByArticleIDs(ctx context.Context, articleIDs []uuid.UUID, limit int) (map[uuid.UUID][]*models.Comment, error) {
+	result := make(map[uuid.UUID][]*models.Comment, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+
+	var comments []*models.Comment
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT id, article_id, user_id, parent_id, content, author, email, website, ip, status, created_at, updated_at
+		FROM (
+			SELECT c.*, ROW_NUMBER() OVER (PARTITION BY article_id ORDER BY created_at DESC, id) AS rn
+			FROM comments c
+			WHERE article_id IN ? AND status = ? AND deleted_at IS NULL
+		) ranked
+		WHERE rn <= ?
+		ORDER BY article_id, created_at DESC, id
+	`, articleIDs, models.CommentStatusApproved, limit).Scan(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range comments {
+		result[c.ArticleID] = append(result[c.ArticleID], c)
+	}
+	return result, nil
+}
+
+func (r *CommentRepository) Update(ctx context.Context, comment *models.Comment) error {
 	query := `
 		UPDATE comments 
 		SET content = $2, status = $3, updated_at = $4
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	
 	comment.UpdatedAt = time.Now()
-	result := database.DB.Exec(query, comment.ID, comment.Content, comment.Status, comment.UpdatedAt)
+	result := database.DB.WithContext(ctx).Exec(query, comment.ID, comment.Content, comment.Status, comment.UpdatedAt)
 	if result.Error != nil {
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("comment not found")
+		return ErrCommentNotFound
 	}
 	return nil
 }
 
-func (r *CommentRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM comments WHERE id = $1`
-	result := database.DB.Exec(query, id)
+// Delete 软删除评论及其全部回复
+func (r *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+		WITH RECURSIVE thread(id) AS (
+			SELECT id FROM comments WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id FROM comments c INNER JOIN thread t ON c.parent_id = t.id
+		)
+		UPDATE comments SET deleted_at = $2
+		WHERE id IN (SELECT id FROM thread) AND deleted_at IS NULL
+	`
+	result := database.DB.WithContext(ctx).Exec(query, id, time.Now())
 	if result.Error != nil {
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("comment not found")
+		return ErrCommentNotFound
 	}
 	return nil
 }
 
-func (r *CommentRepository) IncrementCommentCount(articleID uuid.UUID) error {
+func (r *CommentRepository) IncrementCommentCount(ctx context.Context, articleID uuid.UUID) error {
+	return r.IncrementCommentCountTx(database.DB.WithContext(ctx), articleID)
+}
+
+// IncrementCommentCountTx 在调用方的事务中将文章评论数加一
+func (r *CommentRepository) IncrementCommentCountTx(tx *gorm.DB, articleID uuid.UUID) error {
 	query := `UPDATE articles SET comment_count = comment_count + 1 WHERE id = $1`
-	return database.DB.Exec(query, articleID).Error
+	return tx.Exec(query, articleID).Error
 }
 
@@ -0,0 +1,77 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrEmailVerificationNotFound 邮箱验证令牌不存在
+var ErrEmailVerificationNotFound = fmt.Errorf("email verification %w", ErrNotFound)
+
+// EmailVerificationRepository 邮箱验证令牌的数据访问
+type EmailVerificationRepository struct{}
+
+// NewEmailVerificationRepository 创建邮箱验证令牌仓库
+func NewEmailVerificationRepository() *EmailVerificationRepository {
+	return &EmailVerificationRepository{}
+}
+
+// Create 保存新的验证令牌（会设置 ID 和创建时间）
+func (r *EmailVerificationRepository) Create(ctx context.Context, v *models.EmailVerification) error {
+	v.ID = uuid.New()
+	v.CreatedAt = time.Now()
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO email_verifications (id, user_id, token_hash, expires_at, used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, v.ID, v.UserID, v.TokenHash, v.ExpiresAt, v.UsedAt, v.CreatedAt).Error
+}
+
+// GetByTokenHash 按令牌哈希获取验证记录（包括已使用和已过期的记录，由调用方判断）
+// 返回: 不存在时返回 ErrEmailVerificationNotFound
+func (r *EmailVerificationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.EmailVerification, error) {
+	v := &models.EmailVerification{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM email_verifications
+		WHERE token_hash = $1
+	`, tokenHash).Scan(v)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrEmailVerificationNotFound
+	}
+	return v, nil
+}
+
+// MarkUsed 将令牌标记为已使用
+// 返回: 令牌是否由本次调用标记；已被使用（如并发验证）时返回 false
+func (r *EmailVerificationRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := database.DB.WithContext(ctx).Exec(`
+		UPDATE email_verifications SET used_at = $1 WHERE id = $2 AND used_at IS NULL
+	`, time.Now(), id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateUnused 作废用户所有未使用的令牌（重新发送验证邮件时只保留最新的链接）
+func (r *EmailVerificationRepository) InvalidateUnused(ctx context.Context, userID uuid.UUID) error {
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE email_verifications SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL
+	`, time.Now(), userID).Error
+}
+
+// CountSince 统计用户在 since 之后生成的令牌数（用于限制发送频率）
+func (r *EmailVerificationRepository) CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM email_verifications WHERE user_id = $1 AND created_at >= $2
+	`, userID, since).Scan(&count).Error
+	return count, err
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrNotFound 记录不存在或已删除；各仓库的 ErrXxxNotFound 都包装了它，
+	// 调用方可以用 errors.Is(err, ErrNotFound) 统一判断
+	ErrNotFound = errors.New("not found")
+	// ErrConflict 写入违反唯一约束（slug、用户名、邮箱等已被占用）
+	ErrConflict = errors.New("conflict")
+)
+
+// conflictError 唯一约束冲突，保留数据库驱动的原始错误
+// 错误信息与原始错误相同（服务层按约束名区分具体冲突的字段），errors.Is(err, ErrConflict) 为 true
+type conflictError struct {
+	err error
+}
+
+func (e *conflictError) Error() string { return e.err.Error() }
+
+func (e *conflictError) Unwrap() error { return e.err }
+
+func (e *conflictError) Is(target error) bool { return target == ErrConflict }
+
+// wrapConflict 唯一约束冲突时包装为 ErrConflict，其他错误原样返回
+// 注意: 兼容 pq/pgx 与 go-sqlite3 的错误文案
+func wrapConflict(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "duplicate key value violates unique constraint") || strings.Contains(msg, "UNIQUE constraint failed") {
+		return &conflictError{err: err}
+	}
+	return err
+}
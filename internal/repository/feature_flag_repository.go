@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FeatureFlagRepository struct{}
+
+func NewFeatureFlagRepository() *FeatureFlagRepository {
+	return &FeatureFlagRepository{}
+}
+
+// List 获取全部功能开关覆盖值
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	var flags []*models.FeatureFlag
+	query := `SELECT name, enabled, rollout_percent, updated_by, created_at, updated_at FROM feature_flags ORDER BY name`
+	err := database.DB.WithContext(ctx).Raw(query).Scan(&flags).Error
+	return flags, err
+}
+
+// Upsert 批量写入功能开关覆盖值（同一事务内）
+// updatedBy: 操作人用户ID
+func (r *FeatureFlagRepository) Upsert(ctx context.Context, flags []*models.FeatureFlag, updatedBy *uuid.UUID) error {
+	now := time.Now()
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, f := range flags {
+			err := tx.Exec(`
+				INSERT INTO feature_flags (name, enabled, rollout_percent, updated_by, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+				ON CONFLICT (name) DO UPDATE
+				SET enabled = EXCLUDED.enabled, rollout_percent = EXCLUDED.rollout_percent,
+				    updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+			`, f.Name, f.Enabled, f.RolloutPercent, updatedBy, now, now).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
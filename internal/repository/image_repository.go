@@ -3,11 +3,11 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 
@@ -15,6 +15,9 @@ import (
 )
 
 // ImageRepository 图片数据访问层，提供图片相关的数据库操作
+// ErrImageNotFound 图片不存在或已删除
+var ErrImageNotFound = fmt.Errorf("image %w", ErrNotFound)
+
 type ImageRepository struct{}
 
 // NewImageRepository 创建新的图片仓库实例
@@ -64,12 +67,12 @@ func (r *ImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Im
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	err := database.DB.WithContext(ctx).Raw(query, id).Scan(image).Error
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("image not found")
-		}
-		return nil, err
+	result := database.DB.WithContext(ctx).Raw(query, id).Scan(image)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrImageNotFound
 	}
 
 	// 加载上传者信息
@@ -104,7 +107,7 @@ func (r *ImageRepository) Update(ctx context.Context, image *models.Image) error
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("image not found")
+		return ErrImageNotFound
 	}
 	return nil
 }
@@ -120,7 +123,7 @@ func (r *ImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("image not found")
+		return ErrImageNotFound
 	}
 	return nil
 }
@@ -133,15 +136,7 @@ func (r *ImageRepository) List(ctx context.Context, query models.ImageQuery) ([]
 	var images []*models.Image
 	var total int64
 
-	if query.Page <= 0 {
-		query.Page = 1
-	}
-	if query.PageSize <= 0 {
-		query.PageSize = 20
-	}
-	if query.PageSize > 100 {
-		query.PageSize = 100
-	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageImages, query.Page, query.PageSize)
 	offset := (query.Page - 1) * query.PageSize
 
 	// 构建查询条件
@@ -153,17 +148,32 @@ func (r *ImageRepository) List(ctx context.Context, query models.ImageQuery) ([]
 		args = append(args, *query.UploaderID)
 	}
 
+	// SQLite 没有全文检索和 JSONB，分别用 LIKE（每个词都需匹配）和 json_each 代替
+	sqlite := database.IsSQLite()
+
 	if query.Search != "" {
-		where = append(where, "to_tsvector('english', coalesce(filename, '') || ' ' || coalesce(description, '')) @@ to_tsquery('english', ?)")
 		searchTerms := strings.Fields(query.Search)
-		tsQuery := strings.Join(searchTerms, " & ")
-		args = append(args, tsQuery)
+		if sqlite {
+			for _, term := range searchTerms {
+				where = append(where, "(filename LIKE ? OR description LIKE ?)")
+				args = append(args, "%"+term+"%", "%"+term+"%")
+			}
+		} else {
+			where = append(where, "to_tsvector('english', coalesce(filename, '') || ' ' || coalesce(description, '')) @@ to_tsquery('english', ?)")
+			tsQuery := strings.Join(searchTerms, " & ")
+			args = append(args, tsQuery)
+		}
 	}
 
 	if query.Tag != "" {
-		where = append(where, "tags @> ?::jsonb")
-		tagJSON := `["` + query.Tag + `"]`
-		args = append(args, tagJSON)
+		if sqlite {
+			where = append(where, "EXISTS (SELECT 1 FROM json_each(tags) WHERE value = ?)")
+			args = append(args, query.Tag)
+		} else {
+			where = append(where, "tags @> ?::jsonb")
+			tagJSON := `["` + query.Tag + `"]`
+			args = append(args, tagJSON)
+		}
 	}
 
 	whereClause := strings.Join(where, " AND ")
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSubscriberNotFound 订阅者不存在
+	ErrSubscriberNotFound = fmt.Errorf("subscriber %w", ErrNotFound)
+	// ErrCampaignNotFound 群发记录不存在
+	ErrCampaignNotFound = fmt.Errorf("newsletter campaign %w", ErrNotFound)
+)
+
+type NewsletterRepository struct{}
+
+func NewNewsletterRepository() *NewsletterRepository {
+	return &NewsletterRepository{}
+}
+
+const subscriberColumns = `id, email, status, confirm_token_hash, confirm_expires_at, ip_address,
+	confirmed_at, unsubscribed_at, bounced_at, created_at, updated_at`
+
+// CreateSubscriber 创建订阅者
+func (r *NewsletterRepository) CreateSubscriber(ctx context.Context, subscriber *models.Subscriber) error {
+	if subscriber.ID == uuid.Nil {
+		subscriber.ID = uuid.New()
+	}
+	now := time.Now()
+	subscriber.CreatedAt, subscriber.UpdatedAt = now, now
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO subscribers (`+subscriberColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, subscriber.ID, subscriber.Email, subscriber.Status, subscriber.ConfirmTokenHash, subscriber.ConfirmExpiresAt,
+		subscriber.IPAddress, subscriber.ConfirmedAt, subscriber.UnsubscribedAt, subscriber.BouncedAt, now, now,
+	).Error
+}
+
+// UpdateSubscriber 修改订阅者的状态、确认令牌和各状态时间
+func (r *NewsletterRepository) UpdateSubscriber(ctx context.Context, subscriber *models.Subscriber) error {
+	subscriber.UpdatedAt = time.Now()
+	result := database.DB.WithContext(ctx).Exec(`
+		UPDATE subscribers
+		SET status = ?, confirm_token_hash = ?, confirm_expires_at = ?, ip_address = ?,
+			confirmed_at = ?, unsubscribed_at = ?, bounced_at = ?, updated_at = ?
+		WHERE id = ?
+	`, subscriber.Status, subscriber.ConfirmTokenHash, subscriber.ConfirmExpiresAt, subscriber.IPAddress,
+		subscriber.ConfirmedAt, subscriber.UnsubscribedAt, subscriber.BouncedAt, subscriber.UpdatedAt, subscriber.ID,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubscriberNotFound
+	}
+	return nil
+}
+
+// GetSubscriber 按 ID 获取订阅者
+func (r *NewsletterRepository) GetSubscriber(ctx context.Context, id uuid.UUID) (*models.Subscriber, error) {
+	return r.getSubscriber(ctx, `id = ?`, id)
+}
+
+// GetSubscriberByEmail 按邮箱（小写）获取订阅者
+func (r *NewsletterRepository) GetSubscriberByEmail(ctx context.Context, email string) (*models.Subscriber, error) {
+	return r.getSubscriber(ctx, `email = ?`, email)
+}
+
+// GetSubscriberByTokenHash 按确认令牌的哈希获取订阅者
+func (r *NewsletterRepository) GetSubscriberByTokenHash(ctx context.Context, tokenHash string) (*models.Subscriber, error) {
+	return r.getSubscriber(ctx, `confirm_token_hash = ?`, tokenHash)
+}
+
+func (r *NewsletterRepository) getSubscriber(ctx context.Context, where string, arg interface{}) (*models.Subscriber, error) {
+	subscriber := &models.Subscriber{}
+	result := database.DB.WithContext(ctx).Raw(`SELECT `+subscriberColumns+` FROM subscribers WHERE `+where, arg).Scan(subscriber)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrSubscriberNotFound
+	}
+	return subscriber, nil
+}
+
+// ListSubscribers 分页获取订阅者（最新的在前）
+// status: 按状态筛选，为空表示全部
+func (r *NewsletterRepository) ListSubscribers(ctx context.Context, status string, page, pageSize int) ([]*models.Subscriber, int64, error) {
+	var subscribers []*models.Subscriber
+	var total int64
+	db := database.DB.WithContext(ctx)
+
+	where := "1 = 1"
+	args := []interface{}{}
+	if status != "" {
+		where = "status = ?"
+		args = append(args, status)
+	}
+
+	if err := db.Raw(`SELECT COUNT(*) FROM subscribers WHERE `+where, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, pageSize, (page-1)*pageSize)
+	err := db.Raw(`
+		SELECT `+subscriberColumns+` FROM subscribers
+		WHERE `+where+`
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, args...).Scan(&subscribers).Error
+	return subscribers, total, err
+}
+
+// CountConfirmed 统计已确认的订阅者数
+func (r *NewsletterRepository) CountConfirmed(ctx context.Context) (int64, error) {
+	var total int64
+	err := database.DB.WithContext(ctx).Raw(`SELECT COUNT(*) FROM subscribers WHERE status = ?`, models.SubscriberConfirmed).Scan(&total).Error
+	return total, err
+}
+
+// ListConfirmedAfter 按 ID 游标分批读取已确认的订阅者
+// afterID: 上一批最后一个订阅者的 ID，首批传 uuid.Nil
+func (r *NewsletterRepository) ListConfirmedAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Subscriber, error) {
+	var subscribers []*models.Subscriber
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT `+subscriberColumns+` FROM subscribers
+		WHERE status = ? AND id > ?
+		ORDER BY id
+		LIMIT ?
+	`, models.SubscriberConfirmed, afterID, limit).Scan(&subscribers).Error
+	return subscribers, err
+}
+
+const campaignColumns = `id, subject, kind, article_id, digest_days, status, recipients,
+	sent_count, failed_count, bounced_count, error, created_by, created_at, finished_at`
+
+// CreateCampaign 创建群发记录
+func (r *NewsletterRepository) CreateCampaign(ctx context.Context, campaign *models.NewsletterCampaign) error {
+	if campaign.ID == uuid.Nil {
+		campaign.ID = uuid.New()
+	}
+	campaign.CreatedAt = time.Now()
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO newsletter_campaigns (`+campaignColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, campaign.ID, campaign.Subject, campaign.Kind, campaign.ArticleID, campaign.DigestDays, campaign.Status,
+		campaign.Recipients, campaign.SentCount, campaign.FailedCount, campaign.BouncedCount, campaign.Error,
+		campaign.CreatedBy, campaign.CreatedAt, campaign.FinishedAt,
+	).Error
+}
+
+// UpdateCampaignProgress 更新群发的状态和发送计数
+func (r *NewsletterRepository) UpdateCampaignProgress(ctx context.Context, campaign *models.NewsletterCampaign) error {
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE newsletter_campaigns
+		SET status = ?, sent_count = ?, failed_count = ?, bounced_count = ?, error = ?, finished_at = ?
+		WHERE id = ?
+	`, campaign.Status, campaign.SentCount, campaign.FailedCount, campaign.BouncedCount, campaign.Error,
+		campaign.FinishedAt, campaign.ID,
+	).Error
+}
+
+// GetCampaign 获取群发记录
+func (r *NewsletterRepository) GetCampaign(ctx context.Context, id uuid.UUID) (*models.NewsletterCampaign, error) {
+	campaign := &models.NewsletterCampaign{}
+	result := database.DB.WithContext(ctx).Raw(`SELECT `+campaignColumns+` FROM newsletter_campaigns WHERE id = ?`, id).Scan(campaign)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// ListCampaigns 分页获取群发记录（最新的在前）
+func (r *NewsletterRepository) ListCampaigns(ctx context.Context, page, pageSize int) ([]*models.NewsletterCampaign, int64, error) {
+	var campaigns []*models.NewsletterCampaign
+	var total int64
+	db := database.DB.WithContext(ctx)
+
+	if err := db.Raw(`SELECT COUNT(*) FROM newsletter_campaigns`).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Raw(`
+		SELECT `+campaignColumns+` FROM newsletter_campaigns
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, pageSize, (page-1)*pageSize).Scan(&campaigns).Error
+	return campaigns, total, err
+}
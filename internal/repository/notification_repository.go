@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotificationNotFound 通知不存在（或不属于当前用户）
+var ErrNotificationNotFound = fmt.Errorf("notification %w", ErrNotFound)
+
+type NotificationRepository struct{}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{}
+}
+
+const notificationColumns = `id, recipient_id, type, actor_id, actor_name, subject_type, subject_id, article_id,
+	summary, read_at, created_at`
+
+// Create 创建通知
+func (r *NotificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	n.CreatedAt = time.Now()
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO notifications (`+notificationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, n.ID, n.RecipientID, n.Type, n.ActorID, n.ActorName, n.SubjectType, n.SubjectID, n.ArticleID,
+		n.Summary, n.ReadAt, n.CreatedAt,
+	).Error
+}
+
+// List 分页获取用户的通知（最新的在前）
+// unreadOnly: 只返回未读通知
+func (r *NotificationRepository) List(ctx context.Context, recipientID uuid.UUID, unreadOnly bool, page, pageSize int) ([]*models.Notification, int64, error) {
+	var notifications []*models.Notification
+	var total int64
+	db := database.DB.WithContext(ctx)
+
+	where := "recipient_id = ?"
+	if unreadOnly {
+		where += " AND read_at IS NULL"
+	}
+
+	if err := db.Raw(`SELECT COUNT(*) FROM notifications WHERE `+where, recipientID).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Raw(`
+		SELECT `+notificationColumns+` FROM notifications
+		WHERE `+where+`
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, recipientID, pageSize, (page-1)*pageSize).Scan(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *NotificationRepository) CountUnread(ctx context.Context, recipientID uuid.UUID) (int64, error) {
+	var total int64
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM notifications WHERE recipient_id = ? AND read_at IS NULL
+	`, recipientID).Scan(&total).Error
+	return total, err
+}
+
+// MarkRead 将用户的一条通知标记为已读
+// 返回: 本次是否由未读变为已读；通知不存在或不属于该用户时返回 ErrNotificationNotFound
+func (r *NotificationRepository) MarkRead(ctx context.Context, recipientID, id uuid.UUID) (bool, error) {
+	db := database.DB.WithContext(ctx)
+	result := db.Exec(`
+		UPDATE notifications SET read_at = ? WHERE id = ? AND recipient_id = ? AND read_at IS NULL
+	`, time.Now(), id, recipientID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	var count int64
+	if err := db.Raw(`SELECT COUNT(*) FROM notifications WHERE id = ? AND recipient_id = ?`, id, recipientID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, ErrNotificationNotFound
+	}
+	return false, nil
+}
+
+// MarkAllRead 将用户的全部未读通知标记为已读
+// 返回: 标记的通知数
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, recipientID uuid.UUID) (int64, error) {
+	result := database.DB.WithContext(ctx).Exec(`
+		UPDATE notifications SET read_at = ? WHERE recipient_id = ? AND read_at IS NULL
+	`, time.Now(), recipientID)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,77 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrPasswordResetNotFound 找回密码令牌不存在
+var ErrPasswordResetNotFound = fmt.Errorf("password reset %w", ErrNotFound)
+
+// PasswordResetRepository 找回密码令牌的数据访问
+type PasswordResetRepository struct{}
+
+// NewPasswordResetRepository 创建找回密码令牌仓库
+func NewPasswordResetRepository() *PasswordResetRepository {
+	return &PasswordResetRepository{}
+}
+
+// Create 保存新的找回密码令牌（会设置 ID 和创建时间）
+func (r *PasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+	reset.ID = uuid.New()
+	reset.CreatedAt = time.Now()
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO password_resets (id, user_id, token_hash, expires_at, used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, reset.ID, reset.UserID, reset.TokenHash, reset.ExpiresAt, reset.UsedAt, reset.CreatedAt).Error
+}
+
+// GetByTokenHash 按令牌哈希获取记录（包括已使用和已过期的记录，由调用方判断）
+// 返回: 不存在时返回 ErrPasswordResetNotFound
+func (r *PasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	reset := &models.PasswordReset{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_resets
+		WHERE token_hash = $1
+	`, tokenHash).Scan(reset)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPasswordResetNotFound
+	}
+	return reset, nil
+}
+
+// MarkUsed 将令牌标记为已使用
+// 返回: 令牌是否由本次调用标记；已被使用（如并发重置）时返回 false
+func (r *PasswordResetRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := database.DB.WithContext(ctx).Exec(`
+		UPDATE password_resets SET used_at = $1 WHERE id = $2 AND used_at IS NULL
+	`, time.Now(), id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateUnused 作废用户所有未使用的令牌（密码重置成功后，其他找回密码邮件中的链接随即失效）
+func (r *PasswordResetRepository) InvalidateUnused(ctx context.Context, userID uuid.UUID) error {
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL
+	`, time.Now(), userID).Error
+}
+
+// CountSince 统计用户在 since 之后生成的令牌数（用于限制发送频率）
+func (r *PasswordResetRepository) CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM password_resets WHERE user_id = $1 AND created_at >= $2
+	`, userID, since).Scan(&count).Error
+	return count, err
+}
@@ -0,0 +1,65 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenNotFound 刷新 token 未登记、已吊销或已过期
+var ErrRefreshTokenNotFound = fmt.Errorf("refresh token %w", ErrNotFound)
+
+// RefreshTokenRepository 刷新 token 登记表的数据访问
+type RefreshTokenRepository struct{}
+
+// NewRefreshTokenRepository 创建刷新 token 仓库
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{}
+}
+
+// Create 登记新签发的刷新 token（会设置创建时间）
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	token.CreatedAt = time.Now()
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO refresh_tokens (id, user_id, expires_at, revoked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, token.ID, token.UserID, token.ExpiresAt, token.RevokedAt, token.CreatedAt).Error
+}
+
+// GetActive 获取未吊销且未过期的刷新 token
+// 返回: 不存在、已吊销或已过期时返回 ErrRefreshTokenNotFound
+func (r *RefreshTokenRepository) GetActive(ctx context.Context, id uuid.UUID) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT id, user_id, expires_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > $2
+	`, id, time.Now()).Scan(token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrRefreshTokenNotFound
+	}
+	return token, nil
+}
+
+// RevokeAllForUser 吊销用户所有未吊销的刷新 token（重置密码后使已登录的会话失效）
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL
+	`, time.Now(), userID).Error
+}
+
+// Revoke 吊销刷新 token，已吊销时不做修改
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL
+	`, time.Now(), id).Error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SettingRepository struct{}
+
+func NewSettingRepository() *SettingRepository {
+	return &SettingRepository{}
+}
+
+// List 获取全部设置项
+func (r *SettingRepository) List(ctx context.Context) ([]*models.Setting, error) {
+	var settings []*models.Setting
+	query := `SELECT key, value, type, updated_by, created_at, updated_at FROM settings ORDER BY key`
+	err := database.DB.WithContext(ctx).Raw(query).Scan(&settings).Error
+	return settings, err
+}
+
+// InsertDefaults 写入缺失的默认设置（已存在的键保持不变）
+func (r *SettingRepository) InsertDefaults(ctx context.Context, settings []*models.Setting) error {
+	now := time.Now()
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, s := range settings {
+			err := tx.Exec(`
+				INSERT INTO settings (key, value, type, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (key) DO NOTHING
+			`, s.Key, s.Value, s.Type, now, now).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Upsert 批量写入设置项（同一事务内）
+// updatedBy: 操作人用户ID
+func (r *SettingRepository) Upsert(ctx context.Context, settings []*models.Setting, updatedBy *uuid.UUID) error {
+	now := time.Now()
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, s := range settings {
+			err := tx.Exec(`
+				INSERT INTO settings (key, value, type, updated_by, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+				ON CONFLICT (key) DO UPDATE
+				SET value = EXCLUDED.value, type = EXCLUDED.type,
+				    updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+			`, s.Key, s.Value, s.Type, updatedBy, now, now).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,51 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ListSitemapEntries 分批列出已发布文章的 ID 和更新时间，用于生成站点地图
+// after: 上一批最后一条的 ID，第一批传 uuid.Nil；limit: 每批条数
+// 注意: 按 ID 排序做键集分页，只查询这两列，文章很多时也不会整表加载
+func (r *ArticleRepository) ListSitemapEntries(ctx context.Context, after uuid.UUID, limit int) ([]models.SitemapEntry, error) {
+	var entries []models.SitemapEntry
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT id, updated_at FROM articles
+		WHERE status = $1 AND deleted_at IS NULL AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`, models.StatusPublished, after, limit).Scan(&entries).Error
+	return entries, err
+}
+
+// ListSitemapEntries 分批列出未删除分类的 slug 和更新时间，用于生成站点地图
+// after: 上一批最后一条的 slug，第一批传空字符串；limit: 每批条数
+func (r *CategoryRepository) ListSitemapEntries(ctx context.Context, after string, limit int) ([]models.SitemapEntry, error) {
+	var entries []models.SitemapEntry
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT slug, updated_at FROM categories
+		WHERE deleted_at IS NULL AND slug > $1
+		ORDER BY slug
+		LIMIT $2
+	`, after, limit).Scan(&entries).Error
+	return entries, err
+}
+
+// ListSitemapEntries 分批列出未删除标签的 slug 和更新时间，用于生成站点地图
+// after: 上一批最后一条的 slug，第一批传空字符串；limit: 每批条数
+func (r *TagRepository) ListSitemapEntries(ctx context.Context, after string, limit int) ([]models.SitemapEntry, error) {
+	var entries []models.SitemapEntry
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT slug, updated_at FROM tags
+		WHERE deleted_at IS NULL AND slug > $1
+		ORDER BY slug
+		LIMIT $2
+	`, after, limit).Scan(&entries).Error
+	return entries, err
+}
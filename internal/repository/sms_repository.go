@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -16,7 +17,7 @@ func NewSMSRepository() *SMSRepository {
 	return &SMSRepository{}
 }
 
-func (r *SMSRepository) Create(code *models.SMSCode) error {
+func (r *SMSRepository) Create(ctx context.Context, code *models.SMSCode) error {
 	query := `
 		INSERT INTO sms_codes (id, phone, code, used, expires_at, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -25,13 +26,13 @@ func (r *SMSRepository) Create(code *models.SMSCode) error {
 	code.ID = uuid.New()
 	code.CreatedAt = time.Now()
 
-	row := database.DB.Raw(
+	row := database.DB.WithContext(ctx).Raw(
 		query, code.ID, code.Phone, code.Code, code.Used, code.ExpiresAt, code.CreatedAt,
 	).Row()
 	return row.Scan(&code.ID)
 }
 
-func (r *SMSRepository) GetValidCode(phone, code string) (*models.SMSCode, error) {
+func (r *SMSRepository) GetValidCode(ctx context.Context, phone, code string) (*models.SMSCode, error) {
 	smsCode := &models.SMSCode{}
 	query := `
 		SELECT id, phone, code, used, expires_at, created_at
@@ -41,7 +42,7 @@ func (r *SMSRepository) GetValidCode(phone, code string) (*models.SMSCode, error
 		LIMIT 1
 	`
 	now := time.Now()
-	result := database.DB.Raw(query, phone, code, now).Scan(smsCode)
+	result := database.DB.WithContext(ctx).Raw(query, phone, code, now).Scan(smsCode)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -51,9 +52,9 @@ func (r *SMSRepository) GetValidCode(phone, code string) (*models.SMSCode, error
 	return smsCode, nil
 }
 
-func (r *SMSRepository) MarkAsUsed(id uuid.UUID) error {
+func (r *SMSRepository) MarkAsUsed(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE sms_codes SET used = TRUE WHERE id = $1`
-	result := database.DB.Exec(query, id)
+	result := database.DB.WithContext(ctx).Exec(query, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -63,14 +64,14 @@ func (r *SMSRepository) MarkAsUsed(id uuid.UUID) error {
 	return nil
 }
 
-func (r *SMSRepository) GetRecentCodeCount(phone string, since time.Time) (int64, error) {
+func (r *SMSRepository) GetRecentCodeCount(ctx context.Context, phone string, since time.Time) (int64, error) {
 	var count int64
 	query := `
 		SELECT COUNT(*)
 		FROM sms_codes
 		WHERE phone = $1 AND created_at > $2
 	`
-	err := database.DB.Raw(query, phone, since).Scan(&count).Error
+	err := database.DB.WithContext(ctx).Raw(query, phone, since).Scan(&count).Error
 	return count, err
 }
 
@@ -0,0 +1,213 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+)
+
+// StatsRepository 统计数据访问层，提供仪表盘等场景使用的聚合查询
+type StatsRepository struct{}
+
+// NewStatsRepository 创建新的统计仓库实例
+func NewStatsRepository() *StatsRepository {
+	return &StatsRepository{}
+}
+
+// DashboardCounts 一次性统计仪表盘所需的用户、文章、评论计数（含待处理队列）
+// today: 业务时区中今天的零点，用于统计今日发布数
+func (r *StatsRepository) DashboardCounts(ctx context.Context, today time.Time) (*models.AdminDashboardData, error) {
+	var data models.AdminDashboardData
+	db := database.DB.WithContext(ctx)
+
+	articleQuery := `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE deleted_at IS NULL) AS total_users,
+			COUNT(*) AS total_articles,
+			COUNT(*) FILTER (WHERE status = ?) AS published_articles,
+			COUNT(*) FILTER (WHERE status = ?) AS draft_articles,
+			COUNT(*) FILTER (WHERE status = ?) AS archived_articles,
+			COUNT(*) FILTER (WHERE status = ?) AS review_articles,
+			COUNT(*) FILTER (WHERE status = ?) AS scheduled_articles,
+			COALESCE(SUM(view_count), 0) AS total_article_views,
+			COALESCE(SUM(like_count), 0) AS total_article_likes,
+			COUNT(*) FILTER (WHERE status = ? AND published_at >= ? AND published_at < ?) AS today_published_count
+		FROM articles
+		WHERE deleted_at IS NULL
+	`
+	if err := db.Raw(articleQuery,
+		models.StatusPublished, models.StatusDraft, models.StatusArchived,
+		models.StatusReview, models.StatusScheduled,
+		models.StatusPublished, localTime(today), localTime(today.AddDate(0, 0, 1)),
+	).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	var comments struct {
+		TotalComments    int64 `db:"total_comments"`
+		PendingComments  int64 `db:"pending_comments"`
+		ReportedComments int64 `db:"reported_comments"`
+	}
+	commentQuery := `
+		SELECT
+			COUNT(*) AS total_comments,
+			COUNT(*) FILTER (WHERE status = ?) AS pending_comments,
+			COUNT(*) FILTER (WHERE status = ?) AS reported_comments
+		FROM comments
+		WHERE deleted_at IS NULL
+	`
+	if err := db.Raw(commentQuery, models.CommentStatusPending, models.CommentStatusReported).Scan(&comments).Error; err != nil {
+		return nil, err
+	}
+	data.TotalComments = comments.TotalComments
+	data.PendingComments = comments.PendingComments
+	data.ReportedComments = comments.ReportedComments
+
+	return &data, nil
+}
+
+// TopArticlesByViews 按浏览量排行（基于按天汇总表 article_view_daily）
+// since: 统计起始日期（含）
+// until: 统计截止日期（不含）
+// limit: 返回条数
+// 注意: 仅统计已发布且未删除的文章
+func (r *StatsRepository) TopArticlesByViews(ctx context.Context, since, until time.Time, limit int) ([]*models.DashboardTopItem, error) {
+	var items []*models.DashboardTopItem
+	query := `
+		SELECT a.id, a.title, SUM(v.views) AS value
+		FROM article_view_daily v
+		INNER JOIN articles a ON a.id = v.article_id
+		WHERE v.day >= ? AND v.day < ? AND a.deleted_at IS NULL AND a.status = ?
+		GROUP BY a.id, a.title
+		ORDER BY value DESC
+		LIMIT ?
+	`
+	err := database.DB.WithContext(ctx).Raw(query, dayParam(since), dayParam(until), models.StatusPublished, limit).Scan(&items).Error
+	return items, err
+}
+
+// TopArticlesByComments 按评论数排行（统计时间窗口内新增的已通过评论）
+// since: 统计起始时间（含）
+// until: 统计截止时间（不含）
+// limit: 返回条数
+// 注意: 仅统计已发布且未删除的文章
+func (r *StatsRepository) TopArticlesByComments(ctx context.Context, since, until time.Time, limit int) ([]*models.DashboardTopItem, error) {
+	var items []*models.DashboardTopItem
+	query := `
+		SELECT a.id, a.title, COUNT(c.id) AS value
+		FROM comments c
+		INNER JOIN articles a ON a.id = c.article_id
+		WHERE c.created_at >= ? AND c.created_at < ? AND c.status = ? AND c.deleted_at IS NULL
+		  AND a.deleted_at IS NULL AND a.status = ?
+		GROUP BY a.id, a.title
+		ORDER BY value DESC
+		LIMIT ?
+	`
+	err := database.DB.WithContext(ctx).Raw(query, localTime(since), localTime(until), models.CommentStatusApproved, models.StatusPublished, limit).Scan(&items).Error
+	return items, err
+}
+
+// TopAuthors 按时间窗口内发布的文章数排行作者
+// since: 统计起始时间（按 published_at 判断，含）
+// until: 统计截止时间（不含）
+// limit: 返回条数
+// 注意: 已删除的用户和文章不参与统计
+func (r *StatsRepository) TopAuthors(ctx context.Context, since, until time.Time, limit int) ([]*models.DashboardTopItem, error) {
+	var items []*models.DashboardTopItem
+	query := `
+		SELECT u.id, u.username, COUNT(a.id) AS value
+		FROM articles a
+		INNER JOIN users u ON u.id = a.author_id
+		WHERE a.status = ? AND a.deleted_at IS NULL AND u.deleted_at IS NULL
+		  AND a.published_at >= ? AND a.published_at < ?
+		GROUP BY u.id, u.username
+		ORDER BY value DESC
+		LIMIT ?
+	`
+	err := database.DB.WithContext(ctx).Raw(query, models.StatusPublished, localTime(since), localTime(until), limit).Scan(&items).Error
+	return items, err
+}
+
+// PeriodCounts 统计时间窗口内新增用户、发布文章和文章浏览量
+// from: 起始时间（含）
+// to: 截止时间（不含）
+func (r *StatsRepository) PeriodCounts(ctx context.Context, from, to time.Time) (*models.PeriodCounts, error) {
+	var counts models.PeriodCounts
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users
+			  WHERE deleted_at IS NULL AND created_at >= ? AND created_at < ?) AS new_users,
+			(SELECT COUNT(*) FROM articles
+			  WHERE deleted_at IS NULL AND status = ? AND published_at >= ? AND published_at < ?) AS published_articles,
+			(SELECT COALESCE(SUM(views), 0) FROM article_view_daily
+			  WHERE day >= ? AND day < ?) AS views
+	`
+	err := database.DB.WithContext(ctx).Raw(query,
+		localTime(from), localTime(to),
+		models.StatusPublished, localTime(from), localTime(to),
+		dayParam(from), dayParam(to),
+	).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// ReviewTransitions 获取 since 之后提交审核（改为 review）和审核结束（离开 review）的状态变更，按文章和时间排序
+// 注意: 已删除的文章不参与统计
+func (r *StatsRepository) ReviewTransitions(ctx context.Context, since time.Time) ([]*models.ArticleStatusChange, error) {
+	var changes []*models.ArticleStatusChange
+	query := `
+		SELECT h.id, h.article_id, h.from_status, h.to_status, h.created_at
+		FROM article_status_history h
+		INNER JOIN articles a ON a.id = h.article_id
+		WHERE a.deleted_at IS NULL AND h.created_at >= ? AND (h.to_status = ? OR h.from_status = ?)
+		ORDER BY h.article_id, h.created_at, h.id
+	`
+	err := database.DB.WithContext(ctx).Raw(query, localTime(since), models.StatusReview, models.StatusReview).Scan(&changes).Error
+	return changes, err
+}
+
+// localTime 将统计边界换算到进程本地时区
+// 注意: 时间列为不带时区的 TIMESTAMP，写入时保存的是进程本地时区（time.Now()）的钟面时间，
+// 按业务时区计算的边界需换算到同一时区后再比较
+func localTime(t time.Time) time.Time {
+	return t.In(time.Local)
+}
+
+// dayParam 按天汇总表日期列（article_view_daily.day）的比较参数
+// t: 业务时区中某天的零点，取其在业务时区中的日期
+func dayParam(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// contentStatsSortFields 内容统计允许的排序字段（白名单，防止 SQL 注入）
+var contentStatsSortFields = map[string]string{
+	"name":               "name",
+	"published_articles": "published_articles",
+	"total_views":        "total_views",
+	"total_comments":     "total_comments",
+	"last_published_at":  "last_published_at",
+}
+
+// NormalizeContentStatsQuery 校验排序参数，非法值回退为按已发布文章数倒序
+func NormalizeContentStatsQuery(query models.ContentStatsQuery) models.ContentStatsQuery {
+	if _, ok := contentStatsSortFields[query.SortBy]; !ok {
+		query.SortBy = "published_articles"
+	}
+	query.Order = strings.ToLower(query.Order)
+	if query.Order != "asc" && query.Order != "desc" {
+		query.Order = "desc"
+	}
+	return query
+}
+
+func contentStatsOrderBy(query models.ContentStatsQuery) string {
+	query = NormalizeContentStatsQuery(query)
+	return fmt.Sprintf("%s %s NULLS LAST, name ASC", contentStatsSortFields[query.SortBy], strings.ToUpper(query.Order))
+}
@@ -0,0 +1,70 @@
+// Package repository 提供数据访问层的实现
+package repository
+
+import (
+	"context"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ArticleIDsTx 在调用方的事务中获取使用该标签的全部文章 ID（含已删除的文章）
+func (r *TagRepository) ArticleIDsTx(tx *gorm.DB, tagID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := tx.Raw(`SELECT article_id FROM article_tags WHERE tag_id = $1`, tagID).Scan(&ids).Error
+	return ids, err
+}
+
+// AddAliasTx 在调用方的事务中记录标签别名：source 的 ID 和 slug 解析到 targetID
+// 注意: 原先指向 source 的别名一并改为指向 targetID，避免多次合并后形成别名链
+func (r *TagRepository) AddAliasTx(tx *gorm.DB, source *models.Tag, targetID uuid.UUID) error {
+	if err := tx.Exec(`
+		UPDATE tag_aliases SET target_tag_id = $1 WHERE target_tag_id = $2
+	`, targetID, source.ID).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO tag_aliases (source_tag_id, source_slug, target_tag_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source_tag_id) DO UPDATE SET source_slug = EXCLUDED.source_slug, target_tag_id = EXCLUDED.target_tag_id
+	`, source.ID, source.Slug, targetID, time.Now()).Error
+}
+
+// ResolveAlias 将已合并（已删除）标签的 ID 解析为目标标签 ID
+// 返回: 目标标签 ID；id 不是别名或对应的标签已恢复时返回 false
+func (r *TagRepository) ResolveAlias(ctx context.Context, id uuid.UUID) (uuid.UUID, bool, error) {
+	var targets []uuid.UUID
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT target_tag_id FROM tag_aliases
+		WHERE source_tag_id = $1 AND NOT EXISTS (SELECT 1 FROM tags WHERE id = $1 AND deleted_at IS NULL)
+	`, id).Scan(&targets).Error
+	if err != nil || len(targets) == 0 {
+		return uuid.Nil, false, err
+	}
+	return targets[0], true, nil
+}
+
+// GetByAliasSlug 根据已合并标签的 slug（大小写不敏感）获取目标标签
+// 返回: 目标标签，别名不存在或目标标签已删除时返回错误
+func (r *TagRepository) GetByAliasSlug(ctx context.Context, slug string) (*models.Tag, error) {
+	tag := &models.Tag{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT t.id, t.name, t.slug, t.color, t.created_at, t.updated_at
+		FROM tag_aliases al
+		INNER JOIN tags t ON t.id = al.target_tag_id AND t.deleted_at IS NULL
+		WHERE LOWER(al.source_slug) = LOWER($1)
+		ORDER BY al.created_at DESC
+		LIMIT 1
+	`, slug).Scan(tag)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
@@ -2,17 +2,22 @@
 package repository
 
 import (
-	"database/sql"
-	"errors"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // TagRepository 标签数据访问层，提供标签相关的数据库操作
+// ErrTagNotFound 标签不存在或已删除
+var ErrTagNotFound = fmt.Errorf("tag %w", ErrNotFound)
+
 type TagRepository struct{}
 
 // NewTagRepository 创建新的标签仓库实例
@@ -23,7 +28,7 @@ func NewTagRepository() *TagRepository {
 // Create 创建新标签
 // tag: 标签对象，会设置ID、创建时间、更新时间
 // 返回: 如果创建失败则返回错误
-func (r *TagRepository) Create(tag *models.Tag) error {
+func (r *TagRepository) Create(ctx context.Context, tag *models.Tag) error {
 	query := `
 		INSERT INTO tags (id, name, slug, color, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -35,83 +40,213 @@ func (r *TagRepository) Create(tag *models.Tag) error {
 	tag.CreatedAt = now
 	tag.UpdatedAt = now
 
-	row := database.DB.Raw(
+	row := database.DB.WithContext(ctx).Raw(
 		query, tag.ID, tag.Name, tag.Slug, tag.Color, tag.CreatedAt, tag.UpdatedAt,
 	).Row()
-	return row.Scan(&tag.ID)
+	return wrapConflict(row.Scan(&tag.ID))
 }
 
 // GetByID 根据ID获取标签
 // id: 标签UUID
-// 返回: 标签对象，如果不存在则返回错误
-func (r *TagRepository) GetByID(id uuid.UUID) (*models.Tag, error) {
+// 返回: 标签对象，如果不存在或已删除则返回错误
+func (r *TagRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
 	tag := &models.Tag{}
-	query := `SELECT id, name, slug, color, created_at, updated_at FROM tags WHERE id = $1`
+	query := `SELECT id, name, slug, color, created_at, updated_at FROM tags WHERE id = $1 AND deleted_at IS NULL`
 	
-	err := database.DB.Raw(query, id).Scan(tag).Error
-	if err == sql.ErrNoRows {
-		return nil, errors.New("tag not found")
+	result := database.DB.WithContext(ctx).Raw(query, id).Scan(tag)
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	return tag, err
+	if result.RowsAffected == 0 {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
 }
 
 // GetBySlug 根据slug获取标签
-// slug: 标签URL友好的标识符
-// 返回: 标签对象，如果不存在则返回错误
-func (r *TagRepository) GetBySlug(slug string) (*models.Tag, error) {
+// slug: 标签URL友好的标识符（大小写不敏感）
+// 返回: 标签对象，如果不存在或已删除则返回错误
+func (r *TagRepository) GetBySlug(ctx context.Context, slug string) (*models.Tag, error) {
 	tag := &models.Tag{}
-	query := `SELECT id, name, slug, color, created_at, updated_at FROM tags WHERE slug = $1`
+	query := `SELECT id, name, slug, color, created_at, updated_at FROM tags WHERE LOWER(slug) = LOWER($1) AND deleted_at IS NULL`
 	
-	err := database.DB.Raw(query, slug).Scan(tag).Error
-	if err == sql.ErrNoRows {
-		return nil, errors.New("tag not found")
+	result := database.DB.WithContext(ctx).Raw(query, slug).Scan(tag)
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	return tag, err
+	if result.RowsAffected == 0 {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// GetByName 根据名称获取标签
+// name: 标签名称
+// 返回: 标签对象，如果不存在或已删除则返回错误
+func (r *TagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	tag := &models.Tag{}
+	query := `SELECT id, name, slug, color, created_at, updated_at FROM tags WHERE name = $1 AND deleted_at IS NULL`
+
+	result := database.DB.WithContext(ctx).Raw(query, name).Scan(tag)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
 }
 
 // Update 更新标签信息
 // tag: 标签对象，会更新更新时间
 // 返回: 如果更新失败或标签不存在则返回错误
-func (r *TagRepository) Update(tag *models.Tag) error {
+func (r *TagRepository) Update(ctx context.Context, tag *models.Tag) error {
 	query := `
 		UPDATE tags 
 		SET name = $2, slug = $3, color = $4, updated_at = $5
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	
 	tag.UpdatedAt = time.Now()
-	result := database.DB.Exec(query, tag.ID, tag.Name, tag.Slug, tag.Color, tag.UpdatedAt)
+	result := database.DB.WithContext(ctx).Exec(query, tag.ID, tag.Name, tag.Slug, tag.Color, tag.UpdatedAt)
 	if result.Error != nil {
-		return result.Error
+		return wrapConflict(result.Error)
 	}
 	
 	if result.RowsAffected == 0 {
-		return errors.New("tag not found")
+		return ErrTagNotFound
 	}
 	return nil
 }
 
-// Delete 删除标签（硬删除）
+// Delete 删除标签（软删除，可在回收站中恢复）
 // id: 标签UUID
 // 返回: 如果删除失败或标签不存在则返回错误
-func (r *TagRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM tags WHERE id = $1`
-	result := database.DB.Exec(query, id)
+func (r *TagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.DeleteTx(database.DB.WithContext(ctx), id)
+}
+
+// DeleteTx 在调用方的事务中软删除标签
+// 注意: 文章与标签的关联保留，恢复标签后关联随之恢复
+func (r *TagRepository) DeleteTx(tx *gorm.DB, id uuid.UUID) error {
+	query := `UPDATE tags SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result := tx.Exec(query, time.Now(), id)
 	if result.Error != nil {
 		return result.Error
 	}
 	
 	if result.RowsAffected == 0 {
-		return errors.New("tag not found")
+		return ErrTagNotFound
 	}
 	return nil
 }
 
-func (r *TagRepository) List() ([]*models.Tag, error) {
+// CountPublishedArticles 统计使用该标签的未删除已发布文章数
+func (r *TagRepository) CountPublishedArticles(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	query := `
+		SELECT COUNT(*) FROM article_tags at
+		INNER JOIN articles a ON a.id = at.article_id
+		WHERE at.tag_id = $1 AND a.status = $2 AND a.deleted_at IS NULL
+	`
+	err := database.DB.WithContext(ctx).Raw(query, id, models.StatusPublished).Scan(&count).Error
+	return count, err
+}
+
+// ReassignArticlesTx 在调用方的事务中将标签的全部文章关联转移到另一个标签（已有目标标签的文章不重复添加）
+// 返回: 转移的关联数
+func (r *TagRepository) ReassignArticlesTx(tx *gorm.DB, from, to uuid.UUID) (int64, error) {
+	if err := tx.Exec(`
+		INSERT INTO article_tags (article_id, tag_id)
+		SELECT article_id, $1 FROM article_tags WHERE tag_id = $2
+		ON CONFLICT DO NOTHING
+	`, to, from).Error; err != nil {
+		return 0, err
+	}
+	return r.DetachArticlesTx(tx, from)
+}
+
+// DetachArticlesTx 在调用方的事务中移除标签与全部文章的关联
+// 返回: 移除的关联数
+func (r *TagRepository) DetachArticlesTx(tx *gorm.DB, id uuid.UUID) (int64, error) {
+	result := tx.Exec(`DELETE FROM article_tags WHERE tag_id = $1`, id)
+	return result.RowsAffected, result.Error
+}
+
+// List 获取全部未删除的标签及其文章数
+// query: PublishedOnly 为 true 时只统计已发布文章；Sort 为 article_count 时按文章数倒序，否则按名称排序
+// 注意: 文章数通过一次分组联表查询得到，已删除的文章不计入
+func (r *TagRepository) List(ctx context.Context, query models.TaxonomyListQuery) ([]*models.Tag, error) {
 	var tags []*models.Tag
-	query := `SELECT id, name, slug, color, created_at, updated_at FROM tags ORDER BY name ASC`
-	
-	err := database.DB.Raw(query).Scan(&tags).Error
+	articleFilter := ""
+	var args []interface{}
+	if query.PublishedOnly {
+		articleFilter = " AND a.status = ?"
+		args = append(args, models.StatusPublished)
+	}
+	orderBy := "t.name ASC"
+	if query.Sort == "article_count" {
+		orderBy = "article_count DESC, t.name ASC"
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT t.id, t.name, t.slug, t.color, t.created_at, t.updated_at,
+			   COUNT(a.id) AS article_count
+		FROM tags t
+		LEFT JOIN article_tags at ON at.tag_id = t.id
+		LEFT JOIN articles a ON a.id = at.article_id AND a.deleted_at IS NULL%s
+		WHERE t.deleted_at IS NULL
+		GROUP BY t.id
+		ORDER BY %s
+	`, articleFilter, orderBy)
+	err := database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&tags).Error
 	return tags, err
 }
 
+// Autocomplete 按名称查找标签（大小写不敏感的子串匹配），用于编辑器自动补全
+// q: 关键词；limit: 返回条数
+// 返回: 名称与关键词相同的标签排在最前，其余按已发布文章数倒序、名称排序
+func (r *TagRepository) Autocomplete(ctx context.Context, q string, limit int) ([]*models.TaxonomySuggestion, error) {
+	var suggestions []*models.TaxonomySuggestion
+	query := `
+		SELECT t.id, t.name, t.slug, COUNT(a.id) AS usage_count
+		FROM tags t
+		LEFT JOIN article_tags at ON at.tag_id = t.id
+		LEFT JOIN articles a ON a.id = at.article_id AND a.deleted_at IS NULL AND a.status = ?
+		WHERE t.deleted_at IS NULL AND LOWER(t.name) LIKE ? ESCAPE '\'
+		GROUP BY t.id, t.name, t.slug
+		ORDER BY CASE WHEN LOWER(t.name) = ? THEN 0 ELSE 1 END, usage_count DESC, t.name ASC
+		LIMIT ?
+	`
+	lower := strings.ToLower(q)
+	err := database.DB.WithContext(ctx).Raw(query, models.StatusPublished, containsPattern(lower), lower, limit).Scan(&suggestions).Error
+	return suggestions, err
+}
+
+// containsPattern 构造 LIKE 子串匹配模式，转义关键词中的通配符
+func containsPattern(q string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q) + "%"
+}
+
+// Stats 按标签统计已发布文章数、总浏览量、总评论数和最近发布时间
+// query: 排序参数
+// 返回: 标签统计列表，未被使用的标签计数为 0
+// 注意: 使用一次分组联表查询完成，避免逐个标签查询
+func (r *TagRepository) Stats(ctx context.Context, query models.ContentStatsQuery) ([]*models.ContentStats, error) {
+	var stats []*models.ContentStats
+	statsQuery := fmt.Sprintf(`
+		SELECT t.id, t.name, t.slug,
+			   COUNT(a.id) AS published_articles,
+			   COALESCE(SUM(a.view_count), 0) AS total_views,
+			   COALESCE(SUM(a.comment_count), 0) AS total_comments,
+			   MAX(a.published_at) AS last_published_at
+		FROM tags t
+		LEFT JOIN article_tags at ON at.tag_id = t.id
+		LEFT JOIN articles a ON a.id = at.article_id AND a.status = ? AND a.deleted_at IS NULL
+		WHERE t.deleted_at IS NULL
+		GROUP BY t.id, t.name, t.slug
+		ORDER BY %s
+	`, contentStatsOrderBy(query))
+	err := database.DB.WithContext(ctx).Raw(statsQuery, models.StatusPublished).Scan(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrTrashItemNotFound 回收站中不存在该条目（不存在或未被删除）
+var ErrTrashItemNotFound = fmt.Errorf("item %w in trash", ErrNotFound)
+
+// trashTables 回收站类型对应的表和名称列（白名单，表名不来自用户输入）
+var trashTables = map[models.TrashType]struct {
+	table   string
+	nameCol string
+}{
+	models.TrashArticles:   {"articles", "title"},
+	models.TrashUsers:      {"users", "username"},
+	models.TrashImages:     {"images", "original_name"},
+	models.TrashCategories: {"categories", "name"},
+	models.TrashTags:       {"tags", "name"},
+}
+
+// TrashRepository 回收站数据访问层，只操作已软删除（deleted_at IS NOT NULL）的记录
+type TrashRepository struct{}
+
+func NewTrashRepository() *TrashRepository {
+	return &TrashRepository{}
+}
+
+// IsValidTrashType 判断回收站类型是否受支持
+func IsValidTrashType(t models.TrashType) bool {
+	_, ok := trashTables[t]
+	return ok
+}
+
+// List 分页查询已删除的记录，按删除时间倒序
+func (r *TrashRepository) List(ctx context.Context, t models.TrashType, page, pageSize int) ([]*models.TrashItem, int64, error) {
+	def, ok := trashTables[t]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported trash type: %s", t)
+	}
+
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE deleted_at IS NOT NULL", def.table)
+	if err := database.DB.WithContext(ctx).Raw(countQuery).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []*models.TrashItem
+	listQuery := fmt.Sprintf(`
+		SELECT id, %s AS name, deleted_at
+		FROM %s
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT ? OFFSET ?
+	`, def.nameCol, def.table)
+	if err := database.DB.WithContext(ctx).Raw(listQuery, pageSize, (page-1)*pageSize).Scan(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	for _, item := range items {
+		item.Type = t
+	}
+	return items, total, nil
+}
+
+// Restore 恢复已删除的记录（清空 deleted_at）
+// 注意: 恢复文章时一并恢复随文章删除的评论（删除时间与文章相同），文章删除前已单独删除的评论保持删除
+func (r *TrashRepository) Restore(ctx context.Context, t models.TrashType, id uuid.UUID) error {
+	def, ok := trashTables[t]
+	if !ok {
+		return fmt.Errorf("unsupported trash type: %s", t)
+	}
+	return database.WithTx(ctx, func(tx *gorm.DB) error {
+		if t == models.TrashArticles {
+			if err := tx.Exec(`
+				UPDATE comments SET deleted_at = NULL
+				WHERE article_id = ? AND deleted_at = (SELECT deleted_at FROM articles WHERE id = ?)
+			`, id, id).Error; err != nil {
+				return err
+			}
+		}
+		query := fmt.Sprintf("UPDATE %s SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL", def.table)
+		result := tx.Exec(query, time.Now(), id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTrashItemNotFound
+		}
+		return nil
+	})
+}
+
+// Purge 永久删除已软删除的记录（关联数据由外键 ON DELETE CASCADE 清理，文章的评论和标签关联显式删除）
+func (r *TrashRepository) Purge(ctx context.Context, t models.TrashType, id uuid.UUID) error {
+	return r.PurgeTx(database.DB.WithContext(ctx), t, id)
+}
+
+// PurgeTx 在调用方的事务中永久删除已软删除的记录
+func (r *TrashRepository) PurgeTx(tx *gorm.DB, t models.TrashType, id uuid.UUID) error {
+	def, ok := trashTables[t]
+	if !ok {
+		return fmt.Errorf("unsupported trash type: %s", t)
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ? AND deleted_at IS NOT NULL", def.table)
+	result := tx.Exec(query, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTrashItemNotFound
+	}
+	if t == models.TrashArticles {
+		// 外键级联之外显式删除评论（含软删除的）和标签关联，不依赖数据库是否启用外键约束
+		if err := tx.Exec(`DELETE FROM comments WHERE article_id = ?`, id).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`DELETE FROM article_tags WHERE article_id = ?`, id).Error
+	}
+	return nil
+}
+
+// GetDeletedArticle 获取已删除文章的唯一性字段（恢复前校验 slug）
+func (r *TrashRepository) GetDeletedArticle(ctx context.Context, id uuid.UUID) (*models.Article, error) {
+	article := &models.Article{}
+	result := database.DB.WithContext(ctx).Raw(
+		`SELECT id, title, slug, deleted_at FROM articles WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	).Scan(article)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTrashItemNotFound
+	}
+	return article, nil
+}
+
+// GetDeletedUser 获取已删除用户的唯一性字段（恢复前校验用户名和邮箱）
+func (r *TrashRepository) GetDeletedUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user := &models.User{}
+	result := database.DB.WithContext(ctx).Raw(
+		`SELECT id, username, email, deleted_at FROM users WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	).Scan(user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTrashItemNotFound
+	}
+	return user, nil
+}
+
+// GetDeletedImage 获取已删除图片的文件路径
+func (r *TrashRepository) GetDeletedImage(ctx context.Context, id uuid.UUID) (*models.Image, error) {
+	image := &models.Image{}
+	result := database.DB.WithContext(ctx).Raw(
+		`SELECT id, original_name, path, deleted_at FROM images WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	).Scan(image)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTrashItemNotFound
+	}
+	return image, nil
+}
+
+// GetDeletedCategory 获取已删除分类的唯一性字段（恢复前校验 slug）
+func (r *TrashRepository) GetDeletedCategory(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	category := &models.Category{}
+	result := database.DB.WithContext(ctx).Raw(
+		`SELECT id, name, slug FROM categories WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	).Scan(category)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTrashItemNotFound
+	}
+	return category, nil
+}
+
+// GetDeletedTag 获取已删除标签的唯一性字段（恢复前校验名称和 slug）
+func (r *TrashRepository) GetDeletedTag(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	tag := &models.Tag{}
+	result := database.DB.WithContext(ctx).Raw(
+		`SELECT id, name, slug FROM tags WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	).Scan(tag)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTrashItemNotFound
+	}
+	return tag, nil
+}
+
+// UserOwnedContentTx 查询用户名下的全部文章 ID 和图片路径（含已删除），永久删除用户前用于清理索引和文件
+// 注意: 与删除用户放在同一事务中执行，避免查询后新增的内容漏清理
+func (r *TrashRepository) UserOwnedContentTx(tx *gorm.DB, userID uuid.UUID) ([]uuid.UUID, []string, error) {
+	var articleIDs []uuid.UUID
+	if err := tx.Raw(
+		`SELECT id FROM articles WHERE author_id = ?`, userID,
+	).Scan(&articleIDs).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var imagePaths []string
+	if err := tx.Raw(
+		`SELECT path FROM images WHERE uploader_id = ?`, userID,
+	).Scan(&imagePaths).Error; err != nil {
+		return nil, nil, err
+	}
+	return articleIDs, imagePaths, nil
+}
@@ -1,22 +1,28 @@
 package repository
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 
 	"github.com/google/uuid"
 )
 
+// ErrUserNotFound 用户不存在或已删除
+var ErrUserNotFound = fmt.Errorf("user %w", ErrNotFound)
+
 type UserRepository struct{}
 
 func NewUserRepository() *UserRepository {
 	return &UserRepository{}
 }
 
-func (r *UserRepository) Create(user *models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (id, username, email, phone, password, role, avatar, bio, status, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
@@ -35,75 +41,108 @@ func (r *UserRepository) Create(user *models.User) error {
 		user.Role = models.RoleReader
 	}
 
-	row := database.DB.Raw(
+	row := database.DB.WithContext(ctx).Raw(
 		query,
 		user.ID, user.Username, user.Email, user.Phone, user.Password, user.Role,
 		user.Avatar, user.Bio, user.Status, user.CreatedAt, user.UpdatedAt,
 	).Row()
-	return row.Scan(&user.ID)
+	return wrapConflict(row.Scan(&user.ID))
 }
 
-func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	user := &models.User{}
 	query := `SELECT id, username, email, phone, password, role, avatar, bio, status, created_at, updated_at, deleted_at
 			  FROM users WHERE id = $1 AND deleted_at IS NULL`
 	
-	result := database.DB.Raw(query, id).Scan(user)
+	result := database.DB.WithContext(ctx).Raw(query, id).Scan(user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
 
-func (r *UserRepository) GetByPhone(phone string) (*models.User, error) {
+// GetByIDs 批量获取用户的公开资料（GraphQL 数据加载器使用）
+// 返回: 未删除的用户，顺序不保证，不存在的 ID 被忽略
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error) {
+	var users []*models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT id, username, avatar FROM users WHERE id IN ? AND deleted_at IS NULL
+	`, ids).Scan(&users).Error
+	return users, err
+}
+
+// GetPublicProfile 获取作者公开资料，同一查询中统计已发布文章数（不含已删除的文章）
+// 返回: 用户不存在、已删除或未激活时返回 ErrUserNotFound
+func (r *UserRepository) GetPublicProfile(ctx context.Context, id uuid.UUID) (*models.PublicProfile, error) {
+	profile := &models.PublicProfile{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT u.id, u.username, u.avatar, u.bio, u.created_at AS joined_at,
+			   (SELECT COUNT(*) FROM articles a
+				WHERE a.author_id = u.id AND a.status = ? AND a.deleted_at IS NULL) AS article_count
+		FROM users u
+		WHERE u.id = ? AND u.deleted_at IS NULL AND u.status = 'active'
+	`, models.StatusPublished, id).Scan(profile)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrUserNotFound
+	}
+	return profile, nil
+}
+
+func (r *UserRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
 	user := &models.User{}
 	query := `SELECT id, username, email, phone, password, role, avatar, bio, status, created_at, updated_at, deleted_at
 			  FROM users WHERE phone = $1 AND deleted_at IS NULL`
 	
-	result := database.DB.Raw(query, phone).Scan(user)
+	result := database.DB.WithContext(ctx).Raw(query, phone).Scan(user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
 	query := `SELECT id, username, email, phone, password, role, avatar, bio, status, created_at, updated_at, deleted_at
 			  FROM users WHERE email = $1 AND deleted_at IS NULL`
 	
-	result := database.DB.Raw(query, email).Scan(user)
+	result := database.DB.WithContext(ctx).Raw(query, email).Scan(user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
 
-func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	user := &models.User{}
 	query := `SELECT id, username, email, phone, password, role, avatar, bio, status, created_at, updated_at, deleted_at
 			  FROM users WHERE username = $1 AND deleted_at IS NULL`
 	
-	result := database.DB.Raw(query, username).Scan(user)
+	result := database.DB.WithContext(ctx).Raw(query, username).Scan(user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
 
-func (r *UserRepository) Update(user *models.User) error {
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users 
 		SET username = $2, email = $3, phone = $4, role = $5, avatar = $6, bio = $7, status = $8, updated_at = $9
@@ -111,68 +150,106 @@ func (r *UserRepository) Update(user *models.User) error {
 	`
 	
 	user.UpdatedAt = time.Now()
-	result := database.DB.Exec(query, user.ID, user.Username, user.Email, user.Phone, user.Role,
+	result := database.DB.WithContext(ctx).Exec(query, user.ID, user.Username, user.Email, user.Phone, user.Role,
 		user.Avatar, user.Bio, user.Status, user.UpdatedAt)
 	if result.Error != nil {
-		return result.Error
+		return wrapConflict(result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	return nil
 }
 
+// Activate 将待验证邮箱（pending）的用户改为 active
+// 返回: 用户是否被激活；用户不是 pending 状态（如已激活或已被禁用）时不做修改并返回 false
+func (r *UserRepository) Activate(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := database.DB.WithContext(ctx).Exec(`
+		UPDATE users SET status = 'active', updated_at = $1
+		WHERE id = $2 AND status = 'pending' AND deleted_at IS NULL
+	`, time.Now(), id)
+	return result.RowsAffected > 0, result.Error
+}
+
 // UpdatePassword 仅更新用户密码（已在 service 层完成哈希）
-func (r *UserRepository) UpdatePassword(id uuid.UUID, hashedPassword string) error {
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
 	query := `
 		UPDATE users
 		SET password = $2, updated_at = $3
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 	now := time.Now()
-	result := database.DB.Exec(query, id, hashedPassword, now)
+	result := database.DB.WithContext(ctx).Exec(query, id, hashedPassword, now)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	return nil
 }
 
-func (r *UserRepository) Delete(id uuid.UUID) error {
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE users SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
-	result := database.DB.Exec(query, time.Now(), id)
+	result := database.DB.WithContext(ctx).Exec(query, time.Now(), id)
 	if result.Error != nil {
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	return nil
 }
 
-func (r *UserRepository) List(page, pageSize int) ([]*models.User, int64, error) {
+func (r *UserRepository) List(ctx context.Context, page, pageSize int) ([]*models.User, int64, error) {
+	return r.ListByQuery(ctx, models.UserQuery{Page: page, PageSize: pageSize})
+}
+
+// ListByQuery 按条件分页查询用户（角色、状态、注册时间范围）
+func (r *UserRepository) ListByQuery(ctx context.Context, query models.UserQuery) ([]*models.User, int64, error) {
 	var users []*models.User
 	var total int64
 
-	offset := (page - 1) * pageSize
+	query.Page, query.PageSize = config.NormalizePage(config.PageUsers, query.Page, query.PageSize)
+	offset := (query.Page - 1) * query.PageSize
+
+	where := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+
+	if query.Role != "" {
+		where = append(where, "role = ?")
+		args = append(args, query.Role)
+	}
+	if query.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, query.Status)
+	}
+	if query.CreatedFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, query.CreatedTo.AddDate(0, 0, 1))
+	}
+
+	whereClause := strings.Join(where, " AND ")
 
 	// 获取总数
-	countQuery := `SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`
-	err := database.DB.Raw(countQuery).Scan(&total).Error
+	countQuery := "SELECT COUNT(*) FROM users WHERE " + whereClause
+	err := database.DB.WithContext(ctx).Raw(countQuery, args...).Scan(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// 获取列表
-	query := `SELECT id, username, email, role, avatar, bio, status, created_at, updated_at
-			  FROM users WHERE deleted_at IS NULL
-			  ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	
-	err = database.DB.Raw(query, pageSize, offset).Scan(&users).Error
+	listQuery := `SELECT id, username, email, role, avatar, bio, status, created_at, updated_at
+			  FROM users WHERE ` + whereClause + `
+			  ORDER BY created_at DESC LIMIT ? OFFSET ?`
+
+	args = append(args, query.PageSize, offset)
+	err = database.DB.WithContext(ctx).Raw(listQuery, args...).Scan(&users).Error
 	return users, total, err
 }
-
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrWebhookNotFound Webhook 不存在
+	ErrWebhookNotFound = fmt.Errorf("webhook %w", ErrNotFound)
+	// ErrWebhookDeliveryNotFound 投递记录不存在
+	ErrWebhookDeliveryNotFound = fmt.Errorf("webhook delivery %w", ErrNotFound)
+)
+
+type WebhookRepository struct{}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{}
+}
+
+const webhookColumns = `id, url, secret, events, active, created_by, created_at, updated_at`
+
+// Create 创建 Webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	now := time.Now()
+	webhook.CreatedAt, webhook.UpdatedAt = now, now
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO webhooks (`+webhookColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, webhook.ID, webhook.URL, webhook.Secret, string(events), webhook.Active, webhook.CreatedBy, now, now).Error
+}
+
+// Update 修改 Webhook 的地址、密钥、订阅事件和启用状态
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+	result := database.DB.WithContext(ctx).Exec(`
+		UPDATE webhooks SET url = ?, secret = ?, events = ?, active = ?, updated_at = ?
+		WHERE id = ?
+	`, webhook.URL, webhook.Secret, string(events), webhook.Active, webhook.UpdatedAt, webhook.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete 删除 Webhook（投递记录一并删除）
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db := database.DB.WithContext(ctx)
+	// SQLite 默认不启用外键约束，投递记录显式删除
+	if err := db.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id).Error; err != nil {
+		return err
+	}
+	result := db.Exec(`DELETE FROM webhooks WHERE id = ?`, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetByID 获取 Webhook（含密钥）
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	result := database.DB.WithContext(ctx).Raw(`SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, id).Scan(webhook)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// List 获取全部 Webhook（含密钥），按创建时间排序
+func (r *WebhookRepository) List(ctx context.Context) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := database.DB.WithContext(ctx).Raw(`SELECT ` + webhookColumns + ` FROM webhooks ORDER BY created_at`).Scan(&webhooks).Error
+	return webhooks, err
+}
+
+// ListActive 获取全部启用的 Webhook（含密钥）
+func (r *WebhookRepository) ListActive(ctx context.Context) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := database.DB.WithContext(ctx).Raw(`SELECT `+webhookColumns+` FROM webhooks WHERE active = ? ORDER BY created_at`, true).Scan(&webhooks).Error
+	return webhooks, err
+}
+
+const webhookDeliveryColumns = `id, webhook_id, event, payload, status, attempts, response_status, response_body, error,
+	redelivery_of, created_at, updated_at, delivered_at`
+
+// CreateDelivery 写入一条待投递记录
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	now := time.Now()
+	delivery.CreatedAt, delivery.UpdatedAt = now, now
+	if delivery.Status == "" {
+		delivery.Status = models.WebhookDeliveryPending
+	}
+	return database.DB.WithContext(ctx).Exec(`
+		INSERT INTO webhook_deliveries (`+webhookDeliveryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, delivery.ID, delivery.WebhookID, delivery.Event, delivery.Payload, delivery.Status, delivery.Attempts,
+		delivery.ResponseStatus, delivery.ResponseBody, delivery.Error, delivery.RedeliveryOf,
+		now, now, delivery.DeliveredAt,
+	).Error
+}
+
+// UpdateDeliveryResult 记录一次投递尝试的结果
+func (r *WebhookRepository) UpdateDeliveryResult(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	return database.DB.WithContext(ctx).Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_status = ?, response_body = ?, error = ?, updated_at = ?, delivered_at = ?
+		WHERE id = ?
+	`, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.ResponseBody, delivery.Error,
+		delivery.UpdatedAt, delivery.DeliveredAt, delivery.ID,
+	).Error
+}
+
+// GetDelivery 获取 Webhook 的一条投递记录
+func (r *WebhookRepository) GetDelivery(ctx context.Context, webhookID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	result := database.DB.WithContext(ctx).Raw(`
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = ? AND webhook_id = ?
+	`, id, webhookID).Scan(delivery)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// ListDeliveries 分页获取 Webhook 的投递记录（最新的在前）
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, page, pageSize int) ([]*models.WebhookDelivery, int64, error) {
+	var deliveries []*models.WebhookDelivery
+	var total int64
+	db := database.DB.WithContext(ctx)
+
+	if err := db.Raw(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = ?`, webhookID).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Raw(`
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, webhookID, pageSize, (page-1)*pageSize).Scan(&deliveries).Error
+	return deliveries, total, err
+}
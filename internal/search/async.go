@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/errorreport"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// pendingOps 已提交但尚未完成的异步索引操作数（索引队列深度）
+var pendingOps atomic.Int64
+
+// PendingOperations 返回尚未完成的异步索引 / 删除操作数
+// 持续增长说明 Elasticsearch 响应变慢或不可用
+func PendingOperations() int64 {
+	return pendingOps.Load()
+}
+
+// IndexArticleAsync 异步索引文章，不阻塞调用方；失败只记录日志（带 ctx 中的请求字段）
+func IndexArticleAsync(ctx context.Context, article *models.Article) {
+	if esClient == nil || article == nil {
+		return
+	}
+	runAsync(ctx, "index", article.ID, func(ctx context.Context) error {
+		return IndexArticle(ctx, article)
+	})
+}
+
+// IndexArticlesAsync 异步批量索引多篇文章（bulk API），不阻塞调用方；失败只记录日志
+func IndexArticlesAsync(ctx context.Context, articles []*models.Article) {
+	if esClient == nil || len(articles) == 0 {
+		return
+	}
+	runAsync(ctx, "bulk_index", uuid.Nil, func(ctx context.Context) error {
+		_, failed, err := BulkIndexArticles(ctx, articles)
+		if err == nil && failed > 0 {
+			err = fmt.Errorf("bulk index: %d of %d documents failed", failed, len(articles))
+		}
+		return err
+	})
+}
+
+// DeleteArticleAsync 异步删除文章文档，不阻塞调用方；失败只记录日志（带 ctx 中的请求字段）
+func DeleteArticleAsync(ctx context.Context, id uuid.UUID) {
+	if esClient == nil {
+		return
+	}
+	runAsync(ctx, "delete", id, func(ctx context.Context) error {
+		return DeleteArticle(ctx, id)
+	})
+}
+
+// runAsync 在后台执行索引操作；reqCtx 被取消（请求结束）不会中断操作
+func runAsync(reqCtx context.Context, op string, id uuid.UUID, fn func(ctx context.Context) error) {
+	pendingOps.Add(1)
+	go func() {
+		defer pendingOps.Add(-1)
+		ctx, cancel := RequestContext(context.WithoutCancel(reqCtx))
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			errorreport.CaptureError(reqCtx, err, map[string]string{"worker": "search_async", "op": op})
+			l := logger.FromContext(reqCtx, "search")
+			l.Warn().Err(err).Str("op", op).Str("article_id", id.String()).Msg("Async search operation failed")
+		}
+	}()
+}
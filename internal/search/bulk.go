@@ -0,0 +1,113 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"enterprise-blog/internal/models"
+)
+
+// ErrSearchDisabled Elasticsearch 未启用或初始化失败
+var ErrSearchDisabled = errors.New("elasticsearch not initialized")
+
+// Enabled 返回 Elasticsearch 是否可用
+func Enabled() bool {
+	return esClient != nil
+}
+
+// Ping 检查 Elasticsearch 是否可以访问（集群返回错误状态码时同样视为不可用）
+// 返回: 未启用或初始化失败时返回 ErrSearchDisabled
+func Ping(ctx context.Context) error {
+	if esClient == nil {
+		return ErrSearchDisabled
+	}
+	res, err := esClient.Ping(esClient.Ping.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch ping: %s", res.Status())
+	}
+	return nil
+}
+
+// BulkIndexArticles 使用 Elasticsearch bulk API 批量索引文章
+//
+// 参数说明：
+// - ctx: 上下文，用于控制请求超时和取消
+// - articles: 要索引的文章列表（调用方负责分批，建议每批几百条）
+//
+// 返回值：
+// - indexed: 索引成功的文档数
+// - failed: 索引失败的文档数（单条失败不影响同批其他文档）
+// - error: 请求整体失败时返回错误，此时 indexed/failed 为 0
+//
+// 设计考虑：
+// - 一次请求提交整批文档，比逐条 IndexArticle 少很多网络往返
+// - 使用文章 ID 作为文档 ID，重复执行是幂等的
+func BulkIndexArticles(ctx context.Context, articles []*models.Article) (indexed int, failed int, err error) {
+	if esClient == nil {
+		return 0, 0, ErrSearchDisabled
+	}
+	if len(articles) == 0 {
+		return 0, 0, nil
+	}
+
+	// bulk 请求体为 NDJSON：每篇文章一行 action + 一行文档
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, article := range articles {
+		action := map[string]interface{}{
+			"index": map[string]interface{}{
+				"_index": articleIndex,
+				"_id":    article.ID.String(),
+			},
+		}
+		if err := enc.Encode(action); err != nil {
+			return 0, 0, err
+		}
+		if err := enc.Encode(articleDocument(article)); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	res, err := esClient.Bulk(
+		bytes.NewReader(buf.Bytes()),
+		esClient.Bulk.WithContext(ctx),
+		esClient.Bulk.WithRefresh("false"),
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("elasticsearch bulk request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return 0, 0, fmt.Errorf("elasticsearch bulk error: %s", res.String())
+	}
+
+	// 逐条检查结果，统计成功 / 失败数量
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return 0, 0, err
+	}
+
+	for _, item := range result.Items {
+		for _, op := range item {
+			if op.Status >= 200 && op.Status < 300 {
+				indexed++
+			} else {
+				failed++
+			}
+		}
+	}
+	return indexed, failed, nil
+}
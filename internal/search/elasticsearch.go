@@ -13,6 +13,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -31,57 +32,81 @@ var esClient *elasticsearch.Client
 // articleIndex Elasticsearch 索引名称，用于存储文章数据
 const articleIndex = "articles"
 
+// RequestContext 返回单次 Elasticsearch 请求的超时上下文，超时时间为 elasticsearch.request_timeout_ms
+func RequestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	var cfg config.ElasticsearchConfig
+	if current := config.Get(); current != nil {
+		cfg = current.Elasticsearch
+	}
+	return context.WithTimeout(parent, cfg.RequestTimeout())
+}
+
 // InitElasticsearch 初始化 Elasticsearch 客户端
 //
 // 功能说明：
-// - 从配置文件读取 Elasticsearch 配置（URL、是否启用）
+// - 从配置文件读取 Elasticsearch 配置（URL、是否启用、认证、CA 证书、重试次数）
 // - 创建 Elasticsearch 客户端连接
 // - 执行健康检查（ping），确保连接可用
 // - 如果初始化失败，仅记录警告日志，不影响系统启动
 //
 // 设计考虑：
 // - 使用优雅降级策略：Elasticsearch 不可用时，系统仍可正常运行
-// - 使用超时控制（2秒），避免启动时长时间阻塞
+// - 使用超时控制（elasticsearch.request_timeout_ms），避免启动时长时间阻塞
 // - 单例模式：全局只有一个客户端实例，节省资源
 //
 // 面试要点：
 // - 为什么使用单例模式？避免重复创建连接，节省资源
 // - 为什么使用优雅降级？提高系统可用性，即使搜索服务不可用，其他功能仍可用
 func InitElasticsearch() {
-	if !config.AppConfig.Elasticsearch.Enabled {
+	esCfg := config.Get().Elasticsearch
+	if !esCfg.Enabled {
 		// Elasticsearch 未启用
 		return
 	}
 
-	url := config.AppConfig.Elasticsearch.URL
+	url := esCfg.URL
 	if url == "" {
 		// 未配置URL则不启用
-		l := logger.GetLogger()
+		l := logger.GetLogger("search")
 		l.Warn().Msg("Elasticsearch enabled but URL not configured, search disabled")
 		return
 	}
 
 	cfg := elasticsearch.Config{
-		Addresses: []string{url},
+		Addresses:  []string{url},
+		Username:   esCfg.Username,
+		Password:   esCfg.Password,
+		MaxRetries: esCfg.MaxRetries,
+		// 0 次重试时显式关闭，否则客户端使用默认的 3 次
+		DisableRetry: esCfg.MaxRetries == 0,
+	}
+	if esCfg.CACert != "" {
+		caCert, err := os.ReadFile(esCfg.CACert)
+		if err != nil {
+			l := logger.GetLogger("search")
+			l.Warn().Err(err).Str("ca_cert", esCfg.CACert).Msg("failed to read elasticsearch CA certificate, search disabled")
+			return
+		}
+		cfg.CACert = caCert
 	}
 	client, err := elasticsearch.NewClient(cfg)
 	if err != nil {
-		l := logger.GetLogger()
+		l := logger.GetLogger("search")
 		l.Warn().Err(err).Msg("failed to init elasticsearch client, search disabled")
 		return
 	}
 
 	// 简单 ping 校验
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := RequestContext(context.Background())
 	defer cancel()
 	if _, err := client.Info(client.Info.WithContext(ctx)); err != nil {
-		l := logger.GetLogger()
+		l := logger.GetLogger("search")
 		l.Warn().Err(err).Msg("failed to connect to elasticsearch, search disabled")
 		return
 	}
 
 	esClient = client
-	l := logger.GetLogger()
+	l := logger.GetLogger("search")
 	l.Info().Str("url", url).Msg("Elasticsearch client initialized")
 }
 
@@ -106,6 +131,7 @@ func InitElasticsearch() {
 // - status: 文章状态（用于筛选）
 // - author_id: 作者ID（用于筛选）
 // - category_id: 分类ID（用于筛选，可为空）
+// - tag_ids: 标签ID列表（用于筛选）
 // - published_at: 发布时间（用于排序）
 // - created_at: 创建时间（用于排序）
 //
@@ -122,21 +148,7 @@ func IndexArticle(ctx context.Context, article *models.Article) error {
 		return nil
 	}
 
-	doc := map[string]interface{}{
-		"title":        article.Title,
-		"content":      article.Content,
-		"excerpt":      article.Excerpt,
-		"status":       string(article.Status),
-		"author_id":    article.AuthorID.String(),
-		"category_id":  nil,
-		"published_at": article.PublishedAt,
-		"created_at":   article.CreatedAt,
-	}
-	if article.CategoryID != nil {
-		doc["category_id"] = article.CategoryID.String()
-	}
-
-	body, err := json.Marshal(doc)
+	body, err := json.Marshal(articleDocument(article))
 	if err != nil {
 		return err
 	}
@@ -159,6 +171,33 @@ func IndexArticle(ctx context.Context, article *models.Article) error {
 	return nil
 }
 
+// articleDocument 将文章转换为 Elasticsearch 文档（单条索引和批量索引共用）
+func articleDocument(article *models.Article) map[string]interface{} {
+	doc := map[string]interface{}{
+		"title":        article.Title,
+		"content":      article.Content,
+		"excerpt":      article.Excerpt,
+		"status":       string(article.Status),
+		"author_id":    article.AuthorID.String(),
+		"category_id":  nil,
+		"published_at": article.PublishedAt,
+		"created_at":   article.CreatedAt,
+		// 字数和阅读时长用于排序
+		"word_count":           article.WordCount,
+		"reading_time_minutes": article.ReadingTimeMinutes,
+	}
+	if article.CategoryID != nil {
+		doc["category_id"] = article.CategoryID.String()
+	}
+	// 标签 ID 用于按标签筛选，文章需带有已加载的 Tags
+	tagIDs := make([]string, 0, len(article.Tags))
+	for _, tag := range article.Tags {
+		tagIDs = append(tagIDs, tag.ID.String())
+	}
+	doc["tag_ids"] = tagIDs
+	return doc
+}
+
 // DeleteArticle 从 Elasticsearch 中删除文章文档
 //
 // 参数说明：
@@ -205,9 +244,11 @@ func DeleteArticle(ctx context.Context, id uuid.UUID) error {
 //   - Search: 搜索关键词（可选）
 //   - Status: 文章状态筛选（可选）
 //   - CategoryID: 分类ID筛选（可选）
+//   - TagID: 标签ID筛选（可选）
 //   - AuthorID: 作者ID筛选（可选）
+//   - Year / Month: 按业务时区的发布年月筛选（可选）
 //   - Page: 页码（默认1）
-//   - PageSize: 每页数量（默认10，最大50）
+//   - PageSize: 每页数量（默认值和上限见 pagination.articles，默认 10 / 100）
 //   - SortBy: 排序字段（可选）
 //   - Order: 排序方向（asc/desc，可选）
 //
@@ -228,14 +269,14 @@ func DeleteArticle(ctx context.Context, id uuid.UUID) error {
 // - 使用 bool 查询的 should 子句，至少匹配一个条件即可
 // - 不同匹配策略有不同的权重，精确匹配优先级最高
 // - 转义特殊字符，防止查询注入攻击
-// - 支持筛选条件（status、category、author），使用 filter 子句（不计算相关性分数，性能更好）
+// - 支持筛选条件（status、category、tag、author、创建 / 发布时间范围），使用 filter 子句（不计算相关性分数，性能更好）
 // - 默认按创建时间倒序排序，最新的在前
 // - 分页参数验证和限制，防止恶意请求
 //
 // 性能优化：
 // - filter 子句不计算相关性分数，比 must 子句性能更好
 // - 使用白名单验证排序字段，防止注入攻击
-// - 限制每页最大数量（50），防止单次查询返回过多数据
+// - 每页数量按文章列表的分页策略（pagination.articles）限制，防止单次查询返回过多数据
 //
 // 错误处理：
 // - 解析 Elasticsearch 错误响应，提取详细的错误信息
@@ -250,15 +291,8 @@ func SearchArticles(ctx context.Context, query models.ArticleQuery) ([]uuid.UUID
 	if esClient == nil {
 		return nil, 0, fmt.Errorf("elasticsearch not initialized")
 	}
-	if query.Page <= 0 {
-		query.Page = 1
-	}
-	if query.PageSize <= 0 {
-		query.PageSize = 10
-	}
-	if query.PageSize > 50 {
-		query.PageSize = 50
-	}
+	// 与数据库路径使用同一分页策略，同一请求无论是否走 Elasticsearch 每页条数都相同
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
 
 	from := (query.Page - 1) * query.PageSize
 
@@ -374,6 +408,13 @@ func SearchArticles(ctx context.Context, query models.ArticleQuery) ([]uuid.UUID
 			})
 		}
 
+		// 标签筛选
+		if query.TagID != nil {
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"term": map[string]interface{}{"tag_ids": query.TagID.String()},
+			})
+		}
+
 		// 作者筛选
 		if query.AuthorID != nil {
 			filterClauses = append(filterClauses, map[string]interface{}{
@@ -381,6 +422,8 @@ func SearchArticles(ctx context.Context, query models.ArticleQuery) ([]uuid.UUID
 			})
 		}
 
+		filterClauses = append(filterClauses, dateRangeFilters(query)...)
+
 		// 如果有筛选条件，添加到 bool 查询中
 		if len(filterClauses) > 0 {
 			boolQuery["filter"] = filterClauses
@@ -403,11 +446,17 @@ func SearchArticles(ctx context.Context, query models.ArticleQuery) ([]uuid.UUID
 				"term": map[string]interface{}{"category_id": query.CategoryID.String()},
 			})
 		}
+		if query.TagID != nil {
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"term": map[string]interface{}{"tag_ids": query.TagID.String()},
+			})
+		}
 		if query.AuthorID != nil {
 			filterClauses = append(filterClauses, map[string]interface{}{
 				"term": map[string]interface{}{"author_id": query.AuthorID.String()},
 			})
 		}
+		filterClauses = append(filterClauses, dateRangeFilters(query)...)
 
 		if len(filterClauses) > 0 {
 			esQuery = map[string]interface{}{
@@ -443,9 +492,11 @@ func SearchArticles(ctx context.Context, query models.ArticleQuery) ([]uuid.UUID
 		// 验证排序字段（白名单验证，防止注入攻击）
 		// 只允许排序指定的字段，防止用户通过排序字段进行注入攻击
 		allowedSortFields := map[string]string{
-			"created_at":   "created_at",
-			"updated_at":   "updated_at",
-			"published_at": "published_at",
+			"created_at":           "created_at",
+			"updated_at":           "updated_at",
+			"published_at":         "published_at",
+			"word_count":           "word_count",
+			"reading_time_minutes": "reading_time_minutes",
 		}
 		if sortField, ok := allowedSortFields[query.SortBy]; ok {
 			sort = []map[string]interface{}{
@@ -550,3 +601,40 @@ func SearchArticles(ctx context.Context, query models.ArticleQuery) ([]uuid.UUID
 
 	return ids, result.Hits.Total.Value, nil
 }
+
+// dateRangeFilters 创建时间 / 发布时间范围及归档年月的 filter 子句（range 查询，与数据库查询一致）
+// 起点晚于终点时 range 查询不匹配任何文档，返回空结果而不是错误
+func dateRangeFilters(query models.ArticleQuery) []map[string]interface{} {
+	var filters []map[string]interface{}
+	for _, r := range []struct {
+		field    string
+		from, to *time.Time
+	}{
+		{"created_at", query.CreatedFrom, query.CreatedTo},
+		{"published_at", query.PublishedFrom, query.PublishedTo},
+	} {
+		bounds := map[string]interface{}{}
+		if r.from != nil {
+			bounds["gte"] = r.from.Format(time.RFC3339Nano)
+		}
+		if r.to != nil {
+			bounds["lte"] = r.to.Format(time.RFC3339Nano)
+		}
+		if len(bounds) > 0 {
+			filters = append(filters, map[string]interface{}{
+				"range": map[string]interface{}{r.field: bounds},
+			})
+		}
+	}
+	// 归档筛选：业务时区中该年 / 该月的发布时间区间（不含终点）
+	if query.Year > 0 {
+		from, to := config.MonthRange(query.Year, query.Month)
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{"published_at": map[string]interface{}{
+				"gte": from.Format(time.RFC3339Nano),
+				"lt":  to.Format(time.RFC3339Nano),
+			}},
+		})
+	}
+	return filters
+}
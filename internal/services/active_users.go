@@ -0,0 +1,57 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/pkg/logger"
+	"enterprise-blog/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// activeUserWindow 活跃用户的统计窗口：窗口内有过认证请求的用户计为活跃
+const activeUserWindow = 15 * time.Minute
+
+// TrackActiveUser 记录用户最近一次认证请求的时间
+// 注意: 由 AuthMiddleware 在每个认证请求中调用，多个实例共用同一个有序集合；Redis 不可用或写入失败时忽略
+func TrackActiveUser(ctx context.Context, userID uuid.UUID) {
+	if database.RedisClient == nil {
+		return
+	}
+	writeCtx, cancel := redisWriteContext(ctx)
+	defer cancel()
+	member := redis.Z{Score: float64(time.Now().Unix()), Member: userID.String()}
+	if err := database.RedisClient.ZAdd(writeCtx, redisActiveUsersKey, member).Err(); err != nil {
+		l := logger.FromContext(ctx)
+		l.Debug().Err(err).Msg("failed to track active user")
+	}
+}
+
+// RefreshActiveUsers 清理窗口外的用户并刷新 active_users 指标
+// 返回: 当前活跃用户数；Redis 未初始化时返回 0
+// 注意: 由定时任务周期调用
+func RefreshActiveUsers(ctx context.Context) (int64, error) {
+	if database.RedisClient == nil {
+		return 0, nil
+	}
+	ctx, cancel := redisWriteContext(ctx)
+	defer cancel()
+
+	cutoff := strconv.FormatInt(time.Now().Add(-activeUserWindow).Unix(), 10)
+	var count *redis.IntCmd
+	_, err := database.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, redisActiveUsersKey, "-inf", "("+cutoff)
+		count = pipe.ZCard(ctx, redisActiveUsersKey)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	metrics.SetActiveUsers(float64(count.Val()))
+	return count.Val(), nil
+}
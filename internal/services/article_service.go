@@ -2,27 +2,76 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html/template"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
 	"enterprise-blog/internal/search"
+	"enterprise-blog/pkg/errorreport"
 	"enterprise-blog/pkg/logger"
+	"enterprise-blog/pkg/markdown"
+	"enterprise-blog/pkg/metrics"
+	"enterprise-blog/pkg/textdiff"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
+var (
+	// ErrArticleVersionRequired 更新文章时未提供期望的版本号
+	ErrArticleVersionRequired = errors.New("expected_version (or If-Match header) is required")
+	// ErrArticleVersionConflict 文章已被其他请求修改，期望的版本号已过期
+	ErrArticleVersionConflict = repository.ErrArticleVersionConflict
+	// ErrTagsNotFound 文章关联的标签不存在或已删除
+	ErrTagsNotFound = repository.ErrTagsNotFound
+	// ErrNotArticleAuthor 非作者操作只允许作者本人进行的文章功能
+	ErrNotArticleAuthor = errors.New("only the article author can perform this action")
+	// ErrInvalidArticleStatus 文章状态不是已定义的状态
+	ErrInvalidArticleStatus = errors.New("invalid article status")
+	// ErrScheduledAtRequired 定时发布的文章未指定发布时间
+	ErrScheduledAtRequired = errors.New("scheduled_at is required for scheduled articles")
+	// ErrRevisionDiffTooLarge 修订内容过大或差异过多，无法在限定时间内比较
+	ErrRevisionDiffTooLarge = errors.New("revisions are too large to compare")
+)
+
+// TagsNotFoundError 关联标签时列出缺失的标签 ID，errors.Is(err, ErrTagsNotFound) 为 true
+type TagsNotFoundError = repository.TagsNotFoundError
+
+// ArticleVersionConflictError 版本冲突时附带服务端当前的文章，客户端可据此合并修改
+// errors.Is(err, ErrArticleVersionConflict) 为 true
+type ArticleVersionConflictError struct {
+	Expected int
+	Current  *models.Article
+}
+
+func (e *ArticleVersionConflictError) Error() string {
+	return fmt.Sprintf("%s: expected version %d, current version %d", ErrArticleVersionConflict, e.Expected, e.Current.Version)
+}
+
+func (e *ArticleVersionConflictError) Is(target error) bool {
+	return target == ErrArticleVersionConflict
+}
+
 // ArticleService 文章服务，提供文章相关的业务逻辑
 type ArticleService struct {
 	articleRepo  *repository.ArticleRepository
 	categoryRepo *repository.CategoryRepository
 	tagRepo      *repository.TagRepository
+	summarizer   Summarizer
 }
 
 // NewArticleService 创建新的文章服务实例
@@ -41,105 +90,133 @@ func NewArticleService(
 	}
 }
 
+// SetSummarizer 设置摘要生成器，未设置时摘要建议和发布时自动补齐都使用截取正文的摘要
+func (s *ArticleService) SetSummarizer(summarizer Summarizer) {
+	s.summarizer = summarizer
+}
+
 // Create 创建新文章
 // authorID: 作者用户UUID
 // req: 文章创建请求，包含标题、内容、分类、标签等
-// 返回: 创建成功的文章对象（包含关联的作者、分类、标签），如果创建失败则返回错误
-// 注意: 会自动生成slug（如果冲突会自动添加数字后缀），自动生成摘要，支持标签关联
-func (s *ArticleService) Create(authorID uuid.UUID, req *models.ArticleCreate) (*models.Article, error) {
+// 返回: 创建成功的文章对象（包含关联的作者、分类、标签），如果创建失败则返回错误；
+// 分类不存在时返回 repository.ErrCategoryNotFound，标签不存在时返回 *TagsNotFoundError
+// 注意: 会自动生成slug（如果冲突会自动添加数字后缀），自动生成摘要，支持标签关联；
+// 标题、正文、摘要和 SEO 描述会经过敏感词过滤，命中时按策略返回 *ContentRejectedError、替换或改为待审核
+func (s *ArticleService) Create(ctx context.Context, authorID uuid.UUID, req *models.ArticleCreate) (*models.Article, error) {
+	// 敏感词过滤在生成 slug 和摘要之前进行，替换后的内容不会出现在 slug 和摘要中
+	title, content, excerpt, metaDescription := req.Title, req.Content, req.Excerpt, req.MetaDescription
+	review, err := filterContent(ctx, "article", &title, &content, &excerpt, &metaDescription)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分类和标签在生成摘要（可能调用外部摘要服务）之前校验，引用不存在时不做多余的工作
+	if req.CategoryID != nil {
+		if err := s.ensureCategoryExists(ctx, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.articleRepo.EnsureTagsExist(ctx, req.TagIDs); err != nil {
+		return nil, err
+	}
+
 	// 生成slug
-	slug := GenerateSlug(req.Title)
+	slug := GenerateSlug(title)
 	if slug == "" {
 		slug = "article"
 	}
-	// 检查slug是否已存在，如果存在则添加数字后缀
-	originalSlug := slug
-	counter := 1
 
 	// 生成摘要
-	excerpt := req.Excerpt
-	if excerpt == "" && len(req.Content) > 200 {
-		excerpt = req.Content[:200] + "..."
-	} else if excerpt == "" {
-		excerpt = req.Content
+	excerptIsAuto := excerpt == ""
+	if excerptIsAuto {
+		excerpt = generateExcerpt(content)
 	}
 
 	article := &models.Article{
-		Title:      req.Title,
+		Title:      title,
 		Slug:       slug,
-		Content:    req.Content,
+		Content:    content,
 		Excerpt:    excerpt,
 		CoverImage: req.CoverImage,
 		Status:     req.Status,
 		AuthorID:   authorID,
+
+		MetaDescription: metaDescription,
 	}
+	applyReadingStats(article)
 
 	if article.Status == "" {
 		article.Status = models.StatusDraft
 	}
-
-	if req.CategoryID != nil {
-		article.CategoryID = req.CategoryID
+	article.ScheduledAt = req.ScheduledAt
+	// 非管理员的状态已由调用方（ArticleHandler.Create）整理，这里按可直接发布处理
+	if err := applySchedule(article, true); err != nil {
+		return nil, err
+	}
+	if review {
+		article.Status = routeToReview(article.Status)
 	}
+	s.autoFillSummary(ctx, article, excerptIsAuto)
+	article.CategoryID = req.CategoryID
 
 	// 创建时如果遇到 slug 唯一约束冲突，则自动追加数字后缀重试几次
-	const maxSlugRetries = 5
-	for retries := 0; retries < maxSlugRetries; retries++ {
-		article.Slug = slug
-
-		if err := s.articleRepo.Create(context.Background(), article); err != nil {
-			// 唯一约束冲突：尝试下一个 slug
-			if isSlugUniqueViolation(err) {
-				slug = fmt.Sprintf("%s-%d", originalSlug, counter)
-				counter++
-				continue
+	_, err = WithUniqueSlug(slug, isSlugUniqueViolation, func(candidate string) error {
+		article.Slug = candidate
+		// 文章和标签关系在同一事务中写入，任一步失败都不会留下半成品
+		return database.WithTx(ctx, func(tx *gorm.DB) error {
+			if err := s.articleRepo.CreateTx(tx, article); err != nil {
+				return err
 			}
-			return nil, fmt.Errorf("failed to create article: %w", err)
-		}
-
-		// 如果需要标签，追加标签关系
-		if len(req.TagIDs) > 0 {
-			if err := s.articleRepo.AddTags(article.ID, req.TagIDs); err != nil {
-				return nil, fmt.Errorf("failed to add article tags: %w", err)
+			// 事务中再次校验标签，期间被删除的标签同样返回 *TagsNotFoundError
+			if err := s.articleRepo.AddTagsTx(tx, article.ID, req.TagIDs); err != nil {
+				return fmt.Errorf("failed to add article tags: %w", err)
 			}
-		}
+			return s.articleRepo.AddStatusChangeTx(tx, &models.ArticleStatusChange{
+				ArticleID: article.ID,
+				ToStatus:  article.Status,
+				ActorID:   &authorID,
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create article: %w", err)
+	}
 
-		// 创建成功，重新从数据库获取完整数据（含作者、分类、标签等关联）
-		created, err := s.articleRepo.GetByIDWithContext(context.Background(), article.ID)
-		if err != nil {
-			return nil, err
-		}
+	// 创建成功，重新从数据库获取完整数据（含作者、分类、标签等关联）
+	created, err := s.articleRepo.GetByID(ctx, article.ID)
+	if err != nil {
+		return nil, err
+	}
 
-		// 写入详情缓存，并清理列表缓存
-		_ = cacheArticleDetail(created)
-		clearArticleListCache()
+	metrics.RecordArticleCreation()
 
-		// 异步同步到 Elasticsearch（如果已启用）
-		go func(a *models.Article) {
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			defer cancel()
-			_ = search.IndexArticle(ctx, a)
-		}(created)
+	// 写入详情缓存，并清理列表缓存
+	_ = cacheArticleDetail(created)
+	clearArticleListCache()
+
+	// 异步同步到 Elasticsearch（如果已启用）
+	search.IndexArticleAsync(ctx, created)
 
-		return created, nil
+	if created.Status == models.StatusPublished {
+		emitWebhookEvent(ctx, models.WebhookEventArticlePublished, newWebhookArticle(created))
 	}
 
-	return nil, fmt.Errorf("failed to create article: slug already exists after %d retries", maxSlugRetries)
+	return created, nil
 }
 
 // GetByID 根据ID获取文章详情
 // id: 文章UUID
 // 返回: 文章对象（包含关联的作者、分类、标签），如果不存在则返回错误
 // 注意: 优先从Redis缓存读取，缓存未命中时从数据库读取并写入缓存，会异步增加浏览计数
-func (s *ArticleService) GetByID(id uuid.UUID) (*models.Article, error) {
+func (s *ArticleService) GetByID(ctx context.Context, id uuid.UUID) (*models.Article, error) {
 	// 优先从缓存读取
 	if article, err := getArticleDetailFromCache(id); err == nil && article != nil {
 		// 增加浏览计数（缓冲）
-		go incrementArticleViewCountBuffered(article.ID)
+		countArticleViewAsync(ctx, article.ID)
 		return article, nil
 	}
 
-	article, err := s.articleRepo.GetByIDWithContext(context.Background(), id)
+	article, err := s.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -148,58 +225,406 @@ func (s *ArticleService) GetByID(id uuid.UUID) (*models.Article, error) {
 	_ = cacheArticleDetail(article)
 
 	// 增加浏览计数：优先写入 Redis 作为缓冲，失败时退回到数据库自增
-	go incrementArticleViewCountBuffered(article.ID)
+	countArticleViewAsync(ctx, article.ID)
 
 	return article, nil
 }
 
 // GetBySlug 根据slug获取文章详情
-// slug: 文章URL友好的标识符
-// 返回: 文章对象，如果不存在则返回错误
-// 注意: 会异步增加浏览计数
-func (s *ArticleService) GetBySlug(slug string) (*models.Article, error) {
-	article, err := s.articleRepo.GetBySlugWithContext(context.Background(), slug)
+// slug: 文章URL友好的标识符，也可以是文章修改前的旧 slug
+// 返回: 文章对象，如果不存在则返回错误；按旧 slug 找到时返回的文章 slug 与参数不同，调用方可据此重定向
+// 注意: 与 GetByID 相同，优先按 slug 读取详情缓存，浏览计数写入 Redis 缓冲（按旧 slug 访问时不计，客户端跟随重定向后再计入）
+func (s *ArticleService) GetBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	if article, err := getArticleDetailBySlugFromCache(slug); err == nil && article != nil {
+		countArticleViewAsync(ctx, article.ID)
+		return article, nil
+	}
+
+	article, redirected, err := s.getBySlugOrRedirect(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
+	if redirected {
+		return article, nil
+	}
+
+	// 写入缓存（忽略错误），同时记录 slug 到 ID 的映射
+	_ = cacheArticleDetail(article)
 
-	// 增加浏览次数
-	go s.articleRepo.IncrementViewCount(article.ID)
+	countArticleViewAsync(ctx, article.ID)
 
 	return article, nil
 }
 
+// getBySlugOrRedirect 按 slug 获取文章，找不到时按文章修改前的旧 slug 查找
+// 返回: 文章对象，以及是否通过旧 slug 找到
+func (s *ArticleService) getBySlugOrRedirect(ctx context.Context, slug string) (*models.Article, bool, error) {
+	article, err := s.articleRepo.GetBySlug(ctx, slug)
+	if err == nil || !errors.Is(err, repository.ErrArticleNotFound) {
+		return article, false, err
+	}
+	id, ok, resolveErr := s.articleRepo.ResolveSlugRedirect(ctx, slug)
+	if resolveErr != nil {
+		return nil, false, resolveErr
+	}
+	if !ok {
+		return nil, false, err
+	}
+	article, err = s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	return article, true, nil
+}
+
+// GetMeta 获取已发布文章的 SEO 字段（标题、描述、分享图、canonical 地址和时间）
+// slug: 文章URL友好的标识符
+// 返回: 文章不存在或未发布时返回 ErrArticleNotFound
+// 注意: 不计入浏览次数；描述为空时使用摘要，封面为相对路径时按 server.public_url 补全
+func (s *ArticleService) GetMeta(ctx context.Context, slug string) (*models.ArticleMeta, error) {
+	article, _, err := s.getBySlugOrRedirect(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != models.StatusPublished {
+		return nil, repository.ErrArticleNotFound
+	}
+
+	var server config.ServerConfig
+	if current := config.Get(); current != nil {
+		server = current.Server
+	}
+	description := article.MetaDescription
+	if description == "" {
+		description = article.Excerpt
+	}
+	return &models.ArticleMeta{
+		Title:           article.Title,
+		MetaDescription: description,
+		OGImage:         absoluteURL(server.PublicURL, article.CoverImage),
+		Canonical:       absoluteURL(server.FrontendURL, "/articles/"+article.ID.String()),
+		PublishedAt:     article.PublishedAt,
+		UpdatedAt:       article.UpdatedAt,
+	}, nil
+}
+
+// ExportHTML 将文章渲染为独立的 HTML 文档（阅读 / 打印视图），用于下载、邮件分享和打印为 PDF
+// userID: 当前用户ID，未登录时为 nil
+// 返回: 文件名和 HTML；文章不存在，或未发布且当前用户不是作者时返回 ErrArticleNotFound
+// 注意: 正文按 Markdown 渲染（见 pkg/markdown，原文中的 HTML 按文本输出），封面和正文中的站内图片按 server.public_url 补全为绝对地址；
+// 渲染结果按文章 ID 和更新时间缓存 articleExportCacheTTL，文章修改后自然失效
+func (s *ArticleService) ExportHTML(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*models.ArticleHTMLExport, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != models.StatusPublished && (userID == nil || *userID != article.AuthorID) {
+		return nil, repository.ErrArticleNotFound
+	}
+
+	filename := article.Slug
+	if filename == "" {
+		filename = article.ID.String()
+	}
+	export := &models.ArticleHTMLExport{Filename: filename + ".html"}
+	if cached, err := getArticleExportFromCache(article); err == nil {
+		export.HTML = cached
+		return export, nil
+	}
+
+	if export.HTML, err = renderArticleHTML(article); err != nil {
+		return nil, err
+	}
+	_ = cacheArticleExport(article, export.HTML)
+	return export, nil
+}
+
+// renderArticleHTML 用 articleExportTemplate 渲染文章
+func renderArticleHTML(article *models.Article) ([]byte, error) {
+	var publicURL string
+	if current := config.Get(); current != nil {
+		publicURL = current.Server.PublicURL
+	}
+	absolute := func(u string) string {
+		if strings.HasPrefix(u, "//") || strings.HasPrefix(u, "mailto:") {
+			return u
+		}
+		return absoluteURL(publicURL, u)
+	}
+
+	data := articleExportData{
+		Title:      article.Title,
+		CoverImage: absolute(article.CoverImage),
+		Body:       template.HTML(markdown.Render(article.Content, markdown.Options{ImageURL: absolute})),
+	}
+	if article.Author != nil {
+		data.Author = article.Author.Username
+	}
+	if article.PublishedAt != nil {
+		data.PublishedAt = article.PublishedAt.In(config.Location())
+	}
+
+	var buf bytes.Buffer
+	if err := articleExportTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateSummary 为文章生成摘要和 SEO 描述建议，不保存
+// userID: 当前用户ID
+// canEditOthers: 是否可以处理他人的文章（管理员、编辑），为 false 时只能处理自己的文章，否则返回 ErrNotArticleAuthor
+// 返回: 摘要建议；摘要服务未配置或调用失败时 Generated 为 false，摘要为截取正文得到的
+func (s *ArticleService) GenerateSummary(ctx context.Context, id, userID uuid.UUID, canEditOthers bool) (*models.ArticleSummary, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canEditOthers && article.AuthorID != userID {
+		return nil, ErrNotArticleAuthor
+	}
+	return s.summarize(ctx, article), nil
+}
+
+// StatusHistory 获取文章的状态变更历史（按时间先后，含审核退回原因）
+// userID: 当前用户ID
+// canViewOthers: 是否可以查看他人的文章（管理员、编辑），为 false 时只能查看自己的文章，否则返回 ErrNotArticleAuthor
+func (s *ArticleService) StatusHistory(ctx context.Context, id, userID uuid.UUID, canViewOthers bool) ([]*models.ArticleStatusChange, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewOthers && article.AuthorID != userID {
+		return nil, ErrNotArticleAuthor
+	}
+	changes, err := s.articleRepo.ListStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []*models.ArticleStatusChange{}
+	}
+	return changes, nil
+}
+
+// contextUserID 从请求上下文中取出当前登录用户的 ID，未登录（或后台任务）时返回 nil
+func contextUserID(ctx context.Context) *uuid.UUID {
+	id, err := uuid.Parse(logger.ContextFields(ctx).UserID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// summarize 调用摘要服务生成摘要，未配置或失败时退回截取正文的摘要
+func (s *ArticleService) summarize(ctx context.Context, article *models.Article) *models.ArticleSummary {
+	fallback := &models.ArticleSummary{Excerpt: generateExcerpt(article.Content)}
+	if s.summarizer == nil {
+		return fallback
+	}
+	summary, err := s.summarizer.Summarize(ctx, article.Title, article.Content, settingInt(models.SettingExcerptLength))
+	if err != nil {
+		if !errors.Is(err, ErrSummarizerDisabled) {
+			l := logger.FromContext(ctx)
+			l.Warn().Err(err).Str("article_id", article.ID.String()).Msg("failed to generate article summary, using excerpt")
+		}
+		return fallback
+	}
+	return &models.ArticleSummary{
+		Excerpt:         summary,
+		MetaDescription: cutRunes(summary, metaDescriptionMaxLen),
+		Generated:       true,
+	}
+}
+
+// autoFillSummary 开启 auto_summary 时，在文章发布（或定时发布）前用生成的摘要补齐空缺的摘要和 SEO 描述
+// excerptIsAuto: 当前摘要是否为截取正文自动生成的（视为空缺）
+// 注意: 生成失败时保留原值，不影响发布
+func (s *ArticleService) autoFillSummary(ctx context.Context, article *models.Article, excerptIsAuto bool) {
+	if article.Status != models.StatusPublished && article.Status != models.StatusScheduled {
+		return
+	}
+	if !excerptIsAuto && article.MetaDescription != "" {
+		return
+	}
+	if !settingBool(models.SettingAutoSummary) {
+		return
+	}
+	summary := s.summarize(ctx, article)
+	if !summary.Generated {
+		return
+	}
+	if excerptIsAuto {
+		article.Excerpt = summary.Excerpt
+	}
+	if article.MetaDescription == "" {
+		article.MetaDescription = summary.MetaDescription
+	}
+}
+
+// ensureCategoryExists 校验文章关联的分类存在且未删除
+// 返回: 分类不存在时返回包装了 repository.ErrCategoryNotFound 的错误（含分类 ID）
+func (s *ArticleService) ensureCategoryExists(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return fmt.Errorf("%w: %s", repository.ErrCategoryNotFound, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// applySchedule 整理定时发布字段：定时发布必须指定时间，时间已到时直接改为发布；发布后清空定时时间
+// canPublish: 当前用户能否直接发布（管理员），为 false 时已到时间的定时发布改为待审核，不会绕过审核
+// 注意: 其余状态（如转入审核）保留定时时间，审核通过改为定时发布时可沿用
+func applySchedule(article *models.Article, canPublish bool) error {
+	switch article.Status {
+	case models.StatusScheduled:
+		if article.ScheduledAt == nil {
+			return ErrScheduledAtRequired
+		}
+		if article.ScheduledAt.After(time.Now()) {
+			break
+		}
+		if !canPublish {
+			article.Status = models.StatusReview
+			break
+		}
+		article.Status = models.StatusPublished
+		article.ScheduledAt = nil
+	case models.StatusPublished:
+		article.ScheduledAt = nil
+	}
+	return nil
+}
+
+// routeToReview 内容命中敏感词且策略为 review 时，将要公开（发布或定时发布）的文章改为待审核，草稿等状态不变
+func routeToReview(status models.ArticleStatus) models.ArticleStatus {
+	if status == models.StatusPublished || status == models.StatusScheduled {
+		return models.StatusReview
+	}
+	return status
+}
+
+// absoluteURL 将站内路径拼接到 base 之后，已是 http(s) 地址或为空时原样返回
+func absoluteURL(base, path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
 // Update 更新文章信息
 // id: 文章UUID
 // req: 文章更新请求，包含可选的标题、内容、摘要、封面、状态、分类、标签等
-// 返回: 更新后的文章对象，如果更新失败则返回错误
-// 注意: 标题改变时会自动更新slug，内容改变时会自动生成摘要，会清理相关缓存并异步同步到Elasticsearch
-func (s *ArticleService) Update(id uuid.UUID, req *models.ArticleUpdate) (*models.Article, error) {
-	article, err := s.articleRepo.GetByIDWithContext(context.Background(), id)
+// 返回: 更新后的文章对象；未提供 ExpectedVersion 时返回 ErrArticleVersionRequired，
+// 版本号已过期时返回 *ArticleVersionConflictError（附带当前文章）
+// 注意: 修改标题不改变 slug，只有显式传入 Slug 时才修改（旧 slug 之后仍可访问），内容改变时会自动生成摘要，
+// 会清理相关缓存并异步同步到Elasticsearch；
+// 本次修改的文本字段会经过敏感词过滤（同 Create）
+func (s *ArticleService) Update(ctx context.Context, id uuid.UUID, req *models.ArticleUpdate) (*models.Article, error) {
+	if req.ExpectedVersion == nil {
+		return nil, ErrArticleVersionRequired
+	}
+
+	article, err := s.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	return s.update(ctx, article, req, true)
+}
 
+// UpdateAsUser 以指定用户的身份更新文章，只有作者本人、编辑和管理员可以修改
+// userID: 当前用户ID
+// role: 当前用户角色
+// 返回: 文章不存在时返回 ErrArticleNotFound，无权修改时返回 ErrNotArticleAuthor，其余同 Update
+// 注意: 非管理员把定时发布时间改到已过去时，文章改为待审核而不是立即发布
+func (s *ArticleService) UpdateAsUser(ctx context.Context, id, userID uuid.UUID, role models.UserRole, req *models.ArticleUpdate) (*models.Article, error) {
+	if req.ExpectedVersion == nil {
+		return nil, ErrArticleVersionRequired
+	}
+
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageArticle(article, userID, role) {
+		return nil, ErrNotArticleAuthor
+	}
+	return s.update(ctx, article, req, role == models.RoleAdmin)
+}
+
+// canManageArticle 判断用户是否可以修改或删除文章：作者本人、编辑和管理员
+func canManageArticle(article *models.Article, userID uuid.UUID, role models.UserRole) bool {
+	return article.AuthorID == userID || role == models.RoleAdmin || role == models.RoleEditor
+}
+
+// update 将更新请求应用到已读取的文章，调用方需保证 req.ExpectedVersion 不为 nil
+// canPublish: 能否直接发布，为 false 时已到时间的定时发布改为待审核（见 applySchedule）
+func (s *ArticleService) update(ctx context.Context, article *models.Article, req *models.ArticleUpdate, canPublish bool) (*models.Article, error) {
+	if req.Status != nil && !req.Status.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArticleStatus, *req.Status)
+	}
+	id := article.ID
+	if article.Version != *req.ExpectedVersion {
+		return nil, &ArticleVersionConflictError{Expected: *req.ExpectedVersion, Current: article}
+	}
+	previousStatus := article.Status
+	// 修改前的快照，内容或状态有变化时写入修订记录
+	revision := &models.ArticleRevision{
+		ArticleRevisionMeta: models.ArticleRevisionMeta{
+			ArticleID: id,
+			Version:   article.Version,
+			Title:     article.Title,
+			Status:    article.Status,
+			EditorID:  contextUserID(ctx),
+		},
+		Content: article.Content,
+		Excerpt: article.Excerpt,
+	}
+	wasPublished := article.Status == models.StatusPublished
+	wasPublic := wasPublished || article.Status == models.StatusScheduled
+	wasInReview := article.Status == models.StatusReview
+
+	// 敏感词只检查本次修改的文本字段
+	var filtered []*string
 	if req.Title != nil {
 		article.Title = *req.Title
-		// 如果标题改变，更新slug
-		article.Slug = GenerateSlug(*req.Title)
+		filtered = append(filtered, &article.Title)
 	}
-
 	if req.Content != nil {
 		article.Content = *req.Content
-		// 如果内容改变但没有摘要，自动生成摘要
-		if req.Excerpt == nil {
-			if len(*req.Content) > 200 {
-				article.Excerpt = (*req.Content)[:200] + "..."
-			} else {
-				article.Excerpt = *req.Content
-			}
-		}
+		filtered = append(filtered, &article.Content)
 	}
-
 	if req.Excerpt != nil {
 		article.Excerpt = *req.Excerpt
+		filtered = append(filtered, &article.Excerpt)
+	}
+	if req.MetaDescription != nil {
+		article.MetaDescription = *req.MetaDescription
+		filtered = append(filtered, &article.MetaDescription)
+	}
+	review, err := filterContent(ctx, "article", filtered...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 修改标题不改变 slug，避免已分享的链接失效；只有显式传入 slug 时才修改（空字符串表示按标题重新生成）
+	previousSlug := article.Slug
+	if req.Slug != nil {
+		slug := GenerateSlug(*req.Slug)
+		if slug == "" {
+			slug = GenerateSlug(article.Title)
+		}
+		if slug == "" {
+			slug = "article"
+		}
+		article.Slug = slug
+	}
+
+	// 如果内容改变但没有摘要，自动生成摘要
+	if req.Content != nil && req.Excerpt == nil {
+		article.Excerpt = generateExcerpt(article.Content)
 	}
 
 	if req.CoverImage != nil {
@@ -209,44 +634,294 @@ func (s *ArticleService) Update(id uuid.UUID, req *models.ArticleUpdate) (*model
 	if req.Status != nil {
 		article.Status = *req.Status
 	}
+	if req.ScheduledAt != nil {
+		article.ScheduledAt = req.ScheduledAt
+	}
+	// 只在修改状态或定时时间时校验，未到期前编辑正文等不受影响
+	if req.Status != nil || req.ScheduledAt != nil {
+		if err := applySchedule(article, canPublish); err != nil {
+			return nil, err
+		}
+	}
+	if review {
+		article.Status = routeToReview(article.Status)
+	}
+	if !wasPublic {
+		// 摘要为空或仍是截取正文得到的，视为没有填写
+		s.autoFillSummary(ctx, article, article.Excerpt == "" || article.Excerpt == generateExcerpt(article.Content))
+	}
 	if req.CategoryID != nil {
+		if err := s.ensureCategoryExists(ctx, *req.CategoryID); err != nil {
+			return nil, err
+		}
 		article.CategoryID = req.CategoryID
 	}
 
-	if err := s.articleRepo.Update(article); err != nil {
-		return nil, err
-	}
+	// 每次保存都重新计算字数和阅读时长（尚未回填的旧文章也随之更新）
+	applyReadingStats(article)
 
-	// 如传入标签 ID，则替换标签关系
-	if len(req.TagIDs) > 0 {
-		if err := s.articleRepo.ReplaceTags(article.ID, req.TagIDs); err != nil {
-			return nil, err
+	// 文章字段和标签关系在同一事务中更新
+	write := func() error {
+		return database.WithTx(ctx, func(tx *gorm.DB) error {
+			return s.updateTx(ctx, tx, article, req, revision, previousStatus, previousSlug)
+		})
+	}
+	if article.Slug != previousSlug {
+		// 新 slug 与其他文章冲突时与 Create 相同，追加数字后缀重试
+		_, err = WithUniqueSlug(article.Slug, isSlugUniqueViolation, func(candidate string) error {
+			article.Slug = candidate
+			return write()
+		})
+	} else {
+		err = write()
+	}
+	if errors.Is(err, ErrArticleVersionConflict) {
+		// 读取之后、写入之前被其他请求修改
+		current, getErr := s.articleRepo.GetByID(ctx, id)
+		if getErr != nil {
+			return nil, getErr
 		}
+		return nil, &ArticleVersionConflictError{Expected: *req.ExpectedVersion, Current: current}
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	updated, err := s.articleRepo.GetByIDWithContext(context.Background(), id)
+	if article.Slug != previousSlug {
+		// 旧 slug 改为经重定向访问，不能再命中缓存
+		deleteArticleSlugCache(previousSlug)
+	}
+	updated, err := s.articleRepo.GetByID(ctx, id)
 	if err == nil {
 		// 更新详情缓存，并清理列表缓存
 		_ = cacheArticleDetail(updated)
 		clearArticleListCache()
 
 		// 异步同步到 Elasticsearch
-		go func(a *models.Article) {
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			defer cancel()
-			_ = search.IndexArticle(ctx, a)
-		}(updated)
+		search.IndexArticleAsync(ctx, updated)
+
+		emitWebhookEvent(ctx, models.WebhookEventArticleUpdated, newWebhookArticle(updated))
+		if !wasPublished && updated.Status == models.StatusPublished {
+			emitWebhookEvent(ctx, models.WebhookEventArticlePublished, newWebhookArticle(updated))
+		}
+		// 审核结果（发布或定时发布为通过，退回草稿或归档为拒绝）通知作者
+		if wasInReview && updated.Status != models.StatusReview {
+			approved := updated.Status == models.StatusPublished || updated.Status == models.StatusScheduled
+			notifyArticleReviewed(ctx, updated, approved)
+		}
 	}
 
 	return updated, err
 }
 
+// updateTx 在事务中写入文章更新：修订记录、文章字段、旧 slug、状态历史和标签关系
+func (s *ArticleService) updateTx(ctx context.Context, tx *gorm.DB, article *models.Article, req *models.ArticleUpdate,
+	revision *models.ArticleRevision, previousStatus models.ArticleStatus, previousSlug string) error {
+	if revisionChanged(revision, article) {
+		if err := s.articleRepo.AddRevisionTx(tx, revision); err != nil {
+			return err
+		}
+	}
+	if err := s.articleRepo.UpdateTx(tx, article); err != nil {
+		return err
+	}
+	if article.Slug != previousSlug {
+		if err := s.articleRepo.AddSlugRedirectTx(tx, article.ID, previousSlug, article.Slug); err != nil {
+			return err
+		}
+	}
+	// 状态变化时记录变更历史（含审核退回原因）
+	if article.Status != previousStatus {
+		change := &models.ArticleStatusChange{
+			ArticleID:  article.ID,
+			FromStatus: &previousStatus,
+			ToStatus:   article.Status,
+			ActorID:    contextUserID(ctx),
+		}
+		if req.StatusReason != nil {
+			change.Reason = cutRunes(strings.TrimSpace(*req.StatusReason), articleStatusReasonMaxLen)
+		}
+		if err := s.articleRepo.AddStatusChangeTx(tx, change); err != nil {
+			return err
+		}
+	}
+	// 传入 tag_ids 时替换标签关系，空数组表示清除全部标签
+	if req.TagIDs != nil {
+		return s.articleRepo.ReplaceTagsTx(tx, article.ID, req.TagIDs)
+	}
+	return nil
+}
+
+// revisionChanged 判断修改后的文章与修改前的快照相比，标题、正文、摘要或状态是否有变化
+func revisionChanged(before *models.ArticleRevision, after *models.Article) bool {
+	return before.Title != after.Title || before.Content != after.Content ||
+		before.Excerpt != after.Excerpt || before.Status != after.Status
+}
+
+// ListRevisions 分页获取文章的修订记录（最新的在前，只含元数据，不含正文）
+// userID / role: 当前用户，只有作者本人、编辑和管理员可以查看，否则返回 ErrNotArticleAuthor
+func (s *ArticleService) ListRevisions(ctx context.Context, id, userID uuid.UUID, role models.UserRole, page, pageSize int) ([]*models.ArticleRevisionMeta, int64, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !canManageArticle(article, userID, role) {
+		return nil, 0, ErrNotArticleAuthor
+	}
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	revisions, total, err := s.articleRepo.ListRevisions(ctx, id, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if revisions == nil {
+		revisions = []*models.ArticleRevisionMeta{}
+	}
+	return revisions, total, nil
+}
+
+// GetRevision 获取文章的一条修订记录（含正文），权限同 ListRevisions
+// 返回: 修订记录不存在或不属于该文章时返回 repository.ErrArticleRevisionNotFound
+func (s *ArticleService) GetRevision(ctx context.Context, id, revisionID, userID uuid.UUID, role models.UserRole) (*models.ArticleRevision, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageArticle(article, userID, role) {
+		return nil, ErrNotArticleAuthor
+	}
+	return s.articleRepo.GetRevision(ctx, id, revisionID)
+}
+
+// revisionDiffTimeout 比较两条修订记录的耗时上限
+const revisionDiffTimeout = 3 * time.Second
+
+// DiffRevisions 按词比较文章的两条修订记录（fromID 为旧版本，toID 为新版本）的标题、摘要和正文，权限同 ListRevisions
+// 返回: 修订记录不存在或不属于该文章时返回 repository.ErrArticleRevisionNotFound；
+// 内容超过 textdiff.MaxInputBytes 或比较超过 revisionDiffTimeout 时返回 ErrRevisionDiffTooLarge
+// 注意: 差异过多时退回按行比较或整段替换（见 textdiff.Compare），结果仍可用于展示
+func (s *ArticleService) DiffRevisions(ctx context.Context, id, fromID, toID, userID uuid.UUID, role models.UserRole) (*models.ArticleRevisionDiff, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageArticle(article, userID, role) {
+		return nil, ErrNotArticleAuthor
+	}
+	from, err := s.articleRepo.GetRevision(ctx, id, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.articleRepo.GetRevision(ctx, id, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, revisionDiffTimeout)
+	defer cancel()
+	diff := &models.ArticleRevisionDiff{From: from.ArticleRevisionMeta, To: to.ArticleRevisionMeta}
+	for _, field := range []struct {
+		hunks *[]textdiff.Hunk
+		a, b  string
+	}{
+		{&diff.Title, from.Title, to.Title},
+		{&diff.Excerpt, from.Excerpt, to.Excerpt},
+		{&diff.Content, from.Content, to.Content},
+	} {
+		hunks, err := textdiff.Compare(ctx, field.a, field.b)
+		if errors.Is(err, textdiff.ErrInputTooLarge) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrRevisionDiffTooLarge
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hunks == nil {
+			hunks = []textdiff.Hunk{}
+		}
+		*field.hunks = hunks
+	}
+	return diff, nil
+}
+
+// RestoreRevision 将文章的标题、正文和摘要恢复为修订记录中的内容，权限同 ListRevisions
+// 返回: 恢复后的文章；错误同 Update
+// 注意: 恢复本身也是一次修改，当前内容会先写入一条新的修订记录，历史不会丢失；文章状态保持不变
+func (s *ArticleService) RestoreRevision(ctx context.Context, id, revisionID, userID uuid.UUID, role models.UserRole) (*models.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageArticle(article, userID, role) {
+		return nil, ErrNotArticleAuthor
+	}
+	revision, err := s.articleRepo.GetRevision(ctx, id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(ctx, article, &models.ArticleUpdate{
+		Title:           &revision.Title,
+		Content:         &revision.Content,
+		Excerpt:         &revision.Excerpt,
+		ExpectedVersion: &article.Version,
+	}, role == models.RoleAdmin)
+}
+
+// scheduledPublishBatchSize 每次检查最多发布的文章数，其余在下一次检查时发布
+const scheduledPublishBatchSize = 100
+
+// PublishDueArticles 发布定时发布时间已到的文章（由后台定时调用）
+// 返回: 本次发布的文章数
+// 注意: 发布与 Update 修改状态的流程相同（设置 published_at、记录状态历史、清理缓存、同步 Elasticsearch、触发 Webhook）；
+// 多个实例同时检查时由版本号保证每篇文章只发布一次，单篇文章失败只记录日志，下一次检查时重试
+func (s *ArticleService) PublishDueArticles(ctx context.Context) (int, error) {
+	defer startBackgroundJob(JobKindScheduledPublish)()
+
+	due, err := s.articleRepo.ListDueScheduled(ctx, time.Now(), scheduledPublishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, article := range due {
+		status := models.StatusPublished
+		version := article.Version
+		_, err := s.Update(ctx, article.ID, &models.ArticleUpdate{Status: &status, ExpectedVersion: &version})
+		switch {
+		case err == nil:
+			published++
+		case errors.Is(err, ErrArticleVersionConflict), errors.Is(err, repository.ErrArticleNotFound):
+			// 已被其他实例发布，或在读取之后被修改 / 删除，下一次检查时按最新状态处理
+		default:
+			l := logger.GetLogger()
+			l.Warn().Err(err).Str("article_id", article.ID.String()).Msg("failed to publish scheduled article")
+		}
+	}
+	return published, nil
+}
+
+// UpdateStatus 只修改文章状态（管理后台使用）
+// expectedVersion: 期望的版本号，为 nil 时以当前版本为准（状态修改按最后一次写入为准）
+// reason: 状态变更说明（如审核退回原因），记录在状态历史中，可为空
+// 返回: 更新后的文章对象；状态未定义时返回 ErrInvalidArticleStatus，版本号已过期时返回 *ArticleVersionConflictError
+func (s *ArticleService) UpdateStatus(ctx context.Context, id uuid.UUID, status models.ArticleStatus, expectedVersion *int, reason string) (*models.Article, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArticleStatus, status)
+	}
+	if expectedVersion == nil {
+		article, err := s.articleRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		expectedVersion = &article.Version
+	}
+	return s.Update(ctx, id, &models.ArticleUpdate{Status: &status, StatusReason: &reason, ExpectedVersion: expectedVersion})
+}
+
 // Delete 删除文章（软删除）
 // id: 文章UUID
 // 返回: 如果删除失败则返回错误
 // 注意: 会清理相关缓存并异步从Elasticsearch删除文档
-func (s *ArticleService) Delete(id uuid.UUID) error {
-	if err := s.articleRepo.Delete(id); err != nil {
+func (s *ArticleService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.articleRepo.Delete(ctx, id); err != nil {
 		return err
 	}
 	// 删除详情缓存并清理列表缓存
@@ -254,48 +929,80 @@ func (s *ArticleService) Delete(id uuid.UUID) error {
 	clearArticleListCache()
 
 	// 异步从 Elasticsearch 删除文档
-	go func(articleID uuid.UUID) {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-		_ = search.DeleteArticle(ctx, articleID)
-	}(id)
+	search.DeleteArticleAsync(ctx, id)
 
 	return nil
 }
 
+// DeleteAsUser 以指定用户的身份删除文章（软删除），只有作者本人、编辑和管理员可以删除
+// userID: 当前用户ID
+// role: 当前用户角色
+// 返回: 文章不存在时返回 ErrArticleNotFound，无权删除时返回 ErrNotArticleAuthor
+func (s *ArticleService) DeleteAsUser(ctx context.Context, id, userID uuid.UUID, role models.UserRole) error {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !canManageArticle(article, userID, role) {
+		return ErrNotArticleAuthor
+	}
+	return s.Delete(ctx, id)
+}
+
 // List 获取文章列表（分页、筛选、搜索、排序）
 // query: 文章查询条件，包含页码、每页数量、状态、分类、标签、作者、搜索关键词、排序等
 // 返回: 文章列表、总数，如果查询失败则返回错误
 // 注意: 
-//   - 如果有搜索关键词，使用Elasticsearch进行全文搜索
+//   - 如果有搜索关键词，优先使用Elasticsearch进行全文搜索；Elasticsearch 未启用或请求失败时使用 PostgreSQL 全文索引
 //   - 如果没有搜索关键词，从数据库查询
 //   - 优先从Redis缓存读取，缓存未命中时从数据库/Elasticsearch读取并写入缓存
-func (s *ArticleService) List(query models.ArticleQuery) ([]*models.Article, int64, error) {
-	if query.Page <= 0 {
-		query.Page = 1
-	}
-	if query.PageSize <= 0 {
-		query.PageSize = 10
-	}
-	if query.PageSize > 100 {
-		query.PageSize = 100
+func (s *ArticleService) List(ctx context.Context, query models.ArticleQuery) ([]*models.Article, int64, error) {
+	query.Page, query.PageSize = config.NormalizePage(config.PageArticles, query.Page, query.PageSize)
+
+	// 已合并的标签按别名解析到目标标签
+	if query.TagID != nil {
+		if target, ok, err := s.tagRepo.ResolveAlias(ctx, *query.TagID); err == nil && ok {
+			query.TagID = &target
+		}
 	}
 
-	// 如果有搜索关键词，使用Elasticsearch进行全文搜索
+	// 如果有搜索关键词，进行全文搜索
 	if query.Search != "" {
-		return s.searchWithElasticsearch(query)
+		if search.Enabled() {
+			articles, total, err := s.searchWithElasticsearch(ctx, query)
+			if err == nil {
+				metrics.RecordSearch("elasticsearch")
+				if err := s.attachCommentsMeta(ctx, query, articles); err != nil {
+					return nil, 0, err
+				}
+				return articles, total, nil
+			}
+			l := logger.FromContext(ctx, "search")
+			l.Warn().Err(err).Msg("falling back to PostgreSQL full-text search")
+		}
+		articles, total, err := s.articleRepo.SearchFullText(ctx, query)
+		if err != nil {
+			return nil, 0, err
+		}
+		metrics.RecordSearch("database")
+		if err := s.attachCommentsMeta(ctx, query, articles); err != nil {
+			return nil, 0, err
+		}
+		return articles, total, nil
 	}
 
-	// 尝试从缓存读取列表
+	// 尝试从缓存读取列表（include=comments_meta 时缓存中已带有评论统计）
 	if articles, total, err := getArticleListFromCache(query); err == nil && articles != nil {
 		return articles, total, nil
 	}
 
-	ctx := context.Background()
 	articles, total, err := s.articleRepo.List(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
+	if err := s.attachCommentsMeta(ctx, query, articles); err != nil {
+		return nil, 0, err
+	}
 
 	// 写入缓存（忽略错误）
 	_ = cacheArticleList(query, articles, total)
@@ -303,18 +1010,57 @@ func (s *ArticleService) List(query models.ArticleQuery) ([]*models.Article, int
 	return articles, total, nil
 }
 
+// Archive 按发布月份统计已发布文章数（如 2024-03 共 12 篇），按月份倒序
+// 注意: 结果缓存在文章列表缓存命名空间下，文章发布、修改或删除清理列表缓存时一并失效
+func (s *ArticleService) Archive(ctx context.Context) ([]*models.ArchiveMonth, error) {
+	if months, err := getArticleArchiveFromCache(); err == nil {
+		return months, nil
+	}
+
+	months, err := s.articleRepo.ArchiveMonths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_ = cacheArticleArchive(months)
+	return months, nil
+}
+
+// attachCommentsMeta 查询带有 include=comments_meta 时，为本页文章批量加载已通过评论的统计
+// 注意: 一次分组查询覆盖本页全部文章，没有评论的文章数量为 0
+func (s *ArticleService) attachCommentsMeta(ctx context.Context, query models.ArticleQuery, articles []*models.Article) error {
+	if !query.Includes(models.ArticleIncludeCommentsMeta) || len(articles) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	meta, err := s.articleRepo.CommentsMeta(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if m, ok := meta[article.ID]; ok {
+			article.CommentsMeta = m
+		} else {
+			article.CommentsMeta = &models.ArticleCommentsMeta{}
+		}
+	}
+	return nil
+}
+
 // searchWithElasticsearch 使用Elasticsearch进行全文搜索
 // query: 文章查询条件，必须包含Search字段
 // 返回: 文章列表、总数，如果搜索失败则返回错误
-// 注意: 如果Elasticsearch不可用，返回错误（不再fallback到PostgreSQL）
-func (s *ArticleService) searchWithElasticsearch(query models.ArticleQuery) ([]*models.Article, int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// 注意: 如果Elasticsearch不可用，返回错误，由 List 回退到 PostgreSQL 全文搜索
+func (s *ArticleService) searchWithElasticsearch(ctx context.Context, query models.ArticleQuery) ([]*models.Article, int64, error) {
+	ctx, cancel := search.RequestContext(ctx)
 	defer cancel()
 
 	// 使用Elasticsearch搜索（支持模糊搜索和按创建时间排序）
 	ids, total, err := search.SearchArticles(ctx, query)
 	if err != nil {
-		l := logger.GetLogger()
+		l := logger.FromContext(ctx, "search")
 		l.Error().Err(err).Msg("Elasticsearch search failed")
 		return nil, 0, fmt.Errorf("search service unavailable: %w", err)
 	}
@@ -323,109 +1069,166 @@ func (s *ArticleService) searchWithElasticsearch(query models.ArticleQuery) ([]*
 		return []*models.Article{}, 0, nil
 	}
 
-	// 从数据库批量获取文章详情（保持原有逻辑，但可以优化为批量查询）
-	articles := make([]*models.Article, 0, len(ids))
-	for _, id := range ids {
-		art, err := s.articleRepo.GetByIDWithContext(ctx, id)
+	// 从数据库批量获取文章详情（保持 Elasticsearch 返回的顺序，不存在或已删除的文章跳过）
+	// 筛选条件已在 Elasticsearch 查询中应用，总数使用 Elasticsearch 的命中数，保证分页正确
+	articles, err := s.articleRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+// NewArticleLiker 根据当前用户构造点赞者，未登录时使用 IP 的 SHA-256 作为指纹
+// userID: 当前登录用户ID，未登录时为 nil
+// ip: 客户端 IP
+func NewArticleLiker(userID *uuid.UUID, ip string) models.ArticleLiker {
+	if userID != nil {
+		return models.ArticleLiker{UserID: userID}
+	}
+	sum := sha256.Sum256([]byte(ip))
+	return models.ArticleLiker{Fingerprint: hex.EncodeToString(sum[:])}
+}
+
+// Like 点赞文章，同一用户（匿名用户按 IP 指纹）对同一篇文章只计一次
+// 返回: 是否为新的点赞，重复点赞返回 false 且不改变计数；文章不存在时返回 ErrArticleNotFound
+// 注意: 先在 article_likes 中去重，只有新的点赞才写计数（优先写入 Redis 缓冲）
+func (s *ArticleService) Like(ctx context.Context, id uuid.UUID, liker models.ArticleLiker) (bool, error) {
+	added, err := s.articleRepo.AddLike(ctx, id, liker)
+	if err != nil || !added {
+		return false, err
+	}
+	if err := s.adjustLikeCount(ctx, id, 1); err != nil {
+		return false, err
+	}
+	metrics.RecordArticleLike()
+	return true, nil
+}
+
+// Unlike 取消点赞
+// 返回: 是否取消了点赞，未点过赞时返回 false 且不改变计数
+func (s *ArticleService) Unlike(ctx context.Context, id uuid.UUID, liker models.ArticleLiker) (bool, error) {
+	removed, err := s.articleRepo.RemoveLike(ctx, id, liker)
+	if err != nil || !removed {
+		return false, err
+	}
+	if err := s.adjustLikeCount(ctx, id, -1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HasLiked 判断点赞者是否已点赞文章
+func (s *ArticleService) HasLiked(ctx context.Context, id uuid.UUID, liker models.ArticleLiker) (bool, error) {
+	return s.articleRepo.HasLiked(ctx, id, liker)
+}
+
+// ArticleStates 批量查询用户对文章的状态
+// userID: 当前用户ID
+// rawIDs: 客户端传入的文章 ID，不是合法 UUID 的条目放入 InvalidIDs，不影响其余条目
+// 返回: 每个合法 ID 的状态（重复的 ID 只出现一次）
+func (s *ArticleService) ArticleStates(ctx context.Context, userID uuid.UUID, rawIDs []string) (*models.ArticleStatesResult, error) {
+	result := &models.ArticleStatesResult{
+		States:     make(map[uuid.UUID]*models.ArticleState, len(rawIDs)),
+		InvalidIDs: []string{},
+	}
+	ids := make([]uuid.UUID, 0, len(rawIDs))
+	for _, raw := range rawIDs {
+		id, err := uuid.Parse(raw)
 		if err != nil {
-			// 如果文章不存在或已删除，跳过
-			continue
-		}
-		// 应用其他筛选条件（状态、分类、标签、作者）
-		if query.Status != "" && art.Status != query.Status {
-			continue
-		}
-		if query.CategoryID != nil && (art.CategoryID == nil || *art.CategoryID != *query.CategoryID) {
+			result.InvalidIDs = append(result.InvalidIDs, raw)
 			continue
 		}
-		if query.AuthorID != nil && art.AuthorID != *query.AuthorID {
-			continue
+		if _, ok := result.States[id]; !ok {
+			result.States[id] = &models.ArticleState{}
+			ids = append(ids, id)
 		}
-		if query.TagID != nil {
-			hasTag := false
-			for _, tag := range art.Tags {
-				if tag.ID == *query.TagID {
-					hasTag = true
-					break
-				}
-			}
-			if !hasTag {
-				continue
-			}
-		}
-		articles = append(articles, art)
 	}
 
-	// 更新总数（根据筛选后的结果）
-	total = int64(len(articles))
-
-	return articles, total, nil
+	liked, err := s.articleRepo.LikedArticleIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	// 服务端还没有收藏数据，Bookmarked 保持 false
+	for id, state := range result.States {
+		state.Liked = liked[id]
+	}
+	return result, nil
 }
 
-func (s *ArticleService) Like(id uuid.UUID) error {
-	// 点赞计数：优先写入 Redis 作为缓冲，失败时退回到数据库自增
-	if err := incrementArticleLikeCountBuffered(id); err != nil {
-		// 记录日志，但不中断请求
-		return s.articleRepo.IncrementLikeCount(id)
+// adjustLikeCount 调整点赞计数：优先写入 Redis 作为缓冲，失败时退回到数据库
+func (s *ArticleService) adjustLikeCount(ctx context.Context, id uuid.UUID, delta int64) error {
+	if err := adjustArticleLikeCountBuffered(id, delta); err != nil {
+		return s.articleRepo.AdjustLikeCount(ctx, id, delta)
 	}
 	return nil
 }
 
-// SearchWithElasticsearch 使用Elasticsearch进行全文搜索（已废弃，使用List方法替代）
-// 保留此方法以保持向后兼容，但内部调用List方法
-// Deprecated: 使用List方法，传入包含Search字段的ArticleQuery
-func (s *ArticleService) SearchWithElasticsearch(query string, page, pageSize int) ([]*models.Article, int64, error) {
-	searchQuery := models.ArticleQuery{
-		Page:     page,
-		PageSize: pageSize,
-		Search:   query,
-	}
-	return s.List(searchQuery)
+// SearchWithElasticsearch 使用Elasticsearch进行全文搜索
+// query: 完整的文章查询条件（关键词、状态、分类、标签、作者、排序、分页），原样传给 search.SearchArticles
+// 返回: 文章列表、总数
+// 注意: 与带 Search 的 List 相同，Elasticsearch 未初始化或请求失败时回退到数据库全文索引；没有关键词时按普通列表查询
+func (s *ArticleService) SearchWithElasticsearch(ctx context.Context, query models.ArticleQuery) ([]*models.Article, int64, error) {
+	return s.List(ctx, query)
 }
 
 // isSlugUniqueViolation 判断是否为 articles.slug 唯一约束冲突
 func isSlugUniqueViolation(err error) bool {
-	if err == nil {
-		return false
-	}
-	// 目前使用字符串包含判断，兼容 pq/pgx 等驱动的错误文案
-	msg := err.Error()
-	return strings.Contains(msg, "duplicate key value violates unique constraint") &&
-		strings.Contains(msg, "articles_slug_key")
+	return isUniqueViolation(err, "articles_slug_key", "articles.slug")
 }
 
-const (
-	redisArticleViewKeyPrefix = "blog:article:view:"
-	redisArticleLikeKeyPrefix = "blog:article:like:"
-	redisArticleDetailPrefix  = "blog:article:detail:"
-	redisArticleListPrefix    = "blog:article:list:"
-)
+// viewCountInflight 跟踪后台进行中的浏览计数写入
+var viewCountInflight sync.WaitGroup
+
+// countArticleViewAsync 在后台增加文章浏览计数，不阻塞请求返回
+func countArticleViewAsync(ctx context.Context, id uuid.UUID) {
+	viewCountInflight.Add(1)
+	go func() {
+		defer viewCountInflight.Done()
+		incrementArticleViewCountBuffered(ctx, id)
+	}()
+}
+
+// WaitArticleViewCounts 等待后台进行中的浏览计数写入完成，ctx 结束时返回 ctx 的错误（退出前或替换 Redis 客户端前调用）
+func WaitArticleViewCounts(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		viewCountInflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
 // incrementArticleViewCountBuffered 将浏览计数写入 Redis，失败时退回到数据库
-func incrementArticleViewCountBuffered(id uuid.UUID) {
+// 注意: 在请求返回后异步执行，reqCtx 只用于日志字段，不受请求取消影响
+func incrementArticleViewCountBuffered(reqCtx context.Context, id uuid.UUID) {
 	if database.RedisClient == nil {
-		_ = (&repository.ArticleRepository{}).IncrementViewCount(id)
+		_ = (&repository.ArticleRepository{}).IncrementViewCount(context.Background(), id)
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	ctx, cancel := redisWriteContext(context.WithoutCancel(reqCtx))
 	defer cancel()
 	key := redisArticleViewKeyPrefix + id.String()
 	if err := database.RedisClient.Incr(ctx, key).Err(); err != nil {
-		l := logger.GetLogger()
+		l := logger.FromContext(reqCtx)
 		l.Warn().Err(err).Str("key", key).Msg("failed to increment view count in redis, fallback to db")
-		_ = (&repository.ArticleRepository{}).IncrementViewCount(id)
+		_ = (&repository.ArticleRepository{}).IncrementViewCount(context.Background(), id)
 	}
 }
 
-// incrementArticleLikeCountBuffered 将点赞计数写入 Redis，失败时返回错误由上层回退处理
-func incrementArticleLikeCountBuffered(id uuid.UUID) error {
+// adjustArticleLikeCountBuffered 将点赞计数的增量（取消点赞为负数）写入 Redis，失败时返回错误由上层回退处理
+func adjustArticleLikeCountBuffered(id uuid.UUID, delta int64) error {
 	if database.RedisClient == nil {
 		return fmt.Errorf("redis not initialized")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	ctx, cancel := redisWriteContext(context.Background())
 	defer cancel()
 	key := redisArticleLikeKeyPrefix + id.String()
-	if err := database.RedisClient.Incr(ctx, key).Err(); err != nil {
+	if err := database.RedisClient.IncrBy(ctx, key, delta).Err(); err != nil {
 		return err
 	}
 	return nil
@@ -437,22 +1240,24 @@ func FlushArticleCountersFromRedis(ctx context.Context) error {
 		return nil
 	}
 
-	l := logger.GetLogger()
+	// 错误单独上报（带错误详情），日志不再经 hook 重复上报
+	l := logger.FromContext(errorreport.WithoutLogReport(ctx))
 	rdb := database.RedisClient
 
 	// 浏览计数
 	if err := flushCounterPrefix(ctx, rdb, redisArticleViewKeyPrefix, func(id uuid.UUID, delta int64) error {
-		query := `UPDATE articles SET view_count = view_count + $1 WHERE id = $2`
-		return database.DB.Exec(query, delta, id).Error
+		// 同时写入按天汇总表，供仪表盘排行榜使用
+		return (&repository.ArticleRepository{}).AddViews(ctx, id, delta)
 	}); err != nil {
+		errorreport.CaptureError(ctx, err, map[string]string{"worker": "counter_flush", "counter": "view"})
 		l.Error().Err(err).Msg("failed to flush view counters from redis")
 	}
 
 	// 点赞计数
 	if err := flushCounterPrefix(ctx, rdb, redisArticleLikeKeyPrefix, func(id uuid.UUID, delta int64) error {
-		query := `UPDATE articles SET like_count = like_count + $1 WHERE id = $2`
-		return database.DB.Exec(query, delta, id).Error
+		return (&repository.ArticleRepository{}).AdjustLikeCount(ctx, id, delta)
 	}); err != nil {
+		errorreport.CaptureError(ctx, err, map[string]string{"worker": "counter_flush", "counter": "like"})
 		l.Error().Err(err).Msg("failed to flush like counters from redis")
 	}
 
@@ -508,16 +1313,28 @@ type redisCmdable interface {
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
 }
 
+// metaDescriptionMaxLen SEO 描述的最大长度（与 articles.meta_description 列宽一致）
+const metaDescriptionMaxLen = 300
+
+// articleStatusReasonMaxLen 状态变更说明（如审核退回原因）的最大长度，超出部分截断
+const articleStatusReasonMaxLen = 500
+
+// generateExcerpt 根据正文生成摘要，长度（字符数）由 excerpt_length 设置控制，规则见 GenerateExcerpt
+func generateExcerpt(content string) string {
+	return GenerateExcerpt(content, settingInt(models.SettingExcerptLength))
+}
+
 // ---- 文章详情 / 列表缓存 ----
 
 func getArticleDetailFromCache(id uuid.UUID) (*models.Article, error) {
 	if database.RedisClient == nil {
 		return nil, fmt.Errorf("redis not initialized")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	ctx, cancel := redisReadContext(context.Background())
 	defer cancel()
 	key := redisArticleDetailPrefix + id.String()
 	val, err := database.RedisClient.Get(ctx, key).Bytes()
+	recordCacheLookup(cacheMetricArticleDetail, err)
 	if err != nil {
 		return nil, err
 	}
@@ -528,28 +1345,116 @@ func getArticleDetailFromCache(id uuid.UUID) (*models.Article, error) {
 	return &article, nil
 }
 
+// getArticleDetailBySlugFromCache 按 slug 到 ID 的映射读取详情缓存
+// 注意: 缓存的详情 slug 与参数不一致（映射已过时）时按未命中处理
+func getArticleDetailBySlugFromCache(slug string) (*models.Article, error) {
+	if database.RedisClient == nil {
+		return nil, fmt.Errorf("redis not initialized")
+	}
+	ctx, cancel := redisReadContext(context.Background())
+	id, err := database.RedisClient.Get(ctx, redisArticleSlugPrefix+slug).Result()
+	cancel()
+	if err != nil {
+		recordCacheLookup(cacheMetricArticleDetail, err)
+		return nil, err
+	}
+	articleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	article, err := getArticleDetailFromCache(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.Slug != slug {
+		return nil, redis.Nil
+	}
+	return article, nil
+}
+
+// cacheArticleDetail 写入详情缓存和 slug 到 ID 的映射，两者过期时间相同
 func cacheArticleDetail(article *models.Article) error {
 	if database.RedisClient == nil || article == nil {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	ctx, cancel := redisWriteContext(context.Background())
 	defer cancel()
 	key := redisArticleDetailPrefix + article.ID.String()
 	data, err := json.Marshal(article)
 	if err != nil {
 		return err
 	}
-	return database.RedisClient.Set(ctx, key, data, 60*time.Second).Err()
+	ttl := time.Duration(settingInt(models.SettingArticleDetailCacheTTL)) * time.Second
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Set(ctx, key, data, ttl)
+	if article.Slug != "" {
+		pipe.Set(ctx, redisArticleSlugPrefix+article.Slug, article.ID.String(), ttl)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
+// deleteArticleDetailCache 删除详情缓存，缓存中有该文章时一并删除其 slug 映射
 func deleteArticleDetailCache(id uuid.UUID) {
 	if database.RedisClient == nil {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	ctx, cancel := redisWriteContext(context.Background())
 	defer cancel()
-	key := redisArticleDetailPrefix + id.String()
-	_ = database.RedisClient.Del(ctx, key).Err()
+	keys := []string{redisArticleDetailPrefix + id.String()}
+	// 直接读取缓存内容，不计入缓存命中率
+	if data, err := database.RedisClient.Get(ctx, keys[0]).Bytes(); err == nil {
+		var cached models.Article
+		if json.Unmarshal(data, &cached) == nil && cached.Slug != "" {
+			keys = append(keys, redisArticleSlugPrefix+cached.Slug)
+		}
+	}
+	_ = database.RedisClient.Del(ctx, keys...).Err()
+}
+
+// deleteArticleDetailCaches 逐篇删除详情缓存（标签合并、转移等批量修改文章关联后调用）
+// 注意: 不依赖按前缀清理，前缀清理中途失败时这些文章也不会返回旧的标签
+func deleteArticleDetailCaches(ids []uuid.UUID) {
+	for _, id := range ids {
+		deleteArticleDetailCache(id)
+	}
+}
+
+// deleteArticleSlugCache 删除 slug 到 ID 的映射
+func deleteArticleSlugCache(slug string) {
+	if database.RedisClient == nil || slug == "" {
+		return
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	_ = database.RedisClient.Del(ctx, redisArticleSlugPrefix+slug).Err()
+}
+
+// articleExportCacheTTL 导出的 HTML 的缓存时间；缓存键含文章更新时间，只需覆盖短时间内的重复下载
+const articleExportCacheTTL = 5 * time.Minute
+
+func articleExportCacheKey(article *models.Article) string {
+	return redisArticleExportPrefix + article.ID.String() + ":" + strconv.FormatInt(article.UpdatedAt.UnixNano(), 10)
+}
+
+func getArticleExportFromCache(article *models.Article) ([]byte, error) {
+	if database.RedisClient == nil {
+		return nil, fmt.Errorf("redis not initialized")
+	}
+	ctx, cancel := redisReadContext(context.Background())
+	defer cancel()
+	val, err := database.RedisClient.Get(ctx, articleExportCacheKey(article)).Bytes()
+	recordCacheLookup(cacheMetricArticleExport, err)
+	return val, err
+}
+
+func cacheArticleExport(article *models.Article, html []byte) error {
+	if database.RedisClient == nil {
+		return nil
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	return database.RedisClient.Set(ctx, articleExportCacheKey(article), html, articleExportCacheTTL).Err()
 }
 
 type cachedArticleList struct {
@@ -559,7 +1464,12 @@ type cachedArticleList struct {
 
 func buildArticleListCacheKey(q models.ArticleQuery) string {
 	var b strings.Builder
-	b.WriteString(redisArticleListPrefix)
+	// 带评论统计的列表使用单独的前缀，评论变化时只需清理这一部分
+	if q.Includes(models.ArticleIncludeCommentsMeta) {
+		b.WriteString(redisArticleListCommentsPrefix)
+	} else {
+		b.WriteString(redisArticleListPrefix)
+	}
 	b.WriteString(fmt.Sprintf("p=%d&ps=%d", q.Page, q.PageSize))
 	if q.Status != "" {
 		b.WriteString("&status=")
@@ -589,6 +1499,24 @@ func buildArticleListCacheKey(q models.ArticleQuery) string {
 		b.WriteString("&author=")
 		b.WriteString(q.AuthorID.String())
 	}
+	if q.Year > 0 {
+		b.WriteString(fmt.Sprintf("&year=%d&month=%d", q.Year, q.Month))
+	}
+	// 时间范围统一转换为 UTC，同一时刻不同时区的写法共用缓存
+	for _, r := range []struct {
+		name  string
+		bound *time.Time
+	}{
+		{"cf", q.CreatedFrom},
+		{"ct", q.CreatedTo},
+		{"pf", q.PublishedFrom},
+		{"pt", q.PublishedTo},
+	} {
+		if r.bound != nil {
+			b.WriteString("&" + r.name + "=")
+			b.WriteString(r.bound.UTC().Format(time.RFC3339Nano))
+		}
+	}
 	return b.String()
 }
 
@@ -596,10 +1524,11 @@ func getArticleListFromCache(q models.ArticleQuery) ([]*models.Article, int64, e
 	if database.RedisClient == nil {
 		return nil, 0, fmt.Errorf("redis not initialized")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	ctx, cancel := redisReadContext(context.Background())
 	defer cancel()
 	key := buildArticleListCacheKey(q)
 	val, err := database.RedisClient.Get(ctx, key).Bytes()
+	recordCacheLookup(cacheMetricArticleList, err)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -614,7 +1543,7 @@ func cacheArticleList(q models.ArticleQuery, articles []*models.Article, total i
 	if database.RedisClient == nil {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	ctx, cancel := redisWriteContext(context.Background())
 	defer cancel()
 	key := buildArticleListCacheKey(q)
 	data, err := json.Marshal(cachedArticleList{
@@ -625,29 +1554,102 @@ func cacheArticleList(q models.ArticleQuery, articles []*models.Article, total i
 		return err
 	}
 	// 列表数据可以稍长一点 TTL
-	return database.RedisClient.Set(ctx, key, data, 120*time.Second).Err()
+	ttl := time.Duration(settingInt(models.SettingArticleListCacheTTL)) * time.Second
+	return database.RedisClient.Set(ctx, key, data, ttl).Err()
+}
+
+func getArticleArchiveFromCache() ([]*models.ArchiveMonth, error) {
+	if database.RedisClient == nil {
+		return nil, fmt.Errorf("redis not initialized")
+	}
+	ctx, cancel := redisReadContext(context.Background())
+	defer cancel()
+	val, err := database.RedisClient.Get(ctx, redisArticleArchiveKey).Bytes()
+	recordCacheLookup(cacheMetricArticleList, err)
+	if err != nil {
+		return nil, err
+	}
+	var months []*models.ArchiveMonth
+	if err := json.Unmarshal(val, &months); err != nil {
+		return nil, err
+	}
+	return months, nil
+}
+
+func cacheArticleArchive(months []*models.ArchiveMonth) error {
+	if database.RedisClient == nil {
+		return nil
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	data, err := json.Marshal(months)
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(settingInt(models.SettingArticleListCacheTTL)) * time.Second
+	return database.RedisClient.Set(ctx, redisArticleArchiveKey, data, ttl).Err()
+}
+
+// clearArticleCommentsListCache 清理带评论统计（include=comments_meta）的文章列表缓存（评论变化后调用）
+func clearArticleCommentsListCache() {
+	if database.RedisClient == nil {
+		return
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	_, _ = deleteKeysByPrefix(ctx, redisArticleListCommentsPrefix)
 }
 
 // clearArticleListCache 简单粗暴地清理所有文章列表缓存（数据更新后调用）
+// 分类 / 标签列表中的文章数和站点地图随文章变化，一并清理
 func clearArticleListCache() {
 	if database.RedisClient == nil {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	ctx, cancel := redisWriteContext(context.Background())
 	defer cancel()
-	var cursor uint64
-	for {
-		keys, next, err := database.RedisClient.Scan(ctx, cursor, redisArticleListPrefix+"*", 100).Result()
-		if err != nil {
-			return
-		}
-		cursor = next
-		if len(keys) > 0 {
-			_ = database.RedisClient.Del(ctx, keys...).Err()
-		}
-		if cursor == 0 {
-			break
-		}
-	}
+	_, _ = deleteKeysByPrefix(ctx, redisArticleListPrefix)
+	_, _ = deleteKeysByPrefix(ctx, redisTaxonomyListPrefix)
+	_, _ = deleteKeysByPrefix(ctx, redisSitemapPrefix)
+}
+
+type articleExportData struct {
+	Title       string
+	Author      string
+	PublishedAt time.Time
+	CoverImage  string
+	Body        template.HTML
 }
 
+// articleExportTemplate 导出的 HTML 文档，样式内联，不依赖前端资源
+var articleExportTemplate = template.Must(template.New("article_export").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 760px; margin: 40px auto; padding: 0 20px; font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; line-height: 1.75; color: #222; }
+header { margin-bottom: 32px; padding-bottom: 16px; border-bottom: 1px solid #eee; }
+h1 { margin-bottom: 8px; }
+.meta { color: #888; font-size: 14px; }
+img { max-width: 100%; }
+.cover { display: block; margin-top: 16px; }
+pre { padding: 12px; overflow: auto; background: #f6f8fa; }
+code { font-family: Menlo, Consolas, monospace; font-size: 0.9em; }
+blockquote { margin: 0; padding-left: 16px; border-left: 4px solid #ddd; color: #555; }
+@media print { body { max-width: none; margin: 0; } pre { white-space: pre-wrap; } }
+</style>
+</head>
+<body>
+<article>
+<header>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Author}}{{if not .PublishedAt.IsZero}}{{if .Author}} · {{end}}<time datetime="{{.PublishedAt.Format "2006-01-02T15:04:05Z07:00"}}">{{.PublishedAt.Format "2006-01-02"}}</time>{{end}}</p>
+{{if .CoverImage}}<img class="cover" src="{{.CoverImage}}" alt="">{{end}}
+</header>
+{{.Body}}
+</article>
+</body>
+</html>
+`))
@@ -0,0 +1,113 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// auditRedactedKeys 请求体中需要脱敏的字段（按小写子串匹配）
+var auditRedactedKeys = []string{"password", "token", "secret", "code", "authorization"}
+
+const auditRedactedValue = "[REDACTED]"
+
+// AuditService 审计日志服务，负责记录、查询和清理管理后台操作日志
+type AuditService struct {
+	auditRepo *repository.AuditLogRepository
+}
+
+// NewAuditService 创建新的审计日志服务实例
+// auditRepo: 审计日志数据访问层仓库
+func NewAuditService(auditRepo *repository.AuditLogRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record 写入一条审计日志
+func (s *AuditService) Record(ctx context.Context, log *models.AuditLog) error {
+	return s.auditRepo.Create(ctx, log)
+}
+
+// List 分页查询审计日志
+// query: 查询条件（操作人、路径前缀、请求方法、时间范围）
+// 返回: 日志列表（不含请求体）、总数
+func (s *AuditService) List(ctx context.Context, query models.AuditLogQuery) ([]*models.AuditLog, int64, error) {
+	query.Page, query.PageSize = config.NormalizePage(config.PageOther, query.Page, query.PageSize)
+	return s.auditRepo.List(ctx, query)
+}
+
+// GetByID 获取审计日志详情（含脱敏后的请求体）
+func (s *AuditService) GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error) {
+	return s.auditRepo.GetByID(ctx, id)
+}
+
+// Prune 按 audit_log_retention_days 设置清理过期日志
+// 返回: 删除的行数
+func (s *AuditService) Prune(ctx context.Context) (int64, error) {
+	days := settingInt(models.SettingAuditLogRetentionDays)
+	if days <= 0 {
+		return 0, nil
+	}
+	return s.auditRepo.DeleteBefore(ctx, time.Now().AddDate(0, 0, -days))
+}
+
+// RedactAuditPayload 将请求体转换为可存储的 JSON，并脱敏密码、token 等敏感字段
+// 注意: 非 JSON 请求体（如文件上传）只记录内容类型和大小
+func RedactAuditPayload(body []byte, contentType string) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var data interface{}
+	if strings.Contains(contentType, "application/json") && json.Unmarshal(body, &data) == nil {
+		if out, err := json.Marshal(redactValue(data)); err == nil {
+			return out
+		}
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{
+		"content_type": contentType,
+		"size":         len(body),
+	})
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if isRedactedKey(k) {
+				val[k] = auditRedactedValue
+				continue
+			}
+			val[k] = redactValue(child)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = redactValue(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func isRedactedKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, k := range auditRedactedKeys {
+		if strings.Contains(lower, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import "sync"
+
+// 后台任务类型（系统状态面板按类型展示运行中的任务数）
+const (
+	JobKindExport        = "export"
+	JobKindSearchReindex = "search_reindex"
+	JobKindWeeklyReport  = "weekly_report"
+	JobKindNewsletter    = "newsletter"
+	// JobKindScheduledPublish 发布定时发布时间已到的文章
+	JobKindScheduledPublish = "scheduled_publish"
+)
+
+var (
+	backgroundJobsMu sync.Mutex
+	backgroundJobs   = map[string]int64{}
+)
+
+// startBackgroundJob 登记一个开始执行的后台任务，返回的函数在任务结束时调用
+func startBackgroundJob(kind string) (done func()) {
+	backgroundJobsMu.Lock()
+	backgroundJobs[kind]++
+	backgroundJobsMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			backgroundJobsMu.Lock()
+			backgroundJobs[kind]--
+			backgroundJobsMu.Unlock()
+		})
+	}
+}
+
+// RunningBackgroundJobs 返回当前实例中各类型正在执行的后台任务数
+func RunningBackgroundJobs() map[string]int64 {
+	backgroundJobsMu.Lock()
+	defer backgroundJobsMu.Unlock()
+
+	running := map[string]int64{
+		JobKindExport:           0,
+		JobKindSearchReindex:    0,
+		JobKindWeeklyReport:     0,
+		JobKindNewsletter:       0,
+		JobKindScheduledPublish: 0,
+	}
+	for kind, n := range backgroundJobs {
+		running[kind] = n
+	}
+	return running
+}
@@ -0,0 +1,229 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/logger"
+	"enterprise-blog/pkg/metrics"
+)
+
+// Redis 键命名空间（集中定义，缓存读写与缓存清理共用，避免两处不一致）
+//
+// 注意：限流（ratelimit:*）和短信验证码（sms:code:*）不属于应用缓存，
+// 由各自模块维护，任何缓存清理都不会涉及；功能开关的变更通知频道（blog:flags:changed）见 internal/flags
+const (
+	redisKeyPrefix = "blog:"
+
+	// 应用缓存（可随时删除，下次读取时重建）
+	redisArticleDetailPrefix = redisKeyPrefix + "article:detail:"
+	// 文章导出的 HTML，位于 redisArticleDetailPrefix 之下，清理文章详情缓存时一并清理
+	redisArticleExportPrefix = redisArticleDetailPrefix + "export:"
+	// slug 到文章 ID 的映射，位于 redisArticleDetailPrefix 之下，清理文章详情缓存时一并清理
+	redisArticleSlugPrefix = redisArticleDetailPrefix + "slug:"
+	redisArticleListPrefix = redisKeyPrefix + "article:list:"
+	// 带评论统计的文章列表，位于 redisArticleListPrefix 之下，清理全部列表缓存时一并清理
+	redisArticleListCommentsPrefix = redisArticleListPrefix + "comments_meta:"
+	redisDashboardPrefix           = redisKeyPrefix + "dashboard:"
+	redisDashboardTopPrefix        = redisDashboardPrefix + "top:"
+	redisDashboardOverviewKey      = redisDashboardPrefix + "overview"
+	redisContentStatsPrefix        = redisKeyPrefix + "stats:"
+	redisTaxonomyListPrefix        = redisKeyPrefix + "taxonomy:"
+	redisSitemapPrefix             = redisKeyPrefix + "sitemap:"
+
+	// 按月份归档的文章数，位于 redisArticleListPrefix 之下，随文章列表缓存一并清理
+	redisArticleArchiveKey = redisArticleListPrefix + "archive"
+
+	// 计数缓冲（尚未回刷到数据库，不能作为缓存清理）
+	redisArticleViewKeyPrefix = redisKeyPrefix + "article:view:"
+	redisArticleLikeKeyPrefix = redisKeyPrefix + "article:like:"
+
+	// 计数器（随数据变更增减，不能作为缓存清理；缺失时从数据库重建）
+	redisNotificationUnreadPrefix = redisKeyPrefix + "notification:unread:"
+
+	// 活跃用户（有序集合，成员为用户 ID、分数为最近一次认证请求的时间，过期成员由定时任务清理）
+	redisActiveUsersKey = redisKeyPrefix + "users:active"
+
+	// 登录失败记录（有序集合，成员为一次失败、分数为失败时间，窗口外的成员在读写时清理；清除即解锁，不能作为缓存清理）
+	redisLoginFailuresPrefix = redisKeyPrefix + "login:failures:"
+
+	// 草稿预览 token 的登记（键为 jti、值为文章 ID，过期时间与 token 相同；删除即吊销，不能作为缓存清理）
+	redisArticlePreviewPrefix = redisKeyPrefix + "article:preview:"
+
+	// 任务状态、分布式锁与通知频道
+	redisReindexLockKey   = redisKeyPrefix + "search:reindex:lock"
+	redisReindexJobPrefix = redisKeyPrefix + "search:reindex:job:"
+	redisExportJobPrefix  = redisKeyPrefix + "export:job:"
+	redisSettingsChannel  = redisKeyPrefix + "settings:changed"
+	redisWeeklyReportLock = redisKeyPrefix + "report:weekly:lock:"
+)
+
+// 缓存命中率指标中的缓存类型（cache 标签）
+const (
+	cacheMetricArticleDetail = "article_detail"
+	cacheMetricArticleList   = "article_list"
+	cacheMetricArticleExport = "article_export"
+	cacheMetricDashboard     = "dashboard"
+	cacheMetricContentStats  = "content_stats"
+	cacheMetricTaxonomyList  = "taxonomy_list"
+)
+
+// recordCacheLookup 记录一次缓存读取的命中 / 未命中（读取失败同样回源，按未命中计）
+func recordCacheLookup(cache string, err error) {
+	if err == nil {
+		metrics.RecordCacheHit(cache)
+		return
+	}
+	metrics.RecordCacheMiss(cache)
+}
+
+// redisConfig 返回 Redis 配置，配置未加载时（单元测试）使用零值，超时取默认值
+func redisConfig() config.RedisConfig {
+	if current := config.Get(); current != nil {
+		return current.Redis
+	}
+	return config.RedisConfig{}
+}
+
+// redisReadContext 单条 Redis 读命令（缓存读取等）的超时上下文，超时时间为 redis.read_timeout_ms
+func redisReadContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, redisConfig().ReadTimeout())
+}
+
+// redisWriteContext 单次 Redis 写操作（写缓存、计数、按前缀清理缓存等）的超时上下文，超时时间为 redis.write_timeout_ms
+func redisWriteContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, redisConfig().WriteTimeout())
+}
+
+// CacheScope 缓存清理范围
+type CacheScope string
+
+const (
+	CacheScopeArticleDetail CacheScope = "article_detail"
+	CacheScopeArticleList   CacheScope = "article_list"
+	CacheScopeDashboard     CacheScope = "dashboard"
+	CacheScopeAllAppCaches  CacheScope = "all_app_caches"
+)
+
+// ErrInvalidCacheScope 缓存清理范围不合法
+var ErrInvalidCacheScope = errors.New("invalid scope, must be one of article_detail, article_list, dashboard, all_app_caches")
+
+// cacheScopePrefixes 各清理范围对应的键前缀（all_app_caches 为全部范围的并集）
+var cacheScopePrefixes = map[CacheScope][]string{
+	CacheScopeArticleDetail: {redisArticleDetailPrefix},
+	CacheScopeArticleList:   {redisArticleListPrefix, redisTaxonomyListPrefix, redisSitemapPrefix},
+	CacheScopeDashboard:     {redisDashboardPrefix, redisContentStatsPrefix},
+}
+
+// FlushCache 按范围清理应用缓存
+// scope: 清理范围（article_detail / article_list / dashboard / all_app_caches）
+// 返回: 每个范围删除的键数量
+// 注意: 只删除应用缓存命名空间下的键，不影响计数缓冲、限流和短信验证码
+func FlushCache(ctx context.Context, scope CacheScope) (map[CacheScope]int64, error) {
+	var scopes []CacheScope
+	switch scope {
+	case CacheScopeAllAppCaches:
+		for s := range cacheScopePrefixes {
+			scopes = append(scopes, s)
+		}
+		sort.Slice(scopes, func(i, j int) bool { return scopes[i] < scopes[j] })
+	default:
+		if _, ok := cacheScopePrefixes[scope]; !ok {
+			return nil, ErrInvalidCacheScope
+		}
+		scopes = []CacheScope{scope}
+	}
+
+	if database.RedisClient == nil {
+		return nil, database.ErrRedisUnavailable
+	}
+
+	removed := make(map[CacheScope]int64, len(scopes))
+	for _, s := range scopes {
+		for _, prefix := range cacheScopePrefixes[s] {
+			n, err := deleteKeysByPrefix(ctx, prefix)
+			removed[s] += n
+			if err != nil {
+				return removed, err
+			}
+		}
+	}
+	return removed, nil
+}
+
+// deleteKeysByPrefix 使用 SCAN 分批删除指定前缀的键（避免 KEYS 阻塞 Redis）
+// 返回: 删除的键数量
+func deleteKeysByPrefix(ctx context.Context, prefix string) (int64, error) {
+	if database.RedisClient == nil {
+		return 0, nil
+	}
+	var removed int64
+	var cursor uint64
+	for {
+		keys, next, err := database.RedisClient.Scan(ctx, cursor, prefix+"*", 100).Result()
+		if err != nil {
+			return removed, err
+		}
+		if len(keys) > 0 {
+			n, err := database.RedisClient.Del(ctx, keys...).Result()
+			removed += n
+			if err != nil {
+				return removed, err
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			return removed, nil
+		}
+	}
+}
+
+// clearTaxonomyCaches 分类 / 标签删除或恢复后清理相关缓存
+// 文章详情和列表中内嵌了分类和标签，内容统计和分类 / 标签列表也按其聚合，都需要失效
+func clearTaxonomyCaches() {
+	if database.RedisClient == nil {
+		return
+	}
+	for _, prefix := range []string{redisArticleDetailPrefix, redisArticleListPrefix, redisContentStatsPrefix, redisTaxonomyListPrefix, redisSitemapPrefix} {
+		ctx, cancel := redisWriteContext(context.Background())
+		_, err := deleteKeysByPrefix(ctx, prefix)
+		cancel()
+		if err != nil {
+			l := logger.GetLogger()
+			l.Warn().Err(err).Str("prefix", prefix).Msg("failed to clear taxonomy caches")
+			return
+		}
+	}
+}
+
+// taxonomyListCacheTTL 分类 / 标签列表（含文章数）缓存时间；公开标签列表每次页面渲染都会请求
+const taxonomyListCacheTTL = 60 * time.Second
+
+// taxonomyListCacheKey 分类 / 标签列表缓存键，公开列表与管理后台列表的计数口径不同，分开缓存
+func taxonomyListCacheKey(kind string, query models.TaxonomyListQuery) string {
+	scope := "all"
+	if query.PublishedOnly {
+		scope = "published"
+	}
+	sort := query.Sort
+	if sort == "" {
+		sort = "default"
+	}
+	return redisTaxonomyListPrefix + kind + ":" + scope + ":" + sort
+}
+
+// clearTaxonomyListCache 新建分类 / 标签后清理列表缓存
+func clearTaxonomyListCache() {
+	if database.RedisClient == nil {
+		return
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	_, _ = deleteKeysByPrefix(ctx, redisTaxonomyListPrefix)
+}
@@ -2,70 +2,136 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCategoryInUse 分类仍被文章或子分类引用，且未指定 reassign_to
+	ErrCategoryInUse = errors.New("category is still in use")
+	// ErrInvalidReassignTarget 转移目标不存在、已删除、与被删除对象相同或是其子孙分类
+	ErrInvalidReassignTarget = errors.New("invalid reassign target")
+	// ErrInvalidParentCategory 父分类不存在、是分类自身或会形成引用环
+	ErrInvalidParentCategory = errors.New("invalid parent category")
+	// ErrCategorySlugExists 追加数字后缀重试后分类 slug 仍然冲突
+	ErrCategorySlugExists = errors.New("category.slug_exists")
 )
 
+// CategoryInUseError 分类仍被引用时的详细计数，errors.Is(err, ErrCategoryInUse) 为 true
+type CategoryInUseError struct {
+	Articles int64 `json:"articles"` // 引用该分类的未删除文章数（含草稿）
+	Children int64 `json:"children"` // 未删除的直接子分类数
+}
+
+func (e *CategoryInUseError) Error() string {
+	return fmt.Sprintf("%s: %d articles and %d child categories, pass reassign_to to move them",
+		ErrCategoryInUse, e.Articles, e.Children)
+}
+
+func (e *CategoryInUseError) Is(target error) bool {
+	return target == ErrCategoryInUse
+}
+
 // CategoryService 分类服务，提供分类相关的业务逻辑
 type CategoryService struct {
 	categoryRepo *repository.CategoryRepository
+	articleRepo  *repository.ArticleRepository
 }
 
 // NewCategoryService 创建新的分类服务实例
 // categoryRepo: 分类数据访问层仓库
-func NewCategoryService(categoryRepo *repository.CategoryRepository) *CategoryService {
+// articleRepo: 文章数据访问层仓库（删除分类并转移文章后重建受影响文章的搜索索引）
+func NewCategoryService(categoryRepo *repository.CategoryRepository, articleRepo *repository.ArticleRepository) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		articleRepo:  articleRepo,
 	}
 }
 
 // Create 创建新分类
 // req: 分类创建请求，包含名称、描述、父分类ID、排序等
-// 返回: 创建成功的分类对象，如果创建失败则返回错误
-func (s *CategoryService) Create(req *models.CategoryCreate) (*models.Category, error) {
+// 返回: 创建成功的分类对象；父分类不存在时返回 ErrInvalidParentCategory；slug 重试后仍冲突时返回 ErrCategorySlugExists
+func (s *CategoryService) Create(ctx context.Context, req *models.CategoryCreate) (*models.Category, error) {
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *req.ParentID); err != nil {
+			return nil, fmt.Errorf("%w: parent category %s does not exist", ErrInvalidParentCategory, *req.ParentID)
+		}
+	}
+
 	category := &models.Category{
 		Name:        req.Name,
-		Slug:        GenerateSlug(req.Name),
 		Description: req.Description,
 		ParentID:    req.ParentID,
 		Order:       req.Order,
 	}
 
-	if err := s.categoryRepo.Create(category); err != nil {
-		return nil, err
+	// slug 冲突（大小写不敏感）时自动追加数字后缀重试
+	_, err := WithUniqueSlug(GenerateSlug(req.Name), isCategorySlugConflict, func(slug string) error {
+		category.Slug = slug
+		return s.categoryRepo.Create(ctx, category)
+	})
+	if err != nil {
+		return nil, categorySlugError(err)
 	}
+	clearTaxonomyListCache()
 
-	return s.categoryRepo.GetByID(category.ID)
+	return s.categoryRepo.GetByID(ctx, category.ID)
 }
 
 // GetByID 根据ID获取分类详情
 // id: 分类UUID
 // 返回: 分类对象，如果不存在则返回错误
-func (s *CategoryService) GetByID(id uuid.UUID) (*models.Category, error) {
-	return s.categoryRepo.GetByID(id)
+func (s *CategoryService) GetByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	return s.categoryRepo.GetByID(ctx, id)
 }
 
-// List 获取所有分类列表
+// List 获取所有分类列表（含文章数）
+// query: PublishedOnly 为 true 时只统计已发布文章（公开接口），Sort 支持 article_count
 // 返回: 分类列表，如果查询失败则返回错误
-func (s *CategoryService) List() ([]*models.Category, error) {
-	return s.categoryRepo.List()
+// 注意: 结果会在 Redis 中短暂缓存，分类或文章变更时清理
+func (s *CategoryService) List(ctx context.Context, query models.TaxonomyListQuery) ([]*models.Category, error) {
+	cacheKey := taxonomyListCacheKey("categories", query)
+	var cached []*models.Category
+	if err := getDashboardCache(cacheKey, &cached); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	categories, err := s.categoryRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if categories == nil {
+		categories = []*models.Category{}
+	}
+	_ = setDashboardCache(cacheKey, categories, taxonomyListCacheTTL)
+	return categories, nil
 }
 
 // Update 更新分类信息
 // id: 分类UUID
 // req: 分类更新请求，包含可选的名称、描述、父分类ID、排序等
-// 返回: 更新后的分类对象，如果更新失败则返回错误
-func (s *CategoryService) Update(id uuid.UUID, req *models.CategoryUpdate) (*models.Category, error) {
-	category, err := s.categoryRepo.GetByID(id)
+// 返回: 更新后的分类对象；父分类不存在、是自身或会形成引用环时返回 ErrInvalidParentCategory；
+// 改名后 slug 重试仍冲突时返回 ErrCategorySlugExists
+func (s *CategoryService) Update(ctx context.Context, id uuid.UUID, req *models.CategoryUpdate) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	renamed := req.Name != nil && *req.Name != category.Name
 	if req.Name != nil {
 		category.Name = *req.Name
-		category.Slug = GenerateSlug(*req.Name)
 	}
 
 	if req.Description != nil {
@@ -73,6 +139,9 @@ func (s *CategoryService) Update(id uuid.UUID, req *models.CategoryUpdate) (*mod
 	}
 
 	if req.ParentID != nil {
+		if err := s.validateParent(ctx, id, *req.ParentID); err != nil {
+			return nil, err
+		}
 		category.ParentID = req.ParentID
 	}
 
@@ -80,17 +149,225 @@ func (s *CategoryService) Update(id uuid.UUID, req *models.CategoryUpdate) (*mod
 		category.Order = *req.Order
 	}
 
-	if err := s.categoryRepo.Update(category); err != nil {
+	write := func(slug string) error {
+		category.Slug = slug
+		return s.categoryRepo.Update(ctx, category)
+	}
+	// 只有名称变化时才重新生成 slug，冲突时与创建一样追加数字后缀重试
+	if renamed {
+		_, err = WithUniqueSlug(GenerateSlug(category.Name), isCategorySlugConflict, write)
+	} else {
+		err = write(category.Slug)
+	}
+	if err != nil {
+		return nil, categorySlugError(err)
+	}
+	// 文章中内嵌了分类名称，列表中也有，一并清理
+	clearTaxonomyCaches()
+
+	return s.categoryRepo.GetByID(ctx, id)
+}
+
+// isCategorySlugConflict 判断是否为分类 slug 唯一索引冲突
+func isCategorySlugConflict(err error) bool {
+	return isUniqueViolation(err, "uniq_categories_slug_lower")
+}
+
+// categorySlugError 将 slug 重试用尽转换为 ErrCategorySlugExists，其他错误原样返回
+func categorySlugError(err error) error {
+	if errors.Is(err, ErrSlugRetriesExhausted) {
+		return fmt.Errorf("%w: %v", ErrCategorySlugExists, err)
+	}
+	return err
+}
+
+// validateParent 校验将 parentID 设为 id 的父分类是否合法
+func (s *CategoryService) validateParent(ctx context.Context, id, parentID uuid.UUID) error {
+	if parentID == id {
+		return fmt.Errorf("%w: a category cannot be its own parent", ErrInvalidParentCategory)
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, parentID); err != nil {
+		return fmt.Errorf("%w: parent category %s does not exist", ErrInvalidParentCategory, parentID)
+	}
+	descendant, err := s.categoryRepo.IsDescendant(ctx, id, parentID)
+	if err != nil {
+		return err
+	}
+	if descendant {
+		return fmt.Errorf("%w: parent category %s is a descendant of this category", ErrInvalidParentCategory, parentID)
+	}
+	return nil
+}
+
+// Tree 获取分类树（含每个节点的已发布文章数）
+// 返回: 根节点列表，同级节点按 order、名称排序
+// 注意: 复用公开分类列表的缓存
+func (s *CategoryService) Tree(ctx context.Context) ([]*models.CategoryNode, error) {
+	categories, err := s.List(ctx, models.TaxonomyListQuery{PublishedOnly: true})
+	if err != nil {
 		return nil, err
 	}
+	return BuildCategoryTree(categories), nil
+}
+
+// BuildCategoryTree 将扁平的分类列表组装为树
+// 父分类不在列表中（已删除）或指向自身的分类作为根节点；
+// 数据中存在引用环时（正常写入路径会拒绝，这里防御历史脏数据），环上排序最靠前的分类被提升为根节点以断开环
+func BuildCategoryTree(categories []*models.Category) []*models.CategoryNode {
+	nodes := make(map[uuid.UUID]*models.CategoryNode, len(categories))
+	all := make([]*models.CategoryNode, 0, len(categories))
+	for _, c := range categories {
+		node := &models.CategoryNode{Category: *c, Children: []*models.CategoryNode{}}
+		nodes[c.ID] = node
+		all = append(all, node)
+	}
+
+	children := make(map[uuid.UUID][]*models.CategoryNode)
+	roots := []*models.CategoryNode{}
+	for _, node := range all {
+		if node.ParentID != nil && *node.ParentID != node.ID {
+			if _, ok := nodes[*node.ParentID]; ok {
+				children[*node.ParentID] = append(children[*node.ParentID], node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
 
-	return s.categoryRepo.GetByID(id)
+	visited := make(map[uuid.UUID]bool, len(all))
+	var attach func(node *models.CategoryNode)
+	attach = func(node *models.CategoryNode) {
+		visited[node.ID] = true
+		for _, child := range children[node.ID] {
+			if visited[child.ID] {
+				continue
+			}
+			node.Children = append(node.Children, child)
+			attach(child)
+		}
+		sortCategoryNodes(node.Children)
+	}
+	for _, root := range roots {
+		attach(root)
+	}
+
+	// 从根节点无法到达的分类都处于引用环上（或挂在环下）
+	sortCategoryNodes(all)
+	for _, node := range all {
+		if visited[node.ID] {
+			continue
+		}
+		l := logger.GetLogger()
+		l.Warn().Str("category_id", node.ID.String()).Msg("Category parent cycle detected, promoted to root")
+		roots = append(roots, node)
+		attach(node)
+	}
+
+	sortCategoryNodes(roots)
+	return roots
 }
 
-// Delete 删除分类（软删除）
+func sortCategoryNodes(nodes []*models.CategoryNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Order != nodes[j].Order {
+			return nodes[i].Order < nodes[j].Order
+		}
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].ID.String() < nodes[j].ID.String()
+	})
+}
+
+// Delete 删除分类（软删除，可在回收站中恢复）
 // id: 分类UUID
-// 返回: 如果删除失败则返回错误
-func (s *CategoryService) Delete(id uuid.UUID) error {
-	return s.categoryRepo.Delete(id)
+// reassignTo: 目标分类UUID，非空时先把该分类下的全部文章和直接子分类移动到目标分类再删除
+// 返回: 未指定 reassignTo 且仍有文章或子分类引用时返回 *CategoryInUseError；目标分类不合法时返回 ErrInvalidReassignTarget
+// 注意: 移动文章（递增文章版本号）、移动子分类和删除分类在同一事务中完成；提交后清理受影响文章的详情缓存，并异步重建其搜索索引
+func (s *CategoryService) Delete(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if reassignTo == nil {
+		articles, err := s.categoryRepo.CountArticles(ctx, id)
+		if err != nil {
+			return err
+		}
+		children, err := s.categoryRepo.CountChildren(ctx, id)
+		if err != nil {
+			return err
+		}
+		if articles > 0 || children > 0 {
+			return &CategoryInUseError{Articles: articles, Children: children}
+		}
+		if err := s.categoryRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		clearTaxonomyCaches()
+		return nil
+	}
+
+	if *reassignTo == id {
+		return ErrInvalidReassignTarget
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, *reassignTo); err != nil {
+		return ErrInvalidReassignTarget
+	}
+	// 目标是被删除分类的子孙时，子分类挂过去会形成环
+	descendant, err := s.categoryRepo.IsDescendant(ctx, id, *reassignTo)
+	if err != nil {
+		return err
+	}
+	if descendant {
+		return ErrInvalidReassignTarget
+	}
+
+	var articleIDs []uuid.UUID
+	err = database.WithTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		if articleIDs, err = s.categoryRepo.ArticleIDsTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.categoryRepo.ReassignArticlesTx(tx, id, *reassignTo); err != nil {
+			return err
+		}
+		if _, err := s.categoryRepo.ReassignChildrenTx(tx, id, *reassignTo); err != nil {
+			return err
+		}
+		return s.categoryRepo.DeleteTx(tx, id)
+	})
+	if err != nil {
+		return err
+	}
+	// 文章详情中内嵌了分类，索引文档中带有分类 ID，转移后一并更新
+	deleteArticleDetailCaches(articleIDs)
+	clearTaxonomyCaches()
+	reindexArticles(ctx, s.articleRepo, articleIDs)
+	return nil
 }
 
+// Autocomplete 编辑器分类自动补全：按名称子串匹配（大小写不敏感），按使用量排序
+// q: 关键词；limit: 返回条数（默认 10，最大 50）
+func (s *CategoryService) Autocomplete(ctx context.Context, q string, limit int) ([]*models.TaxonomySuggestion, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return []*models.TaxonomySuggestion{}, nil
+	}
+	suggestions, err := s.categoryRepo.Autocomplete(ctx, q, normalizeAutocompleteLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []*models.TaxonomySuggestion{}
+	}
+	return suggestions, nil
+}
+
+// Stats 获取按分类统计的内容数据（已发布文章数、总浏览量、总评论数、最近发布时间）
+// query: 排序参数，非法值回退为按已发布文章数倒序
+// 返回: 统计列表，如果查询失败则返回错误
+// 注意: 结果会在 Redis 中短暂缓存
+func (s *CategoryService) Stats(ctx context.Context, query models.ContentStatsQuery) ([]*models.ContentStats, error) {
+	return loadContentStats(ctx, "categories", query, s.categoryRepo.Stats)
+}
@@ -2,10 +2,16 @@
 package services
 
 import (
+	"context"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/metrics"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // CommentService 评论服务，提供评论相关的业务逻辑
@@ -32,10 +38,11 @@ func NewCommentService(
 // ip: 评论者IP地址，用于记录
 // req: 评论创建请求，包含文章ID、内容、作者信息等
 // 返回: 创建成功的评论对象，如果创建失败则返回错误
-// 注意: 新评论默认状态为pending（待审核），会异步更新文章评论数
-func (s *CommentService) Create(userID *uuid.UUID, ip string, req *models.CommentCreate) (*models.Comment, error) {
+// 注意: 新评论默认状态为pending（待审核，关闭评论审核时直接为approved），会在同一事务中更新文章评论数；
+// 内容和昵称会经过敏感词过滤，命中时按策略返回 *ContentRejectedError、替换或保持待审核
+func (s *CommentService) Create(ctx context.Context, userID *uuid.UUID, ip string, req *models.CommentCreate) (*models.Comment, error) {
 	// 验证文章是否存在
-	_, err := s.articleRepo.GetByID(req.ArticleID)
+	_, err := s.articleRepo.GetByID(ctx, req.ArticleID)
 	if err != nil {
 		return nil, err
 	}
@@ -49,17 +56,48 @@ func (s *CommentService) Create(userID *uuid.UUID, ip string, req *models.Commen
 		Email:     req.Email,
 		Website:   req.Website,
 		IP:        ip,
-		Status:    "pending", // 默认待审核
+		Status:    models.CommentStatusPending, // 默认待审核
+	}
+
+	review, err := filterContent(ctx, "comment", &comment.Content, &comment.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	// 关闭评论审核时直接通过（命中敏感词且策略为 review 时仍需审核）
+	if !settingBool(models.SettingCommentModeration) && !review {
+		comment.Status = models.CommentStatusApproved
 	}
 
-	if err := s.commentRepo.Create(comment); err != nil {
+	// 插入评论和更新文章评论数在同一事务中完成
+	err = database.WithTx(ctx, func(tx *gorm.DB) error {
+		if err := s.commentRepo.CreateTx(tx, comment); err != nil {
+			return err
+		}
+		return s.commentRepo.IncrementCommentCountTx(tx, req.ArticleID)
+	})
+	if err != nil {
 		return nil, err
 	}
+	metrics.RecordCommentCreation()
+	clearArticleCommentsListCache()
 
-	// 更新文章评论数
-	go s.commentRepo.IncrementCommentCount(req.ArticleID)
+	created, err := s.commentRepo.GetByID(ctx, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	emitWebhookEvent(ctx, models.WebhookEventCommentCreated, newWebhookComment(created))
+	if created.Status == models.CommentStatusApproved {
+		notifyCommentApproved(ctx, created)
+	}
+	return created, nil
+}
 
-	return s.commentRepo.GetByID(comment.ID)
+// GetByID 根据ID获取单条评论
+// id: 评论UUID
+// 返回: 评论对象（不含回复列表），不存在或已删除时返回 repository.ErrCommentNotFound
+func (s *CommentService) GetByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	return s.commentRepo.GetByID(ctx, id)
 }
 
 // GetByArticleID 获取指定文章下的评论列表（分页）
@@ -67,26 +105,31 @@ func (s *CommentService) Create(userID *uuid.UUID, ip string, req *models.Commen
 // page: 页码，从1开始
 // pageSize: 每页数量，默认20
 // 返回: 评论列表、总数，如果查询失败则返回错误
-// 注意: 只返回父评论（parent_id为NULL的评论）
-func (s *CommentService) GetByArticleID(articleID uuid.UUID, page, pageSize int) ([]*models.Comment, int64, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if pageSize <= 0 {
-		pageSize = 20
-	}
-	return s.commentRepo.GetByArticleID(articleID, page, pageSize)
+// 注意: 分页只针对父评论（parent_id为NULL的评论），每条父评论的回复在 Replies 中一并返回
+func (s *CommentService) GetByArticleID(ctx context.Context, articleID uuid.UUID, page, pageSize int) ([]*models.Comment, int64, error) {
+	page, pageSize = config.NormalizePage(config.PageComments, page, pageSize)
+	return s.commentRepo.GetByArticleID(ctx, articleID, page, pageSize)
+}
+
+// List 管理后台按条件获取评论列表（分页，支持按状态筛选，如待审核、被举报的评论）
+// query: 评论查询条件
+// 返回: 评论列表、总数，如果查询失败则返回错误
+func (s *CommentService) List(ctx context.Context, query models.CommentQuery) ([]*models.Comment, int64, error) {
+	query.Page, query.PageSize = config.NormalizePage(config.PageComments, query.Page, query.PageSize)
+	return s.commentRepo.List(ctx, query)
 }
 
 // Update 更新评论信息
 // id: 评论UUID
 // req: 评论更新请求，包含可选的内容和状态
 // 返回: 更新后的评论对象，如果更新失败则返回错误
-func (s *CommentService) Update(id uuid.UUID, req *models.CommentUpdate) (*models.Comment, error) {
-	comment, err := s.commentRepo.GetByID(id)
+// 注意: 状态改为 approved 时发出 comment.approved 事件，并通知文章作者和被回复的评论作者
+func (s *CommentService) Update(ctx context.Context, id uuid.UUID, req *models.CommentUpdate) (*models.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	wasApproved := comment.Status == models.CommentStatusApproved
 
 	if req.Content != nil {
 		comment.Content = *req.Content
@@ -96,17 +139,30 @@ func (s *CommentService) Update(id uuid.UUID, req *models.CommentUpdate) (*model
 		comment.Status = *req.Status
 	}
 
-	if err := s.commentRepo.Update(comment); err != nil {
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
 		return nil, err
 	}
+	clearArticleCommentsListCache()
 
-	return s.commentRepo.GetByID(id)
+	updated, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !wasApproved && updated.Status == models.CommentStatusApproved {
+		emitWebhookEvent(ctx, models.WebhookEventCommentApproved, newWebhookComment(updated))
+		notifyCommentApproved(ctx, updated)
+	}
+	return updated, nil
 }
 
 // Delete 删除评论（硬删除）
 // id: 评论UUID
 // 返回: 如果删除失败则返回错误
-func (s *CommentService) Delete(id uuid.UUID) error {
-	return s.commentRepo.Delete(id)
+func (s *CommentService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.commentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	clearArticleCommentsListCache()
+	return nil
 }
 
@@ -0,0 +1,237 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/ahocorasick"
+	"enterprise-blog/pkg/logger"
+)
+
+// ErrContentRejected 内容命中敏感词且处理方式为 reject
+var ErrContentRejected = errors.New("content contains sensitive words")
+
+// defaultWordCategory 词表中未写分类的词归入的分类
+const defaultWordCategory = "default"
+
+// ContentRejectedError 列出命中的敏感词分类（不含具体词），errors.Is(err, ErrContentRejected) 为 true
+type ContentRejectedError struct {
+	Categories []string
+}
+
+func (e *ContentRejectedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrContentRejected, strings.Join(e.Categories, ", "))
+}
+
+func (e *ContentRejectedError) Is(target error) bool {
+	return target == ErrContentRejected
+}
+
+// defaultContentFilter 当前进程使用的敏感词过滤器，由 NewContentFilter 注册
+// 未注册时（例如单元测试）不过滤
+var defaultContentFilter atomic.Pointer[ContentFilter]
+
+// ContentFilter 用户提交内容（文章、评论）的敏感词过滤
+//
+// 设计考虑：
+// - 词表来自敏感词文件（content_filter.words_file）和 settings 表中的 content_filter_words，两者合并
+// - 用 Aho-Corasick 自动机一次扫描匹配全部词，忽略大小写；词表变化后在下次过滤时重新构建
+// - 命中后按 content_filter_policy 拒绝、替换为 * 或转入人工审核
+// - 日志只记录命中的分类，不记录具体的词和内容
+type ContentFilter struct {
+	mu        sync.Mutex
+	fileWords string
+	matcher   *wordMatcher
+}
+
+// wordMatcher 由一份词表构建的自动机
+type wordMatcher struct {
+	source     string
+	automaton  *ahocorasick.Automaton
+	categories []string // 与模式串下标一一对应
+}
+
+// NewContentFilter 创建新的敏感词过滤器，并注册为当前进程的过滤器
+// 注意: 创建后只有 settings 中的词表生效，敏感词文件通过 LoadFile 加载
+func NewContentFilter() *ContentFilter {
+	f := &ContentFilter{}
+	defaultContentFilter.Store(f)
+	return f
+}
+
+// LoadFile 重新读取敏感词文件
+// path: 文件路径，为空表示不使用文件
+// 返回: 文件不可读或格式错误时返回错误，此时继续使用原来的词表
+// 注意: 启动时和配置重新加载后调用，修改文件内容后重新加载配置即可生效
+func (f *ContentFilter) LoadFile(path string) error {
+	var words string
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read content filter words: %w", err)
+		}
+		words = string(data)
+		if err := validateWordList(words); err != nil {
+			return fmt.Errorf("invalid content filter words file %s: %w", path, err)
+		}
+	}
+
+	f.mu.Lock()
+	f.fileWords = words
+	f.mu.Unlock()
+	return nil
+}
+
+// Check 检查并按处理方式处理内容
+// kind: 内容类型（article / comment），只用于日志
+// fields: 需要检查的字段，策略为 replace 时原地替换命中的词
+// 返回: 策略为 review 且有命中时 review 为 true；策略为 reject 且有命中时返回 *ContentRejectedError
+func (f *ContentFilter) Check(ctx context.Context, kind string, fields ...*string) (review bool, err error) {
+	m := f.currentMatcher()
+	if m == nil {
+		return false, nil
+	}
+
+	hit := make(map[string]bool)
+	ranges := make([][]ahocorasick.Match, len(fields))
+	for i, field := range fields {
+		if *field == "" {
+			continue
+		}
+		ranges[i] = m.automaton.FindAll(foldRunes(*field))
+		for _, match := range ranges[i] {
+			hit[m.categories[match.Pattern]] = true
+		}
+	}
+	if len(hit) == 0 {
+		return false, nil
+	}
+
+	categories := make([]string, 0, len(hit))
+	for category := range hit {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	policy := settingString(models.SettingContentFilterPolicy)
+	l := logger.FromContext(ctx)
+	l.Info().Str("kind", kind).Strs("categories", categories).Str("policy", policy).Msg("content matched sensitive words")
+
+	switch policy {
+	case models.ContentFilterReplace:
+		for i, field := range fields {
+			if len(ranges[i]) > 0 {
+				*field = maskRanges(*field, ranges[i])
+			}
+		}
+		return false, nil
+	case models.ContentFilterReview:
+		return true, nil
+	default:
+		return false, &ContentRejectedError{Categories: categories}
+	}
+}
+
+// currentMatcher 返回当前词表对应的自动机，词表变化时重新构建；词表为空时返回 nil
+func (f *ContentFilter) currentMatcher() *wordMatcher {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	source := f.fileWords + "\n" + settingString(models.SettingContentFilterWords)
+	if f.matcher == nil || f.matcher.source != source {
+		f.matcher = buildWordMatcher(source)
+	}
+	if len(f.matcher.categories) == 0 {
+		return nil
+	}
+	return f.matcher
+}
+
+func buildWordMatcher(source string) *wordMatcher {
+	m := &wordMatcher{source: source}
+	var patterns []string
+	for _, entry := range parseWordList(source) {
+		patterns = append(patterns, string(foldRunes(entry.word)))
+		m.categories = append(m.categories, entry.category)
+	}
+	m.automaton = ahocorasick.New(patterns)
+	return m
+}
+
+type wordEntry struct {
+	category string
+	word     string
+}
+
+// parseWordList 解析词表：每行“分类: 词1, 词2”，没有分类的行归入 default，# 开头的行和空行忽略
+func parseWordList(text string) []wordEntry {
+	var entries []wordEntry
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		category := defaultWordCategory
+		if i := strings.IndexAny(line, ":："); i >= 0 {
+			category = strings.TrimSpace(line[:i])
+			line = strings.TrimLeft(line[i:], ":：")
+		}
+		for _, word := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == '，' }) {
+			if word = strings.TrimSpace(word); word != "" {
+				entries = append(entries, wordEntry{category: category, word: word})
+			}
+		}
+	}
+	return entries
+}
+
+// validateWordList 校验词表格式：分类名不能为空
+func validateWordList(text string) error {
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.IndexAny(line, ":："); i >= 0 && strings.TrimSpace(line[:i]) == "" {
+			return fmt.Errorf("line %d: empty category", n+1)
+		}
+	}
+	return nil
+}
+
+// foldRunes 转为小写的 rune 序列，与原文逐 rune 对应，便于按位置替换
+func foldRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// maskRanges 将命中区间内的字符替换为 *
+func maskRanges(s string, matches []ahocorasick.Match) string {
+	runes := []rune(s)
+	for _, match := range matches {
+		for i := match.Start; i < match.End; i++ {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
+
+// filterContent 通过当前进程的敏感词过滤器检查内容，未注册时不过滤，见 ContentFilter.Check
+func filterContent(ctx context.Context, kind string, fields ...*string) (bool, error) {
+	if f := defaultContentFilter.Load(); f != nil {
+		return f.Check(ctx, kind, fields...)
+	}
+	return false, nil
+}
@@ -0,0 +1,308 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidTopKind 排行榜类型不合法
+	ErrInvalidTopKind = errors.New("invalid kind, must be one of articles_views, articles_comments, authors")
+	// ErrInvalidTopPeriod 排行榜统计周期不合法
+	ErrInvalidTopPeriod = errors.New("invalid period, must be one of 7d, 30d")
+)
+
+const (
+	defaultDashboardTopLimit = 10
+	maxDashboardTopLimit     = 50
+	dashboardTopCacheTTL     = 60 * time.Second
+	dashboardOverviewTTL     = 30 * time.Second
+	contentStatsCacheTTL     = 120 * time.Second
+	weeklyReportTopLimit     = 5
+	// reviewStatsDays 审核耗时统计窗口（按审核通过时间）
+	reviewStatsDays = 30
+	// reviewSubmissionLookbackDays 窗口开始前向前查找提交审核记录的天数
+	reviewSubmissionLookbackDays = 30
+)
+
+// dashboardPeriods 支持的统计周期
+var dashboardPeriods = map[string]int{
+	"7d":  7,
+	"30d": 30,
+}
+
+// DashboardService 仪表盘服务，提供后台统计相关的业务逻辑
+type DashboardService struct {
+	statsRepo *repository.StatsRepository
+}
+
+// NewDashboardService 创建新的仪表盘服务实例
+// statsRepo: 统计数据访问层仓库
+func NewDashboardService(statsRepo *repository.StatsRepository) *DashboardService {
+	return &DashboardService{
+		statsRepo: statsRepo,
+	}
+}
+
+// Overview 获取仪表盘核心统计（含待审核评论、被举报评论、待审核文章、定时发布文章等待处理队列）
+// 返回: 仪表盘统计数据，如果查询失败则返回错误
+// 注意: 所有计数在同一次聚合中计算，结果会在 Redis 中短暂缓存
+func (s *DashboardService) Overview(ctx context.Context) (*models.AdminDashboardData, error) {
+	var data *models.AdminDashboardData
+	if err := getDashboardCache(redisDashboardOverviewKey, &data); err != nil || data == nil {
+		data, err = s.statsRepo.DashboardCounts(ctx, config.StartOfDay(time.Now()))
+		if err != nil {
+			return nil, err
+		}
+		if err := s.fillReviewDuration(ctx, data, time.Now()); err != nil {
+			return nil, err
+		}
+		_ = setDashboardCache(redisDashboardOverviewKey, data, dashboardOverviewTTL)
+	}
+
+	data.Links = dashboardQueueLinks()
+	return data, nil
+}
+
+// fillReviewDuration 根据状态变更历史计算近 30 天文章从提交审核到审核通过的中位时长
+// 注意: 每次审核通过与此前最近一次提交审核配对；多次提交时只计最后一轮
+func (s *DashboardService) fillReviewDuration(ctx context.Context, data *models.AdminDashboardData, now time.Time) error {
+	windowStart := now.AddDate(0, 0, -reviewStatsDays)
+	changes, err := s.statsRepo.ReviewTransitions(ctx, windowStart.AddDate(0, 0, -reviewSubmissionLookbackDays))
+	if err != nil {
+		return err
+	}
+
+	var durations []int64
+	var submittedAt *time.Time
+	var current uuid.UUID
+	for _, c := range changes {
+		if c.ArticleID != current {
+			current, submittedAt = c.ArticleID, nil
+		}
+		switch {
+		case c.ToStatus == models.StatusReview:
+			t := c.CreatedAt
+			submittedAt = &t
+		case c.ToStatus == models.StatusPublished || c.ToStatus == models.StatusScheduled:
+			if submittedAt != nil && !c.CreatedAt.Before(windowStart) {
+				durations = append(durations, int64(c.CreatedAt.Sub(*submittedAt)/time.Second))
+			}
+			submittedAt = nil
+		default:
+			// 退回草稿或归档，本轮审核结束
+			submittedAt = nil
+		}
+	}
+
+	data.ReviewSampleSize = len(durations)
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	median := durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		median = (durations[len(durations)/2-1] + median) / 2
+	}
+	data.MedianReviewSeconds = &median
+	return nil
+}
+
+// dashboardQueueLinks 待处理队列到后台筛选列表的映射
+func dashboardQueueLinks() map[string]models.DashboardQueueLink {
+	return map[string]models.DashboardQueueLink{
+		"pending_comments": {
+			Resource: "comments",
+			Status:   models.CommentStatusPending,
+			Path:     "/api/v1/admin/comments?status=" + models.CommentStatusPending,
+		},
+		"reported_comments": {
+			Resource: "comments",
+			Status:   models.CommentStatusReported,
+			Path:     "/api/v1/admin/comments?status=" + models.CommentStatusReported,
+		},
+		"review_articles": {
+			Resource: "articles",
+			Status:   string(models.StatusReview),
+			Path:     "/api/v1/admin/articles?status=" + string(models.StatusReview),
+		},
+		"scheduled_articles": {
+			Resource: "articles",
+			Status:   string(models.StatusScheduled),
+			Path:     "/api/v1/admin/articles?status=" + string(models.StatusScheduled),
+		},
+	}
+}
+
+// Top 获取仪表盘排行榜
+// kind: 排行类型（articles_views / articles_comments / authors）
+// period: 统计周期（7d / 30d，默认 7d）
+// limit: 返回条数（默认 10，最大 50）
+// 返回: 排行榜结果，如果参数不合法或查询失败则返回错误
+// 注意: 每种组合的结果会在 Redis 中短暂缓存
+func (s *DashboardService) Top(ctx context.Context, kind models.DashboardTopKind, period string, limit int) (*models.DashboardTopResult, error) {
+	if period == "" {
+		period = "7d"
+	}
+	days, ok := dashboardPeriods[period]
+	if !ok {
+		return nil, ErrInvalidTopPeriod
+	}
+	if limit <= 0 {
+		limit = defaultDashboardTopLimit
+	}
+	if limit > maxDashboardTopLimit {
+		limit = maxDashboardTopLimit
+	}
+
+	var fetch func(context.Context, time.Time, time.Time, int) ([]*models.DashboardTopItem, error)
+	switch kind {
+	case models.TopArticlesByViews:
+		fetch = s.statsRepo.TopArticlesByViews
+	case models.TopArticlesByComments:
+		fetch = s.statsRepo.TopArticlesByComments
+	case models.TopAuthors:
+		fetch = s.statsRepo.TopAuthors
+	default:
+		return nil, ErrInvalidTopKind
+	}
+
+	cacheKey := fmt.Sprintf("%s%s:%s:%d", redisDashboardTopPrefix, kind, period, limit)
+	var cached *models.DashboardTopResult
+	if err := getDashboardCache(cacheKey, &cached); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	// 统计窗口从 N 天前的零点（业务时区）开始（含今天）
+	today := config.StartOfDay(time.Now())
+	since := today.AddDate(0, 0, -(days - 1))
+
+	items, err := fetch(ctx, since, today.AddDate(0, 0, 1), limit)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []*models.DashboardTopItem{}
+	}
+
+	result := &models.DashboardTopResult{
+		Kind:   kind,
+		Period: period,
+		Since:  since,
+		Items:  items,
+	}
+	_ = setDashboardCache(cacheKey, result, dashboardTopCacheTTL)
+
+	return result, nil
+}
+
+// WeeklyReport 汇总时间窗口内的新增用户、发布文章、浏览量、浏览量前 5 的文章以及当前待处理积压
+// from: 起始时间（含）
+// to: 截止时间（不含）
+// 注意: 不使用缓存，积压数据为生成报表时的实时值
+func (s *DashboardService) WeeklyReport(ctx context.Context, from, to time.Time) (*models.WeeklyReport, error) {
+	counts, err := s.statsRepo.PeriodCounts(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := s.statsRepo.TopArticlesByViews(ctx, from, to, weeklyReportTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	if top == nil {
+		top = []*models.DashboardTopItem{}
+	}
+
+	backlog, err := s.statsRepo.DashboardCounts(ctx, config.StartOfDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WeeklyReport{
+		From:             from,
+		To:               to,
+		Counts:           *counts,
+		TopArticles:      top,
+		PendingComments:  backlog.PendingComments,
+		ReportedComments: backlog.ReportedComments,
+		ReviewArticles:   backlog.ReviewArticles,
+	}, nil
+}
+
+// loadContentStats 读取分类 / 标签内容统计，结果按排序参数短暂缓存（聚合查询开销较大）
+func loadContentStats(
+	ctx context.Context,
+	kind string,
+	query models.ContentStatsQuery,
+	fetch func(context.Context, models.ContentStatsQuery) ([]*models.ContentStats, error),
+) ([]*models.ContentStats, error) {
+	query = repository.NormalizeContentStatsQuery(query)
+	cacheKey := fmt.Sprintf("%s%s:%s:%s", redisContentStatsPrefix, kind, query.SortBy, query.Order)
+
+	var cached []*models.ContentStats
+	if err := getDashboardCache(cacheKey, &cached); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	stats, err := fetch(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []*models.ContentStats{}
+	}
+	_ = setDashboardCache(cacheKey, stats, contentStatsCacheTTL)
+	return stats, nil
+}
+
+// dashboardCacheMetric 按键前缀区分 getDashboardCache 读取的缓存类型
+func dashboardCacheMetric(key string) string {
+	switch {
+	case strings.HasPrefix(key, redisTaxonomyListPrefix):
+		return cacheMetricTaxonomyList
+	case strings.HasPrefix(key, redisContentStatsPrefix):
+		return cacheMetricContentStats
+	}
+	return cacheMetricDashboard
+}
+
+func getDashboardCache(key string, dest interface{}) error {
+	if database.RedisClient == nil {
+		return fmt.Errorf("redis not initialized")
+	}
+	ctx, cancel := redisReadContext(context.Background())
+	defer cancel()
+	val, err := database.RedisClient.Get(ctx, key).Bytes()
+	recordCacheLookup(dashboardCacheMetric(key), err)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(val, dest)
+}
+
+func setDashboardCache(key string, value interface{}, ttl time.Duration) error {
+	if database.RedisClient == nil {
+		return nil
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return database.RedisClient.Set(ctx, key, data, ttl).Err()
+}
@@ -0,0 +1,113 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/pkg/logger"
+)
+
+// EmailMessage 待发送的邮件
+type EmailMessage struct {
+	To      []string
+	Subject string
+	HTML    string
+	// Headers 额外的邮件头（如 List-Unsubscribe）
+	Headers map[string]string
+}
+
+// EmailSender 邮件发送抽象，便于切换服务商或在测试中替换
+type EmailSender interface {
+	Send(ctx context.Context, msg *EmailMessage) error
+}
+
+// NewEmailSender 根据配置创建邮件发送器
+// 注意: 未配置 SMTP_HOST 时返回只写日志的发送器（开发 / 测试环境）
+func NewEmailSender(cfg config.EmailConfig) EmailSender {
+	if cfg.SMTPHost == "" {
+		return &LogEmailSender{}
+	}
+	return &SMTPEmailSender{cfg: cfg}
+}
+
+// SMTPEmailSender 通过 SMTP 发送邮件（支持 STARTTLS 和 PLAIN 认证）
+type SMTPEmailSender struct {
+	cfg config.EmailConfig
+}
+
+// Send 发送 HTML 邮件
+func (s *SMTPEmailSender) Send(ctx context.Context, msg *EmailMessage) error {
+	if len(msg.To) == 0 {
+		return errors.New("email has no recipients")
+	}
+
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.SMTPHost)
+	}
+
+	// net/smtp 不支持 context，放到 goroutine 中执行以便超时返回
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, s.cfg.From, msg.To, buildMIMEMessage(s.cfg.From, msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsPermanentEmailError 判断发信错误是否为永久性失败（SMTP 5xx，如收件人不存在），此类地址不应再次发送
+func IsPermanentEmailError(err error) bool {
+	var smtpErr *textproto.Error
+	return errors.As(err, &smtpErr) && smtpErr.Code >= 500 && smtpErr.Code < 600
+}
+
+// LogEmailSender 只在日志中输出邮件摘要，不真正发送
+type LogEmailSender struct{}
+
+// Send 记录邮件收件人和主题
+func (s *LogEmailSender) Send(ctx context.Context, msg *EmailMessage) error {
+	l := logger.FromContext(ctx)
+	l.Info().
+		Strs("to", msg.To).
+		Str("subject", msg.Subject).
+		Int("html_size", len(msg.HTML)).
+		Msg("Email not sent (SMTP not configured)")
+	return nil
+}
+
+func buildMIMEMessage(from string, msg *EmailMessage) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	headers := make([]string, 0, len(msg.Headers))
+	for name := range msg.Headers {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+	for _, name := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, msg.Headers[name])
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.HTML)
+	return []byte(b.String())
+}
@@ -0,0 +1,191 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+)
+
+const (
+	// emailVerificationTTL 验证链接有效期
+	emailVerificationTTL = 24 * time.Hour
+	// emailVerificationInterval 同一用户两次发送验证邮件的最小间隔（防刷，与短信验证码相同）
+	emailVerificationInterval = time.Minute
+	// emailVerificationSendTimeout 验证邮件的发送超时时间
+	emailVerificationSendTimeout = 30 * time.Second
+)
+
+var (
+	// ErrInvalidVerificationToken 邮箱验证令牌无效、已使用或已过期
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	// ErrVerificationTooFrequent 重新发送验证邮件过于频繁
+	ErrVerificationTooFrequent = errors.New("verification email requested too frequently")
+)
+
+// EmailVerificationService 注册邮箱验证：注册后账号为 pending，点击验证邮件中的链接后改为 active
+//
+// 设计考虑：
+// - 令牌为 32 字节随机数，只保存 SHA-256 哈希，24 小时有效，只能使用一次
+// - 重新发送时作废之前未使用的令牌，同一用户 1 分钟内只能发送一次
+// - 重新发送接口对不存在、已验证的邮箱同样返回成功，避免通过接口探测邮箱是否注册
+type EmailVerificationService struct {
+	verificationRepo *repository.EmailVerificationRepository
+	userRepo         *repository.UserRepository
+	sender           EmailSender
+	frontendURL      string
+
+	inflight sync.WaitGroup
+}
+
+// NewEmailVerificationService 创建新的邮箱验证服务实例
+// verificationRepo: 验证令牌数据访问层仓库
+// userRepo: 用户数据访问层仓库
+// sender: 邮件发送器
+// server: 提供验证链接使用的前端地址（前端 /verify-email 页面调用验证接口）
+func NewEmailVerificationService(verificationRepo *repository.EmailVerificationRepository, userRepo *repository.UserRepository, sender EmailSender, server config.ServerConfig) *EmailVerificationService {
+	return &EmailVerificationService{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		sender:           sender,
+		frontendURL:      strings.TrimRight(server.FrontendURL, "/"),
+	}
+}
+
+// Issue 为用户生成新的验证令牌并发送验证邮件（后台发送），之前未使用的令牌作废
+func (s *EmailVerificationService) Issue(ctx context.Context, user *models.User) error {
+	token, tokenHash, err := generateConfirmToken()
+	if err != nil {
+		return err
+	}
+	if err := s.verificationRepo.InvalidateUnused(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to invalidate old verification tokens: %w", err)
+	}
+	verification := &models.EmailVerification{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		return fmt.Errorf("failed to save verification token: %w", err)
+	}
+
+	html, err := renderNewsletterTemplate(verificationEmailTemplate, map[string]string{
+		"Username":  user.Username,
+		"VerifyURL": s.frontendURL + "/verify-email?token=" + token,
+		"ExpiresIn": emailVerificationTTL.String(),
+	})
+	if err != nil {
+		return err
+	}
+	msg := &EmailMessage{To: []string{user.Email}, Subject: "请验证您的邮箱", HTML: html}
+
+	ctx = context.WithoutCancel(ctx)
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		sendCtx, cancel := context.WithTimeout(ctx, emailVerificationSendTimeout)
+		defer cancel()
+		if err := s.sender.Send(sendCtx, msg); err != nil {
+			l := logger.FromContext(ctx, "email_verification")
+			l.Warn().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send verification email")
+		}
+	}()
+	return nil
+}
+
+// Verify 使用验证令牌激活账号
+// 返回: 验证的用户；令牌不存在、已使用或已过期时返回 ErrInvalidVerificationToken
+// 注意: 只有 pending 状态的用户会被激活，已被管理员禁用的账号不会因验证邮箱而恢复
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+	verification, err := s.verificationRepo.GetByTokenHash(ctx, hashConfirmToken(token))
+	if errors.Is(err, repository.ErrEmailVerificationNotFound) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+	// 并发使用同一令牌时只有一个请求成功
+	marked, err := s.verificationRepo.MarkUsed(ctx, verification.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	if _, err := s.userRepo.Activate(ctx, verification.UserID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, verification.UserID)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// Resend 重新发送验证邮件
+// email: 注册邮箱
+// 返回: 同一用户 1 分钟内重复请求时返回 ErrVerificationTooFrequent；邮箱不存在或已验证时不发送，同样返回 nil
+func (s *EmailVerificationService) Resend(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Status != "pending" {
+		return nil
+	}
+
+	count, err := s.verificationRepo.CountSince(ctx, user.ID, time.Now().Add(-emailVerificationInterval))
+	if err != nil {
+		return fmt.Errorf("failed to check recent verification emails: %w", err)
+	}
+	if count >= 1 {
+		return ErrVerificationTooFrequent
+	}
+	return s.Issue(ctx, user)
+}
+
+// Wait 等待后台发送中的验证邮件完成（关闭服务和测试时使用）
+func (s *EmailVerificationService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var verificationEmailTemplate = template.Must(template.New("verify").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+<p>{{.Username}}，您好，感谢注册。</p>
+<p><a href="{{.VerifyURL}}">点击此处验证邮箱并激活账号</a>（{{.ExpiresIn}} 内有效）</p>
+<p style="color: #999;">如果这不是您本人的操作，请忽略此邮件。</p>
+</body>
+</html>
+`))
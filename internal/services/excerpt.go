@@ -0,0 +1,91 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// excerptEllipsis 摘要被截断时追加的省略号
+const excerptEllipsis = "..."
+
+var (
+	excerptFencePattern    = regexp.MustCompile("(?m)^\\s*(```|~~~)")
+	excerptHTMLTagPattern  = regexp.MustCompile(`<[^>]*>`)
+	excerptImagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	excerptLinkPattern     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	excerptBlockPattern    = regexp.MustCompile(`^\s*(#{1,6}\s+|>\s?|[-*+]\s+|\d+[.)]\s+)`)
+	excerptRulePattern     = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	excerptStrongPattern   = regexp.MustCompile(`(\*\*|__|~~)([^*_~\s](?:[^*_~]*[^*_~\s])?)(\*\*|__|~~)`)
+	excerptEmphasisPattern = regexp.MustCompile(`(^|[^\p{L}\p{N}])[*_]([^*_\s](?:[^*_]*[^*_\s])?)[*_]`)
+)
+
+// GenerateExcerpt 根据正文生成纯文本摘要
+// content: 文章正文（Markdown，可能夹杂 HTML）
+// maxRunes: 摘要最多包含的字符数（按 rune 计，不含省略号），<= 0 表示不截断
+// 返回: 去掉 Markdown / HTML 标记并合并空白后的文本；超出长度时截断并追加 "..."
+// 注意: 按字符截断，不会切开多字节字符；截断位置落在英文等以空格分词的单词中间时回退到上一个空格，
+// 中日韩文字没有空格分词，直接在字符边界截断
+func GenerateExcerpt(content string, maxRunes int) string {
+	text := stripMarkup(content)
+	if maxRunes <= 0 {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	cut := maxRunes
+	if isSpacedWordRune(runes[cut-1]) && isSpacedWordRune(runes[cut]) {
+		// 回退到单词开头；整段都是一个词（如很长的链接）时仍按字符截断
+		for i := cut - 1; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + excerptEllipsis
+}
+
+// stripMarkup 去掉 Markdown 和 HTML 标记，只保留可读文字
+// 围栏代码块、分隔线和图片整体去掉，链接保留文字，多余空白合并为一个空格；
+// 加粗 / 斜体只去掉成对的标记，敏感词过滤替换出的 *** 等不受影响
+func stripMarkup(content string) string {
+	var b strings.Builder
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if excerptFencePattern.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence || excerptRulePattern.MatchString(line) {
+			continue
+		}
+		line = excerptBlockPattern.ReplaceAllString(line, "")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	text := excerptHTMLTagPattern.ReplaceAllString(b.String(), " ")
+	text = excerptImagePattern.ReplaceAllString(text, "")
+	text = excerptLinkPattern.ReplaceAllString(text, "$1")
+	text = strings.ReplaceAll(text, "`", "")
+	text = excerptStrongPattern.ReplaceAllString(text, "$2")
+	text = excerptEmphasisPattern.ReplaceAllString(text, "$1$2")
+	text = html.UnescapeString(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isSpacedWordRune 判断字符是否属于以空格分词的单词（字母或数字，中日韩文字除外）
+func isSpacedWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
@@ -0,0 +1,412 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrExportJobNotFound 导出任务不存在或已过期
+	ErrExportJobNotFound = errors.New("export job not found")
+	// ErrExportJobNotReady 导出任务尚未完成
+	ErrExportJobNotReady = errors.New("export job not finished")
+	// ErrExportFileMissing 任务已完成，但导出目录中没有对应的文件（导出目录未在实例间共享，或文件已被清理）
+	ErrExportFileMissing = errors.New("export file not found in export directory")
+)
+
+const (
+	ExportKindUsers    = "users"
+	ExportKindArticles = "articles"
+
+	// exportMaxPageSize 分页读取数据库时每页最多的行数
+	exportMaxPageSize = 500
+	// exportJobTTL 任务状态在 Redis 中的保留时间，导出文件也按此时间清理
+	exportJobTTL = 24 * time.Hour
+)
+
+var (
+	// 仅导出必要字段，除用户名、邮箱外不包含手机号、IP 等个人信息
+	exportUserHeader    = []string{"id", "username", "email", "role", "status", "created_at"}
+	exportArticleHeader = []string{"id", "title", "slug", "status", "author_id", "author", "category",
+		"view_count", "like_count", "comment_count", "published_at", "created_at"}
+)
+
+// ExportService 数据导出服务，按页读取仓库数据并输出 CSV
+//
+// 设计考虑：
+// - 同步导出：直接流式写入 HTTP 响应，受 maxRows 限制
+// - 异步导出：数据量超过 maxRows 时在后台生成文件，完成后可下载
+// - 任务状态保存在 Redis 中（与索引重建任务相同），任意实例都可以查询
+// - 文件保存在导出目录，多实例部署时导出目录需要共享（如挂载同一个卷），否则只能从生成文件的实例下载
+// - 导出文件包含用户邮箱，任务记录过期后无法再下载，由 PruneExpiredFiles 定期删除
+type ExportService struct {
+	userRepo    *repository.UserRepository
+	articleRepo *repository.ArticleRepository
+	exportDir   string
+	maxRows     int
+}
+
+// NewExportService 创建新的导出服务实例
+// userRepo: 用户数据访问层仓库
+// articleRepo: 文章数据访问层仓库
+// exportDir: 异步导出文件保存目录
+// maxRows: 同步导出的最大行数
+func NewExportService(
+	userRepo *repository.UserRepository,
+	articleRepo *repository.ArticleRepository,
+	exportDir string,
+	maxRows int,
+) *ExportService {
+	if exportDir == "" {
+		exportDir = "./exports"
+	}
+	if maxRows <= 0 {
+		maxRows = 10000
+	}
+	os.MkdirAll(exportDir, 0755)
+	return &ExportService{
+		userRepo:    userRepo,
+		articleRepo: articleRepo,
+		exportDir:   exportDir,
+		maxRows:     maxRows,
+	}
+}
+
+// MaxRows 返回同步导出的最大行数
+func (s *ExportService) MaxRows() int {
+	return s.maxRows
+}
+
+// CountUsers 统计符合条件的用户数
+func (s *ExportService) CountUsers(ctx context.Context, query models.UserQuery) (int64, error) {
+	query.Page, query.PageSize = 1, 1
+	_, total, err := s.userRepo.ListByQuery(ctx, query)
+	return total, err
+}
+
+// CountArticles 统计符合条件的文章数
+func (s *ExportService) CountArticles(ctx context.Context, query models.ArticleQuery) (int64, error) {
+	query.Page, query.PageSize = 1, 1
+	_, total, err := s.articleRepo.List(ctx, query)
+	return total, err
+}
+
+// WriteUsersCSV 分页读取用户并写入 CSV
+// limit: 最多写入的行数，<= 0 表示不限制
+// 返回: 实际写入的数据行数
+func (s *ExportService) WriteUsersCSV(ctx context.Context, w io.Writer, query models.UserQuery, limit int64) (int64, error) {
+	pageSize := exportPageSize(config.PageUsers)
+	return writeCSVPages(w, exportUserHeader, limit, pageSize, func(page int) ([][]string, error) {
+		query.Page, query.PageSize = page, pageSize
+		users, _, err := s.userRepo.ListByQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([][]string, 0, len(users))
+		for _, u := range users {
+			rows = append(rows, []string{
+				u.ID.String(),
+				u.Username,
+				u.Email,
+				string(u.Role),
+				u.Status,
+				u.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		return rows, nil
+	})
+}
+
+// WriteArticlesCSV 分页读取文章并写入 CSV
+// limit: 最多写入的行数，<= 0 表示不限制
+// 返回: 实际写入的数据行数
+func (s *ExportService) WriteArticlesCSV(ctx context.Context, w io.Writer, query models.ArticleQuery, limit int64) (int64, error) {
+	// 导出不走全文搜索，按创建时间稳定排序
+	query.Search = ""
+	query.SortBy, query.Order = "created_at", "desc"
+
+	pageSize := exportPageSize(config.PageArticles)
+	return writeCSVPages(w, exportArticleHeader, limit, pageSize, func(page int) ([][]string, error) {
+		query.Page, query.PageSize = page, pageSize
+		articles, _, err := s.articleRepo.List(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([][]string, 0, len(articles))
+		for _, a := range articles {
+			var author, category, publishedAt string
+			if a.Author != nil {
+				author = a.Author.Username
+			}
+			if a.Category != nil {
+				category = a.Category.Name
+			}
+			if a.PublishedAt != nil {
+				publishedAt = a.PublishedAt.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{
+				a.ID.String(),
+				a.Title,
+				a.Slug,
+				string(a.Status),
+				a.AuthorID.String(),
+				author,
+				category,
+				strconv.Itoa(a.ViewCount),
+				strconv.Itoa(a.LikeCount),
+				strconv.Itoa(a.CommentCount),
+				publishedAt,
+				a.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		return rows, nil
+	})
+}
+
+// StartUsersJob 创建异步用户导出任务
+// 返回: 新建的任务信息，Redis 不可用时返回 database.ErrRedisUnavailable
+func (s *ExportService) StartUsersJob(ctx context.Context, query models.UserQuery) (*models.ExportJob, error) {
+	return s.startJob(ctx, ExportKindUsers, func(ctx context.Context, w io.Writer) (int64, error) {
+		return s.WriteUsersCSV(ctx, w, query, 0)
+	})
+}
+
+// StartArticlesJob 创建异步文章导出任务
+// 返回: 新建的任务信息，Redis 不可用时返回 database.ErrRedisUnavailable
+func (s *ExportService) StartArticlesJob(ctx context.Context, query models.ArticleQuery) (*models.ExportJob, error) {
+	return s.startJob(ctx, ExportKindArticles, func(ctx context.Context, w io.Writer) (int64, error) {
+		return s.WriteArticlesCSV(ctx, w, query, 0)
+	})
+}
+
+// GetJob 获取导出任务状态
+// 返回: 任务信息；任务不存在或已过期时返回 ErrExportJobNotFound
+func (s *ExportService) GetJob(ctx context.Context, id uuid.UUID) (*models.ExportJob, error) {
+	if database.RedisClient == nil {
+		return nil, database.ErrRedisUnavailable
+	}
+	val, err := database.RedisClient.Get(ctx, redisExportJobPrefix+id.String()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrExportJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job models.ExportJob
+	if err := json.Unmarshal(val, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// JobFile 获取已完成导出任务的文件路径
+// 返回: 任务信息和文件路径；任务未完成时返回 ErrExportJobNotReady，导出目录中没有文件时返回 ErrExportFileMissing
+func (s *ExportService) JobFile(ctx context.Context, id uuid.UUID) (*models.ExportJob, string, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if job.Status != models.ExportJobDone {
+		return nil, "", ErrExportJobNotReady
+	}
+	path := s.jobFilePath(job.ID)
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", ErrExportFileMissing
+	}
+	return job, path, nil
+}
+
+// PruneExpiredFiles 删除导出目录中超过 exportJobTTL 的导出文件（含写入中断残留的临时文件）
+// 返回: 删除的文件数
+// 注意: 文件在任务完成时写入，任务记录在完成后 exportJobTTL 过期，此时文件已无法下载；导出目录中的其他文件不受影响
+func (s *ExportService) PruneExpiredFiles(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(s.exportDir)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-exportJobTTL)
+	deleted := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return deleted, ctx.Err()
+		}
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".csv") || strings.HasSuffix(name, ".csv.tmp")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.exportDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *ExportService) jobFilePath(id uuid.UUID) string {
+	return filepath.Join(s.exportDir, id.String()+".csv")
+}
+
+func (s *ExportService) startJob(ctx context.Context, kind string, write func(context.Context, io.Writer) (int64, error)) (*models.ExportJob, error) {
+	job := &models.ExportJob{
+		ID:        uuid.New(),
+		Kind:      kind,
+		Status:    models.ExportJobPending,
+		FileName:  ExportFileName(kind, time.Now()),
+		CreatedAt: time.Now(),
+	}
+	if err := saveExportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// 后台任务持有自己的副本，之后只通过 Redis 共享状态
+	copied := *job
+	go s.runJob(&copied, write)
+
+	return job, nil
+}
+
+func (s *ExportService) runJob(job *models.ExportJob, write func(context.Context, io.Writer) (int64, error)) {
+	defer startBackgroundJob(JobKindExport)()
+
+	l := logger.GetLogger()
+	job.Status = models.ExportJobRunning
+	if err := saveExportJob(context.Background(), job); err != nil {
+		l.Warn().Err(err).Str("job_id", job.ID.String()).Msg("Failed to save export job status")
+	}
+
+	rows, err := s.writeJobFile(job.ID, write)
+
+	now := time.Now()
+	job.Rows = rows
+	job.FinishedAt = &now
+	if err != nil {
+		job.Status = models.ExportJobFailed
+		job.Error = err.Error()
+		l.Error().Err(err).Str("job_id", job.ID.String()).Msg("Export job failed")
+	} else {
+		job.Status = models.ExportJobDone
+	}
+	if err := saveExportJob(context.Background(), job); err != nil {
+		l.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to save export job status")
+	}
+}
+
+func (s *ExportService) writeJobFile(id uuid.UUID, write func(context.Context, io.Writer) (int64, error)) (int64, error) {
+	// 先写入临时文件，完成后再重命名，避免下载到不完整的文件
+	finalPath := s.jobFilePath(id)
+	tmpPath := finalPath + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	rows, err := write(context.Background(), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return rows, err
+	}
+
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		os.Remove(tmpPath)
+		return rows, fmt.Errorf("failed to save export file: %w", err)
+	}
+	return rows, nil
+}
+
+func saveExportJob(ctx context.Context, job *models.ExportJob) error {
+	if database.RedisClient == nil {
+		return database.ErrRedisUnavailable
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return database.RedisClient.Set(ctx, redisExportJobPrefix+job.ID.String(), data, exportJobTTL).Err()
+}
+
+// ExportFileName 生成带日期的导出文件名，例如 users-20240102.csv
+func ExportFileName(kind string, t time.Time) string {
+	return fmt.Sprintf("%s-%s.csv", kind, t.Format("20060102"))
+}
+
+// exportPageSize 导出时每页读取的行数：不超过 exportMaxPageSize，也不超过仓库层按分页策略限制的上限，
+// 否则仓库返回的整页行数少于请求的行数，会被误判为最后一页
+func exportPageSize(resource config.PageResource) int {
+	_, pageSize := config.NormalizePage(resource, 1, exportMaxPageSize)
+	return pageSize
+}
+
+// writeCSVPages 逐页获取数据并写入 CSV，每页写完后立即 flush，便于流式输出
+// pageSize: fetch 每页返回的行数，返回的行数少于 pageSize 时视为最后一页
+func writeCSVPages(w io.Writer, header []string, limit int64, pageSize int, fetch func(page int) ([][]string, error)) (int64, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return 0, err
+	}
+
+	var written int64
+	for page := 1; ; page++ {
+		rows, err := fetch(page)
+		if err != nil {
+			return written, err
+		}
+
+		for _, row := range rows {
+			if limit > 0 && written >= limit {
+				cw.Flush()
+				return written, cw.Error()
+			}
+			for i := range row {
+				row[i] = sanitizeCSVCell(row[i])
+			}
+			if err := cw.Write(row); err != nil {
+				return written, err
+			}
+			written++
+		}
+
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return written, err
+		}
+
+		if len(rows) < pageSize {
+			return written, nil
+		}
+	}
+}
+
+// sanitizeCSVCell 防止 CSV 公式注入（表格软件会把 = + - @ 开头的单元格当作公式执行）
+func sanitizeCSVCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
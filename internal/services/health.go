@@ -0,0 +1,104 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/search"
+)
+
+// errDependencyNotConfigured 依赖未初始化（启动时连接失败或未配置）
+var errDependencyNotConfigured = errors.New("not initialized")
+
+// CheckHealth 检查数据库、Redis 和 Elasticsearch 是否可用，用于负载均衡的就绪检查
+//
+// 设计考虑：
+// - 三项检查并发执行，每项使用 cfg.TimeoutMs 的超时，检查总耗时不超过一次超时
+// - 数据库是必需依赖；Redis 和 Elasticsearch 默认是可选依赖（不可用时限流、缓存、搜索降级运行），不可用时报告 degraded，配置为必需时报告 down
+// - Elasticsearch 未启用时报告 disabled；Redis 客户端未初始化时按不可用处理
+// - 有任一依赖为 down 时整体为 unavailable，有 degraded 时整体为 degraded
+func CheckHealth(ctx context.Context, cfg config.HealthConfig) *models.HealthReport {
+	current := config.Get()
+	esEnabled := current != nil && current.Elasticsearch.Enabled
+	checks := []struct {
+		name     string
+		required bool
+		disabled bool
+		ping     func(ctx context.Context) error
+	}{
+		{"database", true, false, pingDatabase},
+		{"redis", cfg.RedisRequired, false, pingRedis},
+		{"elasticsearch", cfg.ElasticsearchRequired, !esEnabled, search.Ping},
+	}
+
+	report := &models.HealthReport{Status: models.HealthOK, Checks: make(map[string]models.DependencyHealth, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	// 未启用的依赖在启动检查 goroutine 之前写入，之后 report.Checks 只在持有 mu 时修改
+	for _, check := range checks {
+		if check.disabled {
+			report.Checks[check.name] = models.DependencyHealth{Status: models.HealthDisabled}
+		}
+	}
+	for _, check := range checks {
+		if check.disabled {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, cfg.Timeout())
+			defer cancel()
+			start := time.Now()
+			err := check.ping(ctx)
+			result := models.DependencyHealth{Status: models.HealthOK, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = models.HealthDegraded
+				if check.required {
+					result.Status = models.HealthDown
+				}
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			report.Checks[check.name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		switch {
+		case result.Status == models.HealthDown:
+			report.Status = models.HealthUnavailable
+		case result.Status == models.HealthDegraded && report.Status == models.HealthOK:
+			report.Status = models.HealthDegraded
+		}
+	}
+	return report
+}
+
+func pingDatabase(ctx context.Context) error {
+	if database.DB == nil {
+		return errDependencyNotConfigured
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func pingRedis(ctx context.Context) error {
+	if database.RedisClient == nil {
+		return errDependencyNotConfigured
+	}
+	return database.RedisClient.Ping(ctx).Err()
+}
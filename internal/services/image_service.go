@@ -18,7 +18,6 @@ import (
 
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
-	"enterprise-blog/pkg/logger"
 
 	"github.com/google/uuid"
 	"image"
@@ -27,6 +26,15 @@ import (
 	_ "image/png"
 )
 
+var (
+	// ErrUnsupportedImageFormat 上传文件的 Content-Type 不在允许的图片格式中
+	ErrUnsupportedImageFormat = errors.New("unsupported image format, only JPEG, PNG, GIF, WebP are allowed")
+	// ErrImageTooLarge 上传文件超过大小限制
+	ErrImageTooLarge = errors.New("image size exceeds 10MB limit")
+	// ErrInvalidImage 上传文件无法按图片解码
+	ErrInvalidImage = errors.New("invalid image file")
+)
+
 // ImageService 图片服务，提供图片相关的业务逻辑
 //
 // 职责：
@@ -110,15 +118,15 @@ func (s *ImageService) Upload(ctx context.Context, uploaderID uuid.UUID, file *m
 
 	mimeType := file.Header.Get("Content-Type")
 	if !allowedMimeTypes[mimeType] {
-		return nil, errors.New("unsupported image format, only JPEG, PNG, GIF, WebP are allowed")
+		return nil, ErrUnsupportedImageFormat
 	}
 
 	// 步骤2：验证文件大小
 	// 限制文件大小，防止DoS攻击和存储空间浪费
-	// TODO: 应该从 config.AppConfig.Upload.MaxSize 读取，这里使用常量简化
+	// TODO: 应该从 config.Get().Upload.MaxSize 读取，这里使用常量简化
 	const maxSize = 10 * 1024 * 1024 // 10MB
 	if file.Size > maxSize {
-		return nil, errors.New("image size exceeds 10MB limit")
+		return nil, ErrImageTooLarge
 	}
 
 	// 步骤3：打开上传的文件
@@ -164,7 +172,7 @@ func (s *ImageService) Upload(ctx context.Context, uploaderID uuid.UUID, file *m
 	img, _, err := image.DecodeConfig(imgFile)
 	if err != nil {
 		os.Remove(filePath) // 如果解码失败，说明不是有效的图片文件，删除文件
-		return nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
 	}
 
 	// TODO: 生成缩略图（可选功能）
@@ -252,36 +260,17 @@ func (s *ImageService) Update(ctx context.Context, id uuid.UUID, req *models.Ima
 // - error: 如果删除失败则返回错误
 //
 // 功能流程：
-// 1. 从数据库获取图片信息（获取文件路径）
-// 2. 删除文件系统中的文件
-// 3. 软删除数据库记录（设置 deleted_at 字段）
+// 1. 软删除数据库记录（设置 deleted_at 字段）
 //
 // 设计考虑：
-// - 软删除：数据库记录不真正删除，只标记为已删除，可以恢复
-// - 文件删除：物理删除文件，释放存储空间
-// - 错误处理：即使文件删除失败，也继续执行数据库删除（避免数据不一致）
+// - 软删除：数据库记录不真正删除，只标记为已删除，可以从回收站恢复
+// - 文件保留：软删除时不删除文件，否则恢复后图片无法访问；文件在回收站永久删除时清理
 //
 // 面试要点：
 // - 为什么使用软删除？可以恢复误删的数据，保留审计记录
-// - 如何处理文件删除失败？记录警告日志，继续执行数据库删除，避免数据不一致
+// - 文件什么时候删除？永久删除（purge）时，见 TrashService.Purge
 func (s *ImageService) Delete(ctx context.Context, id uuid.UUID) error {
-	// 先获取图片信息，获取文件路径
-	image, err := s.imageRepo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	// 删除文件系统中的文件
-	// 如果文件删除失败，记录警告日志但不中断流程
-	// 这样可以避免文件删除失败导致数据库记录无法删除的情况
-	if err := os.Remove(image.Path); err != nil {
-		l := logger.GetLogger()
-		l.Warn().Err(err).Str("path", image.Path).Msg("failed to delete image file")
-		// 继续执行数据库删除，即使文件删除失败
-	}
-
 	// 软删除数据库记录（设置 deleted_at 字段）
-	// 软删除的好处：可以恢复数据，保留审计记录
 	return s.imageRepo.Delete(ctx, id)
 }
 
@@ -0,0 +1,187 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/pkg/logger"
+	"enterprise-blog/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLoginLocked 登录失败次数过多，暂时不能登录
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// LoginLockedError 带有可以重试前需要等待的时间，errors.Is(err, ErrLoginLocked) 为 true
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// 登录失败的锁定范围（login_lockouts_total 指标的 scope 标签）
+const (
+	loginScopeEmail = "email"
+	loginScopeIP    = "ip"
+)
+
+// loginLockScript 清理窗口外的失败记录，失败次数达到上限时返回还需等待的毫秒数，否则返回 0
+// KEYS[1]: 失败记录键；ARGV[1]: 上限；ARGV[2]: 窗口（毫秒）
+// 时间取 Redis 服务器时间，多个实例共用同一时钟
+var loginLockScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local n = redis.call('ZCARD', KEYS[1])
+if n < limit then
+	return 0
+end
+local oldest = redis.call('ZRANGE', KEYS[1], n - limit, n - limit, 'WITHSCORES')
+return tonumber(oldest[2]) + window - now
+`)
+
+// loginFailureScript 记录一次失败并返回窗口内的失败次数
+// KEYS[1]: 失败记录键；ARGV[1]: 窗口（毫秒）；ARGV[2]: 本次失败的唯一成员
+var loginFailureScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = tonumber(ARGV[1])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+redis.call('ZADD', KEYS[1], now, ARGV[2])
+redis.call('PEXPIRE', KEYS[1], window)
+return redis.call('ZCARD', KEYS[1])
+`)
+
+// LoginGuard 登录防暴力破解：按邮箱和 IP 统计滑动窗口内的密码错误次数，达到上限后暂时锁定
+//
+// 设计考虑：
+// - 计数按请求中的邮箱而不是用户，邮箱不存在时同样计数和锁定，锁定响应不会暴露邮箱是否注册
+// - IP 计数的上限高于邮箱，覆盖同一来源用多个邮箱轮流尝试的情况
+// - 登录成功只清除邮箱计数，否则攻击者可以用自己的账号登录来清除 IP 计数
+// - 锁定期间的请求直接拒绝，不校验密码也不再计数，窗口内最早的失败过期后自动解锁
+// - Redis 不可用或读写失败时不限制登录（接口限流仍然按 IP 限制请求）
+type LoginGuard struct {
+	maxFailures   atomic.Int64
+	ipMaxFailures atomic.Int64
+	window        atomic.Int64
+}
+
+// NewLoginGuard 创建登录防暴力破解组件
+// 参数:
+//   - maxFailures: 同一邮箱在窗口内允许的密码错误次数
+//   - ipMaxFailures: 同一 IP 在窗口内允许的登录失败次数
+//   - window: 滑动窗口
+func NewLoginGuard(maxFailures, ipMaxFailures int, window time.Duration) *LoginGuard {
+	g := &LoginGuard{}
+	g.SetLimits(maxFailures, ipMaxFailures, window)
+	return g
+}
+
+// SetLimits 修改上限和窗口，对之后的请求生效（配置热加载）
+func (g *LoginGuard) SetLimits(maxFailures, ipMaxFailures int, window time.Duration) {
+	g.maxFailures.Store(int64(maxFailures))
+	g.ipMaxFailures.Store(int64(ipMaxFailures))
+	g.window.Store(int64(window))
+}
+
+// Check 检查邮箱和 IP 是否处于锁定状态
+// ip: 客户端 IP，为空时只检查邮箱
+// 返回: 锁定时返回 *LoginLockedError，否则返回 nil
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	if database.RedisClient == nil {
+		return nil
+	}
+	ctx, cancel := redisWriteContext(ctx)
+	defer cancel()
+
+	window := time.Duration(g.window.Load())
+	var retryAfter time.Duration
+	for _, k := range g.keys(email, ip) {
+		wait, err := loginLockScript.Run(ctx, database.RedisClient, []string{k.key}, k.limit, window.Milliseconds()).Int64()
+		if err != nil {
+			l := logger.FromContext(ctx, "auth")
+			l.Warn().Err(err).Msg("Failed to check login lockout")
+			return nil
+		}
+		if d := time.Duration(wait) * time.Millisecond; d > retryAfter {
+			retryAfter = d
+		}
+	}
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次密码错误，失败次数达到上限时记录锁定指标
+func (g *LoginGuard) RecordFailure(ctx context.Context, email, ip string) {
+	if database.RedisClient == nil {
+		return
+	}
+	ctx, cancel := redisWriteContext(ctx)
+	defer cancel()
+
+	window := time.Duration(g.window.Load())
+	for _, k := range g.keys(email, ip) {
+		n, err := loginFailureScript.Run(ctx, database.RedisClient, []string{k.key}, window.Milliseconds(), uuid.NewString()).Int64()
+		if err != nil {
+			l := logger.FromContext(ctx, "auth")
+			l.Warn().Err(err).Msg("Failed to record login failure")
+			return
+		}
+		if n == k.limit {
+			metrics.RecordLoginLockout(k.scope)
+			l := logger.FromContext(ctx, "auth")
+			l.Warn().Str("scope", k.scope).Str("ip", ip).Msg("Login locked after repeated failures")
+		}
+	}
+}
+
+// Reset 登录成功后清除邮箱的失败记录（IP 的失败记录保留）
+func (g *LoginGuard) Reset(ctx context.Context, email string) {
+	if database.RedisClient == nil {
+		return
+	}
+	ctx, cancel := redisWriteContext(ctx)
+	defer cancel()
+	if err := database.RedisClient.Del(ctx, loginFailureKey(loginScopeEmail, email)).Err(); err != nil {
+		l := logger.FromContext(ctx, "auth")
+		l.Warn().Err(err).Msg("Failed to reset login failures")
+	}
+}
+
+// loginGuardKey 一个计数范围的失败记录键和上限
+type loginGuardKey struct {
+	scope string
+	key   string
+	limit int64
+}
+
+func (g *LoginGuard) keys(email, ip string) []loginGuardKey {
+	keys := []loginGuardKey{{loginScopeEmail, loginFailureKey(loginScopeEmail, email), g.maxFailures.Load()}}
+	if ip != "" {
+		keys = append(keys, loginGuardKey{loginScopeIP, loginFailureKey(loginScopeIP, ip), g.ipMaxFailures.Load()})
+	}
+	return keys
+}
+
+// loginFailureKey 失败记录键，邮箱不区分大小写
+func loginFailureKey(scope, value string) string {
+	return redisLoginFailuresPrefix + scope + ":" + strings.ToLower(strings.TrimSpace(value))
+}
@@ -0,0 +1,564 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/errorreport"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultDigestDays 摘要群发未指定天数时汇总的天数
+	defaultDigestDays = 7
+	// maxDigestDays 摘要群发最多汇总的天数
+	maxDigestDays = 90
+	// maxDigestArticles 摘要中最多列出的文章数
+	maxDigestArticles = 50
+	// newsletterSendTimeout 单封邮件的发送超时时间
+	newsletterSendTimeout = 30 * time.Second
+	// unsubscribeSignaturePrefix 退订链接签名的用途前缀，避免与同一密钥的其他签名混用
+	unsubscribeSignaturePrefix = "newsletter-unsubscribe:"
+)
+
+var (
+	// ErrInvalidCampaign 群发类型、文章或摘要范围不合法
+	ErrInvalidCampaign = errors.New("invalid newsletter campaign")
+	// ErrInvalidConfirmToken 确认令牌无效或已过期
+	ErrInvalidConfirmToken = errors.New("invalid or expired confirmation token")
+	// ErrInvalidUnsubscribeLink 退订链接签名不正确
+	ErrInvalidUnsubscribeLink = errors.New("invalid unsubscribe link")
+	// ErrSubscriberNotFound 订阅者不存在
+	ErrSubscriberNotFound = repository.ErrSubscriberNotFound
+	// ErrCampaignNotFound 群发记录不存在
+	ErrCampaignNotFound = repository.ErrCampaignNotFound
+)
+
+// NewsletterService 邮件订阅服务：双重确认订阅、退订和群发
+//
+// 设计考虑：
+// - 订阅需点击邮件中的确认链接后才生效（双重确认），确认令牌只保存哈希
+// - 订阅接口对已订阅、新订阅等情况返回相同的结果，避免通过接口探测邮箱是否已订阅
+// - 每封群发邮件带有该订阅者的退订链接（HMAC 签名，无需登录）和 List-Unsubscribe 头
+// - 群发在后台按批读取订阅者逐封发送，按发信服务商的速率限制控制间隔
+// - 收到永久性错误（SMTP 5xx）的地址标记为 bounced，之后不再发送
+type NewsletterService struct {
+	newsletterRepo *repository.NewsletterRepository
+	articleRepo    *repository.ArticleRepository
+	sender         EmailSender
+	cfg            config.NewsletterConfig
+	publicURL      string
+	frontendURL    string
+	signingKey     []byte
+	throttle       *sendThrottle
+
+	inflight sync.WaitGroup
+}
+
+// NewNewsletterService 创建新的邮件订阅服务实例
+// newsletterRepo: 订阅者和群发记录数据访问层仓库
+// articleRepo: 文章数据访问层仓库（群发内容）
+// sender: 邮件发送器
+// cfg: 批量大小、发信速率和确认链接有效期配置
+// server: 提供邮件中链接使用的 API 和前端地址
+// signingKey: 退订链接的签名密钥
+func NewNewsletterService(newsletterRepo *repository.NewsletterRepository, articleRepo *repository.ArticleRepository, sender EmailSender, cfg config.NewsletterConfig, server config.ServerConfig, signingKey string) *NewsletterService {
+	return &NewsletterService{
+		newsletterRepo: newsletterRepo,
+		articleRepo:    articleRepo,
+		sender:         sender,
+		cfg:            cfg,
+		publicURL:      strings.TrimRight(server.PublicURL, "/"),
+		frontendURL:    strings.TrimRight(server.FrontendURL, "/"),
+		signingKey:     []byte(signingKey),
+		throttle:       newSendThrottle(cfg.RatePerMinute(emailProvider(sender))),
+	}
+}
+
+// Subscribe 提交订阅，向邮箱发送确认邮件（后台发送）
+// email: 订阅邮箱（不区分大小写）
+// ip: 提交请求的客户端 IP
+// 注意: 已确认的邮箱不做任何处理；未确认、已退订和退信的邮箱重新生成确认链接
+func (s *NewsletterService) Subscribe(ctx context.Context, email, ip string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	token, tokenHash, err := generateConfirmToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.cfg.ConfirmTTL())
+
+	subscriber, err := s.newsletterRepo.GetSubscriberByEmail(ctx, email)
+	switch {
+	case errors.Is(err, ErrSubscriberNotFound):
+		subscriber = &models.Subscriber{
+			Email:            email,
+			Status:           models.SubscriberPending,
+			ConfirmTokenHash: &tokenHash,
+			ConfirmExpiresAt: &expiresAt,
+			IPAddress:        ip,
+		}
+		if err := s.newsletterRepo.CreateSubscriber(ctx, subscriber); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case subscriber.Status == models.SubscriberConfirmed:
+		return nil
+	default:
+		subscriber.Status = models.SubscriberPending
+		subscriber.ConfirmTokenHash, subscriber.ConfirmExpiresAt = &tokenHash, &expiresAt
+		subscriber.IPAddress = ip
+		if err := s.newsletterRepo.UpdateSubscriber(ctx, subscriber); err != nil {
+			return err
+		}
+	}
+
+	html, err := renderNewsletterTemplate(confirmEmailTemplate, map[string]string{
+		"ConfirmURL": s.publicURL + "/api/v1/newsletter/confirm?token=" + token,
+		"ExpiresIn":  s.cfg.ConfirmTTL().String(),
+	})
+	if err != nil {
+		return err
+	}
+	msg := &EmailMessage{To: []string{email}, Subject: "请确认您的邮件订阅", HTML: html}
+
+	ctx = context.WithoutCancel(ctx)
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		sendCtx, cancel := context.WithTimeout(ctx, newsletterSendTimeout)
+		defer cancel()
+		if err := s.sender.Send(sendCtx, msg); err != nil {
+			l := logger.FromContext(ctx, "newsletter")
+			l.Warn().Err(err).Str("subscriber_id", subscriber.ID.String()).Msg("Failed to send newsletter confirmation email")
+		}
+	}()
+	return nil
+}
+
+// Confirm 使用确认令牌完成订阅
+// 返回: 令牌不存在、已使用或已过期时返回 ErrInvalidConfirmToken
+func (s *NewsletterService) Confirm(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidConfirmToken
+	}
+	subscriber, err := s.newsletterRepo.GetSubscriberByTokenHash(ctx, hashConfirmToken(token))
+	if errors.Is(err, ErrSubscriberNotFound) {
+		return ErrInvalidConfirmToken
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if subscriber.Status != models.SubscriberPending || subscriber.ConfirmExpiresAt == nil || now.After(*subscriber.ConfirmExpiresAt) {
+		return ErrInvalidConfirmToken
+	}
+
+	subscriber.Status = models.SubscriberConfirmed
+	subscriber.ConfirmedAt = &now
+	subscriber.ConfirmTokenHash, subscriber.ConfirmExpiresAt = nil, nil
+	subscriber.UnsubscribedAt, subscriber.BouncedAt = nil, nil
+	return s.newsletterRepo.UpdateSubscriber(ctx, subscriber)
+}
+
+// Unsubscribe 通过退订链接退订（重复退订视为成功）
+// id: 订阅者ID
+// signature: 退订链接中的签名
+// 返回: 签名不正确时返回 ErrInvalidUnsubscribeLink
+func (s *NewsletterService) Unsubscribe(ctx context.Context, id uuid.UUID, signature string) error {
+	if !hmac.Equal([]byte(signature), []byte(s.unsubscribeSignature(id))) {
+		return ErrInvalidUnsubscribeLink
+	}
+	subscriber, err := s.newsletterRepo.GetSubscriber(ctx, id)
+	if err != nil {
+		return err
+	}
+	if subscriber.Status != models.SubscriberPending && subscriber.Status != models.SubscriberConfirmed {
+		return nil
+	}
+
+	now := time.Now()
+	subscriber.Status = models.SubscriberUnsubscribed
+	subscriber.UnsubscribedAt = &now
+	subscriber.ConfirmTokenHash, subscriber.ConfirmExpiresAt = nil, nil
+	return s.newsletterRepo.UpdateSubscriber(ctx, subscriber)
+}
+
+// ListSubscribers 分页获取订阅者（管理后台，含邮箱）
+// status: 按状态筛选，为空表示全部
+func (s *NewsletterService) ListSubscribers(ctx context.Context, status string, page, pageSize int) ([]*models.Subscriber, int64, error) {
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	subscribers, total, err := s.newsletterRepo.ListSubscribers(ctx, status, page, pageSize)
+	if subscribers == nil {
+		subscribers = []*models.Subscriber{}
+	}
+	return subscribers, total, err
+}
+
+// CreateCampaign 创建群发并在后台发送给全部已确认的订阅者
+// createdBy: 操作人用户ID
+// 返回: 状态为 sending 的群发记录，进度可通过 GetCampaign 查询；文章未发布或摘要范围内没有文章时返回 ErrInvalidCampaign
+func (s *NewsletterService) CreateCampaign(ctx context.Context, req *models.CampaignCreate, createdBy *uuid.UUID) (*models.NewsletterCampaign, error) {
+	campaign := &models.NewsletterCampaign{
+		Kind:      req.Kind,
+		Subject:   strings.TrimSpace(req.Subject),
+		Status:    models.CampaignSending,
+		CreatedBy: createdBy,
+	}
+	content := &newsletterContent{Kind: req.Kind}
+
+	switch req.Kind {
+	case models.CampaignKindArticle:
+		if req.ArticleID == nil {
+			return nil, fmt.Errorf("%w: article_id is required", ErrInvalidCampaign)
+		}
+		article, err := s.articleRepo.GetByID(ctx, *req.ArticleID)
+		if errors.Is(err, repository.ErrArticleNotFound) || (err == nil && article.Status != models.StatusPublished) {
+			return nil, fmt.Errorf("%w: article is not published", ErrInvalidCampaign)
+		}
+		if err != nil {
+			return nil, err
+		}
+		campaign.ArticleID = &article.ID
+		content.Articles = []newsletterArticle{s.newsletterArticle(article)}
+		if campaign.Subject == "" {
+			campaign.Subject = article.Title
+		}
+	case models.CampaignKindDigest:
+		days := req.DigestDays
+		if days == 0 {
+			days = defaultDigestDays
+		}
+		if days < 1 || days > maxDigestDays {
+			return nil, fmt.Errorf("%w: digest_days must be between 1 and %d", ErrInvalidCampaign, maxDigestDays)
+		}
+		since := config.StartOfDay(time.Now()).AddDate(0, 0, -days+1)
+		articles, err := s.articleRepo.ListPublishedSince(ctx, since, maxDigestArticles)
+		if err != nil {
+			return nil, err
+		}
+		if len(articles) == 0 {
+			return nil, fmt.Errorf("%w: no articles published in the last %d days", ErrInvalidCampaign, days)
+		}
+		campaign.DigestDays = days
+		content.Days = days
+		for _, article := range articles {
+			content.Articles = append(content.Articles, s.newsletterArticle(article))
+		}
+		if campaign.Subject == "" {
+			campaign.Subject = fmt.Sprintf("最近 %d 天的文章", days)
+		}
+	default:
+		return nil, fmt.Errorf("%w: kind must be %s or %s", ErrInvalidCampaign, models.CampaignKindArticle, models.CampaignKindDigest)
+	}
+
+	recipients, err := s.newsletterRepo.CountConfirmed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Recipients = int(recipients)
+	if err := s.newsletterRepo.CreateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	running := *campaign
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		defer startBackgroundJob(JobKindNewsletter)()
+		s.send(context.WithoutCancel(ctx), &running, content)
+	}()
+	return campaign, nil
+}
+
+// GetCampaign 获取群发记录（含发送进度）
+func (s *NewsletterService) GetCampaign(ctx context.Context, id uuid.UUID) (*models.NewsletterCampaign, error) {
+	return s.newsletterRepo.GetCampaign(ctx, id)
+}
+
+// ListCampaigns 分页获取群发记录（最新的在前）
+func (s *NewsletterService) ListCampaigns(ctx context.Context, page, pageSize int) ([]*models.NewsletterCampaign, int64, error) {
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	campaigns, total, err := s.newsletterRepo.ListCampaigns(ctx, page, pageSize)
+	if campaigns == nil {
+		campaigns = []*models.NewsletterCampaign{}
+	}
+	return campaigns, total, err
+}
+
+// Wait 等待进行中的确认邮件和群发完成，ctx 结束时返回 ctx 的错误（退出前调用）
+func (s *NewsletterService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// send 按批读取已确认的订阅者逐封发送，每批结束后更新进度
+func (s *NewsletterService) send(ctx context.Context, campaign *models.NewsletterCampaign, content *newsletterContent) {
+	l := logger.FromContext(ctx, "newsletter")
+	var lastErr error
+	afterID := uuid.Nil
+
+batches:
+	for {
+		subscribers, err := s.newsletterRepo.ListConfirmedAfter(ctx, afterID, s.cfg.BatchSize)
+		if err != nil {
+			errorreport.CaptureError(ctx, err, map[string]string{"worker": "newsletter", "campaign_id": campaign.ID.String()})
+			lastErr = err
+			break
+		}
+		if len(subscribers) == 0 {
+			break
+		}
+
+		for _, subscriber := range subscribers {
+			if err := s.throttle.Wait(ctx); err != nil {
+				lastErr = err
+				break batches
+			}
+			err := s.sendToSubscriber(ctx, campaign, content, subscriber)
+			switch {
+			case err == nil:
+				campaign.SentCount++
+			case IsPermanentEmailError(err):
+				campaign.BouncedCount++
+				s.markBounced(ctx, subscriber)
+			default:
+				campaign.FailedCount++
+				lastErr = err
+				l.Warn().Err(err).Str("campaign_id", campaign.ID.String()).Str("subscriber_id", subscriber.ID.String()).Msg("Failed to send newsletter")
+			}
+		}
+		afterID = subscribers[len(subscribers)-1].ID
+
+		if err := s.newsletterRepo.UpdateCampaignProgress(ctx, campaign); err != nil {
+			l.Warn().Err(err).Str("campaign_id", campaign.ID.String()).Msg("Failed to update newsletter campaign progress")
+		}
+		if len(subscribers) < s.cfg.BatchSize {
+			break
+		}
+	}
+
+	now := time.Now()
+	campaign.Status, campaign.FinishedAt = models.CampaignSent, &now
+	if lastErr != nil && campaign.SentCount == 0 {
+		campaign.Status, campaign.Error = models.CampaignFailed, lastErr.Error()
+	}
+	if err := s.newsletterRepo.UpdateCampaignProgress(ctx, campaign); err != nil {
+		l.Warn().Err(err).Str("campaign_id", campaign.ID.String()).Msg("Failed to update newsletter campaign progress")
+	}
+	l.Info().
+		Str("campaign_id", campaign.ID.String()).
+		Str("status", campaign.Status).
+		Int("sent", campaign.SentCount).
+		Int("failed", campaign.FailedCount).
+		Int("bounced", campaign.BouncedCount).
+		Msg("Newsletter campaign finished")
+}
+
+// sendToSubscriber 向一个订阅者发送群发邮件（带该订阅者的退订链接）
+func (s *NewsletterService) sendToSubscriber(ctx context.Context, campaign *models.NewsletterCampaign, content *newsletterContent, subscriber *models.Subscriber) error {
+	unsubscribeURL := s.unsubscribeURL(subscriber.ID)
+	html, err := renderNewsletterTemplate(campaignEmailTemplate, struct {
+		*newsletterContent
+		UnsubscribeURL string
+	}{content, unsubscribeURL})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, newsletterSendTimeout)
+	defer cancel()
+	return s.sender.Send(ctx, &EmailMessage{
+		To:      []string{subscriber.Email},
+		Subject: campaign.Subject,
+		HTML:    html,
+		Headers: map[string]string{
+			// RFC 8058 一键退订：邮件客户端向该地址发送 POST 请求
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+// markBounced 将收到永久性错误的订阅者标记为退信，之后不再发送
+func (s *NewsletterService) markBounced(ctx context.Context, subscriber *models.Subscriber) {
+	now := time.Now()
+	subscriber.Status, subscriber.BouncedAt = models.SubscriberBounced, &now
+	if err := s.newsletterRepo.UpdateSubscriber(ctx, subscriber); err != nil {
+		l := logger.FromContext(ctx, "newsletter")
+		l.Warn().Err(err).Str("subscriber_id", subscriber.ID.String()).Msg("Failed to mark newsletter subscriber as bounced")
+	}
+}
+
+func (s *NewsletterService) unsubscribeURL(id uuid.UUID) string {
+	query := url.Values{"id": {id.String()}, "sig": {s.unsubscribeSignature(id)}}
+	return s.publicURL + "/api/v1/newsletter/unsubscribe?" + query.Encode()
+}
+
+// unsubscribeSignature 退订链接签名：hex(HMAC-SHA256(key, "newsletter-unsubscribe:" + 订阅者ID))
+func (s *NewsletterService) unsubscribeSignature(id uuid.UUID) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(unsubscribeSignaturePrefix))
+	mac.Write([]byte(id.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *NewsletterService) newsletterArticle(a *models.Article) newsletterArticle {
+	return newsletterArticle{
+		Title:       a.Title,
+		Excerpt:     a.Excerpt,
+		URL:         s.frontendURL + "/articles/" + a.ID.String(),
+		PublishedAt: a.PublishedAt,
+	}
+}
+
+// generateConfirmToken 生成确认令牌，返回令牌和保存到数据库的哈希
+func generateConfirmToken() (token, tokenHash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(buf)
+	return token, hashConfirmToken(token), nil
+}
+
+func hashConfirmToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// emailProvider 发信服务商名称，对应 newsletter.provider_rates 的键
+func emailProvider(sender EmailSender) string {
+	switch sender.(type) {
+	case *SMTPEmailSender:
+		return "smtp"
+	case *LogEmailSender:
+		return "log"
+	default:
+		return ""
+	}
+}
+
+// sendThrottle 按每分钟的发送上限控制相邻两封邮件的间隔（同一服务的群发共享）
+type sendThrottle struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newSendThrottle 创建发送节流器，perMinute 为 0 时不限制
+func newSendThrottle(perMinute int) *sendThrottle {
+	t := &sendThrottle{}
+	if perMinute > 0 {
+		t.interval = time.Minute / time.Duration(perMinute)
+	}
+	return t
+}
+
+// Wait 等待到允许发送下一封邮件，ctx 结束时返回 ctx 的错误
+func (t *sendThrottle) Wait(ctx context.Context) error {
+	if t.interval == 0 {
+		return ctx.Err()
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	at := t.next
+	if at.Before(now) {
+		at = now
+	}
+	t.next = at.Add(t.interval)
+	t.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newsletterContent 群发邮件内容（单篇文章或摘要），退订链接按订阅者生成
+type newsletterContent struct {
+	Kind     string
+	Days     int
+	Articles []newsletterArticle
+}
+
+type newsletterArticle struct {
+	Title       string
+	Excerpt     string
+	URL         string
+	PublishedAt *time.Time
+}
+
+func renderNewsletterTemplate(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var confirmEmailTemplate = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+<p>您好，我们收到了使用此邮箱订阅博客更新的请求。</p>
+<p><a href="{{.ConfirmURL}}">点击此处确认订阅</a>（{{.ExpiresIn}} 内有效）</p>
+<p style="color: #999;">如果这不是您本人的操作，请忽略此邮件，您不会收到任何后续邮件。</p>
+</body>
+</html>
+`))
+
+var campaignEmailTemplate = template.Must(template.New("campaign").Funcs(template.FuncMap{
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(config.Location()).Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+{{if eq .Kind "digest"}}<h2>最近 {{.Days}} 天的文章</h2>
+<ul>
+{{range .Articles}}<li><a href="{{.URL}}">{{.Title}}</a>（{{date .PublishedAt}}）{{if .Excerpt}}<br>{{.Excerpt}}{{end}}</li>
+{{end}}</ul>
+{{else}}{{range .Articles}}<h2>{{.Title}}</h2>
+{{if .Excerpt}}<p>{{.Excerpt}}</p>{{end}}
+<p><a href="{{.URL}}">阅读全文</a></p>
+{{end}}{{end}}<hr>
+<p style="color: #999; font-size: 12px;">您收到此邮件是因为订阅了博客更新。<a href="{{.UnsubscribeURL}}">退订</a></p>
+</body>
+</html>
+`))
@@ -0,0 +1,281 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// notificationUnreadTTL 未读数缓存时间，计数偏差（如并发重建）最多持续这么久
+	notificationUnreadTTL = 24 * time.Hour
+	// notificationSummaryLength 评论内容摘要的最大字符数
+	notificationSummaryLength = 100
+)
+
+// ErrNotificationNotFound 通知不存在（或不属于当前用户）
+var ErrNotificationNotFound = repository.ErrNotificationNotFound
+
+// adjustUnreadScript 只在未读数已缓存时增减（未缓存时下次读取从数据库重建），结果小于 0 时删除缓存
+var adjustUnreadScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local n = redis.call('INCRBY', KEYS[1], ARGV[1])
+if n < 0 then
+	redis.call('DEL', KEYS[1])
+end
+return n
+`)
+
+// defaultNotifications 当前进程使用的通知服务，由 NewNotificationService 注册
+// 未注册时（例如单元测试）不产生通知
+var defaultNotifications atomic.Pointer[NotificationService]
+
+// NotificationService 站内通知服务
+//
+// 设计考虑：
+// - 通知由文章、评论服务在操作成功后触发，查询接收人和写入都在后台 goroutine 中进行，失败只记日志，不影响原操作
+// - 未读数缓存在 Redis 计数器中（导航栏角标频繁读取），新通知和标记已读时增减，缓存缺失时从数据库重建
+// - 不通知用户自己触发的操作（如作者回复自己文章下的评论）
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	articleRepo      *repository.ArticleRepository
+	commentRepo      *repository.CommentRepository
+
+	inflight sync.WaitGroup
+}
+
+// NewNotificationService 创建新的通知服务实例，并注册为当前进程的通知服务
+// notificationRepo: 通知数据访问层仓库
+// articleRepo: 文章数据访问层仓库（查询文章作者）
+// commentRepo: 评论数据访问层仓库（查询被回复的评论）
+func NewNotificationService(notificationRepo *repository.NotificationRepository, articleRepo *repository.ArticleRepository, commentRepo *repository.CommentRepository) *NotificationService {
+	s := &NotificationService{
+		notificationRepo: notificationRepo,
+		articleRepo:      articleRepo,
+		commentRepo:      commentRepo,
+	}
+	defaultNotifications.Store(s)
+	return s
+}
+
+// List 分页获取用户的通知（最新的在前）
+// unreadOnly: 只返回未读通知
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]*models.Notification, int64, error) {
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	notifications, total, err := s.notificationRepo.List(ctx, userID, unreadOnly, page, pageSize)
+	if notifications == nil {
+		notifications = []*models.Notification{}
+	}
+	return notifications, total, err
+}
+
+// UnreadCount 获取用户的未读通知数（优先读取 Redis 计数器）
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	key := redisNotificationUnreadPrefix + userID.String()
+	if database.RedisClient != nil {
+		readCtx, cancel := redisReadContext(ctx)
+		n, err := database.RedisClient.Get(readCtx, key).Int64()
+		cancel()
+		if err == nil {
+			return n, nil
+		}
+	}
+
+	n, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if database.RedisClient != nil {
+		writeCtx, cancel := redisWriteContext(ctx)
+		_ = database.RedisClient.Set(writeCtx, key, n, notificationUnreadTTL).Err()
+		cancel()
+	}
+	return n, nil
+}
+
+// MarkRead 将用户的一条通知标记为已读（重复标记视为成功）
+// 返回: 通知不存在或不属于该用户时返回 ErrNotificationNotFound
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	changed, err := s.notificationRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if changed {
+		adjustUnreadCount(ctx, userID, -1)
+	}
+	return nil
+}
+
+// MarkAllRead 将用户的全部未读通知标记为已读
+// 返回: 标记的通知数
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	n, err := s.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if database.RedisClient != nil {
+		writeCtx, cancel := redisWriteContext(ctx)
+		_ = database.RedisClient.Set(writeCtx, redisNotificationUnreadPrefix+userID.String(), 0, notificationUnreadTTL).Err()
+		cancel()
+	}
+	return n, nil
+}
+
+// Wait 等待后台创建中的通知完成，ctx 结束时返回 ctx 的错误（退出前调用）
+func (s *NotificationService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CommentApproved 评论审核通过（或无需审核直接发布）后，在后台通知文章作者和被回复的评论作者
+// 注意: 被回复的评论作者同时是文章作者时只收到回复通知
+func (s *NotificationService) CommentApproved(ctx context.Context, comment *models.Comment) {
+	s.run(ctx, "comment_approved", func(ctx context.Context) ([]*models.Notification, error) {
+		article, err := s.articleRepo.GetByID(ctx, comment.ArticleID)
+		if err != nil {
+			return nil, err
+		}
+
+		base := models.Notification{
+			ActorID:     comment.UserID,
+			ActorName:   comment.Author,
+			SubjectType: models.NotificationSubjectComment,
+			SubjectID:   comment.ID,
+			ArticleID:   &article.ID,
+			Summary:     truncateRunes(comment.Content, notificationSummaryLength),
+		}
+
+		var notifications []*models.Notification
+		var replyTo *uuid.UUID
+		if comment.ParentID != nil {
+			parent, err := s.commentRepo.GetByID(ctx, *comment.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent.UserID != nil && !sameUser(parent.UserID, comment.UserID) {
+				replyTo = parent.UserID
+				n := base
+				n.RecipientID, n.Type = *parent.UserID, models.NotificationCommentReply
+				notifications = append(notifications, &n)
+			}
+		}
+		if !sameUser(&article.AuthorID, comment.UserID) && !sameUser(&article.AuthorID, replyTo) {
+			n := base
+			n.RecipientID, n.Type = article.AuthorID, models.NotificationCommentOnArticle
+			notifications = append(notifications, &n)
+		}
+		return notifications, nil
+	})
+}
+
+// ArticleReviewed 待审核的文章被发布或退回后，在后台通知文章作者
+// approved: 是否审核通过（发布）
+func (s *NotificationService) ArticleReviewed(ctx context.Context, article *models.Article, approved bool) {
+	actorID := contextUserID(ctx)
+	if sameUser(&article.AuthorID, actorID) {
+		return
+	}
+
+	n := &models.Notification{
+		RecipientID: article.AuthorID,
+		Type:        models.NotificationArticleRejected,
+		ActorID:     actorID,
+		SubjectType: models.NotificationSubjectArticle,
+		SubjectID:   article.ID,
+		ArticleID:   &article.ID,
+		Summary:     truncateRunes(article.Title, notificationSummaryLength),
+	}
+	if approved {
+		n.Type = models.NotificationArticleApproved
+	}
+	s.run(ctx, "article_reviewed", func(context.Context) ([]*models.Notification, error) {
+		return []*models.Notification{n}, nil
+	})
+}
+
+// run 在后台生成并写入通知，失败只记录日志
+func (s *NotificationService) run(ctx context.Context, event string, build func(ctx context.Context) ([]*models.Notification, error)) {
+	ctx = context.WithoutCancel(ctx)
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		l := logger.FromContext(ctx, "notification")
+
+		notifications, err := build(ctx)
+		if err != nil {
+			l.Warn().Err(err).Str("event", event).Msg("Failed to prepare notifications")
+			return
+		}
+		for _, n := range notifications {
+			if err := s.notificationRepo.Create(ctx, n); err != nil {
+				l.Warn().Err(err).Str("event", event).Str("recipient_id", n.RecipientID.String()).Msg("Failed to create notification")
+				continue
+			}
+			adjustUnreadCount(ctx, n.RecipientID, 1)
+		}
+	}()
+}
+
+// adjustUnreadCount 增减已缓存的未读数，失败时删除缓存（下次读取从数据库重建）
+func adjustUnreadCount(ctx context.Context, userID uuid.UUID, delta int) {
+	if database.RedisClient == nil {
+		return
+	}
+	writeCtx, cancel := redisWriteContext(ctx)
+	defer cancel()
+	key := redisNotificationUnreadPrefix + userID.String()
+	if err := adjustUnreadScript.Run(writeCtx, database.RedisClient, []string{key}, strconv.Itoa(delta)).Err(); err != nil {
+		_ = database.RedisClient.Del(writeCtx, key).Err()
+	}
+}
+
+// sameUser 判断两个可选的用户 ID 是否为同一用户（任一为空时视为不同）
+func sameUser(a, b *uuid.UUID) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// truncateRunes 截断到最多 n 个字符，超出时追加省略号
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+// notifyCommentApproved 通过当前进程的通知服务发送评论通知，未注册时忽略
+func notifyCommentApproved(ctx context.Context, comment *models.Comment) {
+	if s := defaultNotifications.Load(); s != nil {
+		s.CommentApproved(ctx, comment)
+	}
+}
+
+// notifyArticleReviewed 通过当前进程的通知服务发送审核结果通知，未注册时忽略
+func notifyArticleReviewed(ctx context.Context, article *models.Article, approved bool) {
+	if s := defaultNotifications.Load(); s != nil {
+		s.ArticleReviewed(ctx, article, approved)
+	}
+}
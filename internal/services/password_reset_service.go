@@ -0,0 +1,193 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+)
+
+const (
+	// passwordResetTTL 找回密码链接有效期
+	passwordResetTTL = 30 * time.Minute
+	// passwordResetLimit / passwordResetWindow 同一邮箱在时间窗口内最多发送的找回密码邮件数
+	passwordResetLimit  = 3
+	passwordResetWindow = time.Hour
+	// passwordResetSendTimeout 找回密码邮件的发送超时时间
+	passwordResetSendTimeout = 30 * time.Second
+)
+
+// ErrInvalidResetToken 找回密码令牌无效、已使用或已过期
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// PasswordResetService 通过邮件找回密码
+//
+// 设计考虑：
+// - 令牌为 32 字节随机数，只保存 SHA-256 哈希，30 分钟有效，只能使用一次
+// - 申请接口无论邮箱是否存在、是否超过频率限制都返回相同结果，避免通过接口探测邮箱是否注册
+// - 同一邮箱每小时最多发送 3 封，超过后不再发送
+// - 重置成功后作废该用户其他未使用的令牌，并吊销所有刷新 token，使已登录的会话失效
+type PasswordResetService struct {
+	resetRepo   *repository.PasswordResetRepository
+	userRepo    *repository.UserRepository
+	refreshRepo *repository.RefreshTokenRepository
+	sender      EmailSender
+	frontendURL string
+
+	inflight sync.WaitGroup
+}
+
+// NewPasswordResetService 创建新的找回密码服务实例
+// resetRepo: 找回密码令牌数据访问层仓库
+// userRepo: 用户数据访问层仓库
+// refreshRepo: 刷新 token 登记仓库，重置密码后吊销刷新 token
+// sender: 邮件发送器
+// server: 提供重置链接使用的前端地址（前端 /reset-password 页面调用重置接口）
+func NewPasswordResetService(resetRepo *repository.PasswordResetRepository, userRepo *repository.UserRepository, refreshRepo *repository.RefreshTokenRepository, sender EmailSender, server config.ServerConfig) *PasswordResetService {
+	return &PasswordResetService{
+		resetRepo:   resetRepo,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+		sender:      sender,
+		frontendURL: strings.TrimRight(server.FrontendURL, "/"),
+	}
+}
+
+// RequestReset 申请找回密码，向邮箱发送重置链接（后台发送）
+// email: 注册邮箱
+// 返回: 只有数据库错误时返回错误；邮箱不存在、账号已被禁用或超过频率限制时不发送，同样返回 nil
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// 已被禁用的账号即使重置密码也不能登录
+	if user.Status != "active" && user.Status != "pending" {
+		return nil
+	}
+
+	count, err := s.resetRepo.CountSince(ctx, user.ID, time.Now().Add(-passwordResetWindow))
+	if err != nil {
+		return fmt.Errorf("failed to check recent password resets: %w", err)
+	}
+	if count >= passwordResetLimit {
+		l := logger.FromContext(ctx, "password_reset")
+		l.Warn().Str("user_id", user.ID.String()).Msg("Password reset requested too frequently, email not sent")
+		return nil
+	}
+
+	token, tokenHash, err := generateConfirmToken()
+	if err != nil {
+		return err
+	}
+	reset := &models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := s.resetRepo.Create(ctx, reset); err != nil {
+		return fmt.Errorf("failed to save password reset token: %w", err)
+	}
+
+	html, err := renderNewsletterTemplate(passwordResetEmailTemplate, map[string]string{
+		"Username":  user.Username,
+		"ResetURL":  s.frontendURL + "/reset-password?token=" + token,
+		"ExpiresIn": passwordResetTTL.String(),
+	})
+	if err != nil {
+		return err
+	}
+	msg := &EmailMessage{To: []string{user.Email}, Subject: "重置您的密码", HTML: html}
+
+	ctx = context.WithoutCancel(ctx)
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		sendCtx, cancel := context.WithTimeout(ctx, passwordResetSendTimeout)
+		defer cancel()
+		if err := s.sender.Send(sendCtx, msg); err != nil {
+			l := logger.FromContext(ctx, "password_reset")
+			l.Warn().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send password reset email")
+		}
+	}()
+	return nil
+}
+
+// ResetPassword 使用找回密码令牌设置新密码
+// 返回: 令牌不存在、已使用或已过期时返回 ErrInvalidResetToken
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	reset, err := s.resetRepo.GetByTokenHash(ctx, hashConfirmToken(token))
+	if errors.Is(err, repository.ErrPasswordResetNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+	// 并发使用同一令牌时只有一个请求成功
+	marked, err := s.resetRepo.MarkUsed(ctx, reset.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrInvalidResetToken
+	}
+
+	user := &models.User{Password: newPassword}
+	if err := user.HashPassword(); err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, reset.UserID, user.Password); err != nil {
+		// 申请后账号已被删除
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if err := s.resetRepo.InvalidateUnused(ctx, reset.UserID); err != nil {
+		return err
+	}
+	return s.refreshRepo.RevokeAllForUser(ctx, reset.UserID)
+}
+
+// Wait 等待后台发送中的找回密码邮件完成（关闭服务和测试时使用）
+func (s *PasswordResetService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var passwordResetEmailTemplate = template.Must(template.New("reset").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+<p>{{.Username}}，您好，我们收到了重置您账号密码的请求。</p>
+<p><a href="{{.ResetURL}}">点击此处设置新密码</a>（{{.ExpiresIn}} 内有效，只能使用一次）</p>
+<p style="color: #999;">如果这不是您本人的操作，请忽略此邮件，您的密码不会改变。</p>
+</body>
+</html>
+`))
@@ -0,0 +1,142 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/jwt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultPreviewTTL 草稿预览 token 的默认有效期
+const defaultPreviewTTL = 24 * time.Hour
+
+var (
+	// ErrInvalidPreviewToken 预览 token 签名错误、已过期、已吊销或与文章不符
+	ErrInvalidPreviewToken = errors.New("invalid or expired preview token")
+	// ErrPreviewTokenNotFound 要吊销的预览 token 不存在（已过期或已吊销）
+	ErrPreviewTokenNotFound = errors.New("preview token not found")
+)
+
+// PreviewService 草稿预览服务：作者为未发布的文章生成只读的分享链接
+//
+// 设计考虑：
+// - token 复用 JWT 签名，类型为 preview 并携带文章 ID，不能作为访问 token 使用，因此不能用于任何写操作
+// - 生成时在 Redis 中登记 jti（过期时间与 token 相同），预览时必须能查到登记记录，删除登记即吊销
+// - Redis 不可用时无法登记和校验吊销状态，生成和预览都直接失败，不降级
+type PreviewService struct {
+	articleRepo *repository.ArticleRepository
+	jwtMgr      *jwt.JWTManager
+}
+
+// NewPreviewService 创建新的草稿预览服务实例
+func NewPreviewService(articleRepo *repository.ArticleRepository, jwtMgr *jwt.JWTManager) *PreviewService {
+	return &PreviewService{
+		articleRepo: articleRepo,
+		jwtMgr:      jwtMgr,
+	}
+}
+
+// Issue 为文章生成预览 token
+// userID: 当前用户ID
+// canShareOthers: 是否可以为他人的文章生成（管理员），为 false 时只能为自己的文章生成，否则返回 ErrNotArticleAuthor
+// ttl: 有效期，<= 0 时使用默认的 24 小时
+// 返回: token、jti 和过期时间；Redis 不可用时返回 database.ErrRedisUnavailable
+func (s *PreviewService) Issue(ctx context.Context, articleID, userID uuid.UUID, canShareOthers bool, ttl time.Duration) (*models.ArticlePreviewToken, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !canShareOthers && article.AuthorID != userID {
+		return nil, ErrNotArticleAuthor
+	}
+	if database.RedisClient == nil {
+		return nil, database.ErrRedisUnavailable
+	}
+	if ttl <= 0 {
+		ttl = defaultPreviewTTL
+	}
+
+	token, claims, err := s.jwtMgr.GeneratePreviewToken(article.ID, userID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	jti, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	rctx, cancel := redisWriteContext(ctx)
+	defer cancel()
+	if err := database.RedisClient.Set(rctx, redisArticlePreviewPrefix+jti.String(), article.ID.String(), ttl).Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.ArticlePreviewToken{
+		Token:     token,
+		JTI:       jti,
+		ArticleID: article.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// Preview 按预览 token 获取文章，不论文章状态
+// 返回: token 无效、已过期或已吊销时返回 ErrInvalidPreviewToken；文章已删除时返回 ErrArticleNotFound
+// 注意: 直接读取数据库，不经过详情缓存，也不计入浏览量
+func (s *PreviewService) Preview(ctx context.Context, token string) (*models.Article, error) {
+	claims, err := s.jwtMgr.ValidatePreviewToken(token)
+	if err != nil {
+		return nil, ErrInvalidPreviewToken
+	}
+	if database.RedisClient == nil {
+		return nil, database.ErrRedisUnavailable
+	}
+
+	rctx, cancel := redisReadContext(ctx)
+	defer cancel()
+	stored, err := database.RedisClient.Get(rctx, redisArticlePreviewPrefix+claims.ID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidPreviewToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored != claims.ArticleID.String() {
+		return nil, ErrInvalidPreviewToken
+	}
+	return s.articleRepo.GetByID(ctx, *claims.ArticleID)
+}
+
+// Revoke 吊销文章的预览 token
+// canShareOthers: 同 Issue
+// 返回: token 不存在、已过期或不属于该文章时返回 ErrPreviewTokenNotFound
+func (s *PreviewService) Revoke(ctx context.Context, articleID, jti, userID uuid.UUID, canShareOthers bool) error {
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return err
+	}
+	if !canShareOthers && article.AuthorID != userID {
+		return ErrNotArticleAuthor
+	}
+	if database.RedisClient == nil {
+		return database.ErrRedisUnavailable
+	}
+
+	key := redisArticlePreviewPrefix + jti.String()
+	rctx, cancel := redisWriteContext(ctx)
+	defer cancel()
+	stored, err := database.RedisClient.Get(rctx, key).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && stored != article.ID.String()) {
+		return ErrPreviewTokenNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return database.RedisClient.Del(rctx, key).Err()
+}
@@ -0,0 +1,91 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"unicode"
+
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// 阅读速度：英文等以空格分词的文字按每分钟 200 个单词，中日韩文字按每分钟 400 个字
+const (
+	readingWordsPerMinute = 200
+	readingCJKPerMinute   = 400
+)
+
+// readingStatsBatchSize 回填阅读统计时每批读取的文章数
+const readingStatsBatchSize = 500
+
+// ReadingStats 统计正文字数和预计阅读时长
+// content: 文章正文（Markdown，统计前去掉标记，规则同 GenerateExcerpt）
+// 返回: 字数（中日韩文字每个字计 1，其他文字按空白分隔的单词计）和阅读分钟数（向上取整，正文非空时至少 1 分钟）
+func ReadingStats(content string) (wordCount, readingMinutes int) {
+	var words, cjk int
+	inWord := false
+	for _, r := range stripMarkup(content) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			// 只含标点的片段（如单独的破折号）不算单词
+			if !inWord {
+				words++
+				inWord = true
+			}
+		}
+	}
+
+	wordCount = words + cjk
+	if wordCount == 0 {
+		return 0, 0
+	}
+	// 两种速度折算为同一单位（1/400 分钟 = 2 个单词或 1 个字）后向上取整
+	units := words*readingCJKPerMinute/readingWordsPerMinute + cjk
+	readingMinutes = (units + readingCJKPerMinute - 1) / readingCJKPerMinute
+	return wordCount, readingMinutes
+}
+
+// applyReadingStats 根据正文计算文章的字数和阅读时长（保存前调用）
+func applyReadingStats(article *models.Article) {
+	article.WordCount, article.ReadingTimeMinutes = ReadingStats(article.Content)
+}
+
+// BackfillReadingStats 为已有文章重新计算字数和阅读时长（上线该功能前保存的文章为 0）
+// 返回: 数值有变化而被更新的文章数
+// 注意: 按 ID 分批处理，不修改 updated_at 和版本号；完成后清理文章缓存。Elasticsearch 中的文档需重建索引后更新
+func (s *ArticleService) BackfillReadingStats(ctx context.Context) (int, error) {
+	updated := 0
+	after := uuid.Nil
+	for {
+		articles, err := s.articleRepo.ListForIndexing(ctx, nil, after, readingStatsBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		for _, article := range articles {
+			words, minutes := ReadingStats(article.Content)
+			if words == article.WordCount && minutes == article.ReadingTimeMinutes {
+				continue
+			}
+			if err := s.articleRepo.UpdateReadingStats(ctx, article.ID, words, minutes); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		if len(articles) < readingStatsBatchSize {
+			break
+		}
+		after = articles[len(articles)-1].ID
+	}
+
+	if updated > 0 {
+		_, _ = FlushCache(ctx, CacheScopeArticleDetail)
+		_, _ = FlushCache(ctx, CacheScopeArticleList)
+	}
+	return updated, nil
+}
@@ -0,0 +1,194 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrReindexRunning 已有索引重建任务在执行
+	ErrReindexRunning = errors.New("a reindex job is already running")
+	// ErrReindexJobNotFound 索引重建任务不存在或已过期
+	ErrReindexJobNotFound = errors.New("reindex job not found")
+)
+
+const (
+	reindexBatchSize = 200
+	reindexLockTTL   = 2 * time.Minute
+	reindexJobTTL    = 24 * time.Hour
+	reindexTimeout   = 2 * time.Hour
+)
+
+// ReindexService 搜索索引重建服务
+//
+// 设计考虑：
+// - 通过 Redis 锁保证同一时间只有一个重建任务（跨实例）
+// - 任务进度保存在 Redis 中，任意实例都可以查询
+// - 按 ID 游标分批读取文章并调用 bulk API，避免长时间占用数据库连接
+type ReindexService struct {
+	articleRepo *repository.ArticleRepository
+}
+
+// NewReindexService 创建新的索引重建服务实例
+// articleRepo: 文章数据访问层仓库
+func NewReindexService(articleRepo *repository.ArticleRepository) *ReindexService {
+	return &ReindexService{
+		articleRepo: articleRepo,
+	}
+}
+
+// Start 异步启动索引重建任务
+// since: 仅重建该时间之后更新过的文章（增量），nil 表示全量重建
+// 返回: 新建的任务信息，如果已有任务在执行则返回 ErrReindexRunning
+// 注意: 依赖 Redis（锁和任务状态）以及已启用的 Elasticsearch
+func (s *ReindexService) Start(ctx context.Context, since *time.Time) (*models.ReindexJob, error) {
+	if !search.Enabled() {
+		return nil, search.ErrSearchDisabled
+	}
+
+	token, ok, err := database.AcquireLock(ctx, redisReindexLockKey, reindexLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReindexRunning
+	}
+
+	total, err := s.articleRepo.CountForIndexing(ctx, since)
+	if err != nil {
+		_ = database.ReleaseLock(ctx, redisReindexLockKey, token)
+		return nil, err
+	}
+
+	job := &models.ReindexJob{
+		ID:        uuid.New(),
+		State:     models.ReindexJobRunning,
+		Since:     since,
+		Total:     total,
+		StartedAt: time.Now(),
+	}
+	if err := saveReindexJob(ctx, job); err != nil {
+		_ = database.ReleaseLock(ctx, redisReindexLockKey, token)
+		return nil, err
+	}
+
+	go s.run(job, token)
+
+	return job, nil
+}
+
+// GetJob 查询索引重建任务进度
+func (s *ReindexService) GetJob(ctx context.Context, id uuid.UUID) (*models.ReindexJob, error) {
+	if database.RedisClient == nil {
+		return nil, database.ErrRedisUnavailable
+	}
+	val, err := database.RedisClient.Get(ctx, redisReindexJobPrefix+id.String()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrReindexJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job models.ReindexJob
+	if err := json.Unmarshal(val, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *ReindexService) run(job *models.ReindexJob, token string) {
+	defer startBackgroundJob(JobKindSearchReindex)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), reindexTimeout)
+	defer cancel()
+	defer func() {
+		_ = database.ReleaseLock(context.Background(), redisReindexLockKey, token)
+	}()
+
+	l := logger.GetLogger()
+	l.Info().Str("job_id", job.ID.String()).Int64("total", job.Total).Msg("Search reindex started")
+
+	err := s.indexAll(ctx, job, token)
+
+	now := time.Now()
+	job.FinishedAt = &now
+	if err != nil {
+		job.State = models.ReindexJobFailed
+		job.Error = err.Error()
+		l.Error().Err(err).Str("job_id", job.ID.String()).Msg("Search reindex failed")
+	} else {
+		job.State = models.ReindexJobCompleted
+		l.Info().Str("job_id", job.ID.String()).
+			Int64("processed", job.Processed).
+			Int64("failed", job.Failed).
+			Msg("Search reindex completed")
+	}
+	_ = saveReindexJob(context.Background(), job)
+}
+
+func (s *ReindexService) indexAll(ctx context.Context, job *models.ReindexJob, token string) error {
+	afterID := uuid.Nil
+	for {
+		articles, err := s.articleRepo.ListForIndexing(ctx, job.Since, afterID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		indexed, failed, err := search.BulkIndexArticles(ctx, articles)
+		if err != nil {
+			// 整批请求失败，计入失败数后继续下一批
+			l := logger.GetLogger()
+			l.Warn().Err(err).Str("job_id", job.ID.String()).Int("batch", len(articles)).Msg("Search reindex batch failed")
+			failed = len(articles)
+			indexed = 0
+		}
+		job.Processed += int64(indexed + failed)
+		job.Failed += int64(failed)
+		afterID = articles[len(articles)-1].ID
+
+		l := logger.GetLogger()
+		l.Info().Str("job_id", job.ID.String()).
+			Int64("processed", job.Processed).
+			Int64("failed", job.Failed).
+			Int64("total", job.Total).
+			Msg("Search reindex progress")
+
+		// 续期锁并保存进度；锁已丢失说明任务超时被其他实例接管，停止执行
+		if held, err := database.RefreshLock(ctx, redisReindexLockKey, token, reindexLockTTL); err != nil || !held {
+			return errors.New("reindex lock lost")
+		}
+		if err := saveReindexJob(ctx, job); err != nil {
+			return err
+		}
+
+		if len(articles) < reindexBatchSize {
+			return nil
+		}
+	}
+}
+
+func saveReindexJob(ctx context.Context, job *models.ReindexJob) error {
+	if database.RedisClient == nil {
+		return database.ErrRedisUnavailable
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return database.RedisClient.Set(ctx, redisReindexJobPrefix+job.ID.String(), data, reindexJobTTL).Err()
+}
@@ -0,0 +1,64 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"sync"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/pkg/logger"
+)
+
+// ReloadService 配置热加载服务（SIGHUP 或管理接口触发）
+//
+// 设计考虑：
+// - 重新执行 config 加载，只应用可在运行中修改的配置（见 config.Reload），其余修改记录为需要重启
+// - 日志级别、跨域来源、限流等运行时组件通过 OnReload 注册的回调更新
+// - 同一时间只执行一次加载，避免并发信号和请求交错应用
+type ReloadService struct {
+	mu        sync.Mutex
+	callbacks []func(cfg *config.Config) error
+}
+
+// NewReloadService 创建新的配置热加载服务实例
+func NewReloadService() *ReloadService {
+	return &ReloadService{}
+}
+
+// OnReload 注册配置重新加载后的回调，按注册顺序执行
+// 注意: 回调返回的错误只记录日志，不影响其他回调
+func (s *ReloadService) OnReload(fn func(cfg *config.Config) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, fn)
+}
+
+// Reload 重新加载配置并应用到运行时组件
+// 返回: 已生效和需要重启的配置键；配置无效时返回错误，当前配置保持不变
+func (s *ReloadService) Reload(ctx context.Context) (*config.ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := logger.FromContext(ctx)
+	result, err := config.Reload()
+	if err != nil {
+		l.Error().Err(err).Msg("Config reload failed, keeping current configuration")
+		return nil, err
+	}
+
+	cfg := config.Get()
+	for _, fn := range s.callbacks {
+		if err := fn(cfg); err != nil {
+			l.Warn().Err(err).Msg("Failed to apply reloaded configuration")
+		}
+	}
+
+	event := l.Info()
+	if len(result.Skipped) > 0 {
+		event = l.Warn()
+	}
+	event.Strs("applied", result.Applied).
+		Strs("restart_required", result.Skipped).
+		Msg("Configuration reloaded")
+	return result, nil
+}
@@ -0,0 +1,180 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/cron"
+	"enterprise-blog/pkg/logger"
+)
+
+// ErrNoReportRecipients 未配置报表收件人
+var ErrNoReportRecipients = errors.New("no report recipients configured")
+
+const (
+	// weeklyReportLockTTL 同一触发时间只允许一个实例发送；锁不主动释放，过期前其他实例不会重复发送
+	weeklyReportLockTTL = 6 * time.Hour
+	weeklyReportTimeout = 2 * time.Minute
+)
+
+// ReportService 定时统计报表服务
+//
+// 设计考虑：
+// - 统计数据复用仪表盘的聚合查询（DashboardService）
+// - 多实例部署时每个实例都会按 cron 触发，通过 Redis 锁（按触发时间区分）保证只发送一次
+// - 发信通过 EmailSender 抽象，未配置 SMTP 时只写日志
+type ReportService struct {
+	dashboardService *DashboardService
+	sender           EmailSender
+	recipients       []string
+}
+
+// NewReportService 创建新的报表服务实例
+// dashboardService: 仪表盘服务，提供统计聚合
+// sender: 邮件发送器
+// recipients: 周报默认收件人
+func NewReportService(dashboardService *DashboardService, sender EmailSender, recipients []string) *ReportService {
+	return &ReportService{
+		dashboardService: dashboardService,
+		sender:           sender,
+		recipients:       recipients,
+	}
+}
+
+// SendWeekly 生成上一个自然周（周一至周日）的统计并发送邮件
+// recipients: 收件人，为空时使用配置的默认收件人
+// 返回: 发送的报表内容
+func (s *ReportService) SendWeekly(ctx context.Context, recipients []string) (*models.WeeklyReport, error) {
+	if len(recipients) == 0 {
+		recipients = s.recipients
+	}
+	if len(recipients) == 0 {
+		return nil, ErrNoReportRecipients
+	}
+
+	from, to := LastWeekRange(time.Now().In(config.Location()))
+	report, err := s.dashboardService.WeeklyReport(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := RenderWeeklyReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &EmailMessage{
+		To:      recipients,
+		Subject: "博客周报 " + from.Format("2006-01-02") + " ~ " + to.AddDate(0, 0, -1).Format("2006-01-02"),
+		HTML:    html,
+	}
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RunWeeklySchedule 按 cron 表达式定时发送周报，直到 ctx 结束
+// schedule: 已解析的 cron 表达式
+func (s *ReportService) RunWeeklySchedule(ctx context.Context, schedule *cron.Schedule) {
+	l := logger.GetLogger()
+	for {
+		// cron 表达式按业务时区解释（如 0 8 * * 1 为业务时区周一 08:00）
+		next := schedule.Next(time.Now().In(config.Location()))
+		if next.IsZero() {
+			l.Warn().Msg("Weekly report schedule never fires, scheduler stopped")
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runScheduled(next)
+	}
+}
+
+func (s *ReportService) runScheduled(fireAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), weeklyReportTimeout)
+	defer cancel()
+
+	l := logger.GetLogger()
+	_, ok, err := database.AcquireLock(ctx, redisWeeklyReportLock+fireAt.Format("200601021504"), weeklyReportLockTTL)
+	if err != nil {
+		l.Warn().Err(err).Msg("Failed to acquire weekly report lock, skipped")
+		return
+	}
+	if !ok {
+		// 其他实例已发送
+		return
+	}
+
+	done := startBackgroundJob(JobKindWeeklyReport)
+	report, err := s.SendWeekly(ctx, nil)
+	done()
+	if err != nil {
+		l.Error().Err(err).Msg("Failed to send weekly report")
+		return
+	}
+	l.Info().
+		Time("from", report.From).
+		Int("recipients", len(s.recipients)).
+		Msg("Weekly report sent")
+}
+
+// LastWeekRange 返回 now 之前最近一个完整自然周的时间范围 [周一 00:00, 下周一 00:00)，按 now 所在时区计算
+func LastWeekRange(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Go 中周日为 0，换算为距本周一的天数
+	offset := (int(today.Weekday()) + 6) % 7
+	thisMonday := today.AddDate(0, 0, -offset)
+	return thisMonday.AddDate(0, 0, -7), thisMonday
+}
+
+var weeklyReportTemplate = template.Must(template.New("weekly").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+	"prevDay": func(t time.Time) time.Time {
+		return t.AddDate(0, 0, -1)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+<h2>博客周报（{{date .From}} ~ {{date (prevDay .To)}}）</h2>
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr><td>新增用户</td><td><b>{{.Counts.NewUsers}}</b></td></tr>
+<tr><td>发布文章</td><td><b>{{.Counts.PublishedArticles}}</b></td></tr>
+<tr><td>文章浏览量</td><td><b>{{.Counts.Views}}</b></td></tr>
+</table>
+<h3>浏览量前 5 的文章</h3>
+{{if .TopArticles}}<ol>
+{{range .TopArticles}}<li>{{.Title}}（{{.Value}} 次浏览）</li>
+{{end}}</ol>{{else}}<p>本周暂无浏览数据</p>{{end}}
+<h3>待处理积压</h3>
+<ul>
+<li>待审核评论：{{.PendingComments}}</li>
+<li>被举报评论：{{.ReportedComments}}</li>
+<li>待审核文章：{{.ReviewArticles}}</li>
+</ul>
+</body>
+</html>
+`))
+
+// RenderWeeklyReport 将周报渲染为 HTML（文章标题等内容会被转义）
+func RenderWeeklyReport(report *models.WeeklyReport) (string, error) {
+	var buf bytes.Buffer
+	if err := weeklyReportTemplate.Execute(&buf, report); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,435 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnknownSetting 设置项不存在
+	ErrUnknownSetting = errors.New("unknown setting")
+	// ErrInvalidSettingValue 设置值类型或取值范围不合法
+	ErrInvalidSettingValue = errors.New("invalid setting value")
+)
+
+// settingDefinition 设置项定义：类型、默认值（来自 config）和取值校验
+type settingDefinition struct {
+	Type     models.SettingType
+	Default  func(site config.SiteConfig) string
+	Validate func(value string) error
+}
+
+var settingDefinitions = map[string]settingDefinition{
+	models.SettingCommentModeration: {
+		Type:    models.SettingTypeBool,
+		Default: func(site config.SiteConfig) string { return strconv.FormatBool(site.CommentModeration) },
+	},
+	models.SettingRegistrationMode: {
+		Type:    models.SettingTypeString,
+		Default: func(site config.SiteConfig) string { return site.RegistrationMode },
+		Validate: func(v string) error {
+			if v != models.RegistrationOpen && v != models.RegistrationClosed {
+				return fmt.Errorf("must be one of %s, %s", models.RegistrationOpen, models.RegistrationClosed)
+			}
+			return nil
+		},
+	},
+	models.SettingExcerptLength: {
+		Type:     models.SettingTypeInt,
+		Default:  func(site config.SiteConfig) string { return strconv.Itoa(site.ExcerptLength) },
+		Validate: intRange(20, 2000),
+	},
+	models.SettingArticleDetailCacheTTL: {
+		Type:     models.SettingTypeInt,
+		Default:  func(site config.SiteConfig) string { return strconv.Itoa(site.ArticleDetailCacheTTL) },
+		Validate: intRange(1, 3600),
+	},
+	models.SettingArticleListCacheTTL: {
+		Type:     models.SettingTypeInt,
+		Default:  func(site config.SiteConfig) string { return strconv.Itoa(site.ArticleListCacheTTL) },
+		Validate: intRange(1, 3600),
+	},
+	models.SettingAuditLogRetentionDays: {
+		Type:     models.SettingTypeInt,
+		Default:  func(site config.SiteConfig) string { return strconv.Itoa(site.AuditLogRetentionDays) },
+		Validate: intRange(1, 3650),
+	},
+	models.SettingContentFilterPolicy: {
+		Type:    models.SettingTypeString,
+		Default: func(site config.SiteConfig) string { return site.ContentFilterPolicy },
+		Validate: func(v string) error {
+			if v != models.ContentFilterReject && v != models.ContentFilterReplace && v != models.ContentFilterReview {
+				return fmt.Errorf("must be one of %s, %s, %s", models.ContentFilterReject, models.ContentFilterReplace, models.ContentFilterReview)
+			}
+			return nil
+		},
+	},
+	models.SettingAutoSummary: {
+		Type:    models.SettingTypeBool,
+		Default: func(site config.SiteConfig) string { return strconv.FormatBool(site.AutoSummary) },
+	},
+	// 在敏感词文件之外追加的词表，格式与文件相同
+	models.SettingContentFilterWords: {
+		Type:     models.SettingTypeString,
+		Default:  func(config.SiteConfig) string { return "" },
+		Validate: validateWordList,
+	},
+	// 维护模式：开启后除健康检查等豁免路径和管理员外的请求都返回 503
+	models.SettingMaintenanceMode: {
+		Type:    models.SettingTypeBool,
+		Default: func(config.SiteConfig) string { return "false" },
+	},
+	// 维护期间返回给客户端的提示，为空时使用 DefaultMaintenanceMessage
+	models.SettingMaintenanceMessage: {
+		Type:    models.SettingTypeString,
+		Default: func(config.SiteConfig) string { return "" },
+		Validate: func(v string) error {
+			if utf8.RuneCountInString(v) > 500 {
+				return errors.New("must be at most 500 characters")
+			}
+			return nil
+		},
+	},
+	models.SettingMaintenanceRetryAfter: {
+		Type:     models.SettingTypeInt,
+		Default:  func(config.SiteConfig) string { return "300" },
+		Validate: intRange(1, 86400),
+	},
+}
+
+// DefaultMaintenanceMessage 未设置维护提示时返回的默认信息
+const DefaultMaintenanceMessage = "the service is temporarily unavailable for maintenance, please try again later"
+
+// defaultSettings 当前进程使用的设置服务，由 NewSettingsService 注册
+// 未注册时（例如单元测试）各设置项回退到 config 中的默认值
+var defaultSettings atomic.Pointer[SettingsService]
+
+// SettingsService 系统设置服务，提供运行时可调整参数的读取与修改
+//
+// 设计考虑：
+// - 设置值保存在 settings 表中，首次启动时用 config 中的值补齐缺失项
+// - 读取走进程内缓存，避免每次请求都访问数据库
+// - 修改后通过 Redis pub/sub 通知所有实例重新加载
+type SettingsService struct {
+	settingRepo *repository.SettingRepository
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewSettingsService 创建新的设置服务实例，并注册为当前进程的默认设置来源
+// settingRepo: 设置数据访问层仓库
+func NewSettingsService(settingRepo *repository.SettingRepository) *SettingsService {
+	s := &SettingsService{
+		settingRepo: settingRepo,
+		values:      make(map[string]string),
+	}
+	defaultSettings.Store(s)
+	return s
+}
+
+// Init 写入缺失的默认设置并加载到本地缓存
+func (s *SettingsService) Init(ctx context.Context) error {
+	site := siteDefaults()
+	defaults := make([]*models.Setting, 0, len(settingDefinitions))
+	for key, def := range settingDefinitions {
+		defaults = append(defaults, &models.Setting{Key: key, Value: def.Default(site), Type: def.Type})
+	}
+	if err := s.settingRepo.InsertDefaults(ctx, defaults); err != nil {
+		return fmt.Errorf("failed to seed settings: %w", err)
+	}
+	return s.Reload(ctx)
+}
+
+// Reload 从数据库重新加载全部设置到本地缓存
+// 注意: 类型不匹配或不再支持的设置项会被忽略，读取时回退到默认值
+func (s *SettingsService) Reload(ctx context.Context) error {
+	settings, err := s.settingRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		def, ok := settingDefinitions[setting.Key]
+		if !ok || validateSettingString(def, setting.Value) != nil {
+			continue
+		}
+		values[setting.Key] = setting.Value
+	}
+
+	s.mu.Lock()
+	s.values = values
+	s.mu.Unlock()
+	return nil
+}
+
+// List 获取全部设置项（按键名排序）
+// 返回: 设置列表，数据库中缺失的项以默认值补齐
+func (s *SettingsService) List(ctx context.Context) ([]*models.Setting, error) {
+	stored, err := s.settingRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*models.Setting, len(stored))
+	for _, setting := range stored {
+		byKey[setting.Key] = setting
+	}
+
+	result := make([]*models.Setting, 0, len(settingDefinitions))
+	for key, def := range settingDefinitions {
+		setting, ok := byKey[key]
+		if !ok {
+			setting = &models.Setting{Key: key, Type: def.Type}
+		}
+		setting.Value = s.Get(key)
+		result = append(result, setting)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// Update 批量修改设置
+// values: 键到新值的映射，值的 JSON 类型需与设置项类型一致
+// updatedBy: 操作人用户ID
+// 返回: 修改后的全部设置，如果存在未知键或非法值则整体不生效并返回错误
+// 注意: 修改成功后会通知其他实例刷新缓存
+func (s *SettingsService) Update(ctx context.Context, values map[string]interface{}, updatedBy *uuid.UUID) ([]*models.Setting, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: no settings provided", ErrInvalidSettingValue)
+	}
+
+	changes := make([]*models.Setting, 0, len(values))
+	for key, raw := range values {
+		def, ok := settingDefinitions[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+		}
+		value, err := normalizeSettingValue(def, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSettingValue, key, err)
+		}
+		changes = append(changes, &models.Setting{Key: key, Value: value, Type: def.Type})
+	}
+
+	if err := s.settingRepo.Upsert(ctx, changes, updatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+	publishSettingsChanged()
+
+	return s.List(ctx)
+}
+
+// SetMaintenance 开启或关闭维护模式，可同时修改提示信息和重试间隔
+// updatedBy: 操作人用户ID
+// 返回: 修改后的维护模式状态；取值不合法时返回 ErrInvalidSettingValue
+// 注意: 与 Update 相同，修改后通过 Redis 通知所有实例刷新
+func (s *SettingsService) SetMaintenance(ctx context.Context, req *models.MaintenanceUpdate, updatedBy *uuid.UUID) (*models.MaintenanceStatus, error) {
+	// 与 JSON 解码后的类型保持一致，复用 Update 的类型和范围校验
+	values := map[string]interface{}{models.SettingMaintenanceMode: *req.Enabled}
+	if req.Message != nil {
+		values[models.SettingMaintenanceMessage] = *req.Message
+	}
+	if req.RetryAfter != nil {
+		values[models.SettingMaintenanceRetryAfter] = float64(*req.RetryAfter)
+	}
+	if _, err := s.Update(ctx, values, updatedBy); err != nil {
+		return nil, err
+	}
+	status := CurrentMaintenance()
+	return &status, nil
+}
+
+// Get 读取设置值（字符串形式），未设置时返回 config 中的默认值
+func (s *SettingsService) Get(key string) string {
+	s.mu.RLock()
+	v, ok := s.values[key]
+	s.mu.RUnlock()
+	if ok {
+		return v
+	}
+	if def, ok := settingDefinitions[key]; ok {
+		return def.Default(siteDefaults())
+	}
+	return ""
+}
+
+// Subscribe 监听设置变更通知并刷新本地缓存，直到 ctx 取消
+// 注意: Redis 未初始化时直接返回，此时只有本实例的修改会立即生效
+func (s *SettingsService) Subscribe(ctx context.Context) {
+	if database.RedisClient == nil {
+		return
+	}
+
+	pubsub := database.RedisClient.Subscribe(ctx, redisSettingsChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+			reloadCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if err := s.Reload(reloadCtx); err != nil {
+				l := logger.GetLogger()
+				l.Warn().Err(err).Msg("failed to reload settings after change notification")
+			}
+			cancel()
+		}
+	}
+}
+
+func publishSettingsChanged() {
+	if database.RedisClient == nil {
+		return
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	if err := database.RedisClient.Publish(ctx, redisSettingsChannel, time.Now().Unix()).Err(); err != nil {
+		l := logger.GetLogger()
+		l.Warn().Err(err).Msg("failed to publish settings change notification")
+	}
+}
+
+// normalizeSettingValue 将 JSON 解码后的值转换为存储用的字符串，并做类型与范围校验
+func normalizeSettingValue(def settingDefinition, raw interface{}) (string, error) {
+	var value string
+	switch def.Type {
+	case models.SettingTypeBool:
+		b, ok := raw.(bool)
+		if !ok {
+			return "", errors.New("must be a boolean")
+		}
+		value = strconv.FormatBool(b)
+	case models.SettingTypeInt:
+		f, ok := raw.(float64)
+		if !ok || f != math.Trunc(f) {
+			return "", errors.New("must be an integer")
+		}
+		value = strconv.FormatInt(int64(f), 10)
+	case models.SettingTypeString:
+		str, ok := raw.(string)
+		if !ok {
+			return "", errors.New("must be a string")
+		}
+		value = str
+	default:
+		return "", fmt.Errorf("unsupported type %s", def.Type)
+	}
+
+	if def.Validate != nil {
+		if err := def.Validate(value); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// validateSettingString 校验数据库中已存储的字符串值
+func validateSettingString(def settingDefinition, value string) error {
+	switch def.Type {
+	case models.SettingTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return err
+		}
+	case models.SettingTypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return err
+		}
+	}
+	if def.Validate != nil {
+		return def.Validate(value)
+	}
+	return nil
+}
+
+func intRange(min, max int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		if n < min || n > max {
+			return fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// siteDefaults 返回 config 中的站点默认设置，配置未加载时使用内置默认值
+func siteDefaults() config.SiteConfig {
+	if current := config.Get(); current != nil {
+		return current.Site
+	}
+	return config.SiteConfig{
+		CommentModeration:     true,
+		RegistrationMode:      models.RegistrationOpen,
+		ExcerptLength:         200,
+		ArticleDetailCacheTTL: 60,
+		ArticleListCacheTTL:   120,
+		AuditLogRetentionDays: 90,
+		ContentFilterPolicy:   models.ContentFilterReject,
+	}
+}
+
+// settingString 读取字符串类型设置
+func settingString(key string) string {
+	if s := defaultSettings.Load(); s != nil {
+		return s.Get(key)
+	}
+	if def, ok := settingDefinitions[key]; ok {
+		return def.Default(siteDefaults())
+	}
+	return ""
+}
+
+// CurrentMaintenance 读取当前的维护模式状态（进程内缓存，开销很小，可在每个请求中调用）
+func CurrentMaintenance() models.MaintenanceStatus {
+	status := models.MaintenanceStatus{
+		Enabled:    settingBool(models.SettingMaintenanceMode),
+		Message:    settingString(models.SettingMaintenanceMessage),
+		RetryAfter: settingInt(models.SettingMaintenanceRetryAfter),
+	}
+	if status.Message == "" {
+		status.Message = DefaultMaintenanceMessage
+	}
+	return status
+}
+
+// settingBool 读取布尔类型设置
+func settingBool(key string) bool {
+	b, _ := strconv.ParseBool(settingString(key))
+	return b
+}
+
+// settingInt 读取整数类型设置
+func settingInt(key string) int {
+	n, _ := strconv.Atoi(settingString(key))
+	return n
+}
@@ -0,0 +1,235 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// sitemapMaxURLs 单个站点地图文件最多包含的地址数（sitemaps.org 协议上限）
+const sitemapMaxURLs = 50000
+
+// sitemapBatchSize 生成站点地图时每次从数据库读取的条数
+const sitemapBatchSize = 1000
+
+// sitemapCacheTTL 站点地图缓存时间；文章列表缓存清理时一并清理，这里只是兜底
+const sitemapCacheTTL = time.Hour
+
+// ErrSitemapNotFound 请求的站点地图分页不存在
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+// SitemapService 站点地图服务，列出已发布文章、分类和标签的地址
+//
+// 设计考虑：
+// - 文章地址与 SEO 信息中的 canonical 相同（server.frontend_url + /articles/<id>），分类和标签为 /categories/<slug>、/tags/<slug>
+// - 仓库只查询地址和更新时间两列，按键集分批读取
+// - 地址数超过 50000 时 /sitemap.xml 返回站点地图索引，各分页为 /sitemaps/<n>.xml（地址按 server.public_url 生成）
+// - 生成的 XML 缓存在 Redis 中，文章列表缓存清理时失效
+type SitemapService struct {
+	articleRepo  *repository.ArticleRepository
+	categoryRepo *repository.CategoryRepository
+	tagRepo      *repository.TagRepository
+	maxURLs      int
+}
+
+// NewSitemapService 创建新的站点地图服务实例
+func NewSitemapService(
+	articleRepo *repository.ArticleRepository,
+	categoryRepo *repository.CategoryRepository,
+	tagRepo *repository.TagRepository,
+) *SitemapService {
+	return &SitemapService{
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		maxURLs:      sitemapMaxURLs,
+	}
+}
+
+// WithMaxURLs 修改单个站点地图文件的地址数上限（默认 50000），返回服务本身
+func (s *SitemapService) WithMaxURLs(n int) *SitemapService {
+	if n > 0 {
+		s.maxURLs = n
+	}
+	return s
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+const sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// Sitemap 生成站点地图
+// page: 0 表示 /sitemap.xml；>= 1 表示地址数超出上限时拆分出的第 page 个分页
+// 返回: XML 文档；分页不存在时返回 ErrSitemapNotFound
+// 注意: 优先读取 Redis 缓存，缓存未命中时生成并写入缓存
+func (s *SitemapService) Sitemap(ctx context.Context, page int) ([]byte, error) {
+	key := redisSitemapPrefix + strconv.Itoa(page)
+	if cached, err := getSitemapFromCache(key); err == nil {
+		return cached, nil
+	}
+
+	urls, err := s.collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pages := (len(urls) + s.maxURLs - 1) / s.maxURLs
+	if pages == 0 {
+		pages = 1
+	}
+
+	var doc interface{}
+	switch {
+	case page == 0 && pages == 1:
+		doc = sitemapURLSet{XMLNS: sitemapXMLNS, URLs: urls}
+	case page == 0:
+		index := sitemapIndex{XMLNS: sitemapXMLNS}
+		for i := 1; i <= pages; i++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapURL{
+				Loc:     absoluteURL(serverConfig().PublicURL, "/sitemaps/"+strconv.Itoa(i)+".xml"),
+				LastMod: latestLastMod(s.pageURLs(urls, i)),
+			})
+		}
+		doc = index
+	case page <= pages && pages > 1:
+		doc = sitemapURLSet{XMLNS: sitemapXMLNS, URLs: s.pageURLs(urls, page)}
+	default:
+		return nil, ErrSitemapNotFound
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	cacheSitemap(key, data)
+	return data, nil
+}
+
+// collect 按文章、分类、标签的顺序分批读取全部地址
+func (s *SitemapService) collect(ctx context.Context) ([]sitemapURL, error) {
+	frontend := serverConfig().FrontendURL
+	var urls []sitemapURL
+
+	after := uuid.Nil
+	for {
+		entries, err := s.articleRepo.ListSitemapEntries(ctx, after, sitemapBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			urls = append(urls, sitemapEntryURL(frontend, "/articles/"+e.ID.String(), e.UpdatedAt))
+		}
+		if len(entries) < sitemapBatchSize {
+			break
+		}
+		after = entries[len(entries)-1].ID
+	}
+
+	taxonomies := []struct {
+		path string
+		list func(ctx context.Context, after string, limit int) ([]models.SitemapEntry, error)
+	}{
+		{"/categories/", s.categoryRepo.ListSitemapEntries},
+		{"/tags/", s.tagRepo.ListSitemapEntries},
+	}
+	for _, taxonomy := range taxonomies {
+		after := ""
+		for {
+			entries, err := taxonomy.list(ctx, after, sitemapBatchSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				urls = append(urls, sitemapEntryURL(frontend, taxonomy.path+url.PathEscape(e.Slug), e.UpdatedAt))
+			}
+			if len(entries) < sitemapBatchSize {
+				break
+			}
+			after = entries[len(entries)-1].Slug
+		}
+	}
+	return urls, nil
+}
+
+// pageURLs 返回第 page 个分页（从 1 开始）的地址
+func (s *SitemapService) pageURLs(urls []sitemapURL, page int) []sitemapURL {
+	start := (page - 1) * s.maxURLs
+	end := start + s.maxURLs
+	if end > len(urls) {
+		end = len(urls)
+	}
+	return urls[start:end]
+}
+
+func sitemapEntryURL(base, path string, updatedAt time.Time) sitemapURL {
+	u := sitemapURL{Loc: absoluteURL(base, path)}
+	if !updatedAt.IsZero() {
+		u.LastMod = updatedAt.UTC().Format(time.RFC3339)
+	}
+	return u
+}
+
+// latestLastMod 返回一组地址中最新的 lastmod（RFC3339 UTC 格式可以直接按字符串比较）
+func latestLastMod(urls []sitemapURL) string {
+	latest := ""
+	for _, u := range urls {
+		if u.LastMod > latest {
+			latest = u.LastMod
+		}
+	}
+	return latest
+}
+
+// serverConfig 返回服务配置，配置未加载时（单元测试）使用零值
+func serverConfig() config.ServerConfig {
+	if current := config.Get(); current != nil {
+		return current.Server
+	}
+	return config.ServerConfig{}
+}
+
+func getSitemapFromCache(key string) ([]byte, error) {
+	if database.RedisClient == nil {
+		return nil, database.ErrRedisUnavailable
+	}
+	ctx, cancel := redisReadContext(context.Background())
+	defer cancel()
+	return database.RedisClient.Get(ctx, key).Bytes()
+}
+
+func cacheSitemap(key string, data []byte) {
+	if database.RedisClient == nil {
+		return
+	}
+	ctx, cancel := redisWriteContext(context.Background())
+	defer cancel()
+	_ = database.RedisClient.Set(ctx, key, data, sitemapCacheTTL).Err()
+}
@@ -0,0 +1,62 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxSlugAttempts 遇到 slug 唯一约束冲突时的最大尝试次数（含第一次使用原始 slug）
+const maxSlugAttempts = 5
+
+// ErrSlugRetriesExhausted 追加数字后缀重试后 slug 仍然冲突
+var ErrSlugRetriesExhausted = errors.New("slug already exists")
+
+// WithUniqueSlug 以 base 作为 slug 调用 write；write 返回的错误被 isConflict 判定为 slug 唯一约束冲突时，
+// 依次改用 base-1、base-2… 重试
+// base: 原始 slug
+// isConflict: 判断错误是否为 slug 唯一约束冲突
+// write: 使用给定 slug 写入数据库（新建或改名）
+// 返回: 最终写入成功的 slug；重试用尽时返回 ErrSlugRetriesExhausted，其他错误原样返回
+func WithUniqueSlug(base string, isConflict func(error) bool, write func(slug string) error) (string, error) {
+	slug := base
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		err := write(slug)
+		if err == nil {
+			return slug, nil
+		}
+		if !isConflict(err) {
+			return "", err
+		}
+		slug = fmt.Sprintf("%s-%d", base, attempt)
+	}
+	return "", fmt.Errorf("%w after %d attempts", ErrSlugRetriesExhausted, maxSlugAttempts)
+}
+
+// isUniqueViolation 判断是否为指定唯一约束（或唯一索引）冲突
+// constraint: PostgreSQL 约束 / 索引名；SQLite 的表达式索引冲突同样报告索引名
+// columns: 普通列唯一索引在 SQLite 下报告的 表.列（如 articles.slug）
+// 注意: 目前使用字符串包含判断，兼容 pq/pgx 与 go-sqlite3 的错误文案
+func isUniqueViolation(err error, constraint string, columns ...string) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "duplicate key value violates unique constraint") {
+		return strings.Contains(msg, constraint)
+	}
+	// SQLite: UNIQUE constraint failed: articles.slug / UNIQUE constraint failed: index 'uniq_tags_slug_lower'
+	if !strings.Contains(msg, "UNIQUE constraint failed") {
+		return false
+	}
+	if strings.Contains(msg, "'"+constraint+"'") {
+		return true
+	}
+	for _, column := range columns {
+		if strings.Contains(msg, column) {
+			return true
+		}
+	}
+	return false
+}
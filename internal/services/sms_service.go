@@ -12,19 +12,28 @@ import (
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
 	"enterprise-blog/pkg/logger"
+	"enterprise-blog/pkg/metrics"
+)
+
+var (
+	// ErrSMSTooFrequent 同一手机号发送验证码过于频繁
+	ErrSMSTooFrequent = errors.New("sms code requested too frequently")
+	// ErrInvalidSMSCode 验证码无效或已过期
+	ErrInvalidSMSCode = errors.New("invalid or expired sms code")
 )
 
 // SMSService 短信服务，提供短信验证码相关的业务逻辑
 type SMSService struct {
 	smsRepo  *repository.SMSRepository
-	userRepo *repository.UserRepository
-	jwtMgr   interface{} // 占位，实际需要 JWTManager
+	userRepo UserRepositoryInterface
+	// jwtMgr LoginWithPhone 签发访问 token 使用，未设置时 LoginWithPhone 返回错误
+	jwtMgr TokenManager
 }
 
 // NewSMSService 创建新的短信服务实例
 // smsRepo: 短信验证码数据访问层仓库
 // userRepo: 用户数据访问层仓库，用于查找或创建用户
-func NewSMSService(smsRepo *repository.SMSRepository, userRepo *repository.UserRepository) *SMSService {
+func NewSMSService(smsRepo *repository.SMSRepository, userRepo UserRepositoryInterface) *SMSService {
 	return &SMSService{
 		smsRepo:  smsRepo,
 		userRepo: userRepo,
@@ -32,25 +41,33 @@ func NewSMSService(smsRepo *repository.SMSRepository, userRepo *repository.UserR
 }
 
 // SetJWTManager 设置 JWT 管理器（在初始化时调用）
-func (s *SMSService) SetJWTManager(jwtMgr interface{}) {
+func (s *SMSService) SetJWTManager(jwtMgr TokenManager) {
 	s.jwtMgr = jwtMgr
 }
 
-// LoginWithPhone 使用手机号和验证码登录，返回 token 和用户信息
-func (s *SMSService) LoginWithPhone(phone, code string) (string, *models.User, error) {
-	user, err := s.VerifyCode(phone, code)
+// LoginWithPhone 使用手机号和验证码登录，返回访问 token 和用户信息（密码已清除）
+// 注意: 需要先通过 SetJWTManager 设置 JWT 管理器（在校验验证码之前检查，不会消耗验证码）；
+// 只签发访问 token，HTTP 接口通过 UserService.IssueTokens 同时签发刷新 token
+func (s *SMSService) LoginWithPhone(ctx context.Context, phone, code string) (string, *models.User, error) {
+	if s.jwtMgr == nil {
+		return "", nil, errors.New("sms login: jwt manager not configured")
+	}
+	user, err := s.VerifyCode(ctx, phone, code)
 	if err != nil {
 		return "", nil, err
 	}
 
 	// 检查用户状态
 	if user.Status != "active" {
-		return "", nil, errors.New("user account is not active")
+		return "", nil, ErrAccountInactive
 	}
 
-	// 生成 JWT token（需要 JWTManager，这里先返回错误，由 handler 层处理）
-	// 实际应该在 handler 层调用 jwtMgr.GenerateToken
-	return "", user, nil
+	token, err := s.jwtMgr.GenerateToken(user.ID, user.Username, string(user.Role))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	user.Password = ""
+	return token, user, nil
 }
 
 /**
@@ -83,15 +100,15 @@ func (s *SMSService) LoginWithPhone(phone, code string) (string, *models.User, e
  * - 如何防止验证码被刷？（频率限制、IP限制）
  * - 验证码存储在哪里？（数据库持久化，Redis 加速验证）
  */
-func (s *SMSService) SendCode(phone string) error {
+func (s *SMSService) SendCode(ctx context.Context, phone string) error {
 	// 检查最近1分钟内是否已发送过验证码（防刷）
 	oneMinAgo := time.Now().Add(-1 * time.Minute)
-	count, err := s.smsRepo.GetRecentCodeCount(phone, oneMinAgo)
+	count, err := s.smsRepo.GetRecentCodeCount(ctx, phone, oneMinAgo)
 	if err != nil {
 		return fmt.Errorf("failed to check recent codes: %w", err)
 	}
 	if count >= 1 {
-		return errors.New("验证码发送过于频繁，请稍后再试")
+		return ErrSMSTooFrequent
 	}
 
 	// 生成6位数字验证码
@@ -107,21 +124,22 @@ func (s *SMSService) SendCode(phone string) error {
 		ExpiresAt: expiresAt,
 	}
 
-	if err := s.smsRepo.Create(smsCode); err != nil {
+	if err := s.smsRepo.Create(ctx, smsCode); err != nil {
 		return fmt.Errorf("failed to save sms code: %w", err)
 	}
+	metrics.RecordSMSCodeSent()
 
 	// 同时存储到 Redis（如果可用），用于快速验证
 	if database.RedisClient != nil {
 		key := fmt.Sprintf("sms:code:%s", phone)
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := redisWriteContext(ctx)
 		defer cancel()
 		_ = database.RedisClient.Set(ctx, key, code, 5*time.Minute).Err()
 	}
 
 	// TODO: 实际生产环境应调用短信服务商 API 发送短信
 	// 当前为模拟实现，仅在日志中输出验证码（开发/测试环境）
-	l := logger.GetLogger()
+	l := logger.FromContext(ctx)
 	l.Info().
 		Str("phone", phone).
 		Str("code", code).
@@ -131,56 +149,60 @@ func (s *SMSService) SendCode(phone string) error {
 }
 
 // VerifyCode 验证验证码并返回用户（如果存在则返回，不存在则自动创建）
-func (s *SMSService) VerifyCode(phone, code string) (*models.User, error) {
+func (s *SMSService) VerifyCode(ctx context.Context, phone, code string) (*models.User, error) {
 	// 先尝试从 Redis 快速验证
 	if database.RedisClient != nil {
 		key := fmt.Sprintf("sms:code:%s", phone)
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := redisReadContext(ctx)
 		defer cancel()
 		storedCode, err := database.RedisClient.Get(ctx, key).Result()
 		if err == nil && storedCode == code {
 			// Redis 验证通过，标记数据库中的验证码为已使用
-			smsCode, err := s.smsRepo.GetValidCode(phone, code)
+			smsCode, err := s.smsRepo.GetValidCode(ctx, phone, code)
 			if err == nil {
-				_ = s.smsRepo.MarkAsUsed(smsCode.ID)
+				_ = s.smsRepo.MarkAsUsed(ctx, smsCode.ID)
 			}
 			_ = database.RedisClient.Del(ctx, key).Err()
 
 			// 查找或创建用户
-			return s.findOrCreateUser(phone)
+			return s.findOrCreateUser(ctx, phone)
 		}
 	}
 
 	// 从数据库验证
-	smsCode, err := s.smsRepo.GetValidCode(phone, code)
+	smsCode, err := s.smsRepo.GetValidCode(ctx, phone, code)
 	if err != nil {
-		return nil, errors.New("验证码无效或已过期")
+		return nil, ErrInvalidSMSCode
 	}
 
 	// 标记为已使用
-	if err := s.smsRepo.MarkAsUsed(smsCode.ID); err != nil {
+	if err := s.smsRepo.MarkAsUsed(ctx, smsCode.ID); err != nil {
 		return nil, fmt.Errorf("failed to mark code as used: %w", err)
 	}
 
 	// 删除 Redis 中的验证码
 	if database.RedisClient != nil {
 		key := fmt.Sprintf("sms:code:%s", phone)
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := redisWriteContext(ctx)
 		defer cancel()
 		_ = database.RedisClient.Del(ctx, key).Err()
 	}
 
 	// 查找或创建用户
-	return s.findOrCreateUser(phone)
+	return s.findOrCreateUser(ctx, phone)
 }
 
 // findOrCreateUser 根据手机号查找用户，不存在则自动创建
-func (s *SMSService) findOrCreateUser(phone string) (*models.User, error) {
-	user, err := s.userRepo.GetByPhone(phone)
+// 注意: 自动创建等同于注册，registration_mode 为 closed 时返回 ErrRegistrationClosed
+func (s *SMSService) findOrCreateUser(ctx context.Context, phone string) (*models.User, error) {
+	user, err := s.userRepo.GetByPhone(ctx, phone)
 	if err == nil {
 		// 用户已存在
 		return user, nil
 	}
+	if settingString(models.SettingRegistrationMode) == models.RegistrationClosed {
+		return nil, ErrRegistrationClosed
+	}
 
 	// 用户不存在，自动创建
 	user = &models.User{
@@ -198,11 +220,17 @@ func (s *SMSService) findOrCreateUser(phone string) (*models.User, error) {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// 重新获取完整用户信息
-	return s.userRepo.GetByID(user.ID)
+	created, err := s.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RecordUserRegistration()
+	emitWebhookEvent(ctx, models.WebhookEventUserRegistered, newWebhookUser(created))
+	return created, nil
 }
 
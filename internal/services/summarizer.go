@@ -0,0 +1,148 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"enterprise-blog/internal/config"
+)
+
+// ErrSummarizerDisabled 未配置摘要生成服务
+var ErrSummarizerDisabled = errors.New("summarizer is not configured")
+
+// summarizerResponseLimit 读取摘要服务响应体的上限
+const summarizerResponseLimit = 1 << 20
+
+// Summarizer 文章摘要生成抽象，便于切换服务商或在测试中替换
+type Summarizer interface {
+	// Summarize 根据标题和正文生成不超过 maxLen 个字符的摘要
+	Summarize(ctx context.Context, title, content string, maxLen int) (string, error)
+}
+
+// NewSummarizer 根据配置创建摘要生成器
+// 注意: 未配置 SUMMARIZER_BASE_URL 时返回不生成摘要的 NoopSummarizer
+func NewSummarizer(cfg config.SummarizerConfig) Summarizer {
+	if cfg.BaseURL == "" {
+		return NoopSummarizer{}
+	}
+	return &OpenAISummarizer{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout()},
+	}
+}
+
+// NoopSummarizer 不生成摘要，调用方使用截取正文的摘要
+type NoopSummarizer struct{}
+
+// Summarize 总是返回 ErrSummarizerDisabled
+func (NoopSummarizer) Summarize(context.Context, string, string, int) (string, error) {
+	return "", ErrSummarizerDisabled
+}
+
+// OpenAISummarizer 通过 OpenAI 兼容的 chat completions 接口生成摘要
+type OpenAISummarizer struct {
+	cfg    config.SummarizerConfig
+	client *http.Client
+}
+
+type chatCompletionMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model    string                  `json:"model"`
+	Messages []chatCompletionMessage `json:"messages"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatCompletionMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Summarize 生成摘要
+// 注意: 正文按 max_input_tokens 截断后发送；返回的摘要超过 maxLen 时截断
+func (s *OpenAISummarizer) Summarize(ctx context.Context, title, content string, maxLen int) (string, error) {
+	prompt := fmt.Sprintf("为下面的博客文章写一段不超过 %d 个字符的摘要，使用与文章相同的语言，只输出摘要本身。", maxLen)
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: s.cfg.Model,
+		Messages: []chatCompletionMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: "标题：" + title + "\n\n" + truncateToTokens(content, s.cfg.MaxInputTokens)},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(s.cfg.BaseURL, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, summarizerResponseLimit))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("summarizer returned status %d", resp.StatusCode)
+	}
+
+	var result chatCompletionResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("invalid summarizer response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", errors.New("summarizer returned no choices")
+	}
+	summary := strings.TrimSpace(result.Choices[0].Message.Content)
+	if summary == "" {
+		return "", errors.New("summarizer returned an empty summary")
+	}
+	return cutRunes(summary, maxLen), nil
+}
+
+// truncateToTokens 按估算的 token 数截断文本
+// 估算规则：中日韩字符每个计 1 个 token，其他字符每 4 个计 1 个 token
+func truncateToTokens(s string, maxTokens int) string {
+	budget := maxTokens * 4 // 以 1/4 token 为单位
+	for i, r := range s {
+		cost := 1
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cost = 4
+		}
+		if budget < cost {
+			return s[:i]
+		}
+		budget -= cost
+	}
+	return s
+}
+
+// cutRunes 截取前 n 个字符（按 rune 计），不追加省略号，用于有长度上限的字段
+func cutRunes(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
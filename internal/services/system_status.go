@@ -0,0 +1,83 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"runtime"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/pkg/metrics"
+)
+
+// processStartedAt 进程启动时间（用于计算运行时长）
+var processStartedAt = time.Now()
+
+// CollectSystemStatus 采集当前实例的连接池、索引队列、后台任务和进程状态，以及维护模式状态
+// 注意: 同时刷新 Prometheus 中对应的 gauge，便于告警
+func CollectSystemStatus() *models.SystemStatus {
+	status := &models.SystemStatus{
+		Search: models.SearchQueueStatus{
+			Enabled:           search.Enabled(),
+			PendingOperations: search.PendingOperations(),
+		},
+		BackgroundJobs: RunningBackgroundJobs(),
+		Maintenance:    CurrentMaintenance(),
+		CollectedAt:    time.Now(),
+	}
+
+	if database.DB != nil {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			stats := sqlDB.Stats()
+			status.Database = models.DBPoolStatus{
+				MaxOpenConnections: stats.MaxOpenConnections,
+				OpenConnections:    stats.OpenConnections,
+				InUse:              stats.InUse,
+				Idle:               stats.Idle,
+				WaitCount:          stats.WaitCount,
+				WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+				MaxIdleClosed:      stats.MaxIdleClosed,
+				MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+			}
+		}
+	}
+
+	if database.RedisClient != nil {
+		stats := database.RedisClient.PoolStats()
+		status.Redis = &models.RedisPoolStatus{
+			Hits:       stats.Hits,
+			Misses:     stats.Misses,
+			Timeouts:   stats.Timeouts,
+			TotalConns: stats.TotalConns,
+			IdleConns:  stats.IdleConns,
+			StaleConns: stats.StaleConns,
+		}
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	status.Process = models.ProcessStatus{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: mem.HeapAlloc,
+		HeapInuseBytes: mem.HeapInuse,
+		SysBytes:       mem.Sys,
+		NumGC:          mem.NumGC,
+		UptimeSeconds:  time.Since(processStartedAt).Seconds(),
+	}
+
+	recordSystemMetrics(status)
+	return status
+}
+
+func recordSystemMetrics(status *models.SystemStatus) {
+	db := status.Database
+	metrics.SetDBPoolStats(db.OpenConnections, db.InUse, db.Idle, db.WaitCount, time.Duration(db.WaitDurationMs)*time.Millisecond)
+	if status.Redis != nil {
+		metrics.SetRedisPoolStats(status.Redis.TotalConns, status.Redis.IdleConns, status.Redis.Timeouts)
+	}
+	metrics.SetSearchPendingOperations(status.Search.PendingOperations)
+	for kind, n := range status.BackgroundJobs {
+		metrics.SetBackgroundJobsRunning(kind, n)
+	}
+}
@@ -2,84 +2,338 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"enterprise-blog/internal/database"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/pkg/logger"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTagInUse 标签仍被已发布文章使用，且未指定 reassign_to 或 detach
+	ErrTagInUse = errors.New("tag is still used by published articles")
+	// ErrTagSlugExists 追加数字后缀重试后标签 slug 仍然冲突
+	ErrTagSlugExists = errors.New("tag.slug_exists")
+	// ErrTagNameExists 标签名称已被其他标签使用
+	ErrTagNameExists = errors.New("tag.name_exists")
+	// ErrInvalidMergeTarget 合并目标标签不存在、已删除或与被合并标签相同
+	ErrInvalidMergeTarget = errors.New("invalid merge target")
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+	// taxonomyReindexBatch 合并 / 删除标签或分类后重建搜索索引时每批加载的文章数
+	taxonomyReindexBatch = 200
 )
 
 // TagService 标签服务，提供标签相关的业务逻辑
 type TagService struct {
-	tagRepo *repository.TagRepository
+	tagRepo     *repository.TagRepository
+	articleRepo *repository.ArticleRepository
 }
 
 // NewTagService 创建新的标签服务实例
 // tagRepo: 标签数据访问层仓库
-func NewTagService(tagRepo *repository.TagRepository) *TagService {
+// articleRepo: 文章数据访问层仓库（合并标签后重建受影响文章的搜索索引）
+func NewTagService(tagRepo *repository.TagRepository, articleRepo *repository.ArticleRepository) *TagService {
 	return &TagService{
-		tagRepo: tagRepo,
+		tagRepo:     tagRepo,
+		articleRepo: articleRepo,
 	}
 }
 
 // Create 创建新标签
 // req: 标签创建请求，包含名称和颜色
-// 返回: 创建成功的标签对象，如果创建失败则返回错误
-func (s *TagService) Create(req *models.TagCreate) (*models.Tag, error) {
+// 返回: 创建成功的标签对象；名称已存在时返回 ErrTagNameExists，slug 重试后仍冲突时返回 ErrTagSlugExists
+func (s *TagService) Create(ctx context.Context, req *models.TagCreate) (*models.Tag, error) {
 	tag := &models.Tag{
 		Name:  req.Name,
-		Slug:  GenerateSlug(req.Name),
 		Color: req.Color,
 	}
 
-	if err := s.tagRepo.Create(tag); err != nil {
-		return nil, err
+	// slug 冲突（大小写不敏感）时自动追加数字后缀重试
+	_, err := WithUniqueSlug(GenerateSlug(req.Name), isTagSlugConflict, func(slug string) error {
+		tag.Slug = slug
+		return s.tagRepo.Create(ctx, tag)
+	})
+	if err != nil {
+		return nil, tagWriteError(err)
 	}
+	clearTaxonomyListCache()
 
-	return s.tagRepo.GetByID(tag.ID)
+	return s.tagRepo.GetByID(ctx, tag.ID)
 }
 
 // GetByID 根据ID获取标签详情
 // id: 标签UUID
 // 返回: 标签对象，如果不存在则返回错误
-func (s *TagService) GetByID(id uuid.UUID) (*models.Tag, error) {
-	return s.tagRepo.GetByID(id)
+func (s *TagService) GetByID(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	return s.tagRepo.GetByID(ctx, id)
+}
+
+// GetBySlug 根据 slug 获取标签（大小写不敏感）
+// 返回: 标签对象；slug 属于已合并的标签时返回合并的目标标签，都不存在时返回错误
+func (s *TagService) GetBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetBySlug(ctx, slug)
+	if err == nil {
+		return tag, nil
+	}
+	if aliased, aliasErr := s.tagRepo.GetByAliasSlug(ctx, slug); aliasErr == nil {
+		return aliased, nil
+	}
+	return nil, err
+}
+
+// Merge 将标签合并到目标标签
+// id: 被合并的标签UUID；targetID: 目标标签UUID
+// 返回: 目标标签和受影响的文章数；目标不合法时返回 ErrInvalidMergeTarget
+// 注意: 转移文章关联（已带有目标标签的文章不重复添加）、记录别名和软删除被合并标签在同一事务中完成；
+// 被合并标签的 ID 和 slug 之后仍解析到目标标签。提交后清理受影响文章的详情缓存，并异步重建其搜索索引
+func (s *TagService) Merge(ctx context.Context, id, targetID uuid.UUID) (*models.TagMergeResult, error) {
+	source, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if targetID == id {
+		return nil, ErrInvalidMergeTarget
+	}
+	target, err := s.tagRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, ErrInvalidMergeTarget
+	}
+
+	var articleIDs []uuid.UUID
+	err = database.WithTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		if articleIDs, err = s.tagRepo.ArticleIDsTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.tagRepo.ReassignArticlesTx(tx, id, targetID); err != nil {
+			return err
+		}
+		if err := s.tagRepo.AddAliasTx(tx, source, targetID); err != nil {
+			return err
+		}
+		return s.tagRepo.DeleteTx(tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 文章中内嵌了标签名称，列表中的文章数也随之变化
+	deleteArticleDetailCaches(articleIDs)
+	clearTaxonomyCaches()
+	reindexArticles(ctx, s.articleRepo, articleIDs)
+
+	return &models.TagMergeResult{Target: target, ArticlesTouched: int64(len(articleIDs))}, nil
+}
+
+// reindexArticles 分批加载文章并异步提交到搜索索引（未启用搜索时跳过）
+// 注意: 已删除的文章不会加载，也不会重新索引
+func reindexArticles(ctx context.Context, articleRepo *repository.ArticleRepository, ids []uuid.UUID) {
+	if !search.Enabled() {
+		return
+	}
+	for start := 0; start < len(ids); start += taxonomyReindexBatch {
+		end := start + taxonomyReindexBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		articles, err := articleRepo.GetByIDs(ctx, ids[start:end])
+		if err != nil {
+			l := logger.FromContext(ctx, "search")
+			l.Warn().Err(err).Int("articles", end-start).Msg("failed to load articles for reindexing after taxonomy change")
+			continue
+		}
+		search.IndexArticlesAsync(ctx, articles)
+	}
 }
 
 // Update 更新标签信息
 // id: 标签UUID
 // req: 标签更新请求，包含可选的名称和颜色
-// 返回: 更新后的标签对象，如果更新失败则返回错误
-func (s *TagService) Update(id uuid.UUID, req *models.TagUpdate) (*models.Tag, error) {
-	tag, err := s.tagRepo.GetByID(id)
+// 返回: 更新后的标签对象；名称已存在时返回 ErrTagNameExists，改名后 slug 重试仍冲突时返回 ErrTagSlugExists
+func (s *TagService) Update(ctx context.Context, id uuid.UUID, req *models.TagUpdate) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	renamed := req.Name != nil && *req.Name != tag.Name
 	if req.Name != nil {
 		tag.Name = *req.Name
-		tag.Slug = GenerateSlug(*req.Name)
 	}
 
 	if req.Color != nil {
 		tag.Color = *req.Color
 	}
 
-	if err := s.tagRepo.Update(tag); err != nil {
-		return nil, err
+	write := func(slug string) error {
+		tag.Slug = slug
+		return s.tagRepo.Update(ctx, tag)
+	}
+	// 只有名称变化时才重新生成 slug，冲突时与创建一样追加数字后缀重试
+	if renamed {
+		_, err = WithUniqueSlug(GenerateSlug(tag.Name), isTagSlugConflict, write)
+	} else {
+		err = write(tag.Slug)
+	}
+	if err != nil {
+		return nil, tagWriteError(err)
 	}
+	// 文章中内嵌了标签名称，列表中也有，一并清理
+	clearTaxonomyCaches()
+
+	return s.tagRepo.GetByID(ctx, id)
+}
 
-	return s.tagRepo.GetByID(id)
+// isTagSlugConflict 判断是否为标签 slug 唯一索引冲突
+func isTagSlugConflict(err error) bool {
+	return isUniqueViolation(err, "uniq_tags_slug_lower")
 }
 
-// Delete 删除标签（软删除）
+// tagWriteError 将名称冲突和 slug 重试用尽转换为对应的错误码，其他错误原样返回
+func tagWriteError(err error) error {
+	if isUniqueViolation(err, "uniq_tags_name_active", "tags.name") {
+		return ErrTagNameExists
+	}
+	if errors.Is(err, ErrSlugRetriesExhausted) {
+		return fmt.Errorf("%w: %v", ErrTagSlugExists, err)
+	}
+	return err
+}
+
+// Delete 删除标签（软删除，可在回收站中恢复）
 // id: 标签UUID
-// 返回: 如果删除失败则返回错误
-func (s *TagService) Delete(id uuid.UUID) error {
-	return s.tagRepo.Delete(id)
+// reassignTo: 目标标签UUID，非空时先把文章关联转移到目标标签
+// detach: 为 true 时先移除该标签与全部文章的关联
+// 返回: 两者都未指定且仍有已发布文章使用该标签时返回 ErrTagInUse；目标标签不合法时返回 ErrInvalidReassignTarget
+// 注意: 转移 / 移除关联和删除标签在同一事务中完成；未转移 / 移除的关联会保留，恢复标签后随之恢复
+func (s *TagService) Delete(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID, detach bool) error {
+	if _, err := s.tagRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if reassignTo != nil {
+		if *reassignTo == id {
+			return ErrInvalidReassignTarget
+		}
+		if _, err := s.tagRepo.GetByID(ctx, *reassignTo); err != nil {
+			return ErrInvalidReassignTarget
+		}
+	} else if !detach {
+		count, err := s.tagRepo.CountPublishedArticles(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %d published articles, pass reassign_to or detach=true", ErrTagInUse, count)
+		}
+	}
+
+	var articleIDs []uuid.UUID
+	err := database.WithTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		if articleIDs, err = s.tagRepo.ArticleIDsTx(tx, id); err != nil {
+			return err
+		}
+		if reassignTo != nil {
+			_, err = s.tagRepo.ReassignArticlesTx(tx, id, *reassignTo)
+		} else if detach {
+			_, err = s.tagRepo.DetachArticlesTx(tx, id)
+		}
+		if err != nil {
+			return err
+		}
+		return s.tagRepo.DeleteTx(tx, id)
+	})
+	if err != nil {
+		return err
+	}
+	deleteArticleDetailCaches(articleIDs)
+	clearTaxonomyCaches()
+	// 索引文档中带有标签 ID，转移或解除关联后重建受影响文章的索引
+	reindexArticles(ctx, s.articleRepo, articleIDs)
+	return nil
 }
 
-// List 获取所有标签列表
+// List 获取所有标签列表（含文章数）
+// query: PublishedOnly 为 true 时只统计已发布文章（公开接口），Sort 支持 article_count
 // 返回: 标签列表，如果查询失败则返回错误
-func (s *TagService) List() ([]*models.Tag, error) {
-	return s.tagRepo.List()
+// 注意: 结果会在 Redis 中短暂缓存，标签或文章变更时清理
+func (s *TagService) List(ctx context.Context, query models.TaxonomyListQuery) ([]*models.Tag, error) {
+	cacheKey := taxonomyListCacheKey("tags", query)
+	var cached []*models.Tag
+	if err := getDashboardCache(cacheKey, &cached); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	tags, err := s.tagRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []*models.Tag{}
+	}
+	_ = setDashboardCache(cacheKey, tags, taxonomyListCacheTTL)
+	return tags, nil
+}
+
+// Autocomplete 编辑器标签自动补全：按名称子串匹配（大小写不敏感），按使用量排序
+// q: 关键词；limit: 返回条数（默认 10，最大 50）
+// createIfMissing: 为 true 且没有同名（大小写不敏感）标签时，按常规规则（含 slug 处理）新建标签并放在结果最前
+// 注意: 是否允许新建由调用方按角色判断
+func (s *TagService) Autocomplete(ctx context.Context, q string, limit int, createIfMissing bool) ([]*models.TaxonomySuggestion, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return []*models.TaxonomySuggestion{}, nil
+	}
+	suggestions, err := s.tagRepo.Autocomplete(ctx, q, normalizeAutocompleteLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []*models.TaxonomySuggestion{}
+	}
+	// 同名标签总是排在最前
+	if !createIfMissing || (len(suggestions) > 0 && strings.EqualFold(suggestions[0].Name, q)) {
+		return suggestions, nil
+	}
+
+	tag, err := s.Create(ctx, &models.TagCreate{Name: q})
+	if err != nil {
+		return nil, err
+	}
+	created := &models.TaxonomySuggestion{ID: tag.ID, Name: tag.Name, Slug: tag.Slug, Created: true}
+	if len(suggestions) >= normalizeAutocompleteLimit(limit) {
+		suggestions = suggestions[:len(suggestions)-1]
+	}
+	return append([]*models.TaxonomySuggestion{created}, suggestions...), nil
+}
+
+// normalizeAutocompleteLimit 规范化自动补全的返回条数
+func normalizeAutocompleteLimit(limit int) int {
+	if limit <= 0 {
+		return defaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		return maxAutocompleteLimit
+	}
+	return limit
+}
+
+// Stats 获取按标签统计的内容数据（已发布文章数、总浏览量、总评论数、最近发布时间）
+// query: 排序参数，非法值回退为按已发布文章数倒序
+// 返回: 统计列表，如果查询失败则返回错误
+// 注意: 结果会在 Redis 中短暂缓存
+func (s *TagService) Stats(ctx context.Context, query models.ContentStatsQuery) ([]*models.ContentStats, error) {
+	return loadContentStats(ctx, "tags", query, s.tagRepo.Stats)
 }
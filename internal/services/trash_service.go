@@ -0,0 +1,279 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidTrashType 回收站类型不合法
+	ErrInvalidTrashType = errors.New("invalid type, must be one of articles, users, images, categories, tags")
+	// ErrTrashItemNotFound 回收站中不存在该条目（不存在或未被删除）
+	ErrTrashItemNotFound = repository.ErrTrashItemNotFound
+	// ErrRestoreConflict 恢复时唯一字段（slug / 用户名 / 邮箱 / 标签名）已被占用
+	ErrRestoreConflict = errors.New("restore conflict")
+	// ErrImageFileMissing 图片文件已不存在，无法恢复
+	ErrImageFileMissing = errors.New("image file no longer exists")
+)
+
+// TrashService 回收站服务，统一管理已软删除的文章、用户、图片、分类和标签
+//
+// 设计考虑：
+// - 恢复前重新校验唯一字段，避免与删除期间新建的数据冲突
+// - 永久删除时同步清理关联资源：图片文件、Elasticsearch 文档、缓存
+// - 删除文章时评论随之软删除，恢复文章时一并恢复；永久删除时评论物理删除
+// - 数据库中的其他关联数据（标签、浏览统计等）由外键级联删除
+// - 永久删除分类时引用它的文章 category_id 置空，永久删除标签时文章关联级联删除
+type TrashService struct {
+	trashRepo    *repository.TrashRepository
+	articleRepo  *repository.ArticleRepository
+	userRepo     *repository.UserRepository
+	categoryRepo *repository.CategoryRepository
+	tagRepo      *repository.TagRepository
+}
+
+// NewTrashService 创建新的回收站服务实例
+// trashRepo: 回收站数据访问层仓库
+// articleRepo: 文章仓库，用于 slug 唯一性校验和恢复后重建索引
+// userRepo: 用户仓库，用于用户名 / 邮箱唯一性校验
+// categoryRepo: 分类仓库，用于 slug 唯一性校验
+// tagRepo: 标签仓库，用于名称 / slug 唯一性校验
+func NewTrashService(
+	trashRepo *repository.TrashRepository,
+	articleRepo *repository.ArticleRepository,
+	userRepo *repository.UserRepository,
+	categoryRepo *repository.CategoryRepository,
+	tagRepo *repository.TagRepository,
+) *TrashService {
+	return &TrashService{
+		trashRepo:    trashRepo,
+		articleRepo:  articleRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+	}
+}
+
+// List 分页查询回收站条目
+// query: 类型和分页参数
+// 返回: 回收站条目（按删除时间倒序）、总数
+func (s *TrashService) List(ctx context.Context, query *models.TrashQuery) ([]*models.TrashItem, int64, error) {
+	if !repository.IsValidTrashType(query.Type) {
+		return nil, 0, ErrInvalidTrashType
+	}
+	query.Page, query.PageSize = config.NormalizePage(config.PageOther, query.Page, query.PageSize)
+
+	items, total, err := s.trashRepo.List(ctx, query.Type, query.Page, query.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if items == nil {
+		items = []*models.TrashItem{}
+	}
+	return items, total, nil
+}
+
+// Restore 从回收站恢复条目
+// 返回: 条目不存在时返回 ErrTrashItemNotFound；唯一字段已被占用时返回 ErrRestoreConflict
+func (s *TrashService) Restore(ctx context.Context, t models.TrashType, id uuid.UUID) error {
+	switch t {
+	case models.TrashArticles:
+		return s.restoreArticle(ctx, id)
+	case models.TrashUsers:
+		return s.restoreUser(ctx, id)
+	case models.TrashImages:
+		return s.restoreImage(ctx, id)
+	case models.TrashCategories:
+		return s.restoreCategory(ctx, id)
+	case models.TrashTags:
+		return s.restoreTag(ctx, id)
+	default:
+		return ErrInvalidTrashType
+	}
+}
+
+// RestoreArticle 恢复已删除的文章
+// 返回: 文章未被删除时不做任何修改，返回 nil；文章不存在时返回 ErrTrashItemNotFound；slug 已被占用时返回 ErrRestoreConflict
+func (s *TrashService) RestoreArticle(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.articleRepo.GetByID(ctx, id); err == nil {
+		return nil
+	}
+	return s.restoreArticle(ctx, id)
+}
+
+func (s *TrashService) restoreArticle(ctx context.Context, id uuid.UUID) error {
+	article, err := s.trashRepo.GetDeletedArticle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.articleRepo.GetBySlug(ctx, article.Slug); err == nil {
+		return fmt.Errorf("%w: slug %q is already used by another article", ErrRestoreConflict, article.Slug)
+	}
+
+	if err := s.trashRepo.Restore(ctx, models.TrashArticles, id); err != nil {
+		return err
+	}
+	clearArticleListCache()
+
+	// 恢复后重新写入 Elasticsearch（删除时已移除文档）
+	if restored, err := s.articleRepo.GetByID(ctx, id); err == nil {
+		search.IndexArticleAsync(ctx, restored)
+	}
+	return nil
+}
+
+func (s *TrashService) restoreUser(ctx context.Context, id uuid.UUID) error {
+	user, err := s.trashRepo.GetDeletedUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.userRepo.GetByUsername(ctx, user.Username); err == nil {
+		return fmt.Errorf("%w: username %q is already taken", ErrRestoreConflict, user.Username)
+	}
+	if _, err := s.userRepo.GetByEmail(ctx, user.Email); err == nil {
+		return fmt.Errorf("%w: email %q is already taken", ErrRestoreConflict, user.Email)
+	}
+	return s.trashRepo.Restore(ctx, models.TrashUsers, id)
+}
+
+func (s *TrashService) restoreImage(ctx context.Context, id uuid.UUID) error {
+	image, err := s.trashRepo.GetDeletedImage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(image.Path); err != nil {
+		return ErrImageFileMissing
+	}
+	return s.trashRepo.Restore(ctx, models.TrashImages, id)
+}
+
+func (s *TrashService) restoreCategory(ctx context.Context, id uuid.UUID) error {
+	category, err := s.trashRepo.GetDeletedCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.categoryRepo.GetBySlug(ctx, category.Slug); err == nil {
+		return fmt.Errorf("%w: slug %q is already used by another category", ErrRestoreConflict, category.Slug)
+	}
+	if err := s.trashRepo.Restore(ctx, models.TrashCategories, id); err != nil {
+		return err
+	}
+	clearTaxonomyCaches()
+	return nil
+}
+
+func (s *TrashService) restoreTag(ctx context.Context, id uuid.UUID) error {
+	tag, err := s.trashRepo.GetDeletedTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.tagRepo.GetByName(ctx, tag.Name); err == nil {
+		return fmt.Errorf("%w: name %q is already used by another tag", ErrRestoreConflict, tag.Name)
+	}
+	if _, err := s.tagRepo.GetBySlug(ctx, tag.Slug); err == nil {
+		return fmt.Errorf("%w: slug %q is already used by another tag", ErrRestoreConflict, tag.Slug)
+	}
+	if err := s.trashRepo.Restore(ctx, models.TrashTags, id); err != nil {
+		return err
+	}
+	clearTaxonomyCaches()
+	return nil
+}
+
+// Purge 永久删除回收站条目，并清理文件、搜索索引和缓存
+// 注意: 永久删除用户会级联删除其全部文章和图片（含未删除的），对应的索引和文件一并清理
+func (s *TrashService) Purge(ctx context.Context, t models.TrashType, id uuid.UUID) error {
+	switch t {
+	case models.TrashArticles:
+		if _, err := s.trashRepo.GetDeletedArticle(ctx, id); err != nil {
+			return err
+		}
+		if err := s.trashRepo.Purge(ctx, t, id); err != nil {
+			return err
+		}
+		purgeArticleResources(ctx, []uuid.UUID{id})
+		return nil
+
+	case models.TrashUsers:
+		if _, err := s.trashRepo.GetDeletedUser(ctx, id); err != nil {
+			return err
+		}
+		// 查询名下内容和删除用户放在同一事务中，提交成功后再清理索引和文件
+		var articleIDs []uuid.UUID
+		var imagePaths []string
+		err := database.WithTx(ctx, func(tx *gorm.DB) error {
+			var err error
+			articleIDs, imagePaths, err = s.trashRepo.UserOwnedContentTx(tx, id)
+			if err != nil {
+				return err
+			}
+			return s.trashRepo.PurgeTx(tx, t, id)
+		})
+		if err != nil {
+			return err
+		}
+		purgeArticleResources(ctx, articleIDs)
+		removeImageFiles(ctx, imagePaths)
+		return nil
+
+	case models.TrashImages:
+		image, err := s.trashRepo.GetDeletedImage(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.trashRepo.Purge(ctx, t, id); err != nil {
+			return err
+		}
+		removeImageFiles(ctx, []string{image.Path})
+		return nil
+
+	case models.TrashCategories, models.TrashTags:
+		// Purge 只删除已软删除的记录，条目不存在时返回 ErrTrashItemNotFound
+		if err := s.trashRepo.Purge(ctx, t, id); err != nil {
+			return err
+		}
+		clearTaxonomyCaches()
+		return nil
+
+	default:
+		return ErrInvalidTrashType
+	}
+}
+
+// purgeArticleResources 清理已永久删除文章的缓存和 Elasticsearch 文档
+func purgeArticleResources(ctx context.Context, ids []uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	for _, id := range ids {
+		deleteArticleDetailCache(id)
+	}
+	clearArticleListCache()
+
+	for _, id := range ids {
+		search.DeleteArticleAsync(ctx, id)
+	}
+}
+
+// removeImageFiles 删除图片文件，文件不存在或删除失败只记录日志
+func removeImageFiles(ctx context.Context, paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			l := logger.FromContext(ctx)
+			l.Warn().Err(err).Str("path", path).Msg("failed to delete image file")
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"context"
+	"time"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/jwt"
+
+	"github.com/google/uuid"
+)
+
+// UserRepositoryInterface 用户服务和短信服务依赖的用户数据访问接口
+// *repository.UserRepository 实现该接口，单元测试中可以替换为 mock
+type UserRepositoryInterface interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetPublicProfile(ctx context.Context, id uuid.UUID) (*models.PublicProfile, error)
+	GetByPhone(ctx context.Context, phone string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByQuery(ctx context.Context, query models.UserQuery) ([]*models.User, int64, error)
+}
+
+// RefreshTokenStore 刷新 token 登记的存取接口，*repository.RefreshTokenRepository 实现该接口
+type RefreshTokenStore interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetActive(ctx context.Context, id uuid.UUID) (*models.RefreshToken, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// TokenManager 签发和校验访问 token、刷新 token 的接口，*jwt.JWTManager 实现该接口
+type TokenManager interface {
+	GenerateToken(userID uuid.UUID, username, role string) (string, error)
+	GenerateImpersonationToken(userID uuid.UUID, username, role string, impersonatorID uuid.UUID, ttl time.Duration) (string, error)
+	ValidateToken(tokenString string) (*jwt.Claims, error)
+	GenerateRefreshToken(userID uuid.UUID) (string, *jwt.Claims, error)
+	ValidateRefreshToken(tokenString string) (*jwt.Claims, error)
+	// ExpireTime 访问 token 的有效期
+	ExpireTime() time.Duration
+}
+
+// 编译期检查具体类型实现了上述接口
+var (
+	_ UserRepositoryInterface = (*repository.UserRepository)(nil)
+	_ RefreshTokenStore       = (*repository.RefreshTokenRepository)(nil)
+	_ TokenManager            = (*jwt.JWTManager)(nil)
+)
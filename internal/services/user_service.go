@@ -2,48 +2,102 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"enterprise-blog/internal/config"
 	"enterprise-blog/internal/models"
 	"enterprise-blog/internal/repository"
-	"enterprise-blog/pkg/jwt"
+	"enterprise-blog/pkg/logger"
+	"enterprise-blog/pkg/metrics"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrRegistrationClosed 站点已关闭注册
+	ErrRegistrationClosed = errors.New("registration is closed")
+	// ErrCannotImpersonateAdmin 不允许模拟其他管理员
+	ErrCannotImpersonateAdmin = errors.New("cannot impersonate an admin")
+	// ErrNotImpersonating 当前会话不是模拟登录会话
+	ErrNotImpersonating = errors.New("not an impersonation session")
+	// ErrImpersonatorNotAdmin 发起模拟的管理员已被删除、降级或禁用，不能再恢复其身份
+	ErrImpersonatorNotAdmin = errors.New("impersonator is no longer an active admin")
+	// ErrEmailExists 邮箱已被其他用户使用
+	ErrEmailExists = errors.New("email already exists")
+	// ErrUsernameExists 用户名已被其他用户使用
+	ErrUsernameExists = errors.New("username already exists")
+	// ErrInvalidCredentials 邮箱不存在或密码错误（不区分两者，避免泄露邮箱是否注册）
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrAccountInactive 账号未激活或已被禁用
+	ErrAccountInactive = errors.New("user account is not active")
+	// ErrEmailNotVerified 注册邮箱尚未验证（同时属于 ErrAccountInactive）
+	ErrEmailNotVerified = fmt.Errorf("email not verified: %w", ErrAccountInactive)
+	// ErrInvalidOldPassword 修改密码时原密码错误
+	ErrInvalidOldPassword = errors.New("invalid old password")
+	// ErrInvalidRefreshToken 刷新 token 无效、已过期或已吊销
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+// impersonationTTL 模拟登录 token 有效期上限
+const impersonationTTL = 30 * time.Minute
+
 // UserService 用户服务，提供用户相关的业务逻辑
 type UserService struct {
-	userRepo *repository.UserRepository
-	jwtMgr   *jwt.JWTManager
+	userRepo    UserRepositoryInterface
+	refreshRepo RefreshTokenStore
+	jwtMgr      TokenManager
+	// verification 邮箱验证服务，未设置时注册后直接激活
+	verification *EmailVerificationService
+	// loginGuard 登录防暴力破解，未设置时不限制密码错误次数
+	loginGuard *LoginGuard
 }
 
 // NewUserService 创建新的用户服务实例
-// userRepo: 用户数据访问层仓库
-// jwtMgr: JWT管理器，用于生成和验证token
-func NewUserService(userRepo *repository.UserRepository, jwtMgr *jwt.JWTManager) *UserService {
+// userRepo: 用户数据访问层仓库（通常为 *repository.UserRepository，单元测试中可替换为 mock）
+// refreshRepo: 刷新 token 登记仓库，用于吊销刷新 token
+// jwtMgr: JWT管理器（通常为 *jwt.JWTManager），用于生成和验证token
+func NewUserService(userRepo UserRepositoryInterface, refreshRepo RefreshTokenStore, jwtMgr TokenManager) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		jwtMgr:   jwtMgr,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+		jwtMgr:      jwtMgr,
 	}
 }
 
+// SetEmailVerification 设置邮箱验证服务，设置后注册的账号为 pending 状态，验证邮箱后才能登录
+func (s *UserService) SetEmailVerification(verification *EmailVerificationService) {
+	s.verification = verification
+}
+
+// SetLoginGuard 设置登录防暴力破解，设置后密码错误次数过多的邮箱和 IP 暂时不能登录
+func (s *UserService) SetLoginGuard(guard *LoginGuard) {
+	s.loginGuard = guard
+}
+
 // Register 用户注册
 // req: 用户注册请求，包含用户名、邮箱、密码等信息
 // 返回: 注册成功的用户对象（密码已清除），如果注册失败则返回错误
-// 注意: 会检查邮箱和用户名是否已存在，密码使用bcrypt加密存储
-func (s *UserService) Register(req *models.UserCreate) (*models.User, error) {
+// 注意: 会检查邮箱和用户名是否已存在，密码使用bcrypt加密存储；registration_mode 为 closed 时拒绝注册；
+// 设置了邮箱验证服务时账号为 pending 状态并发送验证邮件，验证邮件生成失败不影响注册（可重新发送）
+func (s *UserService) Register(ctx context.Context, req *models.UserCreate) (*models.User, error) {
+	if settingString(models.SettingRegistrationMode) == models.RegistrationClosed {
+		return nil, ErrRegistrationClosed
+	}
+
 	// 检查邮箱是否已存在
-	_, err := s.userRepo.GetByEmail(req.Email)
+	_, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailExists
 	}
 
 	// 检查用户名是否已存在
-	_, err = s.userRepo.GetByUsername(req.Username)
+	_, err = s.userRepo.GetByUsername(ctx, req.Username)
 	if err == nil {
-		return nil, errors.New("username already exists")
+		return nil, ErrUsernameExists
 	}
 
 	// 创建用户
@@ -57,6 +111,9 @@ func (s *UserService) Register(req *models.UserCreate) (*models.User, error) {
 	if user.Role == "" {
 		user.Role = models.RoleReader
 	}
+	if s.verification != nil {
+		user.Status = "pending"
+	}
 
 	// 加密密码
 	if err := user.HashPassword(); err != nil {
@@ -64,52 +121,217 @@ func (s *UserService) Register(req *models.UserCreate) (*models.User, error) {
 	}
 
 	// 保存用户
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	metrics.RecordUserRegistration()
+
+	if s.verification != nil {
+		if err := s.verification.Issue(ctx, user); err != nil {
+			l := logger.FromContext(ctx, "email_verification")
+			l.Warn().Err(err).Str("user_id", user.ID.String()).Msg("Failed to issue verification email")
+		}
+	}
+
 	// 清除密码
 	user.Password = ""
+	emitWebhookEvent(ctx, models.WebhookEventUserRegistered, newWebhookUser(user))
 	return user, nil
 }
 
 // Login 用户登录（邮箱密码方式）
 // req: 用户登录请求，包含邮箱和密码
-// 返回: JWT token、用户对象（密码已清除），如果登录失败则返回错误
-// 注意: 会验证密码和用户状态，只有active状态的用户才能登录
-func (s *UserService) Login(req *models.UserLogin) (string, *models.User, error) {
+// ip: 客户端 IP，用于按 IP 统计登录失败次数（为空时只按邮箱统计）
+// 返回: 访问 token 和刷新 token、用户对象（密码已清除），如果登录失败则返回错误
+// 注意: 会验证密码和用户状态，只有active状态的用户才能登录，邮箱未验证（pending）时返回 ErrEmailNotVerified；
+// 设置了登录防暴力破解时，密码错误次数过多的邮箱或 IP 返回 *LoginLockedError（不校验密码）
+func (s *UserService) Login(ctx context.Context, req *models.UserLogin, ip string) (*models.AuthTokens, *models.User, error) {
+	if s.loginGuard != nil {
+		if err := s.loginGuard.Check(ctx, req.Email, ip); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// 获取用户
-	user, err := s.userRepo.GetByEmail(req.Email)
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		s.recordLoginFailure(ctx, req.Email, ip)
+		return nil, nil, ErrInvalidCredentials
 	}
 
 	// 验证密码
 	if !user.CheckPassword(req.Password) {
-		return "", nil, errors.New("invalid email or password")
+		s.recordLoginFailure(ctx, req.Email, ip)
+		return nil, nil, ErrInvalidCredentials
+	}
+	if s.loginGuard != nil {
+		s.loginGuard.Reset(ctx, req.Email)
 	}
 
 	// 检查用户状态
+	if user.Status == "pending" {
+		return nil, nil, ErrEmailNotVerified
+	}
+	if user.Status != "active" {
+		return nil, nil, ErrAccountInactive
+	}
+
+	tokens, err := s.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics.RecordUserLogin("password")
+
+	// 清除密码
+	user.Password = ""
+	return tokens, user, nil
+}
+
+// recordLoginFailure 记录一次密码错误（邮箱不存在同样记录）
+func (s *UserService) recordLoginFailure(ctx context.Context, email, ip string) {
+	if s.loginGuard != nil {
+		s.loginGuard.RecordFailure(ctx, email, ip)
+	}
+}
+
+// IssueTokens 为已通过认证的用户签发访问 token 和刷新 token（登录成功后调用）
+// 返回: token 及各自的过期时间
+// 注意: 刷新 token 的 jti 登记在 refresh_tokens 表中，退出登录时吊销
+func (s *UserService) IssueTokens(ctx context.Context, user *models.User) (*models.AuthTokens, error) {
+	token, err := s.jwtMgr.GenerateToken(user.ID, user.Username, string(user.Role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	refreshToken, claims, err := s.jwtMgr.GenerateRefreshToken(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	jti, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	if err := s.refreshRepo.Create(ctx, &models.RefreshToken{ID: jti, UserID: user.ID, ExpiresAt: claims.ExpiresAt.Time}); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return &models.AuthTokens{
+		Token:                 token,
+		ExpiresAt:             time.Now().Add(s.jwtMgr.ExpireTime()),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// Refresh 使用刷新 token 换取新的访问 token
+// 返回: 新的访问 token，刷新 token 原样返回；刷新 token 无效、已过期或已吊销时返回 ErrInvalidRefreshToken
+// 注意: 刷新 token 不轮换，过期时间在登录时确定，不会因刷新而延长；访问 token 按用户当前的角色签发，账号被禁用后无法刷新
+func (s *UserService) Refresh(ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	stored, err := s.activeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
 	if user.Status != "active" {
-		return "", nil, errors.New("user account is not active")
+		return nil, ErrAccountInactive
 	}
 
-	// 生成JWT token
 	token, err := s.jwtMgr.GenerateToken(user.ID, user.Username, string(user.Role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return &models.AuthTokens{
+		Token:                 token,
+		ExpiresAt:             time.Now().Add(s.jwtMgr.ExpireTime()),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+// Logout 退出登录，吊销刷新 token
+// 返回: 刷新 token 无效、已过期或已吊销时返回 ErrInvalidRefreshToken
+// 注意: 已签发的访问 token 在过期前仍然有效
+func (s *UserService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.activeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	return s.refreshRepo.Revoke(ctx, stored.ID)
+}
+
+// activeRefreshToken 校验刷新 token 并获取其登记记录
+// 返回: 签名、有效期或类型不符，以及未登记或已吊销时返回 ErrInvalidRefreshToken
+func (s *UserService) activeRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshToken, error) {
+	claims, err := s.jwtMgr.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	jti, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	stored, err := s.refreshRepo.GetActive(ctx, jti)
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) || (err == nil && stored.UserID != claims.UserID) {
+		return nil, ErrInvalidRefreshToken
+	}
+	return stored, err
+}
+
+// Impersonate 管理员以目标用户身份登录（用于复现用户问题）
+// adminID: 发起模拟的管理员ID
+// targetID: 被模拟的用户ID
+// 返回: 携带 impersonator_id 的短期 token（30 分钟）和目标用户（密码已清除）
+// 注意: 不允许模拟管理员；不经过 Login 流程，不会计入目标用户的登录记录
+func (s *UserService) Impersonate(ctx context.Context, adminID, targetID uuid.UUID) (string, *models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return "", nil, err
+	}
+	if user.Role == models.RoleAdmin {
+		return "", nil, ErrCannotImpersonateAdmin
+	}
+
+	token, err := s.jwtMgr.GenerateImpersonationToken(user.ID, user.Username, string(user.Role), adminID, impersonationTTL)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	// 清除密码
 	user.Password = ""
 	return token, user, nil
 }
 
+// StopImpersonation 结束模拟登录，重新签发发起模拟的管理员的普通 token
+// impersonatorID: 模拟会话中的 impersonator_id
+// 返回: 管理员 token、管理员用户对象（密码已清除）；管理员已被删除、降级或禁用时返回 ErrImpersonatorNotAdmin
+// 注意: 会重新校验管理员角色和状态
+func (s *UserService) StopImpersonation(ctx context.Context, impersonatorID uuid.UUID) (string, *models.User, error) {
+	admin, err := s.userRepo.GetByID(ctx, impersonatorID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return "", nil, ErrImpersonatorNotAdmin
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if admin.Role != models.RoleAdmin || admin.Status != "active" {
+		return "", nil, ErrImpersonatorNotAdmin
+	}
+
+	token, err := s.jwtMgr.GenerateToken(admin.ID, admin.Username, string(admin.Role))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	admin.Password = ""
+	return token, admin, nil
+}
+
 // GetByID 根据ID获取用户详情
 // id: 用户UUID
 // 返回: 用户对象（密码已清除），如果不存在则返回错误
-func (s *UserService) GetByID(id uuid.UUID) (*models.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -117,31 +339,37 @@ func (s *UserService) GetByID(id uuid.UUID) (*models.User, error) {
 	return user, nil
 }
 
+// GetPublicProfile 获取作者公开资料（用户名、头像、简介、已发布文章数、注册时间）
+// 返回: 用户不存在、已删除或未激活时返回 repository.ErrUserNotFound
+func (s *UserService) GetPublicProfile(ctx context.Context, id uuid.UUID) (*models.PublicProfile, error) {
+	return s.userRepo.GetPublicProfile(ctx, id)
+}
+
 // Update 更新用户信息
 // id: 用户UUID
 // req: 用户更新请求，包含可选的用户名、邮箱、角色、头像、简介、状态等
 // 返回: 更新后的用户对象（密码已清除），如果更新失败则返回错误
 // 注意: 会检查用户名和邮箱是否已被其他用户使用
-func (s *UserService) Update(id uuid.UUID, req *models.UserUpdate) (*models.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *UserService) Update(ctx context.Context, id uuid.UUID, req *models.UserUpdate) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if req.Username != nil {
 		// 检查用户名是否已被其他用户使用
-		existing, err := s.userRepo.GetByUsername(*req.Username)
+		existing, err := s.userRepo.GetByUsername(ctx, *req.Username)
 		if err == nil && existing.ID != id {
-			return nil, errors.New("username already exists")
+			return nil, ErrUsernameExists
 		}
 		user.Username = *req.Username
 	}
 
 	if req.Email != nil {
 		// 检查邮箱是否已被其他用户使用
-		existing, err := s.userRepo.GetByEmail(*req.Email)
+		existing, err := s.userRepo.GetByEmail(ctx, *req.Email)
 		if err == nil && existing.ID != id {
-			return nil, errors.New("email already exists")
+			return nil, ErrEmailExists
 		}
 		user.Email = *req.Email
 	}
@@ -162,7 +390,7 @@ func (s *UserService) Update(id uuid.UUID, req *models.UserUpdate) (*models.User
 		user.Status = *req.Status
 	}
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
@@ -173,8 +401,8 @@ func (s *UserService) Update(id uuid.UUID, req *models.UserUpdate) (*models.User
 // Delete 删除用户（软删除）
 // id: 用户UUID
 // 返回: 如果删除失败则返回错误
-func (s *UserService) Delete(id uuid.UUID) error {
-	return s.userRepo.Delete(id)
+func (s *UserService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.userRepo.Delete(ctx, id)
 }
 
 // ChangePassword 修改用户密码（需提供旧密码进行验证）
@@ -182,21 +410,21 @@ func (s *UserService) Delete(id uuid.UUID) error {
 // oldPassword: 当前密码，用于验证用户身份
 // newPassword: 新密码，将使用bcrypt加密后存储
 // 返回: 如果旧密码错误或更新失败则返回错误
-func (s *UserService) ChangePassword(id uuid.UUID, oldPassword, newPassword string) error {
-	user, err := s.userRepo.GetByID(id)
+func (s *UserService) ChangePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	// 校验旧密码
 	if !user.CheckPassword(oldPassword) {
-		return errors.New("invalid old password")
+		return ErrInvalidOldPassword
 	}
 	// 设置新密码并哈希
 	user.Password = newPassword
 	if err := user.HashPassword(); err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
-	return s.userRepo.UpdatePassword(id, user.Password)
+	return s.userRepo.UpdatePassword(ctx, id, user.Password)
 }
 
 // List 获取用户列表（分页）
@@ -204,18 +432,18 @@ func (s *UserService) ChangePassword(id uuid.UUID, oldPassword, newPassword stri
 // pageSize: 每页数量，最大100
 // 返回: 用户列表、总数，如果查询失败则返回错误
 // 注意: 返回的用户对象密码已清除
-func (s *UserService) List(page, pageSize int) ([]*models.User, int64, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if pageSize <= 0 {
-		pageSize = 10
-	}
-	if pageSize > 100 {
-		pageSize = 100
-	}
+func (s *UserService) List(ctx context.Context, page, pageSize int) ([]*models.User, int64, error) {
+	return s.ListByQuery(ctx, models.UserQuery{Page: page, PageSize: pageSize})
+}
+
+// ListByQuery 按条件获取用户列表（分页，支持角色、状态、注册时间筛选）
+// query: 用户查询条件
+// 返回: 用户列表、总数，如果查询失败则返回错误
+// 注意: 返回的用户对象密码已清除
+func (s *UserService) ListByQuery(ctx context.Context, query models.UserQuery) ([]*models.User, int64, error) {
+	query.Page, query.PageSize = config.NormalizePage(config.PageUsers, query.Page, query.PageSize)
 
-	users, total, err := s.userRepo.List(page, pageSize)
+	users, total, err := s.userRepo.ListByQuery(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
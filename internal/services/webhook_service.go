@@ -0,0 +1,485 @@
+// Package services 提供业务逻辑层的服务实现
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/errorreport"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Webhook 请求头
+const (
+	// WebhookSignatureHeader 签名：sha256=<hex(HMAC-SHA256(secret, 时间戳 + "." + 请求体))>
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookTimestampHeader 签名时间（Unix 秒），接收方可据此拒绝过旧的请求
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// webhookResponseBodyLimit 投递记录中保存的响应体最大长度
+const webhookResponseBodyLimit = 2048
+
+var (
+	// ErrInvalidWebhook Webhook 地址或订阅事件不合法
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	// ErrWebhookNotFound Webhook 不存在
+	ErrWebhookNotFound = repository.ErrWebhookNotFound
+	// ErrWebhookDeliveryNotFound 投递记录不存在
+	ErrWebhookDeliveryNotFound = repository.ErrWebhookDeliveryNotFound
+)
+
+// defaultWebhooks 当前进程使用的 Webhook 服务，由 NewWebhookService 注册
+// 未注册时（例如单元测试）内容事件不投递
+var defaultWebhooks atomic.Pointer[WebhookService]
+
+// WebhookService Webhook 服务：管理回调地址并投递内容事件
+//
+// 设计考虑：
+// - 事件由各业务服务在操作成功后发出，查询订阅和投递都在后台 goroutine 中进行，不阻塞原请求
+// - 每次投递写入 webhook_deliveries，失败按指数退避重试，最终结果可在管理后台查询并重新投递
+// - 请求体用 Webhook 密钥做 HMAC-SHA256 签名，接收方据此校验来源
+// - 重试在进程内进行，进程退出时尚未完成的投递保持 pending，可手动重新投递
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	cfg         config.WebhookConfig
+	client      *http.Client
+
+	inflight sync.WaitGroup
+}
+
+// NewWebhookService 创建新的 Webhook 服务实例，并注册为当前进程的事件投递服务
+// webhookRepo: Webhook 数据访问层仓库
+// cfg: 投递超时和重试配置
+func NewWebhookService(webhookRepo *repository.WebhookRepository, cfg config.WebhookConfig) *WebhookService {
+	s := &WebhookService{
+		webhookRepo: webhookRepo,
+		cfg:         cfg,
+		client:      &http.Client{Timeout: cfg.Timeout()},
+	}
+	defaultWebhooks.Store(s)
+	return s
+}
+
+// List 获取全部 Webhook（不含密钥）
+func (s *WebhookService) List(ctx context.Context) ([]*models.Webhook, error) {
+	webhooks, err := s.webhookRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []*models.Webhook{}
+	}
+	for _, w := range webhooks {
+		w.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetByID 获取 Webhook（不含密钥）
+func (s *WebhookService) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// Create 创建 Webhook
+// createdBy: 操作人用户ID
+// 返回: 创建的 Webhook（含密钥，未提供时自动生成，只在此时返回）；地址或事件不合法时返回 ErrInvalidWebhook
+func (s *WebhookService) Create(ctx context.Context, req *models.WebhookCreate, createdBy *uuid.UUID) (*models.Webhook, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.Webhook{
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    events,
+		Active:    req.Active == nil || *req.Active,
+		CreatedBy: createdBy,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Update 修改 Webhook
+// 返回: 修改后的 Webhook（只有修改了密钥时才包含密钥）
+func (s *WebhookService) Update(ctx context.Context, id uuid.UUID, req *models.WebhookUpdate) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		if webhook.Events, err = normalizeWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	secretChanged := req.Secret != nil
+	if secretChanged {
+		if *req.Secret == "" {
+			return nil, fmt.Errorf("%w: secret must not be empty", ErrInvalidWebhook)
+		}
+		webhook.Secret = *req.Secret
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	if !secretChanged {
+		webhook.Secret = ""
+	}
+	return webhook, nil
+}
+
+// Delete 删除 Webhook 及其投递记录
+func (s *WebhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// ListDeliveries 分页获取 Webhook 的投递记录（最新的在前）
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, page, pageSize int) ([]*models.WebhookDelivery, int64, error) {
+	if _, err := s.webhookRepo.GetByID(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+	page, pageSize = config.NormalizePage(config.PageOther, page, pageSize)
+	deliveries, total, err := s.webhookRepo.ListDeliveries(ctx, webhookID, page, pageSize)
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+	return deliveries, total, err
+}
+
+// Redeliver 按原请求体重新投递，生成一条新的投递记录并在后台发送
+// 注意: Webhook 已停用时同样投递（管理员手动触发）
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.webhookRepo.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		WebhookID:    webhook.ID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	queued := *delivery
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		s.deliver(context.WithoutCancel(ctx), webhook, &queued)
+	}()
+	return delivery, nil
+}
+
+// Emit 向订阅了事件的启用中的 Webhook 投递事件，立即返回
+// data: 事件数据，序列化为请求体的 data 字段
+func (s *WebhookService) Emit(ctx context.Context, event string, data interface{}) {
+	ctx = context.WithoutCancel(ctx)
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		l := logger.FromContext(ctx, "webhook")
+		l.Warn().Err(err).Str("event", event).Msg("Failed to encode webhook payload")
+		return
+	}
+
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+
+		webhooks, err := s.webhookRepo.ListActive(ctx)
+		if err != nil {
+			errorreport.CaptureError(ctx, err, map[string]string{"worker": "webhook", "event": event})
+			l := logger.FromContext(errorreport.WithoutLogReport(ctx), "webhook")
+			l.Error().Err(err).Str("event", event).Msg("Failed to load webhooks")
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, webhook := range webhooks {
+			if !webhook.Subscribed(event) {
+				continue
+			}
+			delivery := &models.WebhookDelivery{WebhookID: webhook.ID, Event: event, Payload: string(payload)}
+			if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+				l := logger.FromContext(ctx, "webhook")
+				l.Warn().Err(err).Str("webhook_id", webhook.ID.String()).Str("event", event).Msg("Failed to record webhook delivery")
+				continue
+			}
+			wg.Add(1)
+			go func(webhook *models.Webhook) {
+				defer wg.Done()
+				s.deliver(ctx, webhook, delivery)
+			}(webhook)
+		}
+		wg.Wait()
+	}()
+}
+
+// Wait 等待进行中的投递（含重试）完成，ctx 结束时返回 ctx 的错误（退出前调用）
+func (s *WebhookService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver 发送一次投递，失败时按指数退避重试，每次尝试后更新投递记录
+func (s *WebhookService) deliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	l := logger.FromContext(ctx, "webhook")
+	backoff := s.cfg.RetryBackoff()
+	for {
+		delivery.Attempts++
+		status, body, err := s.send(ctx, webhook, delivery)
+		delivery.ResponseStatus, delivery.ResponseBody, delivery.Error = status, body, ""
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+
+		switch {
+		case err == nil:
+			now := time.Now()
+			delivery.Status, delivery.DeliveredAt = models.WebhookDeliverySucceeded, &now
+		case delivery.Attempts >= s.cfg.MaxAttempts:
+			delivery.Status = models.WebhookDeliveryFailed
+		}
+		if err := s.webhookRepo.UpdateDeliveryResult(ctx, delivery); err != nil {
+			l.Warn().Err(err).Str("delivery_id", delivery.ID.String()).Msg("Failed to update webhook delivery")
+		}
+		if delivery.Status != models.WebhookDeliveryPending {
+			if delivery.Status == models.WebhookDeliveryFailed {
+				l.Warn().
+					Str("webhook_id", webhook.ID.String()).
+					Str("delivery_id", delivery.ID.String()).
+					Str("event", delivery.Event).
+					Int("attempts", delivery.Attempts).
+					Str("error", delivery.Error).
+					Msg("Webhook delivery failed")
+			}
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// send 发送一次请求
+// 返回: 响应状态码和（截断后的）响应体；非 2xx 响应和网络错误返回错误
+func (s *WebhookService) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout())
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "enterprise-blog-webhook/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// SignWebhookPayload 计算 Webhook 签名头的值：sha256=<hex(HMAC-SHA256(secret, timestamp + "." + body))>
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateWebhookURL 只允许带主机名的 http / https 地址
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// normalizeWebhookEvents 校验订阅事件并去重，至少订阅一个事件
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	known := make(map[string]bool, len(models.WebhookEvents))
+	for _, e := range models.WebhookEvents {
+		known[e] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		if !known[e] {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+	return result, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// emitWebhookEvent 通过当前进程的 Webhook 服务投递内容事件，未注册时忽略
+func emitWebhookEvent(ctx context.Context, event string, data interface{}) {
+	if s := defaultWebhooks.Load(); s != nil {
+		s.Emit(ctx, event, data)
+	}
+}
+
+// webhookArticle 文章事件数据（不含正文）
+type webhookArticle struct {
+	ID          uuid.UUID            `json:"id"`
+	Title       string               `json:"title"`
+	Slug        string               `json:"slug"`
+	Excerpt     string               `json:"excerpt"`
+	Status      models.ArticleStatus `json:"status"`
+	AuthorID    uuid.UUID            `json:"author_id"`
+	CategoryID  *uuid.UUID           `json:"category_id,omitempty"`
+	PublishedAt *time.Time           `json:"published_at,omitempty"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+func newWebhookArticle(a *models.Article) webhookArticle {
+	return webhookArticle{
+		ID:          a.ID,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Excerpt:     a.Excerpt,
+		Status:      a.Status,
+		AuthorID:    a.AuthorID,
+		CategoryID:  a.CategoryID,
+		PublishedAt: a.PublishedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+}
+
+// webhookComment 评论事件数据（不含邮箱和 IP）
+type webhookComment struct {
+	ID        uuid.UUID  `json:"id"`
+	ArticleID uuid.UUID  `json:"article_id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	Author    string     `json:"author"`
+	Content   string     `json:"content"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newWebhookComment(c *models.Comment) webhookComment {
+	return webhookComment{
+		ID:        c.ID,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		UserID:    c.UserID,
+		Author:    c.Author,
+		Content:   c.Content,
+		Status:    c.Status,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// webhookUser 用户事件数据（不含邮箱和手机号）
+type webhookUser struct {
+	ID        uuid.UUID       `json:"id"`
+	Username  string          `json:"username"`
+	Role      models.UserRole `json:"role"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newWebhookUser(u *models.User) webhookUser {
+	return webhookUser{ID: u.ID, Username: u.Username, Role: u.Role, CreatedAt: u.CreatedAt}
+}
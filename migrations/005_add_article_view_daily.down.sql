@@ -0,0 +1,2 @@
+-- 删除文章浏览量按天汇总表
+DROP TABLE IF EXISTS article_view_daily;
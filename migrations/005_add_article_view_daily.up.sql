@@ -0,0 +1,9 @@
+-- 文章浏览量按天汇总表（由 Redis 计数回刷任务写入，用于仪表盘排行榜等统计）
+CREATE TABLE IF NOT EXISTS article_view_daily (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_view_daily_day ON article_view_daily(day);
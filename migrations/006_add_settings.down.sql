@@ -0,0 +1,2 @@
+-- 删除系统设置表
+DROP TABLE IF EXISTS settings;
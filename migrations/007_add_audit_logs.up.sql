@@ -0,0 +1,22 @@
+-- 创建管理后台操作审计日志表
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    action VARCHAR(200) NOT NULL,
+    resource_type VARCHAR(50),
+    resource_id VARCHAR(100),
+    status_code INTEGER NOT NULL DEFAULT 0,
+    ip VARCHAR(45),
+    user_agent VARCHAR(500),
+    payload JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_audit_logs_actor_created ON audit_logs(actor_id, created_at);
+CREATE INDEX idx_audit_logs_method_created ON audit_logs(method, created_at);
+-- 支持 path LIKE 'prefix%' 前缀查询
+CREATE INDEX idx_audit_logs_path ON audit_logs(path varchar_pattern_ops);
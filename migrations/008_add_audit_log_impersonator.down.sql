@@ -0,0 +1,3 @@
+-- 删除模拟登录发起人字段和索引
+DROP INDEX IF EXISTS idx_audit_logs_impersonator;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS impersonator_id;
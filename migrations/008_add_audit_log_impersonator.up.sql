@@ -0,0 +1,3 @@
+-- 为审计日志添加模拟登录发起人字段（管理员以其他用户身份操作时记录）
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS impersonator_id UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_logs_impersonator ON audit_logs(impersonator_id) WHERE impersonator_id IS NOT NULL;
@@ -0,0 +1,17 @@
+-- 永久删除已软删除的分类和标签，恢复全表唯一约束
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+DELETE FROM tags WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS uniq_categories_slug_active;
+DROP INDEX IF EXISTS uniq_tags_name_active;
+DROP INDEX IF EXISTS uniq_tags_slug_active;
+
+ALTER TABLE categories ADD CONSTRAINT categories_slug_key UNIQUE (slug);
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);
+ALTER TABLE tags ADD CONSTRAINT tags_slug_key UNIQUE (slug);
+
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+DROP INDEX IF EXISTS idx_tags_deleted_at;
+
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE tags DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,15 @@
+-- 分类和标签改为软删除
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
+
+-- 唯一约束只作用于未删除的记录，已删除的名称 / slug 可以被新建的记录复用（恢复时再校验冲突）
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_slug_key;
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_slug_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_categories_slug_active ON categories(slug) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_tags_name_active ON tags(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_tags_slug_active ON tags(slug) WHERE deleted_at IS NULL;
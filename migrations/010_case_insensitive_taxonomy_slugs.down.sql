@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS uniq_categories_slug_lower;
+DROP INDEX IF EXISTS uniq_tags_slug_lower;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_categories_slug_active ON categories(slug) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_tags_slug_active ON tags(slug) WHERE deleted_at IS NULL;
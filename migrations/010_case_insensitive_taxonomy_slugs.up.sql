@@ -0,0 +1,6 @@
+-- 分类和标签 slug 唯一性改为大小写不敏感（仍只作用于未删除的记录）
+DROP INDEX IF EXISTS uniq_categories_slug_active;
+DROP INDEX IF EXISTS uniq_tags_slug_active;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_categories_slug_lower ON categories(LOWER(slug)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_tags_slug_lower ON tags(LOWER(slug)) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- 文章乐观锁版本号，每次更新 +1
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,22 @@
+-- 恢复 002 中的 search_vector 触发器和权重
+CREATE OR REPLACE FUNCTION articles_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.content, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(NEW.excerpt, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS articles_search_vector_trigger ON articles;
+CREATE TRIGGER articles_search_vector_trigger
+    BEFORE INSERT OR UPDATE ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION articles_search_vector_update();
+
+UPDATE articles SET search_vector =
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(content, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(excerpt, '')), 'C');
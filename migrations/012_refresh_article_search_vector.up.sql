@@ -0,0 +1,27 @@
+-- 调整文章全文索引（search_vector），作为未部署 Elasticsearch 时的默认搜索路径
+-- 权重：标题 A > 摘要 B > 正文 C（与 Elasticsearch 的字段权重一致）
+CREATE OR REPLACE FUNCTION articles_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.excerpt, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(NEW.content, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 只在标题 / 摘要 / 正文变化时重新计算，浏览数、点赞数等计数更新不再触发分词
+DROP TRIGGER IF EXISTS articles_search_vector_trigger ON articles;
+CREATE TRIGGER articles_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF title, excerpt, content ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION articles_search_vector_update();
+
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN(search_vector);
+
+-- 按新权重重建现有数据
+UPDATE articles SET search_vector =
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(excerpt, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(content, '')), 'C');
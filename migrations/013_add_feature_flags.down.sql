@@ -0,0 +1,2 @@
+-- 删除功能开关表
+DROP TABLE IF EXISTS feature_flags;
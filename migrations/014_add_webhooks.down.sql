@@ -0,0 +1,3 @@
+-- 删除 Webhook 相关表
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
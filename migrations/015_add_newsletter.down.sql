@@ -0,0 +1,3 @@
+-- 删除邮件群发记录表和订阅者表
+DROP TABLE IF EXISTS newsletter_campaigns;
+DROP TABLE IF EXISTS subscribers;
@@ -0,0 +1,42 @@
+-- 创建邮件订阅者表（双重确认：提交后为 pending，点击确认链接后为 confirmed）
+CREATE TABLE IF NOT EXISTS subscribers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- 小写保存
+    email VARCHAR(255) NOT NULL UNIQUE,
+    -- pending / confirmed / unsubscribed / bounced
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    -- 确认令牌的 SHA-256，确认后清空
+    confirm_token_hash VARCHAR(64),
+    confirm_expires_at TIMESTAMP,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    confirmed_at TIMESTAMP,
+    unsubscribed_at TIMESTAMP,
+    bounced_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建邮件群发记录表
+CREATE TABLE IF NOT EXISTS newsletter_campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject VARCHAR(200) NOT NULL,
+    -- article（单篇文章）/ digest（近期文章摘要）
+    kind VARCHAR(20) NOT NULL,
+    article_id UUID REFERENCES articles(id) ON DELETE SET NULL,
+    digest_days INTEGER NOT NULL DEFAULT 0,
+    -- sending / sent / failed
+    status VARCHAR(20) NOT NULL DEFAULT 'sending',
+    recipients INTEGER NOT NULL DEFAULT 0,
+    sent_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    bounced_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX idx_subscribers_status ON subscribers(status);
+CREATE INDEX idx_subscribers_confirm_token_hash ON subscribers(confirm_token_hash);
+CREATE INDEX idx_newsletter_campaigns_created_at ON newsletter_campaigns(created_at);
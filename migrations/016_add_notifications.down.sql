@@ -0,0 +1,2 @@
+-- 删除站内通知表
+DROP TABLE IF EXISTS notifications;
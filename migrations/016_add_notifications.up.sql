@@ -0,0 +1,23 @@
+-- 创建站内通知表
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- comment_on_article / comment_reply / article_approved / article_rejected
+    type VARCHAR(30) NOT NULL,
+    -- 触发通知的用户（游客评论时为空），actor_name 为当时的显示名
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_name VARCHAR(100) NOT NULL DEFAULT '',
+    -- 通知对象：article / comment
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id UUID NOT NULL,
+    -- 所属文章，便于前端跳转
+    article_id UUID REFERENCES articles(id) ON DELETE CASCADE,
+    -- 文章标题或评论内容摘要
+    summary VARCHAR(255) NOT NULL DEFAULT '',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX idx_notifications_recipient_created ON notifications(recipient_id, created_at);
+CREATE INDEX idx_notifications_recipient_unread ON notifications(recipient_id) WHERE read_at IS NULL;
@@ -0,0 +1,2 @@
+-- 文章 SEO 描述（meta description），为空时使用摘要
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS meta_description VARCHAR(300) NOT NULL DEFAULT '';
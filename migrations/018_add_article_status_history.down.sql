@@ -0,0 +1,2 @@
+-- 删除文章状态变更记录表
+DROP TABLE IF EXISTS article_status_history;
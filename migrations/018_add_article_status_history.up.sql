@@ -0,0 +1,17 @@
+-- 文章状态变更记录（作者可查看审核进度，与管理后台的审计日志分开）
+CREATE TABLE IF NOT EXISTS article_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    -- 创建文章时为空
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    -- 修改状态的用户（后台任务或已删除的用户为空）
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- 退回原因等说明
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX idx_article_status_history_article_created ON article_status_history(article_id, created_at);
+CREATE INDEX idx_article_status_history_created ON article_status_history(created_at);
@@ -0,0 +1,3 @@
+-- 删除分类 / 标签名称的 trigram 索引（保留 pg_trgm 扩展）
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_tags_name_trgm;
@@ -0,0 +1,5 @@
+-- 编辑器自动补全按名称做大小写不敏感的子串匹配（LOWER(name) LIKE '%q%'），使用 trigram 索引
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_tags_name_trgm ON tags USING GIN (LOWER(name) gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (LOWER(name) gin_trgm_ops) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- 删除标签别名表
+DROP TABLE IF EXISTS tag_aliases;
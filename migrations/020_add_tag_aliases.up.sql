@@ -0,0 +1,10 @@
+-- 标签别名：合并标签后，被合并标签的 ID 和 slug 继续解析到目标标签
+CREATE TABLE IF NOT EXISTS tag_aliases (
+    source_tag_id UUID PRIMARY KEY,
+    source_slug VARCHAR(50) NOT NULL,
+    target_tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tag_aliases_slug_lower ON tag_aliases(LOWER(source_slug));
+CREATE INDEX IF NOT EXISTS idx_tag_aliases_target_tag_id ON tag_aliases(target_tag_id);
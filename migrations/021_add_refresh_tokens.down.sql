@@ -0,0 +1,2 @@
+-- 删除刷新 token 表
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,2 @@
+-- 删除文章点赞记录表
+DROP TABLE IF EXISTS article_likes;
@@ -0,0 +1,12 @@
+-- 文章点赞记录：登录用户按 user_id 去重，匿名用户按 IP 指纹去重
+CREATE TABLE IF NOT EXISTS article_likes (
+    id UUID PRIMARY KEY,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((user_id IS NULL) <> (fingerprint IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_likes_user ON article_likes(article_id, user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_likes_fingerprint ON article_likes(article_id, fingerprint);
@@ -0,0 +1,4 @@
+-- 回滚评论软删除：已软删除的评论无法区分，一并物理删除
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
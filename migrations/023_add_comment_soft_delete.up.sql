@@ -0,0 +1,8 @@
+-- 评论改为软删除：删除文章时其评论随之软删除（deleted_at 与文章相同），恢复文章时一并恢复
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments(deleted_at);
+
+-- 已在回收站中的文章，其评论按文章的删除时间补齐
+UPDATE comments SET deleted_at = articles.deleted_at
+FROM articles
+WHERE comments.article_id = articles.id AND articles.deleted_at IS NOT NULL AND comments.deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- 删除文章修订记录表
+DROP TABLE IF EXISTS article_revisions;
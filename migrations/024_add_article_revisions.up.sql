@@ -0,0 +1,17 @@
+-- 文章修订记录：每次修改文章前保存修改前的标题、正文、摘要和状态，可查看和恢复
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    -- 快照时文章的版本号
+    version INTEGER NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL,
+    excerpt TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    -- 进行这次修改的用户（后台任务或已删除的用户为空）
+    editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX idx_article_revisions_article_created ON article_revisions(article_id, created_at);
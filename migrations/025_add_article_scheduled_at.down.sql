@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_articles_scheduled_at;
+ALTER TABLE articles DROP COLUMN IF EXISTS scheduled_at;
@@ -0,0 +1,3 @@
+-- 定时发布：状态为 scheduled 的文章到达 scheduled_at 后由后台任务发布
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_articles_scheduled_at ON articles(scheduled_at) WHERE status = 'scheduled' AND deleted_at IS NULL;
@@ -0,0 +1,8 @@
+-- 文章旧 slug：修改 slug 后旧链接继续解析到文章
+CREATE TABLE IF NOT EXISTS article_slug_redirects (
+    old_slug VARCHAR(200) PRIMARY KEY,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slug_redirects_article_id ON article_slug_redirects(article_id);
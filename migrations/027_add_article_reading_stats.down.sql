@@ -0,0 +1,2 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS reading_time_minutes;
+ALTER TABLE articles DROP COLUMN IF EXISTS word_count;
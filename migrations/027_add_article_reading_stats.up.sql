@@ -0,0 +1,3 @@
+-- 字数和预计阅读时长，保存文章时计算；已有文章通过 POST /api/v1/admin/articles/reading-stats/backfill 回填
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS word_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS reading_time_minutes INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- 删除邮箱验证令牌表
+DROP TABLE IF EXISTS email_verifications;
@@ -0,0 +1,12 @@
+-- 注册邮箱验证令牌：只保存令牌的 SHA-256 哈希，24 小时有效，验证后或重新发送时作废
+CREATE TABLE IF NOT EXISTS email_verifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS email_verifications_token_hash_key ON email_verifications(token_hash);
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user_id ON email_verifications(user_id);
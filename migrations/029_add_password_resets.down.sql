@@ -0,0 +1,2 @@
+-- 删除找回密码令牌表
+DROP TABLE IF EXISTS password_resets;
@@ -0,0 +1,114 @@
+// Package ahocorasick 提供基于 Aho-Corasick 自动机的多模式字符串匹配
+//
+// 一次扫描即可找出文本中所有模式串的出现位置，耗时与文本长度和命中数成正比，与模式串数量无关。
+// 按 rune 匹配，支持中文等多字节字符；匹配区分大小写，需要忽略大小写时由调用方统一转换。
+package ahocorasick
+
+// Match 一次命中
+type Match struct {
+	// Pattern 命中的模式串在 New 参数中的下标
+	Pattern int
+	// Start、End 命中位置在文本 rune 序列中的区间 [Start, End)
+	Start, End int
+}
+
+type node struct {
+	next map[rune]int
+	fail int
+	// output 以该节点结尾的模式串下标
+	output []int
+	// dict 沿失配链最近的有输出的节点（-1 表示没有），用于收集被包含的较短模式串
+	dict int
+}
+
+// Automaton 构建完成的自动机，只读，可并发使用
+type Automaton struct {
+	nodes    []node
+	patterns [][]rune
+}
+
+// New 构建自动机
+// patterns: 模式串，空串会被忽略；重复的模式串各自产生命中
+func New(patterns []string) *Automaton {
+	a := &Automaton{
+		nodes:    []node{{next: map[rune]int{}, dict: -1}},
+		patterns: make([][]rune, len(patterns)),
+	}
+	for i, p := range patterns {
+		runes := []rune(p)
+		a.patterns[i] = runes
+		if len(runes) == 0 {
+			continue
+		}
+		cur := 0
+		for _, r := range runes {
+			child, ok := a.nodes[cur].next[r]
+			if !ok {
+				child = len(a.nodes)
+				a.nodes = append(a.nodes, node{next: map[rune]int{}, dict: -1})
+				a.nodes[cur].next[r] = child
+			}
+			cur = child
+		}
+		a.nodes[cur].output = append(a.nodes[cur].output, i)
+	}
+	a.buildFailLinks()
+	return a
+}
+
+// buildFailLinks 按层次遍历计算失配链和输出链
+func (a *Automaton) buildFailLinks() {
+	queue := make([]int, 0, len(a.nodes))
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range a.nodes[cur].next {
+			fail := a.nodes[cur].fail
+			for fail != 0 {
+				if _, ok := a.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = a.nodes[fail].fail
+			}
+			if target, ok := a.nodes[fail].next[r]; ok && target != child {
+				a.nodes[child].fail = target
+			}
+			f := a.nodes[child].fail
+			if len(a.nodes[f].output) > 0 {
+				a.nodes[child].dict = f
+			} else {
+				a.nodes[child].dict = a.nodes[f].dict
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// FindAll 找出文本中所有模式串的出现位置（可能重叠），按结束位置排序
+func (a *Automaton) FindAll(text []rune) []Match {
+	var matches []Match
+	cur := 0
+	for i, r := range text {
+		for cur != 0 {
+			if _, ok := a.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = a.nodes[cur].fail
+		}
+		if next, ok := a.nodes[cur].next[r]; ok {
+			cur = next
+		}
+		for n := cur; n > 0; n = a.nodes[n].dict {
+			for _, p := range a.nodes[n].output {
+				matches = append(matches, Match{Pattern: p, Start: i + 1 - len(a.patterns[p]), End: i + 1})
+			}
+			if a.nodes[n].dict < 0 {
+				break
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,145 @@
+// Package cron 提供标准 5 段 cron 表达式（分 时 日 月 周）的解析和下次触发时间计算
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 已解析的 cron 表达式
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // 每个字段允许值的位图
+	domStar, dowStar              bool
+}
+
+type fieldBounds struct {
+	name     string
+	min, max int
+}
+
+var fields = []fieldBounds{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Parse 解析 cron 表达式，例如 "0 8 * * 1" 表示每周一 08:00
+// 每个字段支持 *、数字、范围（1-5）、列表（1,3,5）和步长（*/15、0-30/10）
+// 周字段中 0 和 7 都表示周日
+func Parse(spec string) (*Schedule, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d", len(parts))
+	}
+
+	// 周字段允许 7（周日），解析后归一化为 0
+	dowBits, err := parseField(parts[4], fieldBounds{"day of week", 0, 7})
+	if err != nil {
+		return nil, err
+	}
+	if dowBits&(1<<7) != 0 {
+		dowBits = dowBits&^(1<<7) | 1
+	}
+
+	s := &Schedule{
+		dow:     dowBits,
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}
+	for i, dest := range []*uint64{&s.minute, &s.hour, &s.dom, &s.month} {
+		bits, err := parseField(parts[i], fields[i])
+		if err != nil {
+			return nil, err
+		}
+		*dest = bits
+	}
+	return s, nil
+}
+
+func parseField(expr string, b fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("cron: invalid step in %s field: %q", b.name, item)
+			}
+			rangeExpr, step = item[:i], n
+		}
+
+		lo, hi := b.min, b.max
+		if rangeExpr != "*" {
+			var err error
+			if i := strings.Index(rangeExpr, "-"); i >= 0 {
+				lo, err = strconv.Atoi(rangeExpr[:i])
+				if err == nil {
+					hi, err = strconv.Atoi(rangeExpr[i+1:])
+				}
+			} else {
+				lo, err = strconv.Atoi(rangeExpr)
+				hi = lo
+				if step > 1 {
+					hi = b.max
+				}
+			}
+			if err != nil {
+				return 0, fmt.Errorf("cron: invalid %s field: %q", b.name, item)
+			}
+		}
+		if lo < b.min || hi > b.max || lo > hi {
+			return 0, fmt.Errorf("cron: %s field out of range: %q", b.name, item)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	if bits == 0 {
+		return 0, errors.New("cron: empty " + b.name + " field")
+	}
+	return bits, nil
+}
+
+// Next 返回严格晚于 t 的下一次触发时间（使用 t 所在时区）
+// 如果五年内都没有匹配的时间（例如 2 月 30 日），返回零值
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 与标准 cron 一致：日和周都被限定时，满足任意一个即可
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,122 @@
+package errorreport
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"enterprise-blog/pkg/logger"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrorReporter 错误上报（如 Sentry）
+//
+// 设计考虑：
+// - 实现必须是非阻塞的：事件进入缓冲区后立即返回，缓冲区满时丢弃，不能拖慢请求处理
+// - ctx 中的请求 ID、用户 ID 和路由（见 logger.ContextFields）随事件一起上报
+type ErrorReporter interface {
+	// CaptureError 上报错误，tags 为附加标签（如 worker: counter_flush）
+	CaptureError(ctx context.Context, err error, tags map[string]string)
+	// CapturePanic 上报 recover 到的 panic，stack 为 panic 发生时的调用栈
+	CapturePanic(ctx context.Context, recovered interface{}, stack []byte)
+	// Flush 等待缓冲区中的事件发送完成（退出前调用），超时返回 false
+	Flush(timeout time.Duration) bool
+}
+
+// NoopReporter 不上报任何错误，未配置上报服务时使用
+type NoopReporter struct{}
+
+func (NoopReporter) CaptureError(context.Context, error, map[string]string) {}
+func (NoopReporter) CapturePanic(context.Context, interface{}, []byte)      {}
+func (NoopReporter) Flush(time.Duration) bool                               { return true }
+
+// reporterHolder 包装接口，atomic.Value 要求每次存入的具体类型一致
+type reporterHolder struct {
+	reporter ErrorReporter
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store(reporterHolder{NoopReporter{}})
+}
+
+// SetReporter 设置全局错误上报，nil 表示不上报
+// 返回: 之前的上报实现（测试中用于恢复）
+func SetReporter(r ErrorReporter) ErrorReporter {
+	if r == nil {
+		r = NoopReporter{}
+	}
+	return current.Swap(reporterHolder{r}).(reporterHolder).reporter
+}
+
+// Reporter 返回当前的全局错误上报
+func Reporter() ErrorReporter {
+	return current.Load().(reporterHolder).reporter
+}
+
+// CaptureError 通过全局错误上报上报错误，err 为 nil 时忽略
+func CaptureError(ctx context.Context, err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	Reporter().CaptureError(ctx, err, tags)
+}
+
+// CapturePanic 通过全局错误上报上报 panic
+func CapturePanic(ctx context.Context, recovered interface{}, stack []byte) {
+	Reporter().CapturePanic(ctx, recovered, stack)
+}
+
+// Flush 等待全局错误上报发送完缓冲的事件
+func Flush(timeout time.Duration) bool {
+	return Reporter().Flush(timeout)
+}
+
+// ContextTags 返回 ctx 中的请求字段（request_id、user_id、route）与 tags 合并后的标签
+func ContextTags(ctx context.Context, tags map[string]string) map[string]string {
+	merged := make(map[string]string, len(tags)+3)
+	fields := logger.ContextFields(ctx)
+	if fields.RequestID != "" {
+		merged["request_id"] = fields.RequestID
+	}
+	if fields.UserID != "" {
+		merged["user_id"] = fields.UserID
+	}
+	if fields.Route != "" {
+		merged["route"] = fields.Route
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
+
+// skipReportKey 标记日志事件已单独上报，LogHook 不再重复上报
+type skipReportKey struct{}
+
+// WithoutLogReport 返回的 ctx 用于 logger.FromContext 时，该日志的错误级别事件不会被 LogHook 上报
+// 注意: 用于已经调用 CaptureError / CapturePanic 上报过（带完整错误信息）的日志，避免重复事件
+func WithoutLogReport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipReportKey{}, true)
+}
+
+// LogHook 将 error 及以上级别的日志上报为错误，通过 logger.AddHook 注册
+// 注意: hook 读取不到日志字段，上报内容为日志消息；请求字段从 logger.FromContext 挂载的 ctx 中读取
+type LogHook struct{}
+
+func (LogHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level < zerolog.ErrorLevel || level == zerolog.NoLevel || level == zerolog.Disabled {
+		return
+	}
+	ctx := e.GetCtx()
+	if skip, _ := ctx.Value(skipReportKey{}).(bool); skip {
+		return
+	}
+	if msg == "" {
+		msg = level.String() + " log event"
+	}
+	CaptureError(ctx, errors.New(msg), map[string]string{"source": "log", "level": level.String()})
+}
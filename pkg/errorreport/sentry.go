@@ -0,0 +1,84 @@
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// sentryBufferSize Sentry 发送缓冲区大小，缓冲区满时新事件直接丢弃
+const sentryBufferSize = 100
+
+// SentryOptions Sentry 配置
+type SentryOptions struct {
+	DSN         string
+	Environment string
+	Release     string
+	// Transport 发送事件的实现，为空时使用带缓冲的异步 HTTP 发送（测试中替换）
+	Transport sentry.Transport
+}
+
+// SentryReporter 基于 Sentry 的错误上报
+type SentryReporter struct {
+	client *sentry.Client
+}
+
+// NewSentryReporter 创建 Sentry 错误上报
+// 返回: DSN 无效时返回错误
+func NewSentryReporter(opts SentryOptions) (*SentryReporter, error) {
+	transport := opts.Transport
+	if transport == nil {
+		httpTransport := sentry.NewHTTPTransport()
+		httpTransport.BufferSize = sentryBufferSize
+		transport = httpTransport
+	}
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:              opts.DSN,
+		Environment:      opts.Environment,
+		Release:          opts.Release,
+		Transport:        transport,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init sentry: %w", err)
+	}
+	return &SentryReporter{client: client}, nil
+}
+
+// scope 根据 ctx 中的请求字段和 tags 构造事件作用域
+func (r *SentryReporter) scope(ctx context.Context, tags map[string]string) *sentry.Scope {
+	scope := sentry.NewScope()
+	merged := ContextTags(ctx, tags)
+	if userID, ok := merged["user_id"]; ok {
+		scope.SetUser(sentry.User{ID: userID})
+	}
+	scope.SetTags(merged)
+	return scope
+}
+
+func (r *SentryReporter) CaptureError(ctx context.Context, err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	r.client.CaptureException(err, &sentry.EventHint{Context: ctx, OriginalException: err}, r.scope(ctx, tags))
+}
+
+func (r *SentryReporter) CapturePanic(ctx context.Context, recovered interface{}, stack []byte) {
+	value := fmt.Sprint(recovered)
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelFatal
+	event.Message = value
+	event.Exception = []sentry.Exception{{
+		Type:       "panic",
+		Value:      value,
+		Stacktrace: sentry.NewStacktrace(),
+	}}
+	event.Extra["stack"] = string(stack)
+	r.client.CaptureEvent(event, &sentry.EventHint{Context: ctx, RecoveredException: recovered}, r.scope(ctx, map[string]string{"source": "panic"}))
+}
+
+func (r *SentryReporter) Flush(timeout time.Duration) bool {
+	return r.client.Flush(timeout)
+}
@@ -12,39 +12,149 @@ type Claims struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Username string    `json:"username"`
 	Role     string    `json:"role"`
+	// ImpersonatorID 模拟登录时发起操作的管理员 ID，普通登录为空
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
+	// TokenType token 类型：访问 token 为空，刷新 token 为 refresh，草稿预览 token 为 preview
+	TokenType string `json:"typ,omitempty"`
+	// ArticleID 草稿预览 token 可以查看的文章，其他 token 为空
+	ArticleID *uuid.UUID `json:"article_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenTypeRefresh 刷新 token 的类型
+const TokenTypeRefresh = "refresh"
+
+// TokenTypePreview 草稿预览 token 的类型
+const TokenTypePreview = "preview"
+
+// DefaultRefreshTTL 刷新 token 的默认有效期
+const DefaultRefreshTTL = 30 * 24 * time.Hour
+
+// ErrWrongTokenType token 类型不符（如用刷新 token 访问接口）
+var ErrWrongTokenType = errors.New("wrong token type")
+
 type JWTManager struct {
 	secret     string
 	expireTime time.Duration
+	refreshTTL time.Duration
 }
 
 func NewJWTManager(secret string, expireTime time.Duration) *JWTManager {
 	return &JWTManager{
 		secret:     secret,
 		expireTime: expireTime,
+		refreshTTL: DefaultRefreshTTL,
+	}
+}
+
+// WithRefreshTTL 设置刷新 token 的有效期（默认 DefaultRefreshTTL）
+// 返回: 管理器本身，便于链式调用
+func (m *JWTManager) WithRefreshTTL(ttl time.Duration) *JWTManager {
+	m.refreshTTL = ttl
+	return m
+}
+
+// ExpireTime 访问 token 的有效期
+func (m *JWTManager) ExpireTime() time.Duration {
+	return m.expireTime
+}
+
+// GenerateRefreshToken 生成刷新 token
+// 返回: token 和其声明（ID 即 jti，用于服务端登记和吊销；ExpiresAt 为固定的过期时间，刷新访问 token 时不会延长）
+func (m *JWTManager) GenerateRefreshToken(userID uuid.UUID) (string, *Claims, error) {
+	claims := &Claims{UserID: userID, TokenType: TokenTypeRefresh}
+	token, err := m.sign(claims, m.refreshTTL)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// ValidateRefreshToken 校验刷新 token 的签名、有效期和类型
+// 注意: 只校验 token 本身，是否已被吊销由调用方查询服务端记录
+func (m *JWTManager) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, ErrWrongTokenType
+	}
+	return claims, nil
+}
+
+// GeneratePreviewToken 生成草稿预览 token
+// articleID: 可以预览的文章；issuerID: 生成 token 的用户（记录在 user_id 中）
+// 返回: token 和其声明（ID 即 jti，用于服务端登记和吊销）
+// 注意: 预览 token 的类型不为空，不能作为访问 token 使用
+func (m *JWTManager) GeneratePreviewToken(articleID, issuerID uuid.UUID, ttl time.Duration) (string, *Claims, error) {
+	claims := &Claims{UserID: issuerID, TokenType: TokenTypePreview, ArticleID: &articleID}
+	token, err := m.sign(claims, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// ValidatePreviewToken 校验草稿预览 token 的签名、有效期和类型
+// 注意: 只校验 token 本身，是否已被吊销由调用方查询服务端记录
+func (m *JWTManager) ValidatePreviewToken(tokenString string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
 	}
+	if claims.TokenType != TokenTypePreview || claims.ArticleID == nil {
+		return nil, ErrWrongTokenType
+	}
+	return claims, nil
 }
 
 func (m *JWTManager) GenerateToken(userID uuid.UUID, username, role string) (string, error) {
-	claims := &Claims{
+	return m.sign(&Claims{
 		UserID:   userID,
 		Username: username,
 		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.expireTime)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			ID:        uuid.New().String(),
-		},
+	}, m.expireTime)
+}
+
+// GenerateImpersonationToken 生成模拟登录 token（携带 impersonator_id，有效期由调用方指定）
+func (m *JWTManager) GenerateImpersonationToken(userID uuid.UUID, username, role string, impersonatorID uuid.UUID, ttl time.Duration) (string, error) {
+	return m.sign(&Claims{
+		UserID:         userID,
+		Username:       username,
+		Role:           role,
+		ImpersonatorID: &impersonatorID,
+	}, ttl)
+}
+
+func (m *JWTManager) sign(claims *Claims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ID:        uuid.New().String(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(m.secret))
 }
 
+// ValidateToken 校验访问 token
+// 注意: 刷新 token 不能作为访问 token 使用，返回 ErrWrongTokenType
 func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != "" {
+		return nil, ErrWrongTokenType
+	}
+	return claims, nil
+}
+
+// parse 校验签名和有效期并解析声明
+func (m *JWTManager) parse(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// contextKey 请求上下文中日志字段的键
+type contextKey struct{}
+
+// Fields 附加到日志的请求上下文字段
+type Fields struct {
+	RequestID string
+	UserID    string
+	Route     string
+	// ImpersonatorID 模拟登录时发起模拟的管理员 ID，普通会话为空
+	ImpersonatorID string
+}
+
+// fieldsFromContext 读取上下文中的日志字段
+func fieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return Fields{}
+	}
+	fields, _ := ctx.Value(contextKey{}).(Fields)
+	return fields
+}
+
+// WithRequestID 返回带请求 ID 的上下文
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	fields := fieldsFromContext(ctx)
+	fields.RequestID = requestID
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// WithUserID 返回带当前用户 ID 的上下文
+func WithUserID(ctx context.Context, userID string) context.Context {
+	fields := fieldsFromContext(ctx)
+	fields.UserID = userID
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// WithImpersonatorID 返回带模拟登录管理员 ID 的上下文，日志可区分管理员代为操作的请求
+func WithImpersonatorID(ctx context.Context, impersonatorID string) context.Context {
+	fields := fieldsFromContext(ctx)
+	fields.ImpersonatorID = impersonatorID
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// WithRoute 返回带路由模板（如 /api/v1/articles/:id）的上下文
+func WithRoute(ctx context.Context, route string) context.Context {
+	fields := fieldsFromContext(ctx)
+	fields.Route = route
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// ContextFields 返回上下文中的日志字段（错误上报等需要同样的请求信息时使用）
+func ContextFields(ctx context.Context) Fields {
+	return fieldsFromContext(ctx)
+}
+
+// FromContext 获取带请求上下文字段（request_id、user_id、impersonator_id、route）的日志
+// 参数:
+//   - ctx: 请求上下文，由请求 ID 和认证中间件写入字段
+//   - module: 可选的模块名，与 GetLogger 相同
+//
+// 注意: 上下文中没有字段时日志内容与 GetLogger 相同；ctx 同时挂到日志事件上，hook 可以通过 Event.GetCtx 读取；
+// 后台任务脱离请求时使用 context.WithoutCancel 保留字段
+func FromContext(ctx context.Context, module ...string) zerolog.Logger {
+	l := GetLogger(module...)
+	if ctx == nil {
+		return l
+	}
+	c := l.With().Ctx(ctx)
+	fields := fieldsFromContext(ctx)
+	if fields.RequestID != "" {
+		c = c.Str("request_id", fields.RequestID)
+	}
+	if fields.UserID != "" {
+		c = c.Str("user_id", fields.UserID)
+	}
+	if fields.ImpersonatorID != "" {
+		c = c.Str("impersonator_id", fields.ImpersonatorID)
+	}
+	if fields.Route != "" {
+		c = c.Str("route", fields.Route)
+	}
+	return c.Logger()
+}
@@ -1,50 +1,205 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// Options 日志配置
+type Options struct {
+	Level string
+	// File 日志文件路径，为空时只输出到控制台
+	File string
+	// MaxSizeMB 单个日志文件的最大大小（MB），超过后轮转；0 表示使用默认值 100MB
+	MaxSizeMB int
+	// MaxBackups 保留的历史日志文件数，0 表示不按数量清理
+	MaxBackups int
+	// MaxAgeDays 历史日志文件的保留天数，0 表示不按时间清理
+	MaxAgeDays int
+	// Compress 是否用 gzip 压缩历史日志文件
+	Compress bool
+	// ModuleLevels 按模块覆盖日志级别（如 search: debug），通过 GetLogger(module) 获取的日志生效
+	ModuleLevels map[string]string
+}
+
+var (
+	// fileMu 保护 fileWriter 的替换（Init）和重新打开（Reopen）
+	fileMu     sync.Mutex
+	fileWriter *lumberjack.Logger
+
+	// levelMu 保护 moduleLevels 和 hooks
+	levelMu      sync.RWMutex
+	moduleLevels map[string]zerolog.Level
+	hooks        []zerolog.Hook
+)
+
+// Init 初始化日志，日志文件使用默认轮转参数
 func Init(level, logFile string) error {
-	// 解析日志级别
-	logLevel, err := zerolog.ParseLevel(level)
+	return InitWithOptions(Options{Level: level, File: logFile})
+}
+
+// InitWithOptions 初始化日志
+//
+// 设计考虑：
+// - 指定日志文件时同时输出到控制台（容器部署通过 stdout 收集）和文件
+// - 文件按大小轮转、按数量 / 天数清理，轮转在写入锁内完成，并发写入不会交错或丢失
+// - 使用外部 logrotate 时，向进程发送 SIGUSR1 重新打开日志文件（见 Reopen）
+// - 全局级别取根级别和模块级别中的最低值，根日志自身仍按 Level 过滤，模块覆盖可以比根级别更详细
+func InitWithOptions(opts Options) error {
+	logLevel, err := applyLevels(opts.Level, opts.ModuleLevels)
 	if err != nil {
-		logLevel = zerolog.DebugLevel
+		return err
 	}
-	zerolog.SetGlobalLevel(logLevel)
 
 	// 配置时间格式
 	zerolog.TimeFieldFormat = time.RFC3339
 
-	// 如果指定了日志文件，则同时输出到文件和控制台
-	if logFile != "" {
-		// 确保日志目录存在
-		if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
-			return err
-		}
+	consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
 
-		file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err != nil {
-			return err
-		}
+	// 未指定日志文件时仅输出到控制台
+	if opts.File == "" {
+		setFileWriter(nil)
+		log.Logger = withHooks(zerolog.New(consoleWriter).Level(logLevel).With().Timestamp().Logger())
+		return nil
+	}
 
-		// 多写入器：同时输出到文件和控制台
-		consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
-		multi := zerolog.MultiLevelWriter(consoleWriter, file)
-		log.Logger = zerolog.New(multi).With().Timestamp().Logger()
-	} else {
-		// 仅输出到控制台
-		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
+	// 确保日志目录存在并且可写
+	if err := os.MkdirAll(filepath.Dir(opts.File), 0755); err != nil {
+		return err
 	}
+	file, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	writer := &lumberjack.Logger{
+		Filename:   opts.File,
+		MaxSize:    opts.MaxSizeMB,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAgeDays,
+		Compress:   opts.Compress,
+		LocalTime:  true,
+	}
+	setFileWriter(writer)
+	watchReopenSignal()
+
+	// 多写入器：同时输出到文件和控制台
+	multi := zerolog.MultiLevelWriter(consoleWriter, writer)
+	log.Logger = withHooks(zerolog.New(multi).Level(logLevel).With().Timestamp().Logger())
+	return nil
+}
+
+// AddHook 为全局日志添加 hook（如错误上报），重新 Init 后仍然生效
+func AddHook(hook zerolog.Hook) {
+	levelMu.Lock()
+	hooks = append(hooks, hook)
+	levelMu.Unlock()
+	log.Logger = log.Logger.Hook(hook)
+}
+
+// withHooks 为新建的根日志挂载已注册的 hook
+func withHooks(l zerolog.Logger) zerolog.Logger {
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+	for _, hook := range hooks {
+		l = l.Hook(hook)
+	}
+	return l
+}
 
+// SetLevels 在运行中修改根日志级别和模块级别覆盖（配置热加载），不重新打开日志文件
+// 返回: 模块级别无效时返回错误，此时级别保持不变
+func SetLevels(level string, modules map[string]string) error {
+	logLevel, err := applyLevels(level, modules)
+	if err != nil {
+		return err
+	}
+	log.Logger = log.Logger.Level(logLevel)
 	return nil
 }
 
-func GetLogger() zerolog.Logger {
-	return log.Logger
+// applyLevels 解析并设置全局级别和模块级别，返回根日志级别（无效时为 debug）
+func applyLevels(level string, modules map[string]string) (zerolog.Level, error) {
+	logLevel, err := zerolog.ParseLevel(level)
+	if err != nil {
+		logLevel = zerolog.DebugLevel
+	}
+	levels, err := parseModuleLevels(modules)
+	if err != nil {
+		return logLevel, err
+	}
+	globalLevel := logLevel
+	for _, l := range levels {
+		if l < globalLevel {
+			globalLevel = l
+		}
+	}
+	zerolog.SetGlobalLevel(globalLevel)
+	levelMu.Lock()
+	moduleLevels = levels
+	levelMu.Unlock()
+	return logLevel, nil
+}
+
+// parseModuleLevels 解析模块级别覆盖
+// 返回: 级别名无效时返回错误（包含模块名）
+func parseModuleLevels(levels map[string]string) (map[string]zerolog.Level, error) {
+	parsed := make(map[string]zerolog.Level, len(levels))
+	for module, name := range levels {
+		level, err := zerolog.ParseLevel(name)
+		if err != nil || name == "" {
+			return nil, fmt.Errorf("invalid log level %q for module %s", name, module)
+		}
+		parsed[module] = level
+	}
+	return parsed, nil
+}
+
+// setFileWriter 替换当前日志文件，并关闭之前的文件
+func setFileWriter(writer *lumberjack.Logger) {
+	fileMu.Lock()
+	previous := fileWriter
+	fileWriter = writer
+	fileMu.Unlock()
+
+	if previous != nil && previous != writer {
+		previous.Close()
+	}
 }
 
+// Reopen 关闭当前日志文件，下一次写入时按原路径重新打开（外部 logrotate 移走文件后使用）
+// 注意: 未配置日志文件时不做任何操作
+func Reopen() error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	if fileWriter == nil {
+		return nil
+	}
+	return fileWriter.Close()
+}
+
+// GetLogger 获取日志
+// 参数:
+//   - module: 可选的模块名（如 "search"），指定时日志带 module 字段，并使用该模块覆盖的级别（未覆盖时与根级别相同）
+func GetLogger(module ...string) zerolog.Logger {
+	if len(module) == 0 {
+		return log.Logger
+	}
+	name := module[0]
+	l := log.Logger
+	levelMu.RLock()
+	level, ok := moduleLevels[name]
+	levelMu.RUnlock()
+	if ok {
+		l = l.Level(level)
+	}
+	return l.With().Str("module", name).Logger()
+}
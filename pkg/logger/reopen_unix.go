@@ -0,0 +1,28 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var reopenOnce sync.Once
+
+// watchReopenSignal 收到 SIGUSR1 时重新打开日志文件（只启动一次）
+func watchReopenSignal() {
+	reopenOnce.Do(func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGUSR1)
+		go func() {
+			for range ch {
+				if err := Reopen(); err != nil {
+					l := GetLogger()
+					l.Error().Err(err).Msg("failed to reopen log file")
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package logger
+
+// watchReopenSignal Windows 没有 SIGUSR1，需要时直接调用 Reopen
+func watchReopenSignal() {}
@@ -0,0 +1,360 @@
+// Package markdown 将文章正文（Markdown）渲染为可直接嵌入页面的安全 HTML，用于导出等不经过前端渲染的场景
+//
+// 只支持常用子集：ATX 标题、段落、加粗 / 斜体、行内代码、围栏代码块、引用、无序 / 有序列表（可嵌套）、分隔线、链接和图片。
+// 先转义、后生成标签：原文中的 HTML 一律按文本输出，链接和图片只接受 http(s)、mailto 和站内相对地址，
+// 其余地址（如 javascript:）只保留文字，因此输出不需要再经过单独的 HTML 过滤。
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Options 渲染选项
+type Options struct {
+	// ImageURL 改写图片地址（如把站内相对地址补全为绝对地址），为 nil 时原样使用；只对通过安全检查的地址调用
+	ImageURL func(string) string
+	// LinkURL 改写链接地址，为 nil 时原样使用；只对通过安全检查的地址调用
+	LinkURL func(string) string
+}
+
+var (
+	headingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	hrRe        = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceRe     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	bulletRe    = regexp.MustCompile(`^( {0,3})[-*+][ \t]+`)
+	orderedRe   = regexp.MustCompile(`^( {0,3})(\d{1,9})[.)][ \t]+`)
+	languageRe  = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+	urlSchemeRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*):`)
+)
+
+// Render 将 Markdown 渲染为 HTML
+func Render(src string, opts Options) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	r := &renderer{opts: opts}
+	return strings.Join(r.blocks(strings.Split(src, "\n")), "\n")
+}
+
+type renderer struct {
+	opts Options
+}
+
+// blocks 按块解析各行，返回每个块的 HTML
+func (r *renderer) blocks(lines []string) []string {
+	var out, para []string
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, "<p>"+r.inline(strings.Join(para, "\n"))+"</p>")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+
+		case fenceRe.MatchString(line):
+			flush()
+			m := fenceRe.FindStringSubmatch(line)
+			fence := m[1]
+			var code []string
+			for i++; i < len(lines); i++ {
+				if t := strings.TrimSpace(lines[i]); strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			class := ""
+			if languageRe.MatchString(m[2]) {
+				class = ` class="language-` + html.EscapeString(m[2]) + `"`
+			}
+			out = append(out, "<pre><code"+class+">"+html.EscapeString(strings.Join(code, "\n"))+"</code></pre>")
+
+		case headingRe.MatchString(line):
+			flush()
+			m := headingRe.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			out = append(out, "<h"+level+">"+r.inline(m[2])+"</h"+level+">")
+
+		case hrRe.MatchString(line):
+			flush()
+			out = append(out, "<hr>")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(t, ">") {
+					break
+				}
+				t = strings.TrimPrefix(t, ">")
+				quoted = append(quoted, strings.TrimPrefix(t, " "))
+			}
+			i--
+			out = append(out, "<blockquote>\n"+strings.Join(r.blocks(quoted), "\n")+"\n</blockquote>")
+
+		case bulletRe.MatchString(line) || orderedRe.MatchString(line):
+			flush()
+			var list string
+			list, i = r.list(lines, i)
+			out = append(out, list)
+			i--
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return out
+}
+
+// list 解析从 start 开始的列表，返回列表的 HTML 和列表之后的第一行
+// 缩进的行属于上一个列表项，按块递归解析（支持嵌套列表）；类型不同的列表项或未缩进的普通行结束列表
+func (r *renderer) list(lines []string, start int) (string, int) {
+	ordered := !bulletRe.MatchString(lines[start])
+	itemRe := bulletRe
+	tag := "ul"
+	open := "<ul>"
+	if ordered {
+		itemRe = orderedRe
+		tag = "ol"
+		open = "<ol>"
+		if n, _ := strconv.Atoi(orderedRe.FindStringSubmatch(lines[start])[2]); n != 1 {
+			open = `<ol start="` + strconv.Itoa(n) + `">`
+		}
+	}
+
+	var items [][]string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := itemRe.FindStringIndex(line); loc != nil && (i == start || !indented(line)) {
+			items = append(items, []string{line[loc[1]:]})
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			// 空行之后仍是缩进内容或同类列表项时列表继续
+			if i+1 < len(lines) && (itemRe.MatchString(lines[i+1]) || indented(lines[i+1])) {
+				items[len(items)-1] = append(items[len(items)-1], "")
+				continue
+			}
+			break
+		}
+		if indented(line) {
+			items[len(items)-1] = append(items[len(items)-1], dedent(line))
+			continue
+		}
+		if bulletRe.MatchString(line) || orderedRe.MatchString(line) || isBlockStart(line) {
+			break
+		}
+		// 惰性续行：未缩进的普通文字接在上一项之后
+		items[len(items)-1] = append(items[len(items)-1], line)
+	}
+
+	var b strings.Builder
+	b.WriteString(open)
+	for _, item := range items {
+		blocks := r.blocks(item)
+		// 只有一个段落的列表项不包 <p>
+		if len(blocks) > 0 && strings.HasPrefix(blocks[0], "<p>") && strings.HasSuffix(blocks[0], "</p>") {
+			blocks[0] = strings.TrimSuffix(strings.TrimPrefix(blocks[0], "<p>"), "</p>")
+		}
+		b.WriteString("\n<li>" + strings.Join(blocks, "\n") + "</li>")
+	}
+	b.WriteString("\n</" + tag + ">")
+	return b.String(), i
+}
+
+// isBlockStart 判断一行是否开始一个新的非段落块
+func isBlockStart(line string) bool {
+	return fenceRe.MatchString(line) || headingRe.MatchString(line) || hrRe.MatchString(line) ||
+		strings.HasPrefix(strings.TrimSpace(line), ">")
+}
+
+func indented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+// dedent 去掉列表项内容的缩进（最多 4 个空格或 1 个制表符）
+func dedent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	n := len(line) - len(strings.TrimLeft(line, " "))
+	if n > 4 {
+		n = 4
+	}
+	return line[n:]
+}
+
+// inline 渲染行内元素，其余文字全部转义
+func (r *renderer) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!>|~", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			run := countRun(s[i:], '`')
+			if end := strings.Index(s[i+run:], s[i:i+run]); end >= 0 {
+				code := strings.TrimSpace(s[i+run : i+run+end])
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += run + end + run
+				continue
+			}
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if alt, url, n, ok := parseLink(s[i+1:]); ok {
+				if safe := safeURL(url); safe != "" {
+					if r.opts.ImageURL != nil {
+						safe = r.opts.ImageURL(safe)
+					}
+					b.WriteString(`<img src="` + html.EscapeString(safe) + `" alt="` + html.EscapeString(alt) + `">`)
+				} else {
+					b.WriteString(html.EscapeString(alt))
+				}
+				i += 1 + n
+				continue
+			}
+
+		case c == '[':
+			if text, url, n, ok := parseLink(s[i:]); ok {
+				if safe := safeURL(url); safe != "" {
+					if r.opts.LinkURL != nil {
+						safe = r.opts.LinkURL(safe)
+					}
+					b.WriteString(`<a href="` + html.EscapeString(safe) + `">` + r.inline(text) + "</a>")
+				} else {
+					b.WriteString(r.inline(text))
+				}
+				i += n
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if out, n, ok := r.emphasis(s, i); ok {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// emphasis 解析 s[i:] 开始的加粗（** / __）或斜体（* / _）
+// 注意: _ 只在词边界生效，避免 snake_case 之类的词被拆开
+func (r *renderer) emphasis(s string, i int) (string, int, bool) {
+	c := s[i]
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", 0, false
+	}
+	delim := s[i : i+1]
+	tag := "em"
+	if strings.HasPrefix(s[i:], delim+delim) {
+		delim += delim
+		tag = "strong"
+	}
+	body := s[i+len(delim):]
+	if body == "" || body[0] == ' ' || body[0] == '\n' {
+		return "", 0, false
+	}
+	for from := 0; ; {
+		end := strings.Index(body[from:], delim)
+		if end < 0 {
+			return "", 0, false
+		}
+		end += from
+		after := end + len(delim)
+		closes := end > 0 && body[end-1] != ' ' && body[end-1] != '\n'
+		if c == '_' && after < len(body) && isWordByte(body[after]) {
+			closes = false
+		}
+		// 斜体的结束符不能是加粗的一部分
+		if tag == "em" && (body[end-1] == c || after < len(body) && body[after] == c) {
+			closes = false
+		}
+		if closes {
+			return "<" + tag + ">" + r.inline(body[:end]) + "</" + tag + ">", len(delim) + after, true
+		}
+		from = after
+	}
+}
+
+// parseLink 解析 [text](url "title")，s 以 [ 开头
+// 返回: 文字、地址（不含标题）和消耗的字节数
+func parseLink(s string) (text, url string, n int, ok bool) {
+	depth := 0
+	closeBracket := -1
+	for i := 0; i < len(s) && closeBracket < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeBracket = i
+			}
+		}
+	}
+	if closeBracket < 0 || closeBracket+1 >= len(s) || s[closeBracket+1] != '(' {
+		return "", "", 0, false
+	}
+	rest := s[closeBracket+2:]
+	closeParen := strings.IndexByte(rest, ')')
+	if closeParen < 0 {
+		return "", "", 0, false
+	}
+	target := strings.TrimSpace(rest[:closeParen])
+	if sp := strings.IndexAny(target, " \t\n"); sp >= 0 {
+		target = target[:sp]
+	}
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	return s[1:closeBracket], target, closeBracket + 2 + closeParen + 1, true
+}
+
+// safeURL 只接受 http(s)、mailto 和不带协议的相对地址，其余返回空字符串
+func safeURL(u string) string {
+	u = strings.TrimSpace(u)
+	if u == "" || strings.ContainsAny(u, "\x00\r\n") {
+		return ""
+	}
+	if m := urlSchemeRe.FindStringSubmatch(u); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "http", "https", "mailto":
+		default:
+			return ""
+		}
+	}
+	return u
+}
+
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
@@ -2,6 +2,7 @@
 package metrics
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -56,6 +57,15 @@ var (
 		[]string{"operation", "table"},
 	)
 
+	// 数据库查询错误数（不含记录不存在）
+	dbQueryErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "Total number of failed database queries",
+		},
+		[]string{"operation", "table"},
+	)
+
 	// Redis操作总数
 	redisOperationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -107,18 +117,130 @@ var (
 		},
 	)
 
-	// 业务指标：当前在线用户数（示例）
+	// 业务指标：登录成功次数（按登录方式：password / sms）
+	userLoginsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "user_logins_total",
+			Help: "Total number of successful logins",
+		},
+		[]string{"method"},
+	)
+
+	// 业务指标：登录锁定次数（按锁定范围：email / ip）
+	loginLockoutsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "login_lockouts_total",
+			Help: "Total number of login lockouts after repeated failures",
+		},
+		[]string{"scope"},
+	)
+
+	// 业务指标：短信验证码发送数
+	smsCodesSentTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sms_codes_sent_total",
+			Help: "Total number of SMS verification codes sent",
+		},
+	)
+
+	// 业务指标：全文搜索次数（按实际执行搜索的后端：elasticsearch / database）
+	searchesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "searches_total",
+			Help: "Total number of full-text searches executed",
+		},
+		[]string{"backend"},
+	)
+
+	// 业务指标：最近 15 分钟内有认证请求的用户数（由定时任务刷新）
 	activeUsers = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "active_users",
-			Help: "Number of currently active users",
+			Help: "Number of users with authenticated requests in the last 15 minutes",
+		},
+	)
+
+	// 缓存命中次数（按缓存类型）
+	cacheHitsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_hits_total",
+			Help: "Total number of cache hits",
+		},
+		[]string{"cache"},
+	)
+
+	// 缓存未命中次数（按缓存类型，含读取失败）
+	cacheMissesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_misses_total",
+			Help: "Total number of cache misses",
+		},
+		[]string{"cache"},
+	)
+
+	// 数据库连接池状态（按 state: open / in_use / idle）
+	dbPoolConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "db_pool_connections",
+			Help: "Database pool connections by state",
+		},
+		[]string{"state"},
+	)
+
+	// 数据库连接池累计等待次数
+	dbPoolWaitCount = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "db_pool_wait_count",
+			Help: "Total number of connections waited for",
 		},
 	)
+
+	// 数据库连接池累计等待时长
+	dbPoolWaitSeconds = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "db_pool_wait_duration_seconds",
+			Help: "Total time blocked waiting for a new connection",
+		},
+	)
+
+	// Redis连接池状态（按 state: total / idle）
+	redisPoolConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_pool_connections",
+			Help: "Redis pool connections by state",
+		},
+		[]string{"state"},
+	)
+
+	// Redis连接池累计获取连接超时次数
+	redisPoolTimeouts = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "redis_pool_timeouts",
+			Help: "Total number of Redis pool wait timeouts",
+		},
+	)
+
+	// Elasticsearch 异步索引队列深度
+	searchPendingOperations = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "search_index_pending_operations",
+			Help: "Number of async search index operations not yet completed",
+		},
+	)
+
+	// 运行中的后台任务数（按任务类型）
+	backgroundJobsRunning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "background_jobs_running",
+			Help: "Number of background jobs currently running",
+		},
+		[]string{"kind"},
+	)
 )
 
 // RecordHTTPRequest 记录HTTP请求指标
 func RecordHTTPRequest(method, path string, statusCode int, duration time.Duration) {
-	status := prometheus.Labels{"method": method, "path": path, "status": string(rune(statusCode))}
+	status := prometheus.Labels{"method": method, "path": path, "status": strconv.Itoa(statusCode)}
 	httpRequestsTotal.With(status).Inc()
 	httpRequestDuration.WithLabelValues(method, path).Observe(duration.Seconds())
 }
@@ -139,6 +261,11 @@ func RecordDBQuery(operation, table string, duration time.Duration) {
 	dbQueryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
 }
 
+// RecordDBQueryError 记录数据库查询错误
+func RecordDBQueryError(operation, table string) {
+	dbQueryErrorsTotal.WithLabelValues(operation, table).Inc()
+}
+
 // RecordRedisOperation 记录Redis操作指标
 func RecordRedisOperation(operation string, duration time.Duration) {
 	redisOperationsTotal.WithLabelValues(operation).Inc()
@@ -165,11 +292,70 @@ func RecordArticleLike() {
 	articleLikesTotal.Inc()
 }
 
+// RecordUserLogin 记录登录成功
+// method: 登录方式（password / sms）
+func RecordUserLogin(method string) {
+	userLoginsTotal.WithLabelValues(method).Inc()
+}
+
+// RecordLoginLockout 记录一次登录锁定（失败次数达到上限）
+// scope: 锁定范围（email / ip）
+func RecordLoginLockout(scope string) {
+	loginLockoutsTotal.WithLabelValues(scope).Inc()
+}
+
+// RecordSMSCodeSent 记录短信验证码发送
+func RecordSMSCodeSent() {
+	smsCodesSentTotal.Inc()
+}
+
+// RecordSearch 记录一次全文搜索
+// backend: 实际执行搜索的后端（elasticsearch / database）
+func RecordSearch(backend string) {
+	searchesTotal.WithLabelValues(backend).Inc()
+}
+
 // SetActiveUsers 设置当前活跃用户数
 func SetActiveUsers(count float64) {
 	activeUsers.Set(count)
 }
 
+// RecordCacheHit 记录缓存命中
+func RecordCacheHit(cache string) {
+	cacheHitsTotal.WithLabelValues(cache).Inc()
+}
+
+// RecordCacheMiss 记录缓存未命中
+func RecordCacheMiss(cache string) {
+	cacheMissesTotal.WithLabelValues(cache).Inc()
+}
+
+// SetDBPoolStats 设置数据库连接池状态
+func SetDBPoolStats(open, inUse, idle int, waitCount int64, waitDuration time.Duration) {
+	dbPoolConnections.WithLabelValues("open").Set(float64(open))
+	dbPoolConnections.WithLabelValues("in_use").Set(float64(inUse))
+	dbPoolConnections.WithLabelValues("idle").Set(float64(idle))
+	dbPoolWaitCount.Set(float64(waitCount))
+	dbPoolWaitSeconds.Set(waitDuration.Seconds())
+}
+
+// SetRedisPoolStats 设置Redis连接池状态
+func SetRedisPoolStats(total, idle, timeouts uint32) {
+	redisPoolConnections.WithLabelValues("total").Set(float64(total))
+	redisPoolConnections.WithLabelValues("idle").Set(float64(idle))
+	redisPoolTimeouts.Set(float64(timeouts))
+}
+
+// SetSearchPendingOperations 设置 Elasticsearch 异步索引队列深度
+func SetSearchPendingOperations(n int64) {
+	searchPendingOperations.Set(float64(n))
+}
+
+// SetBackgroundJobsRunning 设置指定类型运行中的后台任务数
+func SetBackgroundJobsRunning(kind string, n int64) {
+	backgroundJobsRunning.WithLabelValues(kind).Set(float64(n))
+}
+
 // MetricsMiddleware Gin中间件，自动记录HTTP请求指标
 func MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
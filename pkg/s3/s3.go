@@ -0,0 +1,159 @@
+// Package s3 提供 S3 兼容对象存储（AWS S3、MinIO 等）的最小客户端：上传和下载单个对象
+//
+// 请求使用 AWS Signature Version 4 签名，请求体不参与签名（UNSIGNED-PAYLOAD），因此应通过 HTTPS 访问。
+// 对象地址使用 path-style（Endpoint/bucket/key），兼容不支持虚拟主机方式的自建服务。
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// unsignedPayload 请求体不参与签名
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+	// errorBodyLimit 错误响应最多读取的字节数
+	errorBodyLimit = 4096
+)
+
+// Client S3 兼容对象存储客户端
+type Client struct {
+	// Endpoint 服务地址，如 https://s3.us-east-1.amazonaws.com 或 http://minio:9000
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// HTTPClient 为 nil 时使用 http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// PutObject 上传对象
+// body: 对象内容，size 为其字节数（S3 要求请求携带 Content-Length）
+func (c *Client) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64) error {
+	req, err := c.newRequest(ctx, http.MethodPut, bucket, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetObject 下载对象，调用方负责关闭返回的 ReadCloser
+func (c *Client) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, bucket, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, bucket, key string, body io.Reader) (*http.Request, error) {
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("s3: bucket and key are required")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(c.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", c.Endpoint)
+	}
+	endpoint.Path += "/" + bucket + "/" + strings.TrimLeft(key, "/")
+	return http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	c.sign(req, unsignedPayload, time.Now().UTC())
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+		return nil, fmt.Errorf("s3: %s %s: unexpected status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// sign 按 Signature Version 4 为请求添加 X-Amz-Date 和 Authorization 头
+// 参与签名的头：host、content-type、range 以及全部 x-amz-* 头
+func (c *Client) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.Format(amzDateFormat)
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"", // 不使用查询参数
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretKey), date)
+	key = hmacSHA256(key, c.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
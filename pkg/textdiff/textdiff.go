@@ -0,0 +1,296 @@
+// Package textdiff 提供按词或按行比较两段文本的差异（Myers 差分算法）
+//
+// 比较以词（连续的字母数字）、空白、标点和单个中日韩字符为单位，而不是按字节，结果适合直接展示给编辑。
+// 先去掉公共前后缀，再在剩余部分上执行 O((N+M)D) 的 Myers 算法；编辑距离 D 超过上限或 ctx 结束时放弃，
+// 由调用方决定退回按行比较或整段替换（见 Compare），保证大文本的耗时和内存有界。
+package textdiff
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op 差异片段的类型
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Hunk 一段连续的相同、新增或删除的文本
+type Hunk struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+const (
+	// MaxEdits 默认的编辑距离上限（按词或行计），Myers 算法的内存与其平方成正比
+	MaxEdits = 2000
+	// MaxInputBytes 两段文本合计的最大字节数
+	MaxInputBytes = 2 << 20
+)
+
+var (
+	// ErrTooComplex 差异超过编辑距离上限
+	ErrTooComplex = errors.New("textdiff: too many differences")
+	// ErrInputTooLarge 输入超过 MaxInputBytes
+	ErrInputTooLarge = errors.New("textdiff: input too large")
+)
+
+// Words 按词比较
+// 返回: 依次拼接 Equal 和 Delete 片段得到 a，拼接 Equal 和 Insert 片段得到 b；超过编辑距离上限时返回 ErrTooComplex
+func Words(ctx context.Context, a, b string, maxEdits int) ([]Hunk, error) {
+	return diff(ctx, splitWords(a), splitWords(b), maxEdits)
+}
+
+// Lines 按行比较，每行包含结尾的换行符
+func Lines(ctx context.Context, a, b string, maxEdits int) ([]Hunk, error) {
+	return diff(ctx, splitLines(a), splitLines(b), maxEdits)
+}
+
+// Compare 比较两段文本，优先按词，差异过多时退回按行，仍然过多时整段替换
+// 返回: 输入超过 MaxInputBytes 时返回 ErrInputTooLarge；ctx 结束时返回 ctx.Err()
+func Compare(ctx context.Context, a, b string) ([]Hunk, error) {
+	if len(a)+len(b) > MaxInputBytes {
+		return nil, ErrInputTooLarge
+	}
+	hunks, err := Words(ctx, a, b, MaxEdits)
+	if errors.Is(err, ErrTooComplex) {
+		hunks, err = Lines(ctx, a, b, MaxEdits)
+	}
+	if errors.Is(err, ErrTooComplex) {
+		return replaceAll(a, b), nil
+	}
+	return hunks, err
+}
+
+// HTML 把差异渲染为 HTML：新增的文本包裹在 <ins> 中，删除的包裹在 <del> 中，文本全部转义
+func HTML(hunks []Hunk) string {
+	var sb strings.Builder
+	for _, h := range hunks {
+		text := html.EscapeString(h.Text)
+		switch h.Op {
+		case Insert:
+			sb.WriteString("<ins>" + text + "</ins>")
+		case Delete:
+			sb.WriteString("<del>" + text + "</del>")
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+func replaceAll(a, b string) []Hunk {
+	var hunks []Hunk
+	if a != "" {
+		hunks = append(hunks, Hunk{Op: Delete, Text: a})
+	}
+	if b != "" {
+		hunks = append(hunks, Hunk{Op: Insert, Text: b})
+	}
+	return hunks
+}
+
+// splitWords 切分为词、空白串、单个标点和单个中日韩字符
+func splitWords(s string) []string {
+	var tokens []string
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		n := size
+		switch {
+		case isCJK(r):
+		case unicode.IsSpace(r):
+			n = spanOf(s, n, unicode.IsSpace)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			n = spanOf(s, n, func(r rune) bool {
+				return (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') && !isCJK(r)
+			})
+		}
+		tokens = append(tokens, s[:n])
+		s = s[n:]
+	}
+	return tokens
+}
+
+// spanOf 从 s[start:] 开始连续满足 f 的字符结束位置
+func spanOf(s string, start int, f func(rune) bool) int {
+	for start < len(s) {
+		r, size := utf8.DecodeRuneInString(s[start:])
+		if !f(r) {
+			break
+		}
+		start += size
+	}
+	return start
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func diff(ctx context.Context, a, b []string, maxEdits int) ([]Hunk, error) {
+	// 公共前后缀不参与 Myers 计算，修订之间通常只改动一小部分
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var hb hunkBuilder
+	hb.add(Equal, a[:prefix]...)
+	if err := myers(ctx, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], maxEdits, &hb); err != nil {
+		return nil, err
+	}
+	hb.add(Equal, a[len(a)-suffix:]...)
+	return hb.result(), nil
+}
+
+// myers 计算最短编辑脚本并按顺序写入 hb
+// 每一步只保存 k ∈ [-d-1, d+1] 范围内的 V，内存为 O(D²)
+func myers(ctx context.Context, a, b []string, maxEdits int, hb *hunkBuilder) error {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		hb.add(Delete, a...)
+		hb.add(Insert, b...)
+		return nil
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		if d > maxEdits {
+			return ErrTooComplex
+		}
+		if d%64 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		lo, hi := offset-d-1, offset+d+2
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(v) {
+			hi = len(v)
+		}
+		snapshot := make([]int, hi-lo)
+		copy(snapshot, v[lo:hi])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				backtrack(a, b, trace, offset, hb)
+				return nil
+			}
+		}
+	}
+	return ErrTooComplex
+}
+
+// backtrack 从终点沿 trace 回溯出编辑脚本
+func backtrack(a, b []string, trace [][]int, offset int, hb *hunkBuilder) {
+	type step struct {
+		op    Op
+		token string
+	}
+	var steps []step
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		lo := offset - d - 1
+		if lo < 0 {
+			lo = 0
+		}
+		get := func(k int) int { return trace[d][offset+k-lo] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			steps = append(steps, step{Equal, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				steps = append(steps, step{Insert, b[y-1]})
+			} else {
+				steps = append(steps, step{Delete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		hb.add(steps[i].op, steps[i].token)
+	}
+}
+
+// hunkBuilder 合并相邻的同类片段
+type hunkBuilder struct {
+	hunks []Hunk
+	op    Op
+	text  strings.Builder
+}
+
+func (hb *hunkBuilder) add(op Op, tokens ...string) {
+	for _, t := range tokens {
+		if hb.text.Len() > 0 && hb.op != op {
+			hb.flush()
+		}
+		hb.op = op
+		hb.text.WriteString(t)
+	}
+}
+
+func (hb *hunkBuilder) flush() {
+	if hb.text.Len() > 0 {
+		hb.hunks = append(hb.hunks, Hunk{Op: hb.op, Text: hb.text.String()})
+		hb.text.Reset()
+	}
+}
+
+// result 返回合并后的片段
+func (hb *hunkBuilder) result() []Hunk {
+	hb.flush()
+	return hb.hunks
+}
@@ -2,7 +2,9 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,6 +20,7 @@ import (
 	"enterprise-blog/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 var (
@@ -33,6 +36,12 @@ func init() {
 	// 初始化数据库（如果失败，则在基准测试中跳过相关用例）
 	if err := database.Init(); err == nil {
 		dbAvailable = true
+		// SQLite 没有迁移文件，按模型建表
+		if database.IsSQLite() {
+			if err := database.AutoMigrate(); err != nil {
+				panic(err)
+			}
+		}
 	}
 
 	gin.SetMode(gin.TestMode)
@@ -48,12 +57,17 @@ func init() {
 	smsRepo := repository.NewSMSRepository()
 	// 如需在基准测试中覆盖评论接口，再初始化 commentRepo / commentService / commentHandler
 
-	userService := services.NewUserService(userRepo, jwtMgr)
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), jwtMgr)
 	smsService := services.NewSMSService(smsRepo, userRepo)
 	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
-	tagService := services.NewTagService(tagRepo)
+	categoryService := services.NewCategoryService(categoryRepo, articleRepo)
+	tagService := services.NewTagService(tagRepo, articleRepo)
 	// commentService := services.NewCommentService(commentRepo, articleRepo)
+	if dbAvailable {
+		if err := seedBenchmarkData(userService, articleService, categoryService, tagService); err != nil {
+			panic(err)
+		}
+	}
 
 	userHandler := handlers.NewUserHandler(userService, smsService, jwtMgr)
 	articleHandler := handlers.NewArticleHandler(articleService)
@@ -71,6 +85,51 @@ func init() {
 	}
 }
 
+// seedBenchmarkData 空库时写入列表接口用到的数据（10 个分类、20 个标签、100 篇已发布文章），已有文章时跳过
+func seedBenchmarkData(userService *services.UserService, articleService *services.ArticleService,
+	categoryService *services.CategoryService, tagService *services.TagService) error {
+	var count int64
+	if err := database.DB.Raw(`SELECT COUNT(*) FROM articles`).Scan(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	ctx := context.Background()
+	author, err := userService.Register(ctx, &models.UserCreate{
+		Username: "bench_author", Email: "bench_author@example.com", Password: "password123", Role: models.RoleAuthor,
+	})
+	if err != nil {
+		return err
+	}
+	var categories []*models.Category
+	for i := 0; i < 10; i++ {
+		category, err := categoryService.Create(ctx, &models.CategoryCreate{Name: fmt.Sprintf("Bench Category %d", i)})
+		if err != nil {
+			return err
+		}
+		categories = append(categories, category)
+	}
+	var tags []*models.Tag
+	for i := 0; i < 20; i++ {
+		tag, err := tagService.Create(ctx, &models.TagCreate{Name: fmt.Sprintf("Bench Tag %d", i)})
+		if err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+	for i := 0; i < 100; i++ {
+		_, err := articleService.Create(ctx, author.ID, &models.ArticleCreate{
+			Title:      fmt.Sprintf("Bench Article %d", i),
+			Content:    "Benchmark content paragraph. ",
+			Status:     models.StatusPublished,
+			CategoryID: &categories[i%len(categories)].ID,
+			TagIDs:     []uuid.UUID{tags[i%len(tags)].ID, tags[(i+1)%len(tags)].ID},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func BenchmarkUserRegister(b *testing.B) {
 	if !dbAvailable {
 		b.Skip("Database not available")
@@ -28,6 +28,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -42,6 +45,7 @@ import (
 	"enterprise-blog/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -76,6 +80,12 @@ func setupTestRouter() {
 	if err := database.Init(); err != nil {
 		panic(err)
 	}
+	// SQLite 没有迁移文件，按模型建表
+	if database.IsSQLite() {
+		if err := database.AutoMigrate(); err != nil {
+			panic(err)
+		}
+	}
 
 	// 初始化JWT
 	testJWT = jwt.NewJWTManager("test-secret-key-for-integration-tests", 3600*1000000000)
@@ -89,11 +99,11 @@ func setupTestRouter() {
 	smsRepo := repository.NewSMSRepository()
 
 	// 初始化Service
-	userService := services.NewUserService(userRepo, testJWT)
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), testJWT)
 	smsService := services.NewSMSService(smsRepo, userRepo)
 	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
-	tagService := services.NewTagService(tagRepo)
+	categoryService := services.NewCategoryService(categoryRepo, articleRepo)
+	tagService := services.NewTagService(tagRepo, articleRepo)
 	commentService := services.NewCommentService(commentRepo, articleRepo)
 
 	// 初始化Handler
@@ -102,9 +112,14 @@ func setupTestRouter() {
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	tagHandler := handlers.NewTagHandler(tagService)
 	commentHandler := handlers.NewCommentHandler(commentService)
+	previewHandler := handlers.NewPreviewHandler(services.NewPreviewService(articleRepo, testJWT))
+	authorHandler := handlers.NewAuthorHandler(userService, articleService)
 
 	// 创建路由
 	testRouter = gin.New()
+	// 与生产默认一致：不信任任何代理
+	_ = testRouter.SetTrustedProxies(nil)
+	testRouter.Use(middleware.RequestIDMiddleware())
 	testRouter.Use(middleware.LoggerMiddleware())
 	testRouter.Use(middleware.CORSMiddleware())
 
@@ -115,9 +130,23 @@ func setupTestRouter() {
 		{
 			public.POST("/auth/register", userHandler.Register)
 			public.POST("/auth/login", userHandler.Login)
+			public.POST("/auth/refresh", userHandler.Refresh)
+			public.POST("/auth/logout", userHandler.Logout)
 			public.GET("/articles", articleHandler.List)
+			public.GET("/articles/archive", articleHandler.Archive)
+			public.GET("/articles/preview", previewHandler.Preview)
+			public.GET("/articles/slug/:slug", articleHandler.GetBySlug)
+			public.GET("/articles/slug/:slug/meta", articleHandler.GetMeta)
+			public.GET("/articles/:id/export.html", middleware.OptionalAuthMiddleware(testJWT), articleHandler.ExportHTML)
 			public.GET("/categories", categoryHandler.List)
+			public.GET("/categories/autocomplete", categoryHandler.Autocomplete)
 			public.GET("/tags", tagHandler.List)
+			public.GET("/tags/autocomplete", middleware.OptionalAuthMiddleware(testJWT), tagHandler.Autocomplete)
+			public.GET("/tags/slug/:slug", tagHandler.GetBySlug)
+			public.GET("/articles/:id/comments", commentHandler.GetByArticleID)
+			public.GET("/comments/:id", commentHandler.GetByID)
+			public.GET("/users/:id/public", authorHandler.Profile)
+			public.GET("/users/:id/articles", authorHandler.Articles)
 		}
 
 		// 需要认证的路由
@@ -126,19 +155,46 @@ func setupTestRouter() {
 		{
 			authenticated.GET("/users/profile", userHandler.GetProfile)
 			authenticated.PUT("/users/profile", userHandler.UpdateProfile)
+			authenticated.GET("/users/articles", articleHandler.ListMine)
+			authenticated.POST("/users/article-states", articleHandler.ArticleStates)
 			authenticated.POST("/articles", articleHandler.Create)
 			authenticated.GET("/articles/:id", articleHandler.GetByID)
 			authenticated.PUT("/articles/:id", articleHandler.Update)
 			authenticated.DELETE("/articles/:id", articleHandler.Delete)
+			authenticated.POST("/articles/:id/generate-summary", middleware.RoleMiddleware("admin", "editor", "author"), articleHandler.GenerateSummary)
+			authenticated.GET("/articles/:id/status-history", articleHandler.StatusHistory)
+			authenticated.GET("/articles/:id/revisions", articleHandler.ListRevisions)
+			authenticated.GET("/articles/:id/revisions/:rev_id", articleHandler.GetRevision)
+			authenticated.GET("/articles/:id/revisions/:rev_id/diff/:to_id", articleHandler.DiffRevisions)
+			authenticated.POST("/articles/:id/revisions/:rev_id/restore", articleHandler.RestoreRevision)
+			authenticated.POST("/articles/:id/preview-token", previewHandler.CreateToken)
+			authenticated.DELETE("/articles/:id/preview-token/:jti", previewHandler.RevokeToken)
 			authenticated.POST("/articles/:id/like", articleHandler.Like)
+			authenticated.DELETE("/articles/:id/like", articleHandler.Unlike)
 			authenticated.POST("/articles/:id/comments", commentHandler.Create)
 		}
 	}
 }
 
 func TestMain(m *testing.M) {
+	// 未指定 DB_DRIVER 时使用临时 SQLite 数据库，无需任何外部服务；设置 DB_DRIVER=postgres 连接真实测试库
+	if os.Getenv("DB_DRIVER") == "" {
+		dir, err := os.MkdirTemp("", "enterprise-blog-integration")
+		if err != nil {
+			panic(err)
+		}
+		os.Setenv("DB_DRIVER", database.DriverSQLite)
+		os.Setenv("DB_SQLITE_PATH", filepath.Join(dir, "test.db"))
+		defer os.RemoveAll(dir)
+	}
+
 	setupTestRouter()
-	m.Run()
+	code := m.Run()
+	database.Close()
+	if code != 0 {
+		// defer 不会在 os.Exit 前执行，失败时保留数据库文件便于排查
+		os.Exit(code)
+	}
 }
 
 /**
@@ -182,13 +238,21 @@ func TestUserRegister(t *testing.T) {
 	}
 
 	// 注册操作返回 201 Created 是符合 RESTful 规范的（创建新资源）
-	// 但某些实现可能返回 200 OK，两种都是可接受的
-	assert.True(t, w.Code == http.StatusOK || w.Code == http.StatusCreated, "Expected 200 or 201, got %d", w.Code)
+	assert.Equal(t, http.StatusCreated, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, float64(200), response["code"])
+	assert.Equal(t, float64(201), response["code"])
+
+	// Location 指向新用户的公开主页
+	data, _ := response["data"].(map[string]interface{})
+	require.NotNil(t, data)
+	assert.Equal(t, "/api/v1/users/"+data["id"].(string)+"/public", w.Header().Get("Location"))
+	req, _ = http.NewRequest("GET", w.Header().Get("Location"), nil)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
 /**
@@ -271,6 +335,37 @@ func TestGetArticlesList(t *testing.T) {
 	assert.Equal(t, float64(200), response["code"])
 }
 
+func TestListPaginationNormalization(t *testing.T) {
+	cases := []struct {
+		name         string
+		path         string
+		wantPage     int
+		wantPageSize int
+	}{
+		{"articles page_size=0", "/api/v1/articles?page_size=0", 1, 10},
+		{"articles negative", "/api/v1/articles?page=-2&page_size=-5", 1, 10},
+		{"articles too large", "/api/v1/articles?page=1&page_size=1000000", 1, models.MaxPageSize},
+		{"comments page_size=0", "/api/v1/articles/00000000-0000-0000-0000-000000000000/comments?page_size=0", 1, 20},
+		{"comments negative", "/api/v1/articles/00000000-0000-0000-0000-000000000000/comments?page=-1&page_size=-1", 1, 20},
+		{"comments too large", "/api/v1/articles/00000000-0000-0000-0000-000000000000/comments?page_size=1000000", 1, models.MaxPageSize},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			w := httptest.NewRecorder()
+			testRouter.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var response struct {
+				Meta models.PaginationMeta `json:"meta"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.wantPage, response.Meta.Page)
+			assert.Equal(t, tc.wantPageSize, response.Meta.PageSize)
+		})
+	}
+}
+
 func TestCreateArticle_Unauthorized(t *testing.T) {
 	reqBody := models.ArticleCreate{
 		Title:   "Test Article",
@@ -366,11 +461,156 @@ func TestCreateArticle_Authorized(t *testing.T) {
 	if w.Code != http.StatusOK && w.Code != http.StatusCreated {
 		t.Logf("Create article response body: %s", w.Body.String())
 	}
-	// 创建文章操作返回 201 Created 是符合 RESTful 规范的
-	assert.True(t, w.Code == http.StatusOK || w.Code == http.StatusCreated, "Expected 200 or 201, got %d", w.Code)
+	// 创建文章返回 201 Created，Location 指向新文章，响应体中的 code 与状态码一致
+	assert.Equal(t, http.StatusCreated, w.Code)
 
-	var response map[string]interface{}
+	var response struct {
+		Code int            `json:"code"`
+		Data models.Article `json:"data"`
+	}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Equal(t, float64(200), response["code"])
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, "/api/v1/articles/"+response.Data.ID.String(), w.Header().Get("Location"))
+}
+
+// registerAndLogin 注册一个作者账号并返回登录 token
+func registerAndLogin(t *testing.T, prefix string) string {
+	t.Helper()
+	timestamp := time.Now().UnixNano()
+	email := fmt.Sprintf("%s_%d@example.com", prefix, timestamp)
+	registerData, _ := json.Marshal(models.UserCreate{
+		Username: fmt.Sprintf("%s_%d", prefix, timestamp),
+		Email:    email,
+		Password: "password123",
+		Role:     models.RoleAuthor,
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(registerData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.True(t, w.Code == http.StatusOK || w.Code == http.StatusCreated, w.Body.String())
+
+	loginData, _ := json.Marshal(models.UserLogin{Email: email, Password: "password123"})
+	req, _ = http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBuffer(loginData))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data.Token
+}
+
+func TestUpdateArticle_OptimisticConcurrency(t *testing.T) {
+	token := registerAndLogin(t, "occ")
+
+	createData, _ := json.Marshal(models.ArticleCreate{Title: "OCC Article", Content: "v1", Status: models.StatusDraft})
+	req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(createData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.Equal(t, 1, created.Data.Version)
+	path := "/api/v1/articles/" + created.Data.ID.String()
+
+	put := func(body interface{}, ifMatch string) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("PUT", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 多个编辑者基于同一版本并发保存，只有一个成功，其余返回 409 和服务端当前内容
+	const editors = 5
+	codes := make(chan int, editors)
+	bodies := make(chan []byte, editors)
+	var wg sync.WaitGroup
+	for i := 0; i < editors; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			content := fmt.Sprintf("edit from editor %d", i)
+			version := 1
+			w := put(models.ArticleUpdate{Content: &content, ExpectedVersion: &version}, "")
+			codes <- w.Code
+			bodies <- w.Body.Bytes()
+		}(i)
+	}
+	wg.Wait()
+	close(codes)
+	close(bodies)
+
+	counts := map[int]int{}
+	for code := range codes {
+		counts[code]++
+	}
+	assert.Equal(t, 1, counts[http.StatusOK])
+	assert.Equal(t, editors-1, counts[http.StatusConflict])
+	for body := range bodies {
+		var resp struct {
+			Code int            `json:"code"`
+			Data models.Article `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		assert.Equal(t, 2, resp.Data.Version)
+	}
+
+	// If-Match 携带最新版本时更新成功
+	title := "OCC Article v3"
+	w = put(models.ArticleUpdate{Title: &title}, `"2"`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	// 过期版本返回 409，未提供版本返回 428
+	w = put(models.ArticleUpdate{Title: &title}, `"2"`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = put(models.ArticleUpdate{Title: &title}, "")
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+}
+
+func TestCreateArticle_MissingTags(t *testing.T) {
+	token := registerAndLogin(t, "tags")
+	missing := uuid.New()
+
+	createData, _ := json.Marshal(models.ArticleCreate{
+		Title:   "Missing Tags Article",
+		Content: "content",
+		Status:  models.StatusDraft,
+		TagIDs:  []uuid.UUID{missing, missing},
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(createData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	// 标签不存在时返回 400 并列出缺失的 ID，而不是外键约束错误
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var resp struct {
+		Message string `json:"message"`
+		Data    struct {
+			MissingTagIDs []uuid.UUID `json:"missing_tag_ids"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []uuid.UUID{missing}, resp.Data.MissingTagIDs)
+	assert.Contains(t, resp.Message, missing.String())
 }
@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleArchive(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "archive_author"))
+	suffix := uuid.NewString()[:8]
+
+	publishAt := func(title string, status models.ArticleStatus, at time.Time) *models.Article {
+		t.Helper()
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: title + " " + suffix, Content: "x", Status: status})
+		require.NoError(t, err)
+		require.NoError(t, database.DB.Exec(`UPDATE articles SET published_at = ? WHERE id = ?`, at, article.ID).Error)
+		return article
+	}
+	march := publishAt("Archive march", models.StatusPublished, time.Date(1999, time.March, 1, 0, 0, 0, 0, time.Local))
+	publishAt("Archive march end", models.StatusPublished, time.Date(1999, time.March, 31, 23, 59, 0, 0, time.Local))
+	publishAt("Archive november", models.StatusPublished, time.Date(1999, time.November, 15, 12, 0, 0, 0, time.Local))
+	publishAt("Archive draft", models.StatusDraft, time.Date(1999, time.March, 10, 0, 0, 0, 0, time.Local))
+
+	archive := func() map[[2]int]int64 {
+		t.Helper()
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/archive", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []models.ArchiveMonth `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		counts := make(map[[2]int]int64, len(resp.Data))
+		for i, m := range resp.Data {
+			counts[[2]int{m.Year, m.Month}] = m.Count
+			if i > 0 {
+				prev := resp.Data[i-1]
+				assert.True(t, prev.Year > m.Year || (prev.Year == m.Year && prev.Month > m.Month), "archive must be sorted newest first")
+			}
+		}
+		return counts
+	}
+	list := func(query string) (int, int64) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles?"+query, nil))
+		var resp models.PaginationResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Meta.Total
+	}
+
+	// 草稿不计入；月份边界按本地时间
+	counts := archive()
+	assert.Equal(t, int64(2), counts[[2]int{1999, 3}])
+	assert.Equal(t, int64(1), counts[[2]int{1999, 11}])
+	_, ok := counts[[2]int{1999, 4}]
+	assert.False(t, ok)
+
+	// 按年份 / 月份筛选文章列表
+	code, total := list("year=1999&month=3")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(2), total)
+	_, total = list("year=1999")
+	assert.Equal(t, int64(3), total)
+	_, total = list("year=1999&month=4")
+	assert.Zero(t, total)
+	code, _ = list("month=3")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = list("year=1999&month=13")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 归档结果被缓存，文章修改清理列表缓存后重新统计
+	require.NoError(t, database.DB.Exec(`UPDATE articles SET published_at = ? WHERE id = ?`, time.Date(1999, time.April, 2, 0, 0, 0, 0, time.Local), march.ID).Error)
+	assert.Equal(t, int64(2), archive()[[2]int{1999, 3}])
+	current, err := articleService.GetByID(ctx, march.ID)
+	require.NoError(t, err)
+	title := "Archive april " + suffix
+	_, err = articleService.Update(ctx, march.ID, &models.ArticleUpdate{Title: &title, ExpectedVersion: &current.Version})
+	require.NoError(t, err)
+	counts = archive()
+	assert.Equal(t, int64(1), counts[[2]int{1999, 3}])
+	assert.Equal(t, int64(1), counts[[2]int{1999, 4}])
+}
+
+func TestArticleArchiveUsesConfiguredTimezone(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "archive_tz_author"))
+	suffix := uuid.NewString()[:8]
+
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+	cfg := *original
+	cfg.Timezone = "Asia/Shanghai"
+	config.Set(&cfg)
+
+	// UTC 1998-06-30 17:00 已是北京时间 7 月 1 日 01:00，归入 7 月
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Archive tz " + suffix, Content: "x", Status: models.StatusPublished})
+	require.NoError(t, err)
+	at := time.Date(1998, time.June, 30, 17, 0, 0, 0, time.UTC).In(time.Local)
+	require.NoError(t, database.DB.Exec(`UPDATE articles SET published_at = ? WHERE id = ?`, at, article.ID).Error)
+
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/archive", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data []models.ArchiveMonth `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	counts := make(map[[2]int]int64, len(resp.Data))
+	for _, m := range resp.Data {
+		counts[[2]int{m.Year, m.Month}] = m.Count
+	}
+	assert.Equal(t, int64(1), counts[[2]int{1998, 7}])
+	_, ok := counts[[2]int{1998, 6}]
+	assert.False(t, ok)
+
+	// 列表筛选使用同一时区的月份边界
+	list := func(query string) int64 {
+		t.Helper()
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp models.PaginationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Meta.Total
+	}
+	assert.Equal(t, int64(1), list("year=1998&month=7"))
+	assert.Zero(t, list("year=1998&month=6"))
+}
@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createBatchArticles 创建 n 篇带分类和标签的已发布文章，返回文章 ID
+func createBatchArticles(tb testing.TB, prefix string, n int) []uuid.UUID {
+	tb.Helper()
+	ctx := context.Background()
+	tagRepo := repository.NewTagRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+
+	suffix := uuid.NewString()[:8]
+	user := &models.User{Username: prefix + "_" + suffix, Email: prefix + suffix + "@example.com", Password: "x", Role: models.RoleAuthor, Status: "active"}
+	require.NoError(tb, repository.NewUserRepository().Create(ctx, user))
+	author := user.ID
+	category, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: prefix + " " + suffix})
+	require.NoError(tb, err)
+	tag, err := services.NewTagService(tagRepo, articleRepo).Create(ctx, &models.TagCreate{Name: prefix + " " + suffix})
+	require.NoError(tb, err)
+
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+			Title: fmt.Sprintf("%s %d", prefix, i), Content: "content", Status: models.StatusPublished,
+			CategoryID: &category.ID, TagIDs: []uuid.UUID{tag.ID},
+		})
+		require.NoError(tb, err)
+		ids[i] = article.ID
+	}
+	return ids
+}
+
+func TestArticleRepository_GetByIDs(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	ids := createBatchArticles(t, "batch", 3)
+	require.NoError(t, articleRepo.Delete(ctx, ids[1]))
+
+	// 保持传入顺序，跳过不存在和已删除的文章，重复 ID 只返回一次
+	requested := []uuid.UUID{ids[2], uuid.New(), ids[1], ids[0], ids[2]}
+	articles, err := articleRepo.GetByIDs(ctx, requested)
+	require.NoError(t, err)
+	require.Len(t, articles, 2)
+	assert.Equal(t, ids[2], articles[0].ID)
+	assert.Equal(t, ids[0], articles[1].ID)
+
+	// 关联数据与逐篇加载一致
+	for _, article := range articles {
+		single, err := articleRepo.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		assert.Equal(t, single, article)
+	}
+
+	articles, err = articleRepo.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+}
+
+func BenchmarkArticleRepository_GetByIDLoop(b *testing.B) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	ids := createBatchArticles(b, "bench_loop", 50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, id := range ids {
+			if _, err := articleRepo.GetByID(ctx, id); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkArticleRepository_GetByIDs(b *testing.B) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	ids := createBatchArticles(b, "bench_batch", 50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := articleRepo.GetByIDs(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
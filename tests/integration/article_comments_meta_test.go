@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleList_IncludeCommentsMeta(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	commentService := services.NewCommentService(repository.NewCommentRepository(), articleRepo)
+
+	author := profileID(t, registerAndLogin(t, "comments_meta"))
+	keyword := "commentsmeta" + uuid.NewString()[:8]
+	create := func(title string) *models.Article {
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: title + " " + keyword, Content: "content", Status: models.StatusPublished})
+		require.NoError(t, err)
+		return article
+	}
+	commented := create("Commented")
+	quiet := create("Quiet")
+
+	approved := models.CommentStatusApproved
+	comment := func(status string) *models.Comment {
+		c, err := commentService.Create(ctx, nil, "192.0.2.1", &models.CommentCreate{ArticleID: commented.ID, Content: "hi", Author: "guest", Email: "guest@example.com"})
+		require.NoError(t, err)
+		c, err = commentService.Update(ctx, c.ID, &models.CommentUpdate{Status: &status})
+		require.NoError(t, err)
+		return c
+	}
+	comment(approved)
+	latest := comment(approved)
+	// 未通过的评论不计入
+	comment(models.CommentStatusRejected)
+
+	list := func(path string) (int, map[uuid.UUID]models.Article) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data []models.Article `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		byID := map[uuid.UUID]models.Article{}
+		for _, a := range resp.Data {
+			byID[a.ID] = a
+		}
+		return w.Code, byID
+	}
+
+	// 不带 include 时不返回评论统计
+	code, articles := list("/api/v1/articles?page_size=100")
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, articles, commented.ID)
+	assert.Nil(t, articles[commented.ID].CommentsMeta)
+
+	path := "/api/v1/articles?page_size=100&include=comments_meta"
+	code, articles = list(path)
+	require.Equal(t, http.StatusOK, code)
+	require.NotNil(t, articles[commented.ID].CommentsMeta)
+	meta := articles[commented.ID].CommentsMeta
+	assert.EqualValues(t, 2, meta.ApprovedCount)
+	require.NotNil(t, meta.LastCommentAt)
+	assert.WithinDuration(t, latest.CreatedAt, *meta.LastCommentAt, 0)
+	require.NotNil(t, articles[quiet.ID].CommentsMeta)
+	assert.EqualValues(t, 0, articles[quiet.ID].CommentsMeta.ApprovedCount)
+	assert.Nil(t, articles[quiet.ID].CommentsMeta.LastCommentAt)
+
+	// 缓存命中时仍带有评论统计；新评论通过后缓存失效
+	_, articles = list(path)
+	require.NotNil(t, articles[commented.ID].CommentsMeta)
+	assert.EqualValues(t, 2, articles[commented.ID].CommentsMeta.ApprovedCount)
+
+	comment(approved)
+	_, articles = list(path)
+	require.NotNil(t, articles[commented.ID].CommentsMeta)
+	assert.EqualValues(t, 3, articles[commented.ID].CommentsMeta.ApprovedCount)
+
+	// 全文搜索同样支持
+	code, articles = list("/api/v1/articles?include=comments_meta&search=" + keyword)
+	require.Equal(t, http.StatusOK, code)
+	require.NotNil(t, articles[commented.ID].CommentsMeta)
+	assert.EqualValues(t, 3, articles[commented.ID].CommentsMeta.ApprovedCount)
+}
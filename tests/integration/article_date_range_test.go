@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminArticleListDateRange(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	router := gin.New()
+	router.GET("/admin/articles", handlers.NewArticleHandler(articleService).AdminList)
+	author := profileID(t, registerAndLogin(t, "date_range"))
+
+	create := func(title string, createdAt, publishedAt time.Time) {
+		t.Helper()
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: title, Content: "x", Status: models.StatusPublished})
+		require.NoError(t, err)
+		require.NoError(t, database.DB.Exec(`UPDATE articles SET created_at = ?, published_at = ? WHERE id = ?`, createdAt, publishedAt, article.ID).Error)
+	}
+	create("Range january", time.Date(1998, time.January, 31, 23, 0, 0, 0, time.UTC), time.Date(1998, time.February, 1, 9, 0, 0, 0, time.UTC))
+	create("Range february", time.Date(1998, time.February, 10, 0, 0, 0, 0, time.UTC), time.Date(1998, time.February, 20, 12, 0, 0, 0, time.UTC))
+	create("Range march", time.Date(1998, time.March, 5, 0, 0, 0, 0, time.UTC), time.Date(1998, time.March, 6, 0, 0, 0, 0, time.UTC))
+
+	list := func(query string) (int, int64, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/articles?author_id="+author.String()+"&"+query, nil))
+		var resp struct {
+			ErrorCode string `json:"error_code"`
+			Meta      struct {
+				Total int64 `json:"total"`
+			} `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp.Meta.Total, resp.ErrorCode
+	}
+
+	cases := []struct {
+		name  string
+		query string
+		want  int64
+	}{
+		{"created date range includes whole end day", "created_from=1998-01-01&created_to=1998-01-31", 1},
+		{"created open-ended", "created_from=1998-02-01", 2},
+		{"published RFC3339 range is inclusive", "published_from=1998-02-01T09:00:00Z&published_to=1998-02-20T12:00:00Z", 2},
+		{"published RFC3339 with offset", "published_to=1998-02-01T17:00:00%2B08:00", 1},
+		{"created and published combined", "created_from=1998-02-01&published_from=1998-03-01", 1},
+		{"from after to returns empty page", "created_from=1998-03-01&created_to=1998-01-01", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, total, _ := list(tc.query)
+			require.Equal(t, http.StatusOK, code)
+			assert.Equal(t, tc.want, total)
+		})
+	}
+
+	// 不同时间范围使用不同的缓存键：先缓存一个范围，再查询另一个范围不会命中旧结果
+	_, total, _ := list("published_from=1998-03-01")
+	assert.Equal(t, int64(1), total)
+	_, total, _ = list("published_from=1998-01-01")
+	assert.Equal(t, int64(3), total)
+
+	code, _, errorCode := list("published_from=last-week")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeValidationFailed, errorCode)
+}
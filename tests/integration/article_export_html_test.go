@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleExportHTML(t *testing.T) {
+	mr := useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+	cfg := *original
+	cfg.Server.PublicURL = "https://api.example.com/"
+	config.Set(&cfg)
+
+	authorToken := registerAndLogin(t, "export_html")
+	author := profileID(t, authorToken)
+	otherToken := registerAndLogin(t, "export_html_other")
+	suffix := uuid.NewString()[:8]
+	content := "## Section\n\nSome **bold** text.\n\n![diagram](/uploads/images/diagram.png)\n\n<script>alert(1)</script>"
+	published, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Export " + suffix, Content: content, CoverImage: "/uploads/images/cover.png", Status: models.StatusPublished,
+	})
+	require.NoError(t, err)
+	draft, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Export draft " + suffix, Content: "draft body", Status: models.StatusDraft})
+	require.NoError(t, err)
+
+	export := func(id uuid.UUID, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/api/v1/articles/"+id.String()+"/export.html", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	w := export(published.ID, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	disposition, params, err := mime.ParseMediaType(w.Header().Get("Content-Disposition"))
+	require.NoError(t, err)
+	assert.Equal(t, "attachment", disposition)
+	assert.Equal(t, published.Slug+".html", params["filename"])
+	body := w.Body.String()
+	assert.Contains(t, body, "<title>Export "+suffix+"</title>")
+	assert.Contains(t, body, "<h2>Section</h2>")
+	assert.Contains(t, body, "<strong>bold</strong>")
+	assert.Contains(t, body, `src="https://api.example.com/uploads/images/diagram.png"`)
+	assert.Contains(t, body, `src="https://api.example.com/uploads/images/cover.png"`)
+	assert.NotContains(t, body, "<script>")
+	assert.Contains(t, body, "&lt;script&gt;")
+
+	// 渲染结果已缓存，文章修改后缓存键随更新时间变化
+	var cached int
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "blog:article:detail:export:"+published.ID.String()) {
+			cached++
+		}
+	}
+	assert.Equal(t, 1, cached)
+	newContent := "updated body"
+	_, err = articleService.Update(ctx, published.ID, &models.ArticleUpdate{Content: &newContent, ExpectedVersion: &published.Version})
+	require.NoError(t, err)
+	w = export(published.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<p>updated body</p>")
+
+	// 草稿只有作者本人可以导出
+	assert.Equal(t, http.StatusNotFound, export(draft.ID, "").Code)
+	assert.Equal(t, http.StatusNotFound, export(draft.ID, otherToken).Code)
+	w = export(draft.ID, authorToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "draft body")
+
+	assert.Equal(t, http.StatusNotFound, export(uuid.New(), "").Code)
+	req, _ := http.NewRequest("GET", "/api/v1/articles/not-a-uuid/export.html", nil)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleService_LikeDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "like_author"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Like dedup", Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	likeCount := func() int {
+		a, err := articleRepo.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		return a.LikeCount
+	}
+
+	user := services.NewArticleLiker(&author, "")
+	guest := services.NewArticleLiker(nil, "192.0.2.1")
+	otherGuest := services.NewArticleLiker(nil, "192.0.2.2")
+
+	added, err := articleService.Like(ctx, article.ID, user)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = articleService.Like(ctx, article.ID, user)
+	require.NoError(t, err)
+	assert.False(t, added)
+	for _, liker := range []models.ArticleLiker{guest, guest, otherGuest} {
+		_, err = articleService.Like(ctx, article.ID, liker)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, likeCount())
+
+	liked, err := articleService.HasLiked(ctx, article.ID, guest)
+	require.NoError(t, err)
+	assert.True(t, liked)
+
+	// 取消点赞只对点过赞的人生效
+	removed, err := articleService.Unlike(ctx, article.ID, guest)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = articleService.Unlike(ctx, article.ID, guest)
+	require.NoError(t, err)
+	assert.False(t, removed)
+	assert.Equal(t, 2, likeCount())
+	liked, err = articleService.HasLiked(ctx, article.ID, guest)
+	require.NoError(t, err)
+	assert.False(t, liked)
+
+	_, err = articleService.Like(ctx, uuid.New(), user)
+	assert.ErrorIs(t, err, repository.ErrArticleNotFound)
+}
+
+func TestArticleService_LikeBufferedInRedis(t *testing.T) {
+	mr := useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "like_buffered"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Like buffered", Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	guest := services.NewArticleLiker(nil, "198.51.100.1")
+	for i := 0; i < 3; i++ {
+		_, err = articleService.Like(ctx, article.ID, guest)
+		require.NoError(t, err)
+	}
+	_, err = articleService.Like(ctx, article.ID, services.NewArticleLiker(&author, ""))
+	require.NoError(t, err)
+	_, err = articleService.Unlike(ctx, article.ID, guest)
+	require.NoError(t, err)
+
+	// 去重发生在写计数之前：Redis 中只累计了两次点赞和一次取消
+	val, err := database.RedisClient.Get(ctx, "blog:article:like:"+article.ID.String()).Int64()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, val)
+
+	require.NoError(t, services.FlushArticleCountersFromRedis(ctx))
+	assert.False(t, mr.Exists("blog:article:like:"+article.ID.String()))
+	stored, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.LikeCount)
+}
+
+func TestArticleHandler_LikeAndUnlike(t *testing.T) {
+	token := registerAndLogin(t, "like_api")
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	article, err := articleService.Create(ctx, profileID(t, token), &models.ArticleCreate{Title: "Like api", Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	do := func(method, path string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+	path := "/api/v1/articles/" + article.ID.String()
+
+	code, data := do("GET", path)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, data["liked"])
+
+	for i := 0; i < 2; i++ {
+		code, data = do("POST", path+"/like")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, data["liked"])
+	}
+	code, data = do("GET", path)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, data["liked"])
+	assert.EqualValues(t, 1, data["like_count"])
+
+	code, data = do("DELETE", path+"/like")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, data["liked"])
+	code, data = do("GET", path)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, data["liked"])
+	assert.EqualValues(t, 0, data["like_count"])
+
+	code, _ = do("POST", "/api/v1/articles/"+uuid.NewString()+"/like")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestArticleHandler_ArticleStates(t *testing.T) {
+	token := registerAndLogin(t, "states_api")
+	userID := profileID(t, token)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	create := func(title string) *models.Article {
+		article, err := articleService.Create(ctx, userID, &models.ArticleCreate{Title: title, Content: "content", Status: models.StatusPublished})
+		require.NoError(t, err)
+		return article
+	}
+	liked := create("States liked")
+	other := create("States other")
+	_, err := articleService.Like(ctx, liked.ID, services.NewArticleLiker(&userID, ""))
+	require.NoError(t, err)
+	// 其他用户和匿名用户的点赞不影响当前用户的状态
+	_, err = articleService.Like(ctx, other.ID, services.NewArticleLiker(nil, "192.0.2.9"))
+	require.NoError(t, err)
+
+	post := func(token string, body interface{}) (int, models.ArticleStatesResult) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", "/api/v1/users/article-states", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data models.ArticleStatesResult `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	missing := uuid.New()
+	code, result := post(token, models.ArticleStatesRequest{ArticleIDs: []string{
+		liked.ID.String(), strings.ToUpper(liked.ID.String()), other.ID.String(), missing.String(), "not-a-uuid",
+	}})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, result.States, 3)
+	assert.True(t, result.States[liked.ID].Liked)
+	assert.False(t, result.States[other.ID].Liked)
+	assert.False(t, result.States[missing].Liked)
+	assert.False(t, result.States[liked.ID].Bookmarked)
+	assert.Equal(t, []string{"not-a-uuid"}, result.InvalidIDs)
+
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+	code, _ = post(token, models.ArticleStatesRequest{ArticleIDs: tooMany})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = post(token, models.ArticleStatesRequest{})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = post("", models.ArticleStatesRequest{ArticleIDs: []string{liked.ID.String()}})
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
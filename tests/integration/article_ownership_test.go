@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleService_UpdateAndDeleteRequireOwnership(t *testing.T) {
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "owner_author"))
+	other := profileID(t, registerAndLogin(t, "owner_other"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Owned article", Content: "content", Status: models.StatusDraft})
+	require.NoError(t, err)
+
+	title := "Changed"
+	update := func(userID uuid.UUID, role models.UserRole) (*models.Article, error) {
+		return articleService.UpdateAsUser(ctx, article.ID, userID, role, &models.ArticleUpdate{Title: &title, ExpectedVersion: &article.Version})
+	}
+
+	// 其他作者不能修改或删除
+	_, err = update(other, models.RoleAuthor)
+	assert.ErrorIs(t, err, services.ErrNotArticleAuthor)
+	assert.ErrorIs(t, articleService.DeleteAsUser(ctx, article.ID, other, models.RoleAuthor), services.ErrNotArticleAuthor)
+
+	// 作者本人、编辑和管理员可以修改
+	article, err = update(author, models.RoleAuthor)
+	require.NoError(t, err)
+	article, err = update(other, models.RoleEditor)
+	require.NoError(t, err)
+	article, err = update(other, models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, "Changed", article.Title)
+
+	// 文章不存在时返回不存在而不是无权限
+	missing := uuid.New()
+	_, err = articleService.UpdateAsUser(ctx, missing, other, models.RoleAuthor, &models.ArticleUpdate{Title: &title, ExpectedVersion: &article.Version})
+	assert.ErrorIs(t, err, repository.ErrArticleNotFound)
+	assert.ErrorIs(t, articleService.DeleteAsUser(ctx, missing, other, models.RoleAuthor), repository.ErrArticleNotFound)
+
+	// 管理员可以删除他人的文章
+	require.NoError(t, articleService.DeleteAsUser(ctx, article.ID, other, models.RoleAdmin))
+	assert.ErrorIs(t, articleService.DeleteAsUser(ctx, article.ID, author, models.RoleAuthor), repository.ErrArticleNotFound)
+}
+
+func TestArticleHandler_UpdateAndDeleteRequireOwnership(t *testing.T) {
+	authorToken := registerAndLogin(t, "owner_api")
+	otherToken := registerAndLogin(t, "owner_api_other")
+	adminToken, err := testJWT.GenerateToken(profileID(t, otherToken), "owner_api_admin", string(models.RoleAdmin))
+	require.NoError(t, err)
+
+	do := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/v1/articles", authorToken, models.ArticleCreate{Title: "Owned via api", Content: "content", Status: models.StatusDraft})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := "/api/v1/articles/" + created.Data.ID.String()
+	title := "Hijacked"
+	version := created.Data.Version
+
+	assert.Equal(t, http.StatusForbidden, do("PUT", path, otherToken, models.ArticleUpdate{Title: &title, ExpectedVersion: &version}).Code)
+	assert.Equal(t, http.StatusForbidden, do("DELETE", path, otherToken, nil).Code)
+
+	missing := "/api/v1/articles/" + uuid.NewString()
+	assert.Equal(t, http.StatusNotFound, do("PUT", missing, otherToken, models.ArticleUpdate{Title: &title, ExpectedVersion: &version}).Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", missing, otherToken, nil).Code)
+
+	// 管理员可以修改和删除他人的文章
+	title = "Moderated"
+	w = do("PUT", path, adminToken, models.ArticleUpdate{Title: &title, ExpectedVersion: &version})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusNoContent, do("DELETE", path, adminToken, nil).Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", path, authorToken, nil).Code)
+}
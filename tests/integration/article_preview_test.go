@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticlePreviewToken(t *testing.T) {
+	mr := useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	authorToken := registerAndLogin(t, "preview_author")
+	otherToken := registerAndLogin(t, "preview_other")
+	draft, err := articleService.Create(ctx, profileID(t, authorToken), &models.ArticleCreate{
+		Title: "Preview draft " + uuid.NewString()[:8], Content: "draft content", Status: models.StatusDraft,
+	})
+	require.NoError(t, err)
+
+	do := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		var reader *bytes.Reader
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+	issue := func(body interface{}) models.ArticlePreviewToken {
+		t.Helper()
+		w := do("POST", "/api/v1/articles/"+draft.ID.String()+"/preview-token", authorToken, body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data models.ArticlePreviewToken `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+	preview := func(token string) *httptest.ResponseRecorder {
+		return do("GET", "/api/v1/articles/preview?token="+url.QueryEscape(token), "", nil)
+	}
+
+	// 作者生成的 token 可以匿名查看草稿
+	issued := issue(nil)
+	assert.Equal(t, draft.ID, issued.ArticleID)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), issued.ExpiresAt, time.Minute)
+	w := preview(issued.Token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, draft.ID, resp.Data.ID)
+	assert.Equal(t, models.StatusDraft, resp.Data.Status)
+	assert.Contains(t, w.Header().Get("Cache-Control"), "no-store")
+
+	// 其他用户不能为这篇文章生成或吊销 token
+	w = do("POST", "/api/v1/articles/"+draft.ID.String()+"/preview-token", otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do("DELETE", "/api/v1/articles/"+draft.ID.String()+"/preview-token/"+issued.JTI.String(), otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do("POST", "/api/v1/articles/"+draft.ID.String()+"/preview-token", authorToken, map[string]int{"expires_in": 10})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 预览 token 不能用于写操作
+	title := "hijacked"
+	w = do("PUT", "/api/v1/articles/"+draft.ID.String(), issued.Token, models.ArticleUpdate{Title: &title})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 缺失、篡改的 token 返回 401
+	assert.Equal(t, http.StatusUnauthorized, preview("").Code)
+	assert.Equal(t, http.StatusUnauthorized, preview(issued.Token+"x").Code)
+	assert.Equal(t, http.StatusUnauthorized, preview(authorToken).Code)
+
+	// 吊销后失效，重复吊销返回 404
+	w = do("DELETE", "/api/v1/articles/"+draft.ID.String()+"/preview-token/"+issued.JTI.String(), authorToken, nil)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, preview(issued.Token).Code)
+	w = do("DELETE", "/api/v1/articles/"+draft.ID.String()+"/preview-token/"+issued.JTI.String(), authorToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 过期后失效
+	short := issue(map[string]int{"expires_in": 60})
+	require.Equal(t, http.StatusOK, preview(short.Token).Code)
+	mr.FastForward(2 * time.Minute)
+	assert.Equal(t, http.StatusUnauthorized, preview(short.Token).Code)
+}
@@ -0,0 +1,170 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateArticle_CategoryAndTags(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	tagService := services.NewTagService(tagRepo, articleRepo)
+
+	token := registerAndLogin(t, "update_relations")
+	author := profileID(t, token)
+	suffix := uuid.NewString()[:8]
+	category, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: "Relations " + suffix})
+	require.NoError(t, err)
+	oldTag, err := tagService.Create(ctx, &models.TagCreate{Name: "relations old " + suffix})
+	require.NoError(t, err)
+	newTag, err := tagService.Create(ctx, &models.TagCreate{Name: "relations new " + suffix})
+	require.NoError(t, err)
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Relations " + suffix, Content: "content", Status: models.StatusDraft, TagIDs: []uuid.UUID{oldTag.ID},
+	})
+	require.NoError(t, err)
+
+	put := func(body interface{}) (*httptest.ResponseRecorder, models.Response) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("PUT", "/api/v1/articles/"+article.ID.String(), bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp models.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	// 分类不存在时返回 400，文章不变
+	missing := uuid.New()
+	version := article.Version
+	w, resp := put(models.ArticleUpdate{CategoryID: &missing, TagIDs: []uuid.UUID{newTag.ID}, ExpectedVersion: &version})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "category_not_found", resp.ErrorCode)
+
+	// 标签不存在时整体回滚，分类也不会被修改
+	w, resp = put(models.ArticleUpdate{CategoryID: &category.ID, TagIDs: []uuid.UUID{newTag.ID, missing}, ExpectedVersion: &version})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "tags_not_found", resp.ErrorCode)
+	current, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Nil(t, current.CategoryID)
+	require.Len(t, current.Tags, 1)
+	assert.Equal(t, oldTag.ID, current.Tags[0].ID)
+	assert.Equal(t, version, current.Version)
+
+	// 更新后的响应包含新的分类和标签
+	w, _ = put(models.ArticleUpdate{CategoryID: &category.ID, TagIDs: []uuid.UUID{newTag.ID}, ExpectedVersion: &version})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.NotNil(t, updated.Data.CategoryID)
+	assert.Equal(t, category.ID, *updated.Data.CategoryID)
+	require.NotNil(t, updated.Data.Category)
+	assert.Equal(t, category.Name, updated.Data.Category.Name)
+	require.Len(t, updated.Data.Tags, 1)
+	assert.Equal(t, newTag.ID, updated.Data.Tags[0].ID)
+
+	// 不传 tag_ids 时标签不变
+	title := "Relations renamed " + suffix
+	version = updated.Data.Version
+	w, _ = put(models.ArticleUpdate{Title: &title, ExpectedVersion: &version})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.Len(t, updated.Data.Tags, 1)
+
+	// tag_ids 为空数组时清除全部标签
+	version = updated.Data.Version
+	w, _ = put(map[string]interface{}{"tag_ids": []uuid.UUID{}, "expected_version": version})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var cleared struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cleared))
+	assert.Empty(t, cleared.Data.Tags)
+	current, err = articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Empty(t, current.Tags)
+}
+
+func TestCreateArticle_CategoryAndTags(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+
+	token := registerAndLogin(t, "create_relations")
+	suffix := uuid.NewString()[:8]
+	category, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: "Create relations " + suffix})
+	require.NoError(t, err)
+	tag, err := services.NewTagService(tagRepo, articleRepo).Create(ctx, &models.TagCreate{Name: "create relations " + suffix})
+	require.NoError(t, err)
+
+	post := func(body models.ArticleCreate) (*httptest.ResponseRecorder, models.Response) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp models.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	// 分类不存在时返回 400，不创建文章
+	missing := uuid.New()
+	title := "Create relations " + suffix
+	w, resp := post(models.ArticleCreate{Title: title, Content: "content", CategoryID: &missing, TagIDs: []uuid.UUID{tag.ID}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "category_not_found", resp.ErrorCode)
+	_, err = articleRepo.GetBySlug(ctx, services.GenerateSlug(title))
+	assert.ErrorIs(t, err, repository.ErrArticleNotFound)
+
+	// 标签不存在时返回 400，message 中指出缺失的标签 ID，不创建文章
+	w, resp = post(models.ArticleCreate{Title: title, Content: "content", CategoryID: &category.ID, TagIDs: []uuid.UUID{tag.ID, missing}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "tags_not_found", resp.ErrorCode)
+	assert.Contains(t, resp.Message, missing.String())
+	_, err = articleRepo.GetBySlug(ctx, services.GenerateSlug(title))
+	assert.ErrorIs(t, err, repository.ErrArticleNotFound)
+
+	// 创建成功后响应、详情缓存都包含分类和标签
+	w, _ = post(models.ArticleCreate{Title: title, Content: "content", CategoryID: &category.ID, TagIDs: []uuid.UUID{tag.ID}})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Data.Category)
+	assert.Equal(t, category.ID, created.Data.Category.ID)
+	require.Len(t, created.Data.Tags, 1)
+	assert.Equal(t, tag.ID, created.Data.Tags[0].ID)
+
+	cached, err := articleService.GetByID(ctx, created.Data.ID)
+	require.NoError(t, err)
+	require.NotNil(t, cached.Category)
+	assert.Equal(t, category.ID, cached.Category.ID)
+	require.Len(t, cached.Tags, 1)
+	assert.Equal(t, tag.ID, cached.Tags[0].ID)
+}
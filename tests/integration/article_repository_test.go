@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestArticleRepository_SearchFullText(t *testing.T) {
+	if database.IsSQLite() {
+		t.Skip("相关度排序和 websearch 语法依赖 PostgreSQL 全文检索")
+	}
+	token := registerAndLogin(t, "fts")
+	keyword := fmt.Sprintf("ftskeyword%d", time.Now().UnixNano())
+
+	create := func(title, content string) {
+		data, _ := json.Marshal(models.ArticleCreate{Title: title, Content: content, Status: models.StatusDraft})
+		req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+	// 标题命中的权重高于正文命中
+	create("Body match", "this article mentions "+keyword+" only in the content")
+	create("Title "+keyword, "plain content")
+
+	repo := repository.NewArticleRepository()
+	ctx := context.Background()
+
+	articles, total, err := repo.SearchFullText(ctx, models.ArticleQuery{Search: keyword})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, articles, 2)
+	assert.Equal(t, "Title "+keyword, articles[0].Title)
+	assert.Equal(t, "Body match", articles[1].Title)
+
+	// 现有筛选条件同样生效
+	_, total, err = repo.SearchFullText(ctx, models.ArticleQuery{Search: keyword, Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	// websearch 语法：-排除
+	articles, total, err = repo.SearchFullText(ctx, models.ArticleQuery{Search: keyword + " -title"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "Body match", articles[0].Title)
+}
+
+func TestArticleRepository_SearchFullTextUsesIndex(t *testing.T) {
+	if database.IsSQLite() {
+		t.Skip("GIN 索引仅存在于 PostgreSQL")
+	}
+	// 测试库数据量小，关闭顺序扫描后确认查询能走 GIN 索引
+	var plan []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL enable_seqscan = off").Error; err != nil {
+			return err
+		}
+		return tx.Raw(`EXPLAIN SELECT COUNT(*) FROM articles a
+			WHERE a.deleted_at IS NULL AND a.search_vector @@ websearch_to_tsquery('english', ?)`, "golang").
+			Scan(&plan).Error
+	})
+	require.NoError(t, err)
+	assert.Contains(t, strings.Join(plan, "\n"), "idx_articles_search_vector")
+}
+
+func TestArticleRepository_SearchFullTextSQLite(t *testing.T) {
+	if !database.IsSQLite() {
+		t.Skip("仅验证 SQLite 下的 LIKE 退化路径")
+	}
+	token := registerAndLogin(t, "like")
+	keyword := fmt.Sprintf("likekeyword%d", time.Now().UnixNano())
+
+	for _, article := range []models.ArticleCreate{
+		{Title: "Older " + keyword, Content: "plain content", Status: models.StatusDraft},
+		{Title: "Newer", Content: "mentions " + keyword + " in the content", Status: models.StatusDraft},
+	} {
+		data, _ := json.Marshal(article)
+		req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	repo := repository.NewArticleRepository()
+	articles, total, err := repo.SearchFullText(context.Background(), models.ArticleQuery{Search: keyword})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, articles, 2)
+	// 没有相关度，按创建时间倒序
+	assert.Equal(t, "Newer", articles[0].Title)
+
+	_, total, err = repo.SearchFullText(context.Background(), models.ArticleQuery{Search: keyword, Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/textdiff"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleRevisionDiff(t *testing.T) {
+	authorToken := registerAndLogin(t, "revdiff")
+	otherToken := registerAndLogin(t, "revdiff_other")
+	editorToken, err := testJWT.GenerateToken(profileID(t, otherToken), "revdiff_editor", string(models.RoleEditor))
+	require.NoError(t, err)
+
+	do := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			data, _ := json.Marshal(body)
+			buf.Write(data)
+		}
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	suffix := uuid.NewString()[:8]
+	w := do("POST", "/api/v1/articles", authorToken, models.ArticleCreate{
+		Title: "Diff v1 " + suffix, Content: "The quick brown fox jumps.", Excerpt: "same excerpt", Status: models.StatusDraft,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := "/api/v1/articles/" + created.Data.ID.String()
+
+	// 两次修改产生两条修订：v1 和 v2 的内容
+	version := created.Data.Version
+	for _, v := range []struct{ title, content string }{
+		{"Diff v2 " + suffix, "The quick red fox jumps if a < b."},
+		{"Diff v3 " + suffix, "unrelated"},
+	} {
+		title, content, excerpt := v.title, v.content, "same excerpt"
+		w = do("PUT", path, authorToken, models.ArticleUpdate{Title: &title, Content: &content, Excerpt: &excerpt, ExpectedVersion: &version})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		version++
+	}
+	w = do("GET", path+"/revisions", authorToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []models.ArticleRevisionMeta `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 2)
+	// 最新的在前
+	from, to := list.Data[1], list.Data[0]
+	diffPath := path + "/revisions/" + from.ID.String() + "/diff/" + to.ID.String()
+
+	join := func(hunks []textdiff.Hunk, skip textdiff.Op) string {
+		var sb strings.Builder
+		for _, h := range hunks {
+			if h.Op != skip {
+				sb.WriteString(h.Text)
+			}
+		}
+		return sb.String()
+	}
+
+	// 默认返回按词的差异片段，可还原出两条修订的内容
+	w = do("GET", diffPath, authorToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff struct {
+		Data models.ArticleRevisionDiff `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, from.ID, diff.Data.From.ID)
+	assert.Equal(t, to.ID, diff.Data.To.ID)
+	assert.Equal(t, "The quick brown fox jumps.", join(diff.Data.Content, textdiff.Insert))
+	assert.Equal(t, "The quick red fox jumps if a < b.", join(diff.Data.Content, textdiff.Delete))
+	assert.Contains(t, diff.Data.Content, textdiff.Hunk{Op: textdiff.Delete, Text: "brown"})
+	assert.Contains(t, diff.Data.Content, textdiff.Hunk{Op: textdiff.Insert, Text: "red"})
+	assert.Equal(t, []textdiff.Hunk{
+		{Op: textdiff.Equal, Text: "Diff "},
+		{Op: textdiff.Delete, Text: "v1"},
+		{Op: textdiff.Insert, Text: "v2"},
+		{Op: textdiff.Equal, Text: " " + suffix},
+	}, diff.Data.Title)
+	assert.Equal(t, []textdiff.Hunk{{Op: textdiff.Equal, Text: "same excerpt"}}, diff.Data.Excerpt)
+
+	// format=html 返回转义后的 <ins> / <del> 标记
+	w = do("GET", diffPath+"?format=html", authorToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var rendered struct {
+		Data models.ArticleRevisionDiffHTML `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rendered))
+	assert.Equal(t, "Diff <del>v1</del><ins>v2</ins> "+suffix, rendered.Data.Title)
+	assert.Equal(t, "The quick <del>brown</del><ins>red</ins> fox jumps<ins> if a &lt; b</ins>.", rendered.Data.Content)
+	assert.Equal(t, "same excerpt", rendered.Data.Excerpt)
+	assert.Equal(t, http.StatusBadRequest, do("GET", diffPath+"?format=xml", authorToken, nil).Code)
+
+	// 编辑可以查看，其他用户返回 403
+	assert.Equal(t, http.StatusOK, do("GET", diffPath, editorToken, nil).Code)
+	w = do("GET", diffPath, otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var resp models.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "not_article_author", resp.ErrorCode)
+
+	// 修订记录不存在或不属于该文章返回 404，格式错误返回 400
+	w = do("GET", path+"/revisions/"+from.ID.String()+"/diff/"+uuid.NewString(), authorToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "revision_not_found", resp.ErrorCode)
+	assert.Equal(t, http.StatusNotFound, do("GET", path+"/revisions/"+uuid.NewString()+"/diff/"+to.ID.String(), authorToken, nil).Code)
+	assert.Equal(t, http.StatusNotFound, do("GET", "/api/v1/articles/"+uuid.NewString()+"/revisions/"+from.ID.String()+"/diff/"+to.ID.String(), editorToken, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, do("GET", path+"/revisions/"+from.ID.String()+"/diff/not-a-uuid", authorToken, nil).Code)
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleRevisions(t *testing.T) {
+	authorToken := registerAndLogin(t, "revisions")
+	author := profileID(t, authorToken)
+	otherToken := registerAndLogin(t, "revisions_other")
+	adminToken, err := testJWT.GenerateToken(profileID(t, otherToken), "revisions_admin", string(models.RoleAdmin))
+	require.NoError(t, err)
+
+	do := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			data, _ := json.Marshal(body)
+			buf.Write(data)
+		}
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	suffix := uuid.NewString()[:8]
+	w := do("POST", "/api/v1/articles", authorToken, models.ArticleCreate{Title: "Revision v1 " + suffix, Content: "first body", Status: models.StatusDraft})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := "/api/v1/articles/" + created.Data.ID.String()
+
+	// 新建文章没有修订记录，每次修改把修改前的内容写入一条
+	listRevisions := func(token string) []map[string]interface{} {
+		w := do("GET", path+"/revisions", token, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []map[string]interface{} `json:"data"`
+			Meta models.PaginationMeta    `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.EqualValues(t, len(resp.Data), resp.Meta.Total)
+		return resp.Data
+	}
+	assert.Empty(t, listRevisions(authorToken))
+
+	title, content := "Revision v2 "+suffix, "second body"
+	version := created.Data.Version
+	w = do("PUT", path, authorToken, models.ArticleUpdate{Title: &title, Content: &content, ExpectedVersion: &version})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	revisions := listRevisions(authorToken)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "Revision v1 "+suffix, revisions[0]["title"])
+	assert.EqualValues(t, 1, revisions[0]["version"])
+	assert.Equal(t, string(models.StatusDraft), revisions[0]["status"])
+	assert.Equal(t, author.String(), revisions[0]["editor_id"])
+	assert.NotEmpty(t, revisions[0]["editor_name"])
+	assert.NotContains(t, revisions[0], "content")
+	revisionID := revisions[0]["id"].(string)
+
+	w = do("GET", path+"/revisions/"+revisionID, authorToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var revision struct {
+		Data models.ArticleRevision `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revision))
+	assert.Equal(t, "first body", revision.Data.Content)
+
+	// 只有作者本人、编辑和管理员可以查看和恢复
+	assert.Equal(t, http.StatusForbidden, do("GET", path+"/revisions", otherToken, nil).Code)
+	assert.Equal(t, http.StatusForbidden, do("GET", path+"/revisions/"+revisionID, otherToken, nil).Code)
+	assert.Equal(t, http.StatusForbidden, do("POST", path+"/revisions/"+revisionID+"/restore", otherToken, nil).Code)
+	assert.Len(t, listRevisions(adminToken), 1)
+
+	// 恢复后内容回到修订记录，状态不变，恢复前的内容写入新的修订记录
+	w = do("POST", path+"/revisions/"+revisionID+"/restore", authorToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var restored struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "Revision v1 "+suffix, restored.Data.Title)
+	assert.Equal(t, "first body", restored.Data.Content)
+	assert.Equal(t, models.StatusDraft, restored.Data.Status)
+	assert.Equal(t, created.Data.Version+2, restored.Data.Version)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	revisions = listRevisions(authorToken)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "Revision v2 "+suffix, revisions[0]["title"])
+	assert.EqualValues(t, 2, revisions[0]["version"])
+
+	// 修订记录不存在或不属于该文章返回 404，格式错误返回 400
+	assert.Equal(t, http.StatusNotFound, do("GET", path+"/revisions/"+uuid.NewString(), authorToken, nil).Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", path+"/revisions/"+uuid.NewString()+"/restore", authorToken, nil).Code)
+	assert.Equal(t, http.StatusNotFound, do("GET", "/api/v1/articles/"+uuid.NewString()+"/revisions/"+revisionID, adminToken, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, do("GET", path+"/revisions/not-a-uuid", authorToken, nil).Code)
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleSearch_FallsBackWithoutElasticsearch(t *testing.T) {
+	require.False(t, search.Enabled(), "integration tests run without Elasticsearch")
+	ctx := context.Background()
+	categoryRepo := repository.NewCategoryRepository()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), categoryRepo, repository.NewTagRepository())
+
+	author := profileID(t, registerAndLogin(t, "fallback_author"))
+	other := profileID(t, registerAndLogin(t, "fallback_other"))
+	keyword := "fallbackkw" + uuid.NewString()[:8]
+	category, err := services.NewCategoryService(categoryRepo, repository.NewArticleRepository()).Create(ctx, &models.CategoryCreate{Name: "Fallback " + keyword})
+	require.NoError(t, err)
+
+	create := func(authorID uuid.UUID, title string, status models.ArticleStatus, categoryID *uuid.UUID) *models.Article {
+		article, err := articleService.Create(ctx, authorID, &models.ArticleCreate{
+			Title: title + " " + keyword, Content: "content", Status: status, CategoryID: categoryID,
+		})
+		require.NoError(t, err)
+		return article
+	}
+	alpha := create(author, "Alpha", models.StatusPublished, &category.ID)
+	beta := create(author, "Beta", models.StatusPublished, &category.ID)
+	create(other, "Gamma", models.StatusPublished, nil)
+	create(author, "Draft", models.StatusDraft, &category.ID)
+
+	// 服务层：完整的查询条件原样生效
+	articles, total, err := articleService.SearchWithElasticsearch(ctx, models.ArticleQuery{
+		Search: keyword, Status: models.StatusPublished, CategoryID: &category.ID, AuthorID: &author,
+		SortBy: "title", Order: "asc",
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, articles, 2)
+	assert.Equal(t, alpha.ID, articles[0].ID)
+	assert.Equal(t, beta.ID, articles[1].ID)
+
+	list := func(path string) (int, []models.Article, int64) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data []models.Article      `json:"data"`
+			Meta models.PaginationMeta `json:"meta"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data, resp.Meta.Total
+	}
+
+	// 公开列表：带 search 时走全文搜索，只返回已发布文章，分类、作者和排序参数生效
+	code, data, total := list("/api/v1/articles?search=" + keyword)
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 3, total)
+
+	code, data, total = list("/api/v1/articles?search=" + keyword + "&category_id=" + category.ID.String() + "&sort_by=title&order=desc")
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, data, 2)
+	assert.Equal(t, beta.ID, data[0].ID)
+	assert.Equal(t, alpha.ID, data[1].ID)
+
+	code, data, _ = list("/api/v1/articles?search=" + keyword + "&author_id=" + other.String())
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, data, 1)
+	assert.Equal(t, other, data[0].AuthorID)
+
+	code, _, _ = list("/api/v1/articles?search=" + keyword + "&category_id=not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestArticleSearch_FallbackMatchesContentBody(t *testing.T) {
+	require.False(t, search.Enabled(), "integration tests run without Elasticsearch")
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "fallback_body"))
+	keyword := "bodykw" + uuid.NewString()[:8]
+
+	matched, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Body match " + uuid.NewString()[:8], Content: "The keyword " + keyword + " only appears in the body.",
+		Excerpt: "no keyword here", Status: models.StatusPublished,
+	})
+	require.NoError(t, err)
+	_, err = articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Body miss " + uuid.NewString()[:8], Content: "Unrelated content.", Status: models.StatusPublished,
+	})
+	require.NoError(t, err)
+
+	// 关键词只出现在正文中时同样命中，不返回未筛选的列表
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles?search="+keyword, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data []models.Article      `json:"data"`
+		Meta models.PaginationMeta `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.EqualValues(t, 1, resp.Meta.Total)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, matched.ID, resp.Data[0].ID)
+}
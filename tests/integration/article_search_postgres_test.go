@@ -0,0 +1,76 @@
+//go:build postgres
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/search"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 需要已执行迁移的 PostgreSQL 测试库：
+// DB_DRIVER=postgres go test -tags postgres -run Postgres ./tests/integration/
+func TestArticleSearch_PostgresFullTextFallback(t *testing.T) {
+	require.False(t, database.IsSQLite(), "postgres 标签的用例需要 DB_DRIVER=postgres")
+	require.False(t, search.Enabled(), "integration tests run without Elasticsearch")
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "pg_fts"))
+	keyword := "pgftskw" + uuid.NewString()[:8]
+
+	create := func(title, excerpt, content string, status models.ArticleStatus) *models.Article {
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+			Title: title, Excerpt: excerpt, Content: content, Status: status,
+		})
+		require.NoError(t, err)
+		return article
+	}
+	// search_vector 的权重：标题 A、摘要 B、正文 C
+	body := create("Body match "+uuid.NewString()[:8], "nothing here", "The keyword "+keyword+" only appears in the body.", models.StatusPublished)
+	title := create("Title "+keyword, "nothing here", "plain content", models.StatusPublished)
+	excerpt := create("Excerpt match "+uuid.NewString()[:8], "summary with "+keyword, "plain content", models.StatusPublished)
+	create("Draft "+keyword, "", "draft content", models.StatusDraft)
+	create("Miss "+uuid.NewString()[:8], "", "unrelated content", models.StatusPublished)
+
+	list := func(search string) []models.Article {
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles?search="+url.QueryEscape(search), nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []models.Article      `json:"data"`
+			Meta models.PaginationMeta `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.EqualValues(t, len(resp.Data), resp.Meta.Total)
+		return resp.Data
+	}
+	ids := func(articles []models.Article) []uuid.UUID {
+		out := make([]uuid.UUID, 0, len(articles))
+		for _, a := range articles {
+			out = append(out, a.ID)
+		}
+		return out
+	}
+
+	// 公开列表走 search_vector：正文命中同样返回，只返回已发布文章，按相关度排序
+	assert.Equal(t, []uuid.UUID{title.ID, excerpt.ID, body.ID}, ids(list(keyword)))
+
+	// websearch 语法：-排除
+	assert.Equal(t, []uuid.UUID{excerpt.ID, body.ID}, ids(list(keyword+" -title")))
+
+	// 英文词干：查询 appearing 命中正文中的 appears，LIKE 回退做不到
+	assert.Equal(t, []uuid.UUID{body.ID}, ids(list(keyword+" appearing")))
+}
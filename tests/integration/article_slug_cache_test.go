@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBySlug_DetailCacheAndBufferedViews(t *testing.T) {
+	mr := useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "slug_cache"))
+	suffix := uuid.NewString()[:8]
+
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Slug cache " + suffix, Content: "content", Status: models.StatusPublished,
+	})
+	require.NoError(t, err)
+	slugKey := "blog:article:detail:slug:" + article.Slug
+	viewKey := "blog:article:view:" + article.ID.String()
+	views := func() string {
+		t.Helper()
+		// 浏览计数异步写入
+		require.Eventually(t, func() bool { return mr.Exists(viewKey) }, 3*time.Second, 10*time.Millisecond)
+		v, _ := mr.Get(viewKey)
+		return v
+	}
+
+	// 浏览计数写入 Redis 缓冲，不直接修改数据库
+	found, err := articleService.GetBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, article.ID, found.ID)
+	assert.Equal(t, "1", views())
+	stored, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.ViewCount)
+	assert.True(t, mr.Exists(slugKey))
+
+	// 再次读取命中详情缓存（数据库中直接修改的标题不可见）
+	require.NoError(t, database.DB.Exec(`UPDATE articles SET title = ? WHERE id = ?`, "changed behind cache", article.ID).Error)
+	found, err = articleService.GetBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, article.Title, found.Title)
+	require.Eventually(t, func() bool {
+		v, _ := mr.Get(viewKey)
+		return v == "2"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// 修改 slug 后旧 slug 的映射被删除，按旧 slug 访问走重定向
+	newSlug := "slug-cache-renamed-" + suffix
+	updated, err := articleService.Update(ctx, article.ID, &models.ArticleUpdate{Slug: &newSlug, ExpectedVersion: &stored.Version})
+	require.NoError(t, err)
+	assert.False(t, mr.Exists(slugKey))
+	found, err = articleService.GetBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, updated.Slug, found.Slug)
+	found, err = articleService.GetBySlug(ctx, updated.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, article.ID, found.ID)
+	assert.True(t, mr.Exists("blog:article:detail:slug:"+updated.Slug))
+
+	// 删除后缓存一并清理，不再返回文章
+	require.NoError(t, articleService.Delete(ctx, article.ID))
+	assert.False(t, mr.Exists("blog:article:detail:slug:"+updated.Slug))
+	_, err = articleService.GetBySlug(ctx, updated.Slug)
+	assert.ErrorIs(t, err, repository.ErrArticleNotFound)
+}
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateArticle_SlugStability(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "slug_stable"))
+	suffix := uuid.NewString()[:8]
+
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Slug original " + suffix, Content: "content", Status: models.StatusPublished,
+	})
+	require.NoError(t, err)
+	originalSlug := article.Slug
+	update := func(req *models.ArticleUpdate) *models.Article {
+		t.Helper()
+		current, err := articleRepo.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		req.ExpectedVersion = &current.Version
+		updated, err := articleService.Update(ctx, article.ID, req)
+		require.NoError(t, err)
+		return updated
+	}
+
+	// 修改标题不改变 slug
+	title := "Slug renamed " + suffix
+	updated := update(&models.ArticleUpdate{Title: &title})
+	assert.Equal(t, originalSlug, updated.Slug)
+
+	// 显式修改 slug，旧 slug 仍能找到文章
+	newSlug := "Slug Custom " + suffix
+	updated = update(&models.ArticleUpdate{Slug: &newSlug})
+	assert.Equal(t, services.GenerateSlug(newSlug), updated.Slug)
+	found, err := articleService.GetBySlug(ctx, originalSlug)
+	require.NoError(t, err)
+	assert.Equal(t, article.ID, found.ID)
+	meta, err := articleService.GetMeta(ctx, originalSlug)
+	require.NoError(t, err)
+	assert.Equal(t, title, meta.Title)
+
+	// HTTP 按旧 slug 访问返回 301 和新地址
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/slug/"+originalSlug, nil))
+	require.Equal(t, http.StatusMovedPermanently, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v1/articles/slug/"+updated.Slug, w.Header().Get("Location"))
+	var resp struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, article.ID, resp.Data.ID)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/slug/"+updated.Slug, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 新 slug 与其他文章冲突时追加后缀
+	other, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Slug other " + suffix, Content: "content", Status: models.StatusDraft,
+	})
+	require.NoError(t, err)
+	taken := other.Slug
+	updated = update(&models.ArticleUpdate{Slug: &taken})
+	assert.Equal(t, other.Slug+"-1", updated.Slug)
+
+	// 空字符串按当前标题重新生成
+	empty := ""
+	updated = update(&models.ArticleUpdate{Slug: &empty})
+	assert.Equal(t, services.GenerateSlug(title), updated.Slug)
+
+	// 改回曾经用过的 slug 时不再重定向到自己
+	back := originalSlug
+	updated = update(&models.ArticleUpdate{Slug: &back})
+	assert.Equal(t, originalSlug, updated.Slug)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/slug/"+originalSlug, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleStatusHistory_RecordsTransitions(t *testing.T) {
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+
+	authorToken := registerAndLogin(t, "history_author")
+	otherToken := registerAndLogin(t, "history_other")
+	author := profileID(t, authorToken)
+	reviewer := profileID(t, registerAndLogin(t, "history_reviewer"))
+	authorCtx := logger.WithUserID(ctx, author.String())
+	reviewerCtx := logger.WithUserID(ctx, reviewer.String())
+
+	article, err := articleService.Create(authorCtx, author, &models.ArticleCreate{Title: "History article", Content: "content"})
+	require.NoError(t, err)
+
+	review := models.StatusReview
+	article, err = articleService.Update(authorCtx, article.ID, &models.ArticleUpdate{Status: &review, ExpectedVersion: &article.Version})
+	require.NoError(t, err)
+	article, err = articleService.UpdateStatus(reviewerCtx, article.ID, models.StatusDraft, nil, "  please add sources  ")
+	require.NoError(t, err)
+	// 只改内容不记录
+	title := "History article v2"
+	article, err = articleService.Update(authorCtx, article.ID, &models.ArticleUpdate{Title: &title, ExpectedVersion: &article.Version})
+	require.NoError(t, err)
+	article, err = articleService.Update(authorCtx, article.ID, &models.ArticleUpdate{Status: &review, ExpectedVersion: &article.Version})
+	require.NoError(t, err)
+	_, err = articleService.UpdateStatus(reviewerCtx, article.ID, models.StatusPublished, nil, "")
+	require.NoError(t, err)
+
+	history, err := articleService.StatusHistory(ctx, article.ID, reviewer, true)
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+	assert.Nil(t, history[0].FromStatus)
+	assert.Equal(t, models.StatusDraft, history[0].ToStatus)
+	assert.Equal(t, &author, history[0].ActorID)
+	transitions := make([][2]models.ArticleStatus, 0, 4)
+	for _, h := range history[1:] {
+		require.NotNil(t, h.FromStatus)
+		transitions = append(transitions, [2]models.ArticleStatus{*h.FromStatus, h.ToStatus})
+	}
+	assert.Equal(t, [][2]models.ArticleStatus{
+		{models.StatusDraft, models.StatusReview},
+		{models.StatusReview, models.StatusDraft},
+		{models.StatusDraft, models.StatusReview},
+		{models.StatusReview, models.StatusPublished},
+	}, transitions)
+	assert.Equal(t, "please add sources", history[2].Reason)
+	assert.Equal(t, &reviewer, history[2].ActorID)
+	assert.Contains(t, history[2].ActorName, "history_reviewer")
+
+	_, err = articleService.StatusHistory(ctx, article.ID, profileID(t, otherToken), false)
+	assert.ErrorIs(t, err, services.ErrNotArticleAuthor)
+
+	send := func(token, id string) (int, []models.ArticleStatusChange) {
+		req, _ := http.NewRequest("GET", "/api/v1/articles/"+id+"/status-history", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data []models.ArticleStatusChange `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	code, data := send(authorToken, article.ID.String())
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, data, 5)
+	assert.Equal(t, "please add sources", data[2].Reason)
+
+	code, _ = send(otherToken, article.ID.String())
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = send(authorToken, uuid.NewString())
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = send(authorToken, "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestDashboard_MedianReviewTime(t *testing.T) {
+	if !database.IsSQLite() {
+		t.Skip("uses a temporary SQLite database")
+	}
+	useEmptySQLiteDB(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	now := time.Now()
+
+	// 每篇文章的状态变更：目标状态和距今的时长
+	type step struct {
+		to  models.ArticleStatus
+		ago time.Duration
+	}
+	record := func(steps ...step) {
+		article, err := articleService.Create(ctx, uuid.New(), &models.ArticleCreate{Title: "Review timing " + uuid.NewString(), Content: "content"})
+		require.NoError(t, err)
+		from := models.StatusDraft
+		for _, s := range steps {
+			prev := from
+			require.NoError(t, articleRepo.AddStatusChangeTx(database.DB, &models.ArticleStatusChange{
+				ArticleID: article.ID, FromStatus: &prev, ToStatus: s.to, CreatedAt: now.Add(-s.ago),
+			}))
+			from = s.to
+		}
+	}
+
+	// 1 小时
+	record(step{models.StatusReview, 2 * time.Hour}, step{models.StatusPublished, time.Hour})
+	// 退回后再次提交：只计最后一轮，3 小时
+	record(step{models.StatusReview, 10 * time.Hour}, step{models.StatusDraft, 9 * time.Hour},
+		step{models.StatusReview, 5 * time.Hour}, step{models.StatusPublished, 2 * time.Hour})
+	// 2 小时（定时发布也算通过）
+	record(step{models.StatusReview, 4 * time.Hour}, step{models.StatusScheduled, 2 * time.Hour})
+	// 窗口之外、仍在审核中的不计入
+	record(step{models.StatusReview, 40 * 24 * time.Hour}, step{models.StatusPublished, 35 * 24 * time.Hour})
+	record(step{models.StatusReview, time.Hour})
+
+	data, err := services.NewDashboardService(repository.NewStatsRepository()).Overview(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, data.ReviewSampleSize)
+	require.NotNil(t, data.MedianReviewSeconds)
+	assert.InDelta(t, 2*3600, *data.MedianReviewSeconds, 1)
+}
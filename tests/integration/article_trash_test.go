@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminArticleTrash(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	trashService := services.NewTrashService(repository.NewTrashRepository(), articleRepo, repository.NewUserRepository(), categoryRepo, tagRepo)
+
+	authorToken := registerAndLogin(t, "trash_author")
+	author := profileID(t, authorToken)
+	adminToken, err := testJWT.GenerateToken(uuid.New(), "trash_admin", string(models.RoleAdmin))
+	require.NoError(t, err)
+
+	tag, err := services.NewTagService(tagRepo, articleRepo).Create(ctx, &models.TagCreate{Name: "trash-" + uuid.NewString()[:8]})
+	require.NoError(t, err)
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Trash article " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished, TagIDs: []uuid.UUID{tag.ID},
+	})
+	require.NoError(t, err)
+
+	trashHandler := handlers.NewTrashHandler(trashService)
+	router := gin.New()
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(testJWT), middleware.RoleMiddleware("admin"))
+	admin.GET("/articles/trash", trashHandler.ListArticles)
+	admin.POST("/articles/:id/restore", trashHandler.RestoreArticle)
+	admin.DELETE("/articles/:id/purge", trashHandler.PurgeArticle)
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	trashed := func() []models.TrashItem {
+		t.Helper()
+		w := do("GET", "/admin/articles/trash?page_size=100", adminToken)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []models.TrashItem `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+	inTrash := func() bool {
+		for _, item := range trashed() {
+			if item.ID == article.ID {
+				return true
+			}
+		}
+		return false
+	}
+	path := "/admin/articles/" + article.ID.String()
+
+	// 未删除的文章：恢复为空操作
+	require.Equal(t, http.StatusOK, do("POST", path+"/restore", adminToken).Code)
+	assert.False(t, inTrash())
+
+	// 删除后出现在回收站，恢复后重新可见
+	require.NoError(t, articleService.Delete(ctx, article.ID))
+	assert.True(t, inTrash())
+	w := do("POST", path+"/restore", adminToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, inTrash())
+	restored, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Len(t, restored.Tags, 1)
+
+	// 永久删除只允许管理员，且只能删除回收站中的文章
+	assert.Equal(t, http.StatusForbidden, do("DELETE", path+"/purge", authorToken).Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", path+"/purge", adminToken).Code)
+	require.NoError(t, articleService.Delete(ctx, article.ID))
+	w = do("DELETE", path+"/purge", adminToken)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.False(t, inTrash())
+
+	var rows int64
+	require.NoError(t, database.DB.Raw(`SELECT COUNT(*) FROM article_tags WHERE article_id = ?`, article.ID).Scan(&rows).Error)
+	assert.Zero(t, rows)
+	require.NoError(t, database.DB.Raw(`SELECT COUNT(*) FROM articles WHERE id = ?`, article.ID).Scan(&rows).Error)
+	assert.Zero(t, rows)
+	assert.Equal(t, http.StatusNotFound, do("POST", path+"/restore", adminToken).Code)
+}
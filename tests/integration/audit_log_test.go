@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditMiddleware_TruncatesLongPath(t *testing.T) {
+	ctx := context.Background()
+	auditService := services.NewAuditService(repository.NewAuditLogRepository())
+
+	prefix := "/api/v1/admin/audit-" + uuid.NewString()[:8] + "/"
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.DELETE(prefix+":id", middleware.AuditMiddleware(auditService), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	// 超长的多字节路径参数：截断后仍是合法的 UTF-8
+	id := strings.Repeat("文", 1000)
+	req := httptest.NewRequest(http.MethodDelete, prefix+id, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// 审计日志异步写入
+	var logs []*models.AuditLog
+	require.Eventually(t, func() bool {
+		var err error
+		logs, _, err = auditService.List(ctx, models.AuditLogQuery{PathPrefix: prefix})
+		return err == nil && len(logs) == 1
+	}, 3*time.Second, 20*time.Millisecond)
+
+	entry := logs[0]
+	assert.LessOrEqual(t, len(entry.Path), 500)
+	assert.Greater(t, len(entry.Path), 490)
+	assert.True(t, strings.HasPrefix(entry.Path, prefix))
+	assert.True(t, utf8.ValidString(entry.Path))
+	assert.Equal(t, "DELETE "+prefix+":id", entry.Action)
+
+	detail, err := auditService.GetByID(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(detail.ResourceID), 100)
+	assert.True(t, utf8.ValidString(detail.ResourceID))
+}
@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorPublicProfileAndArticles(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "public_author"))
+	other := profileID(t, registerAndLogin(t, "public_other"))
+
+	for _, a := range []struct {
+		author uuid.UUID
+		title  string
+		status models.ArticleStatus
+	}{
+		{author, "Public one", models.StatusPublished},
+		{author, "Public two", models.StatusPublished},
+		{author, "Public draft", models.StatusDraft},
+		{other, "Other published", models.StatusPublished},
+	} {
+		_, err := articleService.Create(ctx, a.author, &models.ArticleCreate{Title: a.title, Content: "content", Status: a.status})
+		require.NoError(t, err)
+	}
+	deleted, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Public deleted", Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+	require.NoError(t, articleService.Delete(ctx, deleted.ID))
+
+	get := func(path string) (int, []byte) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code, w.Body.Bytes()
+	}
+
+	// 公开资料只有用户名、头像、简介、已发布文章数和注册时间
+	code, body := get("/api/v1/users/" + author.String() + "/public")
+	require.Equal(t, http.StatusOK, code, string(body))
+	var raw struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &raw))
+	assert.NotContains(t, raw.Data, "email")
+	assert.NotContains(t, raw.Data, "phone")
+	assert.NotContains(t, raw.Data, "role")
+	var profile struct {
+		Data models.PublicProfile `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &profile))
+	assert.Equal(t, author, profile.Data.ID)
+	assert.NotEmpty(t, profile.Data.Username)
+	assert.EqualValues(t, 2, profile.Data.ArticleCount)
+	assert.False(t, profile.Data.JoinedAt.IsZero())
+
+	// 文章列表只包含该作者已发布的文章，忽略请求中的 status / author_id
+	code, body = get("/api/v1/users/" + author.String() + "/articles?status=draft&author_id=" + other.String())
+	require.Equal(t, http.StatusOK, code, string(body))
+	var list struct {
+		Data []models.Article      `json:"data"`
+		Meta models.PaginationMeta `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(body, &list))
+	assert.EqualValues(t, 2, list.Meta.Total)
+	for _, a := range list.Data {
+		assert.Equal(t, author, a.AuthorID)
+		assert.Equal(t, models.StatusPublished, a.Status)
+	}
+
+	// 不存在、未激活、已删除的用户返回 404
+	code, _ = get("/api/v1/users/" + uuid.NewString() + "/public")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("/api/v1/users/not-a-uuid/public")
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.NoError(t, database.DB.Exec(`UPDATE users SET status = 'inactive' WHERE id = ?`, other).Error)
+	code, _ = get("/api/v1/users/" + other.String() + "/public")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("/api/v1/users/" + other.String() + "/articles")
+	assert.Equal(t, http.StatusNotFound, code)
+	require.NoError(t, repository.NewUserRepository().Delete(ctx, author))
+	code, _ = get("/api/v1/users/" + author.String() + "/public")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("/api/v1/users/" + author.String() + "/articles")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"enterprise-blog/internal/backup"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/database/sqlite"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// useEmptySQLiteDB 把 database.DB 临时切换到新建的空 SQLite 库，测试结束后切回
+func useEmptySQLiteDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+filepath.Join(t.TempDir(), "restore.db")+"?_busy_timeout=5000&_foreign_keys=on"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	require.NoError(t, err)
+	original := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = original
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	require.NoError(t, database.AutoMigrate())
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	if !database.IsSQLite() {
+		t.Skip("restore target is a temporary SQLite database")
+	}
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	commentRepo := repository.NewCommentRepository()
+	userRepo := repository.NewUserRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, repository.NewTagRepository())
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+
+	author := profileID(t, registerAndLogin(t, "backup_author"))
+	parent, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: "Backup parent"})
+	require.NoError(t, err)
+	child, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: "Backup child", ParentID: &parent.ID})
+	require.NoError(t, err)
+	tag, err := services.NewTagService(repository.NewTagRepository(), articleRepo).Create(ctx, &models.TagCreate{Name: "backup-tag"})
+	require.NoError(t, err)
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Backup article", Content: "backup content", Status: models.StatusPublished, CategoryID: &child.ID, TagIDs: []uuid.UUID{tag.ID},
+	})
+	require.NoError(t, err)
+	comment, err := commentService.Create(ctx, &author, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, Content: "first", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	reply, err := commentService.Create(ctx, &author, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, ParentID: &comment.ID, Content: "reply", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	_, err = articleService.Like(ctx, article.ID, services.NewArticleLiker(nil, "192.0.2.1"))
+	require.NoError(t, err)
+	sourceUser, err := userRepo.GetByID(ctx, author)
+	require.NoError(t, err)
+
+	uploadsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(uploadsDir, "2024"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(uploadsDir, "2024", "a.png"), []byte("png"), 0o644))
+
+	// 导出：每批 2 行，覆盖游标分页（article_tags 为联合主键）
+	var buf bytes.Buffer
+	manifest, err := backup.Export(ctx, repository.NewBackupRepository(), &buf, backup.ExportOptions{
+		Tables: repository.BackupTables, Driver: database.DriverSQLite, UploadsDir: uploadsDir, BatchSize: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, backup.FormatVersion, manifest.FormatVersion)
+	assert.Equal(t, 1, manifest.Uploads)
+	users, _ := manifest.Table("users")
+	assert.Contains(t, users.TimeColumns, "created_at")
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	// 恢复到空库：UUID、时间戳、密码哈希和父子关系保持不变
+	useEmptySQLiteDB(t)
+	restoredUploads := t.TempDir()
+	result, err := backup.Restore(ctx, repository.NewBackupRepository(), zr, backup.RestoreOptions{
+		Tables: repository.BackupTables, UploadsDir: restoredUploads, BatchSize: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Tables, len(repository.BackupTables))
+	for _, table := range result.Tables {
+		expected, _ := manifest.Table(table.Name)
+		assert.Equal(t, expected.Rows, table.Inserted, table.Name)
+	}
+	assert.Equal(t, 1, result.Uploads)
+	data, err := os.ReadFile(filepath.Join(restoredUploads, "2024", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	restoredUser, err := userRepo.GetByID(ctx, author)
+	require.NoError(t, err)
+	assert.Equal(t, sourceUser.Password, restoredUser.Password)
+	assert.True(t, restoredUser.CreatedAt.Equal(sourceUser.CreatedAt))
+
+	restoredArticle, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, article.Slug, restoredArticle.Slug)
+	assert.True(t, restoredArticle.CreatedAt.Equal(article.CreatedAt))
+	require.Len(t, restoredArticle.Tags, 1)
+	assert.Equal(t, tag.ID, restoredArticle.Tags[0].ID)
+
+	restoredChild, err := categoryRepo.GetByID(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &parent.ID, restoredChild.ParentID)
+	restoredReply, err := commentRepo.GetByID(ctx, reply.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &comment.ID, restoredReply.ParentID)
+
+	// 目标库非空时拒绝恢复；--force 时跳过已存在的行
+	_, err = backup.Restore(ctx, repository.NewBackupRepository(), zr, backup.RestoreOptions{Tables: repository.BackupTables})
+	var notEmpty *backup.NotEmptyError
+	require.ErrorAs(t, err, &notEmpty)
+	assert.Equal(t, []string{"users", "categories", "tags", "articles", "article_tags", "article_likes", "comments", "article_status_history", "article_revisions", "article_slug_redirects"}, notEmpty.Tables)
+
+	tables, err := backup.ResolveTables([]string{"tags", "users"})
+	require.NoError(t, err)
+	result, err = backup.Restore(ctx, repository.NewBackupRepository(), zr, backup.RestoreOptions{Tables: tables, Force: true})
+	require.NoError(t, err)
+	require.Len(t, result.Tables, 2)
+	assert.Equal(t, "users", result.Tables[0].Name)
+	assert.Zero(t, result.Tables[0].Inserted)
+	assert.Equal(t, users.Rows, result.Tables[0].Read)
+
+	_, err = backup.ResolveTables([]string{"settings"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/metrics"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricValue 从默认 registry 中读取计数器或 gauge 的当前值，指标或标签组合不存在时为 0
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := 0
+			for _, pair := range m.GetLabel() {
+				if labels[pair.GetName()] == pair.GetValue() {
+					matched++
+				}
+			}
+			if matched != len(labels) {
+				continue
+			}
+			if m.GetCounter() != nil {
+				return m.GetCounter().GetValue()
+			}
+			return m.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
+
+// useMiniRedis 使用内存 Redis 替换全局客户端，测试结束后恢复
+// 替换和恢复前都等待后台的浏览计数写入完成，避免其读取全局客户端时与替换产生数据竞争
+func useMiniRedis(t *testing.T) *miniredis.Miniredis {
+	mr := miniredis.RunT(t)
+	require.NoError(t, services.WaitArticleViewCounts(context.Background()))
+	prev := database.RedisClient
+	database.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		require.NoError(t, services.WaitArticleViewCounts(context.Background()))
+		database.RedisClient.Close()
+		database.RedisClient = prev
+	})
+	return mr
+}
+
+func TestBusinessMetrics_RecordedAfterPersistence(t *testing.T) {
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository()
+	articleRepo := repository.NewArticleRepository()
+	commentRepo := repository.NewCommentRepository()
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), testJWT)
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+	smsService := services.NewSMSService(repository.NewSMSRepository(), userRepo)
+
+	// 计数器在进程内累计，按差值断言
+	counters := []struct {
+		name   string
+		labels map[string]string
+	}{
+		{"user_registrations_total", nil},
+		{"user_logins_total", map[string]string{"method": "password"}},
+		{"article_creations_total", nil},
+		{"article_likes_total", nil},
+		{"comment_creations_total", nil},
+		{"sms_codes_sent_total", nil},
+		{"searches_total", map[string]string{"backend": "database"}},
+	}
+	before := make(map[string]float64)
+	for _, c := range counters {
+		before[c.name] = metricValue(t, c.name, c.labels)
+	}
+
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("metrics_%d@example.com", suffix)
+	user, err := userService.Register(ctx, &models.UserCreate{Username: fmt.Sprintf("metrics_%d", suffix), Email: email, Password: "password123"})
+	require.NoError(t, err)
+	_, _, err = userService.Login(ctx, &models.UserLogin{Email: email, Password: "password123"}, "")
+	require.NoError(t, err)
+	// 登录失败不计数
+	_, _, err = userService.Login(ctx, &models.UserLogin{Email: email, Password: "wrong-password"}, "")
+	require.Error(t, err)
+
+	article, err := articleService.Create(ctx, user.ID, &models.ArticleCreate{Title: fmt.Sprintf("Metrics %d", suffix), Content: "metrics content"})
+	require.NoError(t, err)
+	_, err = articleService.Like(ctx, article.ID, services.NewArticleLiker(&user.ID, ""))
+	require.NoError(t, err)
+	_, err = commentService.Create(ctx, &user.ID, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, Content: "metrics", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	require.NoError(t, smsService.SendCode(ctx, fmt.Sprintf("138%08d", suffix%100000000)))
+	_, _, err = articleService.List(ctx, models.ArticleQuery{Search: "metrics content"})
+	require.NoError(t, err)
+
+	for _, c := range counters {
+		assert.Equal(t, before[c.name]+1, metricValue(t, c.name, c.labels), c.name)
+	}
+}
+
+func TestBusinessMetrics_CacheHitsAndActiveUsers(t *testing.T) {
+	ctx := context.Background()
+	mr := useMiniRedis(t)
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+
+	// 文章列表：第一次未命中并写入缓存，第二次命中
+	hit := map[string]string{"cache": "article_list"}
+	hitsBefore, missesBefore := metricValue(t, "cache_hits_total", hit), metricValue(t, "cache_misses_total", hit)
+	query := models.ArticleQuery{Page: 1, PageSize: 5, Status: models.StatusPublished}
+	_, _, err := articleService.List(ctx, query)
+	require.NoError(t, err)
+	_, _, err = articleService.List(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, missesBefore+1, metricValue(t, "cache_misses_total", hit))
+	assert.Equal(t, hitsBefore+1, metricValue(t, "cache_hits_total", hit))
+
+	// 认证请求记录活跃用户，超出 15 分钟窗口的用户在刷新时被清理
+	token := registerAndLogin(t, "metrics_active")
+	req, _ := http.NewRequest("GET", "/api/v1/users/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	_, err = mr.ZAdd("blog:users:active", float64(time.Now().Add(-20*time.Minute).Unix()), "stale-user")
+	require.NoError(t, err)
+
+	n, err := services.RefreshActiveUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, float64(1), metricValue(t, "active_users", nil))
+	members, err := mr.ZMembers("blog:users:active")
+	require.NoError(t, err)
+	assert.NotContains(t, members, "stale-user")
+}
+
+func TestMetrics_RecordedWhenHandlersAreExercised(t *testing.T) {
+	useMiniRedis(t)
+	// 与 database.InitRedis 相同，通过钩子记录 Redis 命令
+	database.RedisClient.AddHook(database.RedisMetricsHook{})
+
+	userRepo := repository.NewUserRepository()
+	userHandler := handlers.NewUserHandler(services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), testJWT), services.NewSMSService(repository.NewSMSRepository(), userRepo), testJWT)
+	router := gin.New()
+	router.Use(metrics.MetricsMiddleware())
+	router.POST("/auth/register", userHandler.Register)
+	router.POST("/auth/send-sms-code", userHandler.SendSMSCode)
+
+	httpCreated := map[string]string{"method": "POST", "path": "/auth/register", "status": "201"}
+	httpBadRequest := map[string]string{"method": "POST", "path": "/auth/register", "status": "400"}
+	dbInsert := map[string]string{"operation": "insert", "table": "users"}
+	redisTotal := func() float64 {
+		families, err := prometheus.DefaultGatherer.Gather()
+		require.NoError(t, err)
+		var total float64
+		for _, family := range families {
+			if family.GetName() == "redis_operations_total" {
+				for _, m := range family.GetMetric() {
+					total += m.GetCounter().GetValue()
+				}
+			}
+		}
+		return total
+	}
+	before := map[string]float64{
+		"created":       metricValue(t, "http_requests_total", httpCreated),
+		"bad_request":   metricValue(t, "http_requests_total", httpBadRequest),
+		"registrations": metricValue(t, "user_registrations_total", nil),
+		"db_insert":     metricValue(t, "db_queries_total", dbInsert),
+		"sms":           metricValue(t, "sms_codes_sent_total", nil),
+	}
+	redisBefore := redisTotal()
+
+	post := func(path string, body interface{}) int {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	suffix := time.Now().UnixNano()
+	require.Equal(t, http.StatusCreated, post("/auth/register", models.UserCreate{Username: fmt.Sprintf("http_metrics_%d", suffix), Email: fmt.Sprintf("http_metrics_%d@example.com", suffix), Password: "password123"}))
+	require.Equal(t, http.StatusBadRequest, post("/auth/register", map[string]string{"email": "not-an-email"}))
+	require.Equal(t, http.StatusOK, post("/auth/send-sms-code", models.SendSMSCodeRequest{Phone: fmt.Sprintf("139%08d", suffix%100000000)}))
+
+	assert.Equal(t, before["created"]+1, metricValue(t, "http_requests_total", httpCreated))
+	assert.Equal(t, before["bad_request"]+1, metricValue(t, "http_requests_total", httpBadRequest))
+	assert.Equal(t, before["registrations"]+1, metricValue(t, "user_registrations_total", nil))
+	assert.GreaterOrEqual(t, metricValue(t, "db_queries_total", dbInsert), before["db_insert"]+1)
+	assert.Equal(t, before["sms"]+1, metricValue(t, "sms_codes_sent_total", nil))
+	// 发送验证码时检查发送频率写入 Redis
+	assert.Greater(t, redisTotal(), redisBefore)
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteCategory_InUseAndReassign(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := repository.NewCategoryRepository()
+	articleRepo := repository.NewArticleRepository()
+	categoryService := services.NewCategoryService(categoryRepo, articleRepo)
+	articleService := services.NewArticleService(articleRepo, categoryRepo, repository.NewTagRepository())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.DELETE("/api/v1/admin/categories/:id", handlers.NewCategoryHandler(categoryService).Delete)
+	deleteCategory := func(id uuid.UUID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/categories/"+id.String()+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	suffix := uuid.NewString()[:8]
+	source, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "Delete source " + suffix})
+	require.NoError(t, err)
+	target, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "Delete target " + suffix})
+	require.NoError(t, err)
+	child, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "Delete child " + suffix, ParentID: &source.ID})
+	require.NoError(t, err)
+
+	author := profileID(t, registerAndLogin(t, "category_delete"))
+	var articles []*models.Article
+	for _, status := range []models.ArticleStatus{models.StatusPublished, models.StatusDraft} {
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+			Title: "Category delete " + uuid.NewString()[:8], Content: "content", Status: status, CategoryID: &source.ID,
+		})
+		require.NoError(t, err)
+		articles = append(articles, article)
+	}
+
+	// 仍被文章（含草稿）和子分类引用：409，附带引用计数，分类不删除
+	w := deleteCategory(source.ID, "")
+	require.Equal(t, http.StatusConflict, w.Code)
+	var resp struct {
+		ErrorCode string                      `json:"error_code"`
+		Data      services.CategoryInUseError `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, i18n.CodeCategoryInUse, resp.ErrorCode)
+	assert.Equal(t, int64(2), resp.Data.Articles)
+	assert.Equal(t, int64(1), resp.Data.Children)
+	_, err = categoryRepo.GetByID(ctx, source.ID)
+	require.NoError(t, err)
+
+	// 转移目标不合法：400
+	assert.Equal(t, http.StatusBadRequest, deleteCategory(source.ID, "?reassign_to="+source.ID.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, deleteCategory(source.ID, "?reassign_to="+child.ID.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, deleteCategory(source.ID, "?reassign_to="+uuid.NewString()).Code)
+	w = deleteCategory(source.ID, "?reassign_to=not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), i18n.CodeInvalidReassignTarget)
+
+	// 指定 reassign_to：文章和子分类转移到目标分类后删除
+	require.Equal(t, http.StatusNoContent, deleteCategory(source.ID, "?reassign_to="+target.ID.String()).Code)
+	for _, article := range articles {
+		moved, err := articleRepo.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		require.NotNil(t, moved.CategoryID)
+		assert.Equal(t, target.ID, *moved.CategoryID)
+		// 转移递增版本号，持有旧版本的编辑者保存时得到版本冲突
+		assert.Equal(t, article.Version+1, moved.Version)
+	}
+	movedChild, err := categoryRepo.GetByID(ctx, child.ID)
+	require.NoError(t, err)
+	require.NotNil(t, movedChild.ParentID)
+	assert.Equal(t, target.ID, *movedChild.ParentID)
+	_, err = categoryRepo.GetByID(ctx, source.ID)
+	assert.Error(t, err)
+
+	// 没有引用的分类可以直接删除
+	assert.Equal(t, http.StatusNoContent, deleteCategory(child.ID, "").Code)
+}
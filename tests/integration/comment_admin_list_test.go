@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminComments_FilterByStatus(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	commentRepo := repository.NewCommentRepository()
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+
+	author := profileID(t, registerAndLogin(t, "admin_comments"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Admin comments " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	create := func(status string) *models.Comment {
+		c := &models.Comment{ArticleID: article.ID, Content: "hi", Author: "guest", Status: status}
+		require.NoError(t, commentRepo.Create(ctx, c))
+		return c
+	}
+	pending := create(models.CommentStatusPending)
+	reported := create(models.CommentStatusReported)
+	create(models.CommentStatusApproved)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/comments", handlers.NewCommentHandler(commentService).AdminList)
+
+	list := func(status string) []models.Comment {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/comments?page_size=100&status="+status, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data []models.Comment `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+	ids := func(comments []models.Comment) []uuid.UUID {
+		out := make([]uuid.UUID, 0, len(comments))
+		for _, c := range comments {
+			assert.NotEqual(t, models.CommentStatusApproved, c.Status)
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	// 仪表盘待处理队列的链接按状态筛选
+	assert.Contains(t, ids(list(models.CommentStatusPending)), pending.ID)
+	assert.NotContains(t, ids(list(models.CommentStatusPending)), reported.ID)
+	assert.Contains(t, ids(list(models.CommentStatusReported)), reported.ID)
+
+	// 已删除的评论不再出现
+	require.NoError(t, commentService.Delete(ctx, pending.ID))
+	assert.NotContains(t, ids(list(models.CommentStatusPending)), pending.ID)
+}
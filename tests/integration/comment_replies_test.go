@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentList_IncludesReplies(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	commentRepo := repository.NewCommentRepository()
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+
+	author := profileID(t, registerAndLogin(t, "comment_replies"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Replies " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	comment := func(userID *uuid.UUID, parentID *uuid.UUID, content string) *models.Comment {
+		c, err := commentService.Create(ctx, userID, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, ParentID: parentID, Content: content, Author: "guest", Email: "guest@example.com"})
+		require.NoError(t, err)
+		return c
+	}
+	first := comment(nil, nil, "first")
+	firstReply := comment(&author, &first.ID, "first reply")
+	nested := comment(nil, &firstReply.ID, "reply to reply")
+	removed := comment(nil, &first.ID, "removed reply")
+	second := comment(nil, nil, "second")
+	require.NoError(t, commentRepo.Delete(ctx, removed.ID))
+
+	req, _ := http.NewRequest("GET", "/api/v1/articles/"+article.ID.String()+"/comments", nil)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []*models.Comment     `json:"data"`
+		Meta models.PaginationMeta `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// 分页和总数只计父评论，按创建时间倒序
+	assert.EqualValues(t, 2, resp.Meta.Total)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, second.ID, resp.Data[0].ID)
+	assert.Empty(t, resp.Data[0].Replies)
+
+	// 回复按创建时间正序，回复的回复归到最上层的父评论下，已删除的回复不返回
+	top := resp.Data[1]
+	assert.Equal(t, first.ID, top.ID)
+	require.Len(t, top.Replies, 2)
+	assert.Equal(t, firstReply.ID, top.Replies[0].ID)
+	assert.Equal(t, nested.ID, top.Replies[1].ID)
+	require.NotNil(t, top.Replies[1].ParentID)
+	assert.Equal(t, firstReply.ID, *top.Replies[1].ParentID)
+	require.NotNil(t, top.Replies[0].User)
+	assert.Equal(t, author, top.Replies[0].User.ID)
+}
@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComments_SoftDeleteFollowsArticle(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	commentRepo := repository.NewCommentRepository()
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+	trashService := services.NewTrashService(repository.NewTrashRepository(), articleRepo, repository.NewUserRepository(), categoryRepo, tagRepo)
+	statsRepo := repository.NewStatsRepository()
+
+	author := profileID(t, registerAndLogin(t, "comment_trash"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Comment trash " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	comment := func(parentID *uuid.UUID) *models.Comment {
+		c, err := commentService.Create(ctx, nil, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, ParentID: parentID, Content: "hi", Author: "guest", Email: "guest@example.com"})
+		require.NoError(t, err)
+		return c
+	}
+	kept := comment(nil)
+	reply := comment(&kept.ID)
+	removed := comment(nil)
+	removedReply := comment(&removed.ID)
+
+	visible := func() int64 {
+		_, total, err := commentService.GetByArticleID(ctx, article.ID, 1, 20)
+		require.NoError(t, err)
+		return total
+	}
+	pendingComments := func() int64 {
+		data, err := statsRepo.DashboardCounts(ctx, time.Now())
+		require.NoError(t, err)
+		return data.PendingComments
+	}
+	rows := func() int64 {
+		var n int64
+		require.NoError(t, database.DB.Raw(`SELECT COUNT(*) FROM comments WHERE article_id = ?`, article.ID).Scan(&n).Error)
+		return n
+	}
+	pending := pendingComments()
+
+	// 单独删除评论：软删除，回复一并删除
+	require.NoError(t, commentService.Delete(ctx, removed.ID))
+	_, err = commentRepo.GetByID(ctx, removedReply.ID)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, visible())
+	assert.Equal(t, pending-2, pendingComments())
+	assert.Error(t, commentService.Delete(ctx, removed.ID))
+
+	// 删除文章：其余评论随之软删除
+	require.NoError(t, articleService.Delete(ctx, article.ID))
+	assert.EqualValues(t, 0, visible())
+	_, err = commentRepo.GetByID(ctx, reply.ID)
+	assert.Error(t, err)
+	assert.Equal(t, pending-4, pendingComments())
+	assert.EqualValues(t, 4, rows())
+
+	// 恢复文章：只恢复随文章删除的评论
+	require.NoError(t, trashService.Restore(ctx, models.TrashArticles, article.ID))
+	assert.EqualValues(t, 1, visible())
+	_, err = commentRepo.GetByID(ctx, reply.ID)
+	assert.NoError(t, err)
+	_, err = commentRepo.GetByID(ctx, removed.ID)
+	assert.Error(t, err)
+	assert.Equal(t, pending-2, pendingComments())
+
+	// 永久删除文章：评论物理删除
+	require.NoError(t, articleService.Delete(ctx, article.ID))
+	require.NoError(t, trashService.Purge(ctx, models.TrashArticles, article.ID))
+	assert.EqualValues(t, 0, rows())
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentFilter_Policies(t *testing.T) {
+	ctx := context.Background()
+	settings := services.NewSettingsService(repository.NewSettingRepository())
+	require.NoError(t, settings.Init(ctx))
+	setSettings := func(values map[string]interface{}) {
+		t.Helper()
+		_, err := settings.Update(ctx, values, nil)
+		require.NoError(t, err)
+	}
+
+	// 词表来自文件和 settings 两处
+	wordsFile := filepath.Join(t.TempDir(), "words.txt")
+	require.NoError(t, os.WriteFile(wordsFile, []byte("# 测试词表\nspam: buy now, Casino\n违禁词\n"), 0o600))
+	filter := services.NewContentFilter()
+	require.NoError(t, filter.LoadFile(wordsFile))
+	setSettings(map[string]interface{}{models.SettingContentFilterWords: "abuse: idiot"})
+	t.Cleanup(func() {
+		_ = filter.LoadFile("")
+		_, _ = settings.Update(ctx, map[string]interface{}{
+			models.SettingContentFilterWords:  "",
+			models.SettingContentFilterPolicy: models.ContentFilterReject,
+			models.SettingCommentModeration:   true,
+		}, nil)
+	})
+
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	commentService := services.NewCommentService(repository.NewCommentRepository(), articleRepo)
+	token := registerAndLogin(t, "filter_author")
+	author := profileID(t, token)
+
+	post := func(path string, body interface{}) (int, models.Response) {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp models.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp
+	}
+
+	// reject（默认）：返回 422 和命中的分类，不包含具体的词；匹配忽略大小写
+	code, resp := post("/api/v1/articles", models.ArticleCreate{Title: "Deals", Content: "BUY NOW before it is gone"})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, i18n.CodeContentRejected, resp.ErrorCode)
+	assert.Equal(t, map[string]interface{}{"categories": []interface{}{"spam"}}, resp.Data)
+
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Clean article", Content: "nothing to see", Status: models.StatusPublished})
+	require.NoError(t, err)
+	code, resp = post("/api/v1/articles/"+article.ID.String()+"/comments", models.CommentCreate{
+		ArticleID: article.ID, Content: "you idiot, go to the casino", Author: "guest", Email: "guest@example.com",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, map[string]interface{}{"categories": []interface{}{"abuse", "spam"}}, resp.Data)
+
+	// replace：命中的词替换为等长的 *，slug 和摘要由替换后的内容生成
+	setSettings(map[string]interface{}{models.SettingContentFilterPolicy: models.ContentFilterReplace})
+	replaced, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Casino night", Content: "这是违禁词示例"})
+	require.NoError(t, err)
+	assert.Equal(t, "****** night", replaced.Title)
+	assert.Equal(t, "这是***示例", replaced.Content)
+	assert.Equal(t, "这是***示例", replaced.Excerpt)
+	assert.NotContains(t, replaced.Slug, "casino")
+
+	comment, err := commentService.Create(ctx, &author, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, Content: "Idiot!", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "*****!", comment.Content)
+
+	// review：要发布的文章改为待审核，草稿不变；关闭评论审核时命中的评论仍需审核
+	setSettings(map[string]interface{}{
+		models.SettingContentFilterPolicy: models.ContentFilterReview,
+		models.SettingCommentModeration:   false,
+	})
+	reviewed, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Casino guide", Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusReview, reviewed.Status)
+	assert.Equal(t, "Casino guide", reviewed.Title)
+
+	draft, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Casino draft", Content: "content"})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusDraft, draft.Status)
+
+	content := "now with casino links"
+	updated, err := articleService.Update(ctx, article.ID, &models.ArticleUpdate{Content: &content, ExpectedVersion: &article.Version})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusReview, updated.Status)
+
+	comment, err = commentService.Create(ctx, &author, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, Content: "idiot", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, models.CommentStatusPending, comment.Status)
+	comment, err = commentService.Create(ctx, &author, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, Content: "nice post", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, models.CommentStatusApproved, comment.Status)
+}
@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailVerificationFlow(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	sender := &recordingEmailSender{}
+	userRepo := repository.NewUserRepository()
+	verificationService := services.NewEmailVerificationService(repository.NewEmailVerificationRepository(), userRepo, sender,
+		config.ServerConfig{FrontendURL: "https://blog.example.com/"})
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), testJWT)
+	userService.SetEmailVerification(verificationService)
+	userHandler := handlers.NewUserHandler(userService, services.NewSMSService(repository.NewSMSRepository(), userRepo), testJWT)
+	verificationHandler := handlers.NewEmailVerificationHandler(verificationService)
+	router := gin.New()
+	router.POST("/auth/register", userHandler.Register)
+	router.POST("/auth/login", userHandler.Login)
+	router.POST("/auth/verify-email", verificationHandler.Verify)
+	router.POST("/auth/resend-verification", verificationHandler.Resend)
+
+	post := func(path string, body interface{}) (int, string) {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp struct {
+			ErrorCode string `json:"error_code"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp.ErrorCode
+	}
+	// latestToken 等待后台发送完成，返回最近一封验证邮件中的令牌
+	latestToken := func(email string) string {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, verificationService.Wait(waitCtx))
+		sent := sender.sentTo(email)
+		require.NotEmpty(t, sent)
+		msg := sent[len(sent)-1]
+		assert.Contains(t, msg.HTML, "https://blog.example.com/verify-email?token=")
+		match := confirmTokenPattern.FindStringSubmatch(msg.HTML)
+		require.Len(t, match, 2)
+		return match[1]
+	}
+	// allowResend 把已发送记录的时间提前，越过重新发送的间隔限制
+	allowResend := func() {
+		t.Helper()
+		require.NoError(t, database.DB.Exec(`UPDATE email_verifications SET created_at = ?`, time.Now().Add(-2*time.Minute)).Error)
+	}
+
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("verify_%d@example.com", suffix)
+	login := models.UserLogin{Email: email, Password: "password123"}
+	code, _ := post("/auth/register", models.UserCreate{Username: fmt.Sprintf("verify_%d", suffix), Email: email, Password: "password123"})
+	require.Equal(t, http.StatusCreated, code)
+	user, err := userRepo.GetByEmail(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", user.Status)
+	first := latestToken(email)
+
+	// 未验证不能登录
+	code, errorCode := post("/auth/login", login)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, i18n.CodeEmailNotVerified, errorCode)
+
+	// 1 分钟内重复发送返回 429；不存在的邮箱同样返回成功且不发送
+	code, errorCode = post("/auth/resend-verification", models.ResendVerificationRequest{Email: email})
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	assert.Equal(t, i18n.CodeVerificationTooFrequent, errorCode)
+	unknown := fmt.Sprintf("nobody_%d@example.com", suffix)
+	code, _ = post("/auth/resend-verification", models.ResendVerificationRequest{Email: unknown})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, sender.sentTo(unknown))
+
+	// 重新发送后旧令牌作废
+	allowResend()
+	code, _ = post("/auth/resend-verification", models.ResendVerificationRequest{Email: email})
+	require.Equal(t, http.StatusOK, code)
+	second := latestToken(email)
+	assert.NotEqual(t, first, second)
+
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"replaced token", first},
+		{"tampered token", second[:len(second)-1] + "x"},
+		{"unknown token", "deadbeef"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, errorCode := post("/auth/verify-email", models.VerifyEmailRequest{Token: tc.token})
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Equal(t, i18n.CodeInvalidVerificationToken, errorCode)
+		})
+	}
+
+	// 过期的令牌无效
+	require.NoError(t, database.DB.Exec(`UPDATE email_verifications SET expires_at = ? WHERE user_id = ?`, time.Now().Add(-time.Minute), user.ID).Error)
+	code, errorCode = post("/auth/verify-email", models.VerifyEmailRequest{Token: second})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeInvalidVerificationToken, errorCode)
+
+	// 验证成功后可以登录，令牌只能使用一次
+	allowResend()
+	code, _ = post("/auth/resend-verification", models.ResendVerificationRequest{Email: email})
+	require.Equal(t, http.StatusOK, code)
+	third := latestToken(email)
+	code, _ = post("/auth/verify-email", models.VerifyEmailRequest{Token: third})
+	require.Equal(t, http.StatusOK, code)
+	user, err = userRepo.GetByEmail(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, "active", user.Status)
+	code, _ = post("/auth/verify-email", models.VerifyEmailRequest{Token: third})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = post("/auth/login", login)
+	assert.Equal(t, http.StatusOK, code)
+
+	// 已验证的邮箱不再发送
+	allowResend()
+	sentBefore := len(sender.sentTo(email))
+	code, _ = post("/auth/resend-verification", models.ResendVerificationRequest{Email: email})
+	assert.Equal(t, http.StatusOK, code)
+	require.NoError(t, verificationService.Wait(ctx))
+	assert.Len(t, sender.sentTo(email), sentBefore)
+
+	// 被禁用的账号不会因验证邮箱而恢复
+	disabledEmail := fmt.Sprintf("verify_disabled_%d@example.com", suffix)
+	code, _ = post("/auth/register", models.UserCreate{Username: fmt.Sprintf("verify_disabled_%d", suffix), Email: disabledEmail, Password: "password123"})
+	require.Equal(t, http.StatusCreated, code)
+	token := latestToken(disabledEmail)
+	require.NoError(t, database.DB.Exec(`UPDATE users SET status = 'inactive' WHERE email = ?`, disabledEmail).Error)
+	code, _ = post("/auth/verify-email", models.VerifyEmailRequest{Token: token})
+	assert.Equal(t, http.StatusOK, code)
+	disabled, err := userRepo.GetByEmail(ctx, disabledEmail)
+	require.NoError(t, err)
+	assert.Equal(t, "inactive", disabled.Status)
+}
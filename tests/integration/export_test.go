@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 导出按页读取，仓库层把每页行数限制在分页上限（100）以内，超过一页的数据也要全部导出
+func TestExportUsersCSV_MoreThanOnePage(t *testing.T) {
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository()
+	exportService := services.NewExportService(userRepo, repository.NewArticleRepository(), t.TempDir(), 10000)
+
+	suffix := time.Now().UnixNano()
+	for i := 0; i < 150; i++ {
+		now := time.Now()
+		require.NoError(t, userRepo.Create(ctx, &models.User{
+			ID:        uuid.New(),
+			Username:  fmt.Sprintf("export_%d_%d", suffix, i),
+			Email:     fmt.Sprintf("export_%d_%d@example.com", suffix, i),
+			Password:  "x",
+			Role:      models.RoleReader,
+			Status:    "active",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}))
+	}
+
+	total, err := exportService.CountUsers(ctx, models.UserQuery{})
+	require.NoError(t, err)
+	require.Greater(t, total, int64(100))
+
+	var buf bytes.Buffer
+	written, err := exportService.WriteUsersCSV(ctx, &buf, models.UserQuery{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, total, written)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, int(total)+1) // 含表头
+
+	// limit 仍然生效
+	buf.Reset()
+	written, err = exportService.WriteUsersCSV(ctx, &buf, models.UserQuery{}, 120)
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), written)
+}
+
+// 异步导出任务的状态保存在 Redis 中，其他实例（共享导出目录）同样可以查询和下载
+func TestExportJob_SharedThroughRedis(t *testing.T) {
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository()
+	articleRepo := repository.NewArticleRepository()
+	dir := t.TempDir()
+
+	require.NoError(t, services.WaitArticleViewCounts(ctx))
+	withoutRedis := database.RedisClient
+	database.RedisClient = nil
+	_, err := services.NewExportService(userRepo, articleRepo, dir, 10).StartUsersJob(ctx, models.UserQuery{})
+	database.RedisClient = withoutRedis
+	assert.ErrorIs(t, err, database.ErrRedisUnavailable)
+
+	useMiniRedis(t)
+	started, err := services.NewExportService(userRepo, articleRepo, dir, 10).StartUsersJob(ctx, models.UserQuery{})
+	require.NoError(t, err)
+
+	other := services.NewExportService(userRepo, articleRepo, dir, 10)
+	require.Eventually(t, func() bool {
+		job, err := other.GetJob(ctx, started.ID)
+		return err == nil && job.Status == models.ExportJobDone
+	}, 5*time.Second, 20*time.Millisecond)
+
+	job, path, err := other.JobFile(ctx, started.ID)
+	require.NoError(t, err)
+	assert.Equal(t, started.FileName, job.FileName)
+	assert.Positive(t, job.Rows)
+	assert.FileExists(t, path)
+
+	// 导出目录未共享的实例查得到任务，但没有文件
+	_, _, err = services.NewExportService(userRepo, articleRepo, t.TempDir(), 10).JobFile(ctx, started.ID)
+	assert.ErrorIs(t, err, services.ErrExportFileMissing)
+
+	_, err = other.GetJob(ctx, uuid.New())
+	assert.ErrorIs(t, err, services.ErrExportJobNotFound)
+}
+
+// 任务记录过期（24 小时）后导出文件无法下载，清理时删除超过保留时间的导出文件和残留的临时文件
+func TestExportService_PruneExpiredFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	exportService := services.NewExportService(repository.NewUserRepository(), repository.NewArticleRepository(), dir, 10)
+
+	old := time.Now().Add(-25 * time.Hour)
+	write := func(name string, modTime time.Time) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("id,email\n"), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		return path
+	}
+	expired := write(uuid.NewString()+".csv", old)
+	staleTmp := write(uuid.NewString()+".csv.tmp", old)
+	fresh := write(uuid.NewString()+".csv", time.Now())
+	unrelated := write("README.txt", old)
+
+	n, err := exportService.PruneExpiredFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoFileExists(t, expired)
+	assert.NoFileExists(t, staleTmp)
+	assert.FileExists(t, fresh)
+	assert.FileExists(t, unrelated)
+}
@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"enterprise-blog/internal/flags"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagService_Update(t *testing.T) {
+	ctx := context.Background()
+	svc := flags.NewService(repository.NewFeatureFlagRepository())
+	require.NoError(t, svc.Init(ctx))
+
+	on, off, half := true, false, 50
+	t.Cleanup(func() {
+		_, _ = svc.Update(ctx, map[string]models.FeatureFlagChange{models.FlagCookieAuth: {Enabled: &off}}, nil)
+	})
+
+	list, err := svc.Update(ctx, map[string]models.FeatureFlagChange{models.FlagCookieAuth: {Enabled: &on, RolloutPercent: &half}}, nil)
+	require.NoError(t, err)
+	var status *models.FeatureFlagStatus
+	for _, s := range list {
+		if s.Name == models.FlagCookieAuth {
+			status = s
+		}
+	}
+	require.NotNil(t, status)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, 50, status.RolloutPercent)
+	assert.Equal(t, models.FlagSourceDatabase, status.Source)
+
+	// 一半左右的用户命中灰度，全局 flags.Enabled 使用同一个服务
+	enabled := 0
+	for i := 0; i < 200; i++ {
+		if flags.Enabled(logger.WithUserID(ctx, uuid.NewString()), models.FlagCookieAuth) {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 100, enabled, 40)
+
+	// 只修改 enabled 时保留已保存的灰度百分比；重新加载后状态不变
+	_, err = svc.Update(ctx, map[string]models.FeatureFlagChange{models.FlagCookieAuth: {Enabled: &off}}, nil)
+	require.NoError(t, err)
+	other := flags.NewService(repository.NewFeatureFlagRepository())
+	require.NoError(t, other.Init(ctx))
+	assert.False(t, other.Enabled(logger.WithUserID(ctx, uuid.NewString()), models.FlagCookieAuth))
+	for _, s := range other.List() {
+		if s.Name == models.FlagCookieAuth {
+			assert.Equal(t, 50, s.RolloutPercent)
+		}
+	}
+
+	_, err = svc.Update(ctx, map[string]models.FeatureFlagChange{"no_such_flag": {Enabled: &on}}, nil)
+	assert.True(t, errors.Is(err, flags.ErrUnknownFlag))
+	tooMuch := 101
+	_, err = svc.Update(ctx, map[string]models.FeatureFlagChange{models.FlagCookieAuth: {RolloutPercent: &tooMuch}}, nil)
+	assert.True(t, errors.Is(err, flags.ErrInvalidFlagValue))
+}
@@ -0,0 +1,224 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/graph"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL_ArticlesWithNestedFieldsAndVisibility(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	commentRepo := repository.NewCommentRepository()
+	server := graph.NewServer(
+		services.NewArticleService(articleRepo, categoryRepo, tagRepo),
+		services.NewCategoryService(categoryRepo, articleRepo),
+		services.NewTagService(tagRepo, articleRepo),
+		commentRepo, repository.NewUserRepository(),
+		config.GraphQLConfig{MaxDepth: 10, MaxComplexity: 1000},
+	)
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+
+	authorToken := registerAndLogin(t, "graphql_author")
+	author := profileID(t, authorToken)
+	reader := profileID(t, registerAndLogin(t, "graphql_reader"))
+
+	// 作者只能创建草稿，通过仓库直接发布其中一篇
+	createArticle := func(title string, publish bool) models.Article {
+		data, _ := json.Marshal(models.ArticleCreate{Title: title, Content: "content", Excerpt: "excerpt"})
+		req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+authorToken)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data models.Article `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		if publish {
+			article, err := articleRepo.GetByID(ctx, resp.Data.ID)
+			require.NoError(t, err)
+			article.Status = models.StatusPublished
+			require.NoError(t, articleRepo.Update(ctx, article))
+		}
+		return resp.Data
+	}
+	published := createArticle("GraphQL published", true)
+	draft := createArticle("GraphQL draft", false)
+
+	// 两条通过审核的评论（其中一条为登录用户），一条待审核评论
+	approved := models.CommentStatusApproved
+	for _, userID := range []*uuid.UUID{&reader, nil} {
+		req := &models.CommentCreate{ArticleID: published.ID, Content: "approved", Author: "guest", Email: "guest@example.com"}
+		comment, err := commentService.Create(ctx, userID, "192.0.2.1", req)
+		require.NoError(t, err)
+		_, err = commentService.Update(ctx, comment.ID, &models.CommentUpdate{Status: &approved})
+		require.NoError(t, err)
+	}
+	pending, err := commentService.Create(ctx, nil, "192.0.2.1", &models.CommentCreate{ArticleID: published.ID, Content: "pending", Author: "guest", Email: "guest@example.com"})
+	require.NoError(t, err)
+	pendingStatus := models.CommentStatusPending
+	_, err = commentService.Update(ctx, pending.ID, &models.CommentUpdate{Status: &pendingStatus})
+	require.NoError(t, err)
+
+	type commentResult struct {
+		Content string `json:"content"`
+		User    *struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	type articleResult struct {
+		Title  string `json:"title"`
+		Status string `json:"status"`
+		Author struct {
+			ID string `json:"id"`
+		} `json:"author"`
+		Comments []commentResult `json:"comments"`
+	}
+	exec := func(viewer graph.Viewer, query string, variables map[string]interface{}, out interface{}) {
+		t.Helper()
+		resp := server.Exec(graph.WithViewer(ctx, viewer), &graph.Request{Query: query, Variables: variables})
+		require.Empty(t, resp.Errors)
+		require.NoError(t, json.Unmarshal(resp.Data, out))
+	}
+
+	listQuery := `query($author: ID, $status: String) {
+		articles(authorId: $author, status: $status, pageSize: 20) {
+			total
+			items { title status author { id } comments(first: 5) { content user { username } } }
+		}
+	}`
+	vars := map[string]interface{}{"author": author.String(), "status": "draft"}
+
+	// 匿名请求忽略 status，只能看到已发布文章；评论只返回已通过审核的，登录用户的评论带用户信息
+	var anonymous struct {
+		Articles struct {
+			Total int             `json:"total"`
+			Items []articleResult `json:"items"`
+		} `json:"articles"`
+	}
+	exec(graph.Viewer{}, listQuery, vars, &anonymous)
+	require.Equal(t, 1, anonymous.Articles.Total)
+	item := anonymous.Articles.Items[0]
+	assert.Equal(t, "GraphQL published", item.Title)
+	assert.Equal(t, author.String(), item.Author.ID)
+	require.Len(t, item.Comments, 2)
+	var withUser int
+	for _, c := range item.Comments {
+		assert.Equal(t, "approved", c.Content)
+		if c.User != nil {
+			withUser++
+			assert.Contains(t, c.User.Username, "graphql_reader")
+		}
+	}
+	assert.Equal(t, 1, withUser)
+
+	// 作者查询自己的文章时可以按状态筛选
+	var own struct {
+		Articles struct {
+			Items []articleResult `json:"items"`
+		} `json:"articles"`
+	}
+	exec(graph.Viewer{UserID: author, Role: models.RoleAuthor}, listQuery, vars, &own)
+	require.Len(t, own.Articles.Items, 1)
+	assert.Equal(t, "GraphQL draft", own.Articles.Items[0].Title)
+
+	// 草稿详情只对作者本人和管理员可见
+	slugQuery := `query($slug: String!) { article(slug: $slug) { title } }`
+	var detail struct {
+		Article *articleResult `json:"article"`
+	}
+	exec(graph.Viewer{}, slugQuery, map[string]interface{}{"slug": draft.Slug}, &detail)
+	assert.Nil(t, detail.Article)
+	exec(graph.Viewer{UserID: reader, Role: models.RoleAuthor}, slugQuery, map[string]interface{}{"slug": draft.Slug}, &detail)
+	assert.Nil(t, detail.Article)
+	exec(graph.Viewer{UserID: author, Role: models.RoleAuthor}, slugQuery, map[string]interface{}{"slug": draft.Slug}, &detail)
+	require.NotNil(t, detail.Article)
+	assert.Equal(t, "GraphQL draft", detail.Article.Title)
+	exec(graph.Viewer{}, slugQuery, map[string]interface{}{"slug": "no-such-article"}, &detail)
+	assert.Nil(t, detail.Article)
+
+	// 分类树和标签列表
+	var taxonomy struct {
+		Categories []struct {
+			ID string `json:"id"`
+		} `json:"categories"`
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+	}
+	exec(graph.Viewer{}, `{ categories { id name children { id } } tags { name articleCount } }`, nil, &taxonomy)
+	assert.NotNil(t, taxonomy.Categories)
+	assert.NotNil(t, taxonomy.Tags)
+}
+
+func TestGraphQL_QueryLimits(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	server := graph.NewServer(
+		articleService,
+		services.NewCategoryService(categoryRepo, articleRepo),
+		services.NewTagService(tagRepo, articleRepo),
+		repository.NewCommentRepository(), repository.NewUserRepository(),
+		config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 200},
+	)
+	author := profileID(t, registerAndLogin(t, "graphql_limits"))
+	for i := 0; i < 3; i++ {
+		_, err := articleService.Create(ctx, author, &models.ArticleCreate{
+			Title: "GraphQL limits " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished,
+		})
+		require.NoError(t, err)
+	}
+
+	// 复杂度未超出时正常执行
+	resp := server.Exec(ctx, &graph.Request{Query: `{ articles(pageSize: 3) { items { title comments(first: 5) { content } } } }`})
+	assert.Empty(t, resp.Errors)
+
+	// pageSize / first 在解析前按请求的数量计入（每篇文章的 comments 分别计入）；超出限制时只返回复杂度错误，不返回部分数据
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+	}{
+		{
+			name:  "comments are charged per article",
+			query: `{ articles(pageSize: 3) { items { title comments(first: 100) { content } } } }`,
+		},
+		{
+			name:      "list size from variables",
+			query:     `query($n: Int) { articles(pageSize: 3) { items { comments(first: $n) { id } } } }`,
+			variables: map[string]interface{}{"n": float64(100)},
+		},
+		{
+			name:  "fragments are counted",
+			query: `{ articles(pageSize: 3) { items { ...f } } } fragment f on Article { id comments(first: 100) { id } }`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := server.Exec(ctx, &graph.Request{Query: tc.query, Variables: tc.variables})
+			require.Len(t, resp.Errors, 1)
+			assert.Contains(t, resp.Errors[0].Message, "query complexity")
+			assert.Empty(t, resp.Data)
+		})
+	}
+}
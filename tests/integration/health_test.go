@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecks(t *testing.T) {
+	mr := useMiniRedis(t)
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+	setHealth := func(redisRequired bool) {
+		cfg := *original
+		cfg.Elasticsearch.Enabled = false
+		cfg.Health = config.HealthConfig{TimeoutMs: 200, RedisRequired: redisRequired}
+		config.Set(&cfg)
+	}
+
+	health := handlers.NewHealthHandler()
+	router := gin.New()
+	router.GET("/health/live", health.Live)
+	router.GET("/health", health.Ready)
+	ready := func() (int, models.HealthReport) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+		var report models.HealthReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report), w.Body.String())
+		return w.Code, report
+	}
+	live := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health/live", nil))
+		return w.Code
+	}
+
+	// 全部可用，未启用的 Elasticsearch 报告 disabled
+	setHealth(false)
+	code, report := ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.HealthOK, report.Status)
+	assert.Equal(t, models.HealthOK, report.Checks["database"].Status)
+	assert.Equal(t, models.HealthOK, report.Checks["redis"].Status)
+	assert.Equal(t, models.HealthDisabled, report.Checks["elasticsearch"].Status)
+
+	// Redis 不可用：默认只降级，配置为必需时返回 503
+	mr.Close()
+	code, report = ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.HealthDegraded, report.Status)
+	assert.Equal(t, models.HealthDegraded, report.Checks["redis"].Status)
+	assert.NotEmpty(t, report.Checks["redis"].Error)
+
+	setHealth(true)
+	code, report = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, models.HealthUnavailable, report.Status)
+	assert.Equal(t, models.HealthDown, report.Checks["redis"].Status)
+	assert.Equal(t, http.StatusOK, live())
+
+	// 数据库不可用时始终返回 503
+	setHealth(false)
+	db := database.DB
+	database.DB = nil
+	t.Cleanup(func() { database.DB = db })
+	code, report = ready()
+	database.DB = db
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, models.HealthDown, report.Checks["database"].Status)
+	assert.Equal(t, http.StatusOK, live())
+}
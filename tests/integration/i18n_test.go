@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestI18n_LocalizedErrorResponses(t *testing.T) {
+	send := func(method, path, body, acceptLanguage string, headers ...string) (int, models.Response) {
+		t.Helper()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp models.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp
+	}
+
+	// 同一错误按 Accept-Language 返回不同语言的消息，error_code 保持不变；未指定时使用默认语言（zh-CN）
+	login := `{"email": "nobody@example.com", "password": "wrong-password"}`
+	code, resp := send("POST", "/api/v1/auth/login", login, "en-US,en;q=0.9")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, i18n.CodeInvalidCredentials, resp.ErrorCode)
+	assert.Equal(t, "Invalid email or password", resp.Message)
+
+	code, resp = send("POST", "/api/v1/auth/login", login, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, i18n.CodeInvalidCredentials, resp.ErrorCode)
+	assert.Equal(t, "邮箱或密码错误", resp.Message)
+
+	// 参数校验错误逐字段翻译，字段名与请求体一致
+	register := `{"username": "i18n_user", "email": "not-an-email", "password": "password123"}`
+	code, resp = send("POST", "/api/v1/auth/register", register, "en")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeValidationFailed, resp.ErrorCode)
+	assert.Equal(t, "email must be a valid email address", resp.Message)
+
+	// data.errors 为逐字段的错误，字段名与请求体一致
+	fieldErrs, ok := resp.Data.(map[string]interface{})["errors"].([]interface{})
+	require.True(t, ok, resp.Data)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, map[string]interface{}{"field": "email", "rule": "email", "message": "email must be a valid email address"}, fieldErrs[0])
+
+	// 字段类型错误同样返回结构化的字段错误
+	code, resp = send("POST", "/api/v1/auth/login", `{"email": 42, "password": "x"}`, "en")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeValidationFailed, resp.ErrorCode)
+	assert.Equal(t, "email has an invalid type", resp.Message)
+
+	code, resp = send("POST", "/api/v1/auth/register", register, "zh-CN")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeValidationFailed, resp.ErrorCode)
+	assert.Contains(t, resp.Message, "email必须是一个有效的邮箱")
+
+	// 请求体不是合法 JSON
+	code, resp = send("POST", "/api/v1/auth/login", "{", "en")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeInvalidRequest, resp.ErrorCode)
+	assert.Equal(t, "Invalid request", resp.Message)
+
+	code, resp = send("GET", "/api/v1/articles/not-a-uuid/comments", "", "en")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeInvalidArticleID, resp.ErrorCode)
+	assert.Equal(t, "Invalid article ID", resp.Message)
+
+	// 认证中间件的 401 同样按请求语言返回
+	code, resp = send("GET", "/api/v1/users/profile", "", "en")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, i18n.CodeUnauthorized, resp.ErrorCode)
+	assert.Equal(t, "Authentication required", resp.Message)
+
+	code, resp = send("GET", "/api/v1/users/profile", "", "zh-CN", "Authorization", "Bearer not-a-token")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, i18n.CodeInvalidToken, resp.ErrorCode)
+	assert.Equal(t, "登录凭证无效或已过期，请重新登录", resp.Message)
+
+	// 普通作者不能通过 autocomplete 创建标签
+	token := registerAndLogin(t, "i18n_tags")
+	code, resp = send("GET", "/api/v1/tags/autocomplete?create_if_missing=true&q=i18n", "", "en", "Authorization", "Bearer "+token)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, i18n.CodeForbidden, resp.ErrorCode)
+	assert.Equal(t, i18n.T(i18n.EN, i18n.CodeForbidden), resp.Message)
+}
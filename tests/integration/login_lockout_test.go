@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLockout(t *testing.T) {
+	useMiniRedis(t)
+	userRepo := repository.NewUserRepository()
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), testJWT)
+	userService.SetLoginGuard(services.NewLoginGuard(3, 5, 15*time.Minute))
+	userHandler := handlers.NewUserHandler(userService, services.NewSMSService(repository.NewSMSRepository(), userRepo), testJWT)
+	router := gin.New()
+	router.POST("/auth/register", userHandler.Register)
+	router.POST("/auth/login", userHandler.Login)
+
+	type loginResult struct {
+		code       int
+		errorCode  string
+		retryAfter string
+		data       map[string]interface{}
+	}
+	post := func(path, ip string, body interface{}) loginResult {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp struct {
+			ErrorCode string                 `json:"error_code"`
+			Data      map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return loginResult{w.Code, resp.ErrorCode, w.Header().Get("Retry-After"), resp.Data}
+	}
+
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("lockout_%d@example.com", suffix)
+	res := post("/auth/register", "10.1.0.1", models.UserCreate{Username: fmt.Sprintf("lockout_%d", suffix), Email: email, Password: "password123"})
+	require.Equal(t, http.StatusCreated, res.code)
+
+	// 登录成功清除该邮箱之前的失败记录
+	for i := 0; i < 2; i++ {
+		res = post("/auth/login", "10.1.0.1", models.UserLogin{Email: email, Password: "wrong-password"})
+		assert.Equal(t, http.StatusUnauthorized, res.code)
+		assert.Equal(t, i18n.CodeInvalidCredentials, res.errorCode)
+	}
+	res = post("/auth/login", "10.1.0.2", models.UserLogin{Email: email, Password: "password123"})
+	require.Equal(t, http.StatusOK, res.code)
+
+	// 已注册和未注册的邮箱达到上限后的响应相同，锁定期间正确的密码同样被拒绝
+	unknown := fmt.Sprintf("lockout_nobody_%d@example.com", suffix)
+	for i, target := range []string{email, unknown} {
+		ip := fmt.Sprintf("10.1.1.%d", i)
+		for j := 0; j < 3; j++ {
+			res = post("/auth/login", ip, models.UserLogin{Email: target, Password: "wrong-password"})
+			assert.Equal(t, http.StatusUnauthorized, res.code)
+		}
+	}
+	for _, target := range []string{email, unknown} {
+		res = post("/auth/login", "10.1.0.4", models.UserLogin{Email: target, Password: "password123"})
+		assert.Equal(t, http.StatusTooManyRequests, res.code, target)
+		assert.Equal(t, i18n.CodeLoginLocked, res.errorCode)
+		assert.NotEmpty(t, res.retryAfter)
+		assert.Equal(t, res.retryAfter, fmt.Sprint(res.data["retry_after"]))
+	}
+
+	// 同一 IP 用不同邮箱累计失败 5 次后，该 IP 的其他邮箱登录也被拒绝，其他 IP 不受影响
+	for i := 0; i < 5; i++ {
+		res = post("/auth/login", "10.1.2.1", models.UserLogin{Email: fmt.Sprintf("lockout_spray_%d_%d@example.com", i, suffix), Password: "wrong-password"})
+		assert.Equal(t, http.StatusUnauthorized, res.code)
+	}
+	other := fmt.Sprintf("lockout_other_%d@example.com", suffix)
+	res = post("/auth/login", "10.1.2.1", models.UserLogin{Email: other, Password: "wrong-password"})
+	assert.Equal(t, http.StatusTooManyRequests, res.code)
+	res = post("/auth/login", "10.1.2.2", models.UserLogin{Email: other, Password: "wrong-password"})
+	assert.Equal(t, http.StatusUnauthorized, res.code)
+}
@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	useMiniRedis(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// 另一个实例通过 Redis 通知刷新设置
+	other := services.NewSettingsService(repository.NewSettingRepository())
+	require.NoError(t, other.Init(ctx))
+	go other.Subscribe(ctx)
+	settings := services.NewSettingsService(repository.NewSettingRepository())
+	require.NoError(t, settings.Init(ctx))
+	t.Cleanup(func() {
+		_, _ = settings.Update(context.Background(), map[string]interface{}{
+			models.SettingMaintenanceMode:    false,
+			models.SettingMaintenanceMessage: "",
+		}, nil)
+	})
+
+	settingsHandler := handlers.NewSettingsHandler(settings)
+	userRepo := repository.NewUserRepository()
+	userService := services.NewUserService(userRepo, repository.NewRefreshTokenRepository(), testJWT)
+	userHandler := handlers.NewUserHandler(userService, services.NewSMSService(repository.NewSMSRepository(), userRepo), testJWT)
+	router := gin.New()
+	router.Use(middleware.MaintenanceMiddleware(testJWT, "/health", "/api/v1/admin/system/maintenance", "/api/v1/auth/login", "/api/v1/auth/refresh"))
+	router.GET("/health", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "ok"}) })
+	router.POST("/api/v1/auth/login", userHandler.Login)
+	router.POST("/api/v1/auth/refresh", userHandler.Refresh)
+	router.GET("/api/v1/articles", func(c *gin.Context) { c.JSON(http.StatusOK, models.Success(nil)) })
+	admin := router.Group("/api/v1/admin", middleware.AuthMiddleware(testJWT), middleware.RoleMiddleware("admin"))
+	admin.POST("/system/maintenance", settingsHandler.SetMaintenance)
+
+	adminToken, err := testJWT.GenerateToken(uuid.New(), "maintenance_admin", string(models.RoleAdmin))
+	require.NoError(t, err)
+	userToken, err := testJWT.GenerateToken(uuid.New(), "maintenance_user", string(models.RoleReader))
+	require.NoError(t, err)
+	do := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			data, _ := json.Marshal(body)
+			buf.Write(data)
+		}
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/articles", "", nil).Code)
+
+	// 只有管理员可以开关
+	enabled, message, retryAfter := true, "database upgrade in progress", 120
+	update := models.MaintenanceUpdate{Enabled: &enabled, Message: &message, RetryAfter: &retryAfter}
+	assert.Equal(t, http.StatusForbidden, do("POST", "/api/v1/admin/system/maintenance", userToken, update).Code)
+	w := do("POST", "/api/v1/admin/system/maintenance", adminToken, update)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data models.MaintenanceStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.MaintenanceStatus{Enabled: true, Message: message, RetryAfter: 120}, resp.Data)
+	assert.Equal(t, resp.Data, services.CollectSystemStatus().Maintenance)
+	assert.Eventually(t, func() bool {
+		return other.Get(models.SettingMaintenanceMode) == "true"
+	}, 3*time.Second, 20*time.Millisecond)
+
+	// 维护期间：普通请求 503，豁免路径和管理员照常
+	w = do("GET", "/api/v1/articles", userToken, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "120", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), message)
+	assert.Equal(t, http.StatusServiceUnavailable, do("GET", "/api/v1/articles", "", nil).Code)
+	assert.Equal(t, http.StatusOK, do("GET", "/health", "", nil).Code)
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/articles", adminToken, nil).Code)
+
+	// token 过期的管理员可以重新登录和刷新 token，再关闭维护模式
+	suffix := time.Now().UnixNano()
+	adminUser, err := userService.Register(ctx, &models.UserCreate{
+		Username: fmt.Sprintf("maint_admin_%d", suffix), Email: fmt.Sprintf("maint_admin_%d@example.com", suffix), Password: "password123",
+	})
+	require.NoError(t, err)
+	adminUser.Role = models.RoleAdmin
+	require.NoError(t, userRepo.Update(ctx, adminUser))
+	w = do("POST", "/api/v1/auth/login", "", models.UserLogin{Email: adminUser.Email, Password: "password123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var login struct {
+		Data models.AuthTokens `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/articles", login.Data.Token, nil).Code)
+	w = do("POST", "/api/v1/auth/refresh", "", models.RefreshTokenRequest{RefreshToken: login.Data.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/articles", login.Data.Token, nil).Code)
+	adminToken = login.Data.Token
+
+	// 取值不合法时整体不生效
+	invalid := -1
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/api/v1/admin/system/maintenance", adminToken, models.MaintenanceUpdate{Enabled: &enabled, RetryAfter: &invalid}).Code)
+	assert.Equal(t, 120, services.CurrentMaintenance().RetryAfter)
+
+	// 关闭后恢复，未提供的字段保持不变
+	disabled := false
+	w = do("POST", "/api/v1/admin/system/maintenance", adminToken, models.MaintenanceUpdate{Enabled: &disabled})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, models.MaintenanceStatus{Enabled: false, Message: message, RetryAfter: 120}, services.CurrentMaintenance())
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/articles", userToken, nil).Code)
+}
@@ -0,0 +1,189 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmailSender 记录发送的邮件；群发邮件（带 List-Unsubscribe 头）发往 bounce 开头的地址时返回 SMTP 550
+type recordingEmailSender struct {
+	mu       sync.Mutex
+	messages []*services.EmailMessage
+}
+
+func (s *recordingEmailSender) Send(ctx context.Context, msg *services.EmailMessage) error {
+	if msg.Headers["List-Unsubscribe"] != "" && strings.HasPrefix(msg.To[0], "bounce") {
+		return &textproto.Error{Code: 550, Msg: "mailbox unavailable"}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// sentTo 返回发往 email 的邮件
+func (s *recordingEmailSender) sentTo(email string) []*services.EmailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*services.EmailMessage
+	for _, msg := range s.messages {
+		if msg.To[0] == email {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+var confirmTokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+func TestNewsletterService_SubscribeConfirmSendUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	sender := &recordingEmailSender{}
+	newsletterRepo := repository.NewNewsletterRepository()
+	svc := services.NewNewsletterService(newsletterRepo, repository.NewArticleRepository(), sender,
+		config.NewsletterConfig{BatchSize: 2, SubscribeLimit: 5, ConfirmTTLHours: 1},
+		config.ServerConfig{PublicURL: "https://api.example.com", FrontendURL: "https://blog.example.com"},
+		"newsletter-test-key",
+	)
+	wait := func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, svc.Wait(waitCtx))
+	}
+
+	suffix := time.Now().UnixNano()
+	emails := []string{
+		fmt.Sprintf("reader1_%d@example.com", suffix),
+		fmt.Sprintf("reader2_%d@example.com", suffix),
+		fmt.Sprintf("bounce_%d@example.com", suffix),
+	}
+
+	// 订阅后收到确认邮件，邮箱按小写保存
+	for _, email := range emails {
+		require.NoError(t, svc.Subscribe(ctx, strings.ToUpper(email), "192.0.2.1"))
+	}
+	wait()
+
+	assert.ErrorIs(t, svc.Confirm(ctx, "not-a-token"), services.ErrInvalidConfirmToken)
+	for _, email := range emails {
+		mails := sender.sentTo(email)
+		require.Len(t, mails, 1, email)
+		match := confirmTokenPattern.FindStringSubmatch(mails[0].HTML)
+		require.NotNil(t, match)
+		require.NoError(t, svc.Confirm(ctx, match[1]))
+		// 令牌只能使用一次
+		assert.ErrorIs(t, svc.Confirm(ctx, match[1]), services.ErrInvalidConfirmToken)
+	}
+
+	// 已确认的邮箱再次订阅不会收到确认邮件
+	require.NoError(t, svc.Subscribe(ctx, emails[0], "192.0.2.1"))
+	wait()
+	assert.Len(t, sender.sentTo(emails[0]), 1)
+
+	// 只能群发已发布的文章（作者只能创建草稿，通过仓库直接发布）
+	token := registerAndLogin(t, "newsletter")
+	articleRepo := repository.NewArticleRepository()
+	createArticle := func(title string, publish bool) uuid.UUID {
+		data, _ := json.Marshal(models.ArticleCreate{Title: title, Content: "content", Excerpt: "excerpt"})
+		req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data models.Article `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		if publish {
+			article, err := articleRepo.GetByID(ctx, resp.Data.ID)
+			require.NoError(t, err)
+			article.Status = models.StatusPublished
+			require.NoError(t, articleRepo.Update(ctx, article))
+		}
+		return resp.Data.ID
+	}
+	published := createArticle("Newsletter published", true)
+	draft := createArticle("Newsletter draft", false)
+
+	_, err := svc.CreateCampaign(ctx, &models.CampaignCreate{Kind: models.CampaignKindArticle, ArticleID: &draft}, nil)
+	assert.ErrorIs(t, err, services.ErrInvalidCampaign)
+	_, err = svc.CreateCampaign(ctx, &models.CampaignCreate{Kind: "weekly"}, nil)
+	assert.ErrorIs(t, err, services.ErrInvalidCampaign)
+
+	campaign, err := svc.CreateCampaign(ctx, &models.CampaignCreate{Kind: models.CampaignKindArticle, ArticleID: &published}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.CampaignSending, campaign.Status)
+	assert.Equal(t, "Newsletter published", campaign.Subject)
+	wait()
+
+	campaign, err = svc.GetCampaign(ctx, campaign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CampaignSent, campaign.Status)
+	assert.NotNil(t, campaign.FinishedAt)
+	assert.GreaterOrEqual(t, campaign.SentCount, 2)
+	assert.GreaterOrEqual(t, campaign.BouncedCount, 1)
+	assert.Equal(t, campaign.Recipients, campaign.SentCount+campaign.BouncedCount+campaign.FailedCount)
+
+	// 永久性错误的地址标记为退信
+	bounced, err := newsletterRepo.GetSubscriberByEmail(ctx, emails[2])
+	require.NoError(t, err)
+	assert.Equal(t, models.SubscriberBounced, bounced.Status)
+
+	// 每封群发邮件带该订阅者的退订链接
+	mails := sender.sentTo(emails[0])
+	require.Len(t, mails, 2)
+	mail := mails[1]
+	assert.Equal(t, "List-Unsubscribe=One-Click", mail.Headers["List-Unsubscribe-Post"])
+	assert.Contains(t, mail.HTML, "https://blog.example.com/articles/"+published.String())
+	unsubscribeURL := strings.Trim(mail.Headers["List-Unsubscribe"], "<>")
+	assert.Contains(t, mail.HTML, strings.ReplaceAll(unsubscribeURL, "&", "&amp;"))
+
+	u, err := url.Parse(unsubscribeURL)
+	require.NoError(t, err)
+	id, err := uuid.Parse(u.Query().Get("id"))
+	require.NoError(t, err)
+	sig := u.Query().Get("sig")
+
+	// 篡改签名或 ID 均无法退订
+	assert.ErrorIs(t, svc.Unsubscribe(ctx, id, strings.Repeat("0", len(sig))), services.ErrInvalidUnsubscribeLink)
+	assert.ErrorIs(t, svc.Unsubscribe(ctx, uuid.New(), sig), services.ErrInvalidUnsubscribeLink)
+	require.NoError(t, svc.Unsubscribe(ctx, id, sig))
+	require.NoError(t, svc.Unsubscribe(ctx, id, sig))
+
+	subscriber, err := newsletterRepo.GetSubscriberByEmail(ctx, emails[0])
+	require.NoError(t, err)
+	assert.Equal(t, models.SubscriberUnsubscribed, subscriber.Status)
+	assert.NotNil(t, subscriber.UnsubscribedAt)
+
+	// 摘要只发给仍然订阅的地址
+	digest, err := svc.CreateCampaign(ctx, &models.CampaignCreate{Kind: models.CampaignKindDigest, DigestDays: 1}, nil)
+	require.NoError(t, err)
+	wait()
+	assert.Len(t, sender.sentTo(emails[0]), 2)
+	mails = sender.sentTo(emails[1])
+	require.Len(t, mails, 3)
+	assert.Equal(t, digest.Subject, mails[2].Subject)
+	assert.Contains(t, mails[2].HTML, "Newsletter published")
+	assert.NotContains(t, mails[2].HTML, "Newsletter draft")
+}
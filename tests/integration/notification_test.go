@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profileID 返回 token 对应用户的 ID
+func profileID(t *testing.T, token string) uuid.UUID {
+	t.Helper()
+	req, _ := http.NewRequest("GET", "/api/v1/users/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data models.User `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data.ID
+}
+
+func TestNotificationService_CommentAndReviewNotifications(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	commentRepo := repository.NewCommentRepository()
+	svc := services.NewNotificationService(repository.NewNotificationRepository(), articleRepo, commentRepo)
+	commentService := services.NewCommentService(commentRepo, articleRepo)
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	wait := func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, svc.Wait(waitCtx))
+	}
+
+	authorToken := registerAndLogin(t, "notify_author")
+	author := profileID(t, authorToken)
+	reader := profileID(t, registerAndLogin(t, "notify_reader"))
+	admin := profileID(t, registerAndLogin(t, "notify_admin"))
+
+	data, _ := json.Marshal(models.ArticleCreate{Title: "Notification article", Content: "content", Excerpt: "excerpt"})
+	req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+authorToken)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	articleID := created.Data.ID
+
+	approve := func(userID uuid.UUID, parentID *uuid.UUID, content string) *models.Comment {
+		comment, err := commentService.Create(ctx, &userID, "192.0.2.1", &models.CommentCreate{
+			ArticleID: articleID, ParentID: parentID, Content: content, Author: "commenter", Email: "c@example.com",
+		})
+		require.NoError(t, err)
+		status := models.CommentStatusApproved
+		comment, err = commentService.Update(ctx, comment.ID, &models.CommentUpdate{Status: &status})
+		require.NoError(t, err)
+		return comment
+	}
+
+	// 读者评论：通知作者；作者回复读者：只通知读者；作者评论自己的文章：不通知
+	readerComment := approve(reader, nil, "Great article")
+	approve(author, &readerComment.ID, "Thanks")
+	approve(author, nil, "Author note")
+	wait()
+
+	authorNotifications, total, err := svc.List(ctx, author, false, 1, 20)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	assert.Equal(t, models.NotificationCommentOnArticle, authorNotifications[0].Type)
+	assert.Equal(t, "Great article", authorNotifications[0].Summary)
+	assert.Equal(t, &reader, authorNotifications[0].ActorID)
+
+	readerNotifications, _, err := svc.List(ctx, reader, true, 1, 20)
+	require.NoError(t, err)
+	require.Len(t, readerNotifications, 1)
+	assert.Equal(t, models.NotificationCommentReply, readerNotifications[0].Type)
+	assert.Equal(t, &articleID, readerNotifications[0].ArticleID)
+
+	// 管理员发布待审核文章：通知作者审核通过
+	article, err := articleRepo.GetByID(ctx, articleID)
+	require.NoError(t, err)
+	article.Status = models.StatusReview
+	require.NoError(t, articleRepo.Update(ctx, article))
+	_, err = articleService.UpdateStatus(logger.WithUserID(ctx, admin.String()), articleID, models.StatusPublished, nil, "")
+	require.NoError(t, err)
+	wait()
+
+	count, err := svc.UnreadCount(ctx, author)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	authorNotifications, _, err = svc.List(ctx, author, true, 1, 20)
+	require.NoError(t, err)
+	require.Len(t, authorNotifications, 2)
+	assert.Equal(t, models.NotificationArticleApproved, authorNotifications[0].Type)
+	assert.Equal(t, &admin, authorNotifications[0].ActorID)
+
+	// 标记已读：只能标记自己的通知，重复标记视为成功
+	assert.ErrorIs(t, svc.MarkRead(ctx, reader, authorNotifications[0].ID), services.ErrNotificationNotFound)
+	require.NoError(t, svc.MarkRead(ctx, author, authorNotifications[0].ID))
+	require.NoError(t, svc.MarkRead(ctx, author, authorNotifications[0].ID))
+	count, err = svc.UnreadCount(ctx, author)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	marked, err := svc.MarkAllRead(ctx, author)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, marked)
+	count, err = svc.UnreadCount(ctx, author)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	_, total, err = svc.List(ctx, author, false, 1, 20)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+}
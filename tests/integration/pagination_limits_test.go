@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginationLimits_PerResource(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	commentService := services.NewCommentService(repository.NewCommentRepository(), articleRepo)
+	imageRepo := repository.NewImageRepository()
+
+	author := profileID(t, registerAndLogin(t, "page_limits"))
+	for i := 0; i < 4; i++ {
+		registerAndLogin(t, "page_limits_extra")
+	}
+	keyword := "pagelimits" + uuid.NewString()[:8]
+	var article *models.Article
+	for i := 0; i < 4; i++ {
+		var err error
+		article, err = articleService.Create(ctx, author, &models.ArticleCreate{Title: "Limits " + keyword, Content: "content", Status: models.StatusPublished})
+		require.NoError(t, err)
+	}
+	for i := 0; i < 4; i++ {
+		_, err := commentService.Create(ctx, &author, "192.0.2.1", &models.CommentCreate{ArticleID: article.ID, Content: "hi"})
+		require.NoError(t, err)
+		require.NoError(t, imageRepo.Create(ctx, &models.Image{
+			Filename: uuid.NewString() + ".png", OriginalName: "a.png", Path: "a.png", URL: "/a.png",
+			MimeType: "image/png", UploaderID: author, Description: keyword,
+		}))
+	}
+
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+	cfg := *original
+	cfg.Pagination = config.PaginationConfig{
+		Articles: config.PageSizeLimits{Default: 2, Max: 3},
+		Comments: config.PageSizeLimits{Default: 1, Max: 2},
+		Images:   config.PageSizeLimits{Default: 2, Max: 3},
+		Users:    config.PageSizeLimits{Default: 1, Max: 2},
+		Other:    config.PageSizeLimits{Default: 20, Max: 100},
+	}
+	config.Set(&cfg)
+
+	imageHandler := handlers.NewImageHandler(services.NewImageService(imageRepo, t.TempDir()))
+	router := gin.New()
+	router.GET("/images", imageHandler.List)
+	userService := services.NewUserService(repository.NewUserRepository(), repository.NewRefreshTokenRepository(), testJWT)
+	router.GET("/users", handlers.NewUserHandler(userService, nil, testJWT).ListUsers)
+
+	list := func(r http.Handler, path string) (int, int) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []json.RawMessage     `json:"data"`
+			Meta models.PaginationMeta `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Meta.PageSize, len(resp.Data)
+	}
+
+	cases := []struct {
+		name         string
+		router       http.Handler
+		path         string
+		wantPageSize int
+	}{
+		{"articles default", testRouter, "/api/v1/articles", 2},
+		{"articles capped", testRouter, "/api/v1/articles?page_size=50", 3},
+		{"article search capped", testRouter, "/api/v1/articles?search=" + keyword + "&page_size=50", 3},
+		{"comments default", testRouter, "/api/v1/articles/" + article.ID.String() + "/comments", 1},
+		{"comments capped", testRouter, "/api/v1/articles/" + article.ID.String() + "/comments?page_size=50", 2},
+		{"images default", router, "/images", 2},
+		{"images capped", router, "/images?page_size=50", 3},
+		{"users default", router, "/users", 1},
+		{"users capped", router, "/users?page_size=50", 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pageSize, n := list(tc.router, tc.path)
+			assert.Equal(t, tc.wantPageSize, pageSize)
+			assert.Equal(t, tc.wantPageSize, n)
+		})
+	}
+
+	// 绕过 handler 直接调用 repository 时上限相同
+	articles, _, err := articleRepo.SearchFullText(ctx, models.ArticleQuery{Search: keyword, Status: models.StatusPublished, PageSize: 50})
+	require.NoError(t, err)
+	assert.Len(t, articles, 3)
+	images, _, err := imageRepo.List(ctx, models.ImageQuery{PageSize: 50})
+	require.NoError(t, err)
+	assert.Len(t, images, 3)
+}
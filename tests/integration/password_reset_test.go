@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetFlow(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	sender := &recordingEmailSender{}
+	userRepo := repository.NewUserRepository()
+	refreshRepo := repository.NewRefreshTokenRepository()
+	resetService := services.NewPasswordResetService(repository.NewPasswordResetRepository(), userRepo, refreshRepo, sender,
+		config.ServerConfig{FrontendURL: "https://blog.example.com"})
+	userService := services.NewUserService(userRepo, refreshRepo, testJWT)
+	resetHandler := handlers.NewPasswordResetHandler(resetService)
+	router := gin.New()
+	router.POST("/auth/forgot-password", resetHandler.Forgot)
+	router.POST("/auth/reset-password", resetHandler.Reset)
+
+	post := func(path string, body interface{}) (int, string, string) {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp struct {
+			ErrorCode string `json:"error_code"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp.ErrorCode, w.Body.String()
+	}
+	// forgot 申请找回密码并等待后台发送完成，返回最近一封邮件中的令牌（没有新邮件时为空）
+	forgot := func(email string) (string, string) {
+		t.Helper()
+		before := len(sender.sentTo(email))
+		code, _, body := post("/auth/forgot-password", models.ForgotPasswordRequest{Email: email})
+		require.Equal(t, http.StatusOK, code)
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, resetService.Wait(waitCtx))
+		sent := sender.sentTo(email)
+		if len(sent) == before {
+			return "", body
+		}
+		msg := sent[len(sent)-1]
+		assert.Contains(t, msg.HTML, "https://blog.example.com/reset-password?token=")
+		match := confirmTokenPattern.FindStringSubmatch(msg.HTML)
+		require.Len(t, match, 2)
+		return match[1], body
+	}
+
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("reset_%d@example.com", suffix)
+	user := &models.User{Username: fmt.Sprintf("reset_%d", suffix), Email: email, Password: "old-password"}
+	require.NoError(t, user.HashPassword())
+	require.NoError(t, userRepo.Create(ctx, user))
+	tokens, _, err := userService.Login(ctx, &models.UserLogin{Email: email, Password: "old-password"}, "")
+	require.NoError(t, err)
+
+	// 不存在的邮箱与存在的邮箱响应相同
+	first, knownBody := forgot(email)
+	require.NotEmpty(t, first)
+	unknown := fmt.Sprintf("nobody_%d@example.com", suffix)
+	none, unknownBody := forgot(unknown)
+	assert.Empty(t, none)
+	assert.Equal(t, knownBody, unknownBody)
+
+	// 同一邮箱每小时最多 3 封，超过后响应不变但不再发送
+	second, _ := forgot(email)
+	third, _ := forgot(email)
+	require.NotEmpty(t, second)
+	require.NotEmpty(t, third)
+	fourth, limitedBody := forgot(email)
+	assert.Empty(t, fourth)
+	assert.Equal(t, knownBody, limitedBody)
+
+	require.NoError(t, database.DB.Exec(`UPDATE password_resets SET expires_at = ? WHERE token_hash = (
+		SELECT token_hash FROM password_resets WHERE user_id = ? ORDER BY created_at LIMIT 1 OFFSET 1)`,
+		time.Now().Add(-time.Minute), user.ID).Error)
+
+	reset := func(token string) (int, string) {
+		t.Helper()
+		code, errorCode, _ := post("/auth/reset-password", models.ResetPasswordRequest{Token: token, NewPassword: "new-password"})
+		return code, errorCode
+	}
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"expired token", second},
+		{"tampered token", third[:len(third)-1] + "x"},
+		{"unknown token", "deadbeef"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, errorCode := reset(tc.token)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Equal(t, i18n.CodeInvalidResetToken, errorCode)
+		})
+	}
+	_, _, err = userService.Login(ctx, &models.UserLogin{Email: email, Password: "old-password"}, "")
+	require.NoError(t, err, "failed reset attempts must not change the password")
+
+	// 新密码同样需要通过校验
+	code, errorCode, _ := post("/auth/reset-password", models.ResetPasswordRequest{Token: first, NewPassword: "123"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeValidationFailed, errorCode)
+
+	// 重置成功：新密码生效，旧刷新 token 被吊销
+	code, _ = reset(first)
+	require.Equal(t, http.StatusOK, code)
+	_, _, err = userService.Login(ctx, &models.UserLogin{Email: email, Password: "old-password"}, "")
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+	_, _, err = userService.Login(ctx, &models.UserLogin{Email: email, Password: "new-password"}, "")
+	assert.NoError(t, err)
+	_, err = userService.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+
+	// 令牌只能使用一次，其他未使用的令牌随之作废
+	code, errorCode = reset(first)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, i18n.CodeInvalidResetToken, errorCode)
+	code, _ = reset(third)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleReadingStats(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "reading_stats"))
+	suffix := uuid.NewString()[:8]
+
+	// 创建时计算，详情和列表都返回
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Reading stats " + suffix, Content: strings.Repeat("word ", 300) + strings.Repeat("字", 100), Status: models.StatusPublished,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 400, article.WordCount)
+	assert.Equal(t, 2, article.ReadingTimeMinutes)
+
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/slug/"+article.Slug, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"word_count":400`)
+	assert.Contains(t, w.Body.String(), `"reading_time_minutes":2`)
+	listed, _, err := articleRepo.List(ctx, models.ArticleQuery{AuthorID: &author})
+	require.NoError(t, err)
+	require.NotEmpty(t, listed)
+	assert.Equal(t, 400, listed[0].WordCount)
+
+	// 修改正文后重新计算
+	content := "短文"
+	updated, err := articleService.Update(ctx, article.ID, &models.ArticleUpdate{Content: &content, ExpectedVersion: &article.Version})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.WordCount)
+	assert.Equal(t, 1, updated.ReadingTimeMinutes)
+
+	// 回填：只更新数值不对的文章，不改变版本号
+	require.NoError(t, database.DB.Exec(`UPDATE articles SET word_count = 0, reading_time_minutes = 0 WHERE id = ?`, article.ID).Error)
+	router := gin.New()
+	router.POST("/backfill", handlers.NewArticleHandler(articleService).BackfillReadingStats)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/backfill", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data struct {
+			Updated int `json:"updated"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.GreaterOrEqual(t, resp.Data.Updated, 1)
+
+	stored, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.WordCount)
+	assert.Equal(t, 1, stored.ReadingTimeMinutes)
+	assert.Equal(t, updated.Version, stored.Version)
+
+	n, err := articleService.BackfillReadingStats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
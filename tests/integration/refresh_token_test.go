@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth_RefreshAndLogout(t *testing.T) {
+	post := func(path string, body interface{}) (int, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	email := fmt.Sprintf("refresh_%d@example.com", time.Now().UnixNano())
+	code, _ := post("/api/v1/auth/register", models.UserCreate{Username: fmt.Sprintf("refresh_%d", time.Now().UnixNano()), Email: email, Password: "password123"})
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, code)
+
+	code, login := post("/api/v1/auth/login", models.UserLogin{Email: email, Password: "password123"})
+	require.Equal(t, http.StatusOK, code)
+	refreshToken, _ := login["refresh_token"].(string)
+	require.NotEmpty(t, refreshToken)
+	require.NotEmpty(t, login["token"])
+	refreshExpiresAt := login["refresh_token_expires_at"]
+
+	// 刷新 token 不能当作访问 token 使用
+	req, _ := http.NewRequest("GET", "/api/v1/users/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+refreshToken)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 刷新得到可用的访问 token，刷新 token 的过期时间不变
+	code, refreshed := post("/api/v1/auth/refresh", models.RefreshTokenRequest{RefreshToken: refreshToken})
+	require.Equal(t, http.StatusOK, code)
+	access, _ := refreshed["token"].(string)
+	require.NotEmpty(t, access)
+	assert.Equal(t, refreshToken, refreshed["refresh_token"])
+	assert.Equal(t, refreshExpiresAt, refreshed["refresh_token_expires_at"])
+
+	req, _ = http.NewRequest("GET", "/api/v1/users/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+access)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 退出登录后刷新 token 失效
+	code, _ = post("/api/v1/auth/logout", models.RefreshTokenRequest{RefreshToken: refreshToken})
+	require.Equal(t, http.StatusOK, code)
+	code, _ = post("/api/v1/auth/refresh", models.RefreshTokenRequest{RefreshToken: refreshToken})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = post("/api/v1/auth/logout", models.RefreshTokenRequest{RefreshToken: refreshToken})
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 访问 token、伪造的 token 和空请求
+	code, _ = post("/api/v1/auth/refresh", models.RefreshTokenRequest{RefreshToken: access})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = post("/api/v1/auth/refresh", models.RefreshTokenRequest{RefreshToken: "not-a-token"})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = post("/api/v1/auth/refresh", map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
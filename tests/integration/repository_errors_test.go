@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryErrors_NotFoundAndConflict(t *testing.T) {
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository()
+	tagRepo := repository.NewTagRepository()
+
+	// 唯一约束冲突包装为 ErrConflict，原始信息保留
+	user := &models.User{
+		ID: uuid.New(), Username: "conflict_" + uuid.NewString()[:8], Email: uuid.NewString()[:8] + "@example.com",
+		Password: "x", Role: models.RoleAuthor, Status: "active", CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+	dup := *user
+	dup.ID = uuid.New()
+	dup.Email = uuid.NewString()[:8] + "@example.com"
+	err := userRepo.Create(ctx, &dup)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	assert.NotErrorIs(t, err, repository.ErrNotFound)
+
+	tag, err := services.NewTagService(tagRepo, repository.NewArticleRepository()).Create(ctx, &models.TagCreate{Name: "conflict-" + uuid.NewString()[:8]})
+	require.NoError(t, err)
+	err = tagRepo.Create(ctx, &models.Tag{Name: tag.Name, Slug: tag.Slug})
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	// 不存在的记录返回 ErrNotFound，数据库错误（如请求已取消）不会被当作不存在
+	_, err = tagRepo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrTagNotFound)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = userRepo.GetByID(cancelled, user.ID)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestServiceErrorStatusMapping(t *testing.T) {
+	useMiniRedis(t)
+	categoryHandler := handlers.NewCategoryHandler(services.NewCategoryService(repository.NewCategoryRepository(), repository.NewArticleRepository()))
+	tagService := services.NewTagService(repository.NewTagRepository(), repository.NewArticleRepository())
+	tagHandler := handlers.NewTagHandler(tagService)
+	router := gin.New()
+	router.GET("/categories/:id", categoryHandler.GetByID)
+	router.GET("/tags/:id", tagHandler.GetByID)
+	router.PUT("/tags/:id", tagHandler.Update)
+
+	category, err := services.NewCategoryService(repository.NewCategoryRepository(), repository.NewArticleRepository()).Create(context.Background(), &models.CategoryCreate{Name: "mapping-" + uuid.NewString()[:8]})
+	require.NoError(t, err)
+	tagName := "mapping-" + uuid.NewString()[:8]
+	taken, err := tagService.Create(context.Background(), &models.TagCreate{Name: "taken-" + uuid.NewString()[:8]})
+	require.NoError(t, err)
+	other, err := tagService.Create(context.Background(), &models.TagCreate{Name: "other-" + uuid.NewString()[:8]})
+	require.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		ctx    context.Context
+		want   int
+	}{
+		{"existing category", "GET", "/categories/" + category.ID.String(), nil, nil, http.StatusOK},
+		{"missing category", "GET", "/categories/" + uuid.NewString(), nil, nil, http.StatusNotFound},
+		{"missing tag", "GET", "/tags/" + uuid.NewString(), nil, nil, http.StatusNotFound},
+		{"update missing tag", "PUT", "/tags/" + uuid.NewString(), models.TagUpdate{Name: &tagName}, nil, http.StatusNotFound},
+		{"duplicate tag name", "PUT", "/tags/" + other.ID.String(), models.TagUpdate{Name: &taken.Name}, nil, http.StatusConflict},
+		{"database failure", "GET", "/categories/" + category.ID.String(), nil, cancelled, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			req := httptest.NewRequest(tc.method, tc.path, &body)
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ctx != nil {
+				req = req.WithContext(tc.ctx)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.want, w.Code, w.Body.String())
+		})
+	}
+
+	// 注册时用户名已被占用返回 409
+	token := registerAndLogin(t, "conflict_register")
+	profile := profileID(t, token)
+	existing, err := repository.NewUserRepository().GetByID(context.Background(), profile)
+	require.NoError(t, err)
+	data, _ := json.Marshal(models.UserCreate{Username: existing.Username, Email: uuid.NewString()[:8] + "@example.com", Password: "password123", Role: models.RoleAuthor})
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestValidation_CreateAndUpdate(t *testing.T) {
+	useMiniRedis(t)
+	token := registerAndLogin(t, "validation")
+	categoryHandler := handlers.NewCategoryHandler(services.NewCategoryService(repository.NewCategoryRepository(), repository.NewArticleRepository()))
+	tagHandler := handlers.NewTagHandler(services.NewTagService(repository.NewTagRepository(), repository.NewArticleRepository()))
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	articleHandler := handlers.NewArticleHandler(articleService)
+	admin := gin.New()
+	admin.PUT("/articles/:id/status", articleHandler.AdminUpdateStatus)
+	admin.POST("/categories", categoryHandler.Create)
+	admin.PUT("/categories/:id", categoryHandler.Update)
+	admin.POST("/tags", tagHandler.Create)
+	admin.PUT("/tags/:id", tagHandler.Update)
+
+	type fieldError struct {
+		Field string `json:"field"`
+		Rule  string `json:"rule"`
+	}
+	send := func(router http.Handler, method, path string, body interface{}) (int, string, []fieldError) {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "en")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp struct {
+			ErrorCode string `json:"error_code"`
+			Data      struct {
+				Errors []fieldError `json:"errors"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp.ErrorCode, resp.Data.Errors
+	}
+	created := func(router http.Handler, path string, body interface{}) uuid.UUID {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				ID uuid.UUID `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.ID
+	}
+
+	article := created(testRouter, "/api/v1/articles", models.ArticleCreate{Title: "Valid " + uuid.NewString()[:8], Content: "content"})
+	category := created(admin, "/categories", map[string]string{"name": "valid-" + uuid.NewString()[:8]})
+	tag := created(admin, "/tags", map[string]string{"name": "valid-" + uuid.NewString()[:8], "color": "#00ADD8"})
+	badStatus := models.ArticleStatus("deleted")
+	version := 1
+	badColor := "red"
+	longName := strings.Repeat("n", 101)
+
+	cases := []struct {
+		name   string
+		router http.Handler
+		method string
+		path   string
+		body   interface{}
+		want   fieldError
+	}{
+		{"article title too long", testRouter, "POST", "/api/v1/articles", models.ArticleCreate{Title: strings.Repeat("t", 201), Content: "content"}, fieldError{"title", "max"}},
+		{"article content required", testRouter, "POST", "/api/v1/articles", models.ArticleCreate{Title: "No content"}, fieldError{"content", "required"}},
+		{"article unknown status", testRouter, "POST", "/api/v1/articles", models.ArticleCreate{Title: "Bad status", Content: "content", Status: badStatus}, fieldError{"status", "article_status"}},
+		{"article update unknown status", testRouter, "PUT", "/api/v1/articles/" + article.String(), models.ArticleUpdate{Status: &badStatus, ExpectedVersion: &version}, fieldError{"status", "article_status"}},
+		{"admin status required", admin, "PUT", "/articles/" + article.String() + "/status", map[string]string{"reason": "no status"}, fieldError{"status", "required"}},
+		{"admin unknown status", admin, "PUT", "/articles/" + article.String() + "/status", map[string]string{"status": "bogus"}, fieldError{"status", "article_status"}},
+		{"category name too long", admin, "POST", "/categories", map[string]string{"name": longName}, fieldError{"name", "max"}},
+		{"category update name too long", admin, "PUT", "/categories/" + category.String(), map[string]string{"name": longName}, fieldError{"name", "max"}},
+		{"tag color not hex", admin, "POST", "/tags", map[string]string{"name": "color-" + uuid.NewString()[:8], "color": badColor}, fieldError{"color", "color"}},
+		{"tag update color not hex", admin, "PUT", "/tags/" + tag.String(), models.TagUpdate{Color: &badColor}, fieldError{"color", "color"}},
+		{"comment email invalid", testRouter, "POST", "/api/v1/articles/" + article.String() + "/comments", models.CommentCreate{ArticleID: article, Content: "hi", Author: "guest", Email: "not-an-email"}, fieldError{"email", "email"}},
+		{"comment content required", testRouter, "POST", "/api/v1/articles/" + article.String() + "/comments", models.CommentCreate{ArticleID: article, Author: "guest"}, fieldError{"content", "required"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, errorCode, fieldErrs := send(tc.router, tc.method, tc.path, tc.body)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Equal(t, i18n.CodeValidationFailed, errorCode)
+			assert.Equal(t, []fieldError{tc.want}, fieldErrs)
+		})
+	}
+
+	// 绕过处理器直接调用服务时同样拒绝未定义的状态
+	_, err := articleService.UpdateStatus(context.Background(), article, badStatus, nil, "")
+	assert.ErrorIs(t, err, services.ErrInvalidArticleStatus)
+
+	// 自定义规则的错误信息同样按语言翻译
+	data, _ := json.Marshal(models.ArticleCreate{Title: "Bad status", Content: "content", Status: badStatus})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), "status must be one of draft, review, published, archived, scheduled")
+
+	// 合法的状态和颜色可以保存，颜色传空字符串表示清除
+	created(testRouter, "/api/v1/articles", models.ArticleCreate{Title: "Review " + uuid.NewString()[:8], Content: "content", Status: models.StatusReview})
+	empty := ""
+	code, _, fieldErrs := send(admin, "PUT", "/tags/"+tag.String(), models.TagUpdate{Color: &empty})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, fieldErrs)
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReturnsLocationAndDeleteReturnsNoContent(t *testing.T) {
+	useMiniRedis(t)
+	categoryHandler := handlers.NewCategoryHandler(services.NewCategoryService(repository.NewCategoryRepository(), repository.NewArticleRepository()))
+	tagHandler := handlers.NewTagHandler(services.NewTagService(repository.NewTagRepository(), repository.NewArticleRepository()))
+	router := gin.New()
+	admin := router.Group("/api/v1/admin")
+	admin.POST("/categories", categoryHandler.Create)
+	admin.GET("/categories/:id", categoryHandler.GetByID)
+	admin.DELETE("/categories/:id", categoryHandler.Delete)
+	admin.POST("/tags", tagHandler.Create)
+	admin.GET("/tags/:id", tagHandler.GetByID)
+	admin.DELETE("/tags/:id", tagHandler.Delete)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, resource := range []string{"categories", "tags"} {
+		t.Run(resource, func(t *testing.T) {
+			w := do("POST", "/api/v1/admin/"+resource, map[string]string{"name": "status-" + uuid.NewString()[:8]})
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			var created struct {
+				Code int `json:"code"`
+				Data struct {
+					ID uuid.UUID `json:"id"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+			assert.Equal(t, http.StatusCreated, created.Code)
+
+			// Location 可以直接用于后续 GET
+			location := w.Header().Get("Location")
+			assert.Equal(t, "/api/v1/admin/"+resource+"/"+created.Data.ID.String(), location)
+			assert.Equal(t, http.StatusOK, do("GET", location, nil).Code)
+
+			w = do("DELETE", location, nil)
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Empty(t, w.Body.Bytes())
+			assert.Equal(t, http.StatusNotFound, do("GET", location, nil).Code)
+		})
+	}
+}
+
+func TestCreateComment_LocationIsTheComment(t *testing.T) {
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	author := profileID(t, registerAndLogin(t, "comment_location"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Comment location " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished})
+	require.NoError(t, err)
+
+	data, _ := json.Marshal(models.CommentCreate{ArticleID: article.ID, Content: "located", Author: "guest", Email: "guest@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/"+article.ID.String()+"/comments", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+registerAndLogin(t, "comment_location_reader"))
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Comment `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	location := w.Header().Get("Location")
+	assert.Equal(t, "/api/v1/comments/"+created.Data.ID.String(), location)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, location, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var fetched struct {
+		Data models.Comment `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, created.Data.ID, fetched.Data.ID)
+	assert.Equal(t, "located", fetched.Data.Content)
+
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/comments/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
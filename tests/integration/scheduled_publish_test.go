@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledPublishing(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), repository.NewTagRepository())
+	token := registerAndLogin(t, "scheduled")
+	suffix := uuid.NewString()[:8]
+
+	adminToken, err := testJWT.GenerateToken(profileID(t, token), "scheduled_admin", string(models.RoleAdmin))
+	require.NoError(t, err)
+	createAs := func(token string, body map[string]interface{}) (int, models.Response, *models.Article) {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp models.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var created struct {
+			Data *models.Article `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return w.Code, resp, created.Data
+	}
+	create := func(body map[string]interface{}) (int, models.Response, *models.Article) {
+		t.Helper()
+		return createAs(token, body)
+	}
+
+	// 普通作者可以定时发布，时间按时区换算后保存
+	at := time.Now().Add(time.Hour).Truncate(time.Second).In(time.FixedZone("UTC+8", 8*3600))
+	code, _, scheduled := create(map[string]interface{}{
+		"title": "Scheduled " + suffix, "content": "later", "status": models.StatusScheduled, "scheduled_at": at,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, models.StatusScheduled, scheduled.Status)
+	require.NotNil(t, scheduled.ScheduledAt)
+	assert.True(t, at.Equal(*scheduled.ScheduledAt))
+	assert.Nil(t, scheduled.PublishedAt)
+
+	// 未指定时间返回 400；普通作者仍不能直接发布
+	code, resp, _ := create(map[string]interface{}{"title": "Scheduled missing " + suffix, "content": "x", "status": models.StatusScheduled})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "scheduled_at_required", resp.ErrorCode)
+	code, _, draft := create(map[string]interface{}{"title": "Scheduled direct " + suffix, "content": "x", "status": models.StatusPublished})
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, models.StatusDraft, draft.Status)
+
+	// 时间已过去时普通作者不能借此直接发布，改为待审核
+	past := time.Now().Add(-time.Minute)
+	code, _, pastReview := create(map[string]interface{}{
+		"title": "Scheduled past " + suffix, "content": "now", "status": models.StatusScheduled, "scheduled_at": past,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, models.StatusReview, pastReview.Status)
+	assert.Nil(t, pastReview.PublishedAt)
+
+	// 修改时把定时发布时间改到过去同样改为待审核
+	pending, err := articleRepo.GetByID(ctx, scheduled.ID)
+	require.NoError(t, err)
+	data, _ := json.Marshal(map[string]interface{}{"scheduled_at": past, "expected_version": pending.Version})
+	req, _ := http.NewRequest("PUT", "/api/v1/articles/"+scheduled.ID.String(), bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated struct {
+		Data *models.Article `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, models.StatusReview, updated.Data.Status)
+	assert.Nil(t, updated.Data.PublishedAt)
+
+	// 管理员的定时发布时间已过去时立即发布
+	code, _, immediate := createAs(adminToken, map[string]interface{}{
+		"title": "Scheduled admin past " + suffix, "content": "now", "status": models.StatusScheduled, "scheduled_at": past,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, models.StatusPublished, immediate.Status)
+	assert.NotNil(t, immediate.PublishedAt)
+	assert.Nil(t, immediate.ScheduledAt)
+
+	// 改回定时发布，供后续检查后台任务
+	version := updated.Data.Version
+	future := time.Now().Add(time.Hour)
+	scheduledStatus := models.StatusScheduled
+	_, err = articleService.Update(ctx, scheduled.ID, &models.ArticleUpdate{Status: &scheduledStatus, ScheduledAt: &future, ExpectedVersion: &version})
+	require.NoError(t, err)
+	scheduled, err = articleRepo.GetByID(ctx, scheduled.ID)
+	require.NoError(t, err)
+
+	// 未到时间的文章不会被发布
+	_, err = articleService.PublishDueArticles(ctx)
+	require.NoError(t, err)
+	current, err := articleRepo.GetByID(ctx, scheduled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusScheduled, current.Status)
+
+	// 到达发布时间后由后台任务发布
+	require.NoError(t, database.DB.Exec(`UPDATE articles SET scheduled_at = ? WHERE id = ?`, time.Now().Add(-time.Second), scheduled.ID).Error)
+	n, err := articleService.PublishDueArticles(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+	published, err := articleService.GetByID(ctx, scheduled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPublished, published.Status)
+	require.NotNil(t, published.PublishedAt)
+	assert.Nil(t, published.ScheduledAt)
+	assert.Equal(t, scheduled.Version+1, published.Version)
+
+	history, err := articleRepo.ListStatusHistory(ctx, scheduled.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	last := history[len(history)-1]
+	assert.Equal(t, models.StatusPublished, last.ToStatus)
+	require.NotNil(t, last.FromStatus)
+	assert.Equal(t, models.StatusScheduled, *last.FromStatus)
+
+	// 已发布的文章不会重复发布
+	n, err = articleService.PublishDueArticles(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
@@ -0,0 +1,211 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEO_RobotsFollowsConfig(t *testing.T) {
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+
+	router := gin.New()
+	router.GET("/robots.txt", handlers.NewSEOHandler(nil).Robots)
+	robots := func(mode string, disallowAll bool) string {
+		cfg := *original
+		cfg.Server.Mode = mode
+		cfg.Server.PublicURL = "https://api.example.com/"
+		cfg.SEO.DisallowAll = disallowAll
+		config.Set(&cfg)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/robots.txt", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "User-agent: *\nAllow: /\n\nSitemap: https://api.example.com/sitemap.xml\n", robots("release", false))
+	assert.Equal(t, "User-agent: *\nDisallow: /\n", robots("debug", false))
+	assert.Equal(t, "User-agent: *\nDisallow: /\n", robots("release", true))
+}
+
+func TestSEO_ArticleMeta(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	token := registerAndLogin(t, "seo_author")
+
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+	cfg := *original
+	cfg.Server.PublicURL = "https://api.example.com"
+	cfg.Server.FrontendURL = "https://blog.example.com/"
+	config.Set(&cfg)
+
+	create := func(req models.ArticleCreate) models.Article {
+		data, _ := json.Marshal(req)
+		httpReq, _ := http.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(data))
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httpReq)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data models.Article `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+	getMeta := func(slug string) (int, models.ArticleMeta) {
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/articles/slug/"+slug+"/meta", nil))
+		var resp struct {
+			Data models.ArticleMeta `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	article := create(models.ArticleCreate{
+		Title:           "SEO meta article",
+		Content:         "content",
+		Excerpt:         "excerpt",
+		MetaDescription: "custom description",
+		CoverImage:      "/uploads/images/cover.png",
+	})
+
+	// 草稿不对外暴露
+	code, _ := getMeta(article.Slug)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	stored, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	stored.Status = models.StatusPublished
+	require.NoError(t, articleRepo.Update(ctx, stored))
+
+	code, meta := getMeta(article.Slug)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "SEO meta article", meta.Title)
+	assert.Equal(t, "custom description", meta.MetaDescription)
+	assert.Equal(t, "https://api.example.com/uploads/images/cover.png", meta.OGImage)
+	assert.Equal(t, "https://blog.example.com/articles/"+article.ID.String(), meta.Canonical)
+	assert.NotNil(t, meta.PublishedAt)
+
+	// 未填写描述时使用摘要；读取 meta 不计入浏览次数
+	fallback := create(models.ArticleCreate{Title: "SEO fallback article", Content: "content", Excerpt: "the excerpt"})
+	stored, err = articleRepo.GetByID(ctx, fallback.ID)
+	require.NoError(t, err)
+	stored.Status = models.StatusPublished
+	require.NoError(t, articleRepo.Update(ctx, stored))
+
+	code, meta = getMeta(fallback.Slug)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "the excerpt", meta.MetaDescription)
+	assert.Empty(t, meta.OGImage)
+	stored, err = articleRepo.GetByID(ctx, fallback.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.ViewCount)
+
+	code, _ = getMeta("no-such-article")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestSEO_Sitemap(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleRepo := repository.NewArticleRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	tagRepo := repository.NewTagRepository()
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	author := profileID(t, registerAndLogin(t, "sitemap_author"))
+	suffix := uuid.NewString()[:8]
+
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+	cfg := *original
+	cfg.Server.PublicURL = "https://api.example.com"
+	cfg.Server.FrontendURL = "https://blog.example.com"
+	config.Set(&cfg)
+
+	sitemapService := services.NewSitemapService(articleRepo, categoryRepo, tagRepo)
+	router := gin.New()
+	seo := handlers.NewSEOHandler(sitemapService)
+	router.GET("/sitemap.xml", seo.Sitemap)
+	router.GET("/sitemaps/:file", seo.SitemapPage)
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code == http.StatusOK {
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/xml")
+		}
+		return w.Code, w.Body.String()
+	}
+
+	category, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: "Sitemap " + suffix})
+	require.NoError(t, err)
+	tag, err := services.NewTagService(tagRepo, articleRepo).Create(ctx, &models.TagCreate{Name: "sitemap " + suffix})
+	require.NoError(t, err)
+	published, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Sitemap published " + suffix, Content: "x", Status: models.StatusPublished})
+	require.NoError(t, err)
+	draft, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Sitemap draft " + suffix, Content: "x", Status: models.StatusDraft})
+	require.NoError(t, err)
+
+	// 只包含已发布文章，地址与 canonical 一致，lastmod 取更新时间
+	code, body := get("/sitemap.xml")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	assert.Contains(t, body, "<url><loc>https://blog.example.com/articles/"+published.ID.String()+"</loc><lastmod>"+
+		published.UpdatedAt.UTC().Format(time.RFC3339)+"</lastmod></url>")
+	assert.NotContains(t, body, draft.ID.String())
+	assert.Contains(t, body, "<loc>https://blog.example.com/categories/"+category.Slug+"</loc>")
+	assert.Contains(t, body, "<loc>https://blog.example.com/tags/"+tag.Slug+"</loc>")
+
+	// 结果被缓存；发布文章清理文章列表缓存后重新生成
+	stored, err := articleRepo.GetByID(ctx, draft.ID)
+	require.NoError(t, err)
+	stored.Status = models.StatusPublished
+	require.NoError(t, articleRepo.Update(ctx, stored))
+	_, body = get("/sitemap.xml")
+	assert.NotContains(t, body, draft.ID.String())
+	title := "Sitemap renamed " + suffix
+	_, err = articleService.Update(ctx, published.ID, &models.ArticleUpdate{Title: &title, ExpectedVersion: &published.Version})
+	require.NoError(t, err)
+	_, body = get("/sitemap.xml")
+	assert.Contains(t, body, draft.ID.String())
+
+	// 超过单个文件的地址数上限时返回索引，分页按上限拆分
+	sitemapService.WithMaxURLs(2)
+	_, err = services.FlushCache(ctx, services.CacheScopeArticleList)
+	require.NoError(t, err)
+	code, body = get("/sitemap.xml")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	assert.Contains(t, body, "<loc>https://api.example.com/sitemaps/1.xml</loc>")
+	pages := strings.Count(body, "<sitemap>")
+	require.GreaterOrEqual(t, pages, 2)
+	code, body = get("/sitemaps/1.xml")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, strings.Count(body, "<url>"))
+	code, _ = get("/sitemaps/" + strconv.Itoa(pages+1) + ".xml")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("/sitemaps/abc")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,59 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSLogin_RespectsRegistrationMode(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository()
+	smsRepo := repository.NewSMSRepository()
+	smsService := services.NewSMSService(smsRepo, userRepo)
+	settings := services.NewSettingsService(repository.NewSettingRepository())
+	require.NoError(t, settings.Init(ctx))
+	t.Cleanup(func() {
+		_, _ = settings.Update(context.Background(), map[string]interface{}{
+			models.SettingRegistrationMode: models.RegistrationOpen,
+		}, nil)
+	})
+
+	suffix := time.Now().UnixNano() % 100000000
+	// 直接写入验证码，避免 SendCode 的 1 分钟发送间隔
+	login := func(phone string) (*models.User, error) {
+		t.Helper()
+		require.NoError(t, smsRepo.Create(ctx, &models.SMSCode{Phone: phone, Code: "246810", ExpiresAt: time.Now().Add(time.Minute)}))
+		return smsService.VerifyCode(ctx, phone, "246810")
+	}
+
+	// 新手机号自动创建账号，计入注册数
+	registrations := metricValue(t, "user_registrations_total", nil)
+	existing := fmt.Sprintf("137%08d", suffix)
+	user, err := login(existing)
+	require.NoError(t, err)
+	assert.Equal(t, existing, user.Phone)
+	assert.Equal(t, registrations+1, metricValue(t, "user_registrations_total", nil))
+
+	// 关闭注册后不再自动创建账号，已有账号仍可登录
+	_, err = settings.Update(ctx, map[string]interface{}{models.SettingRegistrationMode: models.RegistrationClosed}, nil)
+	require.NoError(t, err)
+	_, err = login(fmt.Sprintf("136%08d", suffix))
+	assert.ErrorIs(t, err, services.ErrRegistrationClosed)
+	_, err = userRepo.GetByPhone(ctx, fmt.Sprintf("136%08d", suffix))
+	assert.Error(t, err)
+	assert.Equal(t, registrations+1, metricValue(t, "user_registrations_total", nil))
+
+	again, err := login(existing)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, again.ID)
+}
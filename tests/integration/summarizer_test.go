@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/i18n"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChatCompletions 模拟 OpenAI 兼容的 chat completions 接口，failing 为 true 时返回 500
+func fakeChatCompletions(t *testing.T, summary string, failing *atomic.Bool, lastInput *atomic.Value) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		var req struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "test-model", req.Model)
+		lastInput.Store(req.Messages[len(req.Messages)-1].Content)
+
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "  " + summary + "\n"}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSummarizer_ProposalAndAutoFill(t *testing.T) {
+	ctx := context.Background()
+	var failing atomic.Bool
+	var lastInput atomic.Value
+	srv := fakeChatCompletions(t, "A concise summary.", &failing, &lastInput)
+
+	settings := services.NewSettingsService(repository.NewSettingRepository())
+	require.NoError(t, settings.Init(ctx))
+	t.Cleanup(func() {
+		_, _ = settings.Update(ctx, map[string]interface{}{models.SettingAutoSummary: false}, nil)
+	})
+
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	articleService.SetSummarizer(services.NewSummarizer(config.SummarizerConfig{
+		BaseURL: srv.URL + "/v1/", APIKey: "test-key", Model: "test-model", TimeoutMs: 2000, MaxInputTokens: 10,
+	}))
+	author := profileID(t, registerAndLogin(t, "summary_author"))
+	other := profileID(t, registerAndLogin(t, "summary_other"))
+
+	// 建议：不保存；正文按 token 预算截断后发送（10 个 token ≈ 40 个英文字符或 10 个汉字）
+	draft, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Summary draft", Content: strings.Repeat("word ", 100)})
+	require.NoError(t, err)
+	summary, err := articleService.GenerateSummary(ctx, draft.ID, author, false)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ArticleSummary{Excerpt: "A concise summary.", MetaDescription: "A concise summary.", Generated: true}, summary)
+	assert.Equal(t, "标题：Summary draft\n\n"+strings.Repeat("word ", 8), lastInput.Load())
+
+	_, err = articleService.GenerateSummary(ctx, draft.ID, other, false)
+	assert.ErrorIs(t, err, services.ErrNotArticleAuthor)
+	_, err = articleService.GenerateSummary(ctx, draft.ID, other, true)
+	assert.NoError(t, err)
+
+	// 关闭 auto_summary（默认）时发布不调用摘要服务
+	published, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: "Summary off", Content: "plain content", Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, "plain content", published.Excerpt)
+	assert.Empty(t, published.MetaDescription)
+
+	// 开启后发布时补齐空缺的摘要和 SEO 描述，作者填写的值保留
+	_, err = settings.Update(ctx, map[string]interface{}{models.SettingAutoSummary: true}, nil)
+	require.NoError(t, err)
+	published, err = articleService.Create(ctx, author, &models.ArticleCreate{Title: "Summary on", Content: "汉字正文内容很长很长很长很长", Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, "A concise summary.", published.Excerpt)
+	assert.Equal(t, "A concise summary.", published.MetaDescription)
+	assert.Equal(t, "标题：Summary on\n\n汉字正文内容很长很长", lastInput.Load())
+
+	published, err = articleService.Create(ctx, author, &models.ArticleCreate{Title: "Own excerpt", Content: "content", Excerpt: "written by author", Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, "written by author", published.Excerpt)
+	assert.Equal(t, "A concise summary.", published.MetaDescription)
+
+	// 分类或标签不存在时在调用摘要服务之前返回
+	lastInput.Store("")
+	missing := uuid.New()
+	_, err = articleService.Create(ctx, author, &models.ArticleCreate{Title: "Missing category", Content: "content", Status: models.StatusPublished, CategoryID: &missing})
+	assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	_, err = articleService.Create(ctx, author, &models.ArticleCreate{Title: "Missing tag", Content: "content", Status: models.StatusPublished, TagIDs: []uuid.UUID{missing}})
+	assert.ErrorIs(t, err, services.ErrTagsNotFound)
+	assert.Equal(t, "", lastInput.Load())
+
+	// 草稿不补齐；草稿发布时补齐
+	assert.Equal(t, strings.TrimSpace(strings.Repeat("word ", 40))+"...", draft.Excerpt)
+	status := models.StatusPublished
+	updated, err := articleService.Update(ctx, draft.ID, &models.ArticleUpdate{Status: &status, ExpectedVersion: &draft.Version})
+	require.NoError(t, err)
+	assert.Equal(t, "A concise summary.", updated.Excerpt)
+
+	// 摘要服务失败时使用截取正文的摘要，不影响发布
+	failing.Store(true)
+	published, err = articleService.Create(ctx, author, &models.ArticleCreate{Title: "Summary failing", Content: "fallback content", Status: models.StatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPublished, published.Status)
+	assert.Equal(t, "fallback content", published.Excerpt)
+	assert.Empty(t, published.MetaDescription)
+}
+
+func TestSummarizer_GenerateSummaryEndpoint(t *testing.T) {
+	authorToken := registerAndLogin(t, "summary_api_author")
+	otherToken := registerAndLogin(t, "summary_api_other")
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+	article, err := articleService.Create(context.Background(), profileID(t, authorToken), &models.ArticleCreate{Title: "Endpoint article", Content: "endpoint content"})
+	require.NoError(t, err)
+
+	send := func(token string) (int, models.Response) {
+		req, _ := http.NewRequest("POST", "/api/v1/articles/"+article.ID.String()+"/generate-summary", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp models.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp
+	}
+
+	// 测试路由未配置摘要服务：返回截取正文的摘要，generated 为 false
+	code, resp := send(authorToken)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"excerpt": "endpoint content", "meta_description": "", "generated": false}, resp.Data)
+
+	code, resp = send(otherToken)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, i18n.CodeNotArticleAuthor, resp.ErrorCode)
+}
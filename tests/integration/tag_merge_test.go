@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagService_Merge(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	tagRepo := repository.NewTagRepository()
+	articleRepo := repository.NewArticleRepository()
+	tagService := services.NewTagService(tagRepo, articleRepo)
+	articleService := services.NewArticleService(articleRepo, repository.NewCategoryRepository(), tagRepo)
+	author := profileID(t, registerAndLogin(t, "merge_author"))
+
+	suffix := uuid.NewString()[:8]
+	source, err := tagService.Create(ctx, &models.TagCreate{Name: "Golang " + suffix})
+	require.NoError(t, err)
+	target, err := tagService.Create(ctx, &models.TagCreate{Name: "Go " + suffix})
+	require.NoError(t, err)
+
+	// 一篇只有被合并标签，一篇两个标签都有
+	create := func(tagIDs ...uuid.UUID) *models.Article {
+		article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+			Title: "Merge article", Content: "content", Status: models.StatusPublished, TagIDs: tagIDs,
+		})
+		require.NoError(t, err)
+		return article
+	}
+	onlySource := create(source.ID)
+	both := create(source.ID, target.ID)
+	// 合并前读取一次，写入详情缓存
+	cached, err := articleService.GetByID(ctx, onlySource.ID)
+	require.NoError(t, err)
+	require.Len(t, cached.Tags, 1)
+	assert.Equal(t, source.ID, cached.Tags[0].ID)
+
+	_, err = tagService.Merge(ctx, source.ID, source.ID)
+	assert.ErrorIs(t, err, services.ErrInvalidMergeTarget)
+	_, err = tagService.Merge(ctx, source.ID, uuid.New())
+	assert.ErrorIs(t, err, services.ErrInvalidMergeTarget)
+
+	result, err := tagService.Merge(ctx, source.ID, target.ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, result.ArticlesTouched)
+	assert.Equal(t, target.ID, result.Target.ID)
+
+	// 被合并标签已软删除，文章关联转移到目标标签且不重复
+	_, err = tagService.GetByID(ctx, source.ID)
+	assert.Error(t, err)
+	for _, id := range []uuid.UUID{onlySource.ID, both.ID} {
+		article, err := articleRepo.GetByID(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, article.Tags, 1)
+		assert.Equal(t, target.ID, article.Tags[0].ID)
+	}
+	// 详情缓存已失效，返回目标标签
+	detail, err := articleService.GetByID(ctx, onlySource.ID)
+	require.NoError(t, err)
+	require.Len(t, detail.Tags, 1)
+	assert.Equal(t, target.ID, detail.Tags[0].ID)
+	count, err := tagRepo.CountPublishedArticles(ctx, target.ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	// 旧标签 ID 的过滤解析到目标标签
+	_, total, err := articleService.List(ctx, models.ArticleQuery{TagID: &source.ID})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+
+	// 旧 slug 解析到目标标签
+	req, _ := http.NewRequest("GET", "/api/v1/tags/slug/"+source.Slug, nil)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data models.Tag `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, target.ID, resp.Data.ID)
+
+	req, _ = http.NewRequest("GET", "/api/v1/tags/slug/missing-"+suffix, nil)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 目标标签再合并到其他标签时，旧别名随之指向新的目标
+	final, err := tagService.Create(ctx, &models.TagCreate{Name: "Go lang " + suffix})
+	require.NoError(t, err)
+	_, err = tagService.Merge(ctx, target.ID, final.ID)
+	require.NoError(t, err)
+	resolved, err := tagService.GetBySlug(ctx, source.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, final.ID, resolved.ID)
+}
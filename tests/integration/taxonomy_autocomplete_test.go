@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxonomyAutocomplete(t *testing.T) {
+	ctx := context.Background()
+	tagRepo := repository.NewTagRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	articleRepo := repository.NewArticleRepository()
+	tagService := services.NewTagService(tagRepo, articleRepo)
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+
+	authorToken := registerAndLogin(t, "autocomplete_author")
+	author := profileID(t, authorToken)
+	editorToken, err := testJWT.GenerateToken(author, "autocomplete_editor", string(models.RoleEditor))
+	require.NoError(t, err)
+
+	suffix := uuid.NewString()[:8]
+	rare, err := tagService.Create(ctx, &models.TagCreate{Name: "Acgo rare " + suffix})
+	require.NoError(t, err)
+	popular, err := tagService.Create(ctx, &models.TagCreate{Name: "acgo popular " + suffix})
+	require.NoError(t, err)
+	_, err = tagService.Create(ctx, &models.TagCreate{Name: "acg%o literal " + suffix})
+	require.NoError(t, err)
+	category, err := services.NewCategoryService(categoryRepo, articleRepo).Create(ctx, &models.CategoryCreate{Name: "Acgo Category " + suffix})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = articleService.Create(ctx, author, &models.ArticleCreate{
+			Title: "Autocomplete article", Content: "content", Status: models.StatusPublished,
+			CategoryID: &category.ID, TagIDs: []uuid.UUID{popular.ID},
+		})
+		require.NoError(t, err)
+	}
+
+	get := func(token, path string) (int, []models.TaxonomySuggestion) {
+		req, _ := http.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data []models.TaxonomySuggestion `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	// 大小写不敏感的子串匹配，按使用量排序；关键词中的 % 不作为通配符
+	code, tags := get("", "/api/v1/tags/autocomplete?q=CGO+&limit=5")
+	require.Equal(t, http.StatusOK, code)
+	var ids []uuid.UUID
+	for _, s := range tags {
+		if s.ID == popular.ID || s.ID == rare.ID {
+			ids = append(ids, s.ID)
+		}
+	}
+	assert.Equal(t, []uuid.UUID{popular.ID, rare.ID}, ids)
+	assert.EqualValues(t, 2, tags[0].UsageCount)
+	assert.Equal(t, popular.Slug, tags[0].Slug)
+
+	code, tags = get("", "/api/v1/tags/autocomplete?q=acg%25o+literal+"+suffix)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, tags, 1)
+
+	code, categories := get("", "/api/v1/categories/autocomplete?q=acgo+category+"+suffix)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, categories, 1)
+	assert.Equal(t, category.ID, categories[0].ID)
+	assert.EqualValues(t, 2, categories[0].UsageCount)
+
+	code, _ = get("", "/api/v1/tags/autocomplete")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// create_if_missing：仅编辑和管理员，已有同名标签（大小写不敏感）时不新建
+	newName := "Brand New " + suffix
+	code, _ = get("", "/api/v1/tags/autocomplete?create_if_missing=true&q="+newName)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = get(authorToken, "/api/v1/tags/autocomplete?create_if_missing=true&q="+newName)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, tags = get(editorToken, "/api/v1/tags/autocomplete?create_if_missing=true&q="+newName)
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, tags)
+	assert.True(t, tags[0].Created)
+	assert.Equal(t, newName, tags[0].Name)
+	assert.Equal(t, "brand-new-"+suffix, tags[0].Slug)
+
+	code, tags = get(editorToken, "/api/v1/tags/autocomplete?create_if_missing=true&q=brand+new+"+suffix)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, tags, 1)
+	assert.False(t, tags[0].Created)
+	assert.Equal(t, newName, tags[0].Name)
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategorySlug_ConflictRetriesWithSuffix(t *testing.T) {
+	ctx := context.Background()
+	categoryService := services.NewCategoryService(repository.NewCategoryRepository(), repository.NewArticleRepository())
+	suffix := uuid.NewString()[:8]
+	base := services.GenerateSlug("Slug Cat " + suffix)
+
+	// slug 唯一索引大小写不敏感：名称只有大小写不同时追加数字后缀
+	names := []string{"Slug Cat ", "SLUG CAT ", "slug cat ", "Slug cat ", "slug Cat "}
+	for i, name := range names {
+		category, err := categoryService.Create(ctx, &models.CategoryCreate{Name: name + suffix})
+		require.NoError(t, err)
+		want := base
+		if i > 0 {
+			want = fmt.Sprintf("%s-%d", base, i)
+		}
+		assert.Equal(t, want, category.Slug)
+	}
+
+	// 重试用尽后返回 category.slug_exists
+	_, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "SLUG cat " + suffix})
+	assert.True(t, errors.Is(err, services.ErrCategorySlugExists))
+
+	// 改名同样按冲突重试；名称不变时不重新生成 slug
+	other, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "Other Cat " + suffix})
+	require.NoError(t, err)
+	third, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "Third Cat " + suffix})
+	require.NoError(t, err)
+	renamed := "OTHER cat " + suffix
+	updated, err := categoryService.Update(ctx, third.ID, &models.CategoryUpdate{Name: &renamed})
+	require.NoError(t, err)
+	assert.Equal(t, other.Slug+"-1", updated.Slug)
+
+	description := "unchanged slug"
+	updated, err = categoryService.Update(ctx, third.ID, &models.CategoryUpdate{Description: &description})
+	require.NoError(t, err)
+	assert.Equal(t, other.Slug+"-1", updated.Slug)
+}
+
+func TestTagSlug_ConflictsAndNameExists(t *testing.T) {
+	ctx := context.Background()
+	tagService := services.NewTagService(repository.NewTagRepository(), repository.NewArticleRepository())
+	suffix := uuid.NewString()[:8]
+	base := services.GenerateSlug("Slug Tag " + suffix)
+
+	first, err := tagService.Create(ctx, &models.TagCreate{Name: "Slug Tag " + suffix})
+	require.NoError(t, err)
+	assert.Equal(t, base, first.Slug)
+
+	// 名称大小写不同：名称不冲突，slug 冲突后追加后缀
+	second, err := tagService.Create(ctx, &models.TagCreate{Name: "slug tag " + suffix})
+	require.NoError(t, err)
+	assert.Equal(t, base+"-1", second.Slug)
+
+	// 名称相同：唯一索引冲突转换为 tag.name_exists，不追加后缀重试
+	_, err = tagService.Create(ctx, &models.TagCreate{Name: "Slug Tag " + suffix})
+	assert.Equal(t, services.ErrTagNameExists, err)
+	name := first.Name
+	_, err = tagService.Update(ctx, second.ID, &models.TagUpdate{Name: &name})
+	assert.Equal(t, services.ErrTagNameExists, err)
+
+	// 名称不变时不重新生成 slug
+	color := "#ff0000"
+	updated, err := tagService.Update(ctx, second.ID, &models.TagUpdate{Color: &color})
+	require.NoError(t, err)
+	assert.Equal(t, base+"-1", updated.Slug)
+
+	// 重试用尽后返回 tag.slug_exists
+	for _, name := range []string{"SLUG TAG ", "Slug tag ", "slug Tag "} {
+		_, err := tagService.Create(ctx, &models.TagCreate{Name: name + suffix})
+		require.NoError(t, err)
+	}
+	_, err = tagService.Create(ctx, &models.TagCreate{Name: "sLUG tAG " + suffix})
+	assert.True(t, errors.Is(err, services.ErrTagSlugExists))
+}
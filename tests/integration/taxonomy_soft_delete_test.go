@@ -0,0 +1,222 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deletedAt 读取行的 deleted_at（未删除时为 nil），不经过过滤已删除记录的仓库方法
+func deletedAt(t *testing.T, table string, id uuid.UUID) *time.Time {
+	t.Helper()
+	var row struct {
+		DeletedAt *time.Time
+	}
+	result := database.DB.Raw(`SELECT deleted_at FROM `+table+` WHERE id = ?`, id).Scan(&row)
+	require.NoError(t, result.Error)
+	require.Equal(t, int64(1), result.RowsAffected, "%s row %s should still exist", table, id)
+	return row.DeletedAt
+}
+
+func TestCategorySoftDelete(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	categoryRepo := repository.NewCategoryRepository()
+	articleRepo := repository.NewArticleRepository()
+	tagRepo := repository.NewTagRepository()
+	categoryService := services.NewCategoryService(categoryRepo, articleRepo)
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	trashService := services.NewTrashService(repository.NewTrashRepository(), articleRepo, repository.NewUserRepository(), categoryRepo, tagRepo)
+
+	name := "Soft category " + uuid.NewString()[:8]
+	category, err := categoryService.Create(ctx, &models.CategoryCreate{Name: name})
+	require.NoError(t, err)
+	target, err := categoryService.Create(ctx, &models.CategoryCreate{Name: "Soft target " + uuid.NewString()[:8]})
+	require.NoError(t, err)
+	author := profileID(t, registerAndLogin(t, "category_soft_delete"))
+	article, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Soft category article " + uuid.NewString()[:8], Content: "content", Status: models.StatusPublished, CategoryID: &category.ID,
+	})
+	require.NoError(t, err)
+
+	listed := func() bool {
+		t.Helper()
+		categories, err := categoryRepo.List(ctx, models.TaxonomyListQuery{})
+		require.NoError(t, err)
+		for _, c := range categories {
+			if c.ID == category.ID {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, listed())
+
+	// 转移文章后删除：只设置 deleted_at，行仍然保留
+	require.NoError(t, categoryService.Delete(ctx, category.ID, &target.ID))
+	assert.NotNil(t, deletedAt(t, "categories", category.ID))
+	assert.Nil(t, deletedAt(t, "categories", target.ID))
+
+	// 已删除的分类不出现在列表中，按 ID / slug 都查不到
+	assert.False(t, listed())
+	_, err = categoryRepo.GetByID(ctx, category.ID)
+	assert.Error(t, err)
+	_, err = categoryRepo.GetBySlug(ctx, category.Slug)
+	assert.Error(t, err)
+
+	// 文章移动到目标分类，不再引用已删除的分类
+	moved, err := articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	require.NotNil(t, moved.CategoryID)
+	assert.Equal(t, target.ID, *moved.CategoryID)
+	count, err := categoryRepo.CountArticles(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// 已删除分类的 slug 可以被同名的新分类使用
+	recreated, err := categoryService.Create(ctx, &models.CategoryCreate{Name: name})
+	require.NoError(t, err)
+	assert.Equal(t, category.Slug, recreated.Slug)
+	assert.NotEqual(t, category.ID, recreated.ID)
+
+	// slug 被占用时恢复冲突，新分类删除后可以恢复
+	err = trashService.Restore(ctx, models.TrashCategories, category.ID)
+	assert.ErrorIs(t, err, services.ErrRestoreConflict)
+	assert.NotNil(t, deletedAt(t, "categories", category.ID))
+	require.NoError(t, categoryService.Delete(ctx, recreated.ID, nil))
+	require.NoError(t, trashService.Restore(ctx, models.TrashCategories, category.ID))
+	assert.Nil(t, deletedAt(t, "categories", category.ID))
+	restored, err := categoryRepo.GetBySlug(ctx, category.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, category.ID, restored.ID)
+	assert.True(t, listed())
+
+	// 转移出去的文章不会随恢复移回
+	moved, err = articleRepo.GetByID(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, target.ID, *moved.CategoryID)
+}
+
+func TestTagSoftDelete(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	categoryRepo := repository.NewCategoryRepository()
+	articleRepo := repository.NewArticleRepository()
+	tagRepo := repository.NewTagRepository()
+	tagService := services.NewTagService(tagRepo, articleRepo)
+	articleService := services.NewArticleService(articleRepo, categoryRepo, tagRepo)
+	trashService := services.NewTrashService(repository.NewTrashRepository(), articleRepo, repository.NewUserRepository(), categoryRepo, tagRepo)
+
+	suffix := uuid.NewString()[:8]
+	newTag := func(name string) *models.Tag {
+		t.Helper()
+		tag, err := tagService.Create(ctx, &models.TagCreate{Name: name})
+		require.NoError(t, err)
+		return tag
+	}
+	reassigned := newTag("soft-reassigned-" + suffix)
+	target := newTag("soft-target-" + suffix)
+	detached := newTag("soft-detached-" + suffix)
+	kept := newTag("soft-kept-" + suffix)
+
+	author := profileID(t, registerAndLogin(t, "tag_soft_delete"))
+	published, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Soft tag published " + suffix, Content: "content", Status: models.StatusPublished,
+		TagIDs: []uuid.UUID{reassigned.ID, detached.ID},
+	})
+	require.NoError(t, err)
+	draft, err := articleService.Create(ctx, author, &models.ArticleCreate{
+		Title: "Soft tag draft " + suffix, Content: "content", Status: models.StatusDraft, TagIDs: []uuid.UUID{kept.ID},
+	})
+	require.NoError(t, err)
+
+	tagIDs := func(articleID uuid.UUID) []uuid.UUID {
+		t.Helper()
+		article, err := articleRepo.GetByID(ctx, articleID)
+		require.NoError(t, err)
+		ids := []uuid.UUID{}
+		for _, tag := range article.Tags {
+			ids = append(ids, tag.ID)
+		}
+		return ids
+	}
+	links := func(tagID uuid.UUID) int64 {
+		t.Helper()
+		var n int64
+		require.NoError(t, database.DB.Raw(`SELECT COUNT(*) FROM article_tags WHERE tag_id = ?`, tagID).Scan(&n).Error)
+		return n
+	}
+	listed := func(id uuid.UUID) bool {
+		t.Helper()
+		tags, err := tagRepo.List(ctx, models.TaxonomyListQuery{})
+		require.NoError(t, err)
+		for _, tag := range tags {
+			if tag.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	// 仍被已发布文章使用时，需要指定 reassign_to 或 detach
+	assert.ErrorIs(t, tagService.Delete(ctx, reassigned.ID, nil, false), services.ErrTagInUse)
+	assert.Nil(t, deletedAt(t, "tags", reassigned.ID))
+
+	// reassign：关联转移到目标标签
+	require.NoError(t, tagService.Delete(ctx, reassigned.ID, &target.ID, false))
+	assert.NotNil(t, deletedAt(t, "tags", reassigned.ID))
+	assert.Zero(t, links(reassigned.ID))
+	assert.ElementsMatch(t, []uuid.UUID{target.ID, detached.ID}, tagIDs(published.ID))
+
+	// detach：只移除关联，不添加其他标签
+	require.NoError(t, tagService.Delete(ctx, detached.ID, nil, true))
+	assert.NotNil(t, deletedAt(t, "tags", detached.ID))
+	assert.Zero(t, links(detached.ID))
+	assert.Equal(t, []uuid.UUID{target.ID}, tagIDs(published.ID))
+
+	// 只被草稿使用时可以直接删除：关联保留，但文章中不再显示
+	require.NoError(t, tagService.Delete(ctx, kept.ID, nil, false))
+	assert.NotNil(t, deletedAt(t, "tags", kept.ID))
+	assert.Equal(t, int64(1), links(kept.ID))
+	assert.Empty(t, tagIDs(draft.ID))
+
+	// 已删除的标签不出现在列表中，按 ID / slug / 名称都查不到
+	for _, tag := range []*models.Tag{reassigned, detached, kept} {
+		assert.False(t, listed(tag.ID), tag.Name)
+		_, err = tagRepo.GetByID(ctx, tag.ID)
+		assert.Error(t, err)
+		_, err = tagRepo.GetBySlug(ctx, tag.Slug)
+		assert.Error(t, err)
+		_, err = tagRepo.GetByName(ctx, tag.Name)
+		assert.Error(t, err)
+	}
+	assert.True(t, listed(target.ID))
+
+	// 恢复后保留的关联随之恢复
+	require.NoError(t, trashService.Restore(ctx, models.TrashTags, kept.ID))
+	assert.Nil(t, deletedAt(t, "tags", kept.ID))
+	assert.True(t, listed(kept.ID))
+	assert.Equal(t, []uuid.UUID{kept.ID}, tagIDs(draft.ID))
+
+	// 已删除标签的名称和 slug 可以被新标签使用，此时恢复冲突；新标签删除后可以恢复
+	recreated := newTag(detached.Name)
+	assert.Equal(t, detached.Slug, recreated.Slug)
+	assert.ErrorIs(t, trashService.Restore(ctx, models.TrashTags, detached.ID), services.ErrRestoreConflict)
+	assert.NotNil(t, deletedAt(t, "tags", detached.ID))
+	require.NoError(t, tagService.Delete(ctx, recreated.ID, nil, false))
+	require.NoError(t, trashService.Restore(ctx, models.TrashTags, detached.ID))
+	restored, err := tagRepo.GetBySlug(ctx, detached.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, detached.ID, restored.ID)
+	// 解除的关联不会随恢复回来
+	assert.Zero(t, links(detached.ID))
+}
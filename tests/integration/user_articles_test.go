@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserArticles_ListsOwnArticlesInAllStatuses(t *testing.T) {
+	useMiniRedis(t)
+	ctx := context.Background()
+	articleService := services.NewArticleService(repository.NewArticleRepository(), repository.NewCategoryRepository(), repository.NewTagRepository())
+
+	authorToken := registerAndLogin(t, "mine_author")
+	otherToken := registerAndLogin(t, "mine_other")
+	author := profileID(t, authorToken)
+
+	for _, a := range []struct {
+		title  string
+		status models.ArticleStatus
+	}{
+		{"Mine draft", models.StatusDraft},
+		{"Mine review", models.StatusReview},
+		{"Mine published", models.StatusPublished},
+	} {
+		_, err := articleService.Create(ctx, author, &models.ArticleCreate{Title: a.title, Content: "content", Status: a.status})
+		require.NoError(t, err)
+	}
+
+	list := func(token, path string) (int, []models.Article, int64) {
+		req, _ := http.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		var resp struct {
+			Data []models.Article      `json:"data"`
+			Meta models.PaginationMeta `json:"meta"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data, resp.Meta.Total
+	}
+
+	code, articles, total := list(authorToken, "/api/v1/users/articles")
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 3, total)
+	statuses := map[models.ArticleStatus]bool{}
+	for _, a := range articles {
+		assert.Equal(t, author, a.AuthorID)
+		statuses[a.Status] = true
+	}
+	assert.Equal(t, map[models.ArticleStatus]bool{models.StatusDraft: true, models.StatusReview: true, models.StatusPublished: true}, statuses)
+
+	// 按状态过滤
+	draftsPath := "/api/v1/users/articles?status=draft"
+	code, articles, _ = list(authorToken, draftsPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "Mine draft", articles[0].Title)
+
+	// 草稿列表已缓存：其他用户和公开接口都读不到
+	code, articles, total = list(otherToken, draftsPath)
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, articles)
+	assert.EqualValues(t, 0, total)
+
+	code, articles, _ = list("", "/api/v1/articles?status=draft&page_size=100")
+	require.Equal(t, http.StatusOK, code)
+	for _, a := range articles {
+		assert.Equal(t, models.StatusPublished, a.Status)
+	}
+
+	code, _, _ = list("", "/api/v1/users/articles")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_DeliverWithRetry(t *testing.T) {
+	type received struct {
+		body      []byte
+		signature string
+		timestamp string
+		event     string
+	}
+	var (
+		mu       sync.Mutex
+		requests []received
+		calls    atomic.Int32
+	)
+	// 第一次返回 500，之后成功
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, received{
+			body:      body,
+			signature: r.Header.Get(services.WebhookSignatureHeader),
+			timestamp: r.Header.Get(services.WebhookTimestampHeader),
+			event:     r.Header.Get(services.WebhookEventHeader),
+		})
+		mu.Unlock()
+		if calls.Add(1) == 1 {
+			http.Error(w, "try later", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	svc := services.NewWebhookService(repository.NewWebhookRepository(), config.WebhookConfig{
+		TimeoutMs: 2000, MaxAttempts: 3, RetryBackoffMs: 10,
+	})
+	webhook, err := svc.Create(ctx, &models.WebhookCreate{
+		URL:    server.URL,
+		Events: []string{models.WebhookEventUserRegistered},
+	}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, webhook.Secret)
+	t.Cleanup(func() { _ = svc.Delete(ctx, webhook.ID) })
+
+	_, err = svc.Create(ctx, &models.WebhookCreate{URL: "ftp://example.com", Events: []string{models.WebhookEventUserRegistered}}, nil)
+	assert.ErrorIs(t, err, services.ErrInvalidWebhook)
+	_, err = svc.Create(ctx, &models.WebhookCreate{URL: server.URL, Events: []string{"article.deleted"}}, nil)
+	assert.ErrorIs(t, err, services.ErrInvalidWebhook)
+
+	registerAndLogin(t, "webhook")
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, svc.Wait(waitCtx))
+
+	mu.Lock()
+	require.Len(t, requests, 2)
+	first := requests[1]
+	mu.Unlock()
+	assert.Equal(t, models.WebhookEventUserRegistered, first.event)
+	assert.Equal(t, services.SignWebhookPayload(webhook.Secret, first.timestamp, first.body), first.signature)
+
+	var payload struct {
+		Event string `json:"event"`
+		Data  struct {
+			Username string `json:"username"`
+			Email    string `json:"email"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(first.body, &payload))
+	assert.Equal(t, models.WebhookEventUserRegistered, payload.Event)
+	assert.NotEmpty(t, payload.Data.Username)
+	assert.Empty(t, payload.Data.Email, "emails are not sent to webhooks")
+
+	deliveries, total, err := svc.ListDeliveries(ctx, webhook.ID, 1, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, models.WebhookDeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusOK, deliveries[0].ResponseStatus)
+	assert.Equal(t, "ok", deliveries[0].ResponseBody)
+
+	// 重新投递生成新的记录，请求体不变
+	redelivery, err := svc.Redeliver(ctx, webhook.ID, deliveries[0].ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.Wait(waitCtx))
+	deliveries, total, err = svc.ListDeliveries(ctx, webhook.ID, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	for _, d := range deliveries {
+		if d.ID == redelivery.ID {
+			assert.Equal(t, models.WebhookDeliverySucceeded, d.Status)
+			assert.Equal(t, &deliveries[1].ID, d.RedeliveryOf)
+			assert.Equal(t, deliveries[1].Payload, d.Payload)
+		}
+	}
+
+	// 停用后不再投递
+	inactive := false
+	_, err = svc.Update(ctx, webhook.ID, &models.WebhookUpdate{Active: &inactive})
+	require.NoError(t, err)
+	registerAndLogin(t, "webhook_off")
+	require.NoError(t, svc.Wait(waitCtx))
+	_, total, err = svc.ListDeliveries(ctx, webhook.ID, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
@@ -0,0 +1,40 @@
+package unit
+
+import (
+	"testing"
+
+	"enterprise-blog/pkg/ahocorasick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAhoCorasickFindAll(t *testing.T) {
+	a := ahocorasick.New([]string{"he", "she", "his", "hers", "", "敏感"})
+
+	// 重叠和互相包含的模式串都会命中，位置按 rune 计算
+	matches := a.FindAll([]rune("ushers"))
+	assert.Equal(t, []ahocorasick.Match{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+	}, matches)
+
+	assert.Equal(t, []ahocorasick.Match{{Pattern: 5, Start: 2, End: 4}}, a.FindAll([]rune("包含敏感词")))
+	assert.Empty(t, a.FindAll([]rune("nothing at all")))
+	assert.Empty(t, a.FindAll(nil))
+
+	// 区分大小写，由调用方统一转换
+	assert.Empty(t, a.FindAll([]rune("SHE")))
+}
+
+func TestAhoCorasickFailLinks(t *testing.T) {
+	// 失配后沿失配链继续，不漏掉以前缀开头的模式串
+	a := ahocorasick.New([]string{"abcd", "bc", "c"})
+	assert.Equal(t, []ahocorasick.Match{
+		{Pattern: 1, Start: 1, End: 3},
+		{Pattern: 2, Start: 2, End: 3},
+	}, a.FindAll([]rune("abce")))
+
+	// 没有任何模式串时不命中
+	assert.Empty(t, ahocorasick.New(nil).FindAll([]rune("abc")))
+}
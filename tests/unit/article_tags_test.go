@@ -0,0 +1,30 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagsNotFoundError(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	var err error = &services.TagsNotFoundError{IDs: []uuid.UUID{a, b}}
+
+	assert.True(t, errors.Is(err, services.ErrTagsNotFound))
+	assert.Contains(t, err.Error(), a.String())
+	assert.Contains(t, err.Error(), b.String())
+
+	// 经过服务层包装后仍能取出缺失的 ID
+	wrapped := fmt.Errorf("failed to create article: %w", fmt.Errorf("failed to add article tags: %w", err))
+	var missing *services.TagsNotFoundError
+	require.True(t, errors.As(wrapped, &missing))
+	assert.Equal(t, []uuid.UUID{a, b}, missing.IDs)
+
+	assert.False(t, errors.Is(services.ErrArticleVersionConflict, services.ErrTagsNotFound))
+}
@@ -0,0 +1,28 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArticleVersionConflictError(t *testing.T) {
+	current := &models.Article{Title: "server copy", Version: 4}
+	var err error = &services.ArticleVersionConflictError{Expected: 3, Current: current}
+
+	assert.True(t, errors.Is(err, services.ErrArticleVersionConflict))
+	assert.Contains(t, err.Error(), "expected version 3, current version 4")
+
+	// 包装后仍能取出服务端当前内容
+	wrapped := fmt.Errorf("update article: %w", err)
+	var conflict *services.ArticleVersionConflictError
+	assert.True(t, errors.As(wrapped, &conflict))
+	assert.Same(t, current, conflict.Current)
+
+	assert.False(t, errors.Is(services.ErrArticleVersionRequired, services.ErrArticleVersionConflict))
+}
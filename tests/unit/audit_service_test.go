@@ -0,0 +1,34 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactAuditPayload(t *testing.T) {
+	body := []byte(`{"username":"alice","password":"secret123","profile":{"api_token":"abc"},"items":[{"code":"123456"}]}`)
+
+	out := services.RedactAuditPayload(body, "application/json")
+
+	var data map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out, &data))
+	assert.Equal(t, "alice", data["username"])
+	assert.Equal(t, "[REDACTED]", data["password"])
+	assert.Equal(t, "[REDACTED]", data["profile"].(map[string]interface{})["api_token"])
+	assert.Equal(t, "[REDACTED]", data["items"].([]interface{})[0].(map[string]interface{})["code"])
+}
+
+func TestRedactAuditPayloadNonJSON(t *testing.T) {
+	out := services.RedactAuditPayload([]byte("binary-data"), "multipart/form-data")
+
+	var data map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out, &data))
+	assert.Equal(t, "multipart/form-data", data["content_type"])
+	assert.Equal(t, float64(11), data["size"])
+
+	assert.Nil(t, services.RedactAuditPayload(nil, "application/json"))
+}
@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlushCacheScopes(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+
+	keys := []string{
+		"blog:article:detail:1",
+		"blog:article:detail:2",
+		"blog:article:list:abc",
+		"blog:taxonomy:tags:published:default",
+		"blog:dashboard:overview",
+		"blog:stats:categories:x",
+		"blog:article:view:1",
+		"ratelimit:127.0.0.1",
+		"sms:code:13800000000",
+	}
+	for _, k := range keys {
+		require.NoError(t, mr.Set(k, "1"))
+	}
+
+	removed, err := services.FlushCache(ctx, services.CacheScopeArticleDetail)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed[services.CacheScopeArticleDetail])
+	assert.False(t, mr.Exists("blog:article:detail:1"))
+	assert.True(t, mr.Exists("blog:article:list:abc"))
+
+	removed, err = services.FlushCache(ctx, services.CacheScopeAllAppCaches)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), removed[services.CacheScopeArticleDetail])
+	assert.Equal(t, int64(2), removed[services.CacheScopeArticleList])
+	assert.False(t, mr.Exists("blog:taxonomy:tags:published:default"))
+	assert.Equal(t, int64(2), removed[services.CacheScopeDashboard])
+
+	// 计数缓冲、限流和短信验证码不受影响
+	assert.True(t, mr.Exists("blog:article:view:1"))
+	assert.True(t, mr.Exists("ratelimit:127.0.0.1"))
+	assert.True(t, mr.Exists("sms:code:13800000000"))
+}
+
+func TestFlushCacheInvalidScope(t *testing.T) {
+	setupMiniRedis(t)
+
+	_, err := services.FlushCache(context.Background(), "ratelimit")
+	assert.ErrorIs(t, err, services.ErrInvalidCacheScope)
+}
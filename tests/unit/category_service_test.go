@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryInUseError(t *testing.T) {
+	var err error = &services.CategoryInUseError{Articles: 500, Children: 2}
+
+	assert.True(t, errors.Is(err, services.ErrCategoryInUse))
+	assert.Contains(t, err.Error(), "500 articles")
+	assert.Contains(t, err.Error(), "2 child categories")
+
+	// 包装后仍能取出计数
+	wrapped := fmt.Errorf("delete category: %w", err)
+	var inUse *services.CategoryInUseError
+	assert.True(t, errors.As(wrapped, &inUse))
+	assert.Equal(t, int64(500), inUse.Articles)
+	assert.Equal(t, int64(2), inUse.Children)
+
+	assert.False(t, errors.Is(services.ErrInvalidReassignTarget, services.ErrCategoryInUse))
+}
+
+func newCategory(name string, order int, parent *uuid.UUID) *models.Category {
+	return &models.Category{ID: uuid.New(), Name: name, Order: order, ParentID: parent}
+}
+
+func TestBuildCategoryTreeNestingAndOrder(t *testing.T) {
+	tech := newCategory("Tech", 1, nil)
+	life := newCategory("Life", 0, nil)
+	golang := newCategory("Go", 2, &tech.ID)
+	rust := newCategory("Rust", 1, &tech.ID)
+	alpha := newCategory("Alpha", 2, &tech.ID)
+	missing := uuid.New()
+	orphan := newCategory("Orphan", 5, &missing)
+
+	tree := services.BuildCategoryTree([]*models.Category{golang, tech, rust, orphan, life, alpha})
+
+	require.Len(t, tree, 3)
+	assert.Equal(t, "Life", tree[0].Name)
+	assert.Equal(t, "Tech", tree[1].Name)
+	// 父分类不存在时作为根节点
+	assert.Equal(t, "Orphan", tree[2].Name)
+
+	// 同级按 order、名称排序
+	children := tree[1].Children
+	require.Len(t, children, 3)
+	assert.Equal(t, "Rust", children[0].Name)
+	assert.Equal(t, "Alpha", children[1].Name)
+	assert.Equal(t, "Go", children[2].Name)
+	assert.NotNil(t, children[0].Children)
+}
+
+func TestBuildCategoryTreeBreaksCycles(t *testing.T) {
+	a := newCategory("A", 0, nil)
+	b := newCategory("B", 1, &a.ID)
+	c := newCategory("C", 2, &b.ID)
+	a.ParentID = &c.ID // A -> C -> B -> A
+	self := newCategory("Self", 3, nil)
+	self.ParentID = &self.ID
+
+	tree := services.BuildCategoryTree([]*models.Category{c, self, b, a})
+
+	// 环上排序最靠前的 A 被提升为根节点，B、C 仍挂在其下
+	require.Len(t, tree, 2)
+	assert.Equal(t, "A", tree[0].Name)
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "B", tree[0].Children[0].Name)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, "C", tree[0].Children[0].Children[0].Name)
+	assert.Empty(t, tree[0].Children[0].Children[0].Children)
+
+	assert.Equal(t, "Self", tree[1].Name)
+	assert.Empty(t, tree[1].Children)
+}
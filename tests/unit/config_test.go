@@ -0,0 +1,338 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadConfigWithFile 写入临时配置文件并通过 CONFIG_FILE 加载，结束后恢复全局配置
+func loadConfigWithFile(t *testing.T, content string) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	return config.Load()
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	// 文件覆盖默认值，环境变量覆盖文件
+	t.Setenv("SERVER_PORT", "9090")
+	t.Setenv("REPORT_WEEKLY_RECIPIENTS", "ops@example.com, dev@example.com")
+	t.Setenv("SERVER_HOST", "")
+	t.Setenv("LOG_MODULE_LEVELS", "search=debug, database = warn")
+	t.Setenv("REDIS_READ_TIMEOUT_MS", "100")
+
+	err := loadConfigWithFile(t, `
+server:
+  host: 127.0.0.1
+  port: "8081"
+database:
+  max_open_conns: 20
+redis:
+  pool_size: 50
+  read_timeout_ms: 300
+elasticsearch:
+  request_timeout_ms: 2000
+upload:
+  max_size: 2048
+  allowed_exts:
+    - .png
+    - .svg
+report:
+  weekly_recipients: [editor@example.com]
+`)
+	require.NoError(t, err)
+	cfg := config.Get()
+
+	// 默认值
+	assert.Equal(t, "debug", cfg.Server.Mode)
+	assert.Equal(t, 10, cfg.Database.MaxIdleConns)
+	assert.False(t, cfg.Database.SkipDefaultTx)
+	// 文件
+	assert.Equal(t, "127.0.0.1", cfg.Server.Host)
+	assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+	assert.Equal(t, int64(2048), cfg.Upload.MaxSize)
+	assert.Equal(t, []string{".png", ".svg"}, cfg.Upload.AllowedExts)
+	// 环境变量
+	assert.Equal(t, "9090", cfg.Server.Port)
+	assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, cfg.Report.WeeklyRecipients)
+	assert.Equal(t, map[string]string{"search": "debug", "database": "warn"}, cfg.Log.ModuleLevels)
+	assert.Equal(t, 50, cfg.Redis.PoolSize)
+	assert.Equal(t, 100*time.Millisecond, cfg.Redis.ReadTimeout())
+	assert.Equal(t, 500*time.Millisecond, cfg.Redis.WriteTimeout())
+	assert.Equal(t, 2*time.Second, cfg.Elasticsearch.RequestTimeout())
+}
+
+func TestConfigFileErrorsNameKey(t *testing.T) {
+	cases := []struct {
+		name, content, key string
+	}{
+		{"type mismatch", "upload:\n  dir: ./uploads\n  max_size: banana\n", `"upload.max_size"`},
+		{"unknown key", "database:\n  hots: db.internal\n", `"database.hots"`},
+		{"list item", "report:\n  weekly_recipients:\n    - a@example.com\n    - {name: b}\n", `"report.weekly_recipients[1]"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := loadConfigWithFile(t, c.content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "config.yaml")
+			assert.Contains(t, err.Error(), c.key)
+		})
+	}
+}
+
+func TestConfigFileSyntaxError(t *testing.T) {
+	err := loadConfigWithFile(t, "server:\n  host: [unclosed\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config.yaml")
+}
+
+func TestConfigFileMissing(t *testing.T) {
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	err := config.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CONFIG_FILE")
+}
+
+// validConfig 通过校验的最小配置
+func validConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{
+			Port: "8080", Mode: "release",
+			PublicURL: "https://api.example.com", FrontendURL: "https://blog.example.com",
+		},
+		Database:   config.DatabaseConfig{Driver: "postgres", Port: "5432", Password: "secret"},
+		Redis:      config.RedisConfig{Port: "6379"},
+		JWT:        config.JWTConfig{Secret: "0123456789abcdef0123456789abcdef", RefreshExpireHours: 720},
+		Upload:     config.UploadConfig{Dir: filepath.Join(t.TempDir(), "uploads", "images")},
+		Webhook:    config.WebhookConfig{TimeoutMs: 1000, MaxAttempts: 1},
+		RateLimit:  config.RateLimitConfig{Requests: 100, WindowSeconds: 60, LikesPerMinute: 10, LoginMaxFailures: 5, LoginIPMaxFailures: 20, LoginWindowMinutes: 15},
+		Newsletter: config.NewsletterConfig{BatchSize: 100, SubscribeLimit: 5, ConfirmTTLHours: 48},
+		GraphQL:    config.GraphQLConfig{MaxDepth: 10, MaxComplexity: 1000},
+		I18n:       config.I18nConfig{DefaultLanguage: "zh-CN"},
+		Site:       config.SiteConfig{ContentFilterPolicy: "reject"},
+		Summarizer: config.SummarizerConfig{TimeoutMs: 10000, MaxInputTokens: 2000},
+		Health:     config.HealthConfig{TimeoutMs: 1000},
+		Pagination: config.PaginationConfig{
+			Articles: config.PageSizeLimits{Default: 10, Max: 100},
+			Comments: config.PageSizeLimits{Default: 20, Max: 100},
+			Images:   config.PageSizeLimits{Default: 20, Max: 100},
+			Users:    config.PageSizeLimits{Default: 10, Max: 100},
+			Other:    config.PageSizeLimits{Default: 20, Max: 100},
+		},
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, validConfig(t).Validate())
+
+	notDir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notDir, nil, 0o600))
+
+	cases := []struct {
+		name    string
+		mutate  func(c *config.Config)
+		problem string
+	}{
+		{"default jwt secret", func(c *config.Config) { c.JWT.Secret = "your-secret-key-change-in-production" }, "jwt.secret (JWT_SECRET): the default secret"},
+		{"short jwt secret", func(c *config.Config) { c.JWT.Secret = "short" }, "at least 32 characters"},
+		{"empty db password", func(c *config.Config) { c.Database.Password = "" }, "database.password (DB_PASSWORD)"},
+		{"invalid port", func(c *config.Config) { c.Server.Port = "80a" }, `server.port (SERVER_PORT): "80a" is not a valid port`},
+		{"port out of range", func(c *config.Config) { c.Redis.Port = "70000" }, "redis.port (REDIS_PORT)"},
+		{"upload dir is a file", func(c *config.Config) { c.Upload.Dir = notDir }, "is not a directory"},
+		{"invalid module level", func(c *config.Config) { c.Log.ModuleLevels = map[string]string{"search": "loud"} }, `log.module_levels.search (LOG_MODULE_LEVELS): "loud"`},
+		{"negative sample rate", func(c *config.Config) { c.Log.SampleRate = -1 }, "log.sample_rate (LOG_SAMPLE_RATE)"},
+		{"invalid sentry dsn", func(c *config.Config) { c.Sentry.DSN = "not a dsn" }, "sentry.dsn (SENTRY_DSN)"},
+		{"page size above max", func(c *config.Config) { c.Pagination.Comments.Default = 200 }, "pagination.comments.default (COMMENTS_PAGE_SIZE)"},
+		{"page size max above hard limit", func(c *config.Config) { c.Pagination.Images.Max = 500 }, "pagination.images.max (IMAGES_MAX_PAGE_SIZE): must be between 1 and 100"},
+		{"zero refresh token lifetime", func(c *config.Config) { c.JWT.RefreshExpireHours = 0 }, "jwt.refresh_expire_hours (JWT_REFRESH_EXPIRE_HOURS)"},
+		{"zero rate limit", func(c *config.Config) { c.RateLimit.Requests = 0 }, "rate_limit.requests (RATE_LIMIT_REQUESTS)"},
+		{"zero like rate limit", func(c *config.Config) { c.RateLimit.LikesPerMinute = 0 }, "rate_limit.likes_per_minute (RATE_LIMIT_LIKES_PER_MINUTE)"},
+		{"ip login limit below account limit", func(c *config.Config) { c.RateLimit.LoginIPMaxFailures = 3 }, "rate_limit.login_ip_max_failures (LOGIN_IP_MAX_FAILURES)"},
+		{"zero login window", func(c *config.Config) { c.RateLimit.LoginWindowMinutes = 0 }, "rate_limit.login_window_minutes (LOGIN_WINDOW_MINUTES)"},
+		{"zero health timeout", func(c *config.Config) { c.Health.TimeoutMs = 0 }, "health.timeout_ms (HEALTH_TIMEOUT_MS)"},
+		{"zero bucket window", func(c *config.Config) {
+			c.RateLimit.Buckets = map[string]config.RateLimitBucket{"sms_send": {Requests: 5}}
+		}, "rate_limit.buckets.sms_send.window_seconds"},
+		{"negative bucket requests", func(c *config.Config) {
+			c.RateLimit.Buckets = map[string]config.RateLimitBucket{"comment_create": {Requests: -1, WindowSeconds: 60}}
+		}, "rate_limit.buckets.comment_create.requests"},
+		{"invalid timezone", func(c *config.Config) { c.Timezone = "Mars/Olympus" }, "timezone (TIMEZONE)"},
+		{"invalid feature flag", func(c *config.Config) { c.FeatureFlags = map[string]string{"new_search": "150%"} }, "feature_flags.new_search (FEATURE_FLAGS)"},
+		{"relative public url", func(c *config.Config) { c.Server.PublicURL = "/api" }, `server.public_url (PUBLIC_URL): "/api"`},
+		{"invalid trusted proxy", func(c *config.Config) { c.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy.local"} }, `server.trusted_proxies (TRUSTED_PROXIES): "proxy.local"`},
+		{"invalid newsletter rate", func(c *config.Config) { c.Newsletter.ProviderRates = map[string]string{"smtp": "fast"} }, "newsletter.provider_rates.smtp (NEWSLETTER_PROVIDER_RATES)"},
+		{"graphql depth unset", func(c *config.Config) { c.GraphQL.MaxDepth = 0 }, "graphql.max_depth (GRAPHQL_MAX_DEPTH)"},
+		{"unsupported language", func(c *config.Config) { c.I18n.DefaultLanguage = "fr" }, "i18n.default_language (I18N_DEFAULT_LANGUAGE)"},
+		{"invalid content filter policy", func(c *config.Config) { c.Site.ContentFilterPolicy = "block" }, "site.content_filter_policy (CONTENT_FILTER_POLICY)"},
+		{"missing words file", func(c *config.Config) { c.ContentFilter.WordsFile = filepath.Join(t.TempDir(), "words.txt") }, "content_filter.words_file (CONTENT_FILTER_WORDS_FILE)"},
+		{"summarizer without model", func(c *config.Config) { c.Summarizer.BaseURL = "https://llm.example.com/v1" }, "summarizer.model (SUMMARIZER_MODEL)"},
+		{"negative redis pool size", func(c *config.Config) { c.Redis.PoolSize = -1 }, "redis.pool_size (REDIS_POOL_SIZE)"},
+		{"invalid redis retries", func(c *config.Config) { c.Redis.MaxRetries = -2 }, "redis.max_retries (REDIS_MAX_RETRIES)"},
+		{"missing elasticsearch ca cert", func(c *config.Config) {
+			c.Elasticsearch.Enabled, c.Elasticsearch.URL = true, "https://es.internal:9200"
+			c.Elasticsearch.CACert = filepath.Join(t.TempDir(), "missing.pem")
+		}, "elasticsearch.ca_cert (ELASTICSEARCH_CA_CERT)"},
+		{"elasticsearch without url", func(c *config.Config) { c.Elasticsearch.Enabled = true }, "elasticsearch.url (ELASTICSEARCH_URL)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			c.mutate(cfg)
+			err := cfg.Validate()
+
+			var validationErr *config.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Len(t, validationErr.Problems, 1)
+			assert.Contains(t, validationErr.Problems[0], c.problem)
+		})
+	}
+
+	// debug 模式允许默认密钥
+	cfg := validConfig(t)
+	cfg.Server.Mode = "debug"
+	cfg.JWT.Secret = "your-secret-key-change-in-production"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfigLoadReportsAllProblems(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SERVER_MODE", "release")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("MAX_UPLOAD_SIZE", "banana")
+	t.Setenv("SERVER_PORT", "0")
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+
+	err := config.Load()
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		`MAX_UPLOAD_SIZE: "banana" is not a valid integer`,
+		"jwt.secret (JWT_SECRET): the default secret must not be used in release mode",
+		`server.port (SERVER_PORT): "0" is not a valid port`,
+	}, validationErr.Problems)
+	// 无法解析的值不会覆盖默认值
+	assert.Equal(t, int64(10485760), config.Get().Upload.MaxSize)
+}
+
+func TestConfigReload(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	writeConfig("server:\n  port: \"8080\"\nlog:\n  level: info\n")
+	t.Setenv("CONFIG_FILE", path)
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	require.NoError(t, config.Load())
+
+	writeConfig(`
+server:
+  port: "9090"
+database:
+  host: db.internal
+log:
+  level: warn
+cors:
+  allowed_origins: [https://blog.example.com]
+rate_limit:
+  requests: 20
+`)
+	result, err := config.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cors.allowed_origins", "log.level", "rate_limit.requests"}, result.Applied)
+	assert.Equal(t, []string{"database.host", "server.port"}, result.Skipped)
+
+	cfg := config.Get()
+	assert.Equal(t, "warn", cfg.Log.Level)
+	assert.Equal(t, []string{"https://blog.example.com"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, 20, cfg.RateLimit.Requests)
+	// 需要重启的配置保持旧值
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, "localhost", cfg.Database.Host)
+
+	// 配置无效时不做任何修改
+	writeConfig("log:\n  level: debug\nrate_limit:\n  requests: 0\n")
+	_, err = config.Reload()
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Same(t, cfg, config.Get())
+	assert.Equal(t, "warn", config.Get().Log.Level)
+
+	// 限流桶字段拼错或缺失时同样拒绝，而不是生成拒绝全部请求的限流器
+	writeConfig("rate_limit:\n  buckets:\n    sms_send:\n      request: 10\n      window_seconds: 3600\n")
+	_, err = config.Reload()
+	require.ErrorContains(t, err, `"rate_limit.buckets.sms_send.request"`)
+	assert.Same(t, cfg, config.Get())
+	writeConfig("rate_limit:\n  buckets:\n    sms_send:\n      window_seconds: 3600\n    comment_create:\n      requests: 10\n")
+	_, err = config.Reload()
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		"rate_limit.buckets.comment_create.window_seconds: must be at least 1",
+		"rate_limit.buckets.sms_send.requests: must be at least 1",
+	}, validationErr.Problems)
+	assert.Same(t, cfg, config.Get())
+}
+
+// 重新加载与请求中的读取并发执行（配合 make test-race 检查数据竞争）
+func TestConfigReloadConcurrentWithReaders(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("cors:\n  allowed_origins: [https://a.example.com]\n"), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	require.NoError(t, config.Load())
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				assert.Len(t, config.Get().CORS.AllowedOrigins, 1)
+				config.PageLimits(config.PageArticles)
+				config.Location()
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		_, err := config.Reload()
+		require.NoError(t, err)
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestStartOfDayUsesConfiguredTimezone(t *testing.T) {
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	config.Set(&config.Config{Timezone: "Asia/Shanghai"})
+
+	// UTC 2024-01-07 17:30 已是北京时间 1 月 8 日
+	start := config.StartOfDay(time.Date(2024, 1, 7, 17, 30, 0, 0, time.UTC))
+	assert.Equal(t, "2024-01-08T00:00:00+08:00", start.Format(time.RFC3339))
+	assert.Equal(t, time.Date(2024, 1, 7, 16, 0, 0, 0, time.UTC), start.UTC())
+
+	config.Set(nil)
+	assert.Equal(t, time.UTC, config.Location())
+}
@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"enterprise-blog/pkg/cron"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNextWeekly(t *testing.T) {
+	s, err := cron.Parse("0 8 * * 1")
+	require.NoError(t, err)
+
+	// 2024-01-03 是周三
+	from := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC), s.Next(from))
+
+	// 恰好在触发时间时返回下一次
+	at := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC), s.Next(at))
+}
+
+func TestCronNextStepsAndLists(t *testing.T) {
+	s, err := cron.Parse("*/15 9-17 * * 1-5")
+	require.NoError(t, err)
+
+	// 周五 17:50 之后下一次是周一 09:00
+	from := time.Date(2024, 1, 5, 17, 50, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC), s.Next(from))
+
+	s, err = cron.Parse("30 6 1,15 * *")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 6, 30, 0, 0, time.UTC), s.Next(time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)))
+
+	// 7 也表示周日
+	s, err = cron.Parse("0 0 * * 7")
+	require.NoError(t, err)
+	assert.Equal(t, time.Sunday, s.Next(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)).Weekday())
+}
+
+func TestCronParseInvalid(t *testing.T) {
+	for _, spec := range []string{"", "0 8 * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := cron.Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
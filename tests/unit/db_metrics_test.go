@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"enterprise-blog/internal/database"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestParseSQLLabels(t *testing.T) {
+	cases := []struct {
+		sql, operation, table string
+	}{
+		{"SELECT id, title FROM articles a WHERE a.id = $1", "select", "articles"},
+		{"\n\t\tSELECT COUNT(*) FROM articles a WHERE a.deleted_at IS NULL", "select", "articles"},
+		{`INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, "insert", "article_tags"},
+		{`UPDATE articles SET title = $1, updated_at = $2 WHERE id = $3`, "update", "articles"},
+		{`DELETE FROM ONLY "public"."Comments" WHERE id = ?`, "delete", "comments"},
+		{"SELECT COUNT(*) FROM (SELECT article_id FROM article_view_daily GROUP BY article_id) t", "select", "article_view_daily"},
+		{"SELECT 'from users' AS note, x FROM settings", "select", "settings"},
+		{"-- from a comment\nSELECT * FROM tags /* from images */", "select", "tags"},
+		{"WITH moved AS (SELECT id FROM categories) UPDATE articles SET category_id = NULL", "update", "categories"},
+		{"SELECT pg_try_advisory_lock($1)", "select", "unknown"},
+		{"SET LOCAL enable_seqscan = off", "other", "unknown"},
+		{"", "other", "unknown"},
+	}
+	for _, c := range cases {
+		operation, table := database.ParseSQLLabels(c.sql)
+		assert.Equal(t, c.operation, operation, c.sql)
+		assert.Equal(t, c.table, table, c.sql)
+	}
+}
+
+// newDryRunDB 不连接数据库、只生成 SQL 的 GORM 实例
+func newDryRunDB(t testing.TB, withMetrics bool) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	require.NoError(t, err)
+	if withMetrics {
+		require.NoError(t, db.Use(database.MetricsPlugin{}))
+	}
+	return db
+}
+
+// metricValue 读取默认注册表中指定标签的计数器值或直方图样本数
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	next:
+		for _, m := range family.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if labels[lp.GetName()] != lp.GetValue() {
+					continue next
+				}
+			}
+			if m.GetCounter() != nil {
+				return m.GetCounter().GetValue()
+			}
+			return float64(m.GetHistogram().GetSampleCount())
+		}
+	}
+	return 0
+}
+
+func TestMetricsPluginRecordsRawQueries(t *testing.T) {
+	db := newDryRunDB(t, true)
+	labels := map[string]string{"operation": "update", "table": "articles"}
+	before := metricValue(t, "db_queries_total", labels)
+	beforeHist := metricValue(t, "db_query_duration_seconds", labels)
+
+	db.Exec("UPDATE articles SET like_count = like_count + 1 WHERE id = $1", 1)
+
+	assert.Equal(t, before+1, metricValue(t, "db_queries_total", labels))
+	assert.Equal(t, beforeHist+1, metricValue(t, "db_query_duration_seconds", labels))
+
+	var count int64
+	selectLabels := map[string]string{"operation": "select", "table": "tags"}
+	before = metricValue(t, "db_queries_total", selectLabels)
+	db.Raw("SELECT COUNT(*) FROM tags WHERE deleted_at IS NULL").Scan(&count)
+	assert.Equal(t, before+1, metricValue(t, "db_queries_total", selectLabels))
+}
+
+func TestMetricsPluginCountsErrors(t *testing.T) {
+	db := newDryRunDB(t, true)
+	labels := map[string]string{"operation": "delete", "table": "images"}
+	before := metricValue(t, "db_query_errors_total", labels)
+
+	db.Callback().Raw().Before("metrics:after_raw").Register("test:fail", func(tx *gorm.DB) {
+		_ = tx.AddError(errors.New("boom"))
+	})
+	db.Exec("DELETE FROM images WHERE id = $1", 1)
+
+	assert.Equal(t, before+1, metricValue(t, "db_query_errors_total", labels))
+}
+
+const benchmarkSQL = `
+		SELECT a.id, a.title, a.slug, a.content, a.excerpt, a.cover_image, a.status,
+			   a.author_id, a.category_id, a.view_count, a.like_count, a.comment_count,
+			   a.published_at, a.created_at, a.updated_at, a.version
+		FROM articles a
+		WHERE a.deleted_at IS NULL AND a.status = ?
+		ORDER BY a.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+func BenchmarkParseSQLLabels(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		database.ParseSQLLabels(benchmarkSQL)
+	}
+}
+
+// BenchmarkRawQuery 对比注册插件前后一次 Raw 查询在 GORM 中的开销（DryRun，不含网络往返）
+func BenchmarkRawQuery(b *testing.B) {
+	for _, withMetrics := range []bool{false, true} {
+		name := "without_metrics"
+		if withMetrics {
+			name = "with_metrics"
+		}
+		b.Run(name, func(b *testing.B) {
+			db := newDryRunDB(b, withMetrics)
+			var ids []int64
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				db.Raw(benchmarkSQL, "published", 10, 0).Scan(&ids)
+			}
+		})
+	}
+}
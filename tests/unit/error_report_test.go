@@ -0,0 +1,160 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/pkg/errorreport"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportedEvent 记录的一次上报
+type reportedEvent struct {
+	err       error
+	recovered interface{}
+	tags      map[string]string
+}
+
+// recordingReporter 记录上报内容的 ErrorReporter
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []reportedEvent
+}
+
+func (r *recordingReporter) CaptureError(ctx context.Context, err error, tags map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, reportedEvent{err: err, tags: errorreport.ContextTags(ctx, tags)})
+}
+
+func (r *recordingReporter) CapturePanic(ctx context.Context, recovered interface{}, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, reportedEvent{recovered: recovered, tags: errorreport.ContextTags(ctx, nil)})
+}
+
+func (r *recordingReporter) Flush(time.Duration) bool { return true }
+
+func (r *recordingReporter) recorded() []reportedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]reportedEvent(nil), r.events...)
+}
+
+var addLogHookOnce sync.Once
+
+// useRecordingReporter 替换全局错误上报并注册日志 hook，结束后恢复
+func useRecordingReporter(t *testing.T) *recordingReporter {
+	t.Helper()
+	addLogHookOnce.Do(func() { logger.AddHook(errorreport.LogHook{}) })
+	reporter := &recordingReporter{}
+	previous := errorreport.SetReporter(reporter)
+	t.Cleanup(func() { errorreport.SetReporter(previous) })
+	return reporter
+}
+
+func TestRecoveryMiddlewareReportsPanic(t *testing.T) {
+	initFileLogger(t, logger.Options{Level: "info"})
+	reporter := useRecordingReporter(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.RecoveryMiddleware())
+	router.GET("/articles/:id", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-panic")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 只上报 panic 本身，恢复时记录的错误日志不重复上报
+	events := reporter.recorded()
+	require.Len(t, events, 1)
+	assert.Equal(t, "boom", events[0].recovered)
+	assert.Equal(t, "req-panic", events[0].tags["request_id"])
+	assert.Equal(t, "/articles/:id", events[0].tags["route"])
+}
+
+func TestLogHookReportsErrorLogs(t *testing.T) {
+	initFileLogger(t, logger.Options{Level: "debug"})
+	reporter := useRecordingReporter(t)
+
+	ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "user-1")
+	l := logger.FromContext(ctx)
+	l.Warn().Msg("just a warning")
+	l.Error().Err(errors.New("db down")).Msg("failed to load settings")
+	skipped := logger.FromContext(errorreport.WithoutLogReport(ctx))
+	skipped.Error().Msg("already reported")
+
+	events := reporter.recorded()
+	require.Len(t, events, 1)
+	assert.EqualError(t, events[0].err, "failed to load settings")
+	assert.Equal(t, "req-1", events[0].tags["request_id"])
+	assert.Equal(t, "user-1", events[0].tags["user_id"])
+	assert.Equal(t, "log", events[0].tags["source"])
+}
+
+// recordingTransport 记录 Sentry 事件而不发送
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Flush(time.Duration) bool       { return true }
+func (t *recordingTransport) Configure(sentry.ClientOptions) {}
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func TestSentryReporter(t *testing.T) {
+	transport := &recordingTransport{}
+	reporter, err := errorreport.NewSentryReporter(errorreport.SentryOptions{
+		DSN:         "https://public@sentry.example.com/1",
+		Environment: "test",
+		Release:     "v1.2.3",
+		Transport:   transport,
+	})
+	require.NoError(t, err)
+
+	ctx := logger.WithRoute(logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "user-1"), "/api/v1/articles")
+	reporter.CaptureError(ctx, errors.New("redis timeout"), map[string]string{"worker": "counter_flush"})
+	reporter.CapturePanic(context.Background(), "boom", []byte("goroutine 1"))
+
+	require.Len(t, transport.events, 2)
+	event := transport.events[0]
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "v1.2.3", event.Release)
+	assert.Equal(t, "redis timeout", event.Exception[len(event.Exception)-1].Value)
+	assert.Equal(t, "user-1", event.User.ID)
+	assert.Equal(t, map[string]string{
+		"request_id": "req-1",
+		"user_id":    "user-1",
+		"route":      "/api/v1/articles",
+		"worker":     "counter_flush",
+	}, event.Tags)
+
+	panicEvent := transport.events[1]
+	assert.Equal(t, sentry.LevelFatal, panicEvent.Level)
+	assert.Equal(t, "boom", panicEvent.Message)
+	assert.Equal(t, "panic", panicEvent.Tags["source"])
+
+	_, err = errorreport.NewSentryReporter(errorreport.SentryOptions{DSN: "not a dsn"})
+	assert.Error(t, err)
+}
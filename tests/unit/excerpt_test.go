@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateExcerpt_ShortContent(t *testing.T) {
+	assert.Equal(t, "Hello world", services.GenerateExcerpt("Hello world", 200))
+	assert.Equal(t, "", services.GenerateExcerpt("", 200))
+	// 恰好等于上限时不追加省略号
+	assert.Equal(t, "你好世界", services.GenerateExcerpt("你好世界", 4))
+	// maxRunes <= 0 表示不截断
+	assert.Equal(t, strings.Repeat("a ", 99)+"a", services.GenerateExcerpt(strings.Repeat("a ", 100), 0))
+}
+
+func TestGenerateExcerpt_Chinese(t *testing.T) {
+	content := strings.Repeat("企业博客系统支持中文内容。", 30)
+	excerpt := services.GenerateExcerpt(content, 20)
+
+	assert.True(t, utf8.ValidString(excerpt))
+	assert.Equal(t, "企业博客系统支持中文内容。企业博客系统支...", excerpt)
+}
+
+func TestGenerateExcerpt_Emoji(t *testing.T) {
+	content := strings.Repeat("🎉😀", 50)
+	excerpt := services.GenerateExcerpt(content, 5)
+
+	assert.True(t, utf8.ValidString(excerpt))
+	assert.Equal(t, "🎉😀🎉😀🎉...", excerpt)
+	// 由多个码点组成的 emoji 也不会切出非法字节
+	assert.True(t, utf8.ValidString(services.GenerateExcerpt(strings.Repeat("👨‍👩‍👧", 20), 7)))
+}
+
+func TestGenerateExcerpt_WordBoundary(t *testing.T) {
+	// 截断位置在单词中间时回退到上一个空格
+	assert.Equal(t, "The quick brown...", services.GenerateExcerpt("The quick brown fox jumps over the lazy dog", 18))
+	// 恰好在单词结尾截断时保留该单词，去掉结尾标点
+	assert.Equal(t, "The quick brown fox...", services.GenerateExcerpt("The quick brown fox, jumps over the lazy dog", 20))
+	// 整段没有空格时按字符截断
+	assert.Equal(t, "abcdefghij...", services.GenerateExcerpt(strings.Repeat("abcdefghij", 5), 10))
+	// 中英混排：中文部分直接截断
+	assert.Equal(t, "使用Go开发...", services.GenerateExcerpt("使用Go开发企业博客", 6))
+}
+
+func TestGenerateExcerpt_StripsMarkup(t *testing.T) {
+	content := "# 标题\n\n这是 **加粗** 和 *斜体*，以及 `code` 与 [链接](https://example.com)。\n\n" +
+		"![图片](/uploads/a.png)\n\n```go\nfmt.Println(\"hidden\")\n```\n\n> 引用内容\n\n- 列表项\n1. 第一项\n\n---\n\n" +
+		"<p>HTML <strong>段落</strong> &amp; 实体</p>\nsnake_case_name 保持不变"
+
+	assert.Equal(t,
+		"标题 这是 加粗 和 斜体，以及 code 与 链接。 引用内容 列表项 第一项 HTML 段落 & 实体 snake_case_name 保持不变",
+		services.GenerateExcerpt(content, 0))
+	// 只去掉成对的标记，敏感词替换出的 * 保留
+	assert.Equal(t, "这是***示例 删除线", services.GenerateExcerpt("这是***示例 ~~删除线~~", 0))
+	// 先去掉标记再计算长度
+	assert.Equal(t, "标题 这是...", services.GenerateExcerpt(content, 5))
+}
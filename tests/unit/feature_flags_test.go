@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/flags"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeatureFlag(t *testing.T) {
+	cases := []struct {
+		value   string
+		enabled bool
+		percent int
+	}{
+		{"on", true, 100},
+		{"TRUE", true, 100},
+		{"off", false, 100},
+		{"25%", true, 25},
+		{" 40 ", true, 40},
+		{"0", true, 0},
+	}
+	for _, c := range cases {
+		enabled, percent, err := config.ParseFeatureFlag(c.value)
+		require.NoError(t, err, c.value)
+		assert.Equal(t, c.enabled, enabled, c.value)
+		assert.Equal(t, c.percent, percent, c.value)
+	}
+
+	for _, value := range []string{"", "maybe", "101%", "-5"} {
+		_, _, err := config.ParseFeatureFlag(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFeatureFlagsFromConfig(t *testing.T) {
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	config.Set(&config.Config{FeatureFlags: map[string]string{
+		models.FlagNewSearch:       "on",
+		models.FlagCommentMarkdown: "30%",
+	}})
+
+	anonymous := context.Background()
+	assert.True(t, flags.Enabled(anonymous, models.FlagNewSearch))
+	assert.False(t, flags.Enabled(anonymous, models.FlagCookieAuth), "code default is off")
+	assert.False(t, flags.Enabled(anonymous, "no_such_flag"))
+	assert.False(t, flags.Enabled(anonymous, models.FlagCommentMarkdown), "anonymous requests are outside a partial rollout")
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		ctx := logger.WithUserID(context.Background(), uuid.NewString())
+		first := flags.Enabled(ctx, models.FlagCommentMarkdown)
+		assert.Equal(t, first, flags.Enabled(ctx, models.FlagCommentMarkdown), "evaluation must be stable per user")
+		if first {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+}
@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/graph"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 嵌套过深或不支持的查询在执行前被拒绝，不会调用服务（这里的服务均为 nil）
+// 复杂度在执行过程中统计，见 integration.TestGraphQL_QueryLimits
+func TestGraphQLRejectsQueriesOverLimits(t *testing.T) {
+	server := graph.NewServer(nil, nil, nil, nil, nil, config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 200})
+	ctx := context.Background()
+
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		problem   string
+	}{
+		{
+			name:    "nested too deep",
+			query:   `{ categories { children { children { children { children { id } } } } } }`,
+			problem: "exceeds max depth",
+		},
+		{
+			name:    "mutations are not supported",
+			query:   `mutation { deleteArticle(id: "1") }`,
+			problem: "mutation",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := server.Exec(ctx, &graph.Request{Query: tc.query, Variables: tc.variables})
+			require.NotEmpty(t, resp.Errors)
+			assert.Contains(t, resp.Errors[0].Message, tc.problem)
+			assert.Empty(t, resp.Data)
+		})
+	}
+}
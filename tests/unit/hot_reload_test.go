@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/internal/services"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSPolicySetAllowedOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policy := middleware.NewCORSPolicy(nil)
+	router := gin.New()
+	router.Use(policy.Middleware())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	allowOrigin := func(origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal(t, "http://localhost:3000", allowOrigin("http://localhost:3000"))
+	assert.Empty(t, allowOrigin("https://blog.example.com"))
+
+	policy.SetAllowedOrigins([]string{"https://blog.example.com"})
+	assert.Equal(t, "https://blog.example.com", allowOrigin("https://blog.example.com"))
+	// 本地开发地址始终允许
+	assert.Equal(t, "http://localhost:3000", allowOrigin("http://localhost:3000"))
+}
+
+func TestRateLimiterSetLimit(t *testing.T) {
+	setupMiniRedis(t)
+	gin.SetMode(gin.TestMode)
+	limiter := middleware.NewRateLimiter(1, time.Minute)
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	status := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, status())
+	assert.Equal(t, http.StatusTooManyRequests, status())
+
+	// 提高限额后已有计数继续累计
+	limiter.SetLimit(3, time.Minute)
+	assert.Equal(t, http.StatusOK, status())
+	assert.Equal(t, http.StatusOK, status())
+	assert.Equal(t, http.StatusTooManyRequests, status())
+}
+
+func TestReloadServiceAppliesCallbacks(t *testing.T) {
+	initFileLogger(t, logger.Options{Level: "info"})
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: info\n"), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+	previous := config.Get()
+	t.Cleanup(func() { config.Set(previous) })
+	require.NoError(t, config.Load())
+
+	reloadService := services.NewReloadService()
+	var applied *config.Config
+	reloadService.OnReload(func(cfg *config.Config) error {
+		applied = cfg
+		return logger.SetLevels(cfg.Log.Level, cfg.Log.ModuleLevels)
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: error\nserver:\n  port: \"9999\"\n"), 0o600))
+	result, err := reloadService.Reload(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log.level"}, result.Applied)
+	assert.Equal(t, []string{"server.port"}, result.Skipped)
+	require.NotNil(t, applied)
+	assert.Equal(t, "error", applied.Log.Level)
+
+	l := logger.GetLogger()
+	assert.False(t, l.Warn().Enabled())
+	assert.True(t, l.Error().Enabled())
+}
@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"testing"
+
+	"enterprise-blog/internal/i18n"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestI18nNegotiate(t *testing.T) {
+	cases := []struct {
+		header   string
+		fallback string
+		want     string
+	}{
+		{"", i18n.EN, i18n.EN},
+		{"", "", i18n.ZhCN},
+		{"*", i18n.EN, i18n.EN},
+		{"en-US,en;q=0.9,zh-CN;q=0.8", i18n.ZhCN, i18n.EN},
+		{"fr-FR,zh;q=0.5,en;q=0.4", i18n.EN, i18n.ZhCN},
+		{"zh-TW;q=0.3, EN-gb;q=0.7", i18n.ZhCN, i18n.EN},
+		{"en;q=0.5,zh-Hans;q=0.5", i18n.ZhCN, i18n.EN},
+		{"de, ja", i18n.EN, i18n.EN},
+		{"en;q=0, zh_CN", i18n.EN, i18n.ZhCN},
+		{"en;q=abc", i18n.ZhCN, i18n.ZhCN},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, i18n.Negotiate(tc.header, tc.fallback), "Accept-Language: %q", tc.header)
+	}
+}
+
+func TestI18nCatalog(t *testing.T) {
+	assert.Equal(t, "邮箱或密码错误", i18n.T(i18n.ZhCN, i18n.CodeInvalidCredentials))
+	assert.Equal(t, "Invalid email or password", i18n.T(i18n.EN, i18n.CodeInvalidCredentials))
+	assert.Equal(t, "Invalid email or password", i18n.T("en-US", i18n.CodeInvalidCredentials))
+	// 不支持的语言使用中文，未登记的错误码原样返回
+	assert.Equal(t, "邮箱或密码错误", i18n.T("fr", i18n.CodeInvalidCredentials))
+	assert.Equal(t, "no_such_code", i18n.T(i18n.EN, "no_such_code"))
+}
+
+func TestI18nValidatorTranslation(t *testing.T) {
+	v, err := i18n.NewValidator(validator.New())
+	require.NoError(t, err)
+
+	type request struct {
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required,min=6"`
+	}
+	verr := v.Struct(&request{Email: "not-an-email", Password: "123"})
+	require.Error(t, verr)
+
+	en, ok := v.Translate(i18n.EN, verr)
+	require.True(t, ok)
+	assert.Equal(t, "email must be a valid email address; password must be at least 6 characters in length", en)
+
+	zh, ok := v.Translate(i18n.ZhCN, verr)
+	require.True(t, ok)
+	assert.Contains(t, zh, "email必须是一个有效的邮箱")
+	assert.Contains(t, zh, "password长度必须至少为6个字符")
+
+	// 非校验错误不翻译
+	_, ok = v.Translate(i18n.EN, assert.AnError)
+	assert.False(t, ok)
+
+	// 每个校验器使用独立的翻译器，可以重复创建
+	_, err = i18n.NewValidator(validator.New())
+	assert.NoError(t, err)
+}
+
+func TestI18nValidatorFieldErrors(t *testing.T) {
+	v, err := i18n.NewValidator(validator.New())
+	require.NoError(t, err)
+
+	type item struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+		Title string `form:"title" validate:"max=3"`
+		Items []item `json:"items" validate:"dive"`
+	}
+	verr := v.Struct(&request{Email: "not-an-email", Title: "too long", Items: []item{{Name: "ok"}, {}}})
+	require.Error(t, verr)
+
+	fieldErrs, ok := v.FieldErrors(i18n.EN, verr)
+	require.True(t, ok)
+	assert.Equal(t, []i18n.FieldError{
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "title", Rule: "max", Message: "title must be a maximum of 3 characters in length"},
+		{Field: "items[1].name", Rule: "required", Message: "name is a required field"},
+	}, fieldErrs)
+
+	_, ok = v.FieldErrors(i18n.EN, assert.AnError)
+	assert.False(t, ok)
+}
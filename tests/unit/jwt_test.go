@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"enterprise-blog/pkg/jwt"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationToken(t *testing.T) {
+	mgr := jwt.NewJWTManager("test-secret", 24*time.Hour)
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	token, err := mgr.GenerateImpersonationToken(userID, "reader", "reader", adminID, 30*time.Minute)
+	require.NoError(t, err)
+
+	claims, err := mgr.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	require.NotNil(t, claims.ImpersonatorID)
+	assert.Equal(t, adminID, *claims.ImpersonatorID)
+	// 模拟登录 token 使用调用方指定的短有效期，而不是默认有效期
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), claims.ExpiresAt.Time, time.Minute)
+}
+
+func TestRegularTokenHasNoImpersonator(t *testing.T) {
+	mgr := jwt.NewJWTManager("test-secret", time.Hour)
+
+	token, err := mgr.GenerateToken(uuid.New(), "admin", "admin")
+	require.NoError(t, err)
+
+	claims, err := mgr.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Nil(t, claims.ImpersonatorID)
+}
+
+func TestRefreshToken(t *testing.T) {
+	mgr := jwt.NewJWTManager("test-secret", time.Hour).WithRefreshTTL(48 * time.Hour)
+	userID := uuid.New()
+
+	token, claims, err := mgr.GenerateRefreshToken(userID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	parsed, err := mgr.ValidateRefreshToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, parsed.UserID)
+	assert.Equal(t, claims.ID, parsed.ID)
+
+	// 刷新 token 不能作为访问 token 使用，反之亦然
+	_, err = mgr.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrWrongTokenType)
+	access, err := mgr.GenerateToken(userID, "reader", "reader")
+	require.NoError(t, err)
+	_, err = mgr.ValidateRefreshToken(access)
+	assert.ErrorIs(t, err, jwt.ErrWrongTokenType)
+}
+
+func TestPreviewToken(t *testing.T) {
+	mgr := jwt.NewJWTManager("test-secret", time.Hour)
+	articleID := uuid.New()
+	authorID := uuid.New()
+
+	token, claims, err := mgr.GeneratePreviewToken(articleID, authorID, 10*time.Minute)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt.Time, time.Minute)
+
+	parsed, err := mgr.ValidatePreviewToken(token)
+	require.NoError(t, err)
+	require.NotNil(t, parsed.ArticleID)
+	assert.Equal(t, articleID, *parsed.ArticleID)
+	assert.Equal(t, claims.ID, parsed.ID)
+
+	// 预览 token 不能作为访问 token 使用，访问 token 也不能用于预览
+	_, err = mgr.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrWrongTokenType)
+	access, err := mgr.GenerateToken(authorID, "author", "author")
+	require.NoError(t, err)
+	_, err = mgr.ValidatePreviewToken(access)
+	assert.ErrorIs(t, err, jwt.ErrWrongTokenType)
+
+	// 其他密钥签名或已过期的 token 无效
+	_, err = jwt.NewJWTManager("other-secret", time.Hour).ValidatePreviewToken(token)
+	assert.Error(t, err)
+	expired, _, err := mgr.GeneratePreviewToken(articleID, authorID, -time.Minute)
+	require.NoError(t, err)
+	_, err = mgr.ValidatePreviewToken(expired)
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/handlers"
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLikeRateLimiter(t *testing.T) {
+	mr := setupMiniRedis(t)
+	gin.SetMode(gin.TestMode)
+	limiter := middleware.NewRateLimiter(2, time.Minute).WithKey(handlers.LikeRateLimitKey)
+	liked := 0
+	router := gin.New()
+	router.POST("/articles/:id/like", limiter.Middleware(), func(c *gin.Context) {
+		liked++
+		c.Status(http.StatusOK)
+	})
+
+	like := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles/"+id+"/like", nil))
+		return w
+	}
+
+	id := uuid.New()
+	assert.Equal(t, http.StatusOK, like(id.String()).Code)
+	// 大写形式计为同一篇文章
+	assert.Equal(t, http.StatusOK, like(strings.ToUpper(id.String())).Code)
+	w := like(id.String())
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.True(t, retryAfter >= 1 && retryAfter <= 60, "Retry-After %d", retryAfter)
+	// 被拒绝的请求不会到达处理器
+	assert.Equal(t, 2, liked)
+
+	// 其他文章单独计数
+	assert.Equal(t, http.StatusOK, like(uuid.NewString()).Code)
+
+	// 窗口过期后恢复
+	mr.FastForward(time.Minute)
+	assert.Equal(t, http.StatusOK, like(id.String()).Code)
+}
+
+func TestAuthSwitch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtMgr := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour)
+	authSwitch := middleware.NewAuthSwitch(false)
+	router := gin.New()
+	router.POST("/like", authSwitch.Middleware(jwtMgr), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	status := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/like", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	token, err := jwtMgr.GenerateToken(uuid.New(), "liker", "user")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, status(""))
+	assert.Equal(t, http.StatusUnauthorized, status("invalid"))
+
+	authSwitch.SetRequired(true)
+	assert.Equal(t, http.StatusUnauthorized, status(""))
+	assert.Equal(t, http.StatusOK, status(token))
+}
@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"enterprise-blog/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initFileLogger 初始化写入临时目录的日志，控制台输出丢弃，结束后恢复为仅控制台
+func initFileLogger(t *testing.T, opts logger.Options) string {
+	t.Helper()
+	dir := t.TempDir()
+	opts.File = filepath.Join(dir, "app.log")
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = devNull
+	err = logger.InitWithOptions(opts)
+	os.Stdout = stdout
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		logger.Init("error", "")
+		devNull.Close()
+	})
+	return dir
+}
+
+// readLogLines 读取目录下全部日志文件（含轮转后的历史文件）的 JSON 行
+func readLogLines(t *testing.T, dir string) []map[string]interface{} {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var lines []map[string]interface{}
+	for _, entry := range entries {
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var line map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line), "interleaved log line in %s", entry.Name())
+			lines = append(lines, line)
+		}
+		require.NoError(t, scanner.Err())
+		f.Close()
+	}
+	return lines
+}
+
+func TestLoggerRotatesWithoutLosingConcurrentWrites(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info", MaxSizeMB: 1})
+
+	const writers, perWriter = 8, 200
+	payload := strings.Repeat("x", 1024)
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			l := logger.GetLogger()
+			for i := 0; i < perWriter; i++ {
+				l.Info().Int("writer", w).Int("seq", i).Str("payload", payload).Msg("rotation test")
+				// 轮转过程中同时有重新打开（SIGUSR1）的请求
+				if w == 0 && i%50 == 0 {
+					assert.NoError(t, logger.Reopen())
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	files, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, files, "expected rotated backups for ~1.6MB of logs with a 1MB limit")
+
+	lines := readLogLines(t, dir)
+	require.Len(t, lines, writers*perWriter)
+	seen := make(map[[2]int]bool, len(lines))
+	for _, line := range lines {
+		seen[[2]int{int(line["writer"].(float64)), int(line["seq"].(float64))}] = true
+	}
+	assert.Len(t, seen, writers*perWriter)
+}
+
+func TestLoggerMaxBackups(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info", MaxSizeMB: 1, MaxBackups: 1})
+
+	l := logger.GetLogger()
+	payload := strings.Repeat("y", 1024)
+	for i := 0; i < 3500; i++ {
+		l.Info().Str("payload", payload).Msg("backup test")
+	}
+
+	// 历史文件在后台清理
+	assert.Eventually(t, func() bool {
+		files, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+		return len(files) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestLoggerModuleLevels(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info", ModuleLevels: map[string]string{"search": "debug", "database": "warn"}})
+
+	root := logger.GetLogger()
+	root.Debug().Msg("root debug")
+	root.Info().Msg("root info")
+	search := logger.GetLogger("search")
+	search.Debug().Msg("search debug")
+	database := logger.GetLogger("database")
+	database.Info().Msg("database info")
+	database.Warn().Msg("database warn")
+	other := logger.GetLogger("cache")
+	other.Debug().Msg("cache debug")
+	other.Info().Msg("cache info")
+
+	var messages []string
+	for _, line := range readLogLines(t, dir) {
+		messages = append(messages, line["message"].(string))
+	}
+	assert.Equal(t, []string{"root info", "search debug", "database warn", "cache info"}, messages)
+}
+
+func TestLoggerRejectsInvalidModuleLevel(t *testing.T) {
+	err := logger.InitWithOptions(logger.Options{Level: "info", ModuleLevels: map[string]string{"search": "loud"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search")
+}
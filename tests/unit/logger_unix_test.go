@@ -0,0 +1,42 @@
+//go:build !windows
+
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"enterprise-blog/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerReopensOnSIGUSR1(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info"})
+	path := filepath.Join(dir, "app.log")
+
+	l := logger.GetLogger()
+	l.Info().Msg("before rotate")
+
+	// 模拟外部 logrotate：移走文件后发送 SIGUSR1
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+
+	assert.Eventually(t, func() bool {
+		l.Info().Msg("after rotate")
+		_, err := os.Stat(path)
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Contains(t, string(rotated), "before rotate")
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(current), "after rotate")
+	assert.NotContains(t, string(current), "before rotate")
+}
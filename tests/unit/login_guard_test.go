@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedFor 返回锁定的剩余时间，未锁定时为 0
+func lockedFor(t *testing.T, err error) time.Duration {
+	t.Helper()
+	if err == nil {
+		return 0
+	}
+	var locked *services.LoginLockedError
+	require.True(t, errors.As(err, &locked), "unexpected error: %v", err)
+	assert.ErrorIs(t, err, services.ErrLoginLocked)
+	return locked.RetryAfter
+}
+
+func TestLoginGuard_LocksAfterMaxFailures(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mr.SetTime(base)
+	guard := services.NewLoginGuard(3, 10, 15*time.Minute)
+
+	for i := 0; i < 2; i++ {
+		guard.RecordFailure(ctx, "victim@example.com", "10.0.0.1")
+		mr.SetTime(base.Add(time.Duration(i+1) * time.Minute))
+	}
+	require.NoError(t, guard.Check(ctx, "victim@example.com", "10.0.0.1"))
+
+	guard.RecordFailure(ctx, "victim@example.com", "10.0.0.1")
+	// 邮箱大小写不同视为同一邮箱，其他 IP 同样被锁定；最早的失败在 12:00，15 分钟后解锁
+	err := guard.Check(ctx, "Victim@Example.com", "10.0.0.2")
+	assert.Equal(t, 13*time.Minute, lockedFor(t, err))
+	// 其他邮箱不受影响
+	assert.NoError(t, guard.Check(ctx, "other@example.com", "10.0.0.1"))
+}
+
+func TestLoginGuard_SlidingWindowExpiry(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard := services.NewLoginGuard(3, 10, 15*time.Minute)
+
+	for _, offset := range []time.Duration{0, 5 * time.Minute, 10 * time.Minute} {
+		mr.SetTime(base.Add(offset))
+		guard.RecordFailure(ctx, "user@example.com", "")
+	}
+	mr.SetTime(base.Add(14 * time.Minute))
+	assert.Equal(t, time.Minute, lockedFor(t, guard.Check(ctx, "user@example.com", "")))
+
+	// 12:00 的失败移出窗口后解锁，但窗口内仍有两次失败，再错一次重新锁定
+	mr.SetTime(base.Add(15 * time.Minute))
+	require.NoError(t, guard.Check(ctx, "user@example.com", ""))
+	guard.RecordFailure(ctx, "user@example.com", "")
+	assert.Equal(t, 5*time.Minute, lockedFor(t, guard.Check(ctx, "user@example.com", "")))
+
+	// 全部移出窗口
+	mr.SetTime(base.Add(31 * time.Minute))
+	assert.NoError(t, guard.Check(ctx, "user@example.com", ""))
+}
+
+func TestLoginGuard_ResetClearsEmailOnly(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+	mr.SetTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	guard := services.NewLoginGuard(2, 3, 15*time.Minute)
+
+	guard.RecordFailure(ctx, "user@example.com", "10.0.0.1")
+	guard.RecordFailure(ctx, "user@example.com", "10.0.0.1")
+	require.Error(t, guard.Check(ctx, "user@example.com", ""))
+
+	// 登录成功清除邮箱计数，IP 计数保留
+	guard.Reset(ctx, "user@example.com")
+	assert.NoError(t, guard.Check(ctx, "user@example.com", ""))
+	guard.RecordFailure(ctx, "another@example.com", "10.0.0.1")
+	assert.Error(t, guard.Check(ctx, "user@example.com", "10.0.0.1"), "IP reached its limit across different emails")
+	assert.NoError(t, guard.Check(ctx, "user@example.com", "10.0.0.2"))
+}
+
+func TestLoginGuard_SetLimits(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+	mr.SetTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	guard := services.NewLoginGuard(5, 10, 15*time.Minute)
+
+	guard.RecordFailure(ctx, "user@example.com", "")
+	guard.RecordFailure(ctx, "user@example.com", "")
+	require.NoError(t, guard.Check(ctx, "user@example.com", ""))
+	guard.SetLimits(2, 10, 15*time.Minute)
+	assert.Error(t, guard.Check(ctx, "user@example.com", ""))
+}
+
+func TestLoginGuard_WithoutRedis(t *testing.T) {
+	prev := database.RedisClient
+	database.RedisClient = nil
+	t.Cleanup(func() { database.RedisClient = prev })
+	ctx := context.Background()
+	guard := services.NewLoginGuard(1, 1, time.Minute)
+
+	guard.RecordFailure(ctx, "user@example.com", "10.0.0.1")
+	assert.NoError(t, guard.Check(ctx, "user@example.com", "10.0.0.1"))
+	guard.Reset(ctx, "user@example.com")
+}
@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"enterprise-blog/pkg/markdown"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownRender(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"heading", "## Title ##", "<h2>Title</h2>"},
+		{"paragraph", "line one\nline two\n\nnext", "<p>line one\nline two</p>\n<p>next</p>"},
+		{"emphasis", "**bold**, *em*, __b__ and _e_", "<p><strong>bold</strong>, <em>em</em>, <strong>b</strong> and <em>e</em></p>"},
+		{"nested emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>"},
+		{"snake case", "use snake_case_names here", "<p>use snake_case_names here</p>"},
+		{"inline code", "run `a < b` now", "<p>run <code>a &lt; b</code> now</p>"},
+		{"escaped", `\*not em\*`, "<p>*not em*</p>"},
+		{"fenced code", "```go\nfmt.Println(\"<x>\")\n```", `<pre><code class="language-go">fmt.Println(&#34;&lt;x&gt;&#34;)</code></pre>`},
+		{"bullet list", "- a\n- b\n  - c", "<ul>\n<li>a</li>\n<li>b\n<ul>\n<li>c</li>\n</ul></li>\n</ul>"},
+		{"ordered list", "3. x\n4. y", "<ol start=\"3\">\n<li>x</li>\n<li>y</li>\n</ol>"},
+		{"blockquote", "> quoted\n> text", "<blockquote>\n<p>quoted\ntext</p>\n</blockquote>"},
+		{"hr", "a\n\n---\n\nb", "<p>a</p>\n<hr>\n<p>b</p>"},
+		{"link", `[site](https://example.com "Title")`, `<p><a href="https://example.com">site</a></p>`},
+		{"image", "![a \"cat\"](/uploads/images/cat.png)", `<p><img src="/uploads/images/cat.png" alt="a &#34;cat&#34;"></p>`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, markdown.Render(tc.src, markdown.Options{}))
+		})
+	}
+}
+
+func TestMarkdownRenderIsSafe(t *testing.T) {
+	out := markdown.Render("<script>alert(1)</script>\n\n[x](javascript:alert(1)) ![y](JaVaScRiPt:alert(1)) <img src=x onerror=alert(1)>", markdown.Options{})
+	assert.NotContains(t, out, "<script")
+	assert.NotContains(t, out, "<img src=x")
+	assert.NotContains(t, strings.ToLower(out), "javascript:")
+	assert.Contains(t, out, "&lt;script&gt;")
+}
+
+func TestMarkdownRenderRewritesImageURLs(t *testing.T) {
+	opts := markdown.Options{ImageURL: func(u string) string {
+		if strings.HasPrefix(u, "/") {
+			return "https://api.example.com" + u
+		}
+		return u
+	}}
+	out := markdown.Render("![a](/uploads/a.png) ![b](https://cdn.example.com/b.png) [c](/articles/1)", opts)
+	assert.Contains(t, out, `src="https://api.example.com/uploads/a.png"`)
+	assert.Contains(t, out, `src="https://cdn.example.com/b.png"`)
+	// 链接不受 ImageURL 影响
+	assert.Contains(t, out, `href="/articles/1"`)
+}
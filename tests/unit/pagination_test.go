@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"math"
+	"testing"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePage(t *testing.T) {
+	cases := []struct {
+		name             string
+		page, pageSize   int
+		wantPage, wantPS int
+	}{
+		{"defaults", 0, 0, 1, 20},
+		{"negative", -3, -10, 1, 20},
+		{"valid", 2, 50, 2, 50},
+		{"page size too large", 1, 100000, 1, models.MaxPageSize},
+		{"offset overflow", math.MaxInt, 100, math.MaxInt32/100 + 1, 100},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, pageSize := models.NormalizePage(tc.page, tc.pageSize, 20)
+			assert.Equal(t, tc.wantPage, page)
+			assert.Equal(t, tc.wantPS, pageSize)
+		})
+	}
+}
+
+func TestNormalizePageWithin(t *testing.T) {
+	page, pageSize := models.NormalizePageWithin(0, 0, 5, 30)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, 5, pageSize)
+	_, pageSize = models.NormalizePageWithin(1, 50, 5, 30)
+	assert.Equal(t, 30, pageSize)
+	// 上限无效时退回 MaxPageSize
+	_, pageSize = models.NormalizePageWithin(1, 100000, 5, 0)
+	assert.Equal(t, models.MaxPageSize, pageSize)
+	_, pageSize = models.NormalizePageWithin(1, 100000, 5, 1000)
+	assert.Equal(t, models.MaxPageSize, pageSize)
+}
+
+func TestPageLimitsFallBackToDefaults(t *testing.T) {
+	original := config.Get()
+	t.Cleanup(func() { config.Set(original) })
+
+	config.Set(nil)
+	assert.Equal(t, config.PageSizeLimits{Default: 20, Max: models.MaxPageSize}, config.PageLimits(config.PageComments))
+
+	config.Set(&config.Config{Pagination: config.PaginationConfig{Comments: config.PageSizeLimits{Max: 50}}})
+	assert.Equal(t, config.PageSizeLimits{Default: 20, Max: 50}, config.PageLimits(config.PageComments))
+	assert.Equal(t, config.PageSizeLimits{Default: 10, Max: models.MaxPageSize}, config.PageLimits(config.PageArticles))
+	page, pageSize := config.NormalizePage(config.PageComments, 0, 80)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, 50, pageSize)
+}
+
+func TestPaginatedNormalizesPageAndPageSize(t *testing.T) {
+	cases := []struct {
+		name             string
+		page, pageSize   int
+		total            int64
+		wantPage, wantPS int
+		wantTotalPage    int
+	}{
+		{"zero page size", 1, 0, 42, 1, models.DefaultPageSize, 3},
+		{"zero page", 0, 10, 42, 1, 10, 5},
+		{"negative", -2, -5, 42, 1, models.DefaultPageSize, 3},
+		{"page size too large", 2, 1000, 250, 2, models.MaxPageSize, 3},
+		{"valid", 3, 10, 42, 3, 10, 5},
+		{"no rows", 1, 10, 0, 1, 10, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp *models.PaginationResponse
+			assert.NotPanics(t, func() { resp = models.Paginated([]string{}, tc.page, tc.pageSize, tc.total) })
+			assert.Equal(t, tc.wantPage, resp.Meta.Page)
+			assert.Equal(t, tc.wantPS, resp.Meta.PageSize)
+			assert.Equal(t, tc.total, resp.Meta.Total)
+			assert.Equal(t, tc.wantTotalPage, resp.Meta.TotalPage)
+		})
+	}
+}
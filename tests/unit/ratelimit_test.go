@@ -0,0 +1,204 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+	"enterprise-blog/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitRouter(limiter *middleware.RateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/limited", limiter.Middleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func getLimited(router *gin.Engine, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiterRedisHeaders(t *testing.T) {
+	mr := setupMiniRedis(t)
+	router := rateLimitRouter(middleware.NewRateLimiter(2, time.Minute))
+
+	now := time.Now().Unix()
+	for _, remaining := range []string{"1", "0"} {
+		w := getLimited(router, "10.0.0.1")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, w.Header().Get("X-RateLimit-Remaining"))
+		reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, now+60, reset, 2)
+	}
+
+	w := getLimited(router, "10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	// 被拒绝的请求不累计，也不会延长窗口
+	count, err := mr.Get("ratelimit:10.0.0.1:/limited")
+	require.NoError(t, err)
+	assert.Equal(t, "2", count)
+
+	// 其他 IP 单独计数
+	assert.Equal(t, http.StatusOK, getLimited(router, "10.0.0.2").Code)
+
+	mr.FastForward(time.Minute)
+	assert.Equal(t, http.StatusOK, getLimited(router, "10.0.0.1").Code)
+}
+
+func TestRateLimiterRedisConcurrent(t *testing.T) {
+	setupMiniRedis(t)
+	router := rateLimitRouter(middleware.NewRateLimiter(5, time.Minute))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if getLimited(router, "10.0.0.3").Code == http.StatusOK {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 5, allowed)
+}
+
+func TestRateLimiterMemoryFallback(t *testing.T) {
+	prev := database.RedisClient
+	database.RedisClient = nil
+	t.Cleanup(func() { database.RedisClient = prev })
+
+	// 每秒补充 2 个令牌
+	router := rateLimitRouter(middleware.NewRateLimiter(2, time.Second))
+	for _, remaining := range []string{"1", "0"} {
+		w := getLimited(router, "10.0.0.4")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, w.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+	}
+	w := getLimited(router, "10.0.0.4")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// 其他 IP 单独计数
+	assert.Equal(t, http.StatusOK, getLimited(router, "10.0.0.5").Code)
+
+	// 补充一个令牌后恢复
+	time.Sleep(600 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, getLimited(router, "10.0.0.4").Code)
+}
+
+func TestRateLimiterRedisErrorFallsBackToMemory(t *testing.T) {
+	mr := setupMiniRedis(t)
+	mr.Close()
+
+	router := rateLimitRouter(middleware.NewRateLimiter(1, time.Minute))
+	assert.Equal(t, http.StatusOK, getLimited(router, "10.0.0.6").Code)
+	// Redis 不可用时仍然限流
+	assert.Equal(t, http.StatusTooManyRequests, getLimited(router, "10.0.0.6").Code)
+}
+
+func TestRateLimitBuckets(t *testing.T) {
+	setupMiniRedis(t)
+	gin.SetMode(gin.TestMode)
+	cfg := config.RateLimitConfig{
+		Requests: 100, WindowSeconds: 60,
+		Buckets: map[string]config.RateLimitBucket{
+			"comment_create": {Requests: 2, WindowSeconds: 60, PerUser: true},
+		},
+		ExemptRoles: []string{"admin"},
+	}
+	buckets := middleware.NewRateLimitBuckets(cfg)
+
+	// 模拟认证中间件：X-Test-User / X-Test-Role 请求头写入 user_id / role
+	fakeAuth := func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Set("user_id", uuid.MustParse(id))
+			c.Set("role", c.GetHeader("X-Test-Role"))
+		}
+		c.Next()
+	}
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	// 同一个桶的路由共用额度
+	router.POST("/articles/:id/comments", fakeAuth, buckets.Middleware("comment_create"), ok)
+	router.POST("/comments/:id/replies", fakeAuth, buckets.Middleware("comment_create"), ok)
+
+	post := func(path, ip, userID, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = ip + ":12345"
+		req.Header.Set("X-Test-User", userID)
+		req.Header.Set("X-Test-Role", role)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 登录用户按用户 ID 计数，换 IP 不能绕过
+	user := uuid.NewString()
+	assert.Equal(t, http.StatusOK, post("/articles/1/comments", "10.0.1.1", user, "user").Code)
+	assert.Equal(t, http.StatusOK, post("/comments/2/replies", "10.0.1.2", user, "user").Code)
+	w := post("/articles/3/comments", "10.0.1.3", user, "user")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// 同一 IP 的其他用户和匿名请求单独计数
+	assert.Equal(t, http.StatusOK, post("/articles/1/comments", "10.0.1.1", uuid.NewString(), "user").Code)
+	assert.Equal(t, http.StatusOK, post("/articles/1/comments", "10.0.1.1", "", "").Code)
+
+	// 管理员不限流
+	admin := uuid.NewString()
+	for i := 0; i < 5; i++ {
+		w := post("/articles/1/comments", "10.0.1.4", admin, "admin")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+
+	// 重新加载配置后额度对已注册的路由生效
+	cfg.Buckets = map[string]config.RateLimitBucket{"comment_create": {Requests: 3, WindowSeconds: 60, PerUser: true}}
+	cfg.ExemptRoles = nil
+	buckets.SetConfig(cfg)
+	assert.Equal(t, http.StatusOK, post("/articles/1/comments", "10.0.1.1", user, "user").Code)
+	assert.Equal(t, http.StatusTooManyRequests, post("/articles/1/comments", "10.0.1.1", user, "user").Code)
+	assert.Equal(t, http.StatusOK, post("/articles/1/comments", "10.0.1.4", admin, "admin").Code)
+}
+
+func TestRateLimitBucketsUnconfiguredNameUsesDefault(t *testing.T) {
+	setupMiniRedis(t)
+	buckets := middleware.NewRateLimitBuckets(config.RateLimitConfig{Requests: 1, WindowSeconds: 60})
+	router := gin.New()
+	router.GET("/limited", buckets.Middleware("unknown"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, http.StatusOK, getLimited(router, "10.0.2.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, getLimited(router, "10.0.2.1").Code)
+}
@@ -0,0 +1,41 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadingStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		words   int
+		minutes int
+	}{
+		{"empty", "", 0, 0},
+		{"only markup", "```go\ncode()\n```\n\n---", 0, 0},
+		{"short english", "Hello, world — again!", 3, 1},
+		{"english 200 wpm", strings.Repeat("word ", 400), 400, 2},
+		{"english rounds up", strings.Repeat("word ", 201), 201, 2},
+		{"chinese 400 cpm", strings.Repeat("中文", 400), 800, 2},
+		{"mixed", "使用 Go 开发企业博客", 9, 1},
+		{"markdown stripped", "# Title\n\n**bold** [link](https://example.com/a-b-c) ![img](/a.png)", 3, 1},
+		{"numbers count as words", "Go 1.22 released in 2024", 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, minutes := services.ReadingStats(tt.content)
+			assert.Equal(t, tt.words, words)
+			assert.Equal(t, tt.minutes, minutes)
+		})
+	}
+
+	// 中英混排按各自速度累加：200 个单词 + 400 个字 = 2 分钟
+	words, minutes := services.ReadingStats(strings.Repeat("word ", 200) + strings.Repeat("字", 400))
+	assert.Equal(t, 600, words)
+	assert.Equal(t, 2, minutes)
+}
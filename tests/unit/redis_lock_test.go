@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/config"
+	"enterprise-blog/internal/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMiniRedis 使用内存 Redis 替换全局客户端，测试结束后恢复
+func setupMiniRedis(t *testing.T) *miniredis.Miniredis {
+	mr := miniredis.RunT(t)
+	prev := database.RedisClient
+	database.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		database.RedisClient.Close()
+		database.RedisClient = prev
+	})
+	return mr
+}
+
+func TestRedisLockExclusive(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+
+	token, ok, err := database.AcquireLock(ctx, "test:lock", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// 锁被持有时不能再次获取
+	_, ok, err = database.AcquireLock(ctx, "test:lock", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 其他 token 无法续期或释放
+	held, err := database.RefreshLock(ctx, "test:lock", "other", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, held)
+	require.NoError(t, database.ReleaseLock(ctx, "test:lock", "other"))
+	assert.True(t, mr.Exists("test:lock"))
+
+	held, err = database.RefreshLock(ctx, "test:lock", token, 2*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, held)
+	assert.Equal(t, 2*time.Minute, mr.TTL("test:lock"))
+
+	require.NoError(t, database.ReleaseLock(ctx, "test:lock", token))
+	assert.False(t, mr.Exists("test:lock"))
+
+	_, ok, err = database.AcquireLock(ctx, "test:lock", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRedisLockExpires(t *testing.T) {
+	mr := setupMiniRedis(t)
+	ctx := context.Background()
+
+	_, ok, err := database.AcquireLock(ctx, "test:lock", time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	mr.FastForward(2 * time.Second)
+
+	_, ok, err = database.AcquireLock(ctx, "test:lock", time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestInitRedisAppliesTuning(t *testing.T) {
+	mr := miniredis.RunT(t)
+	host, port, _ := strings.Cut(mr.Addr(), ":")
+	previousConfig, previousClient := config.Get(), database.RedisClient
+	config.Set(&config.Config{Redis: config.RedisConfig{
+		Host:           host,
+		Port:           port,
+		PoolSize:       7,
+		MinIdleConns:   2,
+		ReadTimeoutMs:  150,
+		WriteTimeoutMs: 300,
+		MaxRetries:     -1,
+	}})
+	t.Cleanup(func() {
+		database.RedisClient.Close()
+		config.Set(previousConfig)
+		database.RedisClient = previousClient
+	})
+
+	require.NoError(t, database.InitRedis())
+	opts := database.RedisClient.Options()
+	assert.Equal(t, 7, opts.PoolSize)
+	assert.Equal(t, 150*time.Millisecond, opts.ReadTimeout)
+	assert.Equal(t, 300*time.Millisecond, opts.WriteTimeout)
+	assert.Equal(t, 0, opts.MaxRetries) // go-redis 将 -1（不重试）规范化为 0
+	assert.True(t, opts.ContextTimeoutEnabled)
+	// MinIdleConns 由 InitRedis 在注册钩子后预热，连接池中已有对应数量的空闲连接
+	stats := database.RedisClient.PoolStats()
+	assert.Equal(t, uint32(2), stats.TotalConns)
+	assert.Equal(t, uint32(2), stats.IdleConns)
+}
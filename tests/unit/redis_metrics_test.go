@@ -0,0 +1,57 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enterprise-blog/internal/database"
+	"enterprise-blog/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisMetricsHook(t *testing.T) {
+	mr := setupMiniRedis(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	client.AddHook(database.RedisMetricsHook{})
+	ctx := context.Background()
+
+	get := map[string]string{"operation": "get"}
+	pipeline := map[string]string{"operation": "pipeline"}
+	getBefore := metricValue(t, "redis_operations_total", get)
+	histBefore := metricValue(t, "redis_operation_duration_seconds", get)
+	pipelineBefore := metricValue(t, "redis_operations_total", pipeline)
+
+	require.NoError(t, client.Set(ctx, "metrics:key", "1", 0).Err())
+	// 键不存在同样记录
+	require.ErrorIs(t, client.Get(ctx, "metrics:missing").Err(), redis.Nil)
+	require.NoError(t, client.Get(ctx, "metrics:key").Err())
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, "metrics:counter")
+		pipe.Get(ctx, "metrics:key")
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, getBefore+2, metricValue(t, "redis_operations_total", get))
+	assert.Equal(t, histBefore+2, metricValue(t, "redis_operation_duration_seconds", get))
+	assert.Equal(t, pipelineBefore+1, metricValue(t, "redis_operations_total", pipeline))
+}
+
+func TestMetricsMiddlewareStatusLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(metrics.MetricsMiddleware())
+	router.POST("/metrics-test/:id", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	labels := map[string]string{"method": "POST", "path": "/metrics-test/:id", "status": "201"}
+	before := metricValue(t, "http_requests_total", labels)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/metrics-test/1", nil))
+	assert.Equal(t, before+1, metricValue(t, "http_requests_total", labels))
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastWeekRange(t *testing.T) {
+	// 周一早上发送时统计上一个完整自然周
+	from, to := services.LastWeekRange(time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), to)
+
+	// 周日时仍统计上一周，而不是包含今天的本周
+	from, to = services.LastWeekRange(time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestRenderWeeklyReport(t *testing.T) {
+	report := &models.WeeklyReport{
+		From:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		Counts: models.PeriodCounts{NewUsers: 12, PublishedArticles: 3, Views: 456},
+		TopArticles: []*models.DashboardTopItem{
+			{ID: uuid.New(), Title: "<script>alert(1)</script>", Value: 99},
+		},
+		PendingComments: 4,
+	}
+
+	html, err := services.RenderWeeklyReport(report)
+	require.NoError(t, err)
+	assert.Contains(t, html, "2024-01-01 ~ 2024-01-07")
+	assert.Contains(t, html, "456")
+	assert.Contains(t, html, "待审核评论：4")
+	// 标题需要转义
+	assert.NotContains(t, html, "<script>")
+	assert.Contains(t, html, "&lt;script&gt;")
+}
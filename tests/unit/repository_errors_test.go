@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"enterprise-blog/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryNotFoundSentinels(t *testing.T) {
+	sentinels := []error{
+		repository.ErrArticleNotFound,
+		repository.ErrArticleRevisionNotFound,
+		repository.ErrCategoryNotFound,
+		repository.ErrTagNotFound,
+		repository.ErrCommentNotFound,
+		repository.ErrUserNotFound,
+		repository.ErrImageNotFound,
+		repository.ErrAuditLogNotFound,
+		repository.ErrRefreshTokenNotFound,
+		repository.ErrNotificationNotFound,
+		repository.ErrTrashItemNotFound,
+		repository.ErrEmailVerificationNotFound,
+		repository.ErrPasswordResetNotFound,
+	}
+	for _, err := range sentinels {
+		t.Run(err.Error(), func(t *testing.T) {
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+			assert.NotErrorIs(t, err, repository.ErrConflict)
+			// 服务层包装后仍能识别
+			assert.ErrorIs(t, fmt.Errorf("load: %w", err), repository.ErrNotFound)
+		})
+	}
+
+	// 引用的标签不存在属于参数错误，不是资源不存在
+	assert.NotErrorIs(t, repository.ErrTagsNotFound, repository.ErrNotFound)
+	// 数据库错误不会被当作不存在
+	assert.NotErrorIs(t, context.DeadlineExceeded, repository.ErrNotFound)
+	assert.NotErrorIs(t, errors.New("connection refused"), repository.ErrConflict)
+}
@@ -0,0 +1,151 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/internal/models"
+	"enterprise-blog/pkg/jwt"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerFromContext(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info"})
+
+	// 没有上下文字段时与 GetLogger 相同
+	plain := logger.FromContext(context.Background())
+	plain.Info().Msg("plain")
+
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	ctx = logger.WithRoute(ctx, "/api/v1/articles/:id")
+	ctx = logger.WithUserID(ctx, "user-1")
+	ctx = logger.WithImpersonatorID(ctx, "admin-1")
+	tagged := logger.FromContext(ctx, "search")
+	tagged.Info().Msg("tagged")
+
+	lines := readLogLines(t, dir)
+	require.Len(t, lines, 2)
+	assert.NotContains(t, lines[0], "request_id")
+	assert.NotContains(t, lines[0], "user_id")
+	assert.NotContains(t, lines[0], "impersonator_id")
+	assert.Equal(t, "req-1", lines[1]["request_id"])
+	assert.Equal(t, "user-1", lines[1]["user_id"])
+	assert.Equal(t, "admin-1", lines[1]["impersonator_id"])
+	assert.Equal(t, "/api/v1/articles/:id", lines[1]["route"])
+	assert.Equal(t, "search", lines[1]["module"])
+}
+
+func TestRequestContextFieldsInLogs(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info"})
+
+	jwtMgr := jwt.NewJWTManager("test-secret", time.Hour)
+	userID := uuid.New()
+	token, err := jwtMgr.GenerateToken(userID, "alice", "author")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.LoggerMiddleware())
+	router.GET("/articles/:id", middleware.AuthMiddleware(jwtMgr), func(c *gin.Context) {
+		l := logger.FromContext(c.Request.Context())
+		l.Info().Msg("service log")
+		c.Status(http.StatusOK)
+	})
+
+	// 沿用上游传入的请求 ID
+	req := httptest.NewRequest(http.MethodGet, "/articles/42", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(middleware.RequestIDHeader, "gateway-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gateway-123", w.Header().Get(middleware.RequestIDHeader))
+
+	// 非法的请求 ID 被替换
+	req = httptest.NewRequest(http.MethodGet, "/articles/42", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(middleware.RequestIDHeader, "bad id\nforged")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	generated := w.Header().Get(middleware.RequestIDHeader)
+	_, err = uuid.Parse(generated)
+	assert.NoError(t, err)
+
+	// 模拟登录的请求同时记录发起模拟的管理员
+	adminID := uuid.New()
+	impersonation, err := jwtMgr.GenerateImpersonationToken(userID, "alice", "author", adminID, time.Hour)
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodGet, "/articles/42", nil)
+	req.Header.Set("Authorization", "Bearer "+impersonation)
+	req.Header.Set(middleware.RequestIDHeader, "gateway-456")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	lines := readLogLines(t, dir)
+	require.Len(t, lines, 6)
+	for i, requestID := range []string{"gateway-123", "gateway-123", generated, generated, "gateway-456", "gateway-456"} {
+		assert.Equal(t, requestID, lines[i]["request_id"])
+		assert.Equal(t, userID.String(), lines[i]["user_id"])
+		assert.Equal(t, "/articles/:id", lines[i]["route"])
+		if i < 4 {
+			assert.NotContains(t, lines[i], "impersonator_id")
+		} else {
+			assert.Equal(t, adminID.String(), lines[i]["impersonator_id"])
+		}
+	}
+	assert.Equal(t, "service log", lines[0]["message"])
+	assert.Equal(t, "HTTP Request", lines[1]["message"])
+}
+
+func TestRequestIDInErrorResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, models.Success("data")) })
+	router.GET("/missing", func(c *gin.Context) { c.JSON(http.StatusNotFound, models.Error(404, "not found")) })
+	router.GET("/empty", func(c *gin.Context) { c.JSON(http.StatusBadRequest, gin.H{}) })
+	router.GET("/text", func(c *gin.Context) { c.String(http.StatusInternalServerError, "boom") })
+
+	get := func(path, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 错误响应体中的 request_id 与响应头一致，其余字段不变
+	w := get("/missing", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), body["request_id"])
+	assert.Equal(t, float64(404), body["code"])
+	assert.Equal(t, "not found", body["message"])
+
+	w = get("/empty", "gateway-\"quoted\"")
+	body = nil
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{"request_id": "gateway-\"quoted\""}, body)
+
+	// 成功响应和非 JSON 响应不修改
+	w = get("/ok", "gateway-123")
+	assert.NotContains(t, w.Body.String(), "request_id")
+	assert.Equal(t, "gateway-123", w.Header().Get(middleware.RequestIDHeader))
+	w = get("/text", "gateway-123")
+	assert.Equal(t, "boom", w.Body.String())
+}
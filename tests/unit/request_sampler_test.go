@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/middleware"
+	"enterprise-blog/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestSamplerShouldLog(t *testing.T) {
+	sampler := middleware.NewRequestSampler(3, []string{"/health", "/uploads/images/*"}, time.Second)
+
+	// 每个路由独立计数：第 1、4、7 个请求被记录
+	var health, images []bool
+	for i := 0; i < 7; i++ {
+		health = append(health, sampler.ShouldLog(http.MethodGet, "/health", http.StatusOK, time.Millisecond))
+		images = append(images, sampler.ShouldLog(http.MethodGet, "/uploads/images/a.png", http.StatusOK, time.Millisecond))
+	}
+	expected := []bool{true, false, false, true, false, false, true}
+	assert.Equal(t, expected, health)
+	assert.Equal(t, expected, images)
+
+	// 始终记录：错误状态、慢请求、写操作、未配置的路由
+	for i := 0; i < 3; i++ {
+		assert.True(t, sampler.ShouldLog(http.MethodGet, "/health", http.StatusNotFound, time.Millisecond))
+		assert.True(t, sampler.ShouldLog(http.MethodGet, "/health", http.StatusInternalServerError, time.Millisecond))
+		assert.True(t, sampler.ShouldLog(http.MethodGet, "/health", http.StatusOK, 2*time.Second))
+		assert.True(t, sampler.ShouldLog(http.MethodPost, "/health", http.StatusOK, time.Millisecond))
+		assert.True(t, sampler.ShouldLog(http.MethodGet, "/api/v1/tags", http.StatusOK, time.Millisecond))
+		assert.True(t, sampler.ShouldLog(http.MethodGet, "/uploads/images", http.StatusOK, time.Millisecond))
+	}
+
+	// rate <= 1 和 nil 采样器不采样
+	noSampling := middleware.NewRequestSampler(1, []string{"/health"}, 0)
+	var nilSampler *middleware.RequestSampler
+	for i := 0; i < 3; i++ {
+		assert.True(t, noSampling.ShouldLog(http.MethodGet, "/health", http.StatusOK, 0))
+		assert.True(t, nilSampler.ShouldLog(http.MethodGet, "/health", http.StatusOK, 0))
+	}
+}
+
+func TestSampledLoggerMiddleware(t *testing.T) {
+	dir := initFileLogger(t, logger.Options{Level: "info"})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.SampledLoggerMiddleware(middleware.NewRequestSampler(10, []string{"/health"}, time.Second)))
+	router.GET("/health", func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 20; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health?fail=1", nil))
+
+	var statuses []float64
+	for _, line := range readLogLines(t, dir) {
+		if line["message"] == "HTTP Request" {
+			statuses = append(statuses, line["status"].(float64))
+		}
+	}
+	assert.Equal(t, []float64{200, 200, 503}, statuses)
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"enterprise-blog/pkg/s3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Client_PutAndGetObject(t *testing.T) {
+	authPattern := regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=AKID/\d{8}/eu-west-1/s3/aws4_request, SignedHeaders=([a-z0-9;-]+), Signature=[0-9a-f]{64}$`)
+	objects := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		match := authPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+		require.NotNil(t, match, r.Header.Get("Authorization"))
+		assert.Equal(t, "UNSIGNED-PAYLOAD", r.Header.Get("X-Amz-Content-Sha256"))
+		assert.Regexp(t, `^\d{8}T\d{6}Z$`, r.Header.Get("X-Amz-Date"))
+
+		switch r.Method {
+		case http.MethodPut:
+			assert.Equal(t, "content-type;host;x-amz-content-sha256;x-amz-date", match[1])
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, int64(len(body)), r.ContentLength)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			assert.Equal(t, "host;x-amz-content-sha256;x-amz-date", match[1])
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+				return
+			}
+			w.Write(body)
+		}
+	}))
+	defer srv.Close()
+
+	client := &s3.Client{Endpoint: srv.URL + "/", Region: "eu-west-1", AccessKey: "AKID", SecretKey: "secret"}
+	ctx := context.Background()
+
+	// path-style 地址：Endpoint/bucket/key
+	require.NoError(t, client.PutObject(ctx, "backups", "blog/2024-01-01.zip", bytes.NewReader([]byte("archive")), 7))
+	assert.Contains(t, objects, "/backups/blog/2024-01-01.zip")
+
+	body, err := client.GetObject(ctx, "backups", "blog/2024-01-01.zip")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "archive", string(data))
+
+	// 非 2xx 响应返回包含状态码和响应内容的错误
+	_, err = client.GetObject(ctx, "backups", "missing.zip")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "NoSuchKey")
+
+	_, err = client.GetObject(ctx, "", "key")
+	assert.Error(t, err)
+	_, err = (&s3.Client{Endpoint: "not a url"}).GetObject(ctx, "bucket", "key")
+	assert.Error(t, err)
+}
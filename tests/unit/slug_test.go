@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSlugConflict = errors.New("slug conflict")
+
+func isSlugConflict(err error) bool {
+	return errors.Is(err, errSlugConflict)
+}
+
+func TestWithUniqueSlugRetriesWithSuffix(t *testing.T) {
+	var tried []string
+	slug, err := services.WithUniqueSlug("go-进阶", isSlugConflict, func(slug string) error {
+		tried = append(tried, slug)
+		if len(tried) < 3 {
+			return errSlugConflict
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "go-进阶-2", slug)
+	assert.Equal(t, []string{"go-进阶", "go-进阶-1", "go-进阶-2"}, tried)
+}
+
+func TestWithUniqueSlugExhausted(t *testing.T) {
+	var tried []string
+	_, err := services.WithUniqueSlug("go", isSlugConflict, func(slug string) error {
+		tried = append(tried, slug)
+		return errSlugConflict
+	})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrSlugRetriesExhausted))
+	assert.Equal(t, []string{"go", "go-1", "go-2", "go-3", "go-4"}, tried)
+}
+
+func TestWithUniqueSlugPassesThroughOtherErrors(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	calls := 0
+	_, err := services.WithUniqueSlug("go", isSlugConflict, func(string) error {
+		calls++
+		return dbErr
+	})
+
+	assert.Equal(t, dbErr, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSlugExistsErrorCodes(t *testing.T) {
+	assert.Equal(t, "category.slug_exists", services.ErrCategorySlugExists.Error())
+	assert.Equal(t, "tag.slug_exists", services.ErrTagSlugExists.Error())
+}
@@ -0,0 +1,27 @@
+package unit
+
+import (
+	"testing"
+
+	"enterprise-blog/internal/database/sqlite"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewritePlaceholders(t *testing.T) {
+	cases := []struct {
+		sql, want string
+		changed   bool
+	}{
+		{"SELECT * FROM articles WHERE id = $1 AND version = $2", "SELECT * FROM articles WHERE id = ?1 AND version = ?2", true},
+		{"UPDATE tags SET name = $2 WHERE id = $1", "UPDATE tags SET name = ?2 WHERE id = ?1", true},
+		{"SELECT * FROM articles WHERE id = ?", "SELECT * FROM articles WHERE id = ?", false},
+		{"SELECT '$1' AS literal, \"$2\" FROM t WHERE a = $1", "SELECT '$1' AS literal, \"$2\" FROM t WHERE a = ?1", true},
+		{"SELECT $ FROM t", "SELECT $ FROM t", false},
+	}
+	for _, c := range cases {
+		got, changed := sqlite.RewritePlaceholders(c.sql)
+		assert.Equal(t, c.want, got, c.sql)
+		assert.Equal(t, c.changed, changed, c.sql)
+	}
+}
@@ -0,0 +1,27 @@
+package unit
+
+import (
+	"testing"
+
+	"enterprise-blog/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectSystemStatus(t *testing.T) {
+	setupMiniRedis(t)
+
+	status := services.CollectSystemStatus()
+	require.NotNil(t, status)
+	require.NotNil(t, status.Redis)
+	assert.Greater(t, status.Process.Goroutines, 0)
+	assert.Greater(t, status.Process.HeapAllocBytes, uint64(0))
+
+	// 所有任务类型都会返回，即使当前没有运行中的任务
+	for _, kind := range []string{services.JobKindExport, services.JobKindSearchReindex, services.JobKindWeeklyReport} {
+		n, ok := status.BackgroundJobs[kind]
+		assert.True(t, ok, kind)
+		assert.Equal(t, int64(0), n, kind)
+	}
+}
@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"enterprise-blog/pkg/textdiff"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// applyHunks 由差异还原出旧文本和新文本
+func applyHunks(hunks []textdiff.Hunk) (before, after string) {
+	var a, b strings.Builder
+	for _, h := range hunks {
+		if h.Op != textdiff.Insert {
+			a.WriteString(h.Text)
+		}
+		if h.Op != textdiff.Delete {
+			b.WriteString(h.Text)
+		}
+	}
+	return a.String(), b.String()
+}
+
+func TestTextDiffWords(t *testing.T) {
+	ctx := context.Background()
+
+	hunks, err := textdiff.Words(ctx, "The quick brown fox jumps.", "The quick red fox leaps.", textdiff.MaxEdits)
+	require.NoError(t, err)
+	assert.Equal(t, []textdiff.Hunk{
+		{Op: textdiff.Equal, Text: "The quick "},
+		{Op: textdiff.Delete, Text: "brown"},
+		{Op: textdiff.Insert, Text: "red"},
+		{Op: textdiff.Equal, Text: " fox "},
+		{Op: textdiff.Delete, Text: "jumps"},
+		{Op: textdiff.Insert, Text: "leaps"},
+		{Op: textdiff.Equal, Text: "."},
+	}, hunks)
+
+	// 中日韩字符逐字比较
+	hunks, err = textdiff.Words(ctx, "今天天气很好", "今天天气不好", textdiff.MaxEdits)
+	require.NoError(t, err)
+	assert.Equal(t, []textdiff.Hunk{
+		{Op: textdiff.Equal, Text: "今天天气"},
+		{Op: textdiff.Delete, Text: "很"},
+		{Op: textdiff.Insert, Text: "不"},
+		{Op: textdiff.Equal, Text: "好"},
+	}, hunks)
+
+	hunks, err = textdiff.Words(ctx, "", "new text", textdiff.MaxEdits)
+	require.NoError(t, err)
+	assert.Equal(t, []textdiff.Hunk{{Op: textdiff.Insert, Text: "new text"}}, hunks)
+
+	hunks, err = textdiff.Words(ctx, "same", "same", textdiff.MaxEdits)
+	require.NoError(t, err)
+	assert.Equal(t, []textdiff.Hunk{{Op: textdiff.Equal, Text: "same"}}, hunks)
+}
+
+func TestTextDiffRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	rnd := rand.New(rand.NewSource(1))
+	words := []string{"alpha", "beta", "gamma", "delta", " ", "\n", ",", "文", "字"}
+	randomText := func() string {
+		var sb strings.Builder
+		for i := rnd.Intn(40); i > 0; i-- {
+			sb.WriteString(words[rnd.Intn(len(words))])
+		}
+		return sb.String()
+	}
+
+	// 任意输入：差异能还原出两段原文
+	for i := 0; i < 200; i++ {
+		a, b := randomText(), randomText()
+		for _, lines := range []bool{false, true} {
+			var hunks []textdiff.Hunk
+			var err error
+			if lines {
+				hunks, err = textdiff.Lines(ctx, a, b, textdiff.MaxEdits)
+			} else {
+				hunks, err = textdiff.Words(ctx, a, b, textdiff.MaxEdits)
+			}
+			require.NoError(t, err)
+			before, after := applyHunks(hunks)
+			require.Equal(t, a, before)
+			require.Equal(t, b, after)
+			for j := 1; j < len(hunks); j++ {
+				require.NotEqual(t, hunks[j-1].Op, hunks[j].Op, "adjacent hunks must be merged")
+			}
+		}
+	}
+}
+
+func TestTextDiffLimits(t *testing.T) {
+	ctx := context.Background()
+	a := strings.Repeat("a ", 50)
+	b := strings.Repeat("b ", 50)
+
+	_, err := textdiff.Words(ctx, a, b, 10)
+	assert.ErrorIs(t, err, textdiff.ErrTooComplex)
+
+	// 按行比较：差异行数在上限内
+	hunks, err := textdiff.Lines(ctx, "one\ntwo\nthree\n", "one\n2\nthree\n", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []textdiff.Hunk{
+		{Op: textdiff.Equal, Text: "one\n"},
+		{Op: textdiff.Delete, Text: "two\n"},
+		{Op: textdiff.Insert, Text: "2\n"},
+		{Op: textdiff.Equal, Text: "three\n"},
+	}, hunks)
+
+	// Compare 在差异过多时退回按行或整段替换，结果仍能还原原文
+	long := strings.Repeat("x ", textdiff.MaxEdits*2)
+	hunks, err = textdiff.Compare(ctx, long, strings.Repeat("y ", textdiff.MaxEdits*2))
+	require.NoError(t, err)
+	before, after := applyHunks(hunks)
+	assert.Equal(t, long, before)
+	assert.Equal(t, strings.Repeat("y ", textdiff.MaxEdits*2), after)
+
+	_, err = textdiff.Compare(ctx, strings.Repeat("x", textdiff.MaxInputBytes), "y")
+	assert.ErrorIs(t, err, textdiff.ErrInputTooLarge)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = textdiff.Words(canceled, a, b, textdiff.MaxEdits)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTextDiffHTML(t *testing.T) {
+	html := textdiff.HTML([]textdiff.Hunk{
+		{Op: textdiff.Equal, Text: "a <b> "},
+		{Op: textdiff.Delete, Text: "old"},
+		{Op: textdiff.Insert, Text: "<script>"},
+	})
+	assert.Equal(t, "a &lt;b&gt; <del>old</del><ins>&lt;script&gt;</ins>", html)
+}
@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"enterprise-blog/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientIPRouter 返回一个回显 ClientIP 的路由，trustedProxies 与 main.go 中的设置方式相同
+func clientIPRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(trustedProxies))
+	router.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+	return router
+}
+
+func requestClientIP(router *gin.Engine, remoteAddr string, headers map[string]string) string {
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	router := clientIPRouter(t, []string{"10.0.0.0/8"})
+
+	// 可信代理转发：取 X-Forwarded-For 中最后一个不可信的地址
+	assert.Equal(t, "203.0.113.7", requestClientIP(router, "10.0.0.2:40000", map[string]string{"X-Forwarded-For": "203.0.113.7"}))
+	// 客户端自己伪造的前缀被忽略
+	assert.Equal(t, "203.0.113.7", requestClientIP(router, "10.0.0.2:40000", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.3"}))
+
+	// 不在可信列表中的来源：伪造的请求头不生效
+	assert.Equal(t, "192.0.2.10", requestClientIP(router, "192.0.2.10:40000", map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"}))
+}
+
+func TestClientIP_NoTrustedProxies(t *testing.T) {
+	// 默认（未配置 TRUSTED_PROXIES）不信任任何代理
+	for _, proxies := range [][]string{nil, {}} {
+		router := clientIPRouter(t, proxies)
+		assert.Equal(t, "10.0.0.2", requestClientIP(router, "10.0.0.2:40000", map[string]string{"X-Forwarded-For": "203.0.113.7"}))
+	}
+}
+
+func TestRateLimiter_IgnoresSpoofedForwardedFor(t *testing.T) {
+	setupMiniRedis(t)
+	router := clientIPRouter(t, nil)
+	limiter := middleware.NewRateLimiter(1, time.Minute)
+	router.GET("/limited", limiter.Middleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(spoofed string) int {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = "192.0.2.10:40000"
+		req.Header.Set("X-Forwarded-For", spoofed)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("198.51.100.1"))
+	// 每次更换伪造的 X-Forwarded-For 也无法绕过限流
+	assert.Equal(t, http.StatusTooManyRequests, request("198.51.100.2"))
+}
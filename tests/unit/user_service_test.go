@@ -1,15 +1,20 @@
 package unit
 
 import (
-	"errors"
+	"context"
 	"testing"
+	"time"
 
 	"enterprise-blog/internal/models"
+	"enterprise-blog/internal/repository"
+	"enterprise-blog/internal/services"
 	"enterprise-blog/pkg/jwt"
 
+	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockUserRepository 模拟用户仓库
@@ -17,12 +22,14 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(user *models.User) error {
+var _ services.UserRepositoryInterface = (*MockUserRepository)(nil)
+
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,7 +37,15 @@ func (m *MockUserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
+func (m *MockUserRepository) GetPublicProfile(ctx context.Context, id uuid.UUID) (*models.PublicProfile, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PublicProfile), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -38,7 +53,7 @@ func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) {
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	args := m.Called(username)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -46,7 +61,7 @@ func (m *MockUserRepository) GetByUsername(username string) (*models.User, error
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByPhone(phone string) (*models.User, error) {
+func (m *MockUserRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
 	args := m.Called(phone)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -54,39 +69,71 @@ func (m *MockUserRepository) GetByPhone(phone string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Update(user *models.User) error {
+func (m *MockUserRepository) Update(ctx context.Context, user *models.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) UpdatePassword(id uuid.UUID, hashedPassword string) error {
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
 	args := m.Called(id, hashedPassword)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Delete(id uuid.UUID) error {
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) List(page, pageSize int) ([]*models.User, int64, error) {
-	args := m.Called(page, pageSize)
+func (m *MockUserRepository) ListByQuery(ctx context.Context, query models.UserQuery) ([]*models.User, int64, error) {
+	args := m.Called(query)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(int64), args.Error(2)
 	}
 	return args.Get(0).([]*models.User), args.Get(1).(int64), args.Error(2)
 }
 
+// MockRefreshTokenStore 模拟刷新 token 登记
+type MockRefreshTokenStore struct {
+	mock.Mock
+}
+
+var _ services.RefreshTokenStore = (*MockRefreshTokenStore)(nil)
+
+func (m *MockRefreshTokenStore) Create(ctx context.Context, token *models.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenStore) GetActive(ctx context.Context, id uuid.UUID) (*models.RefreshToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenStore) Revoke(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 // MockJWTManager 模拟JWT管理器
 type MockJWTManager struct {
 	mock.Mock
 }
 
+var _ services.TokenManager = (*MockJWTManager)(nil)
+
 func (m *MockJWTManager) GenerateToken(userID uuid.UUID, username, role string) (string, error) {
 	args := m.Called(userID, username, role)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockJWTManager) GenerateImpersonationToken(userID uuid.UUID, username, role string, impersonatorID uuid.UUID, ttl time.Duration) (string, error) {
+	args := m.Called(userID, username, role, impersonatorID, ttl)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockJWTManager) ValidateToken(token string) (*jwt.Claims, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
@@ -95,13 +142,32 @@ func (m *MockJWTManager) ValidateToken(token string) (*jwt.Claims, error) {
 	return args.Get(0).(*jwt.Claims), args.Error(1)
 }
 
+func (m *MockJWTManager) GenerateRefreshToken(userID uuid.UUID) (string, *jwt.Claims, error) {
+	args := m.Called(userID)
+	if args.Get(1) == nil {
+		return "", nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).(*jwt.Claims), args.Error(2)
+}
+
+func (m *MockJWTManager) ValidateRefreshToken(token string) (*jwt.Claims, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*jwt.Claims), args.Error(1)
+}
+
+func (m *MockJWTManager) ExpireTime() time.Duration {
+	return m.Called().Get(0).(time.Duration)
+}
+
 func TestUserService_Register(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *models.UserCreate
 		setup   func(*MockUserRepository, *MockJWTManager)
-		wantErr bool
-		errMsg  string
+		wantErr error
 	}{
 		{
 			name: "成功注册",
@@ -113,13 +179,14 @@ func TestUserService_Register(t *testing.T) {
 			},
 			setup: func(mockRepo *MockUserRepository, mockJWT *MockJWTManager) {
 				// 邮箱不存在
-				mockRepo.On("GetByEmail", "test@example.com").Return(nil, errors.New("not found"))
+				mockRepo.On("GetByEmail", "test@example.com").Return(nil, repository.ErrUserNotFound)
 				// 用户名不存在
-				mockRepo.On("GetByUsername", "testuser").Return(nil, errors.New("not found"))
-				// 创建成功
-				mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
+				mockRepo.On("GetByUsername", "testuser").Return(nil, repository.ErrUserNotFound)
+				// 创建成功，保存的是哈希后的密码
+				mockRepo.On("Create", mock.MatchedBy(func(u *models.User) bool {
+					return u.Username == "testuser" && u.CheckPassword("password123")
+				})).Return(nil)
 			},
-			wantErr: false,
 		},
 		{
 			name: "邮箱已存在",
@@ -132,8 +199,7 @@ func TestUserService_Register(t *testing.T) {
 				// 邮箱已存在
 				mockRepo.On("GetByEmail", "existing@example.com").Return(&models.User{}, nil)
 			},
-			wantErr: true,
-			errMsg:  "email already exists",
+			wantErr: services.ErrEmailExists,
 		},
 		{
 			name: "用户名已存在",
@@ -144,45 +210,36 @@ func TestUserService_Register(t *testing.T) {
 			},
 			setup: func(mockRepo *MockUserRepository, mockJWT *MockJWTManager) {
 				// 邮箱不存在
-				mockRepo.On("GetByEmail", "test@example.com").Return(nil, errors.New("not found"))
+				mockRepo.On("GetByEmail", "test@example.com").Return(nil, repository.ErrUserNotFound)
 				// 用户名已存在
 				mockRepo.On("GetByUsername", "existinguser").Return(&models.User{}, nil)
 			},
-			wantErr: true,
-			errMsg:  "username already exists",
+			wantErr: services.ErrUsernameExists,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// TODO: 当前 UserService 使用具体类型 *repository.UserRepository 而非接口
-			// 无法直接使用 mock。需要重构 UserService 使用接口才能进行单元测试。
-			// 目前这些测试被跳过，请使用集成测试 (tests/integration/) 来测试用户服务功能。
-			t.Skip("跳过：UserService 需要重构为使用接口才能进行单元测试")
-			
 			mockRepo := new(MockUserRepository)
+			mockRefresh := new(MockRefreshTokenStore)
 			mockJWT := new(MockJWTManager)
 			tt.setup(mockRepo, mockJWT)
+			userService := services.NewUserService(mockRepo, mockRefresh, mockJWT)
 
-			// 创建服务（需要适配实际的构造函数）
-			// 这里需要根据实际的UserService结构来调整
-			// userService := services.NewUserService(mockRepo, mockJWT)
+			result, err := userService.Register(context.Background(), tt.req)
 
-			// result, err := userService.Register(tt.req)
-
-			// if tt.wantErr {
-			// 	assert.Error(t, err)
-			// 	assert.Contains(t, err.Error(), tt.errMsg)
-			// 	assert.Nil(t, result)
-			// } else {
-			// 	assert.NoError(t, err)
-			// 	assert.NotNil(t, result)
-			// 	assert.Equal(t, tt.req.Username, result.Username)
-			// 	assert.Equal(t, tt.req.Email, result.Email)
-			// 	assert.Empty(t, result.Password) // 密码应该被清除
-			// }
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.req.Username, result.Username)
+				assert.Equal(t, tt.req.Email, result.Email)
+				assert.Empty(t, result.Password) // 密码应该被清除
+			}
 
 			mockRepo.AssertExpectations(t)
+			mockRefresh.AssertExpectations(t)
 			mockJWT.AssertExpectations(t)
 		})
 	}
@@ -190,16 +247,17 @@ func TestUserService_Register(t *testing.T) {
 
 func TestUserService_Login(t *testing.T) {
 	userID := uuid.New()
+	refreshID := uuid.New()
+	refreshExpiresAt := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
 	testUser := &models.User{Password: "password123"}
-	testUser.HashPassword()
+	require.NoError(t, testUser.HashPassword())
 	hashedPassword := testUser.Password
-	
+
 	tests := []struct {
 		name    string
 		req     *models.UserLogin
-		setup   func(*MockUserRepository, *MockJWTManager)
-		wantErr bool
-		errMsg  string
+		setup   func(*MockUserRepository, *MockRefreshTokenStore, *MockJWTManager)
+		wantErr error
 	}{
 		{
 			name: "成功登录",
@@ -207,18 +265,28 @@ func TestUserService_Login(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			setup: func(mockRepo *MockUserRepository, mockJWT *MockJWTManager) {
+			setup: func(mockRepo *MockUserRepository, mockRefresh *MockRefreshTokenStore, mockJWT *MockJWTManager) {
 				user := &models.User{
 					ID:       userID,
+					Username: "testuser",
 					Email:    "test@example.com",
 					Password: hashedPassword,
 					Status:   "active",
 					Role:     models.RoleReader,
 				}
 				mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
-				mockJWT.On("GenerateToken", userID, user.Username, string(user.Role)).Return("test-token", nil)
+				mockJWT.On("GenerateToken", userID, "testuser", string(models.RoleReader)).Return("test-token", nil)
+				claims := &jwt.Claims{UserID: userID, TokenType: jwt.TokenTypeRefresh, RegisteredClaims: jwtlib.RegisteredClaims{
+					ID:        refreshID.String(),
+					ExpiresAt: jwtlib.NewNumericDate(refreshExpiresAt),
+				}}
+				mockJWT.On("GenerateRefreshToken", userID).Return("test-refresh-token", claims, nil)
+				mockJWT.On("ExpireTime").Return(time.Hour)
+				// 刷新 token 的 jti 登记到服务端
+				mockRefresh.On("Create", mock.MatchedBy(func(rt *models.RefreshToken) bool {
+					return rt.ID == refreshID && rt.UserID == userID && rt.ExpiresAt.Equal(refreshExpiresAt)
+				})).Return(nil)
 			},
-			wantErr: false,
 		},
 		{
 			name: "用户不存在",
@@ -226,11 +294,10 @@ func TestUserService_Login(t *testing.T) {
 				Email:    "notfound@example.com",
 				Password: "password123",
 			},
-			setup: func(mockRepo *MockUserRepository, mockJWT *MockJWTManager) {
-				mockRepo.On("GetByEmail", "notfound@example.com").Return(nil, errors.New("not found"))
+			setup: func(mockRepo *MockUserRepository, mockRefresh *MockRefreshTokenStore, mockJWT *MockJWTManager) {
+				mockRepo.On("GetByEmail", "notfound@example.com").Return(nil, repository.ErrUserNotFound)
 			},
-			wantErr: true,
-			errMsg:  "invalid email or password",
+			wantErr: services.ErrInvalidCredentials,
 		},
 		{
 			name: "密码错误",
@@ -238,7 +305,7 @@ func TestUserService_Login(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "wrongpassword",
 			},
-			setup: func(mockRepo *MockUserRepository, mockJWT *MockJWTManager) {
+			setup: func(mockRepo *MockUserRepository, mockRefresh *MockRefreshTokenStore, mockJWT *MockJWTManager) {
 				user := &models.User{
 					ID:       userID,
 					Email:    "test@example.com",
@@ -247,8 +314,24 @@ func TestUserService_Login(t *testing.T) {
 				}
 				mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
 			},
-			wantErr: true,
-			errMsg:  "invalid email or password",
+			wantErr: services.ErrInvalidCredentials,
+		},
+		{
+			name: "邮箱未验证",
+			req: &models.UserLogin{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			setup: func(mockRepo *MockUserRepository, mockRefresh *MockRefreshTokenStore, mockJWT *MockJWTManager) {
+				user := &models.User{
+					ID:       userID,
+					Email:    "test@example.com",
+					Password: hashedPassword,
+					Status:   "pending",
+				}
+				mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+			},
+			wantErr: services.ErrEmailNotVerified,
 		},
 		{
 			name: "用户状态非active",
@@ -256,7 +339,7 @@ func TestUserService_Login(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			setup: func(mockRepo *MockUserRepository, mockJWT *MockJWTManager) {
+			setup: func(mockRepo *MockUserRepository, mockRefresh *MockRefreshTokenStore, mockJWT *MockJWTManager) {
 				user := &models.User{
 					ID:       userID,
 					Email:    "test@example.com",
@@ -265,42 +348,93 @@ func TestUserService_Login(t *testing.T) {
 				}
 				mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
 			},
-			wantErr: true,
-			errMsg:  "user account is not active",
+			wantErr: services.ErrAccountInactive,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// TODO: 当前 UserService 使用具体类型 *repository.UserRepository 而非接口
-			// 无法直接使用 mock。需要重构 UserService 使用接口才能进行单元测试。
-			// 目前这些测试被跳过，请使用集成测试 (tests/integration/) 来测试用户服务功能。
-			t.Skip("跳过：UserService 需要重构为使用接口才能进行单元测试")
-			
 			mockRepo := new(MockUserRepository)
+			mockRefresh := new(MockRefreshTokenStore)
 			mockJWT := new(MockJWTManager)
-			tt.setup(mockRepo, mockJWT)
+			tt.setup(mockRepo, mockRefresh, mockJWT)
+			userService := services.NewUserService(mockRepo, mockRefresh, mockJWT)
 
-			// 注意：这里需要根据实际的UserService结构来调整
-			// 由于UserService使用了具体的repository类型，我们需要使用接口或调整测试方式
+			tokens, user, err := userService.Login(context.Background(), tt.req, "")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tokens)
+				assert.Nil(t, user)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "test-token", tokens.Token)
+				assert.Equal(t, "test-refresh-token", tokens.RefreshToken)
+				assert.True(t, tokens.RefreshTokenExpiresAt.Equal(refreshExpiresAt))
+				assert.Equal(t, userID, user.ID)
+				assert.Empty(t, user.Password) // 密码应该被清除
+			}
 
 			mockRepo.AssertExpectations(t)
+			mockRefresh.AssertExpectations(t)
 			mockJWT.AssertExpectations(t)
 		})
 	}
 }
 
-// 辅助函数：创建测试用户
-func createTestUser() *models.User {
-	user := &models.User{
-		ID:       uuid.New(),
-		Username: "testuser",
-		Email:    "test@example.com",
-		Password: "hashedpassword",
-		Role:     models.RoleReader,
-		Status:   "active",
+func TestUserService_StopImpersonation(t *testing.T) {
+	adminID := uuid.New()
+	tests := []struct {
+		name    string
+		admin   *models.User
+		repoErr error
+		wantErr error
+	}{
+		{name: "管理员仍有效", admin: &models.User{ID: adminID, Username: "admin", Role: models.RoleAdmin, Status: "active", Password: "hash"}},
+		{name: "管理员已被降级", admin: &models.User{ID: adminID, Username: "admin", Role: models.RoleEditor, Status: "active"}, wantErr: services.ErrImpersonatorNotAdmin},
+		{name: "管理员已被禁用", admin: &models.User{ID: adminID, Username: "admin", Role: models.RoleAdmin, Status: "inactive"}, wantErr: services.ErrImpersonatorNotAdmin},
+		{name: "管理员已被删除", repoErr: repository.ErrUserNotFound, wantErr: services.ErrImpersonatorNotAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockJWT := new(MockJWTManager)
+			if tt.admin != nil {
+				mockRepo.On("GetByID", adminID).Return(tt.admin, nil)
+			} else {
+				mockRepo.On("GetByID", adminID).Return(nil, tt.repoErr)
+			}
+			if tt.wantErr == nil {
+				mockJWT.On("GenerateToken", adminID, "admin", string(models.RoleAdmin)).Return("admin-token", nil)
+			}
+			userService := services.NewUserService(mockRepo, new(MockRefreshTokenStore), mockJWT)
+
+			token, user, err := userService.StopImpersonation(context.Background(), adminID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, token)
+				assert.Nil(t, user)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "admin-token", token)
+				assert.Empty(t, user.Password)
+			}
+			mockRepo.AssertExpectations(t)
+			mockJWT.AssertExpectations(t)
+		})
 	}
-	return user
+}
+
+func TestSMSService_LoginWithPhoneRequiresJWTManager(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	smsService := services.NewSMSService(repository.NewSMSRepository(), mockRepo)
+
+	// 未设置 JWT 管理器时直接返回错误，不查询验证码
+	_, _, err := smsService.LoginWithPhone(context.Background(), "13800138000", "123456")
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
 }
 
 // 辅助函数：验证用户密码
@@ -311,8 +445,7 @@ func TestUserPasswordHashing(t *testing.T) {
 
 	err := user.HashPassword()
 	assert.NoError(t, err)
-	assert.NotEqual(t, "testpassword123", user.Password) // 密码应该被哈希
+	assert.NotEqual(t, "testpassword123", user.Password)  // 密码应该被哈希
 	assert.True(t, user.CheckPassword("testpassword123")) // 应该能验证正确密码
 	assert.False(t, user.CheckPassword("wrongpassword"))  // 应该拒绝错误密码
 }
-